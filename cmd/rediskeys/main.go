@@ -0,0 +1,76 @@
+// rediskeys 把 Redis 中已有的键迁移到当前的命名空间前缀(redis.key_prefix)下
+//
+// 开启 redis.key_prefix 后服务只读写带前缀的键，之前写入的缓存、登录锁定、配额计数和会话都需要迁移，
+// 否则会话全部失效、锁定被解除。先加 -dry-run 查看需要迁移的键数，停止旧版本服务后执行迁移再启动新版本；
+// 修改前缀时用 -from 指定旧前缀。缓存库(redis.db)和会话库(session.redis_db)都会迁移，可以重复执行。
+//
+//	SERVER_ENV=prod go run ./cmd/rediskeys -dry-run
+//	SERVER_ENV=prod go run ./cmd/rediskeys -from gin-app:production:
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"strings"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/redis"
+	"gin-app-start/pkg/database"
+)
+
+func main() {
+	from := flag.String("from", "", "current prefix of the keys to migrate, empty for keys without prefix")
+	match := flag.String("match", "*", "only migrate keys matching this pattern (after the -from prefix)")
+	batch := flag.Int64("batch", 500, "keys per SCAN")
+	dryRun := flag.Bool("dry-run", false, "count the keys without renaming them")
+	var overrides config.Overrides
+	flag.Var(&overrides, "set", "override a config key, e.g. -set redis.addr=redis:6379 (repeatable)")
+	flag.Parse()
+
+	cfg, err := config.Load(overrides...)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if !cfg.Redis.Enabled {
+		log.Fatalf("redis.enabled is false, nothing to migrate")
+	}
+
+	to := cfg.Redis.Namespace(cfg.Env)
+	// 迁移不带前缀的键时，跳过其他环境命名空间下的键(如 gin-app:dev:)
+	var skip []string
+	if i := strings.Index(cfg.Redis.KeyPrefix, "{env}"); i > 0 {
+		skip = append(skip, cfg.Redis.KeyPrefix[:i])
+	}
+
+	dbs := []int{cfg.Redis.DB}
+	if cfg.Session.UseRedis && cfg.Session.RedisDB != cfg.Redis.DB {
+		dbs = append(dbs, cfg.Session.RedisDB)
+	}
+
+	for _, db := range dbs {
+		client, err := database.NewRedisClient(&database.RedisConfig{
+			Addr:     cfg.Redis.Addr,
+			Password: cfg.Redis.Password,
+			DB:       db,
+		})
+		if err != nil {
+			log.Fatalf("Failed to connect to redis db %d: %v", db, err)
+		}
+
+		res, err := redis.MigrateKeyPrefix(context.Background(), client, *from, to, *match, skip, *batch, *dryRun)
+		client.Close()
+		if err != nil {
+			log.Fatalf("Migrate redis db %d stopped: %v", db, err)
+		}
+
+		if *dryRun {
+			log.Printf("db %d: %d keys would be renamed from %q to %q", db, res.Matched, *from, to)
+			continue
+		}
+		log.Printf("db %d: renamed %d of %d keys from %q to %q", db, res.Renamed, res.Matched, *from, to)
+		for _, key := range res.Conflict {
+			log.Printf("db %d: skipped %s, target key already exists", db, key)
+		}
+	}
+}
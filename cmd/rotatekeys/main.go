@@ -0,0 +1,80 @@
+// rotatekeys 用当前密钥(encryption.current_key)重新加密用户的手机号、邮箱
+//
+// 首次开启加密时用它加密已有的明文；轮换密钥时先把新密钥加入 encryption.keys 并设为 current_key，
+// 重启服务后执行本命令，完成后才能从 keys 中移除旧密钥。可以重复执行，已是当前密钥的值会被跳过。
+//
+//	SERVER_ENV=prod go run ./cmd/rotatekeys -batch 500
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/database"
+	"gin-app-start/pkg/fieldcrypt"
+	"gin-app-start/pkg/secrets"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	batchSize := flag.Int("batch", 500, "rows per batch")
+	var overrides config.Overrides
+	flag.Var(&overrides, "set", "override a config key, e.g. -set database.host=db (repeatable)")
+	flag.Parse()
+
+	cfg, err := config.Load(overrides...)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if !cfg.Encryption.Enabled {
+		log.Fatalf("encryption.enabled is false, nothing to rotate")
+	}
+	if *batchSize <= 0 {
+		log.Fatalf("batch must be positive")
+	}
+
+	provider, err := secrets.New(cfg.Secrets.Provider, cfg.Secrets.EnvPrefix, cfg.Secrets.Dir)
+	if err != nil {
+		log.Fatalf("Invalid secrets config: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	cipher, err := fieldcrypt.FromProvider(ctx, provider, cfg.Encryption.CurrentKey, cfg.Encryption.Keys)
+	cancel()
+	if err != nil {
+		log.Fatalf("Failed to load encryption keys: %v", err)
+	}
+	fieldcrypt.Use(cipher)
+
+	db, err := database.NewPostgresDB(&database.PostgresConfig{
+		Host:         cfg.Database.Host,
+		Port:         cfg.Database.Port,
+		User:         cfg.Database.User,
+		Password:     cfg.Database.Password,
+		DBName:       cfg.Database.DBName,
+		SSLMode:      cfg.Database.SSLMode,
+		MaxIdleConns: cfg.Database.MaxIdleConns,
+		MaxOpenConns: cfg.Database.MaxOpenConns,
+		MaxLifetime:  cfg.Database.MaxLifetime,
+		LogLevel:     cfg.Database.LogLevel,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer database.DBRepo.DbClose()
+
+	// 只访问数据库，不使用缓存
+	userRepo := repository.NewUserRepository(db, nil)
+
+	start := time.Now()
+	updated, err := userRepo.ReencryptPII(common.NewBackgroundContext(zap.NewNop()), *batchSize)
+	if err != nil {
+		log.Fatalf("Re-encrypt stopped after %d users: %v", updated, err)
+	}
+	log.Printf("Re-encrypted %d users with key %s in %s", updated, cipher.CurrentKey(), time.Since(start).Round(time.Millisecond))
+}
@@ -2,28 +2,62 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"gin-app-start/configs"
 	_ "gin-app-start/docs"
+	"gin-app-start/internal/activity"
+	"gin-app-start/internal/code"
 	"gin-app-start/internal/common"
 	"gin-app-start/internal/config"
 	"gin-app-start/internal/controller"
+	"gin-app-start/internal/dependency"
+	"gin-app-start/internal/doctor"
+	"gin-app-start/internal/filelink"
+	"gin-app-start/internal/filescan"
+	"gin-app-start/internal/hooks"
+	"gin-app-start/internal/httpcache"
+	"gin-app-start/internal/lockout"
+	"gin-app-start/internal/middleware"
 	"gin-app-start/internal/model"
+	"gin-app-start/internal/quota"
 	"gin-app-start/internal/redis"
+	"gin-app-start/internal/refreshtoken"
 	"gin-app-start/internal/repository"
+	"gin-app-start/internal/reqstats"
 	"gin-app-start/internal/router"
 	"gin-app-start/internal/service"
+	"gin-app-start/internal/spa"
+	"gin-app-start/internal/telemetry"
+	"gin-app-start/internal/web"
+	"gin-app-start/pkg/buildinfo"
+	"gin-app-start/pkg/carrier"
 	"gin-app-start/pkg/database"
+	"gin-app-start/pkg/fieldcrypt"
+	"gin-app-start/pkg/hashid"
+	"gin-app-start/pkg/i18n"
+	"gin-app-start/pkg/jwt"
+	"gin-app-start/pkg/lifecycle"
 	"gin-app-start/pkg/logger"
+	"gin-app-start/pkg/mail"
+	"gin-app-start/pkg/secrets"
 	"gin-app-start/pkg/timeutil"
 
+	"github.com/gin-gonic/gin"
+	"github.com/sony/gobreaker"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
 )
 
 //	@title			Gin App API
@@ -43,36 +77,187 @@ import (
 
 //	@schemes	http https
 
+//	@securityDefinitions.apikey	SessionCookie
+//	@in							header
+//	@name						Cookie
+//	@description				Session cookie issued by POST /api/v1/users/login, e.g. "mysession=<value>"
+
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+//	@description				Bearer token, "Bearer <token>"
+
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						X-API-Key
+//	@description				API key for server-to-server calls
+
+//	@tag.name			users
+//	@tag.description	Registration, login and profile. Endpoints with a lock require a session; x-roles lists who may call them ("owner" means the session user's own data)
+//	@tag.name			orders
+//	@tag.description	Orders of the session user; admin can access every user's orders
+//	@tag.name			health
+//	@tag.description	Liveness and readiness probes, no authentication
+//	@tag.name			admin
+//	@tag.description	Operations endpoints, served only on the admin port (admin.port) and never exposed on the public host
+
+// Version 兼容旧的 -X main.Version 注入方式，推荐使用 gin-app-start/pkg/buildinfo
 var Version string
 
 func main() {
-	log.Printf("Version: %s\n", Version)
+	if Version != "" {
+		buildinfo.Version = Version
+	}
+	build := buildinfo.Get()
+	log.Printf("Version: %s\n", build.Version)
+
+	// 第一个参数不以 - 开头时为子命令(routes、doctor、config)，其余为参数
+	command, args := "", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	// config 子命令: 生成配置文件，不加载配置
+	if command == "config" {
+		os.Exit(runConfigCommand(args))
+	}
+
+	var overrides config.Overrides
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	flags.Var(&overrides, "set", "override a config key, e.g. -set server.port=9062 (repeatable)")
+	migrate := flags.Bool("migrate", false, "migrate database schema on startup, same as -set database.auto_migrate=true")
+	flags.Parse(args)
+	if *migrate {
+		overrides = append(overrides, "database.auto_migrate=true")
+	}
+
+	// doctor 子命令: 输出部署前检查报告，有检查失败时以非 0 状态码退出
+	if command == "doctor" {
+		os.Exit(runDoctor(overrides))
+	}
 
-	cfg, err := config.Load()
+	cfg, err := config.Load(overrides...)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	accessLogger, err := logger.Init(
-		cfg,
+	switch command {
+	case "":
+	case "routes":
+		// routes 子命令: 打印路由表后退出
+		if err := printRoutes(cfg); err != nil {
+			log.Fatalf("Failed to print routes: %v", err)
+		}
+		return
+	default:
+		log.Fatalf("Unknown command %q, expected routes, doctor or config", command)
+	}
 
+	logOptions := []logger.Option{
 		// 禁用控制台输出
 		logger.WithDisableConsole(),
 		// 添加自定义字段 "domain"，格式为 "项目名[环境]"，例如：go-gin-api[fat]，便于区分不同环境和项目的日志
 		logger.WithField("domain", fmt.Sprintf("%s[%s]", common.ProjectName, cfg.Server.Mode)),
 		// 设置时间格式为 "2006-01-02 15:04:05"
 		logger.WithTimeLayout(timeutil.CSTLayout),
-		// 日志输出到文件 cfg.Log.FilePath
-		logger.WithFileP(cfg.Log.FilePath),
-	)
+		// 日志输出到文件 cfg.Log.FilePath，按 max_size/max_age/max_backups 轮转
+		logger.WithFileRotation(cfg.Log.FilePath, logger.RotationFromConfig(cfg.Log)),
+	}
+
+	// 实时日志: 最近的日志保存在内存中，通过管理端口的 /logs/tail 查看
+	var logTail *logger.Ring
+	if cfg.Log.Tail.Enabled {
+		logTail = logger.NewRing(cfg.Log.Tail.Size, zapcore.DebugLevel)
+		logOptions = append(logOptions, logger.WithRing(logTail))
+	}
+	// 最近的错误日志: 保存在内存中，通过管理端口的 /errors/recent 查看，不方便查看日志文件时排查问题
+	var recentErrors *logger.Ring
+	if cfg.Log.RecentErrors.Enabled {
+		recentErrors = logger.NewRing(cfg.Log.RecentErrors.Size, zapcore.ErrorLevel)
+		logOptions = append(logOptions, logger.WithRing(recentErrors))
+	}
+
+	// 日志外发目标(syslog、Loki)由访问日志和慢日志共用
+	logSinks, err := logger.OpenSinks(cfg.Log.Sinks)
+	if err != nil {
+		log.Fatalf("Failed to initialize log sinks: %v", err)
+	}
+	logOptions = append(logOptions, logSinks.Option())
 
+	accessLogger, err := logger.Init(cfg, logOptions...)
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 
-	defer accessLogger.Sync()
+	// 慢请求、慢 SQL 单独输出到 slow.log，便于排查
+	slowLogFile := cfg.Log.SlowFilePath
+	if slowLogFile == "" {
+		slowLogFile = filepath.Join(filepath.Dir(cfg.Log.FilePath), "slow.log")
+	}
+	slowLogger, err := logger.New(
+		cfg,
+		logger.WithDisableConsole(),
+		logger.WithField("domain", fmt.Sprintf("%s[%s]", common.ProjectName, cfg.Server.Mode)),
+		logger.WithTimeLayout(timeutil.CSTLayout),
+		logger.WithFileRotation(slowLogFile, logger.RotationFromConfig(cfg.Log)),
+		logSinks.Option(),
+	)
+	if err != nil {
+		log.Fatalf("Failed to initialize slow logger: %v", err)
+	}
+
+	// 退出时按启动的相反顺序关闭各组件: 先停止接收请求，再停止后台任务，最后关闭 Redis、数据库连接
+	shutdownTimeout := time.Duration(cfg.Server.ShutdownTimeout) * time.Second
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = lifecycle.DefaultTimeout
+	}
+	lc := lifecycle.New(logger.Module(accessLogger, "lifecycle"),
+		lifecycle.WithTimeout(shutdownTimeout),
+		lifecycle.WithHookTimeout(time.Duration(cfg.Server.ShutdownHookTimeout)*time.Second),
+	)
+
+	accessLogger.Info("Application starting",
+		zap.String("version", build.Version),
+		zap.String("git_commit", build.GitCommit),
+		zap.String("build_time", build.BuildTime),
+		zap.String("go_version", build.GoVersion),
+		zap.String("env", cfg.Env),
+		zap.String("config_file", cfg.ConfigFile),
+		zap.String("mode", cfg.Server.Mode),
+	)
 
-	accessLogger.Info("Application starting", zap.String("version", Version), zap.String("mode", cfg.Server.Mode))
+	// 错误信息和提示信息按请求的 Accept-Language 返回，未指定或不支持时使用 language.local
+	i18n.Use(code.NewBundle(cfg.Language.Local))
+
+	// 敏感字段加密必须在访问数据库之前启用，否则读到密文会报错、新写入的数据为明文
+	if cfg.Encryption.Enabled {
+		if err := useEncryption(cfg); err != nil {
+			accessLogger.Fatal("Failed to initialize field encryption", zap.Error(err))
+		}
+		accessLogger.Info("Field encryption enabled", zap.String("current_key", cfg.Encryption.CurrentKey))
+	}
+
+	if cfg.IDs.Enabled {
+		if err := useIDObfuscation(cfg.IDs); err != nil {
+			accessLogger.Fatal("Invalid id obfuscation config", zap.Error(err))
+		}
+		accessLogger.Info("User id obfuscation enabled")
+	}
+
+	// 追踪需要在创建数据库、Redis 客户端之前初始化，它们的追踪插件使用全局 TracerProvider
+	if cfg.Tracing.Enabled {
+		shutdownTracing, err := telemetry.Init(cfg.Tracing, build.Version, cfg.Env)
+		if err != nil {
+			accessLogger.Fatal("Failed to initialize tracing", zap.Error(err))
+		}
+		// 导出缓冲中剩余的 span
+		lc.Register("tracing", shutdownTracing)
+		accessLogger.Info("Tracing enabled",
+			zap.String("service_name", telemetry.ServiceName(cfg.Tracing)),
+			zap.String("endpoint", cfg.Tracing.Endpoint),
+			zap.Float64("sample_ratio", cfg.Tracing.SampleRatio),
+		)
+	}
 
 	db, err := database.NewPostgresDB(&database.PostgresConfig{
 		Host:         cfg.Database.Host,
@@ -85,47 +270,291 @@ func main() {
 		MaxOpenConns: cfg.Database.MaxOpenConns,
 		MaxLifetime:  cfg.Database.MaxLifetime,
 		LogLevel:     cfg.Database.LogLevel,
+
+		SlowThreshold: time.Duration(cfg.Log.SlowQueryThreshold) * time.Millisecond,
+		SlowLogger:    slowLogger,
+		Tracing:       cfg.Tracing.Enabled,
 	})
 	if err != nil {
 		accessLogger.Fatal("Failed to initialize database", zap.Error(err))
 	}
-	defer database.DBRepo.DbClose()
+	lc.Register("postgres", lifecycle.Closer(database.DBRepo.DbClose))
 
 	accessLogger.Info("Database connected successfully")
 
 	if cfg.Database.AutoMigrate {
-		if err := db.AutoMigrate(&model.User{}, &model.Order{}); err != nil {
+		if err := repository.AutoMigrate(db); err != nil {
 			accessLogger.Fatal("Database migration failed", zap.Error(err))
 		}
 		accessLogger.Info("Database migration completed")
 	}
 
-	redisClient, err := database.NewRedisClient(&database.RedisConfig{
+	redisConfig := &database.RedisConfig{
 		Addr:         cfg.Redis.Addr,
 		Password:     cfg.Redis.Password,
 		DB:           cfg.Redis.DB,
 		PoolSize:     cfg.Redis.PoolSize,
 		MinIdleConns: cfg.Redis.MinIdleConns,
 		MaxRetries:   cfg.Redis.MaxRetries,
+		Breaker:      redisBreakerConfig(cfg.Redis.Breaker, logger.Module(accessLogger, "redis")),
+		Tracing:      cfg.Tracing.Enabled,
+	}
+
+	// Redis 禁用时注入空实现，缓存读写全部跳过
+	var redisRepo redis.RedisRepository
+	if cfg.Redis.Enabled {
+		redisClient, err := database.NewRedisClient(redisConfig)
+		if err != nil {
+			accessLogger.Warn("Failed to initialize Redis", zap.Error(err))
+		} else {
+			accessLogger.Info("Redis connected successfully")
+		}
+		redisRepo = redis.NewRedisRepository(redisClient, context.Background(), cfg.Redis.Namespace(cfg.Env))
+	} else {
+		accessLogger.Info("Redis is disabled, caching is skipped")
+		redisRepo = redis.NewNoopRepository(context.Background())
+	}
+	lc.Register("redis", lifecycle.Func(redisRepo.Close))
+
+	// 后台探活 Postgres、Redis，Redis 启动时不可用或中途断开后自动重连
+	deps := dependency.NewContainer(accessLogger, time.Duration(cfg.Health.CheckInterval)*time.Second)
+	deps.Register(dependency.Dependency{
+		Name:     "postgres",
+		Required: true,
+		Check: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		},
 	})
+	if cfg.Redis.Enabled {
+		deps.Register(dependency.Dependency{
+			Name: "redis",
+			Check: func(ctx context.Context) error {
+				client := redisRepo.GetRedisClient()
+				if client == nil {
+					return redis.ErrUnavailable
+				}
+				return client.Ping(ctx).Err()
+			},
+			Reconnect: func(ctx context.Context) error {
+				client, err := database.NewRedisClient(redisConfig)
+				if err != nil {
+					return err
+				}
+				redisRepo.SetRedisClient(client)
+				return nil
+			},
+		})
+	}
+	deps.Start()
+	lc.Register("dependency", lifecycle.Func(deps.Stop))
+
+	// 请求生命周期和业务事件钩子，扩展行为时在此注册，如 hookRegistry.OnOrderPaid(...)
+	hookRegistry := hooks.New()
+
+	userRepo := repository.NewUserRepository(db, redisRepo)
+	referralService := service.NewReferralService(userRepo, redisRepo)
+	passwordPolicy, err := service.NewPasswordPolicy(cfg.Password)
 	if err != nil {
-		accessLogger.Warn("Failed to initialize Redis", zap.Error(err))
-	} else {
-		defer redisClient.Close()
-		accessLogger.Info("Redis connected successfully")
+		accessLogger.Fatal("Invalid password config", zap.Error(err))
+	}
+	passwordHasher, err := service.NewPasswordHasher(cfg.Password.Hash)
+	if err != nil {
+		accessLogger.Fatal("Invalid password hash config", zap.Error(err))
+	}
+	// 登录锁定依赖 Redis 计数，Redis 未启用时不锁定
+	var loginGuard *lockout.Guard
+	if cfg.Redis.Enabled {
+		loginGuard = lockout.NewGuard(redisRepo, cfg.Lockout)
+	} else if cfg.Lockout.Enabled {
+		accessLogger.Warn("Login lockout is enabled but redis is disabled, lockout will not be enforced")
 	}
+	userService := service.NewUserService(userRepo, referralService, passwordPolicy, passwordHasher, loginGuard, hookRegistry)
+
+	tagRepo := repository.NewTagRepository(db)
+	tagService := service.NewTagService(tagRepo, userRepo)
 
-	userRepo := repository.NewUserRepository(db)
-	userService := service.NewUserService(userRepo)
-	userController := controller.NewUserController(userService)
-	healthController := controller.NewHealthController()
+	// 邮件未启用时群发只发送站内信
+	var mailer mail.Sender
+	if cfg.Mail.Enabled {
+		smtpSender, err := mail.NewSMTPSender(cfg.Mail.Host, cfg.Mail.Port, cfg.Mail.Username, cfg.Mail.Password, cfg.Mail.From)
+		if err != nil {
+			accessLogger.Fatal("Invalid mail config", zap.Error(err))
+		}
+		mailer = smtpSender
+	}
+	broadcastService := service.NewBroadcastService(repository.NewBroadcastRepository(db), tagRepo, tagService, mailer, cfg.Broadcast, logger.Module(accessLogger, "broadcast"))
+	if cfg.Broadcast.Enabled {
+		broadcastService.Start()
+		lc.Register("broadcast", lifecycle.Func(broadcastService.Stop))
+	}
+
+	// Redis 禁用时收藏直接写入数据库，不需要后台落库
+	wishlistService := service.NewWishlistService(repository.NewWishlistRepository(db), redisRepo, cfg.Redis.Enabled, cfg.Wishlist, logger.Module(accessLogger, "wishlist"))
+	if cfg.Redis.Enabled {
+		wishlistService.Start()
+		lc.Register("wishlist", lifecycle.Func(wishlistService.Stop))
+	}
+	wishlistController := controller.NewWishlistController(wishlistService)
+	settingsController := controller.NewSettingsController(service.NewSettingsService(repository.NewUserSettingRepository(db), cfg.Language.Local))
+
+	// Redis 禁用时浏览直接写入数据库，不需要后台落库
+	viewService := service.NewViewCounterService(repository.NewViewCountRepository(db), redisRepo, cfg.Redis.Enabled, cfg.Views, logger.Module(accessLogger, "views"))
+	if cfg.Redis.Enabled {
+		viewService.Start()
+		lc.Register("views", lifecycle.Func(viewService.Stop))
+	}
+	productRepo := repository.NewProductRepository(db)
+	productService := service.NewProductService(productRepo)
+	productController := controller.NewProductController(productService, viewService)
+
+	// 会话超时依赖 Redis 记录时间戳，Redis 未启用时不检查
+	var sessionTracker *activity.Tracker
+	if cfg.Redis.Enabled {
+		sessionTracker = activity.NewTracker(redisRepo, cfg.Session)
+	} else if cfg.Session.IdleTimeout > 0 || cfg.Session.AbsoluteTimeout > 0 {
+		accessLogger.Warn("Session timeout is configured but redis is disabled, session timeout will not be enforced")
+	}
+	// auth.mode 为 jwt 时登录签发令牌，需要登录的接口改为验证访问令牌
+	tokens, err := newTokenManager(cfg.Auth)
+	if err != nil {
+		accessLogger.Fatal("Invalid auth config", zap.Error(err))
+	}
+	// 刷新令牌的轮换和吊销记录在 Redis 中，Redis 未启用时刷新令牌在有效期内可以反复使用
+	var refreshTokens *refreshtoken.Store
+	if tokens != nil && cfg.Redis.Enabled {
+		refreshTokens = refreshtoken.NewStore(redisRepo, tokens.RefreshTTL())
+	} else if tokens != nil {
+		accessLogger.Warn("Auth mode is jwt but redis is disabled, refresh tokens will not be rotated or revoked")
+	}
+	// 头像等上传文件通过限时签名链接下载，不需要登录
+	fileLinks, generated, err := filelink.NewSigner(cfg.File)
+	if err != nil {
+		accessLogger.Fatal("Failed to create file link signer", zap.Error(err))
+	}
+	if generated {
+		accessLogger.Warn("file.sign_key is not set, using a random key: download links become invalid after restart and cannot be verified by other instances")
+	}
+	// file.scan.enabled 时上传的文件先放入隔离区，后台扫描通过后才能下载
+	quarantine, err := filescan.New(cfg.File.Scan, cfg.File.DirName, logger.Module(accessLogger, "filescan"))
+	if err != nil {
+		accessLogger.Fatal("Failed to create file scanner", zap.Error(err))
+	}
+	if quarantine != nil {
+		quarantine.Start()
+		lc.Register("filescan", lifecycle.Func(quarantine.Stop))
+	}
+	userController := controller.NewUserController(userService, referralService, broadcastService, sessionTracker, tokens, refreshTokens, fileLinks, quarantine)
+	fileController := controller.NewFileController(fileLinks, quarantine, cfg.File.DirName)
+	impersonationController := controller.NewImpersonationController(userService, cfg.Impersonation)
+	healthController := controller.NewHealthController(deps, time.Duration(cfg.Health.ProbeTimeout)*time.Millisecond)
+	// auth.api_key.enabled 时用户可以创建 API Key，需要登录的接口同时接受 X-API-Key 请求头
+	var apiKeys middleware.APIKeyAuthenticator
+	var apiKeyController *controller.APIKeyController
+	if cfg.Auth.APIKey.Enabled {
+		apiKeyService := service.NewAPIKeyService(repository.NewAPIKeyRepository(db), userRepo, cfg.Auth.APIKey)
+		apiKeys = apiKeyService
+		apiKeyController = controller.NewAPIKeyController(apiKeyService)
+	}
 
-	redisRepo := redis.NewRedisRepository(redisClient, context.Background())
 	orderRepo := repository.NewOrderRepository(db)
-	orderService := service.NewOrderService(orderRepo, redisRepo)
-	orderController := controller.NewOrderController(orderService)
+	if err := cfg.OrderNumber.Validate(model.OrderNumberMaxLen); err != nil {
+		accessLogger.Fatal("Invalid order number config", zap.Error(err))
+	}
+	leaderboardService := service.NewLeaderboardService(orderRepo, userRepo, redisRepo)
+	leaderboardController := controller.NewLeaderboardController(leaderboardService)
+	// 响应缓存保存在 Redis 中，Redis 未启用时不缓存
+	var responseCache *httpcache.Cache
+	if cfg.Redis.Enabled {
+		responseCache = httpcache.New(redisRepo, cfg.ResponseCache)
+	} else if cfg.ResponseCache.Enabled {
+		accessLogger.Warn("Response cache is enabled but redis is disabled, responses will not be cached")
+	}
+	geoService := service.NewGeoService(repository.NewStoreRepository(db), orderRepo, redisRepo, responseCache)
+	storeController := controller.NewStoreController(geoService)
+	orgRepo := repository.NewOrganizationRepository(db)
+	projectionService := service.NewOrderProjectionService(orderRepo, repository.NewOrderSummaryRepository(db), cfg.Projection, logger.Module(accessLogger, "projection"))
+	archiveRepo := repository.NewOrderArchiveRepository(db)
+	orderService := service.NewOrderService(orderRepo, userRepo, redisRepo, cfg.Cache, cfg.OrderNumber, leaderboardService, geoService, orgRepo, projectionService, archiveRepo, repository.NewOrderItemRepository(db), productRepo, hookRegistry)
+	// NewOrderService 中注册了读模型更新后的回调，需在其之后启动
+	if cfg.Projection.Enabled {
+		projectionService.Start()
+		lc.Register("projection", lifecycle.Func(projectionService.Stop))
+	}
+	if cfg.Archive.Enabled {
+		archiveService := service.NewOrderArchiveService(archiveRepo, orderService, cfg.Archive, logger.Module(accessLogger, "archive"))
+		archiveService.Start()
+		lc.Register("archive", lifecycle.Func(archiveService.Stop))
+	}
+	orderController := controller.NewOrderController(orderService, viewService)
+	// 邮件未启用时邀请只返回给邀请人，由其自行转发
+	organizationController := controller.NewOrganizationController(service.NewOrganizationService(orgRepo, userRepo, orderRepo, mailer))
+
+	// 尚未接入承运商查询接口，物流事件由承运商回调或管理端录入；接入后在此注册，键为承运商代码
+	trackers := map[string]carrier.Tracker{}
+	shipmentService := service.NewShipmentService(repository.NewShipmentRepository(db), orderRepo, trackers, cfg.Shipment, logger.Module(accessLogger, "shipment"))
+	if cfg.Shipment.PollEnabled {
+		shipmentService.Start()
+		lc.Register("shipment", lifecycle.Func(shipmentService.Stop))
+	}
+	shipmentController := controller.NewShipmentController(shipmentService)
+
+	// 请求录制会保存完整的请求和响应，只允许在非 release 模式下开启
+	var recordingService service.RecordingService
+	if cfg.Tape.Enabled {
+		if cfg.Server.Mode != gin.ReleaseMode && cfg.Redis.Enabled {
+			recordingService = service.NewRecordingService(redisRepo, cfg.Tape, logger.Module(accessLogger, "tape"))
+			lc.Register("tape", lifecycle.Func(recordingService.Close))
+		} else {
+			accessLogger.Warn("Tape is only available in non-release mode with redis enabled, ignored")
+		}
+	}
+
+	// 按用户的请求统计保存在 Redis 中，Redis 未启用时不统计也不限制并发
+	var requestStats *reqstats.Tracker
+	if cfg.Concurrency.PerUser.Enabled {
+		if cfg.Redis.Enabled {
+			requestStats = reqstats.NewTracker(redisRepo, cfg.Concurrency.PerUser)
+		} else {
+			accessLogger.Warn("Per-user concurrency is enabled but redis is disabled, requests will not be tracked")
+		}
+	}
 
-	s, err := router.SetupRouter(accessLogger, healthController, userController, orderController, cfg)
+	cacheService := service.NewCacheService(redisRepo)
+	adminController := controller.NewAdminController(cfg, deps, cacheService, orderService, productService, referralService, tagService, broadcastService, shipmentService, leaderboardService, geoService, projectionService, loginGuard, requestStats, recordingService, logTail, recentErrors)
+
+	// 请求配额计数保存在 Redis 中，Redis 未启用时不限制
+	var quotaLimiter *quota.Limiter
+	if cfg.Quota.Enabled {
+		if cfg.Redis.Enabled {
+			quotaLimiter = quota.NewLimiter(redisRepo, cfg.Quota, logger.Module(accessLogger, "quota"))
+			quotaLimiter.Start()
+			lc.Register("quota", lifecycle.Func(quotaLimiter.Stop))
+		} else {
+			accessLogger.Warn("Quota is enabled but redis is disabled, quota will not be enforced")
+		}
+	}
+
+	// HTTP 中间件日志单独作为一个模块，级别由 log.levels.middleware 控制
+	httpLogger := logger.Module(accessLogger, "middleware")
+
+	// 模块按顺序注册路由，可以通过 modules 配置关闭
+	modules := []router.Module{healthController, userController, fileController, impersonationController, wishlistController, settingsController, orderController, organizationController, storeController, productController, leaderboardController, shipmentController}
+	if apiKeyController != nil {
+		modules = append(modules, apiKeyController)
+	}
+	// pages.enabled 时在 /ui 下提供管理员使用的服务端渲染页面
+	if cfg.Pages.Enabled {
+		renderer, err := web.NewRenderer()
+		if err != nil {
+			accessLogger.Fatal("Failed to parse page templates", zap.Error(err))
+		}
+		modules = append(modules, controller.NewPageController(userService, orderService, sessionTracker, renderer, cfg.Pages.Size()))
+	}
+	s, err := router.SetupRouter(httpLogger, slowLogger, modules, quotaLimiter, sessionTracker, requestStats, recordingService, responseCache, tokens, apiKeys, hookRegistry, cfg)
 	if err != nil {
 		accessLogger.Fatal("Failed to initialize router", zap.Error(err))
 	}
@@ -137,6 +566,30 @@ func main() {
 		WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
 	}
 
+	var adminServer *http.Server
+	if cfg.Admin.Enabled {
+		as, err := router.SetupAdminRouter(httpLogger, adminController, cfg)
+		if err != nil {
+			accessLogger.Fatal("Failed to initialize admin router", zap.Error(err))
+		}
+
+		adminServer = &http.Server{
+			Addr:         fmt.Sprintf(":%d", cfg.Admin.Port),
+			Handler:      as.Mux,
+			ReadTimeout:  time.Duration(cfg.Server.ReadTimeout) * time.Second,
+			WriteTimeout: time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		}
+
+		go func() {
+			accessLogger.Info("Admin server started", zap.String("addr", adminServer.Addr))
+
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				accessLogger.Fatal("Admin server failed to start", zap.Error(err))
+			}
+		}()
+		lc.RegisterWithTimeout("admin_server", shutdownTimeout, adminServer.Shutdown)
+	}
+
 	go func() {
 		appURL := fmt.Sprintf("http://localhost:%d", cfg.Server.Port)
 		swaggerURL := fmt.Sprintf("http://localhost:%d/swagger/index.html", cfg.Server.Port)
@@ -148,6 +601,8 @@ func main() {
 			accessLogger.Fatal("Server failed to start", zap.Error(err))
 		}
 	}()
+	// HTTP 服务需要等待处理中的请求结束，时限与总时限相同
+	lc.RegisterWithTimeout("http_server", shutdownTimeout, server.Shutdown)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -155,12 +610,311 @@ func main() {
 
 	accessLogger.Info("Server shutting down...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := server.Shutdown(ctx); err != nil {
+	if err := lc.Shutdown(context.Background()); err != nil {
 		accessLogger.Error("Server shutdown failed", zap.Error(err))
 	}
 
 	accessLogger.Info("Server stopped")
+
+	// 日志最后刷盘，关闭过程中的日志也要写入文件
+	_ = slowLogger.Sync()
+	_ = accessLogger.Sync()
+	_ = logSinks.Close()
+}
+
+// printRoutes 用未注入依赖的 controller 构建公网和管理端口的路由，输出每个路由的处理函数和拦截器
+// 只注册路由不处理请求，不连接数据库和 Redis
+func printRoutes(cfg *config.Config) error {
+	routeCfg := *cfg
+	routeCfg.Session.UseRedis = false
+	// 前端构建目录只处理未匹配路由的请求，不在路由表中，也不要求构建目录存在
+	routeCfg.SPA.Enabled = false
+	nop := zap.NewNop()
+
+	tokens, err := newTokenManager(cfg.Auth)
+	if err != nil {
+		return err
+	}
+	modules := []router.Module{new(controller.HealthController), new(controller.UserController), new(controller.FileController), new(controller.ImpersonationController), new(controller.WishlistController), new(controller.SettingsController), new(controller.OrderController), new(controller.OrganizationController), new(controller.StoreController), new(controller.ProductController), new(controller.LeaderboardController), new(controller.ShipmentController)}
+	var apiKeys middleware.APIKeyAuthenticator
+	if cfg.Auth.APIKey.Enabled {
+		apiKeys = service.NewAPIKeyService(nil, nil, cfg.Auth.APIKey)
+		modules = append(modules, new(controller.APIKeyController))
+	}
+	if cfg.Pages.Enabled {
+		modules = append(modules, new(controller.PageController))
+	}
+	s, err := router.SetupRouter(nop, nil, modules, nil, nil, reqstats.NewTracker(nil, cfg.Concurrency.PerUser), nil, nil, tokens, apiKeys, nil, &routeCfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Public routes (:%d)\n", cfg.Server.Port)
+	if err := router.WriteRoutes(os.Stdout, s, "public"); err != nil {
+		return err
+	}
+
+	if !cfg.Admin.Enabled {
+		return nil
+	}
+	as, err := router.SetupAdminRouter(nop, new(controller.AdminController), &routeCfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nAdmin routes (:%d)\n", cfg.Admin.Port)
+	return router.WriteRoutes(os.Stdout, as, "admin-port")
+}
+
+// runDoctor 依次检查配置、字段加密密钥、Postgres、表结构、Redis 和文件存储目录，返回退出码
+//
+// 配置加载失败时依赖配置的检查全部跳过；不会执行迁移，只报告 AutoMigrate 尚未完成的变更。
+func runDoctor(overrides []string) int {
+	d := doctor.New(30 * time.Second)
+	defer func() {
+		if err := d.WriteReport(os.Stdout); err != nil {
+			log.Printf("Failed to write report: %v", err)
+		}
+	}()
+
+	var cfg *config.Config
+	configOK := d.Run("config", func(ctx context.Context) (string, error) {
+		c, err := config.Load(overrides...)
+		if err != nil {
+			return "", err
+		}
+		cfg = c
+
+		var errs []error
+		if err := c.OrderNumber.Validate(model.OrderNumberMaxLen); err != nil {
+			errs = append(errs, fmt.Errorf("order_number: %w", err))
+		}
+		if _, err := c.Session.SessionKeys(); err != nil {
+			errs = append(errs, fmt.Errorf("session: %w", err))
+		}
+		if c.IDs.Enabled {
+			if err := useIDObfuscation(c.IDs); err != nil {
+				errs = append(errs, fmt.Errorf("id_obfuscation: %w", err))
+			}
+		}
+		if _, err := service.NewPasswordHasher(c.Password.Hash); err != nil {
+			errs = append(errs, fmt.Errorf("password.hash: %w", err))
+		}
+		if c.SPA.Enabled {
+			if _, err := spa.New(c.SPA); err != nil {
+				errs = append(errs, fmt.Errorf("spa: %w", err))
+			}
+		}
+		if c.Tracing.Enabled {
+			if err := telemetry.Validate(c.Tracing); err != nil {
+				errs = append(errs, fmt.Errorf("tracing: %w", err))
+			}
+		}
+		if c.Admin.Enabled && c.Admin.Port == c.Server.Port {
+			errs = append(errs, fmt.Errorf("admin.port %d conflicts with server.port", c.Admin.Port))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return "", err
+		}
+		file := c.ConfigFile
+		if file == "" {
+			file = "(defaults only)"
+		}
+		return fmt.Sprintf("env=%s file=%s", c.Env, file), nil
+	})
+	if !configOK {
+		for _, name := range []string{"encryption", "postgres", "schema", "redis", "file_storage"} {
+			d.Skip(name, "config not loaded")
+		}
+		return 1
+	}
+
+	if cfg.Encryption.Enabled {
+		d.Run("encryption", func(ctx context.Context) (string, error) {
+			if err := useEncryption(cfg); err != nil {
+				return "", err
+			}
+			return "current_key=" + cfg.Encryption.CurrentKey, nil
+		})
+	} else {
+		d.Skip("encryption", "encryption.enabled is false")
+	}
+
+	var db *gorm.DB
+	dbOK := d.Run("postgres", func(ctx context.Context) (string, error) {
+		var err error
+		db, err = database.NewPostgresDB(&database.PostgresConfig{
+			Host:         cfg.Database.Host,
+			Port:         cfg.Database.Port,
+			User:         cfg.Database.User,
+			Password:     cfg.Database.Password,
+			DBName:       cfg.Database.DBName,
+			SSLMode:      cfg.Database.SSLMode,
+			MaxIdleConns: 1,
+			MaxOpenConns: 1,
+			LogLevel:     "silent",
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s:%d/%s", cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName), nil
+	})
+	if dbOK {
+		defer database.DBRepo.DbClose()
+		d.Run("schema", func(ctx context.Context) (string, error) {
+			pending, err := repository.PendingMigrations(db.WithContext(ctx))
+			if err != nil {
+				return "", err
+			}
+			if len(pending) > 0 {
+				return "", fmt.Errorf("%d pending (run with database.auto_migrate): %s", len(pending), strings.Join(pending, "; "))
+			}
+			return "up to date", nil
+		})
+	} else {
+		d.Skip("schema", "postgres unavailable")
+	}
+
+	if cfg.Redis.Enabled {
+		d.Run("redis", func(ctx context.Context) (string, error) {
+			client, err := database.NewRedisClient(&database.RedisConfig{
+				Addr:     cfg.Redis.Addr,
+				Password: cfg.Redis.Password,
+				DB:       cfg.Redis.DB,
+			})
+			if err != nil {
+				return "", err
+			}
+			defer client.Close()
+			return fmt.Sprintf("%s/%d", cfg.Redis.Addr, cfg.Redis.DB), nil
+		})
+	} else {
+		d.Skip("redis", "redis.enabled is false")
+	}
+
+	d.Run("file_storage", func(ctx context.Context) (string, error) {
+		return doctor.WritableDir(cfg.File.DirName)
+	})
+
+	if cfg.File.Scan.Enabled {
+		d.Run("file_scan", func(ctx context.Context) (string, error) {
+			if _, err := filescan.New(cfg.File.Scan, cfg.File.DirName, zap.NewNop()); err != nil {
+				return "", err
+			}
+			return doctor.WritableDir(cfg.File.Scan.QuarantineDir)
+		})
+	} else {
+		d.Skip("file_scan", "file.scan.enabled is false")
+	}
+
+	if d.Failed() > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runConfigCommand 执行 config 子命令，返回退出码
+//
+//	config init [-o path] [-force]  把内置的默认配置写出为配置文件，默认为 configs/config.<SERVER_ENV>.yaml
+func runConfigCommand(args []string) int {
+	if len(args) == 0 || args[0] != "init" {
+		log.Printf("Usage: %s config init [-o path] [-force]", os.Args[0])
+		return 2
+	}
+
+	env := os.Getenv("SERVER_ENV")
+	if env == "" {
+		env = "local"
+	}
+	flags := flag.NewFlagSet("config init", flag.ExitOnError)
+	output := flags.String("o", filepath.Join("configs", fmt.Sprintf("config.%s.yaml", env)), "output file")
+	force := flags.Bool("force", false, "overwrite the output file if it exists")
+	flags.Parse(args[1:])
+
+	fileFlag := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if *force {
+		fileFlag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	if err := os.MkdirAll(filepath.Dir(*output), os.ModePerm); err != nil {
+		log.Printf("Failed to create config directory: %v", err)
+		return 1
+	}
+	f, err := os.OpenFile(*output, fileFlag, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			log.Printf("%s already exists, use -force to overwrite", *output)
+		} else {
+			log.Printf("Failed to write config: %v", err)
+		}
+		return 1
+	}
+	_, err = f.Write(configs.Example)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Printf("Failed to write config: %v", err)
+		return 1
+	}
+
+	log.Printf("Wrote default config to %s", *output)
+	return 0
+}
+
+// newTokenManager auth.mode 为 jwt 时创建令牌管理器，session 模式下返回 nil
+func newTokenManager(cfg config.AuthConfig) (*jwt.Manager, error) {
+	enabled, err := cfg.JWTEnabled()
+	if err != nil || !enabled {
+		return nil, err
+	}
+	accessTTL, refreshTTL := cfg.JWT.TTLs()
+	return jwt.New([]byte(cfg.JWT.Secret), cfg.JWT.Issuer, accessTTL, refreshTTL), nil
+}
+
+// useEncryption 从密钥服务读取字段加密密钥并启用加密
+func useEncryption(cfg *config.Config) error {
+	provider, err := secrets.New(cfg.Secrets.Provider, cfg.Secrets.EnvPrefix, cfg.Secrets.Dir)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cipher, err := fieldcrypt.FromProvider(ctx, provider, cfg.Encryption.CurrentKey, cfg.Encryption.Keys)
+	if err != nil {
+		return err
+	}
+	fieldcrypt.Use(cipher)
+	return nil
+}
+
+// useIDObfuscation 启用对外接口的用户ID混淆
+func useIDObfuscation(cfg config.IDConfig) error {
+	if cfg.Salt == "" {
+		return fmt.Errorf("id_obfuscation.salt must be set when id_obfuscation is enabled")
+	}
+	codec, err := hashid.New(cfg.Salt, cfg.Alphabet, cfg.MinLength)
+	if err != nil {
+		return err
+	}
+	hashid.Use(codec)
+	return nil
+}
+
+// redisBreakerConfig 转换 Redis 熔断配置，未启用时返回 nil
+func redisBreakerConfig(cfg config.RedisBreakerConfig, logger *zap.Logger) *database.RedisBreakerConfig {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return &database.RedisBreakerConfig{
+		MaxFailures:      cfg.MaxFailures,
+		OpenTimeout:      time.Duration(cfg.OpenTimeout) * time.Second,
+		HalfOpenRequests: cfg.HalfOpenRequests,
+		OnStateChange: func(from, to gobreaker.State) {
+			logger.Warn("Redis circuit breaker state changed",
+				zap.String("from", from.String()),
+				zap.String("to", to.String()),
+			)
+		},
+	}
 }
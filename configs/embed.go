@@ -0,0 +1,9 @@
+// Package configs 内置的默认配置
+package configs
+
+import _ "embed"
+
+// Example 带注释的默认配置，config.Load 以它作为最底层，config init 命令把它写出为配置文件
+//
+//go:embed config.example.yaml
+var Example []byte
@@ -1,5 +1,5 @@
-// Package docs2 Code generated by swaggo/swag. DO NOT EDIT
-package docs2
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
 
 import "github.com/swaggo/swag"
 
@@ -24,52 +24,86 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/orders": {
+        "/api/v1/files/{filepath}": {
             "get": {
-                "description": "Get paginated list of orders",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download an uploaded file (such as an avatar) with a signed link issued by the server. The link carries its own expiry and signature, so no session is needed. When upload scanning is enabled, a file that is still being scanned returns 503 with Retry-After and an infected file returns 422",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "orders"
+                    "files"
                 ],
-                "summary": "List orders",
+                "summary": "Download a file by signed link",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "type": "string",
+                        "description": "File path relative to the upload directory, such as john/avatar.png",
+                        "name": "filepath",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Page size",
-                        "name": "page_size",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Signature from the download link",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "type": "file"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "410": {
+                        "description": "Gone",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "422": {
+                        "description": "File was rejected by the virus scan",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "503": {
+                        "description": "File is being scanned, see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new order with user_id, total_price, description",
+            }
+        },
+        "/api/v1/leaderboards/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the top users of a leaderboard and the session user's own rank. Available leaderboards: top_spenders (total order amount), most_active (order count)",
                 "consumes": [
                     "application/json"
                 ],
@@ -77,45 +111,83 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "leaderboards"
                 ],
-                "summary": "Create a new order",
+                "summary": "Get leaderboard",
                 "parameters": [
                     {
-                        "description": "Order information",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateOrderRequest"
-                        }
+                        "enum": [
+                            "top_spenders",
+                            "most_active"
+                        ],
+                        "type": "string",
+                        "description": "Leaderboard name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of top users, at most 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.LeaderboardResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/orders/{order_number}": {
+        "/api/v1/orders": {
             "get": {
-                "description": "Get order information by order_number",
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get paginated list of orders. With include_archived=true, archived orders are listed together with current ones, newest first.\nAdmins can search orders by the email or phone of the ordering user (exact match, archived orders are not searched); each order in the result carries the user's contact info\nOrders can be filtered by status, creation time range and total price range, total counts the orders matching the filters. Sort by comma separated fields (id, created_at, total_price, status), prefix a field with - for descending order, newest first by default. Filters and sort cannot be combined with include_archived\nWith the cursor parameter (empty for the first page), orders are listed by id in ascending order after the cursor and page is ignored; pass next_cursor of the response to get the next page, total is not counted. Cursor pagination cannot be combined with email, phone, include_archived or sort",
                 "consumes": [
                     "application/json"
                 ],
@@ -125,44 +197,154 @@ const docTemplate = `{
                 "tags": [
                     "orders"
                 ],
-                "summary": "Get order by order_number",
+                "summary": "List orders",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Order Number",
-                        "name": "order_number",
-                        "in": "path",
-                        "required": true
+                        "description": "Username",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email of the ordering user, admin only",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Phone of the ordering user, admin only",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include archived orders",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor for keyset pagination, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "paid",
+                            "shipped",
+                            "completed",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Order status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Created at or after (RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Created before (RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum total price",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum total price",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, e.g. -total_price,created_at",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ListOrdersResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
-                }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
             },
             "put": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "description": "Update order information by order_number",
                 "consumes": [
                     "application/json"
@@ -175,20 +357,13 @@ const docTemplate = `{
                 ],
                 "summary": "Update order information",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Order Number",
-                        "name": "order_number",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "Order information to update",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdateOrderRequest"
+                            "$ref": "#/definitions/gin-app-start_internal_dto.UpdateOrderRequest"
                         }
                     }
                 ],
@@ -196,31 +371,70 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
-                }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
             },
-            "delete": {
-                "description": "Delete order by order_number",
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new order for username with total_price, description. Users can only order for themselves, an admin ordering for another user makes that user the owner. When items are given, the order, its items and the stock decrement are written in one transaction and total_price is computed from the items",
                 "consumes": [
                     "application/json"
                 ],
@@ -230,90 +444,183 @@ const docTemplate = `{
                 "tags": [
                     "orders"
                 ],
-                "summary": "Delete order",
+                "summary": "Create a new order",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Order Number",
-                        "name": "order_number",
-                        "in": "path",
-                        "required": true
+                        "description": "Order information",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateOrderRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
-                }
-            }
-        },
-        "/api/v1/users": {
-            "get": {
-                "description": "Get paginated list of users",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "users"
-                ],
-                "summary": "List users",
-                "parameters": [
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            },
+            "delete": {
+                "security": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "SessionCookie": []
                     },
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Page size",
-                        "name": "page_size",
-                        "in": "query"
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete order by order_number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Delete order",
+                "parameters": [
+                    {
+                        "description": "Order to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.DeleteOrderRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.DeleteOrderRequest"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
-                }
-            },
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/orders/batch_get": {
             "post": {
-                "description": "Create a new user with username, email, phone and password",
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get up to 100 orders by order number in one request. Orders that do not exist or are not visible to the session user are listed in missing, the others are returned in request order",
                 "consumes": [
                     "application/json"
                 ],
@@ -321,17 +628,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "orders"
                 ],
-                "summary": "Create a new user",
+                "summary": "Get orders by order numbers",
                 "parameters": [
                     {
-                        "description": "User information",
+                        "description": "Order numbers",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateUserRequest"
+                            "$ref": "#/definitions/gin-app-start_internal_dto.BatchGetOrdersRequest"
                         }
                     }
                 ],
@@ -339,27 +646,60 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.BatchGetOrdersResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
-                }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
             }
         },
-        "/api/v1/users/{id}": {
-            "get": {
-                "description": "Get user information by user ID",
+        "/api/v1/orders/notes": {
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Append a note to an order; internal notes can only be written and read by admins",
                 "consumes": [
                     "application/json"
                 ],
@@ -367,47 +707,90 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "orders"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Add order note",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Order note",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateOrderNoteRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderNoteResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
-                }
-            },
-            "put": {
-                "description": "Update user information by user ID",
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/orders/search": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get order information by order_number. With include_archived=true, orders moved to the archive table are also searched",
                 "consumes": [
                     "application/json"
                 ],
@@ -415,204 +798,7785 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "orders"
                 ],
-                "summary": "Update user information",
+                "summary": "Get order by order_number",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "User information to update",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateUserRequest"
-                        }
+                        "type": "string",
+                        "description": "Order Number",
+                        "name": "order_number",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Also search archived orders",
+                        "name": "include_archived",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
-                }
-            },
-            "delete": {
-                "description": "Delete user by user ID",
-                "consumes": [
-                    "application/json"
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/orders/stream": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Export all orders of a user (all orders for admin) without pagination. Orders are read from the database in batches by id and each batch is flushed to the client as soon as it is read. The default format is NDJSON (one order per line), format=json streams a single JSON array. Errors after the first batch can only be logged, clients should treat a truncated stream as failed",
                 "produces": [
+                    "application/x-ndjson",
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "orders"
                 ],
-                "summary": "Delete user",
+                "summary": "Stream orders",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ndjson",
+                            "json"
+                        ],
+                        "type": "string",
+                        "default": "ndjson",
+                        "description": "Output format",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/gin-app-start_internal_dto.OrderResponse"
+                            }
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
                         }
                     }
-                }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
             }
         },
-        "/health": {
-            "get": {
-                "description": "Check if the service is running",
+        "/api/v1/orders/{order_number}": {
+            "patch": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update only the fields present in the body, zero values such as total_price 0 or an empty description are applied. Accepts application/json and application/merge-patch+json, null fields are left unchanged",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/merge-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "orders"
+                ],
+                "summary": "Partially update order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order number",
+                        "name": "order_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.PatchOrderRequest"
+                        }
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/response.Response"
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
                                 },
                                 {
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "object",
-                                            "properties": {
-                                                "message": {
-                                                    "type": "string"
-                                                },
-                                                "status": {
-                                                    "type": "string"
-                                                }
-                                            }
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/orders/{order_number}/items": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the line items of an order with the order total computed from them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List order items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order Number",
+                        "name": "order_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderItemsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            },
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a product to an order at its current price. Adding a product already in the order increases its quantity. The order total is recomputed from all items in the same transaction",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Add order item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order Number",
+                        "name": "order_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order item",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.AddOrderItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderItemsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/orders/{order_number}/items/{item_id}": {
+            "put": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change the quantity of an order item and recompute the order total",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Update order item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order Number",
+                        "name": "order_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quantity",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.UpdateOrderItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderItemsResponse"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            },
+            "delete": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
                     }
+                ],
+                "description": "Remove an item from an order and recompute the order total",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Remove order item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order Number",
+                        "name": "order_number",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderItemsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/orgs": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List organizations the session user belongs to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List my organizations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.OrganizationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            },
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create an organization, the session user becomes its owner",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Create organization",
+                "parameters": [
+                    {
+                        "description": "Organization",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateOrganizationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrganizationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            }
+        },
+        "/api/v1/orgs/invitations/accept": {
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Join an organization with an invitation token. The session user's email must match the invited email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Accept organization invitation",
+                "parameters": [
+                    {
+                        "description": "Invitation token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.AcceptInvitationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrganizationMemberResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            }
+        },
+        "/api/v1/orgs/{id}/invitations": {
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Owners invite a user by email. The invitation token is mailed when mail is configured and is always returned to the inviter; it expires after 7 days",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Invite organization member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invitation",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.InviteMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.InvitationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/orgs/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List members of an organization, only members can see them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organization members",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.OrganizationMemberResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/orgs/{id}/members/{user_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Owners can remove any member, members can remove themselves to leave. The last owner cannot be removed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "Remove organization member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID, encoded when id_obfuscation is enabled",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/orgs/{id}/orders": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List orders placed on behalf of an organization, newest first. Any member can see them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organizations"
+                ],
+                "summary": "List organization orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Organization ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/gin-app-start_pkg_response.Paged"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/gin-app-start_internal_dto.OrderResponse"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/products": {
+            "get": {
+                "description": "List products in the catalog by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.ProductResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/{product_id}": {
+            "get": {
+                "description": "Get a product by its SKU",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ProductResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/products/{product_id}/views": {
+            "get": {
+                "description": "Get the total number of views of a product, including views not yet written to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Product view count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ViewCountResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Count one view of a product. Views are buffered in Redis and written to the database in batches, so they show up in the view count right away but may be lost if views.loss_tolerant is enabled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Record product view",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/shipments": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the shipments of an order with their tracking events",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipments"
+                ],
+                "summary": "List order shipments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order Number",
+                        "name": "order_number",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.ShipmentResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/shipments/webhook/{carrier}": {
+            "post": {
+                "description": "Receive tracking events pushed by a carrier. The body must be signed with the carrier's webhook secret: X-Signature = hex(HMAC-SHA256(secret, body)). Duplicate events are ignored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipments"
+                ],
+                "summary": "Carrier webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Carrier code",
+                        "name": "carrier",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Body signature",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Tracking events",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.ShipmentWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ShipmentWebhookResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/shipments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get a shipment and its tracking events",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shipments"
+                ],
+                "summary": "Track shipment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ShipmentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/stores/nearby": {
+            "get": {
+                "description": "Find stores within a radius of a location, nearest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stores"
+                ],
+                "summary": "Nearby stores",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude",
+                        "name": "latitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude",
+                        "name": "longitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 5,
+                        "description": "Radius in kilometers, at most 50",
+                        "name": "radius",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Max number of stores, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.NearbyStoreResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get paginated list of users. Users can be filtered by status, username prefix and creation time range, total counts the users matching the filters. Sort by comma separated fields (id, created_at, username), prefix a field with - for descending order, by id by default\nWith the cursor parameter (empty for the first page), users are listed by id in ascending order after the cursor and page is ignored; pass next_cursor of the response to get the next page, total is not counted. Cursor pagination cannot be combined with sort",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor for keyset pagination, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "User status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username prefix",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Created at or after (RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Created before (RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, e.g. -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ListUsersResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "admin"
+                ]
+            },
+            "post": {
+                "description": "Create a new user with username, email, phone and password. The password must satisfy the password policy, each violated rule is listed in the message",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User information, invite_code credits the referrer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/api_keys": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the session user's API keys including revoked ones, newest first. Plaintext keys are never returned",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List my API keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.APIKeyResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            },
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create an API key for scripts and integrations. Requests with the key in the X-API-Key header act as the session user. The plaintext key is only returned once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create API key",
+                "parameters": [
+                    {
+                        "description": "API key name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateAPIKeyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            }
+        },
+        "/api/v1/users/api_keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke one of the session user's API keys, requests with the key are rejected immediately",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Revoke API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            }
+        },
+        "/api/v1/users/change_pwd": {
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Change a user's password with old password and new password. The new password must satisfy the password policy. Requires a recent login or re-authentication",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change a user's password",
+                "parameters": [
+                    {
+                        "description": "User update password information",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.UpdatePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/users/file": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Check that the caller may view the image, then redirect to a signed download link for it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user image by username and image name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "image name",
+                        "name": "imageName",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the signed download link",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/users/impersonate": {
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    }
+                ],
+                "description": "Admin only. Act as another user to debug user-specific issues: until stopped or max_duration passes, the session is treated as that user by every ownership check and admin privileges do not apply. Responses carry a banner and the X-Impersonated-By header, every request is written to the audit log, and operations requiring recent authentication are refused. Requires a recent login or re-authentication",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Impersonate a user",
+                "parameters": [
+                    {
+                        "description": "User to impersonate and the reason",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.ImpersonateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ImpersonationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "admin"
+                ]
+            },
+            "delete": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    }
+                ],
+                "description": "End the current impersonation, the session becomes the admin again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Stop impersonating",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ImpersonationResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/users/login": {
+            "post": {
+                "description": "Login user with username and password. With auth.mode=session a session cookie is set; with auth.mode=jwt no session is created and the response carries an access token and a refresh token, send the access token as \"Authorization: Bearer \u003caccess_token\u003e\"",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Login user",
+                "parameters": [
+                    {
+                        "description": "User login information",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "avatar": {
+                                                    "type": "string"
+                                                },
+                                                "email": {
+                                                    "type": "string"
+                                                },
+                                                "phone": {
+                                                    "type": "string"
+                                                },
+                                                "token": {
+                                                    "$ref": "#/definitions/gin-app-start_internal_dto.TokenResponse"
+                                                },
+                                                "userId": {
+                                                    "type": "integer"
+                                                },
+                                                "username": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed logins, see Retry-After",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Destroy the current session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Logout user",
+                "parameters": [
+                    {
+                        "description": "User to logout",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/users/me/settings": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the session user's settings (locale, theme, notifications.email, notifications.sms, notifications.broadcast), settings never changed are returned with their defaults",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Get settings",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            },
+            "patch": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update the settings present in the body and leave the others unchanged, a null value resets the setting to its default. Unknown settings, wrong types and values outside the allowed ones are rejected with per-setting details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settings"
+                ],
+                "summary": "Update settings",
+                "parameters": [
+                    {
+                        "description": "Settings to change, e.g. {\\",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            }
+        },
+        "/api/v1/users/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the session user's in-app notifications, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List my notifications",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.NotificationResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            }
+        },
+        "/api/v1/users/reauth": {
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Verify the current user's password again. Changing password, changing email and deleting account require a login or re-authentication within session.reauth_max_age, otherwise they return 401 with the X-Reauth-Required header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Re-authenticate",
+                "parameters": [
+                    {
+                        "description": "Current password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.ReauthRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ReauthResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/users/referral": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the session user's invitation code (generated on first call), invited user count and earned credits",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get my invitation code",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ReferralResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            }
+        },
+        "/api/v1/users/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and refresh token (auth.mode=jwt only). Each refresh token can be used once; presenting an already used refresh token revokes every token refreshed from the same login, log in again to continue",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Refresh tokens",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.TokenResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/upload_avatar": {
+            "post": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Upload avatar image for user as multipart form data, or as JSON {\"username\": \"...\", \"image_base64\": \"...\"} with Content-Type application/json for clients that cannot send multipart. JSON images are limited to file.max_size bytes and must be JPEG, PNG, GIF or WebP. Returns a signed download link that expires after file.link_ttl seconds. When upload scanning is enabled the link returns 503 until the scan finishes",
+                "consumes": [
+                    "multipart/form-data",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Upload Avatar Image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "User avatar image",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Base64 image exceeds file.max_size",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/api/v1/users/wishlist": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the session user's favorited products, most recently saved first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "List my wishlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.FavoriteResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            }
+        },
+        "/api/v1/users/wishlist/{product_id}": {
+            "put": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Add a product to the session user's wishlist, favoriting an already favorited product is a no-op",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Favorite product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            },
+            "delete": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Remove a product from the session user's wishlist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "wishlist"
+                ],
+                "summary": "Unfavorite product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner"
+                ]
+            }
+        },
+        "/api/v1/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get user information by user ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, encoded when id_obfuscation is enabled",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            },
+            "put": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update user information by user ID. Changing email requires a recent login or re-authentication",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, encoded when id_obfuscation is enabled",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User information to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            },
+            "delete": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete user by user ID. Requires a recent login or re-authentication",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, encoded when id_obfuscation is enabled",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            },
+            "patch": {
+                "security": [
+                    {
+                        "SessionCookie": []
+                    },
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update only the fields present in the body, zero values such as status 0 are applied. Accepts application/json and application/merge-patch+json, null fields are left unchanged. Changing email requires a recent login or re-authentication",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Partially update user information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, encoded when id_obfuscation is enabled",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.PatchUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.UserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                },
+                "x-roles": [
+                    "owner",
+                    "admin"
+                ]
+            }
+        },
+        "/broadcasts": {
+            "get": {
+                "description": "List broadcasts with their status and progress, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List broadcasts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.BroadcastResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Schedule an in-app and/or email broadcast to the users carrying the given tags at a future time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Schedule broadcast",
+                "parameters": [
+                    {
+                        "description": "Broadcast",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateBroadcastRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.BroadcastResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/broadcasts/{id}": {
+            "get": {
+                "description": "Get a broadcast with its status and delivery progress",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get broadcast",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Broadcast ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.BroadcastResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/broadcasts/{id}/cancel": {
+            "post": {
+                "description": "Cancel a pending or running broadcast, a running broadcast stops after the current batch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Cancel broadcast",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Broadcast ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cache": {
+            "get": {
+                "description": "Get type, ttl and contents of a cache key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Inspect cache key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cache key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.CacheKeyResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a cache key by its exact name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete cache key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cache key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/cache/order_list/warm": {
+            "post": {
+                "description": "Reload every cached order list page from the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Re-warm order list caches",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.WarmCacheResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/config": {
+            "get": {
+                "description": "Dump the configuration loaded by this instance, with passwords and keys masked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Effective configuration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/dependencies": {
+            "get": {
+                "description": "Get connectivity status of Postgres and Redis as seen by the background health checker",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Dependency status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dependency.Status"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/errors/recent": {
+            "get": {
+                "description": "List the most recent error, panic and fatal log entries kept in memory (log.recent_errors), newest first. Each entry is the JSON log entry as written to the log file. Entries are lost on restart.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Recent errors",
+                "parameters": [
+                    {
+                        "maximum": 10000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Number of entries, defaults to all kept entries",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.RecentErrorsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/geo/rebuild": {
+            "post": {
+                "description": "Rebuild the Redis GEO indexes of stores and order delivery locations from the database. Nearby results may be incomplete while rebuilding",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Rebuild geo index",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.GeoRebuildResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the process is running, dependencies are not checked so an unavailable database does not get the pod restarted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "message": {
+                                                    "type": "string"
+                                                },
+                                                "status": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Check if the process is running, dependencies are not checked so an unavailable database does not get the pod restarted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "message": {
+                                                    "type": "string"
+                                                },
+                                                "status": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboards/{name}/rebuild": {
+            "post": {
+                "description": "Recompute a leaderboard from the orders table, e.g. after Redis data loss. The leaderboard may be incomplete while rebuilding",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Rebuild leaderboard",
+                "parameters": [
+                    {
+                        "enum": [
+                            "top_spenders",
+                            "most_active"
+                        ],
+                        "type": "string",
+                        "description": "Leaderboard name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.LeaderboardRebuildResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/tail": {
+            "get": {
+                "description": "Stream application logs over WebSocket (wscat -c ws://localhost:9061/logs/tail). The most recent entries (see lines) are sent first, then new entries as they are written; each text message is one JSON log entry. Entries are dropped when the client reads too slowly.",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Tail logs",
+                "parameters": [
+                    {
+                        "maximum": 10000,
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Number of recent entries sent first",
+                        "name": "lines",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "debug",
+                            "info",
+                            "warn",
+                            "error"
+                        ],
+                        "type": "string",
+                        "description": "Minimum level",
+                        "name": "level",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/nearby": {
+            "get": {
+                "description": "Find orders whose delivery location is within a radius of a location, nearest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Nearby orders",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude",
+                        "name": "latitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude",
+                        "name": "longitude",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 5,
+                        "description": "Radius in kilometers, at most 50",
+                        "name": "radius",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Max number of orders, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.NearbyOrderResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/projection/rebuild": {
+            "post": {
+                "description": "Regenerate the order list read model (order_summaries) from the orders table, e.g. after an instance crashed before applying pending order changes. Lists stay available but may lag while rebuilding",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Rebuild order list projection",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderProjectionRebuildResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "post": {
+                "description": "Add a product to the catalog so it can be added to orders",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create product",
+                "parameters": [
+                    {
+                        "description": "Product",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ProductResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{product_id}": {
+            "put": {
+                "description": "Change the name or price of a product. Items already added to orders keep the name and price they were added with",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.UpdateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ProductResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a product from the catalog. Items already added to orders are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/ready": {
+            "get": {
+                "description": "Ping every dependency with a timeout and report its status and latency. Returns 503 with the same status and dependencies in data when a required one (Postgres) is down; optional ones (Redis) only degrade the service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "dependencies": {
+                                                    "type": "array",
+                                                    "items": {
+                                                        "$ref": "#/definitions/gin-app-start_internal_dependency.Status"
+                                                    }
+                                                },
+                                                "status": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "dependencies": {
+                                                    "type": "array",
+                                                    "items": {
+                                                        "$ref": "#/definitions/gin-app-start_internal_dependency.Status"
+                                                    }
+                                                },
+                                                "status": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Ping every dependency with a timeout and report its status and latency. Returns 503 with the same status and dependencies in data when a required one (Postgres) is down; optional ones (Redis) only degrade the service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "dependencies": {
+                                                    "type": "array",
+                                                    "items": {
+                                                        "$ref": "#/definitions/gin-app-start_internal_dependency.Status"
+                                                    }
+                                                },
+                                                "status": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "object",
+                                            "properties": {
+                                                "dependencies": {
+                                                    "type": "array",
+                                                    "items": {
+                                                        "$ref": "#/definitions/gin-app-start_internal_dependency.Status"
+                                                    }
+                                                },
+                                                "status": {
+                                                    "type": "string"
+                                                }
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/recordings/{trace_id}": {
+            "get": {
+                "description": "Get the recorded request/response pair of a sampled request by trace id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get request recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trace ID",
+                        "name": "trace_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.Recording"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/referrals/stats": {
+            "get": {
+                "description": "Get issued invitation codes, referred users, signup conversion and top referrers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Referral conversion statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ReferralStatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/requests/users": {
+            "get": {
+                "description": "List users with requests in the sliding window of concurrency.per_user, ordered by requests in progress and then by request count, across all instances. Use it to find abusive clients and cap them in concurrency.per_user.users",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List request statistics by user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Max number of users, at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.RequestStatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/requests/users/{username}": {
+            "get": {
+                "description": "Get the requests in progress and the request count in the sliding window of one user. Counts are 0 for users without recent requests",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Request statistics of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.RequestStatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/security/blocked": {
+            "get": {
+                "description": "List accounts and IPs locked after too many failed logins, and clients currently throttled by the rate limiter of this instance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List blocked accounts and IPs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.BlockedResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/security/unblock": {
+            "post": {
+                "description": "Remove a login lockout (kind account or ip) or reset the rate limit of a client on this instance (kind rate_limit). Every unblock is written to the audit log with the operator",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Unblock account or IP",
+                "parameters": [
+                    {
+                        "description": "Block to remove",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.UnblockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/segments/export": {
+            "get": {
+                "description": "Download users carrying any (match=any) or all (match=all) of the given tags as CSV, e.g. as the audience of a targeted notification",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export segment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag names",
+                        "name": "tags",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Match any or all tags",
+                        "name": "match",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/segments/users": {
+            "get": {
+                "description": "List users carrying any (match=any) or all (match=all) of the given tags",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Query users by tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated tag names",
+                        "name": "tags",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "any",
+                        "description": "Match any or all tags",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.UserResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/shipments": {
+            "post": {
+                "description": "Register a carrier tracking number for an order, tracking events then come from the carrier webhook or poller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Register shipment",
+                "parameters": [
+                    {
+                        "description": "Shipment",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateShipmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ShipmentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/shipments/{id}/events": {
+            "post": {
+                "description": "Manually record tracking events for a shipment, e.g. for carriers without webhook or tracking API",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Add shipment events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Shipment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tracking events",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.AddShipmentEventsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.ShipmentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores": {
+            "post": {
+                "description": "Create a store and add it to the nearby search index",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create store",
+                "parameters": [
+                    {
+                        "description": "Store",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.CreateStoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/gin-app-start_internal_dto.StoreResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/stores/{id}": {
+            "delete": {
+                "description": "Delete a store and remove it from the nearby search index",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete store",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Store ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "List all user tags with the number of users carrying each tag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.TagResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/tags": {
+            "get": {
+                "description": "List the tags of a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List user tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, encoded when id_obfuscation is enabled",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.TagResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add tags to a user, tags that do not exist yet are created",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Tag user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, encoded when id_obfuscation is enabled",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags to add",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.TagUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/gin-app-start_internal_dto.TagResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/tags/{tag}": {
+            "delete": {
+                "description": "Remove a tag from a user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Untag user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, encoded when id_obfuscation is enabled",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tag name",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Get version, git commit, build time, Go version and config environment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Build information",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/gin-app-start_internal_common.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_controller.versionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "gin-app-start_internal_common.Response": {
+            "type": "object",
+            "properties": {
+                "banner": {
+                    "description": "需要客户端醒目展示的提示，如管理员正在代入用户",
+                    "type": "string",
+                    "example": "admin is impersonating john_doe (user 12) until 2023-01-01T00:30:00Z, all actions are audited"
+                },
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {},
+                "debug": {
+                    "description": "管理员携带请求头 X-Debug: 1 时返回本次请求执行的 SQL、Redis 命令及耗时",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/gin-app-start_pkg_trace.Summary"
+                        }
+                    ]
+                },
+                "details": {
+                    "description": "参数校验失败时逐个列出不合法的字段",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_pkg_response.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "page": {
+                    "$ref": "#/definitions/gin-app-start_pkg_response.Page"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "trace-id-123"
+                }
+            }
+        },
+        "gin-app-start_internal_dependency.Status": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "last_check": {
+                    "description": "最近一次探活时间",
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "description": "最近一次探活的耗时，单位毫秒；超时时为超时时间",
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "since": {
+                    "description": "进入当前状态的时间",
+                    "type": "string"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2023-01-02T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nightly export"
+                },
+                "prefix": {
+                    "description": "明文的前几位",
+                    "type": "string",
+                    "example": "gak_3f9a1c"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2023-01-03T00:00:00Z"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.AcceptInvitationRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "3f2a9c0d4b5e6f708192a3b4c5d6e7f8"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.AddOrderItemRequest": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "SKU-10001"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 9999,
+                    "minimum": 1,
+                    "example": 2
+                }
+            }
+        },
+        "gin-app-start_internal_dto.AddShipmentEventsRequest": {
+            "type": "object",
+            "required": [
+                "events"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.ShipmentEventRequest"
+                    }
+                }
+            }
+        },
+        "gin-app-start_internal_dto.BatchGetOrdersRequest": {
+            "type": "object",
+            "required": [
+                "order_numbers"
+            ],
+            "properties": {
+                "order_numbers": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "EC20231215123456",
+                        "EC20231215654321"
+                    ]
+                }
+            }
+        },
+        "gin-app-start_internal_dto.BatchGetOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.OrderResponse"
+                    }
+                }
+            }
+        },
+        "gin-app-start_internal_dto.BlockedResponse": {
+            "type": "object",
+            "properties": {
+                "lockout_enabled": {
+                    "description": "未启用登录锁定或 Redis 未启用时为 false",
+                    "type": "boolean",
+                    "example": true
+                },
+                "locks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.LockResponse"
+                    }
+                },
+                "rate_limited": {
+                    "description": "仅当前实例",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.RateLimitedResponse"
+                    }
+                }
+            }
+        },
+        "gin-app-start_internal_dto.BroadcastResponse": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "in_app",
+                        "email"
+                    ]
+                },
+                "content": {
+                    "type": "string",
+                    "example": "20% off for all VIP members"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "email_failed": {
+                    "description": "邮件发送失败数",
+                    "type": "integer",
+                    "example": 2
+                },
+                "email_sent": {
+                    "description": "邮件发送成功数",
+                    "type": "integer",
+                    "example": 180
+                },
+                "error": {
+                    "type": "string",
+                    "example": ""
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2030-01-01T09:03:00+08:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "match": {
+                    "type": "string",
+                    "example": "any"
+                },
+                "processed": {
+                    "description": "已处理的用户数",
+                    "type": "integer",
+                    "example": 200
+                },
+                "scheduled_at": {
+                    "type": "string",
+                    "example": "2030-01-01T09:00:00+08:00"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2030-01-01T09:00:01+08:00"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "running",
+                        "completed",
+                        "cancelled",
+                        "failed"
+                    ],
+                    "example": "running"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vip",
+                        "beta"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Spring sale"
+                },
+                "total": {
+                    "description": "分群用户数，开始发送时统计",
+                    "type": "integer",
+                    "example": 1000
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CacheKeyResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "order:EC20231215123456"
+                },
+                "ttl": {
+                    "description": "剩余过期时间，单位秒；-1 表示未设置过期时间",
+                    "type": "integer",
+                    "example": 1800
+                },
+                "type": {
+                    "type": "string",
+                    "example": "string"
+                },
+                "value": {}
+            }
+        },
+        "gin-app-start_internal_dto.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "用途说明，便于之后辨认和吊销",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "nightly export"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "key": {
+                    "description": "请求时放在 X-API-Key 头中",
+                    "type": "string",
+                    "example": "gak_3f9a1c0Qm8tZr2YvLk7HdXcWb5NsEa"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2023-01-02T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nightly export"
+                },
+                "prefix": {
+                    "description": "明文的前几位",
+                    "type": "string",
+                    "example": "gak_3f9a1c"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2023-01-03T00:00:00Z"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CreateBroadcastRequest": {
+            "type": "object",
+            "required": [
+                "channels",
+                "content",
+                "scheduled_at",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "in_app",
+                        "email"
+                    ]
+                },
+                "content": {
+                    "type": "string",
+                    "maxLength": 10000,
+                    "example": "20% off for all VIP members"
+                },
+                "match": {
+                    "type": "string",
+                    "enum": [
+                        "any",
+                        "all"
+                    ],
+                    "example": "any"
+                },
+                "scheduled_at": {
+                    "type": "string",
+                    "example": "2030-01-01T09:00:00+08:00"
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vip",
+                        "beta"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "Spring sale"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CreateOrderNoteRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "order_number"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "Please deliver after 6pm"
+                },
+                "internal": {
+                    "description": "内部备注仅管理员可写、可见",
+                    "type": "boolean",
+                    "example": false
+                },
+                "order_number": {
+                    "type": "string",
+                    "example": "EC20231215123456"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CreateOrderRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Order for John Doe"
+                },
+                "items": {
+                    "description": "订单明细，可选；与订单在同一事务中写入并扣减库存，任一商品库存不足时订单不会创建",
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.AddOrderItemRequest"
+                    }
+                },
+                "latitude": {
+                    "description": "收货地坐标，可选，需同时提供",
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": 31.2304
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": 121.4737
+                },
+                "order_type": {
+                    "description": "订单类型，决定订单号格式，为空时使用默认格式",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "wholesale"
+                },
+                "organization_id": {
+                    "description": "以组织名义下单，需为组织成员，组织成员均可查看该订单",
+                    "type": "integer",
+                    "example": 1
+                },
+                "total_price": {
+                    "description": "提供 items 时按明细计算，不能填写",
+                    "type": "number",
+                    "example": 99.99
+                },
+                "user_id": {
+                    "description": "已忽略，下单用户由 username 确定",
+                    "type": "integer",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "example": "John Doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CreateOrganizationRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "Acme Inc."
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "price",
+                "product_id"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "Coffee mug"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 25
+                },
+                "product_id": {
+                    "description": "商品 SKU，收藏、浏览计数等接口中的商品ID",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "SKU-10001"
+                },
+                "stock": {
+                    "description": "库存，不填表示不限库存",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 100
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CreateShipmentRequest": {
+            "type": "object",
+            "required": [
+                "carrier",
+                "order_number",
+                "tracking_number"
+            ],
+            "properties": {
+                "carrier": {
+                    "description": "承运商代码，与回调地址和 shipment.webhook_secrets 中的名称一致",
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "sf"
+                },
+                "order_number": {
+                    "type": "string",
+                    "example": "EC20231215123456"
+                },
+                "tracking_number": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "SF1234567890"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CreateStoreRequest": {
+            "type": "object",
+            "required": [
+                "latitude",
+                "longitude",
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 256,
+                    "example": "120 Nanjing Road, Shanghai"
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": 31.2304
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": 121.4737
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "People's Square Store"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "invite_code": {
+                    "description": "邀请码，邀请人获得奖励",
+                    "type": "string",
+                    "maxLength": 16,
+                    "example": "K7QX2M9P"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 6,
+                    "example": "password123"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "13800138000"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3,
+                    "example": "John Doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.DeleteOrderRequest": {
+            "type": "object",
+            "required": [
+                "order_number",
+                "username"
+            ],
+            "properties": {
+                "order_number": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "John Doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.FavoriteResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "SKU-10001"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.GeoRebuildResponse": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "description": "写入索引的订单数",
+                    "type": "integer",
+                    "example": 860
+                },
+                "stores": {
+                    "description": "写入索引的门店数",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ImpersonateRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "user_id"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 256,
+                    "example": "Debug ticket #1024: order list is empty"
+                },
+                "user_id": {
+                    "description": "启用 id_obfuscation 时为编码后的字符串",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ImpersonationResponse": {
+            "type": "object",
+            "properties": {
+                "admin_name": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:30:00Z"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Debug ticket #1024: order list is empty"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.InvitationResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2023-01-08T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "role": {
+                    "type": "string",
+                    "example": "member"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "3f2a9c0d4b5e6f708192a3b4c5d6e7f8"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.InviteMemberRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "john@example.com"
+                },
+                "role": {
+                    "description": "默认为 member",
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "member"
+                    ],
+                    "example": "member"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.LeaderboardEntry": {
+            "type": "object",
+            "properties": {
+                "rank": {
+                    "description": "名次，从 1 开始",
+                    "type": "integer",
+                    "example": 1
+                },
+                "score": {
+                    "description": "top_spenders 为消费总额，most_active 为订单数",
+                    "type": "number",
+                    "example": 1999.5
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.LeaderboardRebuildResponse": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "top_spenders"
+                },
+                "users": {
+                    "description": "上榜用户数",
+                    "type": "integer",
+                    "example": 1024
+                }
+            }
+        },
+        "gin-app-start_internal_dto.LeaderboardResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.LeaderboardEntry"
+                    }
+                },
+                "me": {
+                    "description": "当前用户不在榜上时为 null",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.LeaderboardEntry"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "top_spenders"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ListOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "游标分页时下一页的游标，没有下一页时为空，不统计 total",
+                    "type": "string",
+                    "example": "NDI"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.OrderResponse"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ListUsersResponse": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "游标分页时下一页的游标，没有下一页时为空，不统计 total",
+                    "type": "string",
+                    "example": "NDI"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.UserResponse"
+                    }
+                }
+            }
+        },
+        "gin-app-start_internal_dto.LockResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:15:00Z"
+                },
+                "failures": {
+                    "description": "触发锁定时的失败次数",
+                    "type": "integer",
+                    "example": 5
+                },
+                "kind": {
+                    "description": "account 或 ip",
+                    "type": "string",
+                    "example": "account"
+                },
+                "locked_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 6,
+                    "example": "password123"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3,
+                    "example": "John Doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.LogoutRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "username": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 3,
+                    "example": "John Doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.NearbyOrderResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Order for product A"
+                },
+                "distance": {
+                    "description": "距离，单位公里",
+                    "type": "number",
+                    "example": 1.25
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 31.2304
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 121.4737
+                },
+                "notes": {
+                    "description": "仅订单详情返回，列表不加载备注",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.OrderNoteResponse"
+                    }
+                },
+                "order_number": {
+                    "type": "string",
+                    "example": "EC20231215123456"
+                },
+                "organization_id": {
+                    "description": "个人订单为空",
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "paid",
+                        "shipped",
+                        "completed",
+                        "cancelled"
+                    ],
+                    "example": "pending"
+                },
+                "total_price": {
+                    "type": "number",
+                    "example": 100
+                },
+                "update_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "user": {
+                    "description": "下单用户，仅管理员按用户搜索订单时返回",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderUserResponse"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.NearbyStoreResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "120 Nanjing Road, Shanghai"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "distance": {
+                    "description": "距离，单位公里",
+                    "type": "number",
+                    "example": 1.25
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 31.2304
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 121.4737
+                },
+                "name": {
+                    "type": "string",
+                    "example": "People's Square Store"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.NotificationResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "20% off for all VIP members"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "read_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Spring sale"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.OrderItemResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Coffee mug"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "SKU-10001"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "subtotal": {
+                    "type": "number",
+                    "example": 50
+                },
+                "unit_price": {
+                    "description": "加入订单时的单价",
+                    "type": "number",
+                    "example": 25
+                }
+            }
+        },
+        "gin-app-start_internal_dto.OrderItemsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.OrderItemResponse"
+                    }
+                },
+                "order_number": {
+                    "type": "string",
+                    "example": "EC20231215123456"
+                },
+                "total_price": {
+                    "type": "number",
+                    "example": 50
+                }
+            }
+        },
+        "gin-app-start_internal_dto.OrderNoteResponse": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "author_name": {
+                    "type": "string",
+                    "example": "john_doe"
+                },
+                "content": {
+                    "type": "string",
+                    "example": "Please deliver after 6pm"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "internal": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "update_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.OrderProjectionRebuildResponse": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "description": "写入读模型的订单数",
+                    "type": "integer",
+                    "example": 120000
+                }
+            }
+        },
+        "gin-app-start_internal_dto.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Order for product A"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 31.2304
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 121.4737
+                },
+                "notes": {
+                    "description": "仅订单详情返回，列表不加载备注",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.OrderNoteResponse"
+                    }
+                },
+                "order_number": {
+                    "type": "string",
+                    "example": "EC20231215123456"
+                },
+                "organization_id": {
+                    "description": "个人订单为空",
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "paid",
+                        "shipped",
+                        "completed",
+                        "cancelled"
+                    ],
+                    "example": "pending"
+                },
+                "total_price": {
+                    "type": "number",
+                    "example": 100
+                },
+                "update_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "user": {
+                    "description": "下单用户，仅管理员按用户搜索订单时返回",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.OrderUserResponse"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.OrderUserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "13800138000"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.OrganizationMemberResponse": {
+            "type": "object",
+            "properties": {
+                "joined_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "member"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.OrganizationResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Acme Inc."
+                },
+                "owner_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "gin-app-start_internal_dto.PatchOrderRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": ""
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "paid",
+                        "shipped",
+                        "completed",
+                        "cancelled"
+                    ],
+                    "example": "cancelled"
+                },
+                "total_price": {
+                    "description": "订单已有明细时总价按明细计算，不能修改",
+                    "type": "number",
+                    "minimum": 0,
+                    "example": 0
+                }
+            }
+        },
+        "gin-app-start_internal_dto.PatchUserRequest": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string",
+                    "example": "https://example.com/avatar.jpg"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "13800138000"
+                },
+                "status": {
+                    "type": "integer",
+                    "enum": [
+                        0,
+                        1
+                    ],
+                    "example": 0
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ProductResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Coffee mug"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 25
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "SKU-10001"
+                },
+                "stock": {
+                    "description": "为 null 表示不限库存",
+                    "type": "integer",
+                    "example": 100
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.RateLimitedResponse": {
+            "type": "object",
+            "properties": {
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "last_access": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ReauthRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 6,
+                    "example": "password123"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ReauthResponse": {
+            "type": "object",
+            "properties": {
+                "authenticated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:05:00Z"
+                },
+                "token": {
+                    "description": "jwt 模式下重新签发的令牌，之后的请求需改用新令牌",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/gin-app-start_internal_dto.TokenResponse"
+                        }
+                    ]
+                }
+            }
+        },
+        "gin-app-start_internal_dto.RecentErrorsResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "未开启 log.recent_errors 时为 false，Entries 为空",
+                    "type": "boolean",
+                    "example": true
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                }
+            }
+        },
+        "gin-app-start_internal_dto.Recording": {
+            "type": "object",
+            "properties": {
+                "client_ip": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "cost_seconds": {
+                    "type": "number",
+                    "example": 0.012
+                },
+                "http_code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "method": {
+                    "type": "string",
+                    "example": "GET"
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "request_body": {
+                    "type": "string"
+                },
+                "request_header": {
+                    "$ref": "#/definitions/http.Header"
+                },
+                "response_body": {
+                    "type": "string"
+                },
+                "response_header": {
+                    "$ref": "#/definitions/http.Header"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "/api/v1/orders"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "5d3c1a2b9e8f7a6b5c4d"
+                },
+                "truncated": {
+                    "description": "请求体或响应体超出 max_body_size 被截断",
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/api/v1/orders?page=1"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ReferralResponse": {
+            "type": "object",
+            "properties": {
+                "credits": {
+                    "description": "累计获得的邀请奖励",
+                    "type": "integer",
+                    "example": 3
+                },
+                "invite_code": {
+                    "type": "string",
+                    "example": "K7QX2M9P"
+                },
+                "invited_count": {
+                    "description": "通过该邀请码注册的用户数",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ReferralStatsResponse": {
+            "type": "object",
+            "properties": {
+                "code_conversion_rate": {
+                    "description": "referrers / issued_codes",
+                    "type": "number",
+                    "example": 0.25
+                },
+                "issued_codes": {
+                    "description": "已生成邀请码的用户数",
+                    "type": "integer",
+                    "example": 120
+                },
+                "referred_users": {
+                    "description": "通过邀请码注册的用户数",
+                    "type": "integer",
+                    "example": 75
+                },
+                "referrers": {
+                    "description": "至少成功邀请一人的用户数",
+                    "type": "integer",
+                    "example": 30
+                },
+                "signup_attempts": {
+                    "description": "携带邀请码的注册请求数，含无效邀请码",
+                    "type": "integer",
+                    "example": 90
+                },
+                "signup_conversion_rate": {
+                    "description": "signups / signup_attempts",
+                    "type": "number",
+                    "example": 0.833
+                },
+                "signups": {
+                    "description": "携带有效邀请码并注册成功的请求数",
+                    "type": "integer",
+                    "example": 75
+                },
+                "top_referrers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.ReferrerStat"
+                    }
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ReferrerStat": {
+            "type": "object",
+            "properties": {
+                "invited": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                }
+            }
+        },
+        "gin-app-start_internal_dto.RequestStatsResponse": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "未启用 concurrency.per_user 或 Redis 未启用时为 false",
+                    "type": "boolean",
+                    "example": true
+                },
+                "users": {
+                    "description": "按并发数、请求数从高到低排列",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.UserRequestStatsResponse"
+                    }
+                },
+                "window": {
+                    "description": "请求数的滑动窗口长度，单位秒",
+                    "type": "integer",
+                    "example": 60
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ShipmentEventRequest": {
+            "type": "object",
+            "required": [
+                "occurred_at",
+                "status"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 512,
+                    "example": "Departed from sorting center"
+                },
+                "location": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "Shenzhen"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2023-01-01T08:00:00+08:00"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "in_transit",
+                        "out_for_delivery",
+                        "delivered",
+                        "exception",
+                        "returned"
+                    ],
+                    "example": "in_transit"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ShipmentEventResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Departed from sorting center"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "Shenzhen"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2023-01-01T08:00:00+08:00"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "in_transit",
+                        "out_for_delivery",
+                        "delivered",
+                        "exception",
+                        "returned"
+                    ],
+                    "example": "in_transit"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ShipmentResponse": {
+            "type": "object",
+            "properties": {
+                "carrier": {
+                    "type": "string",
+                    "example": "sf"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "events": {
+                    "description": "按发生时间排列",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.ShipmentEventResponse"
+                    }
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_event_at": {
+                    "type": "string",
+                    "example": "2023-01-01T08:00:00+08:00"
+                },
+                "order_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "in_transit",
+                        "out_for_delivery",
+                        "delivered",
+                        "exception",
+                        "returned"
+                    ],
+                    "example": "in_transit"
+                },
+                "tracking_number": {
+                    "type": "string",
+                    "example": "SF1234567890"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ShipmentWebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "tracking_number"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_internal_dto.ShipmentEventRequest"
+                    }
+                },
+                "tracking_number": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "SF1234567890"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.ShipmentWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "description": "新记录的事件数，重复推送的事件不计入",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "gin-app-start_internal_dto.StoreResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "120 Nanjing Road, Shanghai"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 31.2304
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 121.4737
+                },
+                "name": {
+                    "type": "string",
+                    "example": "People's Square Store"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.TagResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "vip"
+                },
+                "users": {
+                    "description": "打了该标签的用户数，仅标签列表返回",
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "gin-app-start_internal_dto.TagUserRequest": {
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vip",
+                        "beta"
+                    ]
+                }
+            }
+        },
+        "gin-app-start_internal_dto.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "expires_in": {
+                    "description": "访问令牌的有效期，单位秒",
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_expires_at": {
+                    "type": "string",
+                    "example": "2023-01-08T00:00:00Z"
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.CreateOrderRequest": {
+        },
+        "gin-app-start_internal_dto.UnblockRequest": {
             "type": "object",
             "required": [
-                "total_price",
-                "user_id"
+                "kind",
+                "operator",
+                "subject"
+            ],
+            "properties": {
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "account",
+                        "ip",
+                        "rate_limit"
+                    ],
+                    "example": "account"
+                },
+                "operator": {
+                    "description": "执行解锁的管理员，记录在审计日志中",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "alice"
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "verified by phone"
+                },
+                "subject": {
+                    "description": "用户名或 IP",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "john_doe"
+                }
+            }
+        },
+        "gin-app-start_internal_dto.UpdateOrderItemRequest": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 9999,
+                    "minimum": 1,
+                    "example": 3
+                }
+            }
+        },
+        "gin-app-start_internal_dto.UpdateOrderRequest": {
+            "type": "object",
+            "required": [
+                "order_number",
+                "username"
             ],
             "properties": {
                 "description": {
                     "type": "string",
                     "example": "Order for John Doe"
                 },
+                "order_number": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "status": {
+                    "description": "为空时不修改",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "paid",
+                        "shipped",
+                        "completed",
+                        "cancelled"
+                    ],
+                    "example": "paid"
+                },
                 "total_price": {
+                    "description": "订单已有明细时总价按明细计算，不能修改",
                     "type": "number",
                     "example": 99.99
                 },
-                "user_id": {
-                    "type": "integer",
-                    "example": 1
+                "username": {
+                    "type": "string",
+                    "example": "John Doe"
                 }
             }
         },
-        "dto.CreateUserRequest": {
+        "gin-app-start_internal_dto.UpdatePasswordRequest": {
             "type": "object",
             "required": [
-                "password",
+                "new_password",
+                "old_password",
                 "username"
             ],
             "properties": {
-                "email": {
+                "new_password": {
                     "type": "string",
-                    "example": "john@example.com"
+                    "maxLength": 32,
+                    "minLength": 6,
+                    "example": "newpassword123"
                 },
-                "password": {
+                "old_password": {
                     "type": "string",
                     "maxLength": 32,
                     "minLength": 6,
                     "example": "password123"
                 },
-                "phone": {
-                    "type": "string",
-                    "example": "13800138000"
-                },
                 "username": {
                     "type": "string",
                     "maxLength": 32,
                     "minLength": 3,
-                    "example": "john_doe"
+                    "example": "John Doe"
                 }
             }
         },
-        "dto.UpdateOrderRequest": {
+        "gin-app-start_internal_dto.UpdateProductRequest": {
             "type": "object",
             "properties": {
-                "description": {
+                "name": {
                     "type": "string",
-                    "example": "Order for John Doe"
+                    "maxLength": 128,
+                    "example": "Coffee mug"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 29
+                },
+                "stock": {
+                    "description": "重新设置库存",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 80
+                }
+            }
+        },
+        "gin-app-start_internal_dto.UpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "avatar": {
+                    "type": "string",
+                    "example": "https://example.com/avatar.jpg"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "13800138000"
                 },
                 "status": {
                     "type": "integer",
@@ -621,57 +8585,428 @@ const docTemplate = `{
                         1
                     ],
                     "example": 1
+                }
+            }
+        },
+        "gin-app-start_internal_dto.UserRequestStatsResponse": {
+            "type": "object",
+            "properties": {
+                "concurrent": {
+                    "description": "正在处理的请求数(所有实例)",
+                    "type": "integer",
+                    "example": 3
                 },
-                "total_price": {
-                    "type": "number",
-                    "example": 99.99
+                "last_seen": {
+                    "description": "最近一次请求的时间，窗口内没有请求时为空",
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "limit": {
+                    "description": "并发上限，0 表示不限制",
+                    "type": "integer",
+                    "example": 5
+                },
+                "rejected": {
+                    "description": "滑动窗口内因超出并发上限被拒绝的请求数",
+                    "type": "integer",
+                    "example": 4
+                },
+                "requests": {
+                    "description": "滑动窗口内的请求数，包括被拒绝的请求",
+                    "type": "integer",
+                    "example": 120
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
                 }
             }
         },
-        "dto.UpdateUserRequest": {
+        "gin-app-start_internal_dto.UserResponse": {
             "type": "object",
             "properties": {
                 "avatar": {
                     "type": "string",
                     "example": "https://example.com/avatar.jpg"
                 },
+                "created_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
                 "email": {
                     "type": "string",
                     "example": "john@example.com"
                 },
+                "id": {
+                    "description": "启用 id_obfuscation 时为编码后的字符串",
+                    "type": "integer",
+                    "example": 1
+                },
                 "phone": {
                     "type": "string",
                     "example": "13800138000"
                 },
                 "status": {
                     "type": "integer",
-                    "enum": [
-                        0,
-                        1
-                    ],
                     "example": 1
+                },
+                "update_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
                 }
             }
         },
-        "response.Response": {
+        "gin-app-start_internal_dto.ViewCountResponse": {
             "type": "object",
             "properties": {
-                "code": {
+                "subject": {
+                    "type": "string",
+                    "example": "product"
+                },
+                "subject_id": {
+                    "type": "string",
+                    "example": "SKU-10001"
+                },
+                "views": {
                     "type": "integer",
-                    "example": 0
+                    "example": 42
+                }
+            }
+        },
+        "gin-app-start_internal_dto.WarmCacheResponse": {
+            "type": "object",
+            "properties": {
+                "warmed": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "gin-app-start_pkg_response.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "字段的 JSON Pointer 路径，查询参数和路径参数为 /参数名",
+                    "type": "string",
+                    "example": "/items/0/price"
                 },
-                "data": {},
                 "message": {
                     "type": "string",
-                    "example": "success"
+                    "example": "price is a required field"
+                },
+                "rule": {
+                    "description": "未通过的校验规则，JSON 类型不匹配时为 type",
+                    "type": "string",
+                    "example": "required"
+                }
+            }
+        },
+        "gin-app-start_pkg_response.Page": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "游标分页时下一页的游标，没有下一页时为空",
+                    "type": "string",
+                    "example": "NDI"
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "gin-app-start_pkg_response.Paged": {
+            "type": "object",
+            "properties": {
+                "list": {},
+                "page": {
+                    "$ref": "#/definitions/gin-app-start_pkg_response.Page"
+                }
+            }
+        },
+        "gin-app-start_pkg_trace.Debug": {
+            "type": "object",
+            "properties": {
+                "cost_seconds": {
+                    "description": "执行时间(单位秒)",
+                    "type": "number"
+                },
+                "key": {
+                    "description": "标示",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "值"
+                }
+            }
+        },
+        "gin-app-start_pkg_trace.Dialog": {
+            "type": "object",
+            "properties": {
+                "cost_seconds": {
+                    "description": "执行时长(单位秒)",
+                    "type": "number"
+                },
+                "request": {
+                    "description": "请求信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/gin-app-start_pkg_trace.Request"
+                        }
+                    ]
+                },
+                "responses": {
+                    "description": "返回信息",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_pkg_trace.Response"
+                    }
+                },
+                "success": {
+                    "description": "是否成功，true 或 false",
+                    "type": "boolean"
+                }
+            }
+        },
+        "gin-app-start_pkg_trace.Redis": {
+            "type": "object",
+            "properties": {
+                "cost_seconds": {
+                    "description": "执行时间(单位秒)",
+                    "type": "number"
+                },
+                "handle": {
+                    "description": "操作，SET/GET 等",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "时间，格式：2006-01-02 15:04:05",
+                    "type": "string"
+                },
+                "ttl": {
+                    "description": "超时时长(单位分)",
+                    "type": "number"
+                },
+                "value": {
+                    "description": "Value",
+                    "type": "string"
+                },
+                "values": {
+                    "description": "哈希字段值",
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "gin-app-start_pkg_trace.Request": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "请求 Body 信息"
+                },
+                "decoded_url": {
+                    "description": "请求地址",
+                    "type": "string"
+                },
+                "header": {
+                    "description": "请求 Header 信息"
+                },
+                "method": {
+                    "description": "请求方式",
+                    "type": "string"
+                },
+                "ttl": {
+                    "description": "请求超时时间",
+                    "type": "string"
+                }
+            }
+        },
+        "gin-app-start_pkg_trace.Response": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "Body 信息"
+                },
+                "business_code": {
+                    "description": "业务码",
+                    "type": "integer"
+                },
+                "business_code_msg": {
+                    "description": "提示信息",
+                    "type": "string"
+                },
+                "cost_seconds": {
+                    "description": "执行时间(单位秒)",
+                    "type": "number"
+                },
+                "header": {
+                    "description": "Header 信息"
+                },
+                "http_code": {
+                    "description": "HTTP 状态码",
+                    "type": "integer"
+                },
+                "http_code_msg": {
+                    "description": "HTTP 状态码信息",
+                    "type": "string"
+                }
+            }
+        },
+        "gin-app-start_pkg_trace.SQL": {
+            "type": "object",
+            "properties": {
+                "cost_seconds": {
+                    "description": "执行时长(单位秒)",
+                    "type": "number"
+                },
+                "rows_affected": {
+                    "description": "影响行数",
+                    "type": "integer"
+                },
+                "sql": {
+                    "description": "SQL 语句",
+                    "type": "string"
+                },
+                "stack": {
+                    "description": "文件地址和行号",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "description": "时间，格式：2006-01-02 15:04:05",
+                    "type": "string"
+                }
+            }
+        },
+        "gin-app-start_pkg_trace.Summary": {
+            "type": "object",
+            "properties": {
+                "cost_seconds": {
+                    "description": "截至写出响应前的执行时长(单位秒)",
+                    "type": "number"
+                },
+                "debugs": {
+                    "description": "调试信息",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_pkg_trace.Debug"
+                    }
+                },
+                "redis": {
+                    "description": "执行的 Redis 信息",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_pkg_trace.Redis"
+                    }
+                },
+                "sqls": {
+                    "description": "执行的 SQL 信息",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_pkg_trace.SQL"
+                    }
+                },
+                "third_party_requests": {
+                    "description": "调用第三方接口的信息",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gin-app-start_pkg_trace.Dialog"
+                    }
                 },
                 "trace_id": {
+                    "description": "链路ID",
+                    "type": "string"
+                }
+            }
+        },
+        "http.Header": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "array",
+                "items": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_controller.versionResponse": {
+            "type": "object",
+            "properties": {
+                "build_time": {
                     "type": "string",
-                    "example": "trace-id-123"
+                    "example": "2024-01-01 00:00:00"
+                },
+                "env": {
+                    "type": "string",
+                    "example": "local"
+                },
+                "git_commit": {
+                    "type": "string",
+                    "example": "0853152"
+                },
+                "go_version": {
+                    "type": "string",
+                    "example": "go1.24.0"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "v1.0.0"
                 }
             }
         }
-    }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API key for server-to-server calls",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Bearer token, \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
+        "SessionCookie": {
+            "description": "Session cookie issued by POST /api/v1/users/login, e.g. \"mysession=\u003cvalue\u003e\"",
+            "type": "apiKey",
+            "name": "Cookie",
+            "in": "header"
+        }
+    },
+    "tags": [
+        {
+            "description": "Registration, login and profile. Endpoints with a lock require a session; x-roles lists who may call them (\"owner\" means the session user's own data)",
+            "name": "users"
+        },
+        {
+            "description": "Orders of the session user; admin can access every user's orders",
+            "name": "orders"
+        },
+        {
+            "description": "Liveness and readiness probes, no authentication",
+            "name": "health"
+        },
+        {
+            "description": "Operations endpoints, served only on the admin port (admin.port) and never exposed on the public host",
+            "name": "admin"
+        }
+    ]
 }`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
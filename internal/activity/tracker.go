@@ -0,0 +1,176 @@
+package activity
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/metrics"
+	"gin-app-start/internal/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	fieldCreatedAt = "created_at"
+	fieldLastSeen  = "last_seen"
+)
+
+var (
+	// ErrIdleTimeout 超过空闲时长没有访问，或会话记录不存在
+	ErrIdleTimeout = errors.New("session idle timeout")
+	// ErrAbsoluteTimeout 超过会话最长有效期
+	ErrAbsoluteTimeout = errors.New("session absolute timeout")
+)
+
+// Tracker 基于 Redis 记录会话的创建时间和最近访问时间，实现空闲超时和绝对超时
+//
+// 每个会话在登录时分配一个令牌，保存在会话中，Redis 中以 session:activity:{令牌} 记录两个时间戳。
+// 每次访问需要登录的接口时检查两个超时并刷新最近访问时间，键的过期时间取空闲时长和剩余有效期中较小的一个，
+// 因此空闲或到期的会话记录会被 Redis 自动清理。
+type Tracker struct {
+	repo     redis.RedisRepository
+	idle     time.Duration // 为 0 时不限制空闲时长
+	absolute time.Duration // 为 0 时不限制最长有效期
+	now      func() time.Time
+}
+
+// NewTracker 创建会话超时记录，两个超时都未配置时返回 nil
+func NewTracker(repo redis.RedisRepository, cfg config.SessionConfig) *Tracker {
+	if cfg.IdleTimeout <= 0 && cfg.AbsoluteTimeout <= 0 {
+		return nil
+	}
+	return &Tracker{
+		repo:     repo,
+		idle:     time.Duration(cfg.IdleTimeout) * time.Second,
+		absolute: time.Duration(cfg.AbsoluteTimeout) * time.Second,
+		now:      time.Now,
+	}
+}
+
+const keyPrefix = "session:activity:"
+
+func activityKey(token string) string {
+	return keyPrefix + token
+}
+
+// ttl 会话记录的过期时间：空闲时长与剩余有效期中较小的一个
+func (t *Tracker) ttl(createdAt, now time.Time) time.Duration {
+	var ttl time.Duration
+	if t.idle > 0 {
+		ttl = t.idle
+	}
+	if t.absolute > 0 {
+		remaining := createdAt.Add(t.absolute).Sub(now)
+		if ttl == 0 || remaining < ttl {
+			ttl = remaining
+		}
+	}
+	return ttl
+}
+
+// Begin 登录时为会话分配令牌并记录创建时间，返回的令牌需保存到会话中
+func (t *Tracker) Begin(ctx context.Context) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	client := t.repo.GetRedisClient()
+	if client == nil {
+		return "", redis.ErrUnavailable
+	}
+
+	now := t.now()
+	key := t.repo.Key(activityKey(token))
+	_, err := client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, key, fieldCreatedAt, now.Unix(), fieldLastSeen, now.Unix())
+		pipe.Expire(ctx, key, t.ttl(now, now))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	metrics.ObserveSession(metrics.SessionCreated)
+	return token, nil
+}
+
+// Touch 检查会话是否已超时，未超时则刷新最近访问时间，滑动延长空闲过期时间，但不会超过最长有效期
+func (t *Tracker) Touch(ctx context.Context, token string) error {
+	client := t.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+
+	key := t.repo.Key(activityKey(token))
+	values, err := client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		metrics.ObserveSession(metrics.SessionIdleTimeout)
+		return ErrIdleTimeout
+	}
+
+	now := t.now()
+	createdAt := unixField(values, fieldCreatedAt)
+	lastSeen := unixField(values, fieldLastSeen)
+
+	// 键的过期时间已覆盖两种超时，这里再按时间戳校验一次，避免过期删除的延迟
+	if t.absolute > 0 && now.Sub(createdAt) >= t.absolute {
+		client.Del(ctx, key)
+		metrics.ObserveSession(metrics.SessionAbsoluteTimeout)
+		return ErrAbsoluteTimeout
+	}
+	if t.idle > 0 && now.Sub(lastSeen) >= t.idle {
+		client.Del(ctx, key)
+		metrics.ObserveSession(metrics.SessionIdleTimeout)
+		return ErrIdleTimeout
+	}
+
+	_, err = client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, key, fieldLastSeen, now.Unix())
+		pipe.Expire(ctx, key, t.ttl(createdAt, now))
+		return nil
+	})
+	return err
+}
+
+// End 登出时删除会话记录
+func (t *Tracker) End(ctx context.Context, token string) error {
+	client := t.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+	if err := client.Del(ctx, t.repo.Key(activityKey(token))).Err(); err != nil {
+		return err
+	}
+	metrics.ObserveSession(metrics.SessionLoggedOut)
+	return nil
+}
+
+// Count 统计未超时的会话数，用于监控指标
+// 空闲和到期的会话记录由 Redis 自动过期删除，用 SCAN 遍历剩余的记录，不会阻塞 Redis
+func (t *Tracker) Count(ctx context.Context) (int64, error) {
+	client := t.repo.GetRedisClient()
+	if client == nil {
+		return 0, redis.ErrUnavailable
+	}
+
+	var n int64
+	iter := client.Scan(ctx, 0, t.repo.Key(keyPrefix+"*"), 1000).Iterator()
+	for iter.Next(ctx) {
+		n++
+	}
+	return n, iter.Err()
+}
+
+func unixField(values map[string]string, field string) time.Time {
+	sec, _ := strconv.ParseInt(values[field], 10, 64)
+	return time.Unix(sec, 0)
+}
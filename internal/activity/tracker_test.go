@@ -0,0 +1,29 @@
+package activity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTL(t *testing.T) {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		idle     time.Duration
+		absolute time.Duration
+		now      time.Time
+		want     time.Duration
+	}{
+		{"idle only", 30 * time.Minute, 0, created.Add(10 * time.Hour), 30 * time.Minute},
+		{"absolute only", 0, 8 * time.Hour, created.Add(time.Hour), 7 * time.Hour},
+		{"idle within lifetime", 30 * time.Minute, 8 * time.Hour, created.Add(time.Hour), 30 * time.Minute},
+		{"capped by lifetime", 30 * time.Minute, 8 * time.Hour, created.Add(7*time.Hour + 50*time.Minute), 10 * time.Minute},
+	}
+	for _, tc := range cases {
+		tr := &Tracker{idle: tc.idle, absolute: tc.absolute}
+		if got := tr.ttl(created, tc.now); got != tc.want {
+			t.Errorf("%s: ttl = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
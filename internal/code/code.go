@@ -11,12 +11,6 @@ import (
 //go:embed code.go
 var ByteCodeFile []byte
 
-// Failure 错误时返回结构
-type Failure struct {
-	Code    int    `json:"code"`    // 业务码
-	Message string `json:"message"` // 描述信息
-}
-
 const (
 	ServerError        = 10101
 	TooManyRequests    = 10102
@@ -45,6 +39,25 @@ const (
 	FileUploadError    = 10125
 	ParamQueryError    = 10126
 	ParseError         = 10127
+	ServerBusy         = 10128
+	ServiceNotReady    = 10129
+	QuotaExceeded      = 10130
+	RecordingNotExist  = 10131
+	SessionExpired     = 10132
+	ReauthRequired     = 10133
+	LoginLocked        = 10134
+	TokenExpired       = 10135
+	RequestTooLarge    = 10136
+	RefreshInvalid     = 10137
+	APIKeyInvalid      = 10138
+	LogTailDisabled    = 10139
+	ConcurrencyLimited = 10140
+	UrlSignExpired     = 10141
+	FileNotExist       = 10142
+	FileScanning       = 10143
+	FileInfected       = 10144
+	FileTooLarge       = 10145
+	FileTypeError      = 10146
 
 	AuthorizedCreateError    = 20101
 	AuthorizedListError      = 20102
@@ -68,6 +81,7 @@ const (
 	AdminMenuCreateError         = 20211
 	AdminOfflineError            = 20212
 	AdminDetailError             = 20213
+	AdminReauthError             = 20214
 
 	MenuCreateError       = 20301
 	MenuUpdateError       = 20302
@@ -84,17 +98,124 @@ const (
 	CronDetailError  = 20404
 	CronExecuteError = 20405
 
-	OrderCreateError = 20501
-	OrderGetError    = 20502
-	OrderUpdateError = 20503
-	OrderDeleteError = 20504
-	OrderListError   = 20505
+	OrderCreateError   = 20501
+	OrderGetError      = 20502
+	OrderUpdateError   = 20503
+	OrderDeleteError   = 20504
+	OrderListError     = 20505
+	OrderNotFound      = 20506
+	OrderForbidden     = 20507
+	OrderNoteError     = 20508
+	OrderNumberError   = 20509
+	OrderItemError     = 20510
+	OrderItemNotFound  = 20511
+	OrderTotalComputed = 20512
+	OrderUserNotFound  = 20513
+
+	ReferralGetError   = 20601
+	ReferralStatsError = 20602
+	InviteCodeInvalid  = 20603
+
+	TagListError       = 20701
+	TagUserError       = 20702
+	UntagUserError     = 20703
+	TagNotFound        = 20704
+	SegmentQueryError  = 20705
+	SegmentExportError = 20706
+
+	BroadcastCreateError  = 20801
+	BroadcastListError    = 20802
+	BroadcastNotFound     = 20803
+	BroadcastCancelError  = 20804
+	NotificationListError = 20805
+
+	ShipmentCreateError     = 20901
+	ShipmentGetError        = 20902
+	ShipmentNotFound        = 20903
+	ShipmentExists          = 20904
+	ShipmentEventError      = 20905
+	WebhookSignatureInvalid = 20906
+
+	WishlistAddError    = 21001
+	WishlistRemoveError = 21002
+	WishlistListError   = 21003
+	WishlistFull        = 21004
+
+	LeaderboardGetError     = 21101
+	LeaderboardNotFound     = 21102
+	LeaderboardRebuildError = 21103
+
+	StoreCreateError  = 21201
+	StoreDeleteError  = 21202
+	StoreNotFound     = 21203
+	NearbySearchError = 21204
+	GeoRebuildError   = 21205
+
+	PasswordTooShort    = 21301
+	PasswordNeedUpper   = 21302
+	PasswordNeedLower   = 21303
+	PasswordNeedDigit   = 21304
+	PasswordNeedSymbol  = 21305
+	PasswordTooCommon   = 21306
+	PasswordBreached    = 21307
+	PasswordPolicyError = 21308
+
+	BlockedListError  = 21401
+	UnblockError      = 21402
+	BlockNotFound     = 21403
+	RequestStatsError = 21404
+
+	OrgCreateError    = 21501
+	OrgListError      = 21502
+	OrgNotFound       = 21503
+	OrgForbidden      = 21504
+	OrgMemberError    = 21505
+	OrgMemberNotFound = 21506
+	OrgLastOwner      = 21507
+	OrgInviteError    = 21508
+	InvitationInvalid = 21509
+
+	ProjectionRebuildError = 21601
+	ProjectionDisabled     = 21602
+
+	ImpersonationDisabled   = 21701
+	ImpersonationForbidden  = 21702
+	ImpersonationError      = 21703
+	NotImpersonating        = 21704
+	ImpersonationRestricted = 21705
+
+	ViewRecordError = 21801
+	ViewCountError  = 21802
+
+	APIKeyCreateError = 21901
+	APIKeyListError   = 21902
+	APIKeyRevokeError = 21903
+	APIKeyNotFound    = 21904
+	APIKeyLimit       = 21905
+	APIKeyRestricted  = 21906
+
+	PageAdminOnly = 22001
+
+	ProductCreateError = 22101
+	ProductUpdateError = 22102
+	ProductDeleteError = 22103
+	ProductListError   = 22104
+	ProductNotFound    = 22105
+	ProductExists      = 22106
+	ProductOutOfStock  = 22107
+
+	SettingsGetError    = 22201
+	SettingsUpdateError = 22202
 )
 
 func Text(code int) string {
-	cfg, err := config.Load()
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+	// 使用启动时加载的配置，命令行参数覆盖的配置项只在其中生效
+	cfg := config.GetConfig()
+	if cfg == nil {
+		var err error
+		if cfg, err = config.Load(); err != nil {
+			log.Fatalf("Failed to load config: %v", err)
+		}
 	}
 
 	lang := cfg.Language.Local
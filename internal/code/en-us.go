@@ -28,6 +28,25 @@ var enUSText = map[int]string{
 	FileUploadError:    "File upload failed",
 	ParamQueryError:    "Parameter query error",
 	ParseError:         "Parameter parsing error",
+	ServerBusy:         "Server is busy, please retry later",
+	ServiceNotReady:    "Service is not ready",
+	QuotaExceeded:      "Request quota exceeded",
+	RecordingNotExist:  "Recording does not exist or has expired",
+	SessionExpired:     "Session expired, please log in again",
+	ReauthRequired:     "Please re-authenticate to perform this operation",
+	LoginLocked:        "Too many failed login attempts, please try again later",
+	TokenExpired:       "Access token expired, please refresh or log in again",
+	RequestTooLarge:    "Request body too large",
+	RefreshInvalid:     "Refresh token is invalid or revoked, please log in again",
+	APIKeyInvalid:      "API key is invalid or revoked",
+	LogTailDisabled:    "Log tail is not enabled",
+	ConcurrencyLimited: "Too many requests in progress for this user, please retry later",
+	UrlSignExpired:     "The link has expired, please request a new one",
+	FileNotExist:       "File does not exist",
+	FileScanning:       "File is being scanned, please retry later",
+	FileInfected:       "File was rejected by the virus scan",
+	FileTooLarge:       "File exceeds the size limit",
+	FileTypeError:      "Only JPEG, PNG, GIF and WebP images are allowed",
 
 	AuthorizedCreateError:    "Failed to create caller",
 	AuthorizedListError:      "Failed to get caller list",
@@ -51,6 +70,7 @@ var enUSText = map[int]string{
 	AdminMenuCreateError:         "Administrator menu authorization failed",
 	AdminOfflineError:            "Offline administrator failed",
 	AdminDetailError:             "Failed to get personal information",
+	AdminReauthError:             "Failed to verify identity",
 
 	MenuCreateError:       "Failed to create menu",
 	MenuUpdateError:       "Failed to update menu",
@@ -62,14 +82,117 @@ var enUSText = map[int]string{
 	MenuDeleteActionError: "Failed to delete menu action",
 
 	CronCreateError:  "Failed to create cron",
-	CronUpdateError:  "Failed to update menu",
+	CronUpdateError:  "Failed to update cron",
 	CronListError:    "Failed to get cron list",
 	CronDetailError:  "Failed to get cron detail",
 	CronExecuteError: "Failed to execute cron",
 
-	OrderCreateError: "Failed to create order",
-	OrderGetError:    "Failed to get order",
-	OrderUpdateError: "Failed to update order",
-	OrderDeleteError: "Failed to delete order",
-	OrderListError:   "Failed to get order list",
+	OrderCreateError:   "Failed to create order",
+	OrderGetError:      "Failed to get order",
+	OrderUpdateError:   "Failed to update order",
+	OrderDeleteError:   "Failed to delete order",
+	OrderListError:     "Failed to get order list",
+	OrderNotFound:      "Order not found",
+	OrderForbidden:     "No permission to access this order",
+	OrderNoteError:     "Failed to add order note",
+	OrderNumberError:   "Failed to generate a unique order number, please retry",
+	OrderItemError:     "Failed to update order items",
+	OrderItemNotFound:  "Order item not found",
+	OrderTotalComputed: "The order total is computed from its items and cannot be set directly",
+	OrderUserNotFound:  "The user to create the order for does not exist",
+
+	ReferralGetError:   "Failed to get referral information",
+	ReferralStatsError: "Failed to get referral statistics",
+	InviteCodeInvalid:  "Invalid invitation code",
+
+	TagListError:       "Failed to get tag list",
+	TagUserError:       "Failed to tag user",
+	UntagUserError:     "Failed to untag user",
+	TagNotFound:        "Tag not found",
+	SegmentQueryError:  "Failed to query segment users",
+	SegmentExportError: "Failed to export segment users",
+
+	BroadcastCreateError:  "Failed to create broadcast",
+	BroadcastListError:    "Failed to get broadcast list",
+	BroadcastNotFound:     "Broadcast not found",
+	BroadcastCancelError:  "Failed to cancel broadcast",
+	NotificationListError: "Failed to get notifications",
+
+	ShipmentCreateError:     "Failed to register shipment",
+	ShipmentGetError:        "Failed to get shipment tracking",
+	ShipmentNotFound:        "Shipment not found",
+	ShipmentExists:          "Tracking number already registered for this carrier",
+	ShipmentEventError:      "Failed to record shipment events",
+	WebhookSignatureInvalid: "Invalid webhook signature",
+
+	WishlistAddError:    "Failed to add to wishlist",
+	WishlistRemoveError: "Failed to remove from wishlist",
+	WishlistListError:   "Failed to get wishlist",
+	WishlistFull:        "Wishlist is full",
+
+	LeaderboardGetError:     "Failed to get leaderboard",
+	LeaderboardNotFound:     "Leaderboard not found",
+	LeaderboardRebuildError: "Failed to rebuild leaderboard",
+
+	StoreCreateError:  "Failed to create store",
+	StoreDeleteError:  "Failed to delete store",
+	StoreNotFound:     "Store not found",
+	NearbySearchError: "Failed to search nearby",
+	GeoRebuildError:   "Failed to rebuild geo index",
+
+	PasswordTooShort:    "Password must be at least %d characters",
+	PasswordNeedUpper:   "Password must contain an uppercase letter",
+	PasswordNeedLower:   "Password must contain a lowercase letter",
+	PasswordNeedDigit:   "Password must contain a digit",
+	PasswordNeedSymbol:  "Password must contain a special character",
+	PasswordTooCommon:   "Password is too common or contains the username",
+	PasswordBreached:    "Password has appeared in a known data breach, please choose another",
+	PasswordPolicyError: "Password does not meet the security policy",
+
+	BlockedListError:  "Failed to list blocked accounts and IPs",
+	UnblockError:      "Failed to unblock",
+	BlockNotFound:     "Block does not exist or has expired",
+	RequestStatsError: "Failed to get request statistics",
+
+	OrgCreateError:    "Failed to create organization",
+	OrgListError:      "Failed to list organizations",
+	OrgNotFound:       "Organization does not exist",
+	OrgForbidden:      "Not allowed in this organization",
+	OrgMemberError:    "Failed to update organization members",
+	OrgMemberNotFound: "Organization member does not exist",
+	OrgLastOwner:      "Organization must keep at least one owner",
+	OrgInviteError:    "Failed to invite organization member",
+	InvitationInvalid: "Invitation is invalid, expired or already accepted",
+
+	ProjectionRebuildError: "Failed to rebuild order list projection",
+	ProjectionDisabled:     "Order list projection is not enabled",
+
+	ImpersonationDisabled:   "Impersonation is not enabled",
+	ImpersonationForbidden:  "Only admins can impersonate, and admins cannot be impersonated",
+	ImpersonationError:      "Failed to impersonate user",
+	NotImpersonating:        "Not impersonating any user",
+	ImpersonationRestricted: "Not allowed while impersonating a user",
+
+	ViewRecordError: "Failed to record view",
+	ViewCountError:  "Failed to get view count",
+
+	APIKeyCreateError: "Failed to create API key",
+	APIKeyListError:   "Failed to get API key list",
+	APIKeyRevokeError: "Failed to revoke API key",
+	APIKeyNotFound:    "API key not found",
+	APIKeyLimit:       "Too many API keys, please revoke unused keys first",
+	APIKeyRestricted:  "API keys cannot be managed with an API key, please log in",
+
+	PageAdminOnly: "Only administrators can sign in to the admin pages",
+
+	ProductCreateError: "Failed to create product",
+	ProductUpdateError: "Failed to update product",
+	ProductDeleteError: "Failed to delete product",
+	ProductListError:   "Failed to get product list",
+	ProductNotFound:    "Product not found",
+	ProductExists:      "Product ID already exists",
+	ProductOutOfStock:  "Insufficient stock for the product",
+
+	SettingsGetError:    "Failed to get settings",
+	SettingsUpdateError: "Failed to update settings",
 }
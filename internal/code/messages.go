@@ -0,0 +1,53 @@
+package code
+
+import (
+	"strconv"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/pkg/i18n"
+)
+
+// zhCNMessages 接口返回的英文提示信息(如 c.Payload("Deleted successfully"))的中文翻译，
+// 键为代码中的英文原文，新增提示信息时在此补充翻译，未补充的按英文返回
+var zhCNMessages = map[string]string{
+	"Deleted successfully":              "删除成功",
+	"Change password success":           "修改密码成功",
+	"Logout successfully":               "退出登录成功",
+	"Remove member successfully":        "移除成员成功",
+	"Add to wishlist successfully":      "收藏成功",
+	"Remove from wishlist successfully": "取消收藏成功",
+	"Untag user successfully":           "移除用户标签成功",
+	"Cancel broadcast successfully":     "取消群发成功",
+	"Delete store successfully":         "删除门店成功",
+	"Unblock successfully":              "解除锁定成功",
+	"Revoked successfully":              "吊销成功",
+	"View recorded":                     "已记录浏览",
+}
+
+// NewBundle 错误码文本和接口提示信息的多语言消息，fallback 为请求未指定或不支持所指定的语言时使用的语言
+//
+// 错误码文本的消息ID为 code.<错误码>，提示信息的消息ID为英文原文；
+// 按配置语言取出的 Text(code) 和英文原文都可以按请求协商的语言翻译。
+func NewBundle(fallback string) *i18n.Bundle {
+	enUS := make(map[string]string, len(enUSText)+len(zhCNMessages))
+	zhCN := make(map[string]string, len(zhCNText)+len(zhCNMessages))
+	for c, text := range enUSText {
+		enUS[messageID(c)] = text
+	}
+	for c, text := range zhCNText {
+		zhCN[messageID(c)] = text
+	}
+	for en, zh := range zhCNMessages {
+		enUS[en] = en
+		zhCN[en] = zh
+	}
+
+	b := i18n.NewBundle(fallback)
+	b.Add(common.ZhCN, zhCN)
+	b.Add(common.EnUS, enUS)
+	return b
+}
+
+func messageID(code int) string {
+	return "code." + strconv.Itoa(code)
+}
@@ -28,6 +28,25 @@ var zhCNText = map[int]string{
 	FileUploadError:    "文件上传失败",
 	ParamQueryError:    "参数查询错误",
 	ParseError:         "参数解析错误",
+	ServerBusy:         "服务繁忙，请稍后重试",
+	ServiceNotReady:    "服务未就绪",
+	QuotaExceeded:      "请求次数已超出配额",
+	RecordingNotExist:  "请求录制不存在或已过期",
+	SessionExpired:     "会话已过期，请重新登录",
+	ReauthRequired:     "该操作需要重新验证身份",
+	LoginLocked:        "登录失败次数过多，请稍后再试",
+	TokenExpired:       "访问令牌已过期，请刷新令牌或重新登录",
+	RequestTooLarge:    "请求体过大",
+	RefreshInvalid:     "刷新令牌无效或已被吊销，请重新登录",
+	APIKeyInvalid:      "API Key 无效或已被吊销",
+	LogTailDisabled:    "未开启实时日志",
+	ConcurrencyLimited: "当前用户正在处理的请求过多，请稍后重试",
+	UrlSignExpired:     "链接已过期，请重新获取",
+	FileNotExist:       "文件不存在",
+	FileScanning:       "文件正在进行安全扫描，请稍后重试",
+	FileInfected:       "文件未通过病毒扫描",
+	FileTooLarge:       "文件超过大小限制",
+	FileTypeError:      "仅支持 JPEG、PNG、GIF、WebP 格式的图片",
 
 	AuthorizedCreateError:    "创建调用方失败",
 	AuthorizedListError:      "获取调用方列表失败",
@@ -51,6 +70,7 @@ var zhCNText = map[int]string{
 	AdminMenuCreateError:         "管理员菜单授权失败",
 	AdminOfflineError:            "下线管理员失败",
 	AdminDetailError:             "获取个人信息失败",
+	AdminReauthError:             "身份验证失败",
 
 	MenuCreateError:       "创建菜单失败",
 	MenuUpdateError:       "更新菜单失败",
@@ -67,9 +87,112 @@ var zhCNText = map[int]string{
 	CronDetailError:  "获取定时任务详情失败",
 	CronExecuteError: "手动执行定时任务失败",
 
-	OrderCreateError: "创建订单失败",
-	OrderGetError:    "获取订单失败",
-	OrderUpdateError: "更新订单失败",
-	OrderDeleteError: "删除订单失败",
-	OrderListError:   "获取订单列表失败",
+	OrderCreateError:   "创建订单失败",
+	OrderGetError:      "获取订单失败",
+	OrderUpdateError:   "更新订单失败",
+	OrderDeleteError:   "删除订单失败",
+	OrderListError:     "获取订单列表失败",
+	OrderNotFound:      "订单不存在",
+	OrderForbidden:     "无权操作该订单",
+	OrderNoteError:     "添加订单备注失败",
+	OrderNumberError:   "订单号生成冲突，请重试",
+	OrderItemError:     "修改订单明细失败",
+	OrderItemNotFound:  "订单明细不存在",
+	OrderTotalComputed: "订单总价由订单明细计算，不能直接修改",
+	OrderUserNotFound:  "下单用户不存在",
+
+	ReferralGetError:   "获取邀请信息失败",
+	ReferralStatsError: "获取邀请统计失败",
+	InviteCodeInvalid:  "邀请码无效",
+
+	TagListError:       "获取标签列表失败",
+	TagUserError:       "添加用户标签失败",
+	UntagUserError:     "移除用户标签失败",
+	TagNotFound:        "标签不存在",
+	SegmentQueryError:  "查询分群用户失败",
+	SegmentExportError: "导出分群用户失败",
+
+	BroadcastCreateError:  "创建群发失败",
+	BroadcastListError:    "获取群发列表失败",
+	BroadcastNotFound:     "群发不存在",
+	BroadcastCancelError:  "取消群发失败",
+	NotificationListError: "获取站内信失败",
+
+	ShipmentCreateError:     "登记包裹失败",
+	ShipmentGetError:        "获取物流信息失败",
+	ShipmentNotFound:        "包裹不存在",
+	ShipmentExists:          "该承运商下的运单号已登记",
+	ShipmentEventError:      "记录物流事件失败",
+	WebhookSignatureInvalid: "回调签名无效",
+
+	WishlistAddError:    "收藏商品失败",
+	WishlistRemoveError: "取消收藏失败",
+	WishlistListError:   "获取收藏列表失败",
+	WishlistFull:        "收藏数量已达上限",
+
+	LeaderboardGetError:     "获取排行榜失败",
+	LeaderboardNotFound:     "排行榜不存在",
+	LeaderboardRebuildError: "重建排行榜失败",
+
+	StoreCreateError:  "创建门店失败",
+	StoreDeleteError:  "删除门店失败",
+	StoreNotFound:     "门店不存在",
+	NearbySearchError: "附近查询失败",
+	GeoRebuildError:   "重建地理位置索引失败",
+
+	PasswordTooShort:    "密码长度不能少于%d位",
+	PasswordNeedUpper:   "密码必须包含大写字母",
+	PasswordNeedLower:   "密码必须包含小写字母",
+	PasswordNeedDigit:   "密码必须包含数字",
+	PasswordNeedSymbol:  "密码必须包含特殊字符",
+	PasswordTooCommon:   "密码过于常见或包含用户名",
+	PasswordBreached:    "密码已出现在公开泄露的数据中，请更换",
+	PasswordPolicyError: "密码不符合安全策略",
+
+	BlockedListError:  "获取锁定列表失败",
+	UnblockError:      "解除锁定失败",
+	BlockNotFound:     "锁定记录不存在或已过期",
+	RequestStatsError: "获取请求统计失败",
+
+	OrgCreateError:    "创建组织失败",
+	OrgListError:      "获取组织列表失败",
+	OrgNotFound:       "组织不存在",
+	OrgForbidden:      "无权操作该组织",
+	OrgMemberError:    "操作组织成员失败",
+	OrgMemberNotFound: "组织成员不存在",
+	OrgLastOwner:      "组织至少需要保留一名所有者",
+	OrgInviteError:    "发送组织邀请失败",
+	InvitationInvalid: "邀请无效、已过期或已被接受",
+
+	ProjectionRebuildError: "重建订单列表读模型失败",
+	ProjectionDisabled:     "订单列表读模型未启用",
+
+	ImpersonationDisabled:   "代入用户功能未启用",
+	ImpersonationForbidden:  "只有管理员可以代入其他用户，且不能代入管理员",
+	ImpersonationError:      "代入用户失败",
+	NotImpersonating:        "当前未代入任何用户",
+	ImpersonationRestricted: "代入用户期间不能执行该操作",
+
+	ViewRecordError: "记录浏览失败",
+	ViewCountError:  "获取浏览数失败",
+
+	APIKeyCreateError: "创建 API Key 失败",
+	APIKeyListError:   "获取 API Key 列表失败",
+	APIKeyRevokeError: "吊销 API Key 失败",
+	APIKeyNotFound:    "API Key 不存在",
+	APIKeyLimit:       "API Key 数量已达上限，请先吊销不再使用的 API Key",
+	APIKeyRestricted:  "不能使用 API Key 管理 API Key，请登录后操作",
+
+	PageAdminOnly: "只有管理员可以登录管理页面",
+
+	ProductCreateError: "创建商品失败",
+	ProductUpdateError: "更新商品失败",
+	ProductDeleteError: "删除商品失败",
+	ProductListError:   "获取商品列表失败",
+	ProductNotFound:    "商品不存在",
+	ProductExists:      "商品ID已存在",
+	ProductOutOfStock:  "商品库存不足",
+
+	SettingsGetError:    "获取设置失败",
+	SettingsUpdateError: "更新设置失败",
 }
@@ -15,4 +15,25 @@ const (
 
 	SESSION_KEY = "session_key"
 	ADMIN_NAME  = "admin"
+
+	// SESSION_ACTIVITY_KEY 会话中保存超时记录令牌的键，见 activity.Tracker
+	SESSION_ACTIVITY_KEY = "session_activity"
+
+	// SESSION_REAUTH_KEY 会话中记录最近一次验证身份的时间(Unix 秒)，登录和 /users/reauth 时更新
+	SESSION_REAUTH_KEY = "session_reauth_at"
+
+	// JWT_AUTH_TIME_KEY jwt 认证的请求中保存令牌 auth_time(Unix 秒)的上下文键，RecentAuth 以此代替会话中的验证时间
+	JWT_AUTH_TIME_KEY = "_jwt_auth_time_"
+
+	// JWT_FAMILY_KEY jwt 认证的请求中保存令牌族ID的上下文键，登出时据此吊销刷新令牌
+	JWT_FAMILY_KEY = "_jwt_family_"
+
+	// API_KEY_AUTH_KEY 通过 API Key 认证的请求中设置的上下文键，见 middleware.APIKeyAuth
+	API_KEY_AUTH_KEY = "_api_key_auth_"
+
+	// NO_RESPONSE_CACHE_KEY 路由组配置了 no_cache 时设置的上下文键，ResponseCache 跳过缓存
+	NO_RESPONSE_CACHE_KEY = "_no_response_cache_"
+
+	// SESSION_IMPERSONATION_KEY 管理员代入其他用户时保存代入记录的键，见 impersonation.Session
+	SESSION_IMPERSONATION_KEY = "session_impersonation"
 )
@@ -29,6 +29,7 @@ const (
 	_PayloadName      = "_payload_"
 	_GraphPayloadName = "_graph_payload_"
 	_SessionUserInfo  = "_session_user_info"
+	_BannerName       = "_banner_"
 	_AbortErrorName   = "_abort_error_"
 	_IsRecordMetrics  = "_is_record_metrics_"
 )
@@ -55,6 +56,14 @@ func NewContext(ctx *gin.Context) Context {
 	return context
 }
 
+// NewBackgroundContext 后台任务使用的 Context，不关联 HTTP 请求，只携带 logger，
+// 用于在定时任务中复用以 Context 为参数的 repository 和 service
+func NewBackgroundContext(logger *zap.Logger) Context {
+	c := &context{ctx: &gin.Context{Request: &http.Request{Header: http.Header{}, URL: &url.URL{}}}}
+	c.SetLogger(logger)
+	return c
+}
+
 func ReleaseContext(ctx Context) {
 	c := ctx.(*context)
 	c.ctx = nil
@@ -116,6 +125,8 @@ type Context interface {
 	// Logger 获取 Logger 对象
 	Logger() *zap.Logger
 	SetLogger(logger *zap.Logger)
+	// AddLoggerFields 为当前请求的 Logger 追加字段(如用户 ID)，之后通过 Logger() 输出的日志都会带上
+	AddLoggerFields(fields ...zap.Field)
 
 	// Payload 正确返回
 	Payload(payload interface{})
@@ -139,6 +150,10 @@ type Context interface {
 	SessionUserInfo() interface{}
 	SetSessionUserInfo(value interface{})
 
+	// Banner 响应中附带的提示，如管理员正在代入该用户
+	Banner() string
+	SetBanner(banner string)
+
 	// Request 获取 Request 对象
 	Request() *http.Request
 	// RawData 获取 Request.Body
@@ -268,6 +283,12 @@ func (c *context) SetLogger(logger *zap.Logger) {
 	c.ctx.Set(_LoggerName, logger)
 }
 
+func (c *context) AddLoggerFields(fields ...zap.Field) {
+	if logger := c.Logger(); logger != nil {
+		c.SetLogger(logger.With(fields...))
+	}
+}
+
 func (c *context) GetPayload() interface{} {
 	if payload, ok := c.ctx.Get(_PayloadName); ok != false {
 		return payload
@@ -305,8 +326,10 @@ func (c *context) AbortWithError(err BusinessError) {
 }
 
 func (c *context) AbortError() BusinessError {
+	// 未调用 AbortWithError 时返回 nil
 	err, _ := c.ctx.Get(_AbortErrorName)
-	return err.(BusinessError)
+	businessErr, _ := err.(BusinessError)
+	return businessErr
 }
 
 func (c *context) GetSession() sessions.Session {
@@ -334,6 +357,14 @@ func (c *context) SetSessionUserInfo(value interface{}) {
 	c.ctx.Set(_SessionUserInfo, value)
 }
 
+func (c *context) Banner() string {
+	return c.ctx.GetString(_BannerName)
+}
+
+func (c *context) SetBanner(banner string) {
+	c.ctx.Set(_BannerName, banner)
+}
+
 // Request 获取 Request
 func (c *context) Request() *http.Request {
 	return c.ctx.Request
@@ -369,11 +400,17 @@ func (c *context) URI() string {
 	return uri
 }
 
-// RequestContext (包装 Trace + Logger) 获取请求的 context (当client关闭后，会自动canceled)
+// RequestContext (包装 Trace + Logger) 获取请求的 context
+//
+// 保留请求 context 中的值(如 OpenTelemetry 的 span，数据库、Redis 操作据此记录为请求的子 span)，
+// 但不随客户端断开而取消，避免请求中途断开时写了一半的数据
 func (c *context) RequestContext() StdContext {
+	parent := stdctx.Background()
+	if c.ctx.Request != nil {
+		parent = stdctx.WithoutCancel(c.ctx.Request.Context())
+	}
 	return StdContext{
-		//c.ctx.Request.Context(),
-		stdctx.Background(),
+		parent,
 		c.Trace(),
 		c.Logger(),
 	}
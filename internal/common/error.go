@@ -5,15 +5,29 @@ import "gin-app-start/pkg/errors"
 var _ BusinessError = (*businessError)(nil)
 
 type BusinessError interface {
+	error
+
 	// i 为了避免被其他包实现
 	i()
 
 	// WithError 设置错误信息
 	WithError(err error) BusinessError
 
+	// WithStack 在未设置底层错误时，记录当前调用栈
+	WithStack() BusinessError
+
+	// WithField 附加错误上下文信息，会随错误日志一起输出
+	WithField(key string, value interface{}) BusinessError
+
 	// WithAlert 设置告警通知
 	WithAlert() BusinessError
 
+	// WithDetails 设置参数校验失败的字段，随错误响应返回
+	WithDetails(details []FieldError) BusinessError
+
+	// WithData 设置随错误响应的 data 返回的数据，用于失败时需要返回结构化上下文的接口(如就绪探针的依赖状态)
+	WithData(data interface{}) BusinessError
+
 	// BusinessCode 获取业务码
 	BusinessCode() int
 
@@ -26,16 +40,31 @@ type BusinessError interface {
 	// StackError 获取带堆栈的错误信息
 	StackError() error
 
+	// Unwrap 获取底层错误，支持 errors.Is/As
+	Unwrap() error
+
+	// Fields 获取附加的上下文信息
+	Fields() map[string]interface{}
+
 	// IsAlert 是否开启告警通知
 	IsAlert() bool
+
+	// Details 获取参数校验失败的字段
+	Details() []FieldError
+
+	// Data 获取随错误响应返回的数据
+	Data() interface{}
 }
 
 type businessError struct {
-	httpCode     int    // HTTP 状态码
-	businessCode int    // 业务码
-	message      string // 错误描述
-	stackError   error  // 含有堆栈信息的错误
-	isAlert      bool   // 是否告警通知
+	httpCode     int                    // HTTP 状态码
+	businessCode int                    // 业务码
+	message      string                 // 错误描述
+	stackError   error                  // 含有堆栈信息的错误
+	fields       map[string]interface{} // 附加的上下文信息
+	isAlert      bool                   // 是否告警通知
+	details      []FieldError           // 参数校验失败的字段
+	data         interface{}            // 随错误响应返回的数据
 }
 
 func Error(httpCode, businessCode int, message string) BusinessError {
@@ -49,16 +78,48 @@ func Error(httpCode, businessCode int, message string) BusinessError {
 
 func (e *businessError) i() {}
 
+func (e *businessError) Error() string {
+	if e.stackError != nil {
+		return e.message + ": " + e.stackError.Error()
+	}
+	return e.message
+}
+
 func (e *businessError) WithError(err error) BusinessError {
 	e.stackError = errors.WithStack(err)
 	return e
 }
 
+func (e *businessError) WithStack() BusinessError {
+	if e.stackError == nil {
+		e.stackError = errors.New(e.message)
+	}
+	return e
+}
+
+func (e *businessError) WithField(key string, value interface{}) BusinessError {
+	if e.fields == nil {
+		e.fields = make(map[string]interface{})
+	}
+	e.fields[key] = value
+	return e
+}
+
 func (e *businessError) WithAlert() BusinessError {
 	e.isAlert = true
 	return e
 }
 
+func (e *businessError) WithDetails(details []FieldError) BusinessError {
+	e.details = details
+	return e
+}
+
+func (e *businessError) WithData(data interface{}) BusinessError {
+	e.data = data
+	return e
+}
+
 func (e *businessError) HTTPCode() int {
 	return e.httpCode
 }
@@ -75,6 +136,22 @@ func (e *businessError) StackError() error {
 	return e.stackError
 }
 
+func (e *businessError) Unwrap() error {
+	return e.stackError
+}
+
+func (e *businessError) Fields() map[string]interface{} {
+	return e.fields
+}
+
 func (e *businessError) IsAlert() bool {
 	return e.isAlert
 }
+
+func (e *businessError) Details() []FieldError {
+	return e.details
+}
+
+func (e *businessError) Data() interface{} {
+	return e.data
+}
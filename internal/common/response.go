@@ -0,0 +1,10 @@
+package common
+
+import "gin-app-start/pkg/response"
+
+// Response Payload 和 AbortWithError 最终输出的响应结构，由 Logger 中间件通过 pkg/response 统一格式化
+// swagger 注释中使用 common.Response{data=dto.Xxx} 描述接口返回
+type Response = response.Response
+
+// FieldError 参数校验失败的字段，随错误响应的 details 返回
+type FieldError = response.FieldError
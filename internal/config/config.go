@@ -1,20 +1,420 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gin-app-start/configs"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Language LanguageConfig `mapstructure:"language"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	Log      LogConfig      `mapstructure:"log"`
-	File     FileConfig     `mapstructure:"file"`
-	Session  SessionConfig  `mapstructure:"session"`
+	Env        string          `mapstructure:"-"` // 配置环境，取自 SERVER_ENV
+	ConfigFile string          `mapstructure:"-"` // 使用的配置文件，为空表示只使用了默认配置
+	Server     ServerConfig    `mapstructure:"server"`
+	Admin      AdminConfig     `mapstructure:"admin"`
+	Language   LanguageConfig  `mapstructure:"language"`
+	Database   DatabaseConfig  `mapstructure:"database"`
+	Redis      RedisConfig     `mapstructure:"redis"`
+	Log        LogConfig       `mapstructure:"log"`
+	File       FileConfig      `mapstructure:"file"`
+	Session    SessionConfig   `mapstructure:"session"`
+	Auth       AuthConfig      `mapstructure:"auth"`
+	IDs        IDConfig        `mapstructure:"id_obfuscation"`
+	Modules    map[string]bool `mapstructure:"modules"` // 模块名 -> 是否启用，未配置的模块默认启用，见 router.Module
+	Metrics    MetricsConfig   `mapstructure:"metrics"`
+
+	Concurrency   ConcurrencyConfig           `mapstructure:"concurrency"`
+	RouteGroups   map[string]RouteGroupConfig `mapstructure:"route_groups"` // 路由组完整路径 -> 中间件配置，如 /api/v1/orders
+	Cache         CacheConfig                 `mapstructure:"cache"`
+	Health        HealthConfig                `mapstructure:"health"`
+	Quota         QuotaConfig                 `mapstructure:"quota"`
+	Tape          TapeConfig                  `mapstructure:"tape"`
+	ResponseCache ResponseCacheConfig         `mapstructure:"response_cache"`
+	OrderNumber   OrderNumberConfig           `mapstructure:"order_number"`
+	Broadcast     BroadcastConfig             `mapstructure:"broadcast"`
+	Mail          MailConfig                  `mapstructure:"mail"`
+	Shipment      ShipmentConfig              `mapstructure:"shipment"`
+	Wishlist      WishlistConfig              `mapstructure:"wishlist"`
+	Views         ViewsConfig                 `mapstructure:"views"`
+	Password      PasswordConfig              `mapstructure:"password"`
+	Lockout       LockoutConfig               `mapstructure:"lockout"`
+	Secrets       SecretsConfig               `mapstructure:"secrets"`
+	Encryption    EncryptionConfig            `mapstructure:"encryption"`
+	Projection    ProjectionConfig            `mapstructure:"projection"`
+	Archive       ArchiveConfig               `mapstructure:"archive"`
+
+	Impersonation ImpersonationConfig `mapstructure:"impersonation"`
+	Pages         PagesConfig         `mapstructure:"pages"`
+	SPA           SPAConfig           `mapstructure:"spa"`
+	Tracing       TracingConfig       `mapstructure:"tracing"`
+}
+
+// TracingConfig OpenTelemetry 分布式追踪，链路通过 OTLP 导出到 Jaeger、Tempo 等后端
+//
+// 请求头中的 W3C traceparent 会被延续，数据库和 Redis 操作作为请求的子 span 记录。
+type TracingConfig struct {
+	Enabled     bool              `mapstructure:"enabled"`
+	ServiceName string            `mapstructure:"service_name"`          // 为空时使用 gin-app-start
+	Protocol    string            `mapstructure:"protocol"`              // grpc(默认，端口 4317) 或 http(端口 4318)
+	Endpoint    string            `mapstructure:"endpoint"`              // OTLP 接收地址，如 localhost:4317，不带协议前缀
+	Insecure    bool              `mapstructure:"insecure"`              // 不使用 TLS，本地 Jaeger、Tempo 通常需要开启
+	SampleRatio float64           `mapstructure:"sample_ratio"`          // 新链路的采样比例 0~1，上游已决定是否采样时沿用上游的结果
+	Headers     map[string]string `mapstructure:"headers" redact:"true"` // 导出请求附带的请求头，如托管服务的鉴权令牌
+}
+
+// SPAConfig 托管前端单页应用的构建目录，与接口由同一个进程提供
+//
+// 未匹配接口路由的 GET、HEAD 请求返回目录中的文件；文件不存在且路径没有扩展名时返回 index，
+// 由前端的 history 路由处理。
+type SPAConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Dir     string   `mapstructure:"dir"`     // 前端构建目录，如 web/dist
+	Prefix  string   `mapstructure:"prefix"`  // 挂载路径，默认为 /
+	Index   string   `mapstructure:"index"`   // 默认为 index.html
+	MaxAge  int      `mapstructure:"max_age"` // index 以外文件的浏览器缓存时间，单位秒；index 总是 no-cache
+	Exclude []string `mapstructure:"exclude"` // 不回退到 index 的路径前缀，如 /api/，未匹配的接口仍返回 JSON 404
+}
+
+// PagesConfig 服务端渲染的管理页面(/ui)，只有管理员可以登录
+type PagesConfig struct {
+	Enabled  bool `mapstructure:"enabled"`
+	PageSize int  `mapstructure:"page_size"` // 用户、订单列表每页条数，最大 100
+}
+
+// defaultPagesPageSize 管理页面列表每页条数的默认值
+const defaultPagesPageSize = 20
+
+// Size 管理页面列表每页条数
+func (c PagesConfig) Size() int {
+	if c.PageSize <= 0 {
+		return defaultPagesPageSize
+	}
+	if c.PageSize > 100 {
+		return 100
+	}
+	return c.PageSize
+}
+
+// ImpersonationConfig 管理员代入用户身份排查问题，代入期间的每个请求都写入审计日志
+type ImpersonationConfig struct {
+	Enabled     bool `mapstructure:"enabled"`
+	MaxDuration int  `mapstructure:"max_duration"` // 单次代入的最长时间，单位分钟，到期后自动恢复为管理员本人
+}
+
+// defaultImpersonationMaxDuration 单次代入最长时间的默认值，单位分钟
+const defaultImpersonationMaxDuration = 30
+
+// Window 单次代入的最长时间
+func (c ImpersonationConfig) Window() time.Duration {
+	if c.MaxDuration <= 0 {
+		return defaultImpersonationMaxDuration * time.Minute
+	}
+	return time.Duration(c.MaxDuration) * time.Minute
+}
+
+// ArchiveConfig 订单归档，创建时间超过 after_months 个月的订单由后台任务移入 orders_archive
+type ArchiveConfig struct {
+	Enabled     bool `mapstructure:"enabled"`      // 是否在本实例上执行归档任务，多实例同时开启时按行加锁互不重复
+	AfterMonths int  `mapstructure:"after_months"` // 订单创建多少个月后归档
+	Interval    int  `mapstructure:"interval"`     // 两次归档的间隔，单位秒
+	BatchSize   int  `mapstructure:"batch_size"`   // 每个事务移动的订单数
+}
+
+// ProjectionConfig 订单列表读模型(order_summaries)，订单写入后由后台任务异步更新
+type ProjectionConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	FlushInterval int  `mapstructure:"flush_interval"` // 合并订单变更后写入读模型的间隔，单位毫秒
+	BatchSize     int  `mapstructure:"batch_size"`     // 重建读模型时每批处理的订单数
+}
+
+// SecretsConfig 密钥来源，密钥本身不写入配置文件
+type SecretsConfig struct {
+	Provider  string `mapstructure:"provider"`   // env(默认) 或 file
+	EnvPrefix string `mapstructure:"env_prefix"` // provider 为 env 时的环境变量前缀，变量名为前缀 + 大写的密钥名
+	Dir       string `mapstructure:"dir"`        // provider 为 file 时的密钥目录，为空时使用 /run/secrets
+}
+
+// EncryptionConfig 敏感字段(手机号、邮箱)加密，密钥从 secrets 读取，密钥名为 pii_key_<id>
+// 轮换密钥时先把新密钥加入 keys 并设为 current_key，再执行 cmd/rotatekeys 重新加密，完成后才能移除旧密钥
+type EncryptionConfig struct {
+	Enabled    bool     `mapstructure:"enabled"`
+	CurrentKey string   `mapstructure:"current_key"` // 加密使用的密钥ID
+	Keys       []string `mapstructure:"keys"`        // 可用于解密的全部密钥ID，必须包含 current_key
+}
+
+// LockoutConfig 登录失败锁定，计数保存在 Redis 中，Redis 未启用时不生效
+type LockoutConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	MaxFailures   int  `mapstructure:"max_failures"`    // 同一账号在 window 内失败多少次后锁定，0 表示不按账号锁定
+	IPMaxFailures int  `mapstructure:"ip_max_failures"` // 同一 IP 在 window 内失败多少次后锁定，0 表示不按 IP 锁定
+	Window        int  `mapstructure:"window"`          // 失败计数窗口，单位秒
+	Duration      int  `mapstructure:"duration"`        // 锁定时长，单位秒
+}
+
+// PasswordConfig 密码策略，注册和修改密码时校验
+type PasswordConfig struct {
+	MinLength      int                `mapstructure:"min_length"`
+	RequireUpper   bool               `mapstructure:"require_upper"`
+	RequireLower   bool               `mapstructure:"require_lower"`
+	RequireDigit   bool               `mapstructure:"require_digit"`
+	RequireSymbol  bool               `mapstructure:"require_symbol"`
+	DictionaryFile string             `mapstructure:"dictionary_file"` // 弱密码字典文件，每行一个，为空时只使用内置字典
+	BreachCheck    BreachCheckConfig  `mapstructure:"breach_check"`
+	Hash           PasswordHashConfig `mapstructure:"hash"`
+}
+
+// PasswordHashConfig 密码哈希算法，修改后已有用户的密码在下次登录时按新配置重新哈希
+type PasswordHashConfig struct {
+	Algorithm  string       `mapstructure:"algorithm"`   // bcrypt(默认) 或 argon2id
+	BcryptCost int          `mapstructure:"bcrypt_cost"` // bcrypt 的 cost，默认 12
+	Argon2     Argon2Config `mapstructure:"argon2"`
+}
+
+// Argon2Config argon2id 的计算参数
+type Argon2Config struct {
+	Time    uint32 `mapstructure:"time"`    // 迭代次数，默认 3
+	Memory  uint32 `mapstructure:"memory"`  // 内存，单位 KiB，默认 65536
+	Threads uint8  `mapstructure:"threads"` // 并行度，默认 2
+}
+
+// BreachCheckConfig 泄露密码检查，通过 Have I Been Pwned 的 k-anonymity 接口查询，只发送密码哈希的前 5 位
+type BreachCheckConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Endpoint  string `mapstructure:"endpoint"`  // 范围查询接口，为空时使用 api.pwnedpasswords.com
+	Timeout   int    `mapstructure:"timeout"`   // 查询超时，单位秒
+	Threshold int    `mapstructure:"threshold"` // 泄露次数达到该值时拒绝，默认 1
+	FailOpen  bool   `mapstructure:"fail_open"` // 查询失败时是否放行，关闭时接口不可用会导致无法注册和修改密码
+}
+
+// BroadcastConfig 定时群发配置，到期的群发由后台任务按批次发送
+type BroadcastConfig struct {
+	Enabled      bool `mapstructure:"enabled"`       // 是否在本实例上执行群发任务，多实例部署时可以只在部分实例上开启
+	PollInterval int  `mapstructure:"poll_interval"` // 扫描到期群发的间隔，单位秒
+	BatchSize    int  `mapstructure:"batch_size"`    // 每批发送的用户数，每批结束后记录进度并检查是否已取消
+}
+
+// MailConfig SMTP 邮件配置，未启用时群发跳过邮件渠道
+type MailConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password" redact:"true"`
+	From     string `mapstructure:"from"`
+}
+
+// ShipmentConfig 物流跟踪配置，物流事件可以由承运商回调推送，也可以由后台任务轮询拉取
+type ShipmentConfig struct {
+	PollEnabled    bool              `mapstructure:"poll_enabled"`                  // 是否在本实例上轮询承运商，只轮询已接入查询接口的承运商
+	PollInterval   int               `mapstructure:"poll_interval"`                 // 同一包裹两次查询的最小间隔，单位秒
+	BatchSize      int               `mapstructure:"batch_size"`                    // 每轮最多查询的包裹数
+	WebhookSecrets map[string]string `mapstructure:"webhook_secrets" redact:"true"` // 承运商 -> 回调签名密钥，未配置密钥的承运商回调一律拒绝
+}
+
+// WishlistConfig 收藏夹配置，Redis 启用时收藏先写入 Redis 集合，再由后台任务定期落库
+type WishlistConfig struct {
+	FlushInterval int `mapstructure:"flush_interval"` // 落库间隔，单位秒
+	MaxItems      int `mapstructure:"max_items"`      // 每个用户最多收藏的商品数
+}
+
+// ViewsConfig 浏览计数配置，Redis 启用时浏览先在 Redis 中累加，再由后台任务批量落库
+//
+// loss_tolerant 为 true 时优先保证性能，计数允许少量丢失：Redis 写入失败的浏览直接丢弃，落库失败的一批增量也丢弃；
+// 为 false 时 Redis 写入失败改为直接写库，落库失败的增量保留在 Redis 中下次重试，极端情况下(写库成功但清理增量失败)会重复计数。
+type ViewsConfig struct {
+	FlushInterval int  `mapstructure:"flush_interval"` // 落库间隔，单位秒
+	BatchSize     int  `mapstructure:"batch_size"`     // 每条 SQL 最多写入的对象数
+	LossTolerant  bool `mapstructure:"loss_tolerant"`  // 是否允许丢失计数
+}
+
+// 订单号格式默认值，生成的订单号形如 EC20231215123456
+const (
+	defaultOrderNumberPrefix       = "EC"
+	defaultOrderNumberDateFormat   = "20060102"
+	defaultOrderNumberRandomLength = 6
+
+	// minOrderNumberRandomLength 随机部分的最小位数，位数过少时同一时间段内很容易重复
+	minOrderNumberRandomLength = 4
+	// maxOrderNumberRandomLength 随机部分的最大位数
+	maxOrderNumberRandomLength = 18
+)
+
+// OrderNumberConfig 订单号格式配置，types 按订单类型覆盖默认格式
+type OrderNumberConfig struct {
+	Prefix       string                       `mapstructure:"prefix"`        // 默认前缀，为空时使用 EC
+	DateFormat   string                       `mapstructure:"date_format"`   // 默认日期格式，为空时使用 20060102
+	RandomLength int                          `mapstructure:"random_length"` // 默认随机数位数，为 0 时使用 6
+	Types        map[string]OrderNumberFormat `mapstructure:"types"`         // 订单类型(小写) -> 格式，prefix 必填且不能与其他前缀冲突，其余未配置的字段沿用默认格式
+}
+
+// OrderNumberFormat 订单号格式: 前缀 + 日期 + 随机数
+type OrderNumberFormat struct {
+	Prefix       string `mapstructure:"prefix"`        // 前缀，只能包含字母和数字
+	DateFormat   string `mapstructure:"date_format"`   // 日期部分的 Go 时间格式，如 20060102
+	RandomLength int    `mapstructure:"random_length"` // 随机数部分的位数
+}
+
+// Format 订单类型 orderType 使用的订单号格式，orderType 为空时返回默认格式，未配置的类型返回 false
+func (c OrderNumberConfig) Format(orderType string) (OrderNumberFormat, bool) {
+	base := OrderNumberFormat{
+		Prefix:       c.Prefix,
+		DateFormat:   c.DateFormat,
+		RandomLength: c.RandomLength,
+	}.withDefaults(OrderNumberFormat{
+		Prefix:       defaultOrderNumberPrefix,
+		DateFormat:   defaultOrderNumberDateFormat,
+		RandomLength: defaultOrderNumberRandomLength,
+	})
+
+	if orderType == "" {
+		return base, true
+	}
+
+	format, ok := c.Types[strings.ToLower(orderType)]
+	if !ok {
+		return OrderNumberFormat{}, false
+	}
+	return format.withDefaults(base), true
+}
+
+func (f OrderNumberFormat) withDefaults(base OrderNumberFormat) OrderNumberFormat {
+	if f.Prefix == "" {
+		f.Prefix = base.Prefix
+	}
+	if f.DateFormat == "" {
+		f.DateFormat = base.DateFormat
+	}
+	if f.RandomLength == 0 {
+		f.RandomLength = base.RandomLength
+	}
+	return f
+}
+
+// Validate 校验所有订单号格式: 生成的订单号不能超过 maxLen，每个类型都需配置自己的前缀，且不同类型的前缀不能互为前缀，
+// 保证不同类型的订单号不会落在同一个号段里
+func (c OrderNumberConfig) Validate(maxLen int) error {
+	prefixes := make(map[string]string, len(c.Types)+1)
+
+	names := make([]string, 0, len(c.Types))
+	for name := range c.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	names = append([]string{""}, names...)
+
+	for _, name := range names {
+		format, _ := c.Format(name)
+		field := "order_number"
+		if name != "" {
+			field = fmt.Sprintf("order_number.types.%s", name)
+			// 沿用默认前缀会与默认格式落在同一个号段里
+			if c.Types[name].Prefix == "" {
+				return fmt.Errorf("%s: prefix is required, each order type needs its own prefix distinct from order_number.prefix", field)
+			}
+		}
+
+		if err := format.validate(maxLen); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+
+		for other, prefix := range prefixes {
+			if strings.HasPrefix(format.Prefix, prefix) || strings.HasPrefix(prefix, format.Prefix) {
+				return fmt.Errorf("%s: prefix %q conflicts with %s prefix %q", field, format.Prefix, other, prefix)
+			}
+		}
+		prefixes[field] = format.Prefix
+	}
+	return nil
+}
+
+func (f OrderNumberFormat) validate(maxLen int) error {
+	for _, r := range f.Prefix {
+		if !isASCIIAlnum(r) {
+			return fmt.Errorf("prefix %q must contain only letters and digits", f.Prefix)
+		}
+	}
+
+	// 日期部分只允许数字，避免订单号中出现空格、冒号等需要转义的字符
+	sample := time.Date(2006, 12, 31, 23, 59, 59, 0, time.UTC).Format(f.DateFormat)
+	for _, r := range sample {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("date_format %q must produce digits only, got %q", f.DateFormat, sample)
+		}
+	}
+
+	if f.RandomLength < minOrderNumberRandomLength || f.RandomLength > maxOrderNumberRandomLength {
+		return fmt.Errorf("random_length must be between %d and %d, got %d",
+			minOrderNumberRandomLength, maxOrderNumberRandomLength, f.RandomLength)
+	}
+
+	if length := len(f.Prefix) + len(sample) + f.RandomLength; length > maxLen {
+		return fmt.Errorf("generated order number is %d characters, exceeds column size %d", length, maxLen)
+	}
+	return nil
+}
+
+func isASCIIAlnum(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// TapeConfig 请求录制配置，按比例抽样保存完整的请求和响应，仅用于非 release 模式下排查问题
+type TapeConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	SampleRate  float64 `mapstructure:"sample_rate"`   // 抽样比例，0~1
+	TTL         int     `mapstructure:"ttl"`           // 录制保存时长，单位分钟
+	MaxBodySize int     `mapstructure:"max_body_size"` // 请求/响应体最多保存的字节数，超出部分截断
+}
+
+// ResponseCacheConfig HTTP 响应缓存配置，缓存附近门店、商品浏览数等公开 GET 接口的响应，需启用 Redis
+type ResponseCacheConfig struct {
+	Enabled bool           `mapstructure:"enabled"`
+	TTL     int            `mapstructure:"ttl"`    // 默认缓存时间，单位秒
+	Routes  map[string]int `mapstructure:"routes"` // 缓存名 -> 缓存时间(秒)，覆盖 ttl，为 0 时不缓存该组
+}
+
+// QuotaConfig 按用户套餐的请求配额，计数保存在 Redis 中
+type QuotaConfig struct {
+	Enabled     bool                             `mapstructure:"enabled"`
+	DefaultTier string                           `mapstructure:"default_tier"` // 未在 users 中配置的用户(及未登录请求)使用的套餐
+	Users       map[string]string                `mapstructure:"users"`        // 用户名(小写) -> 套餐
+	Tiers       map[string]QuotaLimit            `mapstructure:"tiers"`        // 套餐默认配额
+	Groups      map[string]map[string]QuotaLimit `mapstructure:"groups"`       // 按路由组覆盖套餐配额，如 orders.free
+}
+
+// QuotaLimit 单个套餐的配额，0 表示不限制
+type QuotaLimit struct {
+	Daily   int64 `mapstructure:"daily"`
+	Monthly int64 `mapstructure:"monthly"`
+}
+
+// Tier 用户所属套餐，viper 会把 map 的键转为小写，因此按小写用户名查找
+func (c QuotaConfig) Tier(username string) string {
+	if tier, ok := c.Users[strings.ToLower(username)]; ok {
+		return tier
+	}
+	return c.DefaultTier
+}
+
+// Limit 路由组 group 下套餐 tier 的配额，路由组未单独配置时使用套餐默认配额
+func (c QuotaConfig) Limit(group, tier string) QuotaLimit {
+	if limit, ok := c.Groups[group][tier]; ok {
+		return limit
+	}
+	return c.Tiers[tier]
+}
+
+// HealthConfig 依赖探活配置
+type HealthConfig struct {
+	CheckInterval int `mapstructure:"check_interval"` // Postgres、Redis 探活间隔，单位秒
+	ProbeTimeout  int `mapstructure:"probe_timeout"`  // /readyz 探活每个依赖的超时，单位毫秒，默认 1000；应小于探针的 timeoutSeconds
 }
 
 type ServerConfig struct {
@@ -23,6 +423,15 @@ type ServerConfig struct {
 	ReadTimeout  int    `mapstructure:"read_timeout"`
 	WriteTimeout int    `mapstructure:"write_timeout"`
 	LimitNum     int    `mapstructure:"limit_num"`
+
+	ShutdownTimeout     int `mapstructure:"shutdown_timeout"`      // 优雅退出的总时限，单位秒，超过后剩余的关闭步骤不再执行
+	ShutdownHookTimeout int `mapstructure:"shutdown_hook_timeout"` // 单个关闭步骤(如关闭数据库连接)的时限，单位秒；HTTP 服务使用总时限等待处理中的请求
+}
+
+// AdminConfig 管理端口配置，版本信息等运维接口只在该端口上提供
+type AdminConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	Port    int  `mapstructure:"port"`
 }
 
 type LanguageConfig struct {
@@ -33,7 +442,7 @@ type DatabaseConfig struct {
 	Host         string `mapstructure:"host"`
 	Port         int    `mapstructure:"port"`
 	User         string `mapstructure:"user"`
-	Password     string `mapstructure:"password"`
+	Password     string `mapstructure:"password" redact:"true"`
 	DBName       string `mapstructure:"dbname"`
 	SSLMode      string `mapstructure:"sslmode"`
 	MaxIdleConns int    `mapstructure:"max_idle_conns"`
@@ -44,12 +453,57 @@ type DatabaseConfig struct {
 }
 
 type RedisConfig struct {
+	Enabled      bool   `mapstructure:"enabled"` // 为 false 时不连接 Redis，缓存全部回源数据库
 	Addr         string `mapstructure:"addr"`
-	Password     string `mapstructure:"password"`
+	Password     string `mapstructure:"password" redact:"true"`
 	DB           int    `mapstructure:"db"`
 	PoolSize     int    `mapstructure:"pool_size"`
 	MinIdleConns int    `mapstructure:"min_idle_conns"`
 	MaxRetries   int    `mapstructure:"max_retries"`
+	// KeyPrefix 键的命名空间前缀，{env} 替换为当前环境，如 gin-app:{env}: ；为空时不加前缀
+	// 多个环境共用一个 Redis 时用它隔离键，修改前先用 cmd/rediskeys 迁移已有的键
+	KeyPrefix string `mapstructure:"key_prefix"`
+
+	Breaker RedisBreakerConfig `mapstructure:"breaker"`
+}
+
+// Namespace 环境 env 下的键前缀
+func (c RedisConfig) Namespace(env string) string {
+	return strings.ReplaceAll(c.KeyPrefix, "{env}", env)
+}
+
+// RedisBreakerConfig Redis 熔断配置，Redis 抖动时缓存操作快速失败并回源数据库
+type RedisBreakerConfig struct {
+	Enabled          bool   `mapstructure:"enabled"`
+	MaxFailures      uint32 `mapstructure:"max_failures"`       // 连续失败多少次后熔断
+	OpenTimeout      int    `mapstructure:"open_timeout"`       // 熔断持续时间，单位秒，之后进入半开状态
+	HalfOpenRequests uint32 `mapstructure:"half_open_requests"` // 半开状态下允许通过的探测请求数
+}
+
+// CacheConfig 缓存降级配置
+//
+// 缓存只是数据库的副本，Redis 不可用时"可选"的缓存操作失败只记录日志并跳过，
+// 避免数据库已写入成功、请求却因为写缓存失败而报错。
+type CacheConfig struct {
+	Optional   bool            `mapstructure:"optional"`   // 缓存操作默认是否可选
+	Operations map[string]bool `mapstructure:"operations"` // 按操作名覆盖默认值，如 order_list_invalidate: false
+
+	LogicalExpiry LogicalExpiryConfig `mapstructure:"logical_expiry"`
+}
+
+// LogicalExpiryConfig 热点键(管理端订单列表)的逻辑过期: 过期后先返回旧数据，由一个 goroutine 在后台刷新
+type LogicalExpiryConfig struct {
+	Enabled  bool `mapstructure:"enabled"`
+	TTL      int  `mapstructure:"ttl"`       // 逻辑过期时间，单位秒
+	StaleTTL int  `mapstructure:"stale_ttl"` // 逻辑过期后仍可返回旧数据的最长时间，单位秒，超过后按未命中处理
+}
+
+// IsOptional 缓存操作 op 失败时是否可以跳过
+func (c CacheConfig) IsOptional(op string) bool {
+	if optional, ok := c.Operations[op]; ok {
+		return optional
+	}
+	return c.Optional
 }
 
 type LogConfig struct {
@@ -57,56 +511,448 @@ type LogConfig struct {
 	FilePath string `mapstructure:"file_path"`
 	MaxSize  int    `mapstructure:"max_size"`
 	MaxAge   int    `mapstructure:"max_age"`
+
+	MaxBackups int  `mapstructure:"max_backups"` // 最多保留的备份文件数，0 表示不按数量清理
+	Compress   bool `mapstructure:"compress"`    // 是否 gzip 压缩备份文件
+
+	SlowFilePath         string `mapstructure:"slow_file_path"`         // 慢日志文件路径，为空时与 file_path 同目录下的 slow.log
+	SlowRequestThreshold int    `mapstructure:"slow_request_threshold"` // 慢请求阈值，单位毫秒；0 表示不记录
+	SlowQueryThreshold   int    `mapstructure:"slow_query_threshold"`   // 慢 SQL 阈值，单位毫秒；0 表示不记录
+
+	DebugTrace bool `mapstructure:"debug_trace"` // 管理员携带请求头 X-Debug: 1 时在响应中返回 SQL、Redis 等链路信息
+
+	Levels   map[string]string `mapstructure:"levels"` // 按模块覆盖日志级别，如 {redis: error, middleware: info}
+	Async    LogAsyncConfig    `mapstructure:"async"`
+	Sampling LogSamplingConfig `mapstructure:"sampling"`
+	Sinks    []LogSinkConfig   `mapstructure:"sinks"`
+	Tail     LogTailConfig     `mapstructure:"tail"`
+
+	RecentErrors LogRecentErrorsConfig `mapstructure:"recent_errors"`
+}
+
+// LogTailConfig 在内存中保留最近的日志，通过管理端口的 WebSocket 接口 /logs/tail 实时查看
+type LogTailConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	Size    int  `mapstructure:"size"` // 保留的日志条数，0 表示使用默认值 1000
+}
+
+// LogRecentErrorsConfig 在内存中保留最近的 error 及以上级别的日志，通过管理端口的 /errors/recent 查看
+type LogRecentErrorsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	Size    int  `mapstructure:"size"` // 保留的日志条数，0 表示使用默认值 1000
+}
+
+// LogSinkConfig 日志外发配置，把日志直接推送到集中式日志系统
+type LogSinkConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Type    string `mapstructure:"type"`    // syslog 或 loki
+	Network string `mapstructure:"network"` // syslog: tcp/udp，为空时连接本机 syslog
+	Address string `mapstructure:"address"` // syslog: host:port；loki: push 接口地址
+	Tag     string `mapstructure:"tag"`     // syslog 标签
+
+	Labels        map[string]string `mapstructure:"labels"`         // loki 日志流标签
+	BatchSize     int               `mapstructure:"batch_size"`     // loki 每批推送的日志条数
+	FlushInterval int               `mapstructure:"flush_interval"` // loki 推送间隔，单位毫秒
+}
+
+// LogAsyncConfig 日志异步缓冲写入配置
+type LogAsyncConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	BufferSize    int  `mapstructure:"buffer_size"`    // 缓冲区大小，单位字节，0 表示使用 zap 默认值 256KB
+	FlushInterval int  `mapstructure:"flush_interval"` // 刷新间隔，单位毫秒，0 表示使用 zap 默认值 30s
+}
+
+// LogSamplingConfig 日志采样配置，每秒内同级别同消息的日志前 initial 条全部输出，之后每 thereafter 条输出 1 条
+type LogSamplingConfig struct {
+	Enabled    bool `mapstructure:"enabled"`
+	Initial    int  `mapstructure:"initial"`
+	Thereafter int  `mapstructure:"thereafter"`
 }
 
 type FileConfig struct {
 	DirName   string `mapstructure:"dir_name"`
-	UrlPrefix string `mapstructure:"url_prefix"`
+	UrlPrefix string `mapstructure:"url_prefix"` // 下载链接的前缀，后接相对 dir_name 的文件路径和签名
 	MaxSize   int64  `mapstructure:"max_size"`
+
+	SignKey string `mapstructure:"sign_key" redact:"true"` // 下载链接的签名密钥，为空时启动时随机生成，重启后已签发的链接失效
+	LinkTTL int    `mapstructure:"link_ttl"`               // 下载链接的有效期，单位秒
+
+	Scan FileScanConfig `mapstructure:"scan"`
+}
+
+// FileScanConfig 上传文件的病毒扫描，上传的文件先放在隔离目录，后台扫描通过后才移入 dir_name 供下载
+type FileScanConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Provider      string `mapstructure:"provider"`       // clamav 或 http
+	Address       string `mapstructure:"address"`        // provider 为 clamav 时 clamd 的 TCP 地址
+	URL           string `mapstructure:"url"`            // provider 为 http 时的扫描接口
+	Timeout       int    `mapstructure:"timeout"`        // 单个文件的扫描超时，单位秒
+	Attempts      int    `mapstructure:"attempts"`       // 扫描服务不可用时每个文件最多尝试的次数
+	Workers       int    `mapstructure:"workers"`        // 并发扫描的文件数
+	QuarantineDir string `mapstructure:"quarantine_dir"` // 隔离目录，未扫描和感染的文件放在这里，不会被下载
+}
+
+const (
+	FileScanProviderClamAV = "clamav"
+	FileScanProviderHTTP   = "http"
+)
+
+// TimeoutDuration 单个文件的扫描超时
+func (c FileScanConfig) TimeoutDuration() time.Duration {
+	if c.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(c.Timeout) * time.Second
+}
+
+// defaultFileLinkTTL 未配置 link_ttl 时下载链接的有效期(秒)
+const defaultFileLinkTTL = 3600
+
+// LinkDuration 下载链接的有效期
+func (c FileConfig) LinkDuration() time.Duration {
+	if c.LinkTTL <= 0 {
+		return defaultFileLinkTTL * time.Second
+	}
+	return time.Duration(c.LinkTTL) * time.Second
 }
 
 type SessionConfig struct {
 	UseRedis bool   `mapstructure:"use_redis"`
 	Name     string `mapstructure:"name"`
 	Size     int    `mapstructure:"size"`
-	Key      string `mapstructure:"key"`
+	Key      string `mapstructure:"key" redact:"true"`
 	MaxAge   int    `mapstructure:"max_age"`
 	Path     string `mapstructure:"path"`
 	Domain   string `mapstructure:"domain"`
 	HttpOnly bool   `mapstructure:"http_only"`
 	Secure   bool   `mapstructure:"secure"`
+
+	// KeyPairs 会话密钥对列表，第一组用于签名和加密新会话，其余仅用于解密旧会话，
+	// 轮换密钥时把新密钥对插到最前面即可，已登录用户不会被登出；为空时退回只签名的 Key
+	KeyPairs []SessionKeyPair `mapstructure:"key_pairs"`
+
+	RedisDB    int    `mapstructure:"redis_db"`   // 会话使用的 Redis 库，与缓存隔离
+	KeyPrefix  string `mapstructure:"key_prefix"` // 会话键前缀，默认 session_
+	Serializer string `mapstructure:"serializer"` // 会话序列化方式: gob(默认) 或 json
+
+	// IdleTimeout 空闲超时，单位秒，超过该时长没有访问需要登录的接口则会话失效，每次访问滑动延长；为 0 时不限制
+	// AbsoluteTimeout 绝对超时，单位秒，从登录开始计算，滑动延长不会超过该时长；为 0 时不限制
+	// 两者依赖 Redis 记录时间戳，max_age 应不小于 absolute_timeout，否则 cookie 会先于会话过期
+	IdleTimeout     int `mapstructure:"idle_timeout"`
+	AbsoluteTimeout int `mapstructure:"absolute_timeout"`
+
+	// ReauthMaxAge 修改密码、修改邮箱、注销账号等敏感操作要求在该时长内登录或重新验证过身份，单位秒，默认 300
+	ReauthMaxAge int `mapstructure:"reauth_max_age"`
+}
+
+// SessionKeyPair 会话密钥对
+type SessionKeyPair struct {
+	AuthKey       string `mapstructure:"auth_key" redact:"true"`       // HMAC 签名密钥，建议 32 或 64 字节
+	EncryptionKey string `mapstructure:"encryption_key" redact:"true"` // AES 加密密钥，必须为 16、24 或 32 字节
+}
+
+// defaultReauthMaxAge 敏感操作要求的身份验证有效期默认值，单位秒
+const defaultReauthMaxAge = 300
+
+// ReauthWindow 登录或重新验证身份后，多久内允许执行敏感操作
+func (c SessionConfig) ReauthWindow() time.Duration {
+	if c.ReauthMaxAge <= 0 {
+		return defaultReauthMaxAge * time.Second
+	}
+	return time.Duration(c.ReauthMaxAge) * time.Second
+}
+
+// SessionKeys 返回会话存储使用的密钥列表(按 签名密钥, 加密密钥 成对排列)
+func (c SessionConfig) SessionKeys() ([][]byte, error) {
+	if len(c.KeyPairs) == 0 {
+		if c.Key == "" {
+			return nil, fmt.Errorf("session.key or session.key_pairs must be set")
+		}
+		return [][]byte{[]byte(c.Key)}, nil
+	}
+
+	keys := make([][]byte, 0, len(c.KeyPairs)*2)
+	for i, pair := range c.KeyPairs {
+		if pair.AuthKey == "" {
+			return nil, fmt.Errorf("session.key_pairs[%d].auth_key is empty", i)
+		}
+		switch len(pair.EncryptionKey) {
+		case 16, 24, 32:
+		default:
+			return nil, fmt.Errorf("session.key_pairs[%d].encryption_key must be 16, 24 or 32 bytes, got %d", i, len(pair.EncryptionKey))
+		}
+		keys = append(keys, []byte(pair.AuthKey), []byte(pair.EncryptionKey))
+	}
+	return keys, nil
+}
+
+// 认证方式
+const (
+	AuthModeSession = "session"
+	AuthModeJWT     = "jwt"
+)
+
+// AuthConfig 接口认证方式
+//
+// session(默认): 登录后写入会话 cookie；jwt: 登录时返回访问令牌和刷新令牌，接口通过
+// Authorization: Bearer <access_token> 认证，不再写入会话，适用于无法保存 cookie 的 API 客户端
+type AuthConfig struct {
+	Mode   string       `mapstructure:"mode"`
+	JWT    JWTConfig    `mapstructure:"jwt"`
+	APIKey APIKeyConfig `mapstructure:"api_key"`
+}
+
+// APIKeyConfig 供脚本和外部系统使用的 API Key，启用后需要登录的接口也接受 X-API-Key 认证
+type APIKeyConfig struct {
+	Enabled    bool `mapstructure:"enabled"`
+	MaxPerUser int  `mapstructure:"max_per_user"` // 每个用户最多持有的未吊销 API Key 数，0 为默认值 10
+}
+
+// defaultAPIKeysPerUser 未配置 max_per_user 时每个用户最多持有的 API Key 数
+const defaultAPIKeysPerUser = 10
+
+// Limit 每个用户最多持有的未吊销 API Key 数
+func (c APIKeyConfig) Limit() int {
+	if c.MaxPerUser <= 0 {
+		return defaultAPIKeysPerUser
+	}
+	return c.MaxPerUser
+}
+
+// JWTConfig jwt 模式下的令牌配置
+type JWTConfig struct {
+	Secret     string `mapstructure:"secret" redact:"true"` // HS256 签名密钥，jwt 模式下必须设置，建议不少于 32 字节
+	Issuer     string `mapstructure:"issuer"`
+	AccessTTL  int    `mapstructure:"access_ttl"`  // 访问令牌有效期，单位秒
+	RefreshTTL int    `mapstructure:"refresh_ttl"` // 刷新令牌有效期，单位秒
+}
+
+const (
+	defaultJWTAccessTTL  = 15 * time.Minute
+	defaultJWTRefreshTTL = 7 * 24 * time.Hour
+)
+
+// JWTEnabled 是否使用 jwt 认证，mode 不是 session 或 jwt 时返回错误
+func (c AuthConfig) JWTEnabled() (bool, error) {
+	switch c.Mode {
+	case "", AuthModeSession:
+		return false, nil
+	case AuthModeJWT:
+		if c.JWT.Secret == "" {
+			return false, fmt.Errorf("auth.jwt.secret must be set when auth.mode is jwt")
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("auth.mode must be session or jwt, got %q", c.Mode)
+	}
+}
+
+// IDConfig 对外接口中的用户ID混淆，启用后 JSON 和路径参数中的用户ID为 Hashids 编码的字符串
+//
+// 修改 salt、alphabet 或 min_length 后已发给客户端的ID全部失效，上线后不应再改动。
+type IDConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Salt      string `mapstructure:"salt" redact:"true"` // 启用时必须设置，不同环境应使用不同的值
+	MinLength int    `mapstructure:"min_length"`         // 编码结果的最小长度
+	Alphabet  string `mapstructure:"alphabet"`           // 为空时使用 hashid.DefaultAlphabet
+}
+
+// TTLs 访问令牌和刷新令牌的有效期
+func (c JWTConfig) TTLs() (access, refresh time.Duration) {
+	access, refresh = defaultJWTAccessTTL, defaultJWTRefreshTTL
+	if c.AccessTTL > 0 {
+		access = time.Duration(c.AccessTTL) * time.Second
+	}
+	if c.RefreshTTL > 0 {
+		refresh = time.Duration(c.RefreshTTL) * time.Second
+	}
+	return access, refresh
+}
+
+type MetricsConfig struct {
+	Enabled   bool             `mapstructure:"enabled"`
+	Path      string           `mapstructure:"path"`
+	Buckets   []float64        `mapstructure:"buckets"`
+	RouteSLOs []RouteSLOConfig `mapstructure:"route_slos"`
+}
+
+// RouteSLOConfig 单个路由的延迟目标
+type RouteSLOConfig struct {
+	Method    string  `mapstructure:"method"`
+	Route     string  `mapstructure:"route"`     // 路由模板，如 /api/v1/users/:id
+	Threshold float64 `mapstructure:"threshold"` // 延迟阈值，单位秒
+}
+
+// ConcurrencyConfig 并发请求数限制，超出全局或单路由上限时返回 503
+type ConcurrencyConfig struct {
+	MaxConcurrent int                      `mapstructure:"max_concurrent"` // 全局最大并发请求数，0 表示不限制
+	RetryAfter    int                      `mapstructure:"retry_after"`    // 503 响应的 Retry-After，单位秒
+	Routes        []RouteConcurrencyConfig `mapstructure:"routes"`
+	PerUser       UserConcurrencyConfig    `mapstructure:"per_user"`
+}
+
+// UserConcurrencyConfig 按用户统计正在处理的请求数和滑动窗口内的请求总数，计数保存在 Redis 中，需启用 Redis
+//
+// 只统计需要登录的路由组，未登录的请求由 server.limit_num 按 IP 限速；
+// 配置了并发上限的用户同时处理的请求数达到上限时，新请求返回 429。
+type UserConcurrencyConfig struct {
+	Enabled       bool           `mapstructure:"enabled"`
+	Window        int            `mapstructure:"window"`         // 请求总数的滑动窗口长度，单位秒，默认 60
+	MaxConcurrent int            `mapstructure:"max_concurrent"` // 每个用户同时处理的请求数上限，0 表示不限制
+	Users         map[string]int `mapstructure:"users"`          // 用户名(小写) -> 并发上限，覆盖 max_concurrent，用于限制滥用的客户端
+	StaleAfter    int            `mapstructure:"stale_after"`    // 请求超过该时长仍未结束时不再计入并发数(实例异常退出时没有结束记录)，单位秒，默认 300
+}
+
+const (
+	defaultUserConcurrencyWindow     = 60
+	defaultUserConcurrencyStaleAfter = 300
+)
+
+// WindowDuration 请求总数的滑动窗口长度
+func (c UserConcurrencyConfig) WindowDuration() time.Duration {
+	if c.Window <= 0 {
+		return defaultUserConcurrencyWindow * time.Second
+	}
+	return time.Duration(c.Window) * time.Second
+}
+
+// StaleDuration 请求计入并发数的最长时间
+func (c UserConcurrencyConfig) StaleDuration() time.Duration {
+	if c.StaleAfter <= 0 {
+		return defaultUserConcurrencyStaleAfter * time.Second
+	}
+	return time.Duration(c.StaleAfter) * time.Second
+}
+
+// Limit 用户的并发上限，0 表示不限制；viper 会把 map 的键转为小写，因此按小写用户名查找
+func (c UserConcurrencyConfig) Limit(username string) int {
+	if limit, ok := c.Users[strings.ToLower(username)]; ok {
+		return limit
+	}
+	return c.MaxConcurrent
+}
+
+// RouteGroupConfig 按路由组调整中间件，注册路由时生效，只作用于模块创建的路由组
+//
+// 同一路径被多个模块用作路由组时(如 /api/v1/users)配置对这些路由组都生效，限速共用一个计数。
+type RouteGroupConfig struct {
+	Auth        string `mapstructure:"auth"`          // 需要登录的路由组使用的认证方式，session 或 jwt，为空时按 auth.mode
+	RateLimit   int    `mapstructure:"rate_limit"`    // 每个客户端 IP 每秒允许的请求数，0 为只受 server.limit_num 限制
+	MaxBodySize int64  `mapstructure:"max_body_size"` // 请求体最大字节数，超出时返回 413，0 为不限制
+	NoCache     bool   `mapstructure:"no_cache"`      // 关闭该路由组的响应缓存
+	Dedup       bool   `mapstructure:"dedup"`         // 合并同一用户同时发起的相同 GET 请求，只执行一次
+}
+
+// RouteConcurrencyConfig 单个路由的最大并发请求数
+type RouteConcurrencyConfig struct {
+	Method string `mapstructure:"method"`
+	Route  string `mapstructure:"route"` // 路由模板，如 /api/v1/orders
+	Limit  int    `mapstructure:"limit"`
 }
 
 var GlobalConfig *Config
 
-func Load() (*Config, error) {
+// EnvPrefix 覆盖配置的环境变量前缀，键中的 . 换成 _ 并转为大写，如 APP_DATABASE_HOST 覆盖 database.host
+const EnvPrefix = "APP"
+
+// Overrides 命令行参数 -set key=value 指定的配置，实现 flag.Value，可以重复指定
+type Overrides []string
+
+func (o *Overrides) String() string {
+	return strings.Join(*o, ",")
+}
+
+func (o *Overrides) Set(value string) error {
+	if key, _, ok := strings.Cut(value, "="); !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	*o = append(*o, value)
+	return nil
+}
+
+// Load 按 内置默认配置 < 配置文件 < 环境变量 < overrides 的顺序合并配置，后者覆盖前者
+//
+// 配置文件为 config.<SERVER_ENV>.yaml，依次在 ./configs、./config、. 中查找，找不到时只使用默认配置；
+// overrides 为 key=value 形式，key 必须是已有的配置项或其下的 map 键。
+func Load(overrides ...string) (*Config, error) {
 	env := os.Getenv("SERVER_ENV")
 	if env == "" {
 		env = "local"
 	}
 
-	viper.SetConfigName(fmt.Sprintf("config.%s", env))
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./configs")
-	viper.AddConfigPath("./config")
-	viper.AddConfigPath(".")
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(configs.Example)); err != nil {
+		return nil, fmt.Errorf("failed to read default config: %w", err)
+	}
+
+	v.SetConfigName(fmt.Sprintf("config.%s", env))
+	v.AddConfigPath("./configs")
+	v.AddConfigPath("./config")
+	v.AddConfigPath(".")
+	if err := v.MergeInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
 
-	// 自动检查环境变量是否与现有的配置键匹配
-	viper.AutomaticEnv()
+	// 默认配置中的每一项都可以用环境变量覆盖
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
 
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	for _, override := range overrides {
+		key, value, _ := strings.Cut(override, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !isKnownKey(key) {
+			return nil, fmt.Errorf("unknown config key %q", key)
+		}
+		v.Set(key, value)
 	}
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	config.Env = env
+	config.ConfigFile = v.ConfigFileUsed()
+
 	GlobalConfig = &config
 	return &config, nil
 }
 
+// isKnownKey key 是否对应 Config 中的配置项，按 mapstructure 标签逐级查找，map 类型的配置项下允许任意键(如 quota.users.alice)
+func isKnownKey(key string) bool {
+	t := reflect.TypeOf(Config{})
+	for _, name := range strings.Split(key, ".") {
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByTag(t, name)
+			if !ok {
+				return false
+			}
+			t = field.Type
+		case reflect.Map:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func fieldByTag(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag := strings.Split(field.Tag.Get("mapstructure"), ",")[0]; tag != "-" && tag == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
 func GetConfig() *Config {
 	return GlobalConfig
 }
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadLayers(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "configs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file := "server:\n  port: 8000\nlog:\n  level: warn\nquota:\n  users:\n    alice: pro\n"
+	if err := os.WriteFile(filepath.Join(dir, "configs", "config.layers.yaml"), []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+	t.Setenv("SERVER_ENV", "layers")
+	t.Setenv("APP_LOG_LEVEL", "error")
+	t.Setenv("APP_REDIS_ADDR", "redis:6379")
+
+	cfg, err := Load("redis.addr=cache:6379", "quota.users.bob=free")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Port != 8000 {
+		t.Errorf("file should override default, port = %d", cfg.Server.Port)
+	}
+	if cfg.Admin.Port != 9061 {
+		t.Errorf("unset key should use default, admin port = %d", cfg.Admin.Port)
+	}
+	if cfg.Log.Level != "error" {
+		t.Errorf("env should override file, level = %q", cfg.Log.Level)
+	}
+	if cfg.Redis.Addr != "cache:6379" {
+		t.Errorf("override should win over env, addr = %q", cfg.Redis.Addr)
+	}
+	if cfg.Quota.Users["alice"] != "pro" || cfg.Quota.Users["bob"] != "free" {
+		t.Errorf("quota users = %v", cfg.Quota.Users)
+	}
+
+	if _, err := Load("server.nope=1"); err == nil {
+		t.Error("unknown key should be rejected")
+	}
+}
+
+func TestLoadDefaultsOnly(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("SERVER_ENV", "missing")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ConfigFile != "" || cfg.Server.Port != 9060 {
+		t.Errorf("expected defaults only, file = %q port = %d", cfg.ConfigFile, cfg.Server.Port)
+	}
+}
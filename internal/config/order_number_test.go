@@ -0,0 +1,50 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrderNumberFormat(t *testing.T) {
+	cfg := OrderNumberConfig{
+		Types: map[string]OrderNumberFormat{"wholesale": {Prefix: "WS", RandomLength: 8}},
+	}
+
+	format, ok := cfg.Format("Wholesale")
+	if !ok {
+		t.Fatal("wholesale format not found")
+	}
+	if format.Prefix != "WS" || format.DateFormat != defaultOrderNumberDateFormat || format.RandomLength != 8 {
+		t.Fatalf("wholesale format = %+v", format)
+	}
+
+	if _, ok := cfg.Format("retail"); ok {
+		t.Fatal("unconfigured type should not be found")
+	}
+
+	if err := cfg.Validate(32); err != nil {
+		t.Fatalf("valid config rejected: %v", err)
+	}
+}
+
+func TestOrderNumberValidate(t *testing.T) {
+	tests := map[string]OrderNumberConfig{
+		"too long":         {Prefix: "ORDER", DateFormat: "20060102150405", RandomLength: 18},
+		"non-digit date":   {DateFormat: "2006-01-02"},
+		"short random":     {RandomLength: 2},
+		"invalid prefix":   {Prefix: "EC-"},
+		"prefix conflicts": {Types: map[string]OrderNumberFormat{"export": {Prefix: "ECX"}}},
+	}
+
+	// 类型没有配置前缀时报错说明需要前缀，而不是报与沿用的默认前缀冲突
+	cfg := OrderNumberConfig{Types: map[string]OrderNumberFormat{"bulk": {RandomLength: 8}}}
+	if err := cfg.Validate(32); err == nil || !strings.Contains(err.Error(), "prefix is required") {
+		t.Errorf("type without prefix: err = %v, want prefix is required", err)
+	}
+
+	for name, cfg := range tests {
+		if err := cfg.Validate(32); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
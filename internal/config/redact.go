@@ -0,0 +1,70 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// redactedValue 敏感字段的替换值
+const redactedValue = "******"
+
+// Redacted 返回脱敏后的配置，键名与配置文件一致(取 mapstructure 标签)
+//
+// 标记了 `redact:"true"` 的字段会被替换为 ******，空值保持为空，
+// 以便运维区分"未配置"和"已配置但被隐藏"。
+func (c *Config) Redacted() map[string]interface{} {
+	return redactStruct(reflect.ValueOf(c).Elem())
+}
+
+func redactStruct(v reflect.Value) map[string]interface{} {
+	t := v.Type()
+	res := make(map[string]interface{}, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		res[name] = redactValue(v.Field(i), field.Tag.Get("redact") == "true")
+	}
+	return res
+}
+
+func redactValue(v reflect.Value, secret bool) interface{} {
+	if secret {
+		if v.IsZero() {
+			return v.Interface()
+		}
+		return redactedValue
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return redactStruct(v)
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem(), false)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items[i] = redactValue(v.Index(i), false)
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import "testing"
+
+func TestRedacted(t *testing.T) {
+	cfg := &Config{
+		Env:      "local",
+		Database: DatabaseConfig{Host: "localhost", Password: "postgres"},
+		Redis:    RedisConfig{Addr: "localhost:6379"},
+		Session:  SessionConfig{Key: "gin-session"},
+	}
+
+	res := cfg.Redacted()
+
+	if _, ok := res["Env"]; ok {
+		t.Fatalf("fields tagged mapstructure:\"-\" should be skipped")
+	}
+
+	db := res["database"].(map[string]interface{})
+	if db["password"] != redactedValue {
+		t.Fatalf("database password not masked: %v", db["password"])
+	}
+	if db["host"] != "localhost" {
+		t.Fatalf("database host = %v", db["host"])
+	}
+
+	redis := res["redis"].(map[string]interface{})
+	if redis["password"] != "" {
+		t.Fatalf("empty secret should stay empty, got %v", redis["password"])
+	}
+
+	session := res["session"].(map[string]interface{})
+	if session["key"] != redactedValue {
+		t.Fatalf("session key not masked: %v", session["key"])
+	}
+}
@@ -0,0 +1,1601 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/dependency"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/lockout"
+	"gin-app-start/internal/middleware"
+	"gin-app-start/internal/reqstats"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/buildinfo"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/hashid"
+	"gin-app-start/pkg/logger"
+	"gin-app-start/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AdminController 运维相关接口，只注册在管理端口上
+type AdminController struct {
+	cfg          *config.Config
+	deps         *dependency.Container
+	cacheService service.CacheService
+	orderService service.OrderService
+
+	productService     service.ProductService
+	referralService    service.ReferralService
+	tagService         service.TagService
+	broadcastService   service.BroadcastService
+	shipmentService    service.ShipmentService
+	leaderboardService service.LeaderboardService
+	geoService         service.GeoService
+	projectionService  service.OrderProjectionService
+	loginGuard         *lockout.Guard           // 未启用登录锁定时为 nil
+	requestStats       *reqstats.Tracker        // 未启用 concurrency.per_user 时为 nil
+	recordingService   service.RecordingService // 未开启请求录制时为 nil
+	logTail            *logger.Ring             // 未开启实时日志时为 nil
+	recentErrors       *logger.Ring             // 未开启 log.recent_errors 时为 nil
+}
+
+func NewAdminController(cfg *config.Config, deps *dependency.Container, cacheService service.CacheService, orderService service.OrderService, productService service.ProductService, referralService service.ReferralService, tagService service.TagService, broadcastService service.BroadcastService, shipmentService service.ShipmentService, leaderboardService service.LeaderboardService, geoService service.GeoService, projectionService service.OrderProjectionService, loginGuard *lockout.Guard, requestStats *reqstats.Tracker, recordingService service.RecordingService, logTail *logger.Ring, recentErrors *logger.Ring) *AdminController {
+	return &AdminController{
+		cfg:                cfg,
+		deps:               deps,
+		cacheService:       cacheService,
+		orderService:       orderService,
+		productService:     productService,
+		referralService:    referralService,
+		tagService:         tagService,
+		broadcastService:   broadcastService,
+		shipmentService:    shipmentService,
+		leaderboardService: leaderboardService,
+		geoService:         geoService,
+		projectionService:  projectionService,
+		loginGuard:         loginGuard,
+		requestStats:       requestStats,
+		recordingService:   recordingService,
+		logTail:            logTail,
+		recentErrors:       recentErrors,
+	}
+}
+
+// Name 模块名
+func (ctrl *AdminController) Name() string {
+	return "admin"
+}
+
+// RegisterRoutes 运维接口，只注册在管理端口上
+func (ctrl *AdminController) RegisterRoutes(r router.Router) {
+	root := r.Group("")
+	{
+		root.GET("/version", ctrl.Version())
+		root.GET("/config", ctrl.Config())
+		root.GET("/dependencies", ctrl.Dependencies())
+	}
+
+	cache := r.Group("/cache")
+	{
+		cache.GET("", ctrl.GetCacheKey())
+		cache.DELETE("", ctrl.DeleteCacheKey())
+		cache.POST("/order_list/warm", ctrl.WarmOrderListCache())
+	}
+
+	tags := r.Group("/tags")
+	{
+		tags.GET("", ctrl.ListTags())
+	}
+
+	users := r.Group("/users")
+	{
+		users.GET("/:id/tags", ctrl.ListUserTags())
+		users.POST("/:id/tags", ctrl.TagUser())
+		users.DELETE("/:id/tags/:tag", ctrl.UntagUser())
+	}
+
+	segments := r.Group("/segments")
+	{
+		segments.GET("/users", ctrl.ListSegment())
+		segments.GET("/export", ctrl.ExportSegment())
+	}
+
+	broadcasts := r.Group("/broadcasts")
+	{
+		broadcasts.POST("", ctrl.CreateBroadcast())
+		broadcasts.GET("", ctrl.ListBroadcasts())
+		broadcasts.GET("/:id", ctrl.GetBroadcast())
+		broadcasts.POST("/:id/cancel", ctrl.CancelBroadcast())
+	}
+
+	shipments := r.Group("/shipments")
+	{
+		shipments.POST("", ctrl.CreateShipment())
+		shipments.POST("/:id/events", ctrl.AddShipmentEvents())
+	}
+
+	stores := r.Group("/stores")
+	{
+		stores.POST("", ctrl.CreateStore())
+		stores.DELETE("/:id", ctrl.DeleteStore())
+	}
+
+	products := r.Group("/products")
+	{
+		products.POST("", ctrl.CreateProduct())
+		products.PUT("/:product_id", ctrl.UpdateProduct())
+		products.DELETE("/:product_id", ctrl.DeleteProduct())
+	}
+
+	orders := r.Group("/orders")
+	{
+		orders.GET("/nearby", ctrl.NearbyOrders())
+		orders.POST("/projection/rebuild", ctrl.RebuildOrderProjection())
+	}
+
+	geo := r.Group("/geo")
+	{
+		geo.POST("/rebuild", ctrl.RebuildGeoIndex())
+	}
+
+	security := r.Group("/security")
+	{
+		security.GET("/blocked", ctrl.ListBlocked())
+		security.POST("/unblock", ctrl.Unblock())
+	}
+
+	requests := r.Group("/requests")
+	{
+		requests.GET("/users", ctrl.ListRequestStats())
+		requests.GET("/users/:username", ctrl.GetRequestStats())
+	}
+
+	leaderboards := r.Group("/leaderboards")
+	{
+		leaderboards.POST("/:name/rebuild", ctrl.RebuildLeaderboard())
+	}
+
+	referrals := r.Group("/referrals")
+	{
+		referrals.GET("/stats", ctrl.ReferralStats())
+	}
+
+	recordings := r.Group("/recordings")
+	{
+		recordings.GET("/:trace_id", ctrl.GetRecording())
+	}
+
+	logs := r.Group("/logs")
+	{
+		logs.GET("/tail", ctrl.TailLogs())
+	}
+
+	errs := r.Group("/errors")
+	{
+		errs.GET("/recent", ctrl.RecentErrors())
+	}
+}
+
+type versionResponse struct {
+	buildinfo.Info
+	Env string `json:"env" example:"local"`
+}
+
+// Version godoc
+//
+//	@Summary		Build information
+//	@Description	Get version, git commit, build time, Go version and config environment
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=controller.versionResponse}
+//	@Router			/version [get]
+func (ctrl *AdminController) Version() common.HandlerFunc {
+	return func(c common.Context) {
+		c.Payload(versionResponse{
+			Info: buildinfo.Get(),
+			Env:  ctrl.cfg.Env,
+		})
+	}
+}
+
+// Config godoc
+//
+//	@Summary		Effective configuration
+//	@Description	Dump the configuration loaded by this instance, with passwords and keys masked
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=object}
+//	@Router			/config [get]
+func (ctrl *AdminController) Config() common.HandlerFunc {
+	return func(c common.Context) {
+		c.Payload(gin.H{
+			"env":    ctrl.cfg.Env,
+			"config": ctrl.cfg.Redacted(),
+		})
+	}
+}
+
+// Dependencies godoc
+//
+//	@Summary		Dependency status
+//	@Description	Get connectivity status of Postgres and Redis as seen by the background health checker
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=[]dependency.Status}
+//	@Router			/dependencies [get]
+func (ctrl *AdminController) Dependencies() common.HandlerFunc {
+	return func(c common.Context) {
+		c.Payload(ctrl.deps.Statuses())
+	}
+}
+
+// GetCacheKey godoc
+//
+//	@Summary		Inspect cache key
+//	@Description	Get type, ttl and contents of a cache key
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			key	query		string	true	"Cache key"
+//	@Success		200	{object}	common.Response{data=dto.CacheKeyResponse}
+//	@Failure		400	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@Router			/cache [get]
+func (ctrl *AdminController) GetCacheKey() common.HandlerFunc {
+	return func(c common.Context) {
+		key := c.Query("key")
+		if key == "" {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamQueryError,
+				code.Text(code.ParamQueryError)).WithError(errors.New("key is empty")),
+			)
+			return
+		}
+
+		res, err := ctrl.cacheService.Inspect(c, key)
+		if err != nil {
+			if errors.Is(err, service.ErrCacheKeyNotFound) {
+				c.AbortWithError(common.Error(
+					http.StatusNotFound,
+					code.CacheNotExist,
+					code.Text(code.CacheNotExist)).WithError(err),
+				)
+				return
+			}
+
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.CacheGetError,
+				code.Text(code.CacheGetError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(res)
+	}
+}
+
+// DeleteCacheKey godoc
+//
+//	@Summary		Delete cache key
+//	@Description	Delete a cache key by its exact name
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			key	query		string	true	"Cache key"
+//	@Success		200	{object}	common.Response{data=object}
+//	@Failure		400	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@Router			/cache [delete]
+func (ctrl *AdminController) DeleteCacheKey() common.HandlerFunc {
+	return func(c common.Context) {
+		key := c.Query("key")
+		if key == "" {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamQueryError,
+				code.Text(code.ParamQueryError)).WithError(errors.New("key is empty")),
+			)
+			return
+		}
+
+		deleted, err := ctrl.cacheService.Delete(c, key)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.CacheDelError,
+				code.Text(code.CacheDelError)).WithError(err),
+			)
+			return
+		}
+
+		if !deleted {
+			c.AbortWithError(common.Error(
+				http.StatusNotFound,
+				code.CacheNotExist,
+				code.Text(code.CacheNotExist)).WithError(service.ErrCacheKeyNotFound),
+			)
+			return
+		}
+
+		c.Payload(gin.H{"key": key, "deleted": true})
+	}
+}
+
+// WarmOrderListCache godoc
+//
+//	@Summary		Re-warm order list caches
+//	@Description	Reload every cached order list page from the database
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=dto.WarmCacheResponse}
+//	@Failure		400	{object}	common.Response
+//	@Router			/cache/order_list/warm [post]
+func (ctrl *AdminController) WarmOrderListCache() common.HandlerFunc {
+	return func(c common.Context) {
+		warmed, err := ctrl.orderService.WarmOrderListCache(c)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.CacheSetError,
+				code.Text(code.CacheSetError)).WithError(err).WithField("warmed", warmed),
+			)
+			return
+		}
+
+		c.Payload(dto.WarmCacheResponse{Warmed: warmed})
+	}
+}
+
+// ReferralStats godoc
+//
+//	@Summary		Referral conversion statistics
+//	@Description	Get issued invitation codes, referred users, signup conversion and top referrers
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=dto.ReferralStatsResponse}
+//	@Failure		400	{object}	common.Response
+//	@Router			/referrals/stats [get]
+func (ctrl *AdminController) ReferralStats() common.HandlerFunc {
+	return func(c common.Context) {
+		stats, err := ctrl.referralService.GetStats(c)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ReferralStatsError,
+				code.Text(code.ReferralStatsError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(stats)
+	}
+}
+
+// GetRecording godoc
+//
+//	@Summary		Get request recording
+//	@Description	Get the recorded request/response pair of a sampled request by trace id
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			trace_id	path		string	true	"Trace ID"
+//	@Success		200			{object}	common.Response{data=dto.Recording}
+//	@Failure		400			{object}	common.Response
+//	@Failure		404			{object}	common.Response
+//	@Router			/recordings/{trace_id} [get]
+func (ctrl *AdminController) GetRecording() common.HandlerFunc {
+	return func(c common.Context) {
+		if ctrl.recordingService == nil {
+			c.AbortWithError(common.Error(
+				http.StatusNotFound,
+				code.RecordingNotExist,
+				code.Text(code.RecordingNotExist)).WithError(errors.New("tape is disabled")),
+			)
+			return
+		}
+
+		rec, err := ctrl.recordingService.GetRecording(c, c.Param("trace_id"))
+		if err != nil {
+			if errors.Is(err, service.ErrRecordingNotFound) {
+				c.AbortWithError(common.Error(
+					http.StatusNotFound,
+					code.RecordingNotExist,
+					code.Text(code.RecordingNotExist)).WithError(err),
+				)
+				return
+			}
+
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.CacheGetError,
+				code.Text(code.CacheGetError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(rec)
+	}
+}
+
+// ListTags godoc
+//
+//	@Summary		List tags
+//	@Description	List all user tags with the number of users carrying each tag
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=[]dto.TagResponse}
+//	@Failure		400	{object}	common.Response
+//	@Router			/tags [get]
+func (ctrl *AdminController) ListTags() common.HandlerFunc {
+	return func(c common.Context) {
+		tags, err := ctrl.tagService.ListTags(c)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.TagListError,
+				code.Text(code.TagListError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(tags)
+	}
+}
+
+// ListUserTags godoc
+//
+//	@Summary		List user tags
+//	@Description	List the tags of a user
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID, encoded when id_obfuscation is enabled"
+//	@Success		200	{object}	common.Response{data=[]dto.TagResponse}
+//	@Failure		400	{object}	common.Response
+//	@Router			/users/{id}/tags [get]
+func (ctrl *AdminController) ListUserTags() common.HandlerFunc {
+	return func(c common.Context) {
+		userID, ok := userIDParam(c, "id")
+		if !ok {
+			return
+		}
+
+		tags, err := ctrl.tagService.ListUserTags(c, userID)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.TagListError,
+				code.Text(code.TagListError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(dto.NewTagResponses(tags))
+	}
+}
+
+// TagUser godoc
+//
+//	@Summary		Tag user
+//	@Description	Add tags to a user, tags that do not exist yet are created
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"User ID, encoded when id_obfuscation is enabled"
+//	@Param			request	body		dto.TagUserRequest	true	"Tags to add"
+//	@Success		200		{object}	common.Response{data=[]dto.TagResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Router			/users/{id}/tags [post]
+func (ctrl *AdminController) TagUser() common.HandlerFunc {
+	return func(c common.Context) {
+		userID, ok := userIDParam(c, "id")
+		if !ok {
+			return
+		}
+
+		var req dto.TagUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		tags, err := ctrl.tagService.TagUser(c, userID, req.Tags)
+		if err != nil {
+			if errors.Is(err, service.ErrUserNotFound) {
+				c.AbortWithError(common.Error(
+					http.StatusNotFound,
+					code.AdminDetailError,
+					code.Text(code.AdminDetailError)).WithError(err),
+				)
+				return
+			}
+
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.TagUserError,
+				code.Text(code.TagUserError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(dto.NewTagResponses(tags))
+	}
+}
+
+// UntagUser godoc
+//
+//	@Summary		Untag user
+//	@Description	Remove a tag from a user
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID, encoded when id_obfuscation is enabled"
+//	@Param			tag	path		string	true	"Tag name"
+//	@Success		200	{object}	common.Response{data=string}
+//	@Failure		400	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@Router			/users/{id}/tags/{tag} [delete]
+func (ctrl *AdminController) UntagUser() common.HandlerFunc {
+	return func(c common.Context) {
+		userID, ok := userIDParam(c, "id")
+		if !ok {
+			return
+		}
+
+		if err := ctrl.tagService.UntagUser(c, userID, c.Param("tag")); err != nil {
+			if errors.Is(err, service.ErrTagNotFound) {
+				c.AbortWithError(common.Error(
+					http.StatusNotFound,
+					code.TagNotFound,
+					code.Text(code.TagNotFound)).WithError(err),
+				)
+				return
+			}
+
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.UntagUserError,
+				code.Text(code.UntagUserError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload("Untag user successfully")
+	}
+}
+
+// ListSegment godoc
+//
+//	@Summary		Query users by tag
+//	@Description	List users carrying any (match=any) or all (match=all) of the given tags
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			tags		query		string	true	"Comma separated tag names"
+//	@Param			match		query		string	false	"Match any or all tags"	Enums(any, all)	default(any)
+//	@Param			page		query		int		false	"Page number"			default(1)
+//	@Param			page_size	query		int		false	"Page size"				default(10)
+//	@Success		200			{object}	common.Response{data=[]dto.UserResponse}
+//	@Failure		400			{object}	common.Response
+//	@Router			/segments/users [get]
+func (ctrl *AdminController) ListSegment() common.HandlerFunc {
+	return func(c common.Context) {
+		var query dto.SegmentQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		users, total, err := ctrl.tagService.ListSegment(c, &query)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.SegmentQueryError,
+				code.Text(code.SegmentQueryError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(response.NewPaged(dto.NewUserResponses(users, dto.AdminViewer), total, query.Page, query.PageSize))
+	}
+}
+
+// ExportSegment godoc
+//
+//	@Summary		Export segment
+//	@Description	Download users carrying any (match=any) or all (match=all) of the given tags as CSV, e.g. as the audience of a targeted notification
+//	@Tags			admin
+//	@Produce		text/csv
+//	@Param			tags	query		string	true	"Comma separated tag names"
+//	@Param			match	query		string	false	"Match any or all tags"	Enums(any, all)	default(any)
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	common.Response
+//	@Router			/segments/export [get]
+func (ctrl *AdminController) ExportSegment() common.HandlerFunc {
+	return func(c common.Context) {
+		var query dto.SegmentQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		ginCtx := c.GetGinContext()
+		fileName := fmt.Sprintf("segment-%s.csv", time.Now().Format("20060102150405"))
+		ginCtx.Header("Content-Type", "text/csv; charset=utf-8")
+		ginCtx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+
+		if err := ctrl.tagService.ExportSegment(c, &query, ginCtx.Writer); err != nil {
+			// 已经开始输出 CSV 时无法再返回错误响应，只能记录日志
+			if ginCtx.Writer.Written() {
+				c.Logger().Error("export segment interrupted", zap.Error(err))
+				return
+			}
+
+			ginCtx.Writer.Header().Del("Content-Disposition")
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.SegmentExportError,
+				code.Text(code.SegmentExportError)).WithError(err),
+			)
+		}
+	}
+}
+
+// CreateBroadcast godoc
+//
+//	@Summary		Schedule broadcast
+//	@Description	Schedule an in-app and/or email broadcast to the users carrying the given tags at a future time
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.CreateBroadcastRequest	true	"Broadcast"
+//	@Success		200		{object}	common.Response{data=dto.BroadcastResponse}
+//	@Failure		400		{object}	common.Response
+//	@Router			/broadcasts [post]
+func (ctrl *AdminController) CreateBroadcast() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.CreateBroadcastRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		broadcast, err := ctrl.broadcastService.Schedule(c, &req)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.BroadcastCreateError,
+				code.Text(code.BroadcastCreateError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(dto.NewBroadcastResponse(broadcast))
+	}
+}
+
+// ListBroadcasts godoc
+//
+//	@Summary		List broadcasts
+//	@Description	List broadcasts with their status and progress, newest first
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(10)
+//	@Success		200			{object}	common.Response{data=[]dto.BroadcastResponse}
+//	@Failure		400			{object}	common.Response
+//	@Router			/broadcasts [get]
+func (ctrl *AdminController) ListBroadcasts() common.HandlerFunc {
+	return func(c common.Context) {
+		page, pageSize := pageQuery(c)
+
+		broadcasts, total, err := ctrl.broadcastService.ListBroadcasts(c, page, pageSize)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.BroadcastListError,
+				code.Text(code.BroadcastListError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(response.NewPaged(dto.NewBroadcastResponses(broadcasts), total, page, pageSize))
+	}
+}
+
+// GetBroadcast godoc
+//
+//	@Summary		Get broadcast
+//	@Description	Get a broadcast with its status and delivery progress
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Broadcast ID"
+//	@Success		200	{object}	common.Response{data=dto.BroadcastResponse}
+//	@Failure		400	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@Router			/broadcasts/{id} [get]
+func (ctrl *AdminController) GetBroadcast() common.HandlerFunc {
+	return func(c common.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		broadcast, err := ctrl.broadcastService.GetBroadcast(c, id)
+		if err != nil {
+			abortBroadcastError(c, err, code.BroadcastListError)
+			return
+		}
+
+		c.Payload(dto.NewBroadcastResponse(broadcast))
+	}
+}
+
+// CancelBroadcast godoc
+//
+//	@Summary		Cancel broadcast
+//	@Description	Cancel a pending or running broadcast, a running broadcast stops after the current batch
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Broadcast ID"
+//	@Success		200	{object}	common.Response{data=string}
+//	@Failure		400	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@Router			/broadcasts/{id}/cancel [post]
+func (ctrl *AdminController) CancelBroadcast() common.HandlerFunc {
+	return func(c common.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		if err := ctrl.broadcastService.Cancel(c, id); err != nil {
+			abortBroadcastError(c, err, code.BroadcastCancelError)
+			return
+		}
+
+		c.Payload("Cancel broadcast successfully")
+	}
+}
+
+// abortBroadcastError 群发不存在返回 404，其余错误使用 fallback 业务码
+func abortBroadcastError(c common.Context, err error, fallback int) {
+	if errors.Is(err, service.ErrBroadcastNotFound) {
+		c.AbortWithError(common.Error(
+			http.StatusNotFound,
+			code.BroadcastNotFound,
+			code.Text(code.BroadcastNotFound)).WithError(err),
+		)
+		return
+	}
+
+	c.AbortWithError(common.Error(
+		http.StatusBadRequest,
+		fallback,
+		code.Text(fallback)).WithError(err),
+	)
+}
+
+// pageQuery 解析分页参数，缺省为第 1 页、每页 10 条，每页最多 100 条
+func pageQuery(c common.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 10
+	}
+	return page, pageSize
+}
+
+// idParam 解析路径参数中的 id，解析失败时直接返回 400
+func idParam(c common.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.ParseError,
+			code.Text(code.ParseError)).WithError(err),
+		)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// userIDParam 解析路径参数中的用户ID，启用 id_obfuscation 时为编码后的字符串，解析失败时直接返回 400
+func userIDParam(c common.Context, name string) (uint, bool) {
+	id, err := hashid.Parse(c.Param(name))
+	if err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.HashIdsDecodeError,
+			code.Text(code.HashIdsDecodeError)).WithError(err),
+		)
+		return 0, false
+	}
+	return id, true
+}
+
+// CreateShipment godoc
+//
+//	@Summary		Register shipment
+//	@Description	Register a carrier tracking number for an order, tracking events then come from the carrier webhook or poller
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.CreateShipmentRequest	true	"Shipment"
+//	@Success		200		{object}	common.Response{data=dto.ShipmentResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Failure		409		{object}	common.Response
+//	@Router			/shipments [post]
+func (ctrl *AdminController) CreateShipment() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.CreateShipmentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		shipment, err := ctrl.shipmentService.CreateShipment(c, &req)
+		if err != nil {
+			abortShipmentError(c, err, code.ShipmentCreateError)
+			return
+		}
+
+		c.Payload(dto.NewShipmentResponse(shipment))
+	}
+}
+
+// AddShipmentEvents godoc
+//
+//	@Summary		Add shipment events
+//	@Description	Manually record tracking events for a shipment, e.g. for carriers without webhook or tracking API
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"Shipment ID"
+//	@Param			request	body		dto.AddShipmentEventsRequest	true	"Tracking events"
+//	@Success		200		{object}	common.Response{data=dto.ShipmentResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Router			/shipments/{id}/events [post]
+func (ctrl *AdminController) AddShipmentEvents() common.HandlerFunc {
+	return func(c common.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		var req dto.AddShipmentEventsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		shipment, err := ctrl.shipmentService.AddEvents(c, id, dto.NewShipmentEvents(req.Events))
+		if err != nil {
+			abortShipmentError(c, err, code.ShipmentEventError)
+			return
+		}
+
+		c.Payload(dto.NewShipmentResponse(shipment))
+	}
+}
+
+// RebuildLeaderboard godoc
+//
+//	@Summary		Rebuild leaderboard
+//	@Description	Recompute a leaderboard from the orders table, e.g. after Redis data loss. The leaderboard may be incomplete while rebuilding
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string	true	"Leaderboard name"	Enums(top_spenders, most_active)
+//	@Success		200		{object}	common.Response{data=dto.LeaderboardRebuildResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Router			/leaderboards/{name}/rebuild [post]
+func (ctrl *AdminController) RebuildLeaderboard() common.HandlerFunc {
+	return func(c common.Context) {
+		name := c.Param("name")
+		users, err := ctrl.leaderboardService.Rebuild(c, name)
+		if err != nil {
+			abortLeaderboardError(c, err, code.LeaderboardRebuildError)
+			return
+		}
+
+		c.Payload(dto.LeaderboardRebuildResponse{Name: name, Users: users})
+	}
+}
+
+// CreateStore godoc
+//
+//	@Summary		Create store
+//	@Description	Create a store and add it to the nearby search index
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.CreateStoreRequest	true	"Store"
+//	@Success		200		{object}	common.Response{data=dto.StoreResponse}
+//	@Failure		400		{object}	common.Response
+//	@Router			/stores [post]
+func (ctrl *AdminController) CreateStore() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.CreateStoreRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		store, err := ctrl.geoService.CreateStore(c, &req)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.StoreCreateError,
+				code.Text(code.StoreCreateError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(dto.NewStoreResponse(store))
+	}
+}
+
+// DeleteStore godoc
+//
+//	@Summary		Delete store
+//	@Description	Delete a store and remove it from the nearby search index
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Store ID"
+//	@Success		200	{object}	common.Response{data=string}
+//	@Failure		400	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@Router			/stores/{id} [delete]
+func (ctrl *AdminController) DeleteStore() common.HandlerFunc {
+	return func(c common.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		if err := ctrl.geoService.DeleteStore(c, id); err != nil {
+			if errors.Is(err, service.ErrStoreNotFound) {
+				c.AbortWithError(common.Error(
+					http.StatusNotFound,
+					code.StoreNotFound,
+					code.Text(code.StoreNotFound)).WithError(err),
+				)
+				return
+			}
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.StoreDeleteError,
+				code.Text(code.StoreDeleteError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload("Delete store successfully")
+	}
+}
+
+// CreateProduct godoc
+//
+//	@Summary		Create product
+//	@Description	Add a product to the catalog so it can be added to orders
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.CreateProductRequest	true	"Product"
+//	@Success		200		{object}	common.Response{data=dto.ProductResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		409		{object}	common.Response
+//	@Router			/products [post]
+func (ctrl *AdminController) CreateProduct() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.CreateProductRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		product, err := ctrl.productService.CreateProduct(c, &req)
+		if err != nil {
+			abortProductError(c, err, code.ProductCreateError)
+			return
+		}
+
+		c.Payload(dto.NewProductResponse(product))
+	}
+}
+
+// UpdateProduct godoc
+//
+//	@Summary		Update product
+//	@Description	Change the name or price of a product. Items already added to orders keep the name and price they were added with
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			product_id	path		string						true	"Product ID"
+//	@Param			request		body		dto.UpdateProductRequest	true	"Product"
+//	@Success		200			{object}	common.Response{data=dto.ProductResponse}
+//	@Failure		400			{object}	common.Response
+//	@Failure		404			{object}	common.Response
+//	@Router			/products/{product_id} [put]
+func (ctrl *AdminController) UpdateProduct() common.HandlerFunc {
+	return func(c common.Context) {
+		productID, ok := bindProduct(c)
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateProductRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		product, err := ctrl.productService.UpdateProduct(c, productID, &req)
+		if err != nil {
+			abortProductError(c, err, code.ProductUpdateError)
+			return
+		}
+
+		c.Payload(dto.NewProductResponse(product))
+	}
+}
+
+// DeleteProduct godoc
+//
+//	@Summary		Delete product
+//	@Description	Remove a product from the catalog. Items already added to orders are kept
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Success		200			{object}	common.Response{data=string}
+//	@Failure		400			{object}	common.Response
+//	@Failure		404			{object}	common.Response
+//	@Router			/products/{product_id} [delete]
+func (ctrl *AdminController) DeleteProduct() common.HandlerFunc {
+	return func(c common.Context) {
+		productID, ok := bindProduct(c)
+		if !ok {
+			return
+		}
+
+		if err := ctrl.productService.DeleteProduct(c, productID); err != nil {
+			abortProductError(c, err, code.ProductDeleteError)
+			return
+		}
+
+		c.Payload("Delete product successfully")
+	}
+}
+
+// NearbyOrders godoc
+//
+//	@Summary		Nearby orders
+//	@Description	Find orders whose delivery location is within a radius of a location, nearest first
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			latitude	query		number	true	"Latitude"
+//	@Param			longitude	query		number	true	"Longitude"
+//	@Param			radius		query		number	false	"Radius in kilometers, at most 50"	default(5)
+//	@Param			limit		query		int		false	"Max number of orders, at most 100"	default(20)
+//	@Success		200			{object}	common.Response{data=[]dto.NearbyOrderResponse}
+//	@Failure		400			{object}	common.Response
+//	@Router			/orders/nearby [get]
+func (ctrl *AdminController) NearbyOrders() common.HandlerFunc {
+	return func(c common.Context) {
+		query, ok := bindNearbyQuery(c)
+		if !ok {
+			return
+		}
+
+		orders, err := ctrl.geoService.NearbyOrders(c, query)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.NearbySearchError,
+				code.Text(code.NearbySearchError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(orders)
+	}
+}
+
+// RebuildOrderProjection godoc
+//
+//	@Summary		Rebuild order list projection
+//	@Description	Regenerate the order list read model (order_summaries) from the orders table, e.g. after an instance crashed before applying pending order changes. Lists stay available but may lag while rebuilding
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=dto.OrderProjectionRebuildResponse}
+//	@Failure		400	{object}	common.Response
+//	@Router			/orders/projection/rebuild [post]
+func (ctrl *AdminController) RebuildOrderProjection() common.HandlerFunc {
+	return func(c common.Context) {
+		orders, err := ctrl.projectionService.Rebuild(c)
+		if err != nil {
+			bizCode := code.ProjectionRebuildError
+			if errors.Is(err, service.ErrProjectionDisabled) {
+				bizCode = code.ProjectionDisabled
+			}
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				bizCode,
+				code.Text(bizCode)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(dto.OrderProjectionRebuildResponse{Orders: orders})
+	}
+}
+
+// RebuildGeoIndex godoc
+//
+//	@Summary		Rebuild geo index
+//	@Description	Rebuild the Redis GEO indexes of stores and order delivery locations from the database. Nearby results may be incomplete while rebuilding
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=dto.GeoRebuildResponse}
+//	@Failure		400	{object}	common.Response
+//	@Router			/geo/rebuild [post]
+func (ctrl *AdminController) RebuildGeoIndex() common.HandlerFunc {
+	return func(c common.Context) {
+		res, err := ctrl.geoService.Rebuild(c)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.GeoRebuildError,
+				code.Text(code.GeoRebuildError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(res)
+	}
+}
+
+// ListBlocked godoc
+//
+//	@Summary		List blocked accounts and IPs
+//	@Description	List accounts and IPs locked after too many failed logins, and clients currently throttled by the rate limiter of this instance
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=dto.BlockedResponse}
+//	@Failure		400	{object}	common.Response
+//	@Router			/security/blocked [get]
+func (ctrl *AdminController) ListBlocked() common.HandlerFunc {
+	return func(c common.Context) {
+		res := &dto.BlockedResponse{
+			LockoutEnabled: ctrl.loginGuard != nil,
+			Locks:          []*dto.LockResponse{},
+			RateLimited:    []*dto.RateLimitedResponse{},
+		}
+
+		if ctrl.loginGuard != nil {
+			locks, err := ctrl.loginGuard.List(c.RequestContext())
+			if err != nil {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.BlockedListError,
+					code.Text(code.BlockedListError)).WithError(err),
+				)
+				return
+			}
+			for _, lock := range locks {
+				res.Locks = append(res.Locks, &dto.LockResponse{
+					Kind:      lock.Kind,
+					Subject:   lock.Subject,
+					Failures:  lock.Failures,
+					LockedAt:  lock.LockedAt,
+					ExpiresAt: lock.ExpiresAt,
+				})
+			}
+		}
+
+		for _, client := range middleware.RateLimitedClients() {
+			res.RateLimited = append(res.RateLimited, &dto.RateLimitedResponse{
+				IP:         client.IP,
+				LastAccess: client.LastAccess,
+			})
+		}
+
+		c.Payload(res)
+	}
+}
+
+// userRequestStatsResponse 转换单个用户的请求统计
+func userRequestStatsResponse(stats *reqstats.Stats) *dto.UserRequestStatsResponse {
+	res := &dto.UserRequestStatsResponse{
+		Username:   stats.Username,
+		Concurrent: stats.Concurrent,
+		Requests:   stats.Requests,
+		Rejected:   stats.Rejected,
+		Limit:      stats.Limit,
+	}
+	if !stats.LastSeen.IsZero() {
+		res.LastSeen = &stats.LastSeen
+	}
+	return res
+}
+
+// ListRequestStats godoc
+//
+//	@Summary		List request statistics by user
+//	@Description	List users with requests in the sliding window of concurrency.per_user, ordered by requests in progress and then by request count, across all instances. Use it to find abusive clients and cap them in concurrency.per_user.users
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			limit	query		int	false	"Max number of users, at most 1000"	default(20)
+//	@Success		200		{object}	common.Response{data=dto.RequestStatsResponse}
+//	@Failure		400		{object}	common.Response
+//	@Router			/requests/users [get]
+func (ctrl *AdminController) ListRequestStats() common.HandlerFunc {
+	return func(c common.Context) {
+		var query dto.RequestStatsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+		if query.Limit == 0 {
+			query.Limit = 20
+		}
+
+		res := &dto.RequestStatsResponse{
+			Enabled: ctrl.requestStats != nil,
+			Users:   []*dto.UserRequestStatsResponse{},
+		}
+		if ctrl.requestStats == nil {
+			c.Payload(res)
+			return
+		}
+		res.Window = int(ctrl.requestStats.Window().Seconds())
+
+		users, err := ctrl.requestStats.Top(c.RequestContext(), query.Limit)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.RequestStatsError,
+				code.Text(code.RequestStatsError)).WithError(err),
+			)
+			return
+		}
+		for _, stats := range users {
+			res.Users = append(res.Users, userRequestStatsResponse(stats))
+		}
+
+		c.Payload(res)
+	}
+}
+
+// GetRequestStats godoc
+//
+//	@Summary		Request statistics of a user
+//	@Description	Get the requests in progress and the request count in the sliding window of one user. Counts are 0 for users without recent requests
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			username	path		string	true	"Username"
+//	@Success		200			{object}	common.Response{data=dto.RequestStatsResponse}
+//	@Failure		400			{object}	common.Response
+//	@Router			/requests/users/{username} [get]
+func (ctrl *AdminController) GetRequestStats() common.HandlerFunc {
+	return func(c common.Context) {
+		res := &dto.RequestStatsResponse{
+			Enabled: ctrl.requestStats != nil,
+			Users:   []*dto.UserRequestStatsResponse{},
+		}
+		if ctrl.requestStats == nil {
+			c.Payload(res)
+			return
+		}
+		res.Window = int(ctrl.requestStats.Window().Seconds())
+
+		stats, err := ctrl.requestStats.User(c.RequestContext(), c.Param("username"))
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.RequestStatsError,
+				code.Text(code.RequestStatsError)).WithError(err),
+			)
+			return
+		}
+		res.Users = append(res.Users, userRequestStatsResponse(stats))
+
+		c.Payload(res)
+	}
+}
+
+// Unblock godoc
+//
+//	@Summary		Unblock account or IP
+//	@Description	Remove a login lockout (kind account or ip) or reset the rate limit of a client on this instance (kind rate_limit). Every unblock is written to the audit log with the operator
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.UnblockRequest	true	"Block to remove"
+//	@Success		200		{object}	common.Response{data=string}
+//	@Failure		400		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Router			/security/unblock [post]
+func (ctrl *AdminController) Unblock() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.UnblockRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		auditFields := []zap.Field{
+			zap.String("operator", req.Operator),
+			zap.String("kind", req.Kind),
+			zap.String("subject", req.Subject),
+			zap.String("reason", req.Reason),
+			zap.String("client_ip", c.GetGinContext().ClientIP()),
+		}
+
+		found := false
+		if req.Kind == "rate_limit" {
+			found = middleware.ResetRateLimit(req.Subject)
+		} else if ctrl.loginGuard != nil {
+			lock, err := ctrl.loginGuard.Unlock(c.RequestContext(), req.Kind, req.Subject)
+			if err != nil {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.UnblockError,
+					code.Text(code.UnblockError)).WithError(err),
+				)
+				return
+			}
+			if lock != nil {
+				found = true
+				auditFields = append(auditFields, zap.Int64("failures", lock.Failures), zap.Time("locked_at", lock.LockedAt))
+			}
+		}
+
+		if !found {
+			c.AbortWithError(common.Error(
+				http.StatusNotFound,
+				code.BlockNotFound,
+				code.Text(code.BlockNotFound)).WithError(errors.Errorf("%s %s is not blocked", req.Kind, req.Subject)),
+			)
+			return
+		}
+
+		logger.Module(c.Logger(), "audit").Info("brute-force block removed", auditFields...)
+		c.Payload("Unblock successfully")
+	}
+}
+
+const (
+	// defaultTailLines 实时日志连接后先发送的最近日志条数
+	defaultTailLines = 100
+	// tailBuffer 待发送日志的缓冲条数，客户端接收不及时、缓冲写满后丢弃新日志
+	tailBuffer = 256
+	// tailPingInterval 没有日志时也定期发送 ping，及时发现断开的连接
+	tailPingInterval = 30 * time.Second
+	// tailWriteTimeout 单条消息的写超时
+	tailWriteTimeout = 10 * time.Second
+)
+
+// tailUpgrader 使用默认的 Origin 检查: 只接受同源的浏览器页面和不带 Origin 的命令行工具，
+// 其他网站的页面不能借用户的浏览器连接管理端口
+var tailUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+}
+
+// TailLogs godoc
+//
+//	@Summary		Tail logs
+//	@Description	Stream application logs over WebSocket (wscat -c ws://localhost:9061/logs/tail). The most recent entries (see lines) are sent first, then new entries as they are written; each text message is one JSON log entry. Entries are dropped when the client reads too slowly.
+//	@Tags			admin
+//	@Param			lines	query		int		false	"Number of recent entries sent first"	default(100)	minimum(0)	maximum(10000)
+//	@Param			level	query		string	false	"Minimum level"							Enums(debug, info, warn, error)
+//	@Success		101		{string}	string	"Switching Protocols"
+//	@Failure		400		{object}	common.Response
+//	@Router			/logs/tail [get]
+func (ctrl *AdminController) TailLogs() common.HandlerFunc {
+	return func(c common.Context) {
+		if ctrl.logTail == nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.LogTailDisabled,
+				code.Text(code.LogTailDisabled)).WithError(errors.New("log.tail is disabled")),
+			)
+			return
+		}
+
+		var query dto.LogTailQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+		lines := defaultTailLines
+		if query.Lines != nil {
+			lines = *query.Lines
+		}
+		level := zapcore.DebugLevel
+		if query.Level != "" {
+			level, _ = zapcore.ParseLevel(query.Level)
+		}
+
+		// 升级失败时 Upgrader 已经返回了 HTTP 错误
+		conn, err := tailUpgrader.Upgrade(c.ResponseWriter(), c.Request(), nil)
+		if err != nil {
+			c.Logger().Warn("log tail upgrade failed", zap.Error(err))
+			return
+		}
+		defer conn.Close()
+
+		recent, entries, cancel := ctrl.logTail.Subscribe(lines, tailBuffer)
+		defer cancel()
+		if lines == 0 {
+			recent = nil
+		}
+
+		// 客户端不发送数据，读循环只用于处理 close、pong 控制帧并发现连接断开
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		send := func(entry logger.RingEntry) error {
+			if entry.Level < level {
+				return nil
+			}
+			conn.SetWriteDeadline(time.Now().Add(tailWriteTimeout))
+			return conn.WriteMessage(websocket.TextMessage, entry.Data)
+		}
+		for _, entry := range recent {
+			if err := send(entry); err != nil {
+				return
+			}
+		}
+
+		ping := time.NewTicker(tailPingInterval)
+		defer ping.Stop()
+		for {
+			select {
+			case entry := <-entries:
+				if err := send(entry); err != nil {
+					return
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(tailWriteTimeout)); err != nil {
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	}
+}
+
+// RecentErrors godoc
+//
+//	@Summary		Recent errors
+//	@Description	List the most recent error, panic and fatal log entries kept in memory (log.recent_errors), newest first. Each entry is the JSON log entry as written to the log file. Entries are lost on restart.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			limit	query		int	false	"Number of entries, defaults to all kept entries"	minimum(1)	maximum(10000)
+//	@Success		200		{object}	common.Response{data=dto.RecentErrorsResponse}
+//	@Failure		400		{object}	common.Response
+//	@Router			/errors/recent [get]
+func (ctrl *AdminController) RecentErrors() common.HandlerFunc {
+	return func(c common.Context) {
+		var query dto.RecentErrorsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		res := &dto.RecentErrorsResponse{
+			Enabled: ctrl.recentErrors != nil,
+			Entries: []json.RawMessage{},
+		}
+		if ctrl.recentErrors != nil {
+			entries := ctrl.recentErrors.Recent(query.Limit)
+			for i := len(entries) - 1; i >= 0; i-- {
+				res.Entries = append(res.Entries, entries[i].Data)
+			}
+		}
+		c.Payload(res)
+	}
+}
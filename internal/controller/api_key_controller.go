@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/errors"
+)
+
+type APIKeyController struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyController(apiKeyService service.APIKeyService) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Name 模块名
+func (kc *APIKeyController) Name() string {
+	return "api_keys"
+}
+
+// RegisterRoutes API Key 管理接口挂在 /users 下，只能由本人登录后操作，不接受 API Key 认证
+func (kc *APIKeyController) RegisterRoutes(r router.Router) {
+	interceptors := r.Interceptors()
+	users := r.AuthGroup("/users", interceptors.Quota("users"))
+	{
+		users.GET("/api_keys", interceptors.NotAPIKey(), kc.ListAPIKeys())
+		users.POST("/api_keys", interceptors.NotAPIKey(), interceptors.RecentAuth(), kc.CreateAPIKey())
+		users.DELETE("/api_keys/:id", interceptors.NotAPIKey(), interceptors.NotImpersonating(), kc.RevokeAPIKey())
+	}
+}
+
+// CreateAPIKey godoc
+//
+//	@Summary		Create API key
+//	@Description	Create an API key for scripts and integrations. Requests with the key in the X-API-Key header act as the session user. The plaintext key is only returned once
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Param			request	body		dto.CreateAPIKeyRequest	true	"API key name"
+//	@Success		200		{object}	common.Response{data=dto.CreateAPIKeyResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		403		{object}	common.Response
+//	@Failure		409		{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/api_keys [post]
+func (kc *APIKeyController) CreateAPIKey() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.CreateAPIKeyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		key, plain, err := kc.apiKeyService.Create(c, user.UserId, req.Name)
+		if err != nil {
+			if errors.Is(err, service.ErrAPIKeyLimit) {
+				c.AbortWithError(common.Error(
+					http.StatusConflict,
+					code.APIKeyLimit,
+					code.Text(code.APIKeyLimit)).WithError(err),
+				)
+				return
+			}
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.APIKeyCreateError,
+				code.Text(code.APIKeyCreateError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(&dto.CreateAPIKeyResponse{
+			APIKeyResponse: *dto.NewAPIKeyResponse(key),
+			Key:            plain,
+		})
+	}
+}
+
+// ListAPIKeys godoc
+//
+//	@Summary		List my API keys
+//	@Description	List the session user's API keys including revoked ones, newest first. Plaintext keys are never returned
+//	@Tags			users
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Success		200	{object}	common.Response{data=[]dto.APIKeyResponse}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@Failure		403	{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/api_keys [get]
+func (kc *APIKeyController) ListAPIKeys() common.HandlerFunc {
+	return func(c common.Context) {
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		keys, err := kc.apiKeyService.List(c, user.UserId)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.APIKeyListError,
+				code.Text(code.APIKeyListError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(dto.NewAPIKeyResponses(keys))
+	}
+}
+
+// RevokeAPIKey godoc
+//
+//	@Summary		Revoke API key
+//	@Description	Revoke one of the session user's API keys, requests with the key are rejected immediately
+//	@Tags			users
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Param			id	path		int	true	"API key ID"
+//	@Success		200	{object}	common.Response{data=string}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@Failure		403	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/api_keys/{id} [delete]
+func (kc *APIKeyController) RevokeAPIKey() common.HandlerFunc {
+	return func(c common.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		if err := kc.apiKeyService.Revoke(c, user.UserId, id); err != nil {
+			if errors.Is(err, service.ErrAPIKeyNotFound) {
+				c.AbortWithError(common.Error(
+					http.StatusNotFound,
+					code.APIKeyNotFound,
+					code.Text(code.APIKeyNotFound)).WithError(err),
+				)
+				return
+			}
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.APIKeyRevokeError,
+				code.Text(code.APIKeyRevokeError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload("Revoked successfully")
+	}
+}
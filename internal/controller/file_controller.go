@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/filelink"
+	"gin-app-start/internal/filescan"
+	"gin-app-start/internal/router"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/token"
+)
+
+// scanRetryAfter 文件仍在扫描时建议客户端等待的秒数
+const scanRetryAfter = 5
+
+type FileController struct {
+	links      *filelink.Signer
+	quarantine *filescan.Quarantine
+	dir        string
+}
+
+// NewFileController quarantine 为 nil 表示未启用上传扫描
+func NewFileController(links *filelink.Signer, quarantine *filescan.Quarantine, dir string) *FileController {
+	return &FileController{
+		links:      links,
+		quarantine: quarantine,
+		dir:        dir,
+	}
+}
+
+// Name 模块名
+func (fc *FileController) Name() string {
+	return "files"
+}
+
+// RegisterRoutes 下载链接自带签名，不需要登录
+func (fc *FileController) RegisterRoutes(r router.Router) {
+	files := r.Group("/files")
+	{
+		files.GET("/*filepath", fc.Download())
+	}
+}
+
+// Download godoc
+//
+//	@Summary		Download a file by signed link
+//	@Description	Download an uploaded file (such as an avatar) with a signed link issued by the server. The link carries its own expiry and signature, so no session is needed. When upload scanning is enabled, a file that is still being scanned returns 503 with Retry-After and an infected file returns 422
+//	@Tags			files
+//	@Produce		octet-stream
+//	@Param			filepath	path		string	true	"File path relative to the upload directory, such as john/avatar.png"
+//	@Param			token		query		string	true	"Signature from the download link"
+//	@Success		200			{file}		file
+//	@Failure		403			{object}	common.Response
+//	@Failure		404			{object}	common.Response
+//	@Failure		410			{object}	common.Response
+//	@Failure		422			{object}	common.Response	"File was rejected by the virus scan"
+//	@Failure		503			{object}	common.Response	"File is being scanned, see Retry-After"
+//	@Router			/api/v1/files/{filepath} [get]
+func (fc *FileController) Download() common.HandlerFunc {
+	return func(c common.Context) {
+		name, ok := filelink.Clean(c.Param("filepath"))
+		if !ok {
+			c.AbortWithError(common.Error(
+				http.StatusNotFound,
+				code.FileNotExist,
+				code.Text(code.FileNotExist)).WithError(filelink.ErrInvalidName),
+			)
+			return
+		}
+
+		if err := fc.links.Verify(name, c.Query(filelink.QueryParam)); err != nil {
+			if errors.Is(err, token.ErrExpired) {
+				c.AbortWithError(common.Error(
+					http.StatusGone,
+					code.UrlSignExpired,
+					code.Text(code.UrlSignExpired)).WithError(err),
+				)
+				return
+			}
+			c.AbortWithError(common.Error(
+				http.StatusForbidden,
+				code.UrlSignError,
+				code.Text(code.UrlSignError)).WithError(err),
+			)
+			return
+		}
+
+		file := filepath.Join(fc.dir, filepath.FromSlash(name))
+		if info, err := os.Stat(file); err != nil || info.IsDir() {
+			if err == nil {
+				err = errors.New(name + " is a directory")
+			} else if fc.abortScanning(c, name) {
+				return
+			}
+			c.AbortWithError(common.Error(
+				http.StatusNotFound,
+				code.FileNotExist,
+				code.Text(code.FileNotExist)).WithError(err),
+			)
+			return
+		}
+
+		c.File(file)
+	}
+}
+
+// abortScanning 文件不在下载目录时检查隔离区，仍在扫描或已判定感染时返回对应错误
+func (fc *FileController) abortScanning(c common.Context, name string) bool {
+	if fc.quarantine == nil {
+		return false
+	}
+
+	switch fc.quarantine.Status(name) {
+	case filescan.StatusPending:
+		c.SetHeader("Retry-After", strconv.Itoa(scanRetryAfter))
+		c.AbortWithError(common.Error(
+			http.StatusServiceUnavailable,
+			code.FileScanning,
+			code.Text(code.FileScanning)).WithError(errors.New(name + " is being scanned")),
+		)
+		return true
+	case filescan.StatusInfected:
+		c.AbortWithError(common.Error(
+			http.StatusUnprocessableEntity,
+			code.FileInfected,
+			code.Text(code.FileInfected)).WithError(errors.New(name + " is infected")),
+		)
+		return true
+	}
+	return false
+}
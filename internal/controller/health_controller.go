@@ -1,25 +1,60 @@
 package controller
 
 import (
+	"context"
+	"net/http"
+	"time"
+
+	"gin-app-start/internal/code"
 	"gin-app-start/internal/common"
+	"gin-app-start/internal/dependency"
+	"gin-app-start/internal/router"
+	"gin-app-start/pkg/errors"
 
 	"github.com/gin-gonic/gin"
 )
 
-type HealthController struct{}
+// defaultProbeTimeout 未配置 health.probe_timeout 时每个依赖的探活超时
+const defaultProbeTimeout = time.Second
+
+type HealthController struct {
+	deps         *dependency.Container
+	probeTimeout time.Duration
+}
+
+// NewHealthController probeTimeout 为就绪探针探活每个依赖的超时，<= 0 时使用默认值 1s
+func NewHealthController(deps *dependency.Container, probeTimeout time.Duration) *HealthController {
+	if probeTimeout <= 0 {
+		probeTimeout = defaultProbeTimeout
+	}
+	return &HealthController{deps: deps, probeTimeout: probeTimeout}
+}
 
-func NewHealthController() *HealthController {
-	return &HealthController{}
+// Name 模块名
+func (ctrl *HealthController) Name() string {
+	return "health"
+}
+
+// RegisterRoutes 探活接口注册在根路径，/health、/ready 为兼容旧探针配置保留
+func (ctrl *HealthController) RegisterRoutes(r router.Router) {
+	root := r.Root()
+	{
+		root.GET("/healthz", ctrl.HealthCheck())
+		root.GET("/readyz", ctrl.Readiness())
+		root.GET("/health", ctrl.HealthCheck())
+		root.GET("/ready", ctrl.Readiness())
+	}
 }
 
 // HealthCheck godoc
 //
-//	@Summary		Health check
-//	@Description	Check if the service is running
+//	@Summary		Liveness check
+//	@Description	Check if the process is running, dependencies are not checked so an unavailable database does not get the pod restarted
 //	@Tags			health
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	response.Response{data=object{status=string,message=string}}
+//	@Success		200	{object}	common.Response{data=object{status=string,message=string}}
+//	@Router			/healthz [get]
 //	@Router			/health [get]
 func (ctrl *HealthController) HealthCheck() common.HandlerFunc {
 	return func(c common.Context) {
@@ -29,3 +64,46 @@ func (ctrl *HealthController) HealthCheck() common.HandlerFunc {
 		})
 	}
 }
+
+// Readiness godoc
+//
+//	@Summary		Readiness check
+//	@Description	Ping every dependency with a timeout and report its status and latency. Returns 503 with the same status and dependencies in data when a required one (Postgres) is down; optional ones (Redis) only degrade the service
+//	@Tags			health
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	common.Response{data=object{status=string,dependencies=[]dependency.Status}}
+//	@Failure		503	{object}	common.Response{data=object{status=string,dependencies=[]dependency.Status}}
+//	@Router			/readyz [get]
+//	@Router			/ready [get]
+func (ctrl *HealthController) Readiness() common.HandlerFunc {
+	return func(c common.Context) {
+		if ctrl.deps == nil {
+			c.Payload(gin.H{
+				"status":       "ready",
+				"dependencies": []dependency.Status{},
+			})
+			return
+		}
+
+		// 不使用请求的 ctx: 探针断开连接时不应把依赖记为不可用
+		statuses, ready := ctrl.deps.Probe(context.Background(), ctrl.probeTimeout)
+		if !ready {
+			// 未就绪时同样返回所有依赖的状态，结构与就绪时一致
+			c.AbortWithError(common.Error(
+				http.StatusServiceUnavailable,
+				code.ServiceNotReady,
+				code.Text(code.ServiceNotReady)).WithError(errors.New("required dependency unavailable")).WithData(gin.H{
+				"status":       "not_ready",
+				"dependencies": statuses,
+			}),
+			)
+			return
+		}
+
+		c.Payload(gin.H{
+			"status":       "ready",
+			"dependencies": statuses,
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/impersonation"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/logger"
+)
+
+type ImpersonationController struct {
+	userService service.UserService
+	cfg         config.ImpersonationConfig
+}
+
+func NewImpersonationController(userService service.UserService, cfg config.ImpersonationConfig) *ImpersonationController {
+	return &ImpersonationController{
+		userService: userService,
+		cfg:         cfg,
+	}
+}
+
+// Name 模块名
+func (ic *ImpersonationController) Name() string {
+	return "impersonation"
+}
+
+// RegisterRoutes 代入接口与用户接口共用 /users 路由组的认证和配额
+func (ic *ImpersonationController) RegisterRoutes(r router.Router) {
+	interceptors := r.Interceptors()
+	users := r.AuthGroup("/users", interceptors.Quota("users"))
+	{
+		users.POST("/impersonate", interceptors.RecentAuth(), ic.StartImpersonation())
+		users.DELETE("/impersonate", ic.StopImpersonation())
+	}
+}
+
+// StartImpersonation godoc
+//
+//	@Summary		Impersonate a user
+//	@Description	Admin only. Act as another user to debug user-specific issues: until stopped or max_duration passes, the session is treated as that user by every ownership check and admin privileges do not apply. Responses carry a banner and the X-Impersonated-By header, every request is written to the audit log, and operations requiring recent authentication are refused. Requires a recent login or re-authentication
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Param			request	body		dto.ImpersonateRequest	true	"User to impersonate and the reason"
+//	@Success		200		{object}	common.Response{data=dto.ImpersonationResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		403		{object}	common.Response
+//	@x-roles		["admin"]
+//	@Router			/api/v1/users/impersonate [post]
+func (ic *ImpersonationController) StartImpersonation() common.HandlerFunc {
+	return func(c common.Context) {
+		if !ic.cfg.Enabled {
+			c.AbortWithError(common.Error(
+				http.StatusForbidden,
+				code.ImpersonationDisabled,
+				code.Text(code.ImpersonationDisabled)).WithError(errors.New("impersonation is disabled")),
+			)
+			return
+		}
+
+		var req dto.ImpersonateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		admin, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+		if admin.UserName != common.ADMIN_NAME {
+			c.AbortWithError(common.Error(
+				http.StatusForbidden,
+				code.ImpersonationForbidden,
+				code.Text(code.ImpersonationForbidden)).WithError(errors.New(admin.UserName + " is not admin")),
+			)
+			return
+		}
+
+		target, err := ic.userService.GetUser(c, uint(req.UserID))
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ImpersonationError,
+				code.Text(code.ImpersonationError)).WithError(err),
+			)
+			return
+		}
+		if target.Username == common.ADMIN_NAME {
+			c.AbortWithError(common.Error(
+				http.StatusForbidden,
+				code.ImpersonationForbidden,
+				code.Text(code.ImpersonationForbidden)).WithError(errors.New("admin cannot be impersonated")),
+			)
+			return
+		}
+
+		userInfo, err := json.MarshalIndent(newSessionData(target), "", "  ")
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusInternalServerError,
+				code.MarshalError,
+				code.Text(code.MarshalError)).WithError(err),
+			)
+			return
+		}
+
+		now := time.Now()
+		imp := &impersonation.Session{
+			UserID:    target.ID,
+			Username:  target.Username,
+			UserInfo:  string(userInfo),
+			AdminID:   admin.UserId,
+			AdminName: admin.UserName,
+			Reason:    req.Reason,
+			StartedAt: now.Unix(),
+			ExpiresAt: now.Add(ic.cfg.Window()).Unix(),
+		}
+
+		session := c.GetSession()
+		if err := impersonation.Save(session, imp); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusInternalServerError,
+				code.ImpersonationError,
+				code.Text(code.ImpersonationError)).WithError(err),
+			)
+			return
+		}
+		session.Save()
+
+		logger.Module(c.Logger(), "audit").Info("impersonation started", imp.Fields()...)
+		// 本次响应就带上提示，后续请求由 SessionAuth 添加
+		c.SetHeader(impersonation.Header, imp.AdminName)
+		c.SetBanner(imp.Banner())
+		c.Payload(dto.NewImpersonationResponse(imp))
+	}
+}
+
+// StopImpersonation godoc
+//
+//	@Summary		Stop impersonating
+//	@Description	End the current impersonation, the session becomes the admin again
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Success		200	{object}	common.Response{data=dto.ImpersonationResponse}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@x-roles		["admin"]
+//	@Router			/api/v1/users/impersonate [delete]
+func (ic *ImpersonationController) StopImpersonation() common.HandlerFunc {
+	return func(c common.Context) {
+		session := c.GetSession()
+		imp, ok := impersonation.Get(session)
+		if !ok {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.NotImpersonating,
+				code.Text(code.NotImpersonating)).WithError(errors.New("session is not impersonating")),
+			)
+			return
+		}
+
+		impersonation.Clear(session)
+		session.Save()
+
+		logger.Module(c.Logger(), "audit").Info("impersonation stopped", imp.Fields()...)
+		// SessionAuth 按代入状态添加的提示在本次响应中去掉
+		c.SetHeader(impersonation.Header, "")
+		c.SetBanner("")
+		c.Payload(dto.NewImpersonationResponse(imp))
+	}
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/pkg/errors"
+)
+
+// 排行榜默认和最多返回的名次数
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+type LeaderboardController struct {
+	leaderboardService service.LeaderboardService
+}
+
+func NewLeaderboardController(leaderboardService service.LeaderboardService) *LeaderboardController {
+	return &LeaderboardController{
+		leaderboardService: leaderboardService,
+	}
+}
+
+// Name 模块名
+func (lc *LeaderboardController) Name() string {
+	return "leaderboards"
+}
+
+func (lc *LeaderboardController) RegisterRoutes(r router.Router) {
+	leaderboards := r.AuthGroup("/leaderboards", r.Interceptors().Quota("leaderboards"))
+	{
+		leaderboards.GET("/:name", lc.GetLeaderboard())
+	}
+}
+
+// abortLeaderboardError 排行榜不存在返回 404，其余错误使用 fallback 业务码
+func abortLeaderboardError(c common.Context, err error, fallback int) {
+	if errors.Is(err, service.ErrLeaderboardNotFound) {
+		c.AbortWithError(common.Error(
+			http.StatusNotFound,
+			code.LeaderboardNotFound,
+			code.Text(code.LeaderboardNotFound)).WithError(err),
+		)
+		return
+	}
+
+	c.AbortWithError(common.Error(
+		http.StatusBadRequest,
+		fallback,
+		code.Text(fallback)).WithError(err),
+	)
+}
+
+// GetLeaderboard godoc
+//
+//	@Summary		Get leaderboard
+//	@Description	Get the top users of a leaderboard and the session user's own rank. Available leaderboards: top_spenders (total order amount), most_active (order count)
+//	@Tags			leaderboards
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			name	path		string	true	"Leaderboard name"	Enums(top_spenders, most_active)
+//	@Param			limit	query		int		false	"Number of top users, at most 100"	default(10)
+//	@Success		200		{object}	common.Response{data=dto.LeaderboardResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Router			/api/v1/leaderboards/{name} [get]
+func (lc *LeaderboardController) GetLeaderboard() common.HandlerFunc {
+	return func(c common.Context) {
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLeaderboardLimit)))
+		if limit <= 0 || limit > maxLeaderboardLimit {
+			limit = defaultLeaderboardLimit
+		}
+
+		var res *dto.LeaderboardResponse
+		res, err = lc.leaderboardService.Get(c, c.Param("name"), orderActor(user), limit)
+		if err != nil {
+			abortLeaderboardError(c, err, code.LeaderboardGetError)
+			return
+		}
+		c.Payload(res)
+	}
+}
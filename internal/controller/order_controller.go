@@ -7,32 +7,150 @@ import (
 	"gin-app-start/internal/code"
 	"gin-app-start/internal/common"
 	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/repository"
+	"gin-app-start/internal/router"
 	"gin-app-start/internal/service"
 	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/cursor"
 	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/response"
+
+	"go.uber.org/zap"
 )
 
 type OrderController struct {
 	orderService service.OrderService
+	viewService  service.ViewCounterService
 }
 
-func NewOrderController(orderService service.OrderService) *OrderController {
+func NewOrderController(orderService service.OrderService, viewService service.ViewCounterService) *OrderController {
 	return &OrderController{
 		orderService: orderService,
+		viewService:  viewService,
+	}
+}
+
+// Name 模块名
+func (oc *OrderController) Name() string {
+	return "orders"
+}
+
+func (oc *OrderController) RegisterRoutes(r router.Router) {
+	orders := r.AuthGroup("/orders", r.Interceptors().Quota("orders"))
+	{
+		orders.POST("", oc.CreateOrder())
+		orders.GET("/search", oc.GetOrderByOrderNumber())
+		orders.POST("/batch_get", oc.BatchGetOrders())
+		orders.PUT("", oc.UpdateOrderByOrderNumber())
+		orders.PATCH("/:order_number", oc.PatchOrder())
+		orders.DELETE("", oc.DeleteOrderByOrderNumber())
+		orders.GET("", oc.ListOrders())
+		orders.GET("/stream", oc.StreamOrders())
+		orders.POST("/notes", oc.AddOrderNote())
+		orders.GET("/:order_number/items", oc.ListOrderItems())
+		orders.POST("/:order_number/items", oc.AddOrderItem())
+		orders.PUT("/:order_number/items/:item_id", oc.UpdateOrderItem())
+		orders.DELETE("/:order_number/items/:item_id", oc.RemoveOrderItem())
+	}
+}
+
+// orderActor 会话用户对应的操作者
+func orderActor(user userSession) service.Actor {
+	return service.Actor{UserID: user.UserId, Username: user.UserName}
+}
+
+// includeArchived 查询参数 include_archived 为 true 时同时查询归档的订单
+func includeArchived(c common.Context) bool {
+	include, _ := strconv.ParseBool(c.Query("include_archived"))
+	return include
+}
+
+// abortOrderError 订单不存在返回 404，无权操作返回 403，其余错误使用 fallback 业务码
+func abortOrderError(c common.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound):
+		c.AbortWithError(common.Error(
+			http.StatusNotFound,
+			code.OrderNotFound,
+			code.Text(code.OrderNotFound)).WithError(err),
+		)
+	case errors.Is(err, service.ErrOrderForbidden):
+		c.AbortWithError(common.Error(
+			http.StatusForbidden,
+			code.OrderForbidden,
+			code.Text(code.OrderForbidden)).WithError(err),
+		)
+	case errors.Is(err, service.ErrOrgForbidden):
+		c.AbortWithError(common.Error(
+			http.StatusForbidden,
+			code.OrgForbidden,
+			code.Text(code.OrgForbidden)).WithError(err),
+		)
+	case errors.Is(err, service.ErrOrderUserNotFound):
+		c.AbortWithError(common.Error(
+			http.StatusNotFound,
+			code.OrderUserNotFound,
+			code.Text(code.OrderUserNotFound)).WithError(err),
+		)
+	case errors.Is(err, service.ErrOrderItemNotFound):
+		c.AbortWithError(common.Error(
+			http.StatusNotFound,
+			code.OrderItemNotFound,
+			code.Text(code.OrderItemNotFound)).WithError(err),
+		)
+	case errors.Is(err, service.ErrProductNotFound):
+		c.AbortWithError(common.Error(
+			http.StatusNotFound,
+			code.ProductNotFound,
+			code.Text(code.ProductNotFound)).WithError(err),
+		)
+	case errors.Is(err, service.ErrProductOutOfStock):
+		c.AbortWithError(common.Error(
+			http.StatusConflict,
+			code.ProductOutOfStock,
+			code.Text(code.ProductOutOfStock)).WithError(err),
+		)
+	case errors.Is(err, service.ErrOrderTotalComputed):
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.OrderTotalComputed,
+			code.Text(code.OrderTotalComputed)).WithError(err),
+		)
+	case errors.Is(err, service.ErrOrderNumberConflict):
+		c.AbortWithError(common.Error(
+			http.StatusConflict,
+			code.OrderNumberError,
+			code.Text(code.OrderNumberError)).WithError(err),
+		)
+	default:
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			fallback,
+			code.Text(fallback)).WithError(err),
+		)
 	}
 }
 
 // CreateOrder godoc
 //
 //	@Summary		Create a new order
-//	@Description	Create a new order with user_id, total_price, description
+//	@Description	Create a new order for username with total_price, description. Users can only order for themselves, an admin ordering for another user makes that user the owner. When items are given, the order, its items and the stock decrement are written in one transaction and total_price is computed from the items
 //	@Tags			orders
 //	@Accept			json
 //	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Param			request	body		dto.CreateOrderRequest	true	"Order information"
-//	@Success		200		{object}	response.Response
-//	@Failure		400		{object}	response.Response
-//	@Failure		500		{object}	response.Response
+//	@Success		200		{object}	common.Response{data=dto.OrderResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		403		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Failure		409		{object}	common.Response
+//	@Failure		500		{object}	common.Response
+//	@x-roles		["owner","admin"]
 //	@Router			/api/v1/orders [post]
 func (oc *OrderController) CreateOrder() common.HandlerFunc {
 	return func(c common.Context) {
@@ -41,7 +159,7 @@ func (oc *OrderController) CreateOrder() common.HandlerFunc {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.ParamBindError,
-				validation.Error(err)).WithError(err),
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
@@ -66,35 +184,37 @@ func (oc *OrderController) CreateOrder() common.HandlerFunc {
 			return
 		}
 
-		req.UserId = user.UserId
-		order, err := oc.orderService.CreateOrder(c, &req)
+		order, err := oc.orderService.CreateOrder(c, orderActor(user), &req)
 		if err != nil {
-			c.AbortWithError(common.Error(
-				http.StatusBadRequest,
-				code.OrderCreateError,
-				code.Text(code.OrderCreateError)).WithError(err),
-			)
+			abortOrderError(c, err, code.OrderCreateError)
 			return
 		}
 
-		c.Payload(order)
+		c.Payload(dto.NewOrderResponse(order))
 	}
 }
 
 // GetOrderByOrderNumber godoc
 //
 //	@Summary		Get order by order_number
-//	@Description	Get order information by order_number
+//	@Description	Get order information by order_number. With include_archived=true, orders moved to the archive table are also searched
 //	@Tags			orders
 //	@Accept			json
 //	@Produce		json
-//	@Param			username	    path		string	true	"Username"
-//	@Param			order_number	path		string	true	"Order Number"
-//	@Success		200	{object}	response.Response
-//	@Failure		400	{object}	response.Response
-//	@Failure		404	{object}	response.Response
-//	@Failure		500	{object}	response.Response
-//	@Router			/api/v1/orders/search?order_number={order_number}&username={username} [get]
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			username			query		string	true	"Username"
+//	@Param			order_number		query		string	true	"Order Number"
+//	@Param			include_archived	query		bool	false	"Also search archived orders"	default(false)
+//	@Success		200					{object}	common.Response{data=dto.OrderResponse}
+//	@Failure		400					{object}	common.Response
+//	@Failure		401					{object}	common.Response
+//	@Failure		403					{object}	common.Response
+//	@Failure		404					{object}	common.Response
+//	@Failure		500					{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders/search [get]
 func (oc *OrderController) GetOrderByOrderNumber() common.HandlerFunc {
 	return func(c common.Context) {
 		orderNumber := c.Query("order_number")
@@ -120,17 +240,79 @@ func (oc *OrderController) GetOrderByOrderNumber() common.HandlerFunc {
 			return
 		}
 
-		order, err := oc.orderService.GetOrderByOrderNumber(c, orderNumber)
+		order, err := oc.orderService.GetOrderByOrderNumber(c, orderActor(user), orderNumber)
+		// 订单表中查不到时再查归档表
+		if errors.Is(err, service.ErrOrderNotFound) && includeArchived(c) {
+			order, err = oc.orderService.GetArchivedOrder(c, orderActor(user), orderNumber)
+		}
 		if err != nil {
+			abortOrderError(c, err, code.OrderGetError)
+			return
+		}
+
+		// 浏览计数失败不影响查询结果
+		if err := oc.viewService.Record(c, model.ViewSubjectOrder, order.OrderNumber); err != nil {
+			c.Logger().Warn("record order view failed", zap.String("order_number", order.OrderNumber), zap.Error(err))
+		}
+
+		c.Payload(dto.NewOrderResponse(order))
+	}
+}
+
+// BatchGetOrders godoc
+//
+//	@Summary		Get orders by order numbers
+//	@Description	Get up to 100 orders by order number in one request. Orders that do not exist or are not visible to the session user are listed in missing, the others are returned in request order
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			request	body		dto.BatchGetOrdersRequest	true	"Order numbers"
+//	@Success		200		{object}	common.Response{data=dto.BatchGetOrdersResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		500		{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders/batch_get [post]
+func (oc *OrderController) BatchGetOrders() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.BatchGetOrdersRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
-				code.OrderGetError,
-				code.Text(code.OrderGetError)).WithError(err),
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
 
-		c.Payload(order)
+		sessionData := c.SessionUserInfo()
+		user, err := getUserSession(sessionData)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		orders, missing, err := oc.orderService.BatchGetOrders(c, orderActor(user), req.OrderNumbers)
+		if err != nil {
+			abortOrderError(c, err, code.OrderGetError)
+			return
+		}
+
+		res := dto.BatchGetOrdersResponse{
+			Orders:  dto.NewOrderResponses(orders),
+			Missing: missing,
+		}
+		if res.Missing == nil {
+			res.Missing = []string{}
+		}
+		c.Payload(res)
 	}
 }
 
@@ -141,11 +323,17 @@ func (oc *OrderController) GetOrderByOrderNumber() common.HandlerFunc {
 //	@Tags			orders
 //	@Accept			json
 //	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Param			request	body		dto.UpdateOrderRequest	true	"Order information to update"
-//	@Success		200		{object}	response.Response
-//	@Failure		400		{object}	response.Response
-//	@Failure		404		{object}	response.Response
-//	@Failure		500		{object}	response.Response
+//	@Success		200		{object}	common.Response{data=dto.OrderResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		403		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Failure		500		{object}	common.Response
+//	@x-roles		["owner","admin"]
 //	@Router			/api/v1/orders [put]
 func (oc *OrderController) UpdateOrderByOrderNumber() common.HandlerFunc {
 	return func(c common.Context) {
@@ -154,7 +342,7 @@ func (oc *OrderController) UpdateOrderByOrderNumber() common.HandlerFunc {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.ParamBindError,
-				validation.Error(err)).WithError(err),
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
@@ -179,16 +367,70 @@ func (oc *OrderController) UpdateOrderByOrderNumber() common.HandlerFunc {
 			return
 		}
 
-		order, err := oc.orderService.UpdateOrderByOrderNumber(c, &req)
+		order, err := oc.orderService.UpdateOrderByOrderNumber(c, orderActor(user), &req)
 		if err != nil {
+			abortOrderError(c, err, code.OrderUpdateError)
+			return
+		}
+		c.Payload(dto.NewOrderResponse(order))
+	}
+}
+
+// PatchOrder godoc
+//
+//	@Summary		Partially update order
+//	@Description	Update only the fields present in the body, zero values such as total_price 0 or an empty description are applied. Accepts application/json and application/merge-patch+json, null fields are left unchanged
+//	@Tags			orders
+//	@Accept			json
+//	@Accept			application/merge-patch+json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			order_number	path		string					true	"Order number"
+//	@Param			request			body		dto.PatchOrderRequest	true	"Order fields to change"
+//	@Success		200				{object}	common.Response{data=dto.OrderResponse}
+//	@Failure		400				{object}	common.Response
+//	@Failure		401				{object}	common.Response
+//	@Failure		403				{object}	common.Response
+//	@Failure		404				{object}	common.Response
+//	@Failure		500				{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders/{order_number} [patch]
+func (oc *OrderController) PatchOrder() common.HandlerFunc {
+	return func(c common.Context) {
+		var uri dto.OrderItemsURI
+		if err := c.ShouldBindURI(&uri); err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
-				code.OrderUpdateError,
-				code.Text(code.OrderUpdateError)).WithError(err),
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
-		c.Payload(order)
+
+		var req dto.PatchOrderRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		// 是否有权修改由 service 按订单归属校验
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		order, err := oc.orderService.PatchOrder(c, actor, uri.OrderNumber, &req)
+		if err != nil {
+			abortOrderError(c, err, code.OrderUpdateError)
+			return
+		}
+		c.Payload(dto.NewOrderResponse(order))
 	}
 }
 
@@ -199,11 +441,17 @@ func (oc *OrderController) UpdateOrderByOrderNumber() common.HandlerFunc {
 //	@Tags			orders
 //	@Accept			json
 //	@Produce		json
-//	@Param			order_number	path		string	true	"Order Number"
-//	@Success		200	{object}	response.Response
-//	@Failure		400	{object}	response.Response
-//	@Failure		404	{object}	response.Response
-//	@Failure		500	{object}	response.Response
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			request	body		dto.DeleteOrderRequest	true	"Order to delete"
+//	@Success		200		{object}	common.Response{data=dto.DeleteOrderRequest}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		403		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Failure		500		{object}	common.Response
+//	@x-roles		["owner","admin"]
 //	@Router			/api/v1/orders [delete]
 func (oc *OrderController) DeleteOrderByOrderNumber() common.HandlerFunc {
 	return func(c common.Context) {
@@ -212,7 +460,7 @@ func (oc *OrderController) DeleteOrderByOrderNumber() common.HandlerFunc {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.ParamBindError,
-				validation.Error(err)).WithError(err),
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
@@ -237,71 +485,201 @@ func (oc *OrderController) DeleteOrderByOrderNumber() common.HandlerFunc {
 			return
 		}
 
-		order, err := oc.orderService.GetOrderByOrderNumber(c, req.OrderNumber)
+		err = oc.orderService.DeleteOrderByOrderNumber(c, orderActor(user), req.OrderNumber)
+		if err != nil {
+			abortOrderError(c, err, code.OrderDeleteError)
+			return
+		}
+
+		c.Payload(req)
+	}
+}
+
+// ListOrders godoc
+//
+//	@Summary		List orders
+//	@Description	Get paginated list of orders. With include_archived=true, archived orders are listed together with current ones, newest first.
+//	@Description	Admins can search orders by the email or phone of the ordering user (exact match, archived orders are not searched); each order in the result carries the user's contact info
+//	@Description	Orders can be filtered by status, creation time range and total price range, total counts the orders matching the filters. Sort by comma separated fields (id, created_at, total_price, status), prefix a field with - for descending order, newest first by default. Filters and sort cannot be combined with include_archived
+//	@Description	With the cursor parameter (empty for the first page), orders are listed by id in ascending order after the cursor and page is ignored; pass next_cursor of the response to get the next page, total is not counted. Cursor pagination cannot be combined with email, phone, include_archived or sort
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			username			query		string	false	"Username"
+//	@Param			email				query		string	false	"Email of the ordering user, admin only"
+//	@Param			phone				query		string	false	"Phone of the ordering user, admin only"
+//	@Param			page				query		int		false	"Page number"				default(1)
+//	@Param			page_size			query		int		false	"Page size"					default(10)
+//	@Param			include_archived	query		bool	false	"Include archived orders"	default(false)
+//	@Param			cursor				query		string	false	"Cursor for keyset pagination, empty for the first page"
+//	@Param			status				query		string	false	"Order status"	Enums(pending, paid, shipped, completed, cancelled)
+//	@Param			created_from		query		string	false	"Created at or after (RFC 3339)"	format(date-time)
+//	@Param			created_to			query		string	false	"Created before (RFC 3339)"			format(date-time)
+//	@Param			min_price			query		number	false	"Minimum total price"
+//	@Param			max_price			query		number	false	"Maximum total price"
+//	@Param			sort				query		string	false	"Sort fields, e.g. -total_price,created_at"
+//	@Success		200				{object}	common.Response{data=dto.ListOrdersResponse}
+//	@Failure		400				{object}	common.Response
+//	@Failure		401				{object}	common.Response
+//	@Failure		500				{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders [get]
+func (oc *OrderController) ListOrders() common.HandlerFunc {
+	return func(c common.Context) {
+		var res dto.ListOrdersResponse
+
+		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+		pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+		username := c.DefaultQuery("username", "")
+
+		sessionData := c.SessionUserInfo()
+		user, err := getUserSession(sessionData)
 		if err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
-				code.OrderGetError,
-				code.Text(code.OrderGetError)).WithError(err),
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
 			)
 			return
 		}
 
-		if order == nil {
+		if user.UserName != common.ADMIN_NAME && user.UserName != username {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
-				code.OrderGetError,
-				code.Text(code.OrderGetError)).WithError(errors.New("order not found")),
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(errors.New(user.UserName + " overstepping authority")),
 			)
 			return
 		}
 
-		if order.Username != req.Username {
+		filter, err := repository.OrderQuery.Parse(c.Request().URL.Query())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+		// 归档订单与当前订单合并分页，不支持过滤和排序
+		if !filter.IsZero() && includeArchived(c) {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
-				code.OrderDeleteError,
-				code.Text(code.OrderDeleteError)).WithError(errors.New("username and order number not match")),
+				code.ParamBindError,
+				code.Text(code.ParamBindError)).WithError(errors.New("filters and sort cannot be combined with include_archived")),
 			)
 			return
 		}
 
-		err = oc.orderService.DeleteOrderByOrderNumber(c, req.OrderNumber)
+		// 按下单用户的联系方式搜索只对管理员开放
+		email, phone := c.Query("email"), c.Query("phone")
+
+		// 游标分页按ID翻页，忽略 page，不统计总数；搜索、合并归档订单和自定义排序只支持页码分页
+		afterID, useCursor, err := cursorQuery(c)
+		if err == nil && useCursor && (email != "" || phone != "" || includeArchived(c) || len(filter.Sort) > 0) {
+			err = errors.New("cursor cannot be combined with email, phone, include_archived or sort")
+		}
 		if err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
-				code.OrderDeleteError,
-				code.Text(code.OrderDeleteError)).WithError(err),
+				code.ParamBindError,
+				code.Text(code.ParamBindError)).WithError(err),
 			)
 			return
 		}
+		if useCursor {
+			orders, more, err := oc.orderService.ListOrdersAfter(c, username, filter, afterID, pageSize)
+			if err != nil {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.OrderListError,
+					code.Text(code.OrderListError)).WithError(err),
+				)
+				return
+			}
 
-		c.Payload(req)
+			res.Orders = dto.NewOrderResponses(orders)
+			if more {
+				res.NextCursor = cursor.Encode(orders[len(orders)-1].ID)
+			}
+			c.Payload(res)
+			return
+		}
+		if email != "" || phone != "" {
+			if user.UserName != common.ADMIN_NAME {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.AuthorizationError,
+					code.Text(code.AuthorizationError)).WithError(errors.New(user.UserName + " is not allowed to search orders by user")),
+				)
+				return
+			}
+
+			search := repository.OrderSearch{Username: username, Email: email, Phone: phone, Filter: filter}
+			found, total, err := oc.orderService.SearchOrders(c, search, page, pageSize)
+			if err != nil {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.OrderListError,
+					code.Text(code.OrderListError)).WithError(err),
+				)
+				return
+			}
+			res.Orders = make([]*dto.OrderResponse, 0, len(found))
+			for _, order := range found {
+				res.Orders = append(res.Orders, dto.NewOrderResponseWithUser(&order.Order, order.UserEmail, order.UserPhone))
+			}
+			res.Total = total
+			c.Payload(res)
+			return
+		}
+
+		var orders []*model.Order
+		var total int64
+		if includeArchived(c) {
+			orders, total, err = oc.orderService.ListOrdersWithArchive(c, username, page, pageSize)
+		} else {
+			orders, total, err = oc.orderService.ListOrders(c, username, filter, page, pageSize)
+		}
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.OrderListError,
+				code.Text(code.OrderListError)).WithError(err),
+			)
+			return
+		}
+		res.Orders = dto.NewOrderResponses(orders)
+		res.Total = total
+		c.Payload(res)
 	}
 }
 
-// ListOrders godoc
+// StreamOrders godoc
 //
-//	@Summary		List orders
-//	@Description	Get paginated list of orders
+//	@Summary		Stream orders
+//	@Description	Export all orders of a user (all orders for admin) without pagination. Orders are read from the database in batches by id and each batch is flushed to the client as soon as it is read. The default format is NDJSON (one order per line), format=json streams a single JSON array. Errors after the first batch can only be logged, clients should treat a truncated stream as failed
 //	@Tags			orders
-//	@Accept			json
+//	@Produce		application/x-ndjson
 //	@Produce		json
-//	@Param          username    query       string     false    "Username"
-//	@Param			page		query		int	       false	"Page number"		default(1)
-//	@Param			page_size	query		int	       false	"Page size"			default(10)
-//	@Success		200			{object}	response.Response
-//	@Failure		500			{object}	response.Response
-//	@Router			/api/v1/orders [get]
-func (oc *OrderController) ListOrders() common.HandlerFunc {
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			username	query		string	false	"Username"
+//	@Param			format		query		string	false	"Output format"	Enums(ndjson, json)	default(ndjson)
+//	@Success		200			{array}		dto.OrderResponse
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders/stream [get]
+func (oc *OrderController) StreamOrders() common.HandlerFunc {
 	return func(c common.Context) {
-		var res dto.ListOrdersResponse
-
-		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-		pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-		username := c.DefaultQuery("username", "")
+		username := c.Query("username")
 
-		sessionData := c.SessionUserInfo()
-		user, err := getUserSession(sessionData)
+		user, err := getUserSession(c.SessionUserInfo())
 		if err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
@@ -320,17 +698,297 @@ func (oc *OrderController) ListOrders() common.HandlerFunc {
 			return
 		}
 
-		orders, total, err := oc.orderService.ListOrders(c, username, page, pageSize)
+		ginCtx := c.GetGinContext()
+		stream := response.NewStreamWriter(ginCtx.Writer, c.Query("format") == "json")
+		ginCtx.Header("Content-Type", stream.ContentType())
+		// 禁止反向代理缓冲，每批订单读出后立即推送给客户端
+		ginCtx.Header("X-Accel-Buffering", "no")
+
+		err = oc.orderService.EachOrder(c, username, func(orders []*model.Order) error {
+			for _, order := range orders {
+				if err := stream.Write(dto.NewOrderResponse(order)); err != nil {
+					return err
+				}
+			}
+			stream.Flush()
+			return nil
+		})
+		if err == nil {
+			err = stream.Close()
+		}
 		if err != nil {
+			// 已经开始输出时无法再返回错误响应，只能记录日志
+			if ginCtx.Writer.Written() {
+				c.Logger().Error("stream orders interrupted", zap.Int("written", stream.Count()), zap.Error(err))
+				return
+			}
+
+			ginCtx.Writer.Header().Del("Content-Type")
+			ginCtx.Writer.Header().Del("X-Accel-Buffering")
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.OrderListError,
 				code.Text(code.OrderListError)).WithError(err),
 			)
+		}
+	}
+}
+
+// AddOrderNote godoc
+//
+//	@Summary		Add order note
+//	@Description	Append a note to an order; internal notes can only be written and read by admins
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			request	body		dto.CreateOrderNoteRequest	true	"Order note"
+//	@Success		200		{object}	common.Response{data=dto.OrderNoteResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		403		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Failure		500		{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders/notes [post]
+func (oc *OrderController) AddOrderNote() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.CreateOrderNoteRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
 			return
 		}
-		res.Orders = orders
-		res.Total = total
-		c.Payload(res)
+
+		sessionData := c.SessionUserInfo()
+		user, err := getUserSession(sessionData)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		note, err := oc.orderService.AddOrderNote(c, orderActor(user), &req)
+		if err != nil {
+			abortOrderError(c, err, code.OrderNoteError)
+			return
+		}
+		c.Payload(dto.NewOrderNoteResponse(note))
+	}
+}
+
+// bindOrderItem 解析路径中的订单号和明细ID，失败时直接返回 400
+func bindOrderItem(c common.Context) (dto.OrderItemURI, bool) {
+	var uri dto.OrderItemURI
+	if err := c.ShouldBindURI(&uri); err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.ParamBindError,
+			validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+		)
+		return uri, false
+	}
+	return uri, true
+}
+
+// ListOrderItems godoc
+//
+//	@Summary		List order items
+//	@Description	List the line items of an order with the order total computed from them
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			order_number	path		string	true	"Order Number"
+//	@Success		200				{object}	common.Response{data=dto.OrderItemsResponse}
+//	@Failure		400				{object}	common.Response
+//	@Failure		401				{object}	common.Response
+//	@Failure		403				{object}	common.Response
+//	@Failure		404				{object}	common.Response
+//	@Failure		500				{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders/{order_number}/items [get]
+func (oc *OrderController) ListOrderItems() common.HandlerFunc {
+	return func(c common.Context) {
+		var uri dto.OrderItemsURI
+		if err := c.ShouldBindURI(&uri); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		order, items, err := oc.orderService.ListOrderItems(c, actor, uri.OrderNumber)
+		if err != nil {
+			abortOrderError(c, err, code.OrderItemError)
+			return
+		}
+		c.Payload(dto.NewOrderItemsResponse(order, items))
+	}
+}
+
+// AddOrderItem godoc
+//
+//	@Summary		Add order item
+//	@Description	Add a product to an order at its current price. Adding a product already in the order increases its quantity. The order total is recomputed from all items in the same transaction
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			order_number	path		string					true	"Order Number"
+//	@Param			request			body		dto.AddOrderItemRequest	true	"Order item"
+//	@Success		200				{object}	common.Response{data=dto.OrderItemsResponse}
+//	@Failure		400				{object}	common.Response
+//	@Failure		401				{object}	common.Response
+//	@Failure		403				{object}	common.Response
+//	@Failure		404				{object}	common.Response
+//	@Failure		500				{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders/{order_number}/items [post]
+func (oc *OrderController) AddOrderItem() common.HandlerFunc {
+	return func(c common.Context) {
+		var uri dto.OrderItemsURI
+		if err := c.ShouldBindURI(&uri); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		var req dto.AddOrderItemRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		order, items, err := oc.orderService.AddOrderItem(c, actor, uri.OrderNumber, &req)
+		if err != nil {
+			abortOrderError(c, err, code.OrderItemError)
+			return
+		}
+		c.Payload(dto.NewOrderItemsResponse(order, items))
+	}
+}
+
+// UpdateOrderItem godoc
+//
+//	@Summary		Update order item
+//	@Description	Change the quantity of an order item and recompute the order total
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			order_number	path		string						true	"Order Number"
+//	@Param			item_id			path		int							true	"Order item ID"
+//	@Param			request			body		dto.UpdateOrderItemRequest	true	"Quantity"
+//	@Success		200				{object}	common.Response{data=dto.OrderItemsResponse}
+//	@Failure		400				{object}	common.Response
+//	@Failure		401				{object}	common.Response
+//	@Failure		403				{object}	common.Response
+//	@Failure		404				{object}	common.Response
+//	@Failure		500				{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders/{order_number}/items/{item_id} [put]
+func (oc *OrderController) UpdateOrderItem() common.HandlerFunc {
+	return func(c common.Context) {
+		uri, ok := bindOrderItem(c)
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateOrderItemRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		order, items, err := oc.orderService.UpdateOrderItem(c, actor, uri.OrderNumber, uri.ItemID, &req)
+		if err != nil {
+			abortOrderError(c, err, code.OrderItemError)
+			return
+		}
+		c.Payload(dto.NewOrderItemsResponse(order, items))
+	}
+}
+
+// RemoveOrderItem godoc
+//
+//	@Summary		Remove order item
+//	@Description	Remove an item from an order and recompute the order total
+//	@Tags			orders
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			order_number	path		string	true	"Order Number"
+//	@Param			item_id			path		int		true	"Order item ID"
+//	@Success		200				{object}	common.Response{data=dto.OrderItemsResponse}
+//	@Failure		400				{object}	common.Response
+//	@Failure		401				{object}	common.Response
+//	@Failure		403				{object}	common.Response
+//	@Failure		404				{object}	common.Response
+//	@Failure		500				{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orders/{order_number}/items/{item_id} [delete]
+func (oc *OrderController) RemoveOrderItem() common.HandlerFunc {
+	return func(c common.Context) {
+		uri, ok := bindOrderItem(c)
+		if !ok {
+			return
+		}
+
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		order, items, err := oc.orderService.RemoveOrderItem(c, actor, uri.OrderNumber, uri.ItemID)
+		if err != nil {
+			abortOrderError(c, err, code.OrderItemError)
+			return
+		}
+		c.Payload(dto.NewOrderItemsResponse(order, items))
 	}
 }
@@ -0,0 +1,377 @@
+package controller
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/response"
+)
+
+type OrganizationController struct {
+	orgService service.OrganizationService
+}
+
+func NewOrganizationController(orgService service.OrganizationService) *OrganizationController {
+	return &OrganizationController{
+		orgService: orgService,
+	}
+}
+
+// Name 模块名
+func (oc *OrganizationController) Name() string {
+	return "orgs"
+}
+
+// RegisterRoutes 组织接口按用户配额计数
+func (oc *OrganizationController) RegisterRoutes(r router.Router) {
+	orgs := r.AuthGroup("/orgs", r.Interceptors().Quota("users"))
+	{
+		orgs.POST("", oc.CreateOrganization())
+		orgs.GET("", oc.ListOrganizations())
+		orgs.POST("/invitations/accept", oc.AcceptInvitation())
+		orgs.GET("/:id/members", oc.ListMembers())
+		orgs.DELETE("/:id/members/:user_id", oc.RemoveMember())
+		orgs.POST("/:id/invitations", oc.Invite())
+		orgs.GET("/:id/orders", oc.ListOrders())
+	}
+}
+
+// sessionActor 解析会话用户，失败时直接返回 400
+func sessionActor(c common.Context) (service.Actor, bool) {
+	user, err := getUserSession(c.SessionUserInfo())
+	if err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.AuthorizationError,
+			code.Text(code.AuthorizationError)).WithError(err),
+		)
+		return service.Actor{}, false
+	}
+	return orderActor(user), true
+}
+
+// abortOrgError 组织或成员不存在返回 404，无权操作返回 403，其余错误使用 fallback 业务码
+func abortOrgError(c common.Context, err error, fallback int) {
+	status, bizCode := http.StatusBadRequest, fallback
+	switch {
+	case errors.Is(err, service.ErrOrgNotFound):
+		status, bizCode = http.StatusNotFound, code.OrgNotFound
+	case errors.Is(err, service.ErrOrgForbidden):
+		status, bizCode = http.StatusForbidden, code.OrgForbidden
+	case errors.Is(err, service.ErrOrgMemberNotFound):
+		status, bizCode = http.StatusNotFound, code.OrgMemberNotFound
+	case errors.Is(err, service.ErrOrgLastOwner):
+		status, bizCode = http.StatusConflict, code.OrgLastOwner
+	case errors.Is(err, service.ErrInvitationInvalid):
+		bizCode = code.InvitationInvalid
+	}
+
+	c.AbortWithError(common.Error(
+		status,
+		bizCode,
+		code.Text(bizCode)).WithError(err),
+	)
+}
+
+// CreateOrganization godoc
+//
+//	@Summary		Create organization
+//	@Description	Create an organization, the session user becomes its owner
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			request	body		dto.CreateOrganizationRequest	true	"Organization"
+//	@Success		200		{object}	common.Response{data=dto.OrganizationResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/orgs [post]
+func (oc *OrganizationController) CreateOrganization() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.CreateOrganizationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		org, err := oc.orgService.CreateOrganization(c, actor, &req)
+		if err != nil {
+			abortOrgError(c, err, code.OrgCreateError)
+			return
+		}
+		c.Payload(dto.NewOrganizationResponse(org))
+	}
+}
+
+// ListOrganizations godoc
+//
+//	@Summary		List my organizations
+//	@Description	List organizations the session user belongs to
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	common.Response{data=[]dto.OrganizationResponse}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/orgs [get]
+func (oc *OrganizationController) ListOrganizations() common.HandlerFunc {
+	return func(c common.Context) {
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		orgs, err := oc.orgService.ListOrganizations(c, actor)
+		if err != nil {
+			abortOrgError(c, err, code.OrgListError)
+			return
+		}
+
+		res := make([]*dto.OrganizationResponse, 0, len(orgs))
+		for _, org := range orgs {
+			res = append(res, dto.NewOrganizationResponse(org))
+		}
+		c.Payload(res)
+	}
+}
+
+// ListMembers godoc
+//
+//	@Summary		List organization members
+//	@Description	List members of an organization, only members can see them
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			id	path		int	true	"Organization ID"
+//	@Success		200	{object}	common.Response{data=[]dto.OrganizationMemberResponse}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@Failure		403	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orgs/{id}/members [get]
+func (oc *OrganizationController) ListMembers() common.HandlerFunc {
+	return func(c common.Context) {
+		orgID, ok := idParam(c)
+		if !ok {
+			return
+		}
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		members, err := oc.orgService.ListMembers(c, actor, orgID)
+		if err != nil {
+			abortOrgError(c, err, code.OrgMemberError)
+			return
+		}
+
+		res := make([]*dto.OrganizationMemberResponse, 0, len(members))
+		for _, member := range members {
+			res = append(res, dto.NewOrganizationMemberResponse(member))
+		}
+		c.Payload(res)
+	}
+}
+
+// RemoveMember godoc
+//
+//	@Summary		Remove organization member
+//	@Description	Owners can remove any member, members can remove themselves to leave. The last owner cannot be removed
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			id		path		int	true	"Organization ID"
+//	@Param			user_id	path		string	true	"User ID, encoded when id_obfuscation is enabled"
+//	@Success		200		{object}	common.Response{data=string}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		403		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Failure		409		{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orgs/{id}/members/{user_id} [delete]
+func (oc *OrganizationController) RemoveMember() common.HandlerFunc {
+	return func(c common.Context) {
+		orgID, ok := idParam(c)
+		if !ok {
+			return
+		}
+		userID, ok := userIDParam(c, "user_id")
+		if !ok {
+			return
+		}
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		if err := oc.orgService.RemoveMember(c, actor, orgID, userID); err != nil {
+			abortOrgError(c, err, code.OrgMemberError)
+			return
+		}
+		c.Payload("Remove member successfully")
+	}
+}
+
+// Invite godoc
+//
+//	@Summary		Invite organization member
+//	@Description	Owners invite a user by email. The invitation token is mailed when mail is configured and is always returned to the inviter; it expires after 7 days
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			id		path		int							true	"Organization ID"
+//	@Param			request	body		dto.InviteMemberRequest	true	"Invitation"
+//	@Success		200		{object}	common.Response{data=dto.InvitationResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		403		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orgs/{id}/invitations [post]
+func (oc *OrganizationController) Invite() common.HandlerFunc {
+	return func(c common.Context) {
+		orgID, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		var req dto.InviteMemberRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		invitation, err := oc.orgService.Invite(c, actor, orgID, &req)
+		if err != nil {
+			abortOrgError(c, err, code.OrgInviteError)
+			return
+		}
+		c.Payload(dto.NewInvitationResponse(invitation))
+	}
+}
+
+// AcceptInvitation godoc
+//
+//	@Summary		Accept organization invitation
+//	@Description	Join an organization with an invitation token. The session user's email must match the invited email
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			request	body		dto.AcceptInvitationRequest	true	"Invitation token"
+//	@Success		200		{object}	common.Response{data=dto.OrganizationMemberResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/orgs/invitations/accept [post]
+func (oc *OrganizationController) AcceptInvitation() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.AcceptInvitationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		member, err := oc.orgService.AcceptInvitation(c, actor, req.Token)
+		if err != nil {
+			abortOrgError(c, err, code.OrgMemberError)
+			return
+		}
+		c.Payload(dto.NewOrganizationMemberResponse(member))
+	}
+}
+
+// ListOrders godoc
+//
+//	@Summary		List organization orders
+//	@Description	List orders placed on behalf of an organization, newest first. Any member can see them
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			id			path		int	true	"Organization ID"
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(10)
+//	@Success		200			{object}	common.Response{data=response.Paged{list=[]dto.OrderResponse}}
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@Failure		403			{object}	common.Response
+//	@Failure		404			{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/orgs/{id}/orders [get]
+func (oc *OrganizationController) ListOrders() common.HandlerFunc {
+	return func(c common.Context) {
+		orgID, ok := idParam(c)
+		if !ok {
+			return
+		}
+		actor, ok := sessionActor(c)
+		if !ok {
+			return
+		}
+
+		page, pageSize := pageQuery(c)
+		orders, total, err := oc.orgService.ListOrders(c, actor, orgID, page, pageSize)
+		if err != nil {
+			abortOrgError(c, err, code.OrderListError)
+			return
+		}
+		c.Payload(response.NewPaged(dto.NewOrderResponses(orders), total, page, pageSize))
+	}
+}
@@ -0,0 +1,301 @@
+package controller
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"gin-app-start/internal/activity"
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/impersonation"
+	"gin-app-start/internal/lockout"
+	"gin-app-start/internal/repository"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/web"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/queryfilter"
+
+	"go.uber.org/zap"
+)
+
+// pagesPath 管理页面的路径前缀，与模板中的链接一致
+const pagesPath = "/ui"
+
+// pageView 页面数据，布局使用 Title、Admin、Error，其余字段由各页面使用
+type pageView struct {
+	Title    string
+	Admin    string // 已登录的管理员，登录页为空
+	Error    string
+	Username string // 登录页回填的用户名，订单页的用户名过滤条件
+	Users    []*dto.UserResponse
+	Orders   []*dto.OrderResponse
+	Pager    *pager
+}
+
+// pager 列表分页，PrevURL、NextURL 为空表示没有上一页、下一页
+type pager struct {
+	Page    int
+	Pages   int
+	Total   int64
+	PrevURL string
+	NextURL string
+}
+
+// newPager 保留 query 中的过滤条件生成翻页链接
+func newPager(path string, query url.Values, page, pageSize int, total int64) *pager {
+	p := &pager{
+		Page:  page,
+		Pages: int(math.Ceil(float64(total) / float64(pageSize))),
+		Total: total,
+	}
+	if p.Pages == 0 {
+		p.Pages = 1
+	}
+
+	link := func(page int) string {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("page", strconv.Itoa(page))
+		return path + "?" + q.Encode()
+	}
+	if page > 1 {
+		p.PrevURL = link(page - 1)
+	}
+	if page < p.Pages {
+		p.NextURL = link(page + 1)
+	}
+	return p
+}
+
+// PageController 服务端渲染的管理页面，与接口使用相同的 service 和会话，只有管理员可以登录
+type PageController struct {
+	userService    service.UserService
+	orderService   service.OrderService
+	sessionTracker *activity.Tracker // 未启用会话超时时为 nil
+	renderer       *web.Renderer
+	pageSize       int
+}
+
+func NewPageController(userService service.UserService, orderService service.OrderService, sessionTracker *activity.Tracker, renderer *web.Renderer, pageSize int) *PageController {
+	return &PageController{
+		userService:    userService,
+		orderService:   orderService,
+		sessionTracker: sessionTracker,
+		renderer:       renderer,
+		pageSize:       pageSize,
+	}
+}
+
+// Name 模块名
+func (pc *PageController) Name() string {
+	return "pages"
+}
+
+// RegisterRoutes 页面注册在根路径的 /ui 下，不经过 /api/v1 的路由组配置
+func (pc *PageController) RegisterRoutes(r router.Router) {
+	ui := r.Root().Group(pagesPath)
+	{
+		ui.GET("/static/*filepath", pc.Static())
+		ui.GET("/login", pc.LoginPage())
+		ui.POST("/login", pc.Login())
+		ui.POST("/logout", pc.Logout())
+	}
+
+	pages := r.Root().Group(pagesPath, pc.PageAuth())
+	{
+		pages.GET("", pc.Home())
+		pages.GET("/users", pc.Users())
+		pages.GET("/orders", pc.Orders())
+	}
+}
+
+// render 以 status 渲染页面 name
+func (pc *PageController) render(c common.Context, status int, name string, view *pageView) {
+	c.GetGinContext().Render(status, pc.renderer.Instance(name, view))
+}
+
+// redirect 跳转到 /ui 下的 path 并结束请求
+func redirect(c common.Context, path string) {
+	c.GetGinContext().Redirect(http.StatusSeeOther, pagesPath+path)
+	c.GetGinContext().Abort()
+}
+
+// PageAuth 页面要求管理员已登录，未登录或会话超时时跳转到登录页；代入用户期间不能访问
+func (pc *PageController) PageAuth() common.HandlerFunc {
+	return func(c common.Context) {
+		session := c.GetSession()
+		sessionData := session.Get(common.SESSION_KEY)
+		user, err := getUserSession(sessionData)
+		if err != nil || user.UserName != common.ADMIN_NAME {
+			redirect(c, "/login")
+			return
+		}
+
+		if token, ok := session.Get(common.SESSION_ACTIVITY_KEY).(string); ok && pc.sessionTracker != nil {
+			err := pc.sessionTracker.Touch(c.RequestContext(), token)
+			if errors.Is(err, activity.ErrIdleTimeout) || errors.Is(err, activity.ErrAbsoluteTimeout) {
+				endSession(c, pc.sessionTracker)
+				redirect(c, "/login")
+				return
+			}
+			if err != nil {
+				c.Logger().Warn("session activity touch failed, request allowed", zap.Error(err))
+			}
+		}
+
+		if _, ok := impersonation.Get(session); ok {
+			pc.render(c, http.StatusForbidden, "error", &pageView{
+				Title: "Forbidden",
+				Admin: user.UserName,
+				Error: code.Text(code.ImpersonationRestricted),
+			})
+			c.GetGinContext().Abort()
+			return
+		}
+
+		c.SetSessionUserInfo(sessionData)
+		c.AddLoggerFields(
+			zap.Uint("user_id", user.UserId),
+			zap.String("username", user.UserName),
+		)
+	}
+}
+
+// Static 嵌入的样式表等静态资源
+func (pc *PageController) Static() common.HandlerFunc {
+	return func(c common.Context) {
+		c.GetGinContext().FileFromFS(c.Param("filepath"), web.Static())
+	}
+}
+
+// LoginPage 登录页，管理员已登录时直接进入用户列表
+func (pc *PageController) LoginPage() common.HandlerFunc {
+	return func(c common.Context) {
+		if user, err := getUserSession(c.GetSession().Get(common.SESSION_KEY)); err == nil && user.UserName == common.ADMIN_NAME {
+			redirect(c, "/users")
+			return
+		}
+		pc.render(c, http.StatusOK, "login", &pageView{Title: "Sign in"})
+	}
+}
+
+// Login 提交登录表单，与 POST /api/v1/users/login 使用相同的登录失败锁定和会话
+func (pc *PageController) Login() common.HandlerFunc {
+	return func(c common.Context) {
+		req := dto.LoginRequest{
+			Username: c.PostForm("username"),
+			Password: c.PostForm("password"),
+		}
+		view := &pageView{Title: "Sign in", Username: req.Username}
+
+		u, err := pc.userService.Login(c, &req)
+		if err != nil {
+			c.Logger().Warn("page login failed", zap.String("username", req.Username), zap.Error(err))
+
+			var locked *lockout.LockedError
+			if errors.As(err, &locked) {
+				c.SetHeader("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+				view.Error = code.Text(code.LoginLocked)
+				pc.render(c, http.StatusTooManyRequests, "login", view)
+				return
+			}
+			view.Error = code.Text(code.AdminLoginError)
+			pc.render(c, http.StatusBadRequest, "login", view)
+			return
+		}
+
+		if u.Username != common.ADMIN_NAME {
+			view.Error = code.Text(code.PageAdminOnly)
+			pc.render(c, http.StatusForbidden, "login", view)
+			return
+		}
+
+		if err := startSession(c, pc.sessionTracker, newSessionData(u)); err != nil {
+			c.Logger().Error("page login session failed", zap.Error(err))
+			view.Error = code.Text(code.MarshalError)
+			pc.render(c, http.StatusInternalServerError, "login", view)
+			return
+		}
+		redirect(c, "/users")
+	}
+}
+
+// Logout 退出登录后回到登录页
+func (pc *PageController) Logout() common.HandlerFunc {
+	return func(c common.Context) {
+		endSession(c, pc.sessionTracker)
+		redirect(c, "/login")
+	}
+}
+
+// Home 首页为用户列表
+func (pc *PageController) Home() common.HandlerFunc {
+	return func(c common.Context) {
+		redirect(c, "/users")
+	}
+}
+
+// Users 用户列表
+func (pc *PageController) Users() common.HandlerFunc {
+	return func(c common.Context) {
+		user, _ := getUserSession(c.SessionUserInfo())
+		view := &pageView{Title: "Users", Admin: user.UserName}
+
+		page := pageParam(c)
+		users, total, err := pc.userService.ListUsers(c, queryfilter.Query{}, page, pc.pageSize)
+		if err != nil {
+			c.Logger().Error("page list users failed", zap.Error(err))
+			view.Error = code.Text(code.AdminListError)
+			pc.render(c, http.StatusInternalServerError, "users", view)
+			return
+		}
+
+		view.Users = dto.NewUserResponses(users, viewerOf(user))
+		view.Pager = newPager(pagesPath+"/users", nil, page, pc.pageSize, total)
+		pc.render(c, http.StatusOK, "users", view)
+	}
+}
+
+// Orders 全部用户的订单，可以按用户名过滤
+func (pc *PageController) Orders() common.HandlerFunc {
+	return func(c common.Context) {
+		user, _ := getUserSession(c.SessionUserInfo())
+		username := c.Query("username")
+		view := &pageView{Title: "Orders", Admin: user.UserName, Username: username}
+
+		page := pageParam(c)
+		found, total, err := pc.orderService.SearchOrders(c, repository.OrderSearch{Username: username}, page, pc.pageSize)
+		if err != nil {
+			c.Logger().Error("page list orders failed", zap.Error(err))
+			view.Error = code.Text(code.OrderListError)
+			pc.render(c, http.StatusInternalServerError, "orders", view)
+			return
+		}
+
+		view.Orders = make([]*dto.OrderResponse, 0, len(found))
+		for _, order := range found {
+			view.Orders = append(view.Orders, dto.NewOrderResponseWithUser(&order.Order, order.UserEmail, order.UserPhone))
+		}
+		var query url.Values
+		if username != "" {
+			query = url.Values{"username": {username}}
+		}
+		view.Pager = newPager(pagesPath+"/orders", query, page, pc.pageSize, total)
+		pc.render(c, http.StatusOK, "orders", view)
+	}
+}
+
+// pageParam 查询参数 page，缺省或不合法时为第 1 页
+func pageParam(c common.Context) int {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
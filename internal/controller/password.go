@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/service"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/password"
+)
+
+// passwordRuleCodes 密码策略规则与错误码的映射
+var passwordRuleCodes = map[password.Rule]int{
+	password.RuleMinLength:  code.PasswordTooShort,
+	password.RuleUpper:      code.PasswordNeedUpper,
+	password.RuleLower:      code.PasswordNeedLower,
+	password.RuleDigit:      code.PasswordNeedDigit,
+	password.RuleSymbol:     code.PasswordNeedSymbol,
+	password.RuleDictionary: code.PasswordTooCommon,
+	password.RuleBreached:   code.PasswordBreached,
+}
+
+// abortPasswordPolicy 密码不满足策略时返回 400，错误码取第一条未满足的规则，提示信息列出所有规则
+// 返回 false 表示不是密码策略错误，由调用方继续处理
+func abortPasswordPolicy(c common.Context, err error) bool {
+	var policyErr *service.PasswordPolicyError
+	if !errors.As(err, &policyErr) || len(policyErr.Rules) == 0 {
+		return false
+	}
+
+	messages := make([]string, 0, len(policyErr.Rules))
+	for _, rule := range policyErr.Rules {
+		ruleCode, ok := passwordRuleCodes[rule]
+		if !ok {
+			ruleCode = code.PasswordPolicyError
+		}
+		text := code.Text(ruleCode)
+		if rule == password.RuleMinLength {
+			text = fmt.Sprintf(text, policyErr.MinLength)
+		}
+		messages = append(messages, text)
+	}
+
+	bizCode, ok := passwordRuleCodes[policyErr.Rules[0]]
+	if !ok {
+		bizCode = code.PasswordPolicyError
+	}
+	c.AbortWithError(common.Error(
+		http.StatusBadRequest,
+		bizCode,
+		strings.Join(messages, ";")).WithError(err),
+	)
+	return true
+}
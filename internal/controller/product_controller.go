@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/httpcache"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/response"
+)
+
+type ProductController struct {
+	productService service.ProductService
+	viewService    service.ViewCounterService
+}
+
+func NewProductController(productService service.ProductService, viewService service.ViewCounterService) *ProductController {
+	return &ProductController{
+		productService: productService,
+		viewService:    viewService,
+	}
+}
+
+// Name 模块名
+func (pc *ProductController) Name() string {
+	return "products"
+}
+
+// RegisterRoutes 商品查询和浏览计数不需要登录，商品的增删改在管理端口上
+func (pc *ProductController) RegisterRoutes(r router.Router) {
+	products := r.Group("/products")
+	{
+		products.GET("", pc.ListProducts())
+		products.GET("/:product_id", pc.GetProduct())
+		products.POST("/:product_id/views", pc.RecordView())
+		products.GET("/:product_id/views", r.Interceptors().ResponseCache(httpcache.ProductViews), pc.GetViews())
+	}
+}
+
+// bindProduct 解析路径中的商品ID，失败时直接返回 400
+func bindProduct(c common.Context) (string, bool) {
+	var req dto.ProductRequest
+	if err := c.ShouldBindURI(&req); err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.ParamBindError,
+			validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+		)
+		return "", false
+	}
+	return req.ProductID, true
+}
+
+// abortProductError 将商品相关的业务错误转换为响应，其它错误返回 400 和 fallback
+func abortProductError(c common.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		c.AbortWithError(common.Error(
+			http.StatusNotFound,
+			code.ProductNotFound,
+			code.Text(code.ProductNotFound)).WithError(err),
+		)
+	case errors.Is(err, service.ErrProductExists):
+		c.AbortWithError(common.Error(
+			http.StatusConflict,
+			code.ProductExists,
+			code.Text(code.ProductExists)).WithError(err),
+		)
+	default:
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			fallback,
+			code.Text(fallback)).WithError(err),
+		)
+	}
+}
+
+// ListProducts godoc
+//
+//	@Summary		List products
+//	@Description	List products in the catalog by ID
+//	@Tags			products
+//	@Accept			json
+//	@Produce		json
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(10)
+//	@Success		200			{object}	common.Response{data=[]dto.ProductResponse}
+//	@Failure		400			{object}	common.Response
+//	@Router			/api/v1/products [get]
+func (pc *ProductController) ListProducts() common.HandlerFunc {
+	return func(c common.Context) {
+		var query dto.ProductListQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		products, total, err := pc.productService.ListProducts(c, &query)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ProductListError,
+				code.Text(code.ProductListError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(response.NewPaged(dto.NewProductResponses(products), total, query.Page, query.PageSize))
+	}
+}
+
+// GetProduct godoc
+//
+//	@Summary		Get product
+//	@Description	Get a product by its SKU
+//	@Tags			products
+//	@Accept			json
+//	@Produce		json
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Success		200			{object}	common.Response{data=dto.ProductResponse}
+//	@Failure		400			{object}	common.Response
+//	@Failure		404			{object}	common.Response
+//	@Router			/api/v1/products/{product_id} [get]
+func (pc *ProductController) GetProduct() common.HandlerFunc {
+	return func(c common.Context) {
+		productID, ok := bindProduct(c)
+		if !ok {
+			return
+		}
+
+		product, err := pc.productService.GetProduct(c, productID)
+		if err != nil {
+			abortProductError(c, err, code.ProductNotFound)
+			return
+		}
+
+		c.Payload(dto.NewProductResponse(product))
+	}
+}
+
+// RecordView godoc
+//
+//	@Summary		Record product view
+//	@Description	Count one view of a product. Views are buffered in Redis and written to the database in batches, so they show up in the view count right away but may be lost if views.loss_tolerant is enabled
+//	@Tags			products
+//	@Accept			json
+//	@Produce		json
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Success		200			{object}	common.Response{data=string}
+//	@Failure		400			{object}	common.Response
+//	@Router			/api/v1/products/{product_id}/views [post]
+func (pc *ProductController) RecordView() common.HandlerFunc {
+	return func(c common.Context) {
+		productID, ok := bindProduct(c)
+		if !ok {
+			return
+		}
+
+		if err := pc.viewService.Record(c, model.ViewSubjectProduct, productID); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ViewRecordError,
+				code.Text(code.ViewRecordError)).WithError(err),
+			)
+			return
+		}
+		c.Payload("View recorded")
+	}
+}
+
+// GetViews godoc
+//
+//	@Summary		Product view count
+//	@Description	Get the total number of views of a product, including views not yet written to the database
+//	@Tags			products
+//	@Accept			json
+//	@Produce		json
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Success		200			{object}	common.Response{data=dto.ViewCountResponse}
+//	@Failure		400			{object}	common.Response
+//	@Router			/api/v1/products/{product_id}/views [get]
+func (pc *ProductController) GetViews() common.HandlerFunc {
+	return func(c common.Context) {
+		productID, ok := bindProduct(c)
+		if !ok {
+			return
+		}
+
+		views, err := pc.viewService.Count(c, model.ViewSubjectProduct, productID)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ViewCountError,
+				code.Text(code.ViewCountError)).WithError(err),
+			)
+			return
+		}
+		c.Payload(&dto.ViewCountResponse{
+			Subject:   model.ViewSubjectProduct,
+			SubjectID: productID,
+			Views:     views,
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/errors"
+)
+
+type SettingsController struct {
+	settingsService service.SettingsService
+}
+
+func NewSettingsController(settingsService service.SettingsService) *SettingsController {
+	return &SettingsController{
+		settingsService: settingsService,
+	}
+}
+
+// Name 模块名
+func (sc *SettingsController) Name() string {
+	return "settings"
+}
+
+// RegisterRoutes 设置接口挂在 /users/me 下，与用户接口共用认证和配额
+func (sc *SettingsController) RegisterRoutes(r router.Router) {
+	users := r.AuthGroup("/users", r.Interceptors().Quota("users"))
+	{
+		users.GET("/me/settings", sc.GetSettings())
+		users.PATCH("/me/settings", sc.UpdateSettings())
+	}
+}
+
+// GetSettings godoc
+//
+//	@Summary		Get settings
+//	@Description	Get the session user's settings (locale, theme, notifications.email, notifications.sms, notifications.broadcast), settings never changed are returned with their defaults
+//	@Tags			settings
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	common.Response{data=object}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/me/settings [get]
+func (sc *SettingsController) GetSettings() common.HandlerFunc {
+	return func(c common.Context) {
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		settings, err := sc.settingsService.Get(c, user.UserId)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.SettingsGetError,
+				code.Text(code.SettingsGetError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(settings)
+	}
+}
+
+// UpdateSettings godoc
+//
+//	@Summary		Update settings
+//	@Description	Update the settings present in the body and leave the others unchanged, a null value resets the setting to its default. Unknown settings, wrong types and values outside the allowed ones are rejected with per-setting details
+//	@Tags			settings
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			request	body		object	true	"Settings to change, e.g. {\"theme\":\"dark\",\"notifications.sms\":true}"
+//	@Success		200		{object}	common.Response{data=object}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/me/settings [patch]
+func (sc *SettingsController) UpdateSettings() common.HandlerFunc {
+	return func(c common.Context) {
+		var patch map[string]any
+		if err := c.ShouldBindJSON(&patch); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		settings, err := sc.settingsService.Update(c, user.UserId, patch)
+		if err != nil {
+			var invalid *service.SettingsError
+			if errors.As(err, &invalid) {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.ParamBindError,
+					invalid.Error()).WithError(err).WithDetails(invalid.Details),
+				)
+				return
+			}
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.SettingsUpdateError,
+				code.Text(code.SettingsUpdateError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(settings)
+	}
+}
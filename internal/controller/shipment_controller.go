@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/errors"
+)
+
+// webhookSignatureHeader 承运商回调的签名头，值为 hex(HMAC-SHA256(secret, body))
+const webhookSignatureHeader = "X-Signature"
+
+type ShipmentController struct {
+	shipmentService service.ShipmentService
+}
+
+func NewShipmentController(shipmentService service.ShipmentService) *ShipmentController {
+	return &ShipmentController{
+		shipmentService: shipmentService,
+	}
+}
+
+// Name 模块名
+func (sc *ShipmentController) Name() string {
+	return "shipments"
+}
+
+func (sc *ShipmentController) RegisterRoutes(r router.Router) {
+	// 承运商回调不走会话认证，由 Webhook 校验请求签名
+	r.POST("/shipments/webhook/:carrier", sc.Webhook())
+
+	shipments := r.AuthGroup("/shipments", r.Interceptors().Quota("orders"))
+	{
+		shipments.GET("", sc.ListOrderShipments())
+		shipments.GET("/:id", sc.GetShipment())
+	}
+}
+
+// abortShipmentError 包裹不存在返回 404，运单号重复返回 409，订单相关错误同 abortOrderError，其余错误使用 fallback 业务码
+func abortShipmentError(c common.Context, err error, fallback int) {
+	switch {
+	case errors.Is(err, service.ErrShipmentNotFound):
+		c.AbortWithError(common.Error(
+			http.StatusNotFound,
+			code.ShipmentNotFound,
+			code.Text(code.ShipmentNotFound)).WithError(err),
+		)
+	case errors.Is(err, service.ErrShipmentExists):
+		c.AbortWithError(common.Error(
+			http.StatusConflict,
+			code.ShipmentExists,
+			code.Text(code.ShipmentExists)).WithError(err),
+		)
+	default:
+		abortOrderError(c, err, fallback)
+	}
+}
+
+// ListOrderShipments godoc
+//
+//	@Summary		List order shipments
+//	@Description	List the shipments of an order with their tracking events
+//	@Tags			shipments
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			order_number	query		string	true	"Order Number"
+//	@Success		200				{object}	common.Response{data=[]dto.ShipmentResponse}
+//	@Failure		400				{object}	common.Response
+//	@Failure		401				{object}	common.Response
+//	@Failure		403				{object}	common.Response
+//	@Failure		404				{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/shipments [get]
+func (sc *ShipmentController) ListOrderShipments() common.HandlerFunc {
+	return func(c common.Context) {
+		orderNumber := c.Query("order_number")
+		if orderNumber == "" {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				code.Text(code.ParamBindError)).WithError(errors.New("order_number required")),
+			)
+			return
+		}
+
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		shipments, err := sc.shipmentService.ListOrderShipments(c, orderActor(user), orderNumber)
+		if err != nil {
+			abortShipmentError(c, err, code.ShipmentGetError)
+			return
+		}
+		c.Payload(dto.NewShipmentResponses(shipments))
+	}
+}
+
+// GetShipment godoc
+//
+//	@Summary		Track shipment
+//	@Description	Get a shipment and its tracking events
+//	@Tags			shipments
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			id	path		int	true	"Shipment ID"
+//	@Success		200	{object}	common.Response{data=dto.ShipmentResponse}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@Failure		403	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/shipments/{id} [get]
+func (sc *ShipmentController) GetShipment() common.HandlerFunc {
+	return func(c common.Context) {
+		id, ok := idParam(c)
+		if !ok {
+			return
+		}
+
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		shipment, err := sc.shipmentService.GetShipment(c, orderActor(user), id)
+		if err != nil {
+			abortShipmentError(c, err, code.ShipmentGetError)
+			return
+		}
+		c.Payload(dto.NewShipmentResponse(shipment))
+	}
+}
+
+// Webhook godoc
+//
+//	@Summary		Carrier webhook
+//	@Description	Receive tracking events pushed by a carrier. The body must be signed with the carrier's webhook secret: X-Signature = hex(HMAC-SHA256(secret, body)). Duplicate events are ignored
+//	@Tags			shipments
+//	@Accept			json
+//	@Produce		json
+//	@Param			carrier		path		string						true	"Carrier code"
+//	@Param			X-Signature	header		string						true	"Body signature"
+//	@Param			request		body		dto.ShipmentWebhookRequest	true	"Tracking events"
+//	@Success		200			{object}	common.Response{data=dto.ShipmentWebhookResponse}
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@Failure		404			{object}	common.Response
+//	@Router			/api/v1/shipments/webhook/{carrier} [post]
+func (sc *ShipmentController) Webhook() common.HandlerFunc {
+	return func(c common.Context) {
+		carrierName := c.Param("carrier")
+		if !sc.shipmentService.VerifyWebhook(carrierName, c.RawData(), c.GetHeader(webhookSignatureHeader)) {
+			c.AbortWithError(common.Error(
+				http.StatusUnauthorized,
+				code.WebhookSignatureInvalid,
+				code.Text(code.WebhookSignatureInvalid)).WithError(errors.New("invalid signature for carrier " + carrierName)),
+			)
+			return
+		}
+
+		var req dto.ShipmentWebhookRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		accepted, err := sc.shipmentService.HandleWebhook(c, carrierName, &req)
+		if err != nil {
+			abortShipmentError(c, err, code.ShipmentEventError)
+			return
+		}
+		c.Payload(dto.ShipmentWebhookResponse{Accepted: accepted})
+	}
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/httpcache"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/validation"
+)
+
+type StoreController struct {
+	geoService service.GeoService
+}
+
+func NewStoreController(geoService service.GeoService) *StoreController {
+	return &StoreController{
+		geoService: geoService,
+	}
+}
+
+// Name 模块名
+func (sc *StoreController) Name() string {
+	return "stores"
+}
+
+// RegisterRoutes 门店查询不需要登录
+func (sc *StoreController) RegisterRoutes(r router.Router) {
+	stores := r.Group("/stores")
+	{
+		stores.GET("/nearby", r.Interceptors().ResponseCache(httpcache.Stores), sc.NearbyStores())
+	}
+}
+
+// bindNearbyQuery 解析附近查询条件，失败时直接返回 400
+func bindNearbyQuery(c common.Context) (*dto.NearbyQuery, bool) {
+	var query dto.NearbyQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.ParamBindError,
+			validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+		)
+		return nil, false
+	}
+	return &query, true
+}
+
+// NearbyStores godoc
+//
+//	@Summary		Nearby stores
+//	@Description	Find stores within a radius of a location, nearest first
+//	@Tags			stores
+//	@Accept			json
+//	@Produce		json
+//	@Param			latitude	query		number	true	"Latitude"
+//	@Param			longitude	query		number	true	"Longitude"
+//	@Param			radius		query		number	false	"Radius in kilometers, at most 50"	default(5)
+//	@Param			limit		query		int		false	"Max number of stores, at most 100"	default(20)
+//	@Success		200			{object}	common.Response{data=[]dto.NearbyStoreResponse}
+//	@Failure		400			{object}	common.Response
+//	@Router			/api/v1/stores/nearby [get]
+func (sc *StoreController) NearbyStores() common.HandlerFunc {
+	return func(c common.Context) {
+		query, ok := bindNearbyQuery(c)
+		if !ok {
+			return
+		}
+
+		stores, err := sc.geoService.NearbyStores(c, query)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.NearbySearchError,
+				code.Text(code.NearbySearchError)).WithError(err),
+			)
+			return
+		}
+		c.Payload(stores)
+	}
+}
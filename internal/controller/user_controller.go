@@ -2,20 +2,36 @@ package controller
 
 import (
 	"encoding/json"
+	"math"
+	"mime/multipart"
 	"net/http"
 	"path"
 	"strconv"
+	"time"
 
+	"gin-app-start/internal/activity"
 	"gin-app-start/internal/code"
 	"gin-app-start/internal/common"
 	"gin-app-start/internal/config"
 	"gin-app-start/internal/dto"
+	"gin-app-start/internal/filelink"
+	"gin-app-start/internal/filescan"
+	"gin-app-start/internal/lockout"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/refreshtoken"
+	"gin-app-start/internal/repository"
+	"gin-app-start/internal/router"
 	"gin-app-start/internal/service"
 	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/cursor"
 	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/jwt"
+	"gin-app-start/pkg/response"
 	"gin-app-start/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap"
 )
 
 type userSession struct {
@@ -32,35 +48,212 @@ func getUserSession(sessionData interface{}) (userSession, error) {
 	}
 
 	// 从session中获取用户信息
+	// JSON 序列化的会话中为字符串，gob 序列化的旧会话中为 []byte
+	var data []byte
+	switch v := sessionData.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return userSession{}, errors.Errorf("unexpected session data type %T", sessionData)
+	}
+
 	var user userSession
-	if err := json.Unmarshal(sessionData.([]byte), &user); err != nil {
+	if err := json.Unmarshal(data, &user); err != nil {
 		return user, err
 	}
 
 	return user, nil
 }
 
+// newSessionData 登录后写入会话的用户信息，见 userSession
+func newSessionData(u *model.User) gin.H {
+	return gin.H{
+		"userId":   u.ID,
+		"username": u.Username,
+		"phone":    u.Phone,
+		"email":    u.Email,
+		"avatar":   config.GlobalConfig.File.UrlPrefix + u.Avatar,
+	}
+}
+
+// startSession 登录成功后写入会话，tracker 为 nil 时不记录会话超时
+func startSession(c common.Context, tracker *activity.Tracker, data gin.H) error {
+	value, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	session := c.GetSession()
+	session.Set(common.SESSION_KEY, string(value))
+	// 登录即完成一次身份验证，之后 reauth_max_age 内可以直接执行敏感操作
+	session.Set(common.SESSION_REAUTH_KEY, time.Now().Unix())
+	// 记录登录时间，会话超时从此刻开始计算；失败时由 SessionAuth 在下次访问时补记
+	session.Delete(common.SESSION_ACTIVITY_KEY)
+	if tracker != nil {
+		if token, err := tracker.Begin(c.RequestContext()); err != nil {
+			c.Logger().Warn("session activity begin failed", zap.Error(err))
+		} else {
+			session.Set(common.SESSION_ACTIVITY_KEY, token)
+		}
+	}
+	session.Save()
+	return nil
+}
+
+// endSession 退出登录，清除会话和会话超时记录
+func endSession(c common.Context, tracker *activity.Tracker) {
+	session := c.GetSession()
+	if token, ok := session.Get(common.SESSION_ACTIVITY_KEY).(string); ok && tracker != nil {
+		if err := tracker.End(c.RequestContext(), token); err != nil {
+			c.Logger().Warn("session activity end failed", zap.Error(err))
+		}
+	}
+	session.Clear()
+	session.Save()
+}
+
+// viewerOf 会话用户作为响应的查看者，决定他人的手机号、邮箱是否脱敏
+func viewerOf(user userSession) dto.Viewer {
+	return dto.Viewer{UserID: user.UserId, Admin: user.UserName == common.ADMIN_NAME}
+}
+
+// cursorQuery 带查询参数 cursor 时使用游标分页，返回上一页最后一条记录的ID；cursor 为空表示第一页
+func cursorQuery(c common.Context) (afterID uint, ok bool, err error) {
+	query := c.Request().URL.Query()
+	if !query.Has("cursor") {
+		return 0, false, nil
+	}
+	if s := query.Get("cursor"); s != "" {
+		afterID, err = cursor.Decode(s)
+	}
+	return afterID, true, err
+}
+
+// abortLoginLocked 账号或 IP 因登录失败次数过多被锁定时返回 429，返回 false 表示不是锁定错误
+func abortLoginLocked(c common.Context, err error) bool {
+	var locked *lockout.LockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+
+	c.SetHeader("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	c.AbortWithError(common.Error(
+		http.StatusTooManyRequests,
+		code.LoginLocked,
+		code.Text(code.LoginLocked)).WithError(err),
+	)
+	return true
+}
+
 type UserController struct {
-	userService service.UserService
+	userService      service.UserService
+	referralService  service.ReferralService
+	broadcastService service.BroadcastService
+	sessionTracker   *activity.Tracker    // 未启用会话超时时为 nil
+	tokens           *jwt.Manager         // auth.mode 为 jwt 时不为 nil，登录签发令牌而不写入会话
+	refreshTokens    *refreshtoken.Store  // jwt 模式且启用 Redis 时不为 nil，刷新令牌可以轮换和吊销
+	fileLinks        *filelink.Signer     // 头像等上传文件的下载链接
+	quarantine       *filescan.Quarantine // 未启用上传扫描时为 nil，启用时上传的文件先放入隔离区
 }
 
-func NewUserController(userService service.UserService) *UserController {
+func NewUserController(userService service.UserService, referralService service.ReferralService, broadcastService service.BroadcastService, sessionTracker *activity.Tracker, tokens *jwt.Manager, refreshTokens *refreshtoken.Store, fileLinks *filelink.Signer, quarantine *filescan.Quarantine) *UserController {
 	return &UserController{
-		userService: userService,
+		userService:      userService,
+		referralService:  referralService,
+		broadcastService: broadcastService,
+		sessionTracker:   sessionTracker,
+		tokens:           tokens,
+		refreshTokens:    refreshTokens,
+		fileLinks:        fileLinks,
+		quarantine:       quarantine,
+	}
+}
+
+// Name 模块名
+func (ctrl *UserController) Name() string {
+	return "users"
+}
+
+// RegisterRoutes 注册、登录和需要登录的用户接口
+func (ctrl *UserController) RegisterRoutes(r router.Router) {
+	users := r.Group("/users")
+	{
+		users.POST("", ctrl.CreateUser())
+		users.POST("/login", ctrl.Login())
+		users.POST("/refresh", ctrl.Refresh())
+	}
+
+	interceptors := r.Interceptors()
+	authUsers := r.AuthGroup("/users", interceptors.Quota("users"))
+	{
+		authUsers.GET("/:id", ctrl.GetUser())
+		authUsers.PUT("/:id", interceptors.RecentAuth("email"), ctrl.UpdateUser())
+		authUsers.PATCH("/:id", interceptors.RecentAuth("email"), ctrl.PatchUser())
+		authUsers.POST("/change_pwd", interceptors.RecentAuth(), ctrl.ChangePassword())
+		authUsers.POST("/reauth", interceptors.NotImpersonating(), ctrl.Reauth())
+		authUsers.POST("/upload_avatar", ctrl.UploadImage())
+		authUsers.GET("/file", ctrl.GetImage())
+		authUsers.DELETE("/:id", interceptors.RecentAuth(), ctrl.DeleteUser())
+		authUsers.GET("", ctrl.ListUsers())
+		authUsers.POST("/logout", ctrl.Logout())
+		authUsers.GET("/referral", ctrl.GetReferral())
+		authUsers.GET("/notifications", ctrl.ListNotifications())
+	}
+}
+
+// issueTokens jwt 模式下为用户签发令牌，authTime 为本次验证密码的时间；失败时中止请求并返回 nil
+// 每次签发都创建新的令牌族，启用 Redis 时记录下来供刷新时轮换
+func (ctrl *UserController) issueTokens(c common.Context, userID uint, username string, authTime time.Time) *dto.TokenResponse {
+	pair, err := ctrl.tokens.Issue(strconv.FormatUint(uint64(userID), 10), username, authTime, "")
+	if err == nil && ctrl.refreshTokens != nil {
+		err = ctrl.refreshTokens.Register(c.RequestContext(), userID, pair.Family, pair.RefreshID)
+	}
+	if err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusInternalServerError,
+			code.ServerError,
+			code.Text(code.ServerError)).WithError(err),
+		)
+		return nil
+	}
+	return dto.NewTokenResponse(pair, authTime)
+}
+
+// revokeFamily 吊销当前访问令牌所属令牌族的刷新令牌，已经签发的访问令牌在过期前仍然有效
+func (ctrl *UserController) revokeFamily(c common.Context, userID uint) {
+	family := c.GetGinContext().GetString(common.JWT_FAMILY_KEY)
+	if ctrl.refreshTokens == nil || family == "" {
+		return
+	}
+	if err := ctrl.refreshTokens.Revoke(c.RequestContext(), userID, family); err != nil {
+		c.Logger().Warn("refresh token revoke failed", zap.Error(err))
+	}
+}
+
+// revokeUser 吊销用户的全部刷新令牌，用于修改密码和注销账号
+func (ctrl *UserController) revokeUser(c common.Context, userID uint) {
+	if ctrl.refreshTokens == nil {
+		return
+	}
+	if err := ctrl.refreshTokens.RevokeUser(c.RequestContext(), userID); err != nil {
+		c.Logger().Warn("refresh token revoke failed", zap.Uint("user_id", userID), zap.Error(err))
 	}
 }
 
 // Login godoc
 //
 //	@Summary		Login user
-//	@Description	Login user with username and password
+//	@Description	Login user with username and password. With auth.mode=session a session cookie is set; with auth.mode=jwt no session is created and the response carries an access token and a refresh token, send the access token as "Authorization: Bearer <access_token>"
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		dto.LoginRequest	true	"User login information"
-//	@Success		200		{object}	response.Response
-//	@Failure		400		{object}	response.Response
-//	@Failure		500		{object}	response.Response
+//	@Success		200		{object}	common.Response{data=object{userId=int,username=string,phone=string,email=string,avatar=string,token=dto.TokenResponse}}
+//	@Failure		400		{object}	common.Response
+//	@Failure		429		{object}	common.Response	"Too many failed logins, see Retry-After"
+//	@Failure		500		{object}	common.Response
 //	@Router			/api/v1/users/login [post]
 func (ctrl *UserController) Login() common.HandlerFunc {
 	return func(c common.Context) {
@@ -69,13 +262,17 @@ func (ctrl *UserController) Login() common.HandlerFunc {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.ParamBindError,
-				validation.Error(err)).WithError(err),
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
 
 		u, err := ctrl.userService.Login(c, &req)
 		if err != nil {
+			if abortLoginLocked(c, err) {
+				return
+			}
+
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.AdminLoginError,
@@ -84,16 +281,20 @@ func (ctrl *UserController) Login() common.HandlerFunc {
 			return
 		}
 
-		data := gin.H{
-			"userId":   u.ID,
-			"username": u.Username,
-			"phone":    u.Phone,
-			"email":    u.Email,
-			"avatar":   config.GlobalConfig.File.UrlPrefix + u.Avatar,
+		data := newSessionData(u)
+
+		// jwt 模式下用户信息随访问令牌携带，不写入会话
+		if ctrl.tokens != nil {
+			token := ctrl.issueTokens(c, u.ID, u.Username, time.Now())
+			if token == nil {
+				return
+			}
+			data["token"] = token
+			c.Payload(data)
+			return
 		}
 
-		value, err := json.MarshalIndent(data, "", "  ")
-		if err != nil {
+		if err := startSession(c, ctrl.sessionTracker, data); err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusInternalServerError,
 				code.MarshalError,
@@ -102,25 +303,112 @@ func (ctrl *UserController) Login() common.HandlerFunc {
 			return
 		}
 
-		session := c.GetSession()
-		session.Set(common.SESSION_KEY, value)
-		session.Save()
-
 		c.Payload(data)
 	}
 }
 
+// Refresh godoc
+//
+//	@Summary		Refresh tokens
+//	@Description	Exchange a refresh token for a new access token and refresh token (auth.mode=jwt only). Each refresh token can be used once; presenting an already used refresh token revokes every token refreshed from the same login, log in again to continue
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.RefreshRequest	true	"Refresh token"
+//	@Success		200		{object}	common.Response{data=dto.TokenResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		500		{object}	common.Response
+//	@Router			/api/v1/users/refresh [post]
+func (ctrl *UserController) Refresh() common.HandlerFunc {
+	return func(c common.Context) {
+		if ctrl.tokens == nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(errors.New("refresh tokens are only issued when auth.mode is jwt")),
+			)
+			return
+		}
+
+		var req dto.RefreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		claims, err := ctrl.tokens.Parse(req.RefreshToken, jwt.TypeRefresh)
+		var userID uint64
+		if err == nil {
+			userID, err = strconv.ParseUint(claims.Subject, 10, 0)
+		}
+		if err != nil {
+			c.SetHeader("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithError(common.Error(
+				http.StatusUnauthorized,
+				code.RefreshInvalid,
+				code.Text(code.RefreshInvalid)).WithError(err),
+			)
+			return
+		}
+
+		// 新令牌沿用原令牌的令牌族和验证时间，刷新不算重新验证身份
+		pair, err := ctrl.tokens.Issue(claims.Subject, claims.Username, time.Unix(claims.AuthTime, 0), claims.Family)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusInternalServerError,
+				code.ServerError,
+				code.Text(code.ServerError)).WithError(err),
+			)
+			return
+		}
+
+		if ctrl.refreshTokens != nil {
+			err := ctrl.refreshTokens.Rotate(c.RequestContext(), uint(userID), claims.Family, claims.ID, pair.RefreshID)
+			if errors.Is(err, refreshtoken.ErrReused) {
+				c.Logger().Warn("refresh token reused, token family revoked",
+					zap.String("user_id", claims.Subject),
+					zap.String("family", claims.Family),
+				)
+			}
+			if errors.Is(err, refreshtoken.ErrReused) || errors.Is(err, refreshtoken.ErrRevoked) {
+				c.SetHeader("WWW-Authenticate", `Bearer error="invalid_token"`)
+				c.AbortWithError(common.Error(
+					http.StatusUnauthorized,
+					code.RefreshInvalid,
+					code.Text(code.RefreshInvalid)).WithError(err),
+				)
+				return
+			}
+			if err != nil {
+				c.AbortWithError(common.Error(
+					http.StatusInternalServerError,
+					code.ServerError,
+					code.Text(code.ServerError)).WithError(err),
+				)
+				return
+			}
+		}
+
+		c.Payload(dto.NewTokenResponse(pair, time.Now()))
+	}
+}
+
 // CreateUser godoc
 //
 //	@Summary		Create a new user
-//	@Description	Create a new user with username, email, phone and password
+//	@Description	Create a new user with username, email, phone and password. The password must satisfy the password policy, each violated rule is listed in the message
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		dto.CreateUserRequest	true	"User information"
-//	@Success		200		{object}	response.Response
-//	@Failure		400		{object}	response.Response
-//	@Failure		500		{object}	response.Response
+//	@Param			request	body		dto.CreateUserRequest	true	"User information, invite_code credits the referrer"
+//	@Success		200		{object}	common.Response{data=dto.UserResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		500		{object}	common.Response
 //	@Router			/api/v1/users [post]
 func (ctrl *UserController) CreateUser() common.HandlerFunc {
 	return func(c common.Context) {
@@ -129,13 +417,26 @@ func (ctrl *UserController) CreateUser() common.HandlerFunc {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.ParamBindError,
-				validation.Error(err)).WithError(err),
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
 
 		user, err := ctrl.userService.CreateUser(c, &req)
 		if err != nil {
+			if abortPasswordPolicy(c, err) {
+				return
+			}
+
+			if errors.Is(err, service.ErrInviteCodeInvalid) {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.InviteCodeInvalid,
+					code.Text(code.InviteCodeInvalid)).WithError(err),
+				)
+				return
+			}
+
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.AdminCreateError,
@@ -143,21 +444,27 @@ func (ctrl *UserController) CreateUser() common.HandlerFunc {
 			)
 			return
 		}
-		c.Payload(user)
+		// 注册时调用方就是新用户本人
+		c.Payload(dto.NewUserResponse(user, dto.Viewer{UserID: user.ID}))
 	}
 }
 
 // CreateUser godoc
 //
 //	@Summary		Change a user's password
-//	@Description	Change a user's password with old password and new password
+//	@Description	Change a user's password with old password and new password. The new password must satisfy the password policy. Requires a recent login or re-authentication
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Param			request	body		dto.UpdatePasswordRequest	true	"User update password information"
-//	@Success		200		{object}	response.Response
-//	@Failure		400		{object}	response.Response
-//	@Failure		500		{object}	response.Response
+//	@Success		200		{object}	common.Response{data=string}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		500		{object}	common.Response
+//	@x-roles		["owner","admin"]
 //	@Router			/api/v1/users/change_pwd [post]
 func (ctrl *UserController) ChangePassword() common.HandlerFunc {
 	return func(c common.Context) {
@@ -166,7 +473,7 @@ func (ctrl *UserController) ChangePassword() common.HandlerFunc {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.ParamBindError,
-				validation.Error(err)).WithError(err),
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
@@ -194,6 +501,10 @@ func (ctrl *UserController) ChangePassword() common.HandlerFunc {
 		}
 
 		if err = ctrl.userService.UpdatePassword(c, &req); err != nil {
+			if abortPasswordPolicy(c, err) {
+				return
+			}
+
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.AdminModifyPasswordError,
@@ -202,6 +513,17 @@ func (ctrl *UserController) ChangePassword() common.HandlerFunc {
 			return
 		}
 
+		// 吊销该用户的刷新令牌，jwt 模式下访问令牌过期后需要重新登录；管理员可以修改他人的密码
+		if req.Username == user.UserName {
+			ctrl.revokeUser(c, user.UserId)
+		} else if ctrl.refreshTokens != nil {
+			if target, err := ctrl.userService.GetUserByUsername(c, req.Username); err != nil {
+				c.Logger().Warn("refresh token revoke failed", zap.String("username", req.Username), zap.Error(err))
+			} else {
+				ctrl.revokeUser(c, target.ID)
+			}
+		}
+
 		// 清除session，重新登录
 		session := c.GetSession()
 		session.Clear()
@@ -214,19 +536,39 @@ func (ctrl *UserController) ChangePassword() common.HandlerFunc {
 // CreateUser godoc
 //
 //	@Summary		Upload Avatar Image
-//	@Description	Upload avatar image for user
+//	@Description	Upload avatar image for user as multipart form data, or as JSON {"username": "...", "image_base64": "..."} with Content-Type application/json for clients that cannot send multipart. JSON images are limited to file.max_size bytes and must be JPEG, PNG, GIF or WebP. Returns a signed download link that expires after file.link_ttl seconds. When upload scanning is enabled the link returns 503 until the scan finishes
 //	@Tags			users
-//	@Accept			json
+//	@Accept			multipart/form-data,json
 //	@Produce		json
-//	@Param			request	    formData	file 	true  	"User avatar image"
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			file		formData	file	true	"User avatar image"
 //	@Param			username	formData	string	true	"username"
-//	@Success		200		{object}	response.Response
-//	@Failure		400		{object}	response.Response
-//	@Failure		500		{object}	response.Response
+//	@Success		200			{object}	common.Response{data=string}
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@Failure		413			{object}	common.Response	"Base64 image exceeds file.max_size"
+//	@Failure		500			{object}	common.Response
+//	@x-roles		["owner","admin"]
 //	@Router			/api/v1/users/upload_avatar [post]
 func (ctrl *UserController) UploadImage() common.HandlerFunc {
 	return func(c common.Context) {
+		// Content-Type 为 application/json 时图片以 base64 放在请求体中，否则为 multipart 表单
+		var req dto.UploadAvatarRequest
+		jsonMode := c.GetGinContext().ContentType() == binding.MIMEJSON
 		username := c.PostForm("username")
+		if jsonMode {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.ParamBindError,
+					validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+				)
+				return
+			}
+			username = req.Username
+		}
 
 		sessionData := c.SessionUserInfo()
 		user, err := getUserSession(sessionData)
@@ -248,14 +590,25 @@ func (ctrl *UserController) UploadImage() common.HandlerFunc {
 			return
 		}
 
-		file, err := c.FormFile("file")
-		if err != nil {
-			c.AbortWithError(common.Error(
-				http.StatusBadRequest,
-				code.ParamBindError,
-				code.Text(code.ParamBindError)).WithError(err),
-			)
-			return
+		var file *multipart.FileHeader
+		var image []byte
+		var ext string
+		if jsonMode {
+			image, ext, err = utils.DecodeBase64Image(req.ImageBase64, config.GlobalConfig.File.MaxSize)
+			if err != nil {
+				abortImageError(c, err)
+				return
+			}
+		} else {
+			file, err = c.FormFile("file")
+			if err != nil {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.ParamBindError,
+					code.Text(code.ParamBindError)).WithError(err),
+				)
+				return
+			}
 		}
 
 		_, err = ctrl.userService.GetUserByUsername(c, username)
@@ -268,10 +621,19 @@ func (ctrl *UserController) UploadImage() common.HandlerFunc {
 			return
 		}
 
+		// 启用上传扫描时先保存到隔离区，扫描通过后才移入下载目录
 		dst := path.Join(config.GlobalConfig.File.DirName, username)
+		if ctrl.quarantine != nil {
+			dst = path.Join(ctrl.quarantine.UploadDir(), username)
+		}
 
 		// 暂时保存文件到服务器，TODO:上传到oss、七牛云
-		filename, err := utils.SaveToFile(file, dst)
+		var filename string
+		if jsonMode {
+			filename, err = utils.SaveBytesToFile(image, ext, dst)
+		} else {
+			filename, err = utils.SaveToFile(file, dst)
+		}
 		if err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
@@ -291,26 +653,67 @@ func (ctrl *UserController) UploadImage() common.HandlerFunc {
 			return
 		}
 
-		// 返回头像url
-		avatarUrl := config.GlobalConfig.File.UrlPrefix + filename
+		if ctrl.quarantine != nil {
+			ctrl.quarantine.Submit(path.Join(username, filename))
+		}
+
+		// 返回头像的下载链接
+		avatarUrl, err := ctrl.fileLinks.URL(path.Join(username, filename))
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusInternalServerError,
+				code.UrlSignError,
+				code.Text(code.UrlSignError)).WithError(err),
+			)
+			return
+		}
 		c.Payload(avatarUrl)
 	}
 }
 
+// abortImageError base64 图片校验失败
+func abortImageError(c common.Context, err error) {
+	switch {
+	case errors.Is(err, utils.ErrImageTooLarge):
+		c.AbortWithError(common.Error(
+			http.StatusRequestEntityTooLarge,
+			code.FileTooLarge,
+			code.Text(code.FileTooLarge)).WithError(err),
+		)
+	case errors.Is(err, utils.ErrImageType):
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.FileTypeError,
+			code.Text(code.FileTypeError)).WithError(err),
+		)
+	default:
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.ParamBindError,
+			code.Text(code.ParamBindError)).WithError(err),
+		)
+	}
+}
+
 // GetImage godoc
 //
 //	@Summary		Get user image by username and image name
-//	@Description	Get user image by username and image name
+//	@Description	Check that the caller may view the image, then redirect to a signed download link for it
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
 //	@Param			username	query		string	true	"username"
 //	@Param			imageName	query		string	true	"image name"
-//	@Success		200	{object}	response.Response
-//	@Failure		400	{object}	response.Response
-//	@Failure		404	{object}	response.Response
-//	@Failure		500	{object}	response.Response
-//	@Router			/api/v1/users/file?username={username}&imageName={imageName} [get]
+//	@Success		302			{string}	string	"Redirect to the signed download link"
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@Failure		404			{object}	common.Response
+//	@Failure		500			{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/users/file [get]
 func (ctrl *UserController) GetImage() common.HandlerFunc {
 	return func(c common.Context) {
 		username := c.Query("username")
@@ -344,9 +747,20 @@ func (ctrl *UserController) GetImage() common.HandlerFunc {
 			return
 		}
 
-		fileName := path.Join(config.GlobalConfig.File.DirName, username, imageName)
-
-		c.File(fileName)
+		// 文件由下载链接的处理函数返回，这里只校验权限并签发链接；imageName 不能带目录，否则可以拿到其他用户文件的链接
+		link, err := ctrl.fileLinks.URL(path.Join(username, imageName))
+		if err == nil && path.Base(imageName) != imageName {
+			err = filelink.ErrInvalidName
+		}
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamQueryError,
+				code.Text(code.ParamQueryError)).WithError(err),
+			)
+			return
+		}
+		c.GetGinContext().Redirect(http.StatusFound, link)
 	}
 }
 
@@ -357,22 +771,21 @@ func (ctrl *UserController) GetImage() common.HandlerFunc {
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		int	true	"User ID"
-//	@Success		200	{object}	response.Response
-//	@Failure		400	{object}	response.Response
-//	@Failure		404	{object}	response.Response
-//	@Failure		500	{object}	response.Response
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			id	path		string	true	"User ID, encoded when id_obfuscation is enabled"
+//	@Success		200	{object}	common.Response{data=dto.UserResponse}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@Failure		500	{object}	common.Response
+//	@x-roles		["owner","admin"]
 //	@Router			/api/v1/users/{id} [get]
 func (ctrl *UserController) GetUser() common.HandlerFunc {
 	return func(c common.Context) {
-		idStr := c.Param("id")
-		id, err := strconv.ParseUint(idStr, 10, 32)
-		if err != nil {
-			c.AbortWithError(common.Error(
-				http.StatusBadRequest,
-				code.ParseError,
-				code.Text(code.ParseError)).WithError(err),
-			)
+		id, ok := userIDParam(c, "id")
+		if !ok {
 			return
 		}
 
@@ -387,7 +800,7 @@ func (ctrl *UserController) GetUser() common.HandlerFunc {
 			return
 		}
 
-		if user.UserName != common.ADMIN_NAME && user.UserId != uint(id) {
+		if user.UserName != common.ADMIN_NAME && user.UserId != id {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.AuthorizationError,
@@ -396,7 +809,7 @@ func (ctrl *UserController) GetUser() common.HandlerFunc {
 			return
 		}
 
-		userData, err := ctrl.userService.GetUser(c, uint(id))
+		userData, err := ctrl.userService.GetUser(c, id)
 		if err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
@@ -406,68 +819,98 @@ func (ctrl *UserController) GetUser() common.HandlerFunc {
 			return
 		}
 
-		c.Payload(userData)
+		c.Payload(dto.NewUserResponse(userData, viewerOf(user)))
 	}
 }
 
 // UpdateUser godoc
 //
 //	@Summary		Update user information
-//	@Description	Update user information by user ID
+//	@Description	Update user information by user ID. Changing email requires a recent login or re-authentication
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			id		path		int						true	"User ID"
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string					true	"User ID, encoded when id_obfuscation is enabled"
 //	@Param			request	body		dto.UpdateUserRequest	true	"User information to update"
-//	@Success		200		{object}	response.Response
-//	@Failure		400		{object}	response.Response
-//	@Failure		404		{object}	response.Response
-//	@Failure		500		{object}	response.Response
+//	@Success		200		{object}	common.Response{data=dto.UserResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Failure		500		{object}	common.Response
+//	@x-roles		["owner","admin"]
 //	@Router			/api/v1/users/{id} [put]
 func (ctrl *UserController) UpdateUser() common.HandlerFunc {
 	return func(c common.Context) {
-		idStr := c.Param("id")
-		id, err := strconv.ParseUint(idStr, 10, 32)
-		if err != nil {
+		id, user, ok := ctrl.updatableUser(c)
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
-				code.ParseError,
-				code.Text(code.ParseError)).WithError(err),
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
 
-		sessionData := c.SessionUserInfo()
-		user, err := getUserSession(sessionData)
+		userData, err := ctrl.userService.UpdateUser(c, id, &req)
 		if err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
-				code.AuthorizationError,
-				code.Text(code.AuthorizationError)).WithError(err),
+				code.AdminUpdateError,
+				code.Text(code.AdminUpdateError)).WithError(err),
 			)
 			return
 		}
 
-		if user.UserName != common.ADMIN_NAME && user.UserId != uint(id) {
-			c.AbortWithError(common.Error(
-				http.StatusBadRequest,
-				code.AuthorizationError,
-				code.Text(code.AuthorizationError)).WithError(errors.New(user.UserName + " overstepping authority")),
-			)
+		c.Payload(dto.NewUserResponse(userData, viewerOf(user)))
+	}
+}
+
+// PatchUser godoc
+//
+//	@Summary		Partially update user information
+//	@Description	Update only the fields present in the body, zero values such as status 0 are applied. Accepts application/json and application/merge-patch+json, null fields are left unchanged. Changing email requires a recent login or re-authentication
+//	@Tags			users
+//	@Accept			json
+//	@Accept			application/merge-patch+json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			id		path		string					true	"User ID, encoded when id_obfuscation is enabled"
+//	@Param			request	body		dto.PatchUserRequest	true	"User fields to change"
+//	@Success		200		{object}	common.Response{data=dto.UserResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@Failure		404		{object}	common.Response
+//	@Failure		500		{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/users/{id} [patch]
+func (ctrl *UserController) PatchUser() common.HandlerFunc {
+	return func(c common.Context) {
+		id, user, ok := ctrl.updatableUser(c)
+		if !ok {
 			return
 		}
 
-		var req dto.UpdateUserRequest
+		var req dto.PatchUserRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.ParamBindError,
-				validation.Error(err)).WithError(err),
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
 
-		userData, err := ctrl.userService.UpdateUser(c, uint(id), &req)
+		userData, err := ctrl.userService.PatchUser(c, id, &req)
 		if err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
@@ -477,33 +920,60 @@ func (ctrl *UserController) UpdateUser() common.HandlerFunc {
 			return
 		}
 
-		c.Payload(userData)
+		c.Payload(dto.NewUserResponse(userData, viewerOf(user)))
+	}
+}
+
+// updatableUser 解析路径中的用户ID并校验会话用户可以修改该用户(本人或管理员)，失败时直接返回 400
+func (ctrl *UserController) updatableUser(c common.Context) (uint, userSession, bool) {
+	id, ok := userIDParam(c, "id")
+	if !ok {
+		return 0, userSession{}, false
+	}
+
+	user, err := getUserSession(c.SessionUserInfo())
+	if err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.AuthorizationError,
+			code.Text(code.AuthorizationError)).WithError(err),
+		)
+		return 0, userSession{}, false
+	}
+
+	if user.UserName != common.ADMIN_NAME && user.UserId != id {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.AuthorizationError,
+			code.Text(code.AuthorizationError)).WithError(errors.New(user.UserName + " overstepping authority")),
+		)
+		return 0, userSession{}, false
 	}
+	return id, user, true
 }
 
 // DeleteUser godoc
 //
 //	@Summary		Delete user
-//	@Description	Delete user by user ID
+//	@Description	Delete user by user ID. Requires a recent login or re-authentication
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		int	true	"User ID"
-//	@Success		200	{object}	response.Response
-//	@Failure		400	{object}	response.Response
-//	@Failure		404	{object}	response.Response
-//	@Failure		500	{object}	response.Response
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			id	path		string	true	"User ID, encoded when id_obfuscation is enabled"
+//	@Success		200	{object}	common.Response{data=string}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@Failure		404	{object}	common.Response
+//	@Failure		500	{object}	common.Response
+//	@x-roles		["owner","admin"]
 //	@Router			/api/v1/users/{id} [delete]
 func (ctrl *UserController) DeleteUser() common.HandlerFunc {
 	return func(c common.Context) {
-		idStr := c.Param("id")
-		id, err := strconv.ParseUint(idStr, 10, 32)
-		if err != nil {
-			c.AbortWithError(common.Error(
-				http.StatusBadRequest,
-				code.ParseError,
-				code.Text(code.ParseError)).WithError(err),
-			)
+		id, ok := userIDParam(c, "id")
+		if !ok {
 			return
 		}
 
@@ -518,7 +988,7 @@ func (ctrl *UserController) DeleteUser() common.HandlerFunc {
 			return
 		}
 
-		if user.UserName != common.ADMIN_NAME && user.UserId != uint(id) {
+		if user.UserName != common.ADMIN_NAME && user.UserId != id {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.AuthorizationError,
@@ -527,7 +997,7 @@ func (ctrl *UserController) DeleteUser() common.HandlerFunc {
 			return
 		}
 
-		if err := ctrl.userService.DeleteUser(c, uint(id)); err != nil {
+		if err := ctrl.userService.DeleteUser(c, id); err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.AdminDeleteError,
@@ -535,6 +1005,7 @@ func (ctrl *UserController) DeleteUser() common.HandlerFunc {
 			)
 			return
 		}
+		ctrl.revokeUser(c, id)
 
 		c.Payload("Deleted successfully")
 	}
@@ -543,14 +1014,27 @@ func (ctrl *UserController) DeleteUser() common.HandlerFunc {
 // ListUsers godoc
 //
 //	@Summary		List users
-//	@Description	Get paginated list of users
+//	@Description	Get paginated list of users. Users can be filtered by status, username prefix and creation time range, total counts the users matching the filters. Sort by comma separated fields (id, created_at, username), prefix a field with - for descending order, by id by default
+//	@Description	With the cursor parameter (empty for the first page), users are listed by id in ascending order after the cursor and page is ignored; pass next_cursor of the response to get the next page, total is not counted. Cursor pagination cannot be combined with sort
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			page		query		int	false	"Page number"		default(1)
-//	@Param			page_size	query		int	false	"Page size"			default(10)
-//	@Success		200			{object}	response.Response
-//	@Failure		500			{object}	response.Response
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			page		query		int		false	"Page number"	default(1)
+//	@Param			page_size	query		int		false	"Page size"		default(10)
+//	@Param			cursor		query		string	false	"Cursor for keyset pagination, empty for the first page"
+//	@Param			status		query		int		false	"User status"
+//	@Param			username	query		string	false	"Username prefix"
+//	@Param			created_from	query		string	false	"Created at or after (RFC 3339)"	format(date-time)
+//	@Param			created_to	query		string	false	"Created before (RFC 3339)"			format(date-time)
+//	@Param			sort		query		string	false	"Sort fields, e.g. -created_at"
+//	@Success		200			{object}	common.Response{data=dto.ListUsersResponse}
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@Failure		500			{object}	common.Response
+//	@x-roles		["admin"]
 //	@Router			/api/v1/users [get]
 func (ctrl *UserController) ListUsers() common.HandlerFunc {
 	return func(c common.Context) {
@@ -577,7 +1061,50 @@ func (ctrl *UserController) ListUsers() common.HandlerFunc {
 		page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 		pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
-		users, total, err := ctrl.userService.ListUsers(c, page, pageSize)
+		filter, err := repository.UserQuery.Parse(c.Request().URL.Query())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		// 游标分页按ID翻页，忽略 page，不统计总数，不支持自定义排序
+		afterID, useCursor, err := cursorQuery(c)
+		if err == nil && useCursor && len(filter.Sort) > 0 {
+			err = errors.New("cursor cannot be combined with sort")
+		}
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				code.Text(code.ParamBindError)).WithError(err),
+			)
+			return
+		}
+		if useCursor {
+			users, more, err := ctrl.userService.ListUsersAfter(c, filter, afterID, pageSize)
+			if err != nil {
+				c.AbortWithError(common.Error(
+					http.StatusBadRequest,
+					code.AdminListError,
+					code.Text(code.AdminListError)).WithError(err),
+				)
+				return
+			}
+
+			res.Users = dto.NewUserResponses(users, viewerOf(user))
+			res.PageSize = pageSize
+			if more {
+				res.NextCursor = cursor.Encode(users[len(users)-1].ID)
+			}
+			c.Payload(res)
+			return
+		}
+
+		users, total, err := ctrl.userService.ListUsers(c, filter, page, pageSize)
 		if err != nil {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
@@ -587,7 +1114,7 @@ func (ctrl *UserController) ListUsers() common.HandlerFunc {
 			return
 		}
 
-		res.Users = users
+		res.Users = dto.NewUserResponses(users, viewerOf(user))
 		res.Total = total
 		res.Page = page
 		res.PageSize = pageSize
@@ -596,6 +1123,185 @@ func (ctrl *UserController) ListUsers() common.HandlerFunc {
 	}
 }
 
+// GetReferral godoc
+//
+//	@Summary		Get my invitation code
+//	@Description	Get the session user's invitation code (generated on first call), invited user count and earned credits
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Success		200	{object}	common.Response{data=dto.ReferralResponse}
+//	@Failure		400	{object}	common.Response
+//	@Failure		401	{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/referral [get]
+func (ctrl *UserController) GetReferral() common.HandlerFunc {
+	return func(c common.Context) {
+		sessionData := c.SessionUserInfo()
+		user, err := getUserSession(sessionData)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		referral, err := ctrl.referralService.GetReferral(c, user.UserId)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ReferralGetError,
+				code.Text(code.ReferralGetError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(referral)
+	}
+}
+
+// ListNotifications godoc
+//
+//	@Summary		List my notifications
+//	@Description	List the session user's in-app notifications, newest first
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(10)
+//	@Success		200			{object}	common.Response{data=[]dto.NotificationResponse}
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/notifications [get]
+func (ctrl *UserController) ListNotifications() common.HandlerFunc {
+	return func(c common.Context) {
+		sessionData := c.SessionUserInfo()
+		user, err := getUserSession(sessionData)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		page, pageSize := pageQuery(c)
+		notifications, total, err := ctrl.broadcastService.ListNotifications(c, user.UserId, page, pageSize)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.NotificationListError,
+				code.Text(code.NotificationListError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(response.NewPaged(dto.NewNotificationResponses(notifications), total, page, pageSize))
+	}
+}
+
+// Reauth godoc
+//
+//	@Summary		Re-authenticate
+//	@Description	Verify the current user's password again. Changing password, changing email and deleting account require a login or re-authentication within session.reauth_max_age, otherwise they return 401 with the X-Reauth-Required header
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			request	body		dto.ReauthRequest	true	"Current password"
+//	@Success		200		{object}	common.Response{data=dto.ReauthResponse}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/users/reauth [post]
+func (ctrl *UserController) Reauth() common.HandlerFunc {
+	return func(c common.Context) {
+		var req dto.ReauthRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.ParamBindError,
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+			)
+			return
+		}
+
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		// 与登录走同一套校验，后续的登录保护同样适用于重新验证
+		if _, err := ctrl.userService.Login(c, &dto.LoginRequest{Username: user.UserName, Password: req.Password}); err != nil {
+			if abortLoginLocked(c, err) {
+				return
+			}
+
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AdminReauthError,
+				code.Text(code.AdminReauthError)).WithError(err),
+			)
+			return
+		}
+
+		now := time.Now()
+		res := &dto.ReauthResponse{
+			AuthenticatedAt: now,
+			ExpiresAt:       now.Add(config.GlobalConfig.Session.ReauthWindow()),
+		}
+
+		// jwt 模式下验证时间记录在令牌的 auth_time 中，重新签发令牌，原令牌族随之吊销
+		if ctrl.tokens != nil {
+			ctrl.revokeFamily(c, user.UserId)
+			if res.Token = ctrl.issueTokens(c, user.UserId, user.UserName, now); res.Token == nil {
+				return
+			}
+			c.Payload(res)
+			return
+		}
+
+		session := c.GetSession()
+		session.Set(common.SESSION_REAUTH_KEY, now.Unix())
+		session.Save()
+
+		c.Payload(res)
+	}
+}
+
+// Logout godoc
+//
+//	@Summary		Logout user
+//	@Description	Destroy the current session
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			request	body		dto.LogoutRequest	true	"User to logout"
+//	@Success		200		{object}	common.Response{data=string}
+//	@Failure		400		{object}	common.Response
+//	@Failure		401		{object}	common.Response
+//	@x-roles		["owner","admin"]
+//	@Router			/api/v1/users/logout [post]
 func (ctrl *UserController) Logout() common.HandlerFunc {
 	return func(c common.Context) {
 		var req dto.LogoutRequest
@@ -603,7 +1309,7 @@ func (ctrl *UserController) Logout() common.HandlerFunc {
 			c.AbortWithError(common.Error(
 				http.StatusBadRequest,
 				code.ParamBindError,
-				validation.Error(err)).WithError(err),
+				validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
 			)
 			return
 		}
@@ -628,10 +1334,11 @@ func (ctrl *UserController) Logout() common.HandlerFunc {
 			return
 		}
 
+		// jwt 模式下吊销刷新令牌，访问令牌在过期前仍然有效
+		ctrl.revokeFamily(c, user.UserId)
+
 		// 清除session，重新登录
-		session := c.GetSession()
-		session.Clear()
-		session.Save()
+		endSession(c, ctrl.sessionTracker)
 
 		c.Payload("Logout successfully")
 	}
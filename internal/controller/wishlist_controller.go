@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/router"
+	"gin-app-start/internal/service"
+	"gin-app-start/internal/validation"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/response"
+)
+
+type WishlistController struct {
+	wishlistService service.WishlistService
+}
+
+func NewWishlistController(wishlistService service.WishlistService) *WishlistController {
+	return &WishlistController{
+		wishlistService: wishlistService,
+	}
+}
+
+// Name 模块名
+func (wc *WishlistController) Name() string {
+	return "wishlist"
+}
+
+// RegisterRoutes 收藏接口挂在 /users 下，与用户接口共用认证和配额
+func (wc *WishlistController) RegisterRoutes(r router.Router) {
+	users := r.AuthGroup("/users", r.Interceptors().Quota("users"))
+	{
+		users.GET("/wishlist", wc.ListFavorites())
+		users.PUT("/wishlist/:product_id", wc.AddFavorite())
+		users.DELETE("/wishlist/:product_id", wc.RemoveFavorite())
+	}
+}
+
+// wishlistItem 解析会话用户和路径中的商品ID，失败时直接返回 400
+func wishlistItem(c common.Context) (userSession, string, bool) {
+	var req dto.WishlistItemRequest
+	if err := c.ShouldBindURI(&req); err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.ParamBindError,
+			validation.Error(err)).WithError(err).WithDetails(validation.Details(err)),
+		)
+		return userSession{}, "", false
+	}
+
+	user, err := getUserSession(c.SessionUserInfo())
+	if err != nil {
+		c.AbortWithError(common.Error(
+			http.StatusBadRequest,
+			code.AuthorizationError,
+			code.Text(code.AuthorizationError)).WithError(err),
+		)
+		return userSession{}, "", false
+	}
+	return user, req.ProductID, true
+}
+
+// AddFavorite godoc
+//
+//	@Summary		Favorite product
+//	@Description	Add a product to the session user's wishlist, favoriting an already favorited product is a no-op
+//	@Tags			wishlist
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Success		200			{object}	common.Response{data=string}
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@Failure		409			{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/wishlist/{product_id} [put]
+func (wc *WishlistController) AddFavorite() common.HandlerFunc {
+	return func(c common.Context) {
+		user, productID, ok := wishlistItem(c)
+		if !ok {
+			return
+		}
+
+		if err := wc.wishlistService.Add(c, user.UserId, productID); err != nil {
+			if errors.Is(err, service.ErrWishlistFull) {
+				c.AbortWithError(common.Error(
+					http.StatusConflict,
+					code.WishlistFull,
+					code.Text(code.WishlistFull)).WithError(err),
+				)
+				return
+			}
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.WishlistAddError,
+				code.Text(code.WishlistAddError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload("Add to wishlist successfully")
+	}
+}
+
+// RemoveFavorite godoc
+//
+//	@Summary		Unfavorite product
+//	@Description	Remove a product from the session user's wishlist
+//	@Tags			wishlist
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			product_id	path		string	true	"Product ID"
+//	@Success		200			{object}	common.Response{data=string}
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/wishlist/{product_id} [delete]
+func (wc *WishlistController) RemoveFavorite() common.HandlerFunc {
+	return func(c common.Context) {
+		user, productID, ok := wishlistItem(c)
+		if !ok {
+			return
+		}
+
+		if err := wc.wishlistService.Remove(c, user.UserId, productID); err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.WishlistRemoveError,
+				code.Text(code.WishlistRemoveError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload("Remove from wishlist successfully")
+	}
+}
+
+// ListFavorites godoc
+//
+//	@Summary		List my wishlist
+//	@Description	List the session user's favorited products, most recently saved first
+//	@Tags			wishlist
+//	@Accept			json
+//	@Produce		json
+//	@Security		SessionCookie
+//	@Security		BearerAuth
+//	@Security		ApiKeyAuth
+//	@Param			page		query		int	false	"Page number"	default(1)
+//	@Param			page_size	query		int	false	"Page size"		default(10)
+//	@Success		200			{object}	common.Response{data=[]dto.FavoriteResponse}
+//	@Failure		400			{object}	common.Response
+//	@Failure		401			{object}	common.Response
+//	@x-roles		["owner"]
+//	@Router			/api/v1/users/wishlist [get]
+func (wc *WishlistController) ListFavorites() common.HandlerFunc {
+	return func(c common.Context) {
+		user, err := getUserSession(c.SessionUserInfo())
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		page, pageSize := pageQuery(c)
+		favorites, total, err := wc.wishlistService.List(c, user.UserId, page, pageSize)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusBadRequest,
+				code.WishlistListError,
+				code.Text(code.WishlistListError)).WithError(err),
+			)
+			return
+		}
+
+		c.Payload(response.NewPaged(dto.NewFavoriteResponses(favorites), total, page, pageSize))
+	}
+}
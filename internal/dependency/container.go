@@ -0,0 +1,279 @@
+package dependency
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultCheckInterval = 10 * time.Second
+	defaultCheckTimeout  = 3 * time.Second
+
+	reconnectBaseDelay = time.Second
+	reconnectMaxDelay  = time.Minute
+)
+
+// Dependency 外部依赖(Postgres、Redis 等)
+type Dependency struct {
+	Name     string
+	Required bool // 必需依赖不可用时服务未就绪；非必需依赖(如缓存)不可用时服务降级运行
+
+	// Check 探活，返回 nil 表示可用
+	Check func(ctx context.Context) error
+	// Reconnect 探活失败后重新建立连接，为 nil 时只探活(如 database/sql 连接池会自动重连)
+	Reconnect func(ctx context.Context) error
+}
+
+// Status 依赖的当前状态
+type Status struct {
+	Name      string    `json:"name"`
+	Required  bool      `json:"required"`
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	Since     time.Time `json:"since"`      // 进入当前状态的时间
+	LastCheck time.Time `json:"last_check"` // 最近一次探活时间
+	LatencyMS float64   `json:"latency_ms"` // 最近一次探活的耗时，单位毫秒；超时时为超时时间
+}
+
+type entry struct {
+	dep      Dependency
+	status   Status
+	failures int       // 连续失败次数，用于计算重连退避
+	nextTry  time.Time // 下次允许重连的时间
+}
+
+// Container 依赖管理: 后台定期探活，失败时按指数退避重连，并向就绪探针提供状态
+type Container struct {
+	logger   *zap.Logger
+	interval time.Duration
+
+	mu      sync.RWMutex
+	entries []*entry
+	checks  singleflight.Group // 每个依赖正在执行的 Check，按名称合并
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewContainer 创建依赖管理器，interval 为探活间隔
+func NewContainer(logger *zap.Logger, interval time.Duration) *Container {
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	return &Container{
+		logger:   logger,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Register 注册依赖，需在 Start 之前调用；会立即探活一次以确定初始状态
+func (c *Container) Register(dep Dependency) {
+	e := &entry{dep: dep, status: Status{Name: dep.Name, Required: dep.Required}}
+	c.check(e)
+
+	c.mu.Lock()
+	c.entries = append(c.entries, e)
+	c.mu.Unlock()
+}
+
+// Start 启动后台探活
+func (c *Container) Start() {
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.mu.RLock()
+				entries := append([]*entry(nil), c.entries...)
+				c.mu.RUnlock()
+
+				for _, e := range entries {
+					c.check(e)
+				}
+			}
+		}
+	}()
+}
+
+// Stop 停止后台探活
+func (c *Container) Stop() {
+	close(c.stop)
+	<-c.done
+}
+
+func (c *Container) check(e *entry) {
+	now := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCheckTimeout)
+	latency, err := c.ping(ctx, e.dep)
+	cancel()
+
+	c.mu.RLock()
+	retry := !now.Before(e.nextTry)
+	c.mu.RUnlock()
+
+	// 探活失败且到了重连时间: 尝试重连，成功后再探活一次
+	if err != nil && e.dep.Reconnect != nil && retry {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultCheckTimeout)
+		if rerr := e.dep.Reconnect(ctx); rerr != nil {
+			err = rerr
+		} else {
+			latency, err = c.ping(ctx, e.dep)
+		}
+		cancel()
+
+		if err != nil {
+			c.mu.Lock()
+			e.failures++
+			e.nextTry = now.Add(backoff(e.failures))
+			c.mu.Unlock()
+		}
+	}
+
+	c.update(e, now, latency, err)
+}
+
+// Probe 立即并发探活所有依赖并更新状态，用于就绪探针；每个依赖最多等待 timeout，失败时不重连(由后台探活负责)
+// 返回探活后所有依赖的状态(按名称排序)和所有必需依赖是否可用
+func (c *Container) Probe(ctx context.Context, timeout time.Duration) ([]Status, bool) {
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+
+	c.mu.RLock()
+	entries := append([]*entry(nil), c.entries...)
+	c.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, e := range entries {
+		wg.Add(1)
+		go func(e *entry) {
+			defer wg.Done()
+
+			now := time.Now()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			latency, err := c.ping(ctx, e.dep)
+			cancel()
+			c.update(e, now, latency, err)
+		}(e)
+	}
+	wg.Wait()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.statuses(), c.ready()
+}
+
+// ping 调用 Check 并计时；Check 没有及时响应 ctx 取消时也在 ctx 结束时返回，避免探针被卡住
+//
+// 同一个依赖同时只执行一个 Check，并发的探活(包括上一次超时返回后 Check 仍未结束时的探活)等待它的结果，
+// 卡住的 Check 不会随探针请求不断累积 goroutine。Check 使用独立的 ctx，超时为 defaultCheckTimeout，
+// 不随某一个调用方取消。
+func (c *Container) ping(ctx context.Context, dep Dependency) (time.Duration, error) {
+	start := time.Now()
+	done := c.checks.DoChan(dep.Name, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultCheckTimeout)
+		defer cancel()
+		return nil, dep.Check(ctx)
+	})
+
+	select {
+	case res := <-done:
+		return time.Since(start), res.Err
+	case <-ctx.Done():
+		return time.Since(start), ctx.Err()
+	}
+}
+
+// update 记录一次探活的结果
+func (c *Container) update(e *entry, now time.Time, latency time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	healthy := err == nil
+	if healthy != e.status.Healthy || e.status.Since.IsZero() {
+		e.status.Since = now
+		c.logStateChange(e, err)
+	}
+	if healthy {
+		e.failures = 0
+		e.nextTry = time.Time{}
+		e.status.Error = ""
+	} else {
+		e.status.Error = err.Error()
+	}
+	e.status.Healthy = healthy
+	e.status.LastCheck = now
+	e.status.LatencyMS = float64(latency.Microseconds()) / 1000
+}
+
+func (c *Container) logStateChange(e *entry, err error) {
+	if c.logger == nil {
+		return
+	}
+	if err == nil {
+		c.logger.Info("dependency is healthy", zap.String("dependency", e.dep.Name))
+		return
+	}
+	c.logger.Warn("dependency is unavailable",
+		zap.String("dependency", e.dep.Name),
+		zap.Bool("required", e.dep.Required),
+		zap.Error(err),
+	)
+}
+
+// backoff 第 failures 次重连失败后的等待时间
+func backoff(failures int) time.Duration {
+	d := reconnectBaseDelay << (failures - 1)
+	if d <= 0 || d > reconnectMaxDelay {
+		d = reconnectMaxDelay
+	}
+	return d
+}
+
+// Statuses 所有依赖的当前状态，按名称排序
+func (c *Container) Statuses() []Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.statuses()
+}
+
+func (c *Container) statuses() []Status {
+	statuses := make([]Status, 0, len(c.entries))
+	for _, e := range c.entries {
+		statuses = append(statuses, e.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// Ready 所有必需依赖是否可用
+func (c *Container) Ready() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ready()
+}
+
+func (c *Container) ready() bool {
+	for _, e := range c.entries {
+		if e.dep.Required && !e.status.Healthy {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package dependency
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProbe(t *testing.T) {
+	c := NewContainer(nil, time.Minute)
+
+	var pgErr error
+	c.Register(Dependency{
+		Name:     "postgres",
+		Required: true,
+		Check:    func(context.Context) error { return pgErr },
+	})
+	// 不响应 ctx 取消的探活也在超时后返回
+	c.Register(Dependency{
+		Name:  "redis",
+		Check: func(context.Context) error { time.Sleep(300 * time.Millisecond); return nil },
+	})
+
+	start := time.Now()
+	statuses, ready := c.Probe(context.Background(), 50*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Fatalf("Probe took %v, want about the timeout", elapsed)
+	}
+	if !ready {
+		t.Fatal("optional dependency down should not make the service unready")
+	}
+	if len(statuses) != 2 || statuses[0].Name != "postgres" || statuses[1].Name != "redis" {
+		t.Fatalf("statuses = %+v, want postgres and redis sorted by name", statuses)
+	}
+	if redis := statuses[1]; redis.Healthy || redis.Error != context.DeadlineExceeded.Error() || redis.LatencyMS < 50 {
+		t.Fatalf("redis = %+v, want unhealthy after timeout", redis)
+	}
+
+	pgErr = errors.New("connection refused")
+	statuses, ready = c.Probe(context.Background(), 50*time.Millisecond)
+	if ready {
+		t.Fatal("required dependency down should make the service unready")
+	}
+	if pg := statuses[0]; pg.Healthy || pg.Error != "connection refused" {
+		t.Fatalf("postgres = %+v, want unhealthy with error", pg)
+	}
+	if c.Ready() {
+		t.Fatal("Probe should update the status seen by Ready")
+	}
+}
+
+func TestProbeHungCheck(t *testing.T) {
+	c := NewContainer(nil, time.Minute)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+	c.Register(Dependency{
+		Name: "postgres",
+		// 既不返回也不响应 ctx 取消的探活
+		Check: func(context.Context) error {
+			if calls.Add(1) > 1 {
+				<-release
+			}
+			return nil
+		},
+	})
+
+	// Check 未结束前的探活复用同一个 Check，不再启动新的
+	for i := 0; i < 5; i++ {
+		statuses, _ := c.Probe(context.Background(), 20*time.Millisecond)
+		if s := statuses[0]; s.Healthy || s.Error != context.DeadlineExceeded.Error() {
+			t.Fatalf("postgres = %+v, want unhealthy after timeout", s)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("Check called %d times, want 2 (register and one shared probe)", got)
+	}
+}
@@ -0,0 +1,148 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	StatusPass = "PASS"
+	StatusFail = "FAIL"
+	StatusSkip = "SKIP"
+)
+
+const defaultCheckTimeout = 10 * time.Second
+
+// CheckFunc 检查函数，返回写入报告的说明；返回 error 表示检查不通过
+type CheckFunc func(ctx context.Context) (string, error)
+
+// Result 单项检查结果
+type Result struct {
+	Name   string
+	Status string
+	Detail string
+	Cost   time.Duration
+}
+
+// Doctor 依次执行部署前检查并汇总为报告，用于 CI/CD 发布前的门禁和新环境的排查
+//
+// 检查按调用顺序同步执行，后面的检查依赖前面的结果时(如表结构依赖数据库连接)，由调用方根据 Run 的返回值决定执行或 Skip。
+type Doctor struct {
+	timeout time.Duration
+	results []Result
+}
+
+// New 创建检查器，timeout 为单项检查的超时时间
+func New(timeout time.Duration) *Doctor {
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	return &Doctor{timeout: timeout}
+}
+
+// Run 执行一项检查并记录结果，返回是否通过
+func (d *Doctor) Run(name string, check CheckFunc) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check(ctx)
+	result := Result{
+		Name:   name,
+		Status: StatusPass,
+		Detail: detail,
+		Cost:   time.Since(start),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+	}
+	d.results = append(d.results, result)
+	return err == nil
+}
+
+// Skip 记录一项未执行的检查，reason 说明跳过的原因
+func (d *Doctor) Skip(name, reason string) {
+	d.results = append(d.results, Result{
+		Name:   name,
+		Status: StatusSkip,
+		Detail: reason,
+	})
+}
+
+// Results 已记录的检查结果，按执行顺序
+func (d *Doctor) Results() []Result {
+	return d.results
+}
+
+// Failed 未通过的检查数
+func (d *Doctor) Failed() int {
+	failed := 0
+	for _, r := range d.results {
+		if r.Status == StatusFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// WriteReport 以表格形式输出检查结果和汇总
+func (d *Doctor) WriteReport(w io.Writer) error {
+	passed, skipped := 0, 0
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tCOST\tDETAIL")
+	for _, r := range d.results {
+		switch r.Status {
+		case StatusPass:
+			passed++
+		case StatusSkip:
+			skipped++
+		}
+		cost := "-"
+		if r.Status != StatusSkip {
+			cost = r.Cost.Round(time.Millisecond).String()
+		}
+		// 多行错误信息合并为一行，避免打乱表格
+		detail := strings.Join(strings.Fields(r.Detail), " ")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Name, r.Status, cost, detail)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d passed, %d failed, %d skipped\n", passed, d.Failed(), skipped)
+	return err
+}
+
+// WritableDir 检查目录可写: 目录不存在时按上传文件的方式创建，再写入并删除一个临时文件
+func WritableDir(dir string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("directory is not configured")
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return "", err
+	}
+	name := f.Name()
+	_, err = f.WriteString("ok")
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if removeErr := os.Remove(name); err == nil {
+		err = removeErr
+	}
+	if err != nil {
+		return "", err
+	}
+	return dir + " is writable", nil
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"gin-app-start/internal/model"
+)
+
+// CreateAPIKeyRequest 创建 API Key
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required,max=64" example:"nightly export"` // 用途说明，便于之后辨认和吊销
+}
+
+// APIKeyResponse API Key 信息，不含明文
+type APIKeyResponse struct {
+	ID         uint       `json:"id" example:"1"`
+	Name       string     `json:"name" example:"nightly export"`
+	Prefix     string     `json:"prefix" example:"gak_3f9a1c"` // 明文的前几位
+	CreatedAt  time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2023-01-02T00:00:00Z"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" example:"2023-01-03T00:00:00Z"`
+}
+
+// CreateAPIKeyResponse 新建的 API Key，明文只在创建时返回一次
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key" example:"gak_3f9a1c0Qm8tZr2YvLk7HdXcWb5NsEa"` // 请求时放在 X-API-Key 头中
+}
+
+// NewAPIKeyResponse 转换 API Key 模型
+func NewAPIKeyResponse(key *model.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
+
+// NewAPIKeyResponses 批量转换 API Key 模型
+func NewAPIKeyResponses(keys []*model.APIKey) []*APIKeyResponse {
+	res := make([]*APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		res = append(res, NewAPIKeyResponse(key))
+	}
+	return res
+}
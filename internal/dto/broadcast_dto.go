@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"gin-app-start/internal/model"
+)
+
+// CreateBroadcastRequest 创建定时群发
+type CreateBroadcastRequest struct {
+	Title       string    `json:"title" binding:"required,max=128" example:"Spring sale"`
+	Content     string    `json:"content" binding:"required,max=10000" example:"20% off for all VIP members"`
+	Channels    []string  `json:"channels" binding:"required,min=1,dive,oneof=in_app email" example:"in_app,email"`
+	Tags        []string  `json:"tags" binding:"required,min=1,max=20,dive,min=1,max=64" example:"vip,beta"`
+	Match       string    `json:"match" binding:"omitempty,oneof=any all" example:"any"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required" example:"2030-01-01T09:00:00+08:00"`
+}
+
+// BroadcastResponse 群发及其发送进度
+type BroadcastResponse struct {
+	ID          uint       `json:"id" example:"1"`
+	Title       string     `json:"title" example:"Spring sale"`
+	Content     string     `json:"content" example:"20% off for all VIP members"`
+	Channels    []string   `json:"channels" example:"in_app,email"`
+	Tags        []string   `json:"tags" example:"vip,beta"`
+	Match       string     `json:"match" example:"any"`
+	ScheduledAt time.Time  `json:"scheduled_at" example:"2030-01-01T09:00:00+08:00"`
+	Status      string     `json:"status" example:"running" enums:"pending,running,completed,cancelled,failed"`
+	Total       int64      `json:"total" example:"1000"`     // 分群用户数，开始发送时统计
+	Processed   int64      `json:"processed" example:"200"`  // 已处理的用户数
+	EmailSent   int64      `json:"email_sent" example:"180"` // 邮件发送成功数
+	EmailFailed int64      `json:"email_failed" example:"2"` // 邮件发送失败数
+	Error       string     `json:"error,omitempty" example:""`
+	StartedAt   *time.Time `json:"started_at,omitempty" example:"2030-01-01T09:00:01+08:00"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty" example:"2030-01-01T09:03:00+08:00"`
+	CreatedAt   time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewBroadcastResponse 将群发模型转换为响应结构
+func NewBroadcastResponse(broadcast *model.Broadcast) *BroadcastResponse {
+	if broadcast == nil {
+		return nil
+	}
+
+	return &BroadcastResponse{
+		ID:          broadcast.ID,
+		Title:       broadcast.Title,
+		Content:     broadcast.Content,
+		Channels:    strings.Split(broadcast.Channels, ","),
+		Tags:        strings.Split(broadcast.Tags, ","),
+		Match:       broadcast.Match,
+		ScheduledAt: broadcast.ScheduledAt,
+		Status:      broadcast.Status,
+		Total:       broadcast.Total,
+		Processed:   broadcast.Processed,
+		EmailSent:   broadcast.EmailSent,
+		EmailFailed: broadcast.EmailFailed,
+		Error:       broadcast.Error,
+		StartedAt:   broadcast.StartedAt,
+		FinishedAt:  broadcast.FinishedAt,
+		CreatedAt:   broadcast.CreatedAt,
+	}
+}
+
+// NewBroadcastResponses 批量转换群发模型
+func NewBroadcastResponses(broadcasts []*model.Broadcast) []*BroadcastResponse {
+	res := make([]*BroadcastResponse, 0, len(broadcasts))
+	for _, broadcast := range broadcasts {
+		res = append(res, NewBroadcastResponse(broadcast))
+	}
+	return res
+}
+
+// NotificationResponse 站内信
+type NotificationResponse struct {
+	ID        uint       `json:"id" example:"1"`
+	Title     string     `json:"title" example:"Spring sale"`
+	Content   string     `json:"content" example:"20% off for all VIP members"`
+	ReadAt    *time.Time `json:"read_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	CreatedAt time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewNotificationResponses 批量转换站内信模型
+func NewNotificationResponses(notifications []*model.Notification) []*NotificationResponse {
+	res := make([]*NotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		res = append(res, &NotificationResponse{
+			ID:        n.ID,
+			Title:     n.Title,
+			Content:   n.Content,
+			ReadAt:    n.ReadAt,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	return res
+}
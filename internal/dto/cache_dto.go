@@ -0,0 +1,14 @@
+package dto
+
+// CacheKeyResponse represents a cache key with its type, ttl and contents
+type CacheKeyResponse struct {
+	Key   string      `json:"key" example:"order:EC20231215123456"`
+	Type  string      `json:"type" example:"string"`
+	TTL   int64       `json:"ttl" example:"1800"` // 剩余过期时间，单位秒；-1 表示未设置过期时间
+	Value interface{} `json:"value"`
+}
+
+// WarmCacheResponse represents the result of a cache re-warm
+type WarmCacheResponse struct {
+	Warmed int `json:"warmed" example:"3"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"gin-app-start/internal/model"
+)
+
+// NearbyQuery 附近查询条件
+type NearbyQuery struct {
+	Latitude  *float64 `form:"latitude" binding:"required,gte=-90,lte=90" example:"31.2304"`
+	Longitude *float64 `form:"longitude" binding:"required,gte=-180,lte=180" example:"121.4737"`
+	Radius    float64  `form:"radius" binding:"omitempty,gt=0,lte=50" example:"5"`   // 半径，单位公里，默认 5
+	Limit     int      `form:"limit" binding:"omitempty,min=1,max=100" example:"20"` // 最多返回条数，默认 20
+}
+
+// CreateStoreRequest 创建门店
+type CreateStoreRequest struct {
+	Name      string   `json:"name" binding:"required,max=128" example:"People's Square Store"`
+	Address   string   `json:"address" binding:"omitempty,max=256" example:"120 Nanjing Road, Shanghai"`
+	Latitude  *float64 `json:"latitude" binding:"required,gte=-90,lte=90" example:"31.2304"`
+	Longitude *float64 `json:"longitude" binding:"required,gte=-180,lte=180" example:"121.4737"`
+}
+
+// StoreResponse 门店
+type StoreResponse struct {
+	ID        uint      `json:"id" example:"1"`
+	Name      string    `json:"name" example:"People's Square Store"`
+	Address   string    `json:"address" example:"120 Nanjing Road, Shanghai"`
+	Latitude  float64   `json:"latitude" example:"31.2304"`
+	Longitude float64   `json:"longitude" example:"121.4737"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewStoreResponse 将门店模型转换为响应结构
+func NewStoreResponse(store *model.Store) *StoreResponse {
+	if store == nil {
+		return nil
+	}
+
+	return &StoreResponse{
+		ID:        store.ID,
+		Name:      store.Name,
+		Address:   store.Address,
+		Latitude:  store.Latitude,
+		Longitude: store.Longitude,
+		CreatedAt: store.CreatedAt,
+	}
+}
+
+// NearbyStoreResponse 附近的门店及距离
+type NearbyStoreResponse struct {
+	StoreResponse
+	Distance float64 `json:"distance" example:"1.25"` // 距离，单位公里
+}
+
+// NearbyOrderResponse 附近的订单及距离
+type NearbyOrderResponse struct {
+	OrderResponse
+	Distance float64 `json:"distance" example:"1.25"` // 距离，单位公里
+}
+
+// GeoRebuildResponse 重建 GEO 索引的结果
+type GeoRebuildResponse struct {
+	Stores int `json:"stores" example:"12"`  // 写入索引的门店数
+	Orders int `json:"orders" example:"860"` // 写入索引的订单数
+}
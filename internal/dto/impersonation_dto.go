@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"gin-app-start/internal/impersonation"
+	"gin-app-start/pkg/hashid"
+)
+
+// ImpersonateRequest 管理员代入用户，原因写入审计日志
+type ImpersonateRequest struct {
+	UserID hashid.ID `json:"user_id" binding:"required" swaggertype:"integer" example:"12"` // 启用 id_obfuscation 时为编码后的字符串
+	Reason string    `json:"reason" binding:"required,max=256" example:"Debug ticket #1024: order list is empty"`
+}
+
+// ImpersonationResponse 当前的代入状态
+type ImpersonationResponse struct {
+	UserID    hashid.ID `json:"user_id" swaggertype:"integer" example:"12"`
+	Username  string    `json:"username" example:"john_doe"`
+	AdminName string    `json:"admin_name" example:"admin"`
+	Reason    string    `json:"reason" example:"Debug ticket #1024: order list is empty"`
+	StartedAt time.Time `json:"started_at" example:"2023-01-01T00:00:00Z"`
+	ExpiresAt time.Time `json:"expires_at" example:"2023-01-01T00:30:00Z"`
+}
+
+func NewImpersonationResponse(s *impersonation.Session) *ImpersonationResponse {
+	return &ImpersonationResponse{
+		UserID:    hashid.ID(s.UserID),
+		Username:  s.Username,
+		AdminName: s.AdminName,
+		Reason:    s.Reason,
+		StartedAt: time.Unix(s.StartedAt, 0),
+		ExpiresAt: time.Unix(s.ExpiresAt, 0),
+	}
+}
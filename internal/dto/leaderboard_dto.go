@@ -0,0 +1,24 @@
+package dto
+
+import "gin-app-start/pkg/hashid"
+
+// LeaderboardEntry 排行榜中的一名用户
+type LeaderboardEntry struct {
+	Rank     int64     `json:"rank" example:"1"` // 名次，从 1 开始
+	UserID   hashid.ID `json:"user_id" swaggertype:"integer" example:"1"`
+	Username string    `json:"username,omitempty" example:"john_doe"`
+	Score    float64   `json:"score" example:"1999.5"` // top_spenders 为消费总额，most_active 为订单数
+}
+
+// LeaderboardResponse 排行榜及当前用户的名次
+type LeaderboardResponse struct {
+	Name    string              `json:"name" example:"top_spenders"`
+	Entries []*LeaderboardEntry `json:"entries"`
+	Me      *LeaderboardEntry   `json:"me"` // 当前用户不在榜上时为 null
+}
+
+// LeaderboardRebuildResponse 重建排行榜的结果
+type LeaderboardRebuildResponse struct {
+	Name  string `json:"name" example:"top_spenders"`
+	Users int    `json:"users" example:"1024"` // 上榜用户数
+}
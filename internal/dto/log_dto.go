@@ -0,0 +1,20 @@
+package dto
+
+import "encoding/json"
+
+// LogTailQuery 实时日志的查询参数
+type LogTailQuery struct {
+	Lines *int   `form:"lines" binding:"omitempty,min=0,max=10000" example:"100"`              // 连接后先发送的最近日志条数，缺省为 100
+	Level string `form:"level" binding:"omitempty,oneof=debug info warn error" example:"warn"` // 只发送不低于该级别的日志，缺省为全部
+}
+
+// RecentErrorsQuery 最近错误日志的查询参数
+type RecentErrorsQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=10000" example:"20"` // 返回的条数，缺省为全部保留的日志
+}
+
+// RecentErrorsResponse 内存中保留的最近错误日志，按时间从新到旧排列
+type RecentErrorsResponse struct {
+	Enabled bool              `json:"enabled" example:"true"` // 未开启 log.recent_errors 时为 false，Entries 为空
+	Entries []json.RawMessage `json:"entries" swaggertype:"array,object"`
+}
@@ -1,13 +1,29 @@
 package dto
 
-import "gin-app-start/internal/model"
+import (
+	"time"
+
+	"gin-app-start/internal/model"
+	"gin-app-start/pkg/hashid"
+)
 
 // CreateOrderRequest represents the request to create a new order
 type CreateOrderRequest struct {
-	UserId      uint    `json:"user_id" binding:"omitempty" example:"1"`
-	Username    string  `json:"username" binding:"required" example:"John Doe"`
-	TotalPrice  float64 `json:"total_price" binding:"required" example:"99.99"`
-	Description string  `json:"description" binding:"omitempty" example:"Order for John Doe"`
+	UserId      hashid.ID `json:"user_id" binding:"omitempty" swaggertype:"integer" example:"1"` // 已忽略，下单用户由 username 确定
+	Username    string    `json:"username" binding:"required" example:"John Doe"`
+	TotalPrice  float64   `json:"total_price" binding:"required_without=Items" example:"99.99"` // 提供 items 时按明细计算，不能填写
+	Description string    `json:"description" binding:"omitempty" example:"Order for John Doe"`
+	OrderType   string    `json:"order_type" binding:"omitempty,max=32" example:"wholesale"` // 订单类型，决定订单号格式，为空时使用默认格式
+
+	// 收货地坐标，可选，需同时提供
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,gte=-90,lte=90" example:"31.2304"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,gte=-180,lte=180" example:"121.4737"`
+
+	// 以组织名义下单，需为组织成员，组织成员均可查看该订单
+	OrganizationID *uint `json:"organization_id" binding:"omitempty,gt=0" example:"1"`
+
+	// 订单明细，可选；与订单在同一事务中写入并扣减库存，任一商品库存不足时订单不会创建
+	Items []*AddOrderItemRequest `json:"items" binding:"omitempty,max=100,dive"`
 }
 
 // GetImage represents the request to get image
@@ -18,11 +34,35 @@ type GetImage struct {
 
 // UpdateOrderRequest represents the request to update order information
 type UpdateOrderRequest struct {
-	Username    string  `json:"username" binding:"required" example:"John Doe"`
-	OrderNumber string  `json:"order_number" binding:"required" example:"123456"`
-	TotalPrice  float64 `json:"total_price" binding:"omitempty" example:"99.99"`
-	Description string  `json:"description" binding:"omitempty" example:"Order for John Doe"`
-	Status      int8    `json:"status" binding:"omitempty,oneof=0 1" example:"1"`
+	Username    string            `json:"username" binding:"required" example:"John Doe"`
+	OrderNumber string            `json:"order_number" binding:"required" example:"123456"`
+	TotalPrice  float64           `json:"total_price" binding:"omitempty" example:"99.99"` // 订单已有明细时总价按明细计算，不能修改
+	Description string            `json:"description" binding:"omitempty" example:"Order for John Doe"`
+	Status      model.OrderStatus `json:"status" binding:"omitempty,enum" swaggertype:"string" enums:"pending,paid,shipped,completed,cancelled" example:"paid"` // 为空时不修改
+}
+
+// PatchOrderRequest 部分更新订单，只修改请求中出现的字段，可以修改为零值(如 total_price 为 0、清空 description)
+// 字段为 null 时与未出现相同，不修改
+type PatchOrderRequest struct {
+	TotalPrice  *float64           `json:"total_price" binding:"omitempty,gte=0" example:"0"` // 订单已有明细时总价按明细计算，不能修改
+	Description *string            `json:"description" example:""`
+	Status      *model.OrderStatus `json:"status" binding:"omitempty,enum" swaggertype:"string" enums:"pending,paid,shipped,completed,cancelled" example:"cancelled"`
+}
+
+// BatchGetOrdersRequest represents the request to get several orders by order number at once
+type BatchGetOrdersRequest struct {
+	OrderNumbers []string `json:"order_numbers" binding:"required,min=1,max=100,dive,required,max=64" example:"EC20231215123456,EC20231215654321"`
+}
+
+// BatchGetOrdersResponse 批量查询结果，不存在和无权查看的订单号都在 missing 中
+type BatchGetOrdersResponse struct {
+	Orders  []*OrderResponse `json:"orders"`
+	Missing []string         `json:"missing"`
+}
+
+// OrderProjectionRebuildResponse 重建订单列表读模型的结果
+type OrderProjectionRebuildResponse struct {
+	Orders int `json:"orders" example:"120000"` // 写入读模型的订单数
 }
 
 // DeleteOrderRequest represents the request to delete an order
@@ -31,8 +71,117 @@ type DeleteOrderRequest struct {
 	OrderNumber string `json:"order_number" binding:"required" example:"123456"`
 }
 
+// CreateOrderNoteRequest represents the request to append a note to an order
+type CreateOrderNoteRequest struct {
+	OrderNumber string `json:"order_number" binding:"required" example:"EC20231215123456"`
+	Content     string `json:"content" binding:"required,max=2000" example:"Please deliver after 6pm"`
+	Internal    bool   `json:"internal" example:"false"` // 内部备注仅管理员可写、可见
+}
+
+// OrderNoteResponse represents an order note returned to clients
+type OrderNoteResponse struct {
+	ID         uint      `json:"id" example:"1"`
+	AuthorID   hashid.ID `json:"author_id" swaggertype:"integer" example:"1"`
+	AuthorName string    `json:"author_name" example:"john_doe"`
+	Content    string    `json:"content" example:"Please deliver after 6pm"`
+	Internal   bool      `json:"internal" example:"false"`
+	CreatedAt  time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdateAt   time.Time `json:"update_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewOrderNoteResponse 将订单备注模型转换为响应结构
+func NewOrderNoteResponse(note *model.OrderNote) *OrderNoteResponse {
+	if note == nil {
+		return nil
+	}
+
+	return &OrderNoteResponse{
+		ID:         note.ID,
+		AuthorID:   hashid.ID(note.AuthorID),
+		AuthorName: note.AuthorName,
+		Content:    note.Content,
+		Internal:   note.Internal,
+		CreatedAt:  note.CreatedAt,
+		UpdateAt:   note.UpdatedAt,
+	}
+}
+
+// OrderResponse represents the order information returned to clients
+type OrderResponse struct {
+	ID          uint              `json:"id" example:"1"`
+	OrderNumber string            `json:"order_number" example:"EC20231215123456"`
+	UserID      hashid.ID         `json:"user_id" swaggertype:"integer" example:"1"`
+	Username    string            `json:"username" example:"john_doe"`
+	TotalPrice  float64           `json:"total_price" example:"100.00"`
+	Description string            `json:"description" example:"Order for product A"`
+	Status      model.OrderStatus `json:"status" swaggertype:"string" enums:"pending,paid,shipped,completed,cancelled" example:"pending"`
+	Latitude    *float64          `json:"latitude,omitempty" example:"31.2304"`
+	Longitude   *float64          `json:"longitude,omitempty" example:"121.4737"`
+	CreatedAt   time.Time         `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdateAt    time.Time         `json:"update_at" example:"2023-01-01T00:00:00Z"`
+
+	OrganizationID *uint `json:"organization_id,omitempty" example:"1"` // 个人订单为空
+
+	Notes []*OrderNoteResponse `json:"notes,omitempty"` // 仅订单详情返回，列表不加载备注
+
+	User *OrderUserResponse `json:"user,omitempty"` // 下单用户，仅管理员按用户搜索订单时返回
+}
+
+// OrderUserResponse 下单用户的联系方式
+type OrderUserResponse struct {
+	Email string `json:"email" example:"john@example.com"`
+	Phone string `json:"phone" example:"13800138000"`
+}
+
+// NewOrderResponse 将订单模型转换为响应结构
+func NewOrderResponse(order *model.Order) *OrderResponse {
+	if order == nil {
+		return nil
+	}
+
+	var notes []*OrderNoteResponse
+	for i := range order.Notes {
+		notes = append(notes, NewOrderNoteResponse(&order.Notes[i]))
+	}
+
+	return &OrderResponse{
+		ID:          order.ID,
+		OrderNumber: order.OrderNumber,
+		UserID:      hashid.ID(order.UserID),
+		Username:    order.Username,
+		TotalPrice:  order.TotalPrice,
+		Description: order.Description,
+		Status:      order.Status,
+		Latitude:    order.Latitude,
+		Longitude:   order.Longitude,
+		CreatedAt:   order.CreatedAt,
+		UpdateAt:    order.UpdatedAt,
+
+		OrganizationID: order.OrganizationID,
+		Notes:          notes,
+	}
+}
+
+// NewOrderResponses 批量转换订单模型
+func NewOrderResponses(orders []*model.Order) []*OrderResponse {
+	res := make([]*OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		res = append(res, NewOrderResponse(order))
+	}
+	return res
+}
+
+// NewOrderResponseWithUser 订单响应附带下单用户的联系方式
+func NewOrderResponseWithUser(order *model.Order, email, phone string) *OrderResponse {
+	res := NewOrderResponse(order)
+	res.User = &OrderUserResponse{Email: email, Phone: phone}
+	return res
+}
+
 // ListOrdersResponse represents the response to list orders
 type ListOrdersResponse struct {
-	Orders []*model.Order `json:"orders"`
-	Total  int64          `json:"total"`
+	Orders []*OrderResponse `json:"orders"`
+	Total  int64            `json:"total"`
+
+	NextCursor string `json:"next_cursor,omitempty" example:"NDI"` // 游标分页时下一页的游标，没有下一页时为空，不统计 total
 }
@@ -0,0 +1,62 @@
+package dto
+
+import "gin-app-start/internal/model"
+
+// OrderItemsURI 路径中的订单号
+type OrderItemsURI struct {
+	OrderNumber string `uri:"order_number" binding:"required,max=64" example:"EC20231215123456"`
+}
+
+// OrderItemURI 路径中的订单号和明细ID
+type OrderItemURI struct {
+	OrderNumber string `uri:"order_number" binding:"required,max=64" example:"EC20231215123456"`
+	ItemID      uint   `uri:"item_id" binding:"required,gt=0" example:"1"`
+}
+
+// AddOrderItemRequest 向订单加入商品，订单中已有该商品时累加数量
+type AddOrderItemRequest struct {
+	ProductID string `json:"product_id" binding:"required,max=64" example:"SKU-10001"`
+	Quantity  int    `json:"quantity" binding:"required,min=1,max=9999" example:"2"`
+}
+
+// UpdateOrderItemRequest 修改明细的数量
+type UpdateOrderItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1,max=9999" example:"3"`
+}
+
+// OrderItemResponse 订单明细
+type OrderItemResponse struct {
+	ID        uint    `json:"id" example:"1"`
+	ProductID string  `json:"product_id" example:"SKU-10001"`
+	Name      string  `json:"name" example:"Coffee mug"`
+	UnitPrice float64 `json:"unit_price" example:"25.00"` // 加入订单时的单价
+	Quantity  int     `json:"quantity" example:"2"`
+	Subtotal  float64 `json:"subtotal" example:"50.00"`
+}
+
+// OrderItemsResponse 订单的全部明细和按明细计算的总价，修改明细后也返回该结构
+type OrderItemsResponse struct {
+	OrderNumber string               `json:"order_number" example:"EC20231215123456"`
+	TotalPrice  float64              `json:"total_price" example:"50.00"`
+	Items       []*OrderItemResponse `json:"items"`
+}
+
+// NewOrderItemsResponse 将订单和明细模型转换为响应结构
+func NewOrderItemsResponse(order *model.Order, items []*model.OrderItem) *OrderItemsResponse {
+	res := &OrderItemsResponse{
+		OrderNumber: order.OrderNumber,
+		TotalPrice:  order.TotalPrice,
+		Items:       make([]*OrderItemResponse, 0, len(items)),
+	}
+	for _, item := range items {
+		res.Items = append(res.Items, &OrderItemResponse{
+			ID:        item.ID,
+			ProductID: item.ProductID,
+			Name:      item.Name,
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+			Subtotal:  item.Subtotal(),
+		})
+	}
+	return res
+}
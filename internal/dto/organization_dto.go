@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"time"
+
+	"gin-app-start/internal/model"
+	"gin-app-start/pkg/hashid"
+)
+
+// CreateOrganizationRequest 创建组织，创建者成为所有者
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=128" example:"Acme Inc."`
+}
+
+// OrganizationResponse 组织信息
+type OrganizationResponse struct {
+	ID        uint      `json:"id" example:"1"`
+	Name      string    `json:"name" example:"Acme Inc."`
+	OwnerID   hashid.ID `json:"owner_id" swaggertype:"integer" example:"1"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewOrganizationResponse 将组织模型转换为响应结构
+func NewOrganizationResponse(org *model.Organization) *OrganizationResponse {
+	if org == nil {
+		return nil
+	}
+
+	return &OrganizationResponse{
+		ID:        org.ID,
+		Name:      org.Name,
+		OwnerID:   hashid.ID(org.OwnerID),
+		CreatedAt: org.CreatedAt,
+	}
+}
+
+// OrganizationMemberResponse 组织成员
+type OrganizationMemberResponse struct {
+	UserID   hashid.ID `json:"user_id" swaggertype:"integer" example:"2"`
+	Username string    `json:"username" example:"john_doe"`
+	Role     string    `json:"role" example:"member"`
+	JoinedAt time.Time `json:"joined_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewOrganizationMemberResponse 将组织成员模型转换为响应结构
+func NewOrganizationMemberResponse(member *model.OrganizationMember) *OrganizationMemberResponse {
+	if member == nil {
+		return nil
+	}
+
+	return &OrganizationMemberResponse{
+		UserID:   hashid.ID(member.UserID),
+		Username: member.Username,
+		Role:     member.Role,
+		JoinedAt: member.CreatedAt,
+	}
+}
+
+// InviteMemberRequest 邀请用户加入组织
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email,max=128" example:"john@example.com"`
+	Role  string `json:"role" binding:"omitempty,oneof=owner member" example:"member"` // 默认为 member
+}
+
+// InvitationResponse 组织邀请
+// Token 只返回给邀请人，未配置邮件时由邀请人转交被邀请人
+type InvitationResponse struct {
+	ID             uint      `json:"id" example:"1"`
+	OrganizationID uint      `json:"organization_id" example:"1"`
+	Email          string    `json:"email" example:"john@example.com"`
+	Role           string    `json:"role" example:"member"`
+	Token          string    `json:"token" example:"3f2a9c0d4b5e6f708192a3b4c5d6e7f8"`
+	ExpiresAt      time.Time `json:"expires_at" example:"2023-01-08T00:00:00Z"`
+}
+
+// NewInvitationResponse 将邀请模型转换为响应结构
+func NewInvitationResponse(invitation *model.OrganizationInvitation) *InvitationResponse {
+	if invitation == nil {
+		return nil
+	}
+
+	return &InvitationResponse{
+		ID:             invitation.ID,
+		OrganizationID: invitation.OrganizationID,
+		Email:          invitation.Email,
+		Role:           invitation.Role,
+		Token:          invitation.Token,
+		ExpiresAt:      invitation.ExpiresAt,
+	}
+}
+
+// AcceptInvitationRequest 接受组织邀请
+type AcceptInvitationRequest struct {
+	Token string `json:"token" binding:"required,max=64" example:"3f2a9c0d4b5e6f708192a3b4c5d6e7f8"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"gin-app-start/internal/model"
+)
+
+// CreateProductRequest 创建商品
+type CreateProductRequest struct {
+	ProductID string  `json:"product_id" binding:"required,max=64" example:"SKU-10001"` // 商品 SKU，收藏、浏览计数等接口中的商品ID
+	Name      string  `json:"name" binding:"required,max=128" example:"Coffee mug"`
+	Price     float64 `json:"price" binding:"required,gt=0" example:"25.00"`
+	Stock     *int    `json:"stock" binding:"omitempty,min=0" example:"100"` // 库存，不填表示不限库存
+}
+
+// ProductListQuery 商品列表分页参数
+type ProductListQuery struct {
+	Page     int `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100" example:"10"`
+}
+
+// UpdateProductRequest 修改商品，为空的字段不修改；已加入订单的明细仍按加入时的名称和单价
+type UpdateProductRequest struct {
+	Name  string  `json:"name" binding:"omitempty,max=128" example:"Coffee mug"`
+	Price float64 `json:"price" binding:"omitempty,gt=0" example:"29.00"`
+	Stock *int    `json:"stock" binding:"omitempty,min=0" example:"80"` // 重新设置库存
+}
+
+// ProductResponse 商品信息
+type ProductResponse struct {
+	ProductID string    `json:"product_id" example:"SKU-10001"`
+	Name      string    `json:"name" example:"Coffee mug"`
+	Price     float64   `json:"price" example:"25.00"`
+	Stock     *int      `json:"stock" example:"100"` // 为 null 表示不限库存
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewProductResponse 将商品模型转换为响应结构
+func NewProductResponse(product *model.Product) *ProductResponse {
+	return &ProductResponse{
+		ProductID: product.SKU,
+		Name:      product.Name,
+		Price:     product.Price,
+		Stock:     product.Stock,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+	}
+}
+
+// NewProductResponses 批量转换商品模型
+func NewProductResponses(products []*model.Product) []*ProductResponse {
+	res := make([]*ProductResponse, 0, len(products))
+	for _, product := range products {
+		res = append(res, NewProductResponse(product))
+	}
+	return res
+}
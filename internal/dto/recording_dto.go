@@ -0,0 +1,23 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+)
+
+// Recording represents a recorded request/response pair
+type Recording struct {
+	TraceID        string      `json:"trace_id" example:"5d3c1a2b9e8f7a6b5c4d"`
+	Method         string      `json:"method" example:"GET"`
+	Route          string      `json:"route" example:"/api/v1/orders"`
+	URL            string      `json:"url" example:"/api/v1/orders?page=1"`
+	ClientIP       string      `json:"client_ip" example:"127.0.0.1"`
+	RequestHeader  http.Header `json:"request_header"`
+	RequestBody    string      `json:"request_body"`
+	HttpCode       int         `json:"http_code" example:"200"`
+	ResponseHeader http.Header `json:"response_header"`
+	ResponseBody   string      `json:"response_body"`
+	Truncated      bool        `json:"truncated"` // 请求体或响应体超出 max_body_size 被截断
+	CostSeconds    float64     `json:"cost_seconds" example:"0.012"`
+	RecordedAt     time.Time   `json:"recorded_at" example:"2023-01-01T00:00:00Z"`
+}
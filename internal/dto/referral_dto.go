@@ -0,0 +1,32 @@
+package dto
+
+import "gin-app-start/pkg/hashid"
+
+// ReferralResponse 当前用户的邀请信息
+type ReferralResponse struct {
+	InviteCode   string `json:"invite_code" example:"K7QX2M9P"`
+	InvitedCount int64  `json:"invited_count" example:"3"` // 通过该邀请码注册的用户数
+	Credits      int64  `json:"credits" example:"3"`       // 累计获得的邀请奖励
+}
+
+// ReferralStatsResponse 邀请转化统计
+type ReferralStatsResponse struct {
+	IssuedCodes   int64 `json:"issued_codes" example:"120"`  // 已生成邀请码的用户数
+	Referrers     int64 `json:"referrers" example:"30"`      // 至少成功邀请一人的用户数
+	ReferredUsers int64 `json:"referred_users" example:"75"` // 通过邀请码注册的用户数
+
+	SignupAttempts int64 `json:"signup_attempts" example:"90"` // 携带邀请码的注册请求数，含无效邀请码
+	Signups        int64 `json:"signups" example:"75"`         // 携带有效邀请码并注册成功的请求数
+
+	CodeConversionRate   float64 `json:"code_conversion_rate" example:"0.25"`    // referrers / issued_codes
+	SignupConversionRate float64 `json:"signup_conversion_rate" example:"0.833"` // signups / signup_attempts
+
+	TopReferrers []*ReferrerStat `json:"top_referrers"`
+}
+
+// ReferrerStat 单个邀请人的邀请人数
+type ReferrerStat struct {
+	UserID   hashid.ID `json:"user_id" swaggertype:"integer" example:"1"`
+	Username string    `json:"username" example:"john_doe"`
+	Invited  int64     `json:"invited" example:"12"`
+}
@@ -0,0 +1,55 @@
+package dto
+
+import "time"
+
+// LockResponse 一条登录失败锁定记录
+type LockResponse struct {
+	Kind      string    `json:"kind" example:"account"` // account 或 ip
+	Subject   string    `json:"subject" example:"john_doe"`
+	Failures  int64     `json:"failures" example:"5"` // 触发锁定时的失败次数
+	LockedAt  time.Time `json:"locked_at" example:"2023-01-01T00:00:00Z"`
+	ExpiresAt time.Time `json:"expires_at" example:"2023-01-01T00:15:00Z"`
+}
+
+// RateLimitedResponse 当前被限速的客户端
+type RateLimitedResponse struct {
+	IP         string    `json:"ip" example:"203.0.113.7"`
+	LastAccess time.Time `json:"last_access" example:"2023-01-01T00:00:00Z"`
+}
+
+// BlockedResponse 当前被锁定的账号、IP 和被限速的客户端
+type BlockedResponse struct {
+	LockoutEnabled bool                   `json:"lockout_enabled" example:"true"` // 未启用登录锁定或 Redis 未启用时为 false
+	Locks          []*LockResponse        `json:"locks"`
+	RateLimited    []*RateLimitedResponse `json:"rate_limited"` // 仅当前实例
+}
+
+// UnblockRequest 手动解除锁定或限速
+type UnblockRequest struct {
+	Kind     string `json:"kind" binding:"required,oneof=account ip rate_limit" example:"account"`
+	Subject  string `json:"subject" binding:"required,max=255" example:"john_doe"` // 用户名或 IP
+	Operator string `json:"operator" binding:"required,max=64" example:"alice"`    // 执行解锁的管理员，记录在审计日志中
+	Reason   string `json:"reason" binding:"max=255" example:"verified by phone"`
+}
+
+// RequestStatsQuery 按用户的请求统计列表
+type RequestStatsQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=1000" example:"20"` // 默认 20
+}
+
+// UserRequestStatsResponse 单个用户的请求统计
+type UserRequestStatsResponse struct {
+	Username   string     `json:"username" example:"john_doe"`
+	Concurrent int64      `json:"concurrent" example:"3"`                             // 正在处理的请求数(所有实例)
+	Requests   int64      `json:"requests" example:"120"`                             // 滑动窗口内的请求数，包括被拒绝的请求
+	Rejected   int64      `json:"rejected" example:"4"`                               // 滑动窗口内因超出并发上限被拒绝的请求数
+	Limit      int        `json:"limit" example:"5"`                                  // 并发上限，0 表示不限制
+	LastSeen   *time.Time `json:"last_seen,omitempty" example:"2023-01-01T00:00:00Z"` // 最近一次请求的时间，窗口内没有请求时为空
+}
+
+// RequestStatsResponse 按用户的请求统计
+type RequestStatsResponse struct {
+	Enabled bool                        `json:"enabled" example:"true"` // 未启用 concurrency.per_user 或 Redis 未启用时为 false
+	Window  int                         `json:"window" example:"60"`    // 请求数的滑动窗口长度，单位秒
+	Users   []*UserRequestStatsResponse `json:"users"`                  // 按并发数、请求数从高到低排列
+}
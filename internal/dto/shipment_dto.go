@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"time"
+
+	"gin-app-start/internal/model"
+)
+
+// CreateShipmentRequest 为订单登记一个包裹
+type CreateShipmentRequest struct {
+	OrderNumber    string `json:"order_number" binding:"required" example:"EC20231215123456"`
+	Carrier        string `json:"carrier" binding:"required,max=32" example:"sf"` // 承运商代码，与回调地址和 shipment.webhook_secrets 中的名称一致
+	TrackingNumber string `json:"tracking_number" binding:"required,max=64" example:"SF1234567890"`
+}
+
+// ShipmentEventRequest 一条物流事件
+type ShipmentEventRequest struct {
+	Status      string    `json:"status" binding:"required,oneof=pending in_transit out_for_delivery delivered exception returned" example:"in_transit"`
+	Location    string    `json:"location" binding:"omitempty,max=128" example:"Shenzhen"`
+	Description string    `json:"description" binding:"omitempty,max=512" example:"Departed from sorting center"`
+	OccurredAt  time.Time `json:"occurred_at" binding:"required" example:"2023-01-01T08:00:00+08:00"`
+}
+
+// AddShipmentEventsRequest 管理端手工录入物流事件
+type AddShipmentEventsRequest struct {
+	Events []ShipmentEventRequest `json:"events" binding:"required,min=1,max=100,dive"`
+}
+
+// ShipmentWebhookRequest 承运商推送的物流事件，请求体需要用 X-Signature 头签名
+type ShipmentWebhookRequest struct {
+	TrackingNumber string                 `json:"tracking_number" binding:"required,max=64" example:"SF1234567890"`
+	Events         []ShipmentEventRequest `json:"events" binding:"required,min=1,max=100,dive"`
+}
+
+// NewShipmentEvents 将请求中的物流事件转换为模型
+func NewShipmentEvents(events []ShipmentEventRequest) []*model.ShipmentEvent {
+	res := make([]*model.ShipmentEvent, 0, len(events))
+	for _, event := range events {
+		res = append(res, &model.ShipmentEvent{
+			Status:      event.Status,
+			Location:    event.Location,
+			Description: event.Description,
+			OccurredAt:  event.OccurredAt,
+		})
+	}
+	return res
+}
+
+// ShipmentWebhookResponse 回调处理结果
+type ShipmentWebhookResponse struct {
+	Accepted int64 `json:"accepted" example:"2"` // 新记录的事件数，重复推送的事件不计入
+}
+
+// ShipmentEventResponse 物流事件
+type ShipmentEventResponse struct {
+	Status      string    `json:"status" example:"in_transit" enums:"pending,in_transit,out_for_delivery,delivered,exception,returned"`
+	Location    string    `json:"location" example:"Shenzhen"`
+	Description string    `json:"description" example:"Departed from sorting center"`
+	OccurredAt  time.Time `json:"occurred_at" example:"2023-01-01T08:00:00+08:00"`
+}
+
+// ShipmentResponse 包裹及其物流轨迹
+type ShipmentResponse struct {
+	ID             uint                     `json:"id" example:"1"`
+	OrderID        uint                     `json:"order_id" example:"1"`
+	Carrier        string                   `json:"carrier" example:"sf"`
+	TrackingNumber string                   `json:"tracking_number" example:"SF1234567890"`
+	Status         string                   `json:"status" example:"in_transit" enums:"pending,in_transit,out_for_delivery,delivered,exception,returned"`
+	LastEventAt    *time.Time               `json:"last_event_at,omitempty" example:"2023-01-01T08:00:00+08:00"`
+	CreatedAt      time.Time                `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	Events         []*ShipmentEventResponse `json:"events"` // 按发生时间排列
+}
+
+// NewShipmentResponse 将包裹模型转换为响应结构
+func NewShipmentResponse(shipment *model.Shipment) *ShipmentResponse {
+	if shipment == nil {
+		return nil
+	}
+
+	events := make([]*ShipmentEventResponse, 0, len(shipment.Events))
+	for _, event := range shipment.Events {
+		events = append(events, &ShipmentEventResponse{
+			Status:      event.Status,
+			Location:    event.Location,
+			Description: event.Description,
+			OccurredAt:  event.OccurredAt,
+		})
+	}
+
+	return &ShipmentResponse{
+		ID:             shipment.ID,
+		OrderID:        shipment.OrderID,
+		Carrier:        shipment.Carrier,
+		TrackingNumber: shipment.TrackingNumber,
+		Status:         shipment.Status,
+		LastEventAt:    shipment.LastEventAt,
+		CreatedAt:      shipment.CreatedAt,
+		Events:         events,
+	}
+}
+
+// NewShipmentResponses 批量转换包裹模型
+func NewShipmentResponses(shipments []*model.Shipment) []*ShipmentResponse {
+	res := make([]*ShipmentResponse, 0, len(shipments))
+	for _, shipment := range shipments {
+		res = append(res, NewShipmentResponse(shipment))
+	}
+	return res
+}
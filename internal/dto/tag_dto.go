@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"gin-app-start/internal/model"
+)
+
+// TagUserRequest 给用户打标签
+type TagUserRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20,dive,min=1,max=64" example:"vip,beta"`
+}
+
+// SegmentQuery 按标签圈选用户，match 为 any 时命中任一标签即可，为 all 时需命中全部标签
+type SegmentQuery struct {
+	Tags     string `form:"tags" binding:"required" example:"vip,beta"` // 逗号分隔的标签名
+	Match    string `form:"match" binding:"omitempty,oneof=any all" example:"any"`
+	Page     int    `form:"page" binding:"omitempty,min=1" example:"1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100" example:"10"`
+}
+
+// TagResponse 标签信息
+type TagResponse struct {
+	ID        uint      `json:"id" example:"1"`
+	Name      string    `json:"name" example:"vip"`
+	Users     *int64    `json:"users,omitempty" example:"42"` // 打了该标签的用户数，仅标签列表返回
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewTagResponses 批量转换标签模型
+func NewTagResponses(tags []*model.Tag) []*TagResponse {
+	res := make([]*TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, &TagResponse{
+			ID:        tag.ID,
+			Name:      tag.Name,
+			CreatedAt: tag.CreatedAt,
+		})
+	}
+	return res
+}
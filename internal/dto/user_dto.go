@@ -1,6 +1,13 @@
 package dto
 
-import "gin-app-start/internal/model"
+import (
+	"time"
+
+	"gin-app-start/internal/model"
+	"gin-app-start/pkg/hashid"
+	"gin-app-start/pkg/jwt"
+	"gin-app-start/pkg/mask"
+)
 
 // CreateUserRequest represents the request to create a new user
 type CreateUserRequest struct {
@@ -8,6 +15,8 @@ type CreateUserRequest struct {
 	Email    string `json:"email" binding:"omitempty,email" example:"john@example.com"`
 	Phone    string `json:"phone" binding:"omitempty,len=11" example:"13800138000"`
 	Password string `json:"password" binding:"required,min=6,max=32" example:"password123"`
+
+	InviteCode string `json:"invite_code" binding:"omitempty,alphanum,max=16" example:"K7QX2M9P"` // 邀请码，邀请人获得奖励
 }
 
 // LoginRequest represents the request to login
@@ -16,6 +25,12 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required,min=6,max=32" example:"password123"`
 }
 
+// UploadAvatarRequest JSON 方式上传头像，用于不方便发送 multipart 的客户端(如部分小程序)
+type UploadAvatarRequest struct {
+	Username    string `json:"username" binding:"required,min=3,max=32" example:"John Doe"`
+	ImageBase64 string `json:"image_base64" binding:"required" example:"data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAA..."` // 可以带 data URL 前缀，类型按内容识别
+}
+
 type UpdatePasswordRequest struct {
 	Username    string `json:"username" binding:"required,min=3,max=32" example:"John Doe"`
 	OldPassword string `json:"old_password" binding:"required,min=6,max=32" example:"password123"`
@@ -30,13 +45,107 @@ type UpdateUserRequest struct {
 	Status int8   `json:"status" binding:"omitempty,oneof=0 1" example:"1"`
 }
 
+// PatchUserRequest 部分更新用户信息，只修改请求中出现的字段，可以修改为零值(如 status 为 0)
+// 字段为 null 时与未出现相同，不修改
+type PatchUserRequest struct {
+	Email  *string `json:"email" binding:"omitempty,email" example:"john@example.com"`
+	Phone  *string `json:"phone" binding:"omitempty,len=11" example:"13800138000"`
+	Avatar *string `json:"avatar" binding:"omitempty,url" example:"https://example.com/avatar.jpg"`
+	Status *int8   `json:"status" binding:"omitempty,oneof=0 1" example:"0"`
+}
+
+// ReauthRequest 敏感操作前重新验证身份
+type ReauthRequest struct {
+	Password string `json:"password" binding:"required,min=6,max=32" example:"password123"`
+}
+
+// ReauthResponse 重新验证的结果，ExpiresAt 之前可以执行敏感操作
+type ReauthResponse struct {
+	AuthenticatedAt time.Time `json:"authenticated_at" example:"2023-01-01T00:00:00Z"`
+	ExpiresAt       time.Time `json:"expires_at" example:"2023-01-01T00:05:00Z"`
+
+	Token *TokenResponse `json:"token,omitempty"` // jwt 模式下重新签发的令牌，之后的请求需改用新令牌
+}
+
+// TokenResponse jwt 模式下登录、重新验证时签发的令牌，session 模式下不返回
+type TokenResponse struct {
+	TokenType        string    `json:"token_type" example:"Bearer"`
+	AccessToken      string    `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	ExpiresIn        int64     `json:"expires_in" example:"900"` // 访问令牌的有效期，单位秒
+	RefreshToken     string    `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at" example:"2023-01-08T00:00:00Z"`
+}
+
+// NewTokenResponse 将签发的令牌转换为响应结构
+func NewTokenResponse(pair *jwt.Pair, now time.Time) *TokenResponse {
+	return &TokenResponse{
+		TokenType:        "Bearer",
+		AccessToken:      pair.AccessToken,
+		ExpiresIn:        int64(pair.AccessExpiresAt.Sub(now).Seconds()),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt,
+	}
+}
+
 type LogoutRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=32" example:"John Doe"`
 }
 
+// RefreshRequest 用刷新令牌换取新的访问令牌和刷新令牌
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+}
+
+// UserResponse represents the user information returned to clients
+// 只暴露允许对外展示的字段，密码和盐值永远不会出现在响应中
+type UserResponse struct {
+	ID        hashid.ID `json:"id" swaggertype:"integer" example:"1"` // 启用 id_obfuscation 时为编码后的字符串
+	Username  string    `json:"username" example:"john_doe"`
+	Email     string    `json:"email" example:"john@example.com"`
+	Phone     string    `json:"phone" example:"13800138000"`
+	Avatar    string    `json:"avatar" example:"https://example.com/avatar.jpg"`
+	Status    int8      `json:"status" example:"1"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdateAt  time.Time `json:"update_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewUserResponse 将用户模型转换为响应结构，viewer 不是本人或管理员时手机号、邮箱脱敏
+func NewUserResponse(user *model.User, viewer Viewer) *UserResponse {
+	if user == nil {
+		return nil
+	}
+
+	res := &UserResponse{
+		ID:        hashid.ID(user.ID),
+		Username:  user.Username,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Avatar:    user.Avatar,
+		Status:    user.Status,
+		CreatedAt: user.CreatedAt,
+		UpdateAt:  user.UpdatedAt,
+	}
+	if !viewer.CanSee(user.ID) {
+		res.Email = mask.Email(res.Email)
+		res.Phone = mask.Phone(res.Phone)
+	}
+	return res
+}
+
+// NewUserResponses 批量转换用户模型
+func NewUserResponses(users []*model.User, viewer Viewer) []*UserResponse {
+	res := make([]*UserResponse, 0, len(users))
+	for _, user := range users {
+		res = append(res, NewUserResponse(user, viewer))
+	}
+	return res
+}
+
 type ListUsersResponse struct {
-	Users    []*model.User `json:"users"`
-	Total    int64         `json:"total"`
-	Page     int           `json:"page"`
-	PageSize int           `json:"page_size"`
+	Users    []*UserResponse `json:"users"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+
+	NextCursor string `json:"next_cursor,omitempty" example:"NDI"` // 游标分页时下一页的游标，没有下一页时为空，不统计 total
 }
@@ -0,0 +1,13 @@
+package dto
+
+// ProductRequest 路径中的商品ID
+type ProductRequest struct {
+	ProductID string `uri:"product_id" binding:"required,max=64" example:"SKU-10001"`
+}
+
+// ViewCountResponse 累计浏览数，包括尚未落库的部分
+type ViewCountResponse struct {
+	Subject   string `json:"subject" example:"product"`
+	SubjectID string `json:"subject_id" example:"SKU-10001"`
+	Views     int64  `json:"views" example:"42"`
+}
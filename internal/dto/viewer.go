@@ -0,0 +1,15 @@
+package dto
+
+// Viewer 查看响应数据的用户，决定响应中的手机号、邮箱是否脱敏
+type Viewer struct {
+	UserID uint
+	Admin  bool
+}
+
+// AdminViewer 管理端口的调用方，看到完整数据
+var AdminViewer = Viewer{Admin: true}
+
+// CanSee 是否可以看到 ownerID 用户的完整敏感信息，只有本人和管理员可以
+func (v Viewer) CanSee(ownerID uint) bool {
+	return v.Admin || (v.UserID != 0 && v.UserID == ownerID)
+}
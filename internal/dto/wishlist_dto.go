@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"gin-app-start/internal/model"
+)
+
+// WishlistItemRequest 收藏或取消收藏的商品
+type WishlistItemRequest struct {
+	ProductID string `uri:"product_id" binding:"required,max=64" example:"SKU-10001"`
+}
+
+// FavoriteResponse 收藏的商品
+type FavoriteResponse struct {
+	ProductID string    `json:"product_id" example:"SKU-10001"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// NewFavoriteResponses 批量转换收藏模型
+func NewFavoriteResponses(favorites []*model.Favorite) []*FavoriteResponse {
+	res := make([]*FavoriteResponse, 0, len(favorites))
+	for _, favorite := range favorites {
+		res = append(res, &FavoriteResponse{
+			ProductID: favorite.ProductID,
+			CreatedAt: favorite.CreatedAt,
+		})
+	}
+	return res
+}
@@ -0,0 +1,110 @@
+// Package filelink 生成和校验上传文件的限时下载链接，持有链接即可下载，不需要登录
+package filelink
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/pkg/token"
+)
+
+// QueryParam 下载链接中携带签名的查询参数
+const QueryParam = "token"
+
+// ErrInvalidName 文件名为空或跳出了文件目录
+var ErrInvalidName = errors.New("filelink: invalid file name")
+
+// Signer 生成和校验下载链接
+//
+// 链接的 token 为 pkg/token 的 download token，主体为文件相对 file.dir_name 的路径，
+// 签名覆盖路径和过期时间，换一个文件名或延长有效期都会校验失败。有效期内可以重复下载，不记录使用次数。
+type Signer struct {
+	tokens *token.Manager
+	prefix string
+	ttl    time.Duration
+}
+
+// NewSigner 按 file 配置创建 Signer，未配置 sign_key 时使用随机密钥，generated 为 true；
+// 随机密钥生成的链接在重启后失效，多实例部署时只能由签发的实例校验
+func NewSigner(cfg config.FileConfig) (s *Signer, generated bool, err error) {
+	key := []byte(cfg.SignKey)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, false, err
+		}
+		generated = true
+	}
+
+	return &Signer{
+		tokens: token.New(key, nil),
+		prefix: cfg.UrlPrefix,
+		ttl:    cfg.LinkDuration(),
+	}, generated, nil
+}
+
+// TTL 下载链接的有效期
+func (s *Signer) TTL() time.Duration {
+	return s.ttl
+}
+
+// URL 文件 name(相对 file.dir_name 的路径，如 john/avatar.png)的下载链接，ttl 后过期
+func (s *Signer) URL(name string) (string, error) {
+	name, ok := Clean(name)
+	if !ok {
+		return "", ErrInvalidName
+	}
+
+	tok, err := s.tokens.Generate(token.PurposeDownload, name, s.ttl)
+	if err != nil {
+		return "", err
+	}
+	return s.prefix + escapePath(name) + "?" + url.Values{QueryParam: {tok}}.Encode(), nil
+}
+
+// Verify 校验 tok 是否为文件 name 的有效链接，过期时返回 token.ErrExpired，其余不匹配返回 token.ErrInvalid
+func (s *Signer) Verify(name, tok string) error {
+	name, ok := Clean(name)
+	if !ok {
+		return ErrInvalidName
+	}
+
+	claims, err := s.tokens.Verify(tok, token.PurposeDownload)
+	if err != nil {
+		if errors.Is(err, token.ErrPurposeMismatch) {
+			return token.ErrInvalid
+		}
+		return err
+	}
+	if claims.Subject != name {
+		return token.ErrInvalid
+	}
+	return nil
+}
+
+// Clean 规范化相对文件目录的路径，name 为空或包含 .. 跳出目录时 ok 为 false
+func Clean(name string) (string, bool) {
+	if name == "" || strings.Contains(name, "\\") {
+		return "", false
+	}
+	cleaned := path.Clean("/" + name)
+	if cleaned == "/" || cleaned != "/"+strings.TrimPrefix(name, "/") {
+		return "", false
+	}
+	return cleaned[1:], true
+}
+
+// escapePath 逐段转义路径，保留分隔符
+func escapePath(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,128 @@
+package filelink
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/pkg/token"
+)
+
+func newTestSigner(t *testing.T, ttl int) *Signer {
+	t.Helper()
+	s, generated, err := NewSigner(config.FileConfig{
+		UrlPrefix: "http://127.0.0.1:9060/api/v1/files/",
+		SignKey:   "test-key",
+		LinkTTL:   ttl,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generated {
+		t.Fatal("key should not be generated when sign_key is set")
+	}
+	return s
+}
+
+// tokenOf 从下载链接中取出文件路径和签名
+func tokenOf(t *testing.T, link string) (string, string) {
+	t.Helper()
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimPrefix(u.Path, "/api/v1/files/"), u.Query().Get(QueryParam)
+}
+
+func TestURLAndVerify(t *testing.T) {
+	s := newTestSigner(t, 60)
+
+	link, err := s.URL("john/avatar 1.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(link, "http://127.0.0.1:9060/api/v1/files/john/avatar%201.png?token=") {
+		t.Fatalf("link = %s", link)
+	}
+
+	name, tok := tokenOf(t, link)
+	if err := s.Verify(name, tok); err != nil {
+		t.Fatalf("Verify = %v", err)
+	}
+	// 签名绑定文件路径，不能用于其它文件
+	if err := s.Verify("bob/avatar 1.png", tok); !errors.Is(err, token.ErrInvalid) {
+		t.Fatalf("Verify other file = %v, want ErrInvalid", err)
+	}
+	if err := s.Verify(name, tok+"x"); !errors.Is(err, token.ErrInvalid) {
+		t.Fatalf("Verify tampered token = %v, want ErrInvalid", err)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	s := newTestSigner(t, 60)
+	link, err := s.URL("john/avatar.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, tok := tokenOf(t, link)
+
+	other := newTestSigner(t, 60)
+	if err := other.Verify(name, tok); err != nil {
+		t.Fatalf("same key should verify: %v", err)
+	}
+
+	// 同一密钥签发的已过期链接
+	expired := &Signer{tokens: token.New([]byte("test-key"), nil), ttl: -time.Second}
+	expiredLink, err := expired.URL("john/avatar.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, expiredTok := tokenOf(t, expiredLink)
+	if err := s.Verify(name, expiredTok); !errors.Is(err, token.ErrExpired) {
+		t.Fatalf("Verify expired = %v, want ErrExpired", err)
+	}
+}
+
+func TestRandomKey(t *testing.T) {
+	a, generated, err := NewSigner(config.FileConfig{})
+	if err != nil || !generated {
+		t.Fatalf("NewSigner = %v, generated %v", err, generated)
+	}
+	if a.TTL() != time.Hour {
+		t.Fatalf("TTL = %v, want default 1h", a.TTL())
+	}
+
+	b, _, _ := NewSigner(config.FileConfig{})
+	link, _ := a.URL("john/avatar.png")
+	name, tok := tokenOf(t, link)
+	if err := b.Verify(name, tok); !errors.Is(err, token.ErrInvalid) {
+		t.Fatalf("link signed with another random key should be invalid, got %v", err)
+	}
+}
+
+func TestClean(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"john/avatar.png", "john/avatar.png", true},
+		{"/john/avatar.png", "john/avatar.png", true},
+		{"", "", false},
+		{"/", "", false},
+		{"../etc/passwd", "", false},
+		{"john/../bob/avatar.png", "", false},
+		{"john//avatar.png", "", false},
+		{"john/", "", false},
+		{`john\avatar.png`, "", false},
+	}
+	for _, tc := range cases {
+		got, ok := Clean(tc.name)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("Clean(%q) = %q, %v, want %q, %v", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package filescan 在后台扫描上传的文件，扫描通过前文件留在隔离目录，不能被下载
+package filescan
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/pkg/avscan"
+	"gin-app-start/pkg/retry"
+
+	"go.uber.org/zap"
+)
+
+// Status 隔离目录中文件的扫描状态
+type Status int
+
+const (
+	StatusUnknown  Status = iota // 不在隔离目录中，可能已扫描通过或不存在
+	StatusPending                // 等待扫描，或扫描服务暂不可用
+	StatusInfected               // 已判定感染，文件已删除
+)
+
+const (
+	pendingDir  = "pending"  // 等待扫描的文件，子路径与 file.dir_name 下的相同
+	infectedDir = "infected" // 感染文件的记录，内容为病毒特征名
+	queueSize   = 1024
+)
+
+// Quarantine 上传文件的隔离区
+//
+// 上传的文件保存到 UploadDir 后调用 Submit，后台按 workers 并发扫描：通过的文件移入 file.dir_name，
+// 感染的文件删除并留下记录，扫描服务不可用时按 attempts 重试，仍失败的文件留在隔离目录，下次启动时重新扫描。
+// 隔离目录与 file.dir_name 需在同一文件系统上，扫描通过的文件通过 rename 移动。
+type Quarantine struct {
+	scanner avscan.Scanner
+	cfg     config.FileScanConfig
+	dir     string
+	logger  *zap.Logger
+
+	queue  chan string
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 按 file.scan 配置创建隔离区，未启用时返回 nil；dir 为 file.dir_name
+func New(cfg config.FileScanConfig, dir string, logger *zap.Logger) (*Quarantine, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var scanner avscan.Scanner
+	switch cfg.Provider {
+	case config.FileScanProviderClamAV:
+		if cfg.Address == "" {
+			return nil, fmt.Errorf("file.scan.address must be set when provider is clamav")
+		}
+		scanner = avscan.NewClamAV(cfg.Address)
+	case config.FileScanProviderHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("file.scan.url must be set when provider is http")
+		}
+		scanner = avscan.NewHTTP(cfg.URL, nil)
+	default:
+		return nil, fmt.Errorf("file.scan.provider must be clamav or http, got %q", cfg.Provider)
+	}
+	return newQuarantine(scanner, cfg, dir, logger), nil
+}
+
+func newQuarantine(scanner avscan.Scanner, cfg config.FileScanConfig, dir string, logger *zap.Logger) *Quarantine {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.Attempts <= 0 {
+		cfg.Attempts = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Quarantine{
+		scanner: scanner,
+		cfg:     cfg,
+		dir:     dir,
+		logger:  logger,
+		queue:   make(chan string, queueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// UploadDir 上传的文件应保存到的目录，其下的子路径与 file.dir_name 下的相同
+func (q *Quarantine) UploadDir() string {
+	return filepath.Join(q.cfg.QuarantineDir, pendingDir)
+}
+
+// Submit 提交 name(相对 UploadDir 的路径，如 john/avatar.png)等待扫描，不阻塞；
+// 队列已满时文件留在隔离目录，下次启动时扫描
+func (q *Quarantine) Submit(name string) {
+	select {
+	case q.queue <- name:
+	default:
+		q.logger.Warn("file scan queue is full, file will be scanned after restart", zap.String("file", name))
+	}
+}
+
+// Status 文件 name 的扫描状态
+func (q *Quarantine) Status(name string) Status {
+	if _, err := os.Stat(q.path(pendingDir, name)); err == nil {
+		return StatusPending
+	}
+	if _, err := os.Stat(q.path(infectedDir, name)); err == nil {
+		return StatusInfected
+	}
+	return StatusUnknown
+}
+
+// Start 启动扫描协程，并重新提交上次退出时仍在隔离目录中的文件
+func (q *Quarantine) Start() {
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		q.resubmit()
+	}()
+}
+
+// Stop 停止扫描，正在扫描的文件留在隔离目录，下次启动时重新扫描
+func (q *Quarantine) Stop() {
+	q.cancel()
+	q.wg.Wait()
+}
+
+func (q *Quarantine) work() {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case name := <-q.queue:
+			q.process(name)
+		}
+	}
+}
+
+// resubmit 提交隔离目录中遗留的文件
+func (q *Quarantine) resubmit() {
+	root := q.UploadDir()
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		select {
+		case q.queue <- filepath.ToSlash(name):
+			return nil
+		case <-q.ctx.Done():
+			return fs.SkipAll
+		}
+	})
+	if err != nil && !os.IsNotExist(err) {
+		q.logger.Error("failed to list quarantined files", zap.Error(err))
+	}
+}
+
+// process 扫描一个文件，根据结果移入 file.dir_name 或删除
+func (q *Quarantine) process(name string) {
+	pending := q.path(pendingDir, name)
+
+	var result *avscan.Result
+	err := retry.Do(q.ctx, func(ctx context.Context) error {
+		f, err := os.Open(pending)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		ctx, cancel := context.WithTimeout(ctx, q.cfg.TimeoutDuration())
+		defer cancel()
+		result, err = q.scanner.Scan(ctx, f)
+		return err
+	},
+		retry.WithName("file.scan"),
+		retry.WithMaxAttempts(q.cfg.Attempts),
+		retry.WithBackoff(time.Second, 30*time.Second),
+		retry.WithClassifier(func(err error) bool { return !os.IsNotExist(err) }),
+	)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// 同一文件被重复提交，已由其它协程处理
+			return
+		}
+		q.logger.Error("failed to scan uploaded file, keeping it in quarantine", zap.String("file", name), zap.Error(err))
+		return
+	}
+
+	if result.Infected {
+		q.reject(name, pending, result.Signature)
+		return
+	}
+
+	target := filepath.Join(q.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		q.logger.Error("failed to release scanned file", zap.String("file", name), zap.Error(err))
+		return
+	}
+	if err := os.Rename(pending, target); err != nil {
+		q.logger.Error("failed to release scanned file", zap.String("file", name), zap.Error(err))
+		return
+	}
+	q.logger.Info("uploaded file passed scan", zap.String("file", name))
+}
+
+// reject 删除感染的文件，记录命中的病毒特征
+func (q *Quarantine) reject(name, pending, signature string) {
+	q.logger.Warn("uploaded file is infected", zap.String("file", name), zap.String("signature", signature))
+
+	marker := q.path(infectedDir, name)
+	if err := os.MkdirAll(filepath.Dir(marker), 0755); err == nil {
+		if err := os.WriteFile(marker, []byte(signature), 0644); err != nil {
+			q.logger.Error("failed to record infected file", zap.String("file", name), zap.Error(err))
+		}
+	}
+	if err := os.Remove(pending); err != nil && !os.IsNotExist(err) {
+		q.logger.Error("failed to remove infected file", zap.String("file", name), zap.Error(err))
+	}
+}
+
+func (q *Quarantine) path(sub, name string) string {
+	return filepath.Join(q.cfg.QuarantineDir, sub, filepath.FromSlash(name))
+}
@@ -0,0 +1,142 @@
+package filescan
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/pkg/avscan"
+
+	"go.uber.org/zap"
+)
+
+// fakeScanner 内容含 virus 时判定感染；failures 为前几次调用返回的错误次数
+type fakeScanner struct {
+	failures atomic.Int32
+	calls    atomic.Int32
+}
+
+func (s *fakeScanner) Scan(_ context.Context, r io.Reader) (*avscan.Result, error) {
+	s.calls.Add(1)
+	if s.failures.Add(-1) >= 0 {
+		return nil, errors.New("connection refused")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(data), "virus") {
+		return &avscan.Result{Infected: true, Signature: "Test.Virus"}, nil
+	}
+	return &avscan.Result{}, nil
+}
+
+func newTestQuarantine(t *testing.T, scanner avscan.Scanner, attempts int) (*Quarantine, string) {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "file")
+	q := newQuarantine(scanner, config.FileScanConfig{
+		Enabled:       true,
+		Attempts:      attempts,
+		Workers:       2,
+		QuarantineDir: filepath.Join(root, "quarantine"),
+	}, dir, zap.NewNop())
+	return q, dir
+}
+
+// upload 模拟上传，把文件写入隔离目录
+func upload(t *testing.T, q *Quarantine, name, content string) {
+	t.Helper()
+	p := filepath.Join(q.UploadDir(), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitStatus(t *testing.T, q *Quarantine, name string, want Status) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for q.Status(name) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("status of %s = %v, want %v", name, q.Status(name), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestScanReleasesCleanAndRejectsInfected(t *testing.T) {
+	q, dir := newTestQuarantine(t, new(fakeScanner), 1)
+	q.Start()
+	defer q.Stop()
+
+	upload(t, q, "john/clean.png", "hello")
+	upload(t, q, "john/bad.png", "a virus inside")
+	if got := q.Status("john/clean.png"); got != StatusPending {
+		t.Fatalf("status before scan = %v, want pending", got)
+	}
+	q.Submit("john/clean.png")
+	q.Submit("john/bad.png")
+
+	waitStatus(t, q, "john/clean.png", StatusUnknown)
+	if data, err := os.ReadFile(filepath.Join(dir, "john", "clean.png")); err != nil || string(data) != "hello" {
+		t.Fatalf("released file = %q, %v", data, err)
+	}
+
+	waitStatus(t, q, "john/bad.png", StatusInfected)
+	if _, err := os.Stat(filepath.Join(dir, "john", "bad.png")); !os.IsNotExist(err) {
+		t.Fatalf("infected file should not be released, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(q.UploadDir(), "john", "bad.png")); !os.IsNotExist(err) {
+		t.Fatalf("infected file should be removed from quarantine, stat err = %v", err)
+	}
+}
+
+func TestScanKeepsFileWhenScannerUnavailable(t *testing.T) {
+	scanner := new(fakeScanner)
+	scanner.failures.Store(100)
+	q, _ := newTestQuarantine(t, scanner, 1)
+
+	upload(t, q, "john/a.png", "hello")
+	q.process("john/a.png")
+
+	if got := q.Status("john/a.png"); got != StatusPending {
+		t.Fatalf("status = %v, want pending after scan failure", got)
+	}
+}
+
+func TestStartResubmitsQuarantinedFiles(t *testing.T) {
+	scanner := new(fakeScanner)
+	q, dir := newTestQuarantine(t, scanner, 1)
+
+	// 上次退出时未扫描的文件
+	upload(t, q, "john/left.png", "hello")
+	q.Start()
+	defer q.Stop()
+
+	waitStatus(t, q, "john/left.png", StatusUnknown)
+	if _, err := os.Stat(filepath.Join(dir, "john", "left.png")); err != nil {
+		t.Fatalf("left-over file should be released: %v", err)
+	}
+}
+
+func TestNewValidatesProvider(t *testing.T) {
+	if q, err := New(config.FileScanConfig{}, "", zap.NewNop()); q != nil || err != nil {
+		t.Fatalf("disabled: %v, %v", q, err)
+	}
+	if _, err := New(config.FileScanConfig{Enabled: true, Provider: "virustotal"}, "", zap.NewNop()); err == nil {
+		t.Fatal("unknown provider should fail")
+	}
+	if _, err := New(config.FileScanConfig{Enabled: true, Provider: config.FileScanProviderClamAV}, "", zap.NewNop()); err == nil {
+		t.Fatal("clamav without address should fail")
+	}
+}
@@ -0,0 +1,159 @@
+package hooks
+
+import (
+	"fmt"
+	"sync"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"go.uber.org/zap"
+)
+
+// RequestHook 请求开始或响应写出前调用，OnRequest 钩子可以调用 AbortWithError 拒绝请求
+type RequestHook func(c common.Context)
+
+// ErrorHook 请求以业务错误结束时调用，在 OnResponse 之前
+type ErrorHook func(c common.Context, err common.BusinessError)
+
+// UserHook 用户事件，user 不能修改
+type UserHook func(ctx common.Context, user *model.User)
+
+// OrderHook 订单事件，order 不能修改
+type OrderHook func(ctx common.Context, order *model.Order)
+
+// Registry 请求生命周期和业务事件的钩子，下游分支在启动时注册钩子扩展行为，不需要修改 controller 和 service
+//
+// 钩子在请求的 goroutine 中按注册顺序同步执行，耗时操作需要自行异步处理；钩子 panic 时记录日志后继续执行后面的钩子。
+// nil 的 Registry 不执行任何钩子。
+type Registry struct {
+	mu          sync.RWMutex
+	request     []RequestHook
+	response    []RequestHook
+	errors      []ErrorHook
+	userCreated []UserHook
+	orderPaid   []OrderHook
+}
+
+// New 创建空的钩子注册表
+func New() *Registry {
+	return new(Registry)
+}
+
+// OnRequest 请求经过全局中间件、进入路由处理之前调用
+func (r *Registry) OnRequest(hook RequestHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.request = append(r.request, hook)
+}
+
+// OnResponse 路由处理完成、响应写出之前调用，可以通过 GetPayload、AbortError 读取结果；panic 的请求不调用
+func (r *Registry) OnResponse(hook RequestHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.response = append(r.response, hook)
+}
+
+// OnError 请求以业务错误(AbortWithError)结束时调用
+func (r *Registry) OnError(hook ErrorHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, hook)
+}
+
+// OnUserCreated 用户注册成功后调用
+func (r *Registry) OnUserCreated(hook UserHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.userCreated = append(r.userCreated, hook)
+}
+
+// OnOrderPaid 订单状态变为已支付后调用
+func (r *Registry) OnOrderPaid(hook OrderHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orderPaid = append(r.orderPaid, hook)
+}
+
+// Request 执行 OnRequest 钩子
+func (r *Registry) Request(c common.Context) {
+	if r == nil {
+		return
+	}
+	r.mu.RLock()
+	hooks := r.request
+	r.mu.RUnlock()
+
+	for _, hook := range hooks {
+		run(c, "request", func() { hook(c) })
+	}
+}
+
+// Response 执行 OnResponse 钩子
+func (r *Registry) Response(c common.Context) {
+	if r == nil {
+		return
+	}
+	r.mu.RLock()
+	hooks := r.response
+	r.mu.RUnlock()
+
+	for _, hook := range hooks {
+		run(c, "response", func() { hook(c) })
+	}
+}
+
+// Error 执行 OnError 钩子
+func (r *Registry) Error(c common.Context, err common.BusinessError) {
+	if r == nil {
+		return
+	}
+	r.mu.RLock()
+	hooks := r.errors
+	r.mu.RUnlock()
+
+	for _, hook := range hooks {
+		run(c, "error", func() { hook(c, err) })
+	}
+}
+
+// UserCreated 执行 OnUserCreated 钩子
+func (r *Registry) UserCreated(ctx common.Context, user *model.User) {
+	if r == nil {
+		return
+	}
+	r.mu.RLock()
+	hooks := r.userCreated
+	r.mu.RUnlock()
+
+	for _, hook := range hooks {
+		run(ctx, "user_created", func() { hook(ctx, user) })
+	}
+}
+
+// OrderPaid 执行 OnOrderPaid 钩子
+func (r *Registry) OrderPaid(ctx common.Context, order *model.Order) {
+	if r == nil {
+		return
+	}
+	r.mu.RLock()
+	hooks := r.orderPaid
+	r.mu.RUnlock()
+
+	for _, hook := range hooks {
+		run(ctx, "order_paid", func() { hook(ctx, order) })
+	}
+}
+
+// run 执行单个钩子，panic 不影响请求和其余钩子
+func run(ctx common.Context, event string, fn func()) {
+	defer func() {
+		if err := recover(); err != nil && ctx.Logger() != nil {
+			ctx.Logger().Error("hook panicked",
+				zap.String("event", event),
+				zap.String("panic", fmt.Sprintf("%+v", err)),
+			)
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,56 @@
+package hooks
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newContext() common.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	return common.NewContext(c)
+}
+
+func TestRegistryRunsHooksInOrder(t *testing.T) {
+	r := New()
+	var calls []string
+	r.OnOrderPaid(func(ctx common.Context, order *model.Order) { calls = append(calls, "first:"+order.OrderNumber) })
+	r.OnOrderPaid(func(ctx common.Context, order *model.Order) { calls = append(calls, "second:"+order.OrderNumber) })
+	r.OnUserCreated(func(ctx common.Context, user *model.User) { calls = append(calls, "user:"+user.Username) })
+
+	ctx := newContext()
+	r.OrderPaid(ctx, &model.Order{OrderNumber: "EC1"})
+	r.UserCreated(ctx, &model.User{Username: "alice"})
+
+	want := []string{"first:EC1", "second:EC1", "user:alice"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRegistryRecoversPanics(t *testing.T) {
+	r := New()
+	called := false
+	r.OnRequest(func(c common.Context) { panic("boom") })
+	r.OnRequest(func(c common.Context) { called = true })
+
+	r.Request(newContext())
+	if !called {
+		t.Fatal("hook after a panicking hook was not called")
+	}
+}
+
+func TestNilRegistry(t *testing.T) {
+	var r *Registry
+	ctx := newContext()
+	r.Request(ctx)
+	r.Response(ctx)
+	r.Error(ctx, nil)
+	r.UserCreated(ctx, &model.User{})
+	r.OrderPaid(ctx, &model.Order{})
+}
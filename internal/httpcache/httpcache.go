@@ -0,0 +1,116 @@
+// Package httpcache 缓存公开 GET 接口的响应，数据保存在 Redis 中
+package httpcache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"time"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/redis"
+)
+
+// 缓存名，路由通过 ResponseCache 拦截器按名称缓存，服务在数据变更后按名称失效
+const (
+	Stores       = "stores"        // 附近门店，门店增删或重建 GEO 索引时失效
+	ProductViews = "product_views" // 商品浏览数，只按过期时间刷新
+)
+
+const defaultTTL = time.Minute
+
+// Cache HTTP 响应缓存
+//
+// 缓存按名称分组，每组在 Redis 中有一个版本号，缓存键带上当前版本号：
+// Invalidate 只递增版本号，旧版本的缓存不再被读取，到期后由 Redis 清理，不需要遍历删除。
+// 值为 nil 时所有方法都是空操作，未启用缓存时服务可以直接调用 Invalidate。
+type Cache struct {
+	repo redis.RedisRepository
+	ttl  time.Duration
+	ttls map[string]time.Duration
+}
+
+// New 创建响应缓存，未启用时返回 nil
+func New(repo redis.RedisRepository, cfg config.ResponseCacheConfig) *Cache {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	ttl := time.Duration(cfg.TTL) * time.Second
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	ttls := make(map[string]time.Duration, len(cfg.Routes))
+	for name, seconds := range cfg.Routes {
+		ttls[name] = time.Duration(seconds) * time.Second
+	}
+	return &Cache{repo: repo, ttl: ttl, ttls: ttls}
+}
+
+// TTL 缓存名对应的缓存时间，为 0 表示不缓存
+func (c *Cache) TTL(name string) time.Duration {
+	if c == nil {
+		return 0
+	}
+	if ttl, ok := c.ttls[name]; ok {
+		return ttl
+	}
+	return c.ttl
+}
+
+func versionKey(name string) string {
+	return "http_cache:" + name + ":version"
+}
+
+// entryKey 缓存键，variant 区分同一组内的不同请求(路径、查询参数、角色)，取摘要避免键过长
+func entryKey(name, version, variant string) string {
+	sum := sha1.Sum([]byte(variant))
+	return "http_cache:" + name + ":v" + version + ":" + hex.EncodeToString(sum[:])
+}
+
+// version 缓存组的当前版本号，从未失效过时为 0
+func (c *Cache) version(name string, options ...redis.Option) string {
+	version, err := c.repo.Get(versionKey(name), options...)
+	if err != nil || version == "" {
+		return "0"
+	}
+	return version
+}
+
+// Get 读取缓存的响应数据
+func (c *Cache) Get(name, variant string, options ...redis.Option) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	data, err := c.repo.Get(entryKey(name, c.version(name, options...), variant), options...)
+	if err != nil || data == "" {
+		return nil, false
+	}
+	return []byte(data), true
+}
+
+// Set 按缓存名的缓存时间保存响应数据
+func (c *Cache) Set(name, variant string, data []byte, options ...redis.Option) error {
+	ttl := c.TTL(name)
+	if ttl <= 0 {
+		return nil
+	}
+	return c.repo.SetWithExpire(entryKey(name, c.version(name, options...), variant), string(data), ttl, options...)
+}
+
+// Invalidate 失效缓存组，之后的请求重新回源
+func (c *Cache) Invalidate(names ...string) error {
+	if c == nil {
+		return nil
+	}
+	for _, name := range names {
+		if _, err := c.repo.Increment(versionKey(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Variant 由请求路径、排序后的查询参数和用户角色组成的缓存区分键
+func Variant(path, encodedQuery, role string) string {
+	return role + " " + path + "?" + encodedQuery
+}
@@ -0,0 +1,82 @@
+package impersonation
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gin-app-start/internal/common"
+
+	"github.com/gin-contrib/sessions"
+	"go.uber.org/zap"
+)
+
+// Header 代入期间每个响应都带上该响应头，值为管理员用户名
+const Header = "X-Impersonated-By"
+
+// Session 管理员代入用户身份的记录，保存在管理员会话的 common.SESSION_IMPERSONATION_KEY 中
+//
+// 代入期间 SessionAuth 把 UserInfo 作为当前用户，订单、用户等接口的归属校验都按被代入用户进行，
+// 管理员权限不再生效；需要最近验证身份的敏感操作一律拒绝。到期后自动恢复为管理员本人。
+type Session struct {
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	UserInfo  string `json:"user_info"` // 被代入用户的会话数据，格式与登录时写入 SESSION_KEY 的相同
+	AdminID   uint   `json:"admin_id"`
+	AdminName string `json:"admin_name"`
+	Reason    string `json:"reason"`
+	StartedAt int64  `json:"started_at"` // Unix 秒
+	ExpiresAt int64  `json:"expires_at"` // Unix 秒
+}
+
+// Expired 代入是否已到期
+func (s *Session) Expired(now time.Time) bool {
+	return now.Unix() >= s.ExpiresAt
+}
+
+// Banner 响应中提示当前处于代入状态的文字
+func (s *Session) Banner() string {
+	return fmt.Sprintf("%s is impersonating %s (user %d) until %s, all actions are audited",
+		s.AdminName, s.Username, s.UserID, time.Unix(s.ExpiresAt, 0).Format(time.RFC3339))
+}
+
+// Save 写入会话，调用方负责 session.Save
+func Save(session sessions.Session, s *Session) error {
+	value, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	session.Set(common.SESSION_IMPERSONATION_KEY, string(value))
+	return nil
+}
+
+// Get 读取会话中的代入记录，未代入或记录无法解析时返回 false
+func Get(session sessions.Session) (*Session, bool) {
+	value, ok := session.Get(common.SESSION_IMPERSONATION_KEY).(string)
+	if !ok || value == "" {
+		return nil, false
+	}
+
+	var s Session
+	if err := json.Unmarshal([]byte(value), &s); err != nil {
+		return nil, false
+	}
+	return &s, true
+}
+
+// Clear 删除会话中的代入记录，调用方负责 session.Save
+func Clear(session sessions.Session) {
+	session.Delete(common.SESSION_IMPERSONATION_KEY)
+}
+
+// Fields 审计日志中标识本次代入的字段
+func (s *Session) Fields() []zap.Field {
+	return []zap.Field{
+		zap.Uint("admin_id", s.AdminID),
+		zap.String("admin_name", s.AdminName),
+		zap.Uint("impersonated_user_id", s.UserID),
+		zap.String("impersonated_username", s.Username),
+		zap.String("reason", s.Reason),
+		zap.Time("started_at", time.Unix(s.StartedAt, 0)),
+	}
+}
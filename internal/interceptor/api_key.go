@@ -0,0 +1,24 @@
+package interceptor
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/pkg/errors"
+)
+
+// NotAPIKey 拒绝通过 API Key 认证的请求，泄露的 Key 不能用来创建新 Key 或吊销其他 Key
+func (i *interceptor) NotAPIKey() common.HandlerFunc {
+	return func(c common.Context) {
+		if !c.GetGinContext().GetBool(common.API_KEY_AUTH_KEY) {
+			return
+		}
+
+		c.AbortWithError(common.Error(
+			http.StatusForbidden,
+			code.APIKeyRestricted,
+			code.Text(code.APIKeyRestricted)).WithError(errors.New("api key management requires a login session")),
+		)
+	}
+}
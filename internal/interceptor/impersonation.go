@@ -0,0 +1,32 @@
+package interceptor
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/impersonation"
+	"gin-app-start/pkg/errors"
+)
+
+// NotImpersonating 代入期间当前用户是被代入的用户，修改密码、重新验证身份等只能由本人执行的操作一律拒绝
+func (i *interceptor) NotImpersonating() common.HandlerFunc {
+	return func(c common.Context) {
+		abortImpersonating(c)
+	}
+}
+
+// abortImpersonating 正在代入时中止请求并返回 true
+func abortImpersonating(c common.Context) bool {
+	imp, ok := impersonation.Get(c.GetSession())
+	if !ok {
+		return false
+	}
+
+	c.AbortWithError(common.Error(
+		http.StatusForbidden,
+		code.ImpersonationRestricted,
+		code.Text(code.ImpersonationRestricted)).WithError(errors.Errorf("%s is impersonating %s", imp.AdminName, imp.Username)),
+	)
+	return true
+}
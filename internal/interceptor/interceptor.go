@@ -1,7 +1,14 @@
 package interceptor
 
 import (
+	"time"
+
+	"gin-app-start/internal/activity"
 	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/httpcache"
+	"gin-app-start/internal/quota"
+	"gin-app-start/internal/reqstats"
 
 	"go.uber.org/zap"
 )
@@ -12,18 +19,89 @@ type Interceptor interface {
 	// SessionAuth 验证用户会话是否有效
 	SessionAuth() common.HandlerFunc
 
+	// Quota 限制路由组的请求配额，需放在 SessionAuth 之后才能按用户计数
+	Quota(group string) common.HandlerFunc
+
+	// UserConcurrency 按用户统计并发请求数和请求总数并限制并发，需放在认证拦截器之后
+	UserConcurrency() common.HandlerFunc
+
+	// RecentAuth 敏感操作要求最近验证过身份，需放在 SessionAuth 之后
+	RecentAuth(fields ...string) common.HandlerFunc
+
+	// NotImpersonating 管理员代入其他用户期间禁止访问，需放在 SessionAuth 之后
+	NotImpersonating() common.HandlerFunc
+
+	// NotAPIKey 禁止通过 API Key 认证的请求访问，需放在 SessionAuth 之后
+	NotAPIKey() common.HandlerFunc
+
+	// ResponseCache 按缓存名缓存 GET 请求的成功响应，只能用于响应与具体用户无关的接口
+	ResponseCache(name string) common.HandlerFunc
+
 	// i 为了避免被其他包实现
 	i()
 }
 
 type interceptor struct {
-	logger *zap.Logger
+	logger   *zap.Logger
+	quota    *quota.Limiter
+	activity *activity.Tracker
+	// requests 为 nil 时 UserConcurrency 不统计
+	requests *reqstats.Tracker
+	// responses 为 nil 时 ResponseCache 不缓存
+	responses *httpcache.Cache
+
+	reauthMaxAge time.Duration
+}
+
+// Option 拦截器选项
+type Option func(*interceptor)
+
+// WithQuota 启用请求配额，limiter 为 nil 时 Quota 不做限制
+func WithQuota(limiter *quota.Limiter) Option {
+	return func(i *interceptor) {
+		i.quota = limiter
+	}
 }
 
-func New(logger *zap.Logger) Interceptor {
-	return &interceptor{
-		logger: logger,
+// WithRequestStats 启用按用户的请求统计和并发上限，tracker 为 nil 时不统计
+func WithRequestStats(tracker *reqstats.Tracker) Option {
+	return func(i *interceptor) {
+		i.requests = tracker
+	}
+}
+
+// WithActivity 启用会话空闲超时和绝对超时，tracker 为 nil 时不检查
+func WithActivity(tracker *activity.Tracker) Option {
+	return func(i *interceptor) {
+		i.activity = tracker
+	}
+}
+
+// WithResponseCache 启用响应缓存，cache 为 nil 时 ResponseCache 不缓存
+func WithResponseCache(cache *httpcache.Cache) Option {
+	return func(i *interceptor) {
+		i.responses = cache
+	}
+}
+
+// WithReauthMaxAge 设置敏感操作要求的身份验证有效期，默认取 SessionConfig 的默认值
+func WithReauthMaxAge(maxAge time.Duration) Option {
+	return func(i *interceptor) {
+		if maxAge > 0 {
+			i.reauthMaxAge = maxAge
+		}
+	}
+}
+
+func New(logger *zap.Logger, opts ...Option) Interceptor {
+	i := &interceptor{
+		logger:       logger,
+		reauthMaxAge: config.SessionConfig{}.ReauthWindow(),
+	}
+	for _, opt := range opts {
+		opt(i)
 	}
+	return i
 }
 
 func (i *interceptor) i() {}
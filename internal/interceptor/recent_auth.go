@@ -0,0 +1,80 @@
+package interceptor
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/pkg/errors"
+)
+
+// RecentAuth 敏感操作要求最近验证过身份(登录或 /users/reauth)，超过 reauth_max_age 返回 401，客户端重新验证后重试
+// fields 为空时总是要求；不为空时仅当 JSON 请求体设置了其中任一字段才要求，如修改邮箱
+// 管理员代入其他用户期间，最近的验证是管理员本人的，不能用于被代入用户的敏感操作，直接拒绝
+func (i *interceptor) RecentAuth(fields ...string) common.HandlerFunc {
+	return func(c common.Context) {
+		if len(fields) > 0 && !hasJSONField(c.RawData(), fields) {
+			return
+		}
+		if abortImpersonating(c) {
+			return
+		}
+
+		authAt, ok := reauthTime(c.GetSession().Get(common.SESSION_REAUTH_KEY))
+		// jwt 认证的请求以令牌中的 auth_time 为准，见 middleware.JWTAuth
+		if value, exists := c.GetGinContext().Get(common.JWT_AUTH_TIME_KEY); exists {
+			authAt, ok = reauthTime(value)
+		}
+		// API Key 认证的请求没有验证时间，同时携带的会话也不算数
+		if c.GetGinContext().GetBool(common.API_KEY_AUTH_KEY) {
+			ok = false
+		}
+		if ok && time.Since(authAt) < i.reauthMaxAge {
+			return
+		}
+
+		c.SetHeader("X-Reauth-Required", "true")
+		c.AbortWithError(common.Error(
+			http.StatusUnauthorized,
+			code.ReauthRequired,
+			code.Text(code.ReauthRequired)).WithError(errors.New("recent authentication required")),
+		)
+	}
+}
+
+// reauthTime 解析会话中记录的验证时间(Unix 秒)，JSON 序列化的会话中数字会被还原为 float64
+func reauthTime(value interface{}) (time.Time, bool) {
+	var sec int64
+	switch v := value.(type) {
+	case int64:
+		sec = v
+	case float64:
+		sec = int64(v)
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		sec = n
+	default:
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0), sec > 0
+}
+
+// hasJSONField 请求体是否设置了 fields 中任一字段(值不为 null 或空字符串)
+func hasJSONField(body []byte, fields []string) bool {
+	var values map[string]json.RawMessage
+	if json.Unmarshal(body, &values) != nil {
+		return false
+	}
+	for _, field := range fields {
+		if raw, ok := values[field]; ok && string(raw) != "null" && string(raw) != `""` {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package interceptor
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/httpcache"
+	"gin-app-start/internal/metrics"
+	"gin-app-start/internal/redis"
+
+	"go.uber.org/zap"
+)
+
+// 响应缓存区分的用户角色，同一角色看到的响应相同
+const (
+	roleGuest = "guest"
+	roleUser  = "user"
+	roleAdmin = "admin"
+)
+
+// ResponseCache 缓存 GET 请求的成功响应，缓存键由缓存名、请求路径、查询参数和用户角色组成
+// 只能用于响应与具体用户无关的接口；放在 SessionAuth 之后时按登录用户的角色区分，否则都按游客缓存
+func (i *interceptor) ResponseCache(name string) common.HandlerFunc {
+	return func(c common.Context) {
+		if i.responses.TTL(name) <= 0 || c.Method() != http.MethodGet || c.GetGinContext().GetBool(common.NO_RESPONSE_CACHE_KEY) {
+			return
+		}
+
+		role := roleGuest
+		if user, ok := sessionUser(c.SessionUserInfo()); ok {
+			role = roleUser
+			if user.UserName == common.ADMIN_NAME {
+				role = roleAdmin
+			}
+		}
+		variant := httpcache.Variant(c.Path(), c.Request().URL.Query().Encode(), role)
+		trace := redis.WithTrace(c.Trace())
+
+		if data, ok := i.responses.Get(name, variant, trace); ok {
+			metrics.ObserveCacheLookup("http_"+name, metrics.CacheHit)
+			c.SetHeader("X-Cache", "HIT")
+			c.Payload(json.RawMessage(data))
+			c.GetGinContext().Abort()
+			return
+		}
+		metrics.ObserveCacheLookup("http_"+name, metrics.CacheMiss)
+		c.SetHeader("X-Cache", "MISS")
+
+		c.GetGinContext().Next()
+
+		payload := c.GetPayload()
+		if c.AbortError() != nil || c.GetGinContext().IsAborted() || payload == nil {
+			return
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return
+		}
+		if err := i.responses.Set(name, variant, data, trace); err != nil {
+			c.Logger().Warn("save response cache failed", zap.String("cache", name), zap.Error(err))
+		}
+	}
+}
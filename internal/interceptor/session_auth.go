@@ -1,15 +1,28 @@
 package interceptor
 
 import (
+	"encoding/json"
 	"net/http"
+	"time"
 
+	"gin-app-start/internal/activity"
 	"gin-app-start/internal/code"
 	"gin-app-start/internal/common"
+	"gin-app-start/internal/impersonation"
 	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/logger"
+
+	"github.com/gin-contrib/sessions"
+	"go.uber.org/zap"
 )
 
 func (i *interceptor) SessionAuth() common.HandlerFunc {
 	return func(c common.Context) {
+		// 已通过 API Key 认证，见 middleware.APIKeyAuth
+		if c.GetGinContext().GetBool(common.API_KEY_AUTH_KEY) {
+			return
+		}
+
 		// 从服务端中获取session
 		session := c.GetSession()
 		sessionData := session.Get(common.SESSION_KEY)
@@ -21,6 +34,105 @@ func (i *interceptor) SessionAuth() common.HandlerFunc {
 			)
 			return
 		}
+
+		if !i.touchSession(c, session) {
+			return
+		}
+		sessionData = i.impersonate(c, session, sessionData)
 		c.SetSessionUserInfo(sessionData)
+
+		// 之后该请求输出的日志都带上当前用户
+		if user, ok := sessionUser(sessionData); ok {
+			c.AddLoggerFields(
+				zap.Uint("user_id", user.UserId),
+				zap.String("username", user.UserName),
+			)
+		}
+	}
+}
+
+// impersonate 管理员正在代入其他用户时返回被代入用户的会话数据，并在响应中提示、写审计日志；
+// 未代入或代入已到期时返回 sessionData 本身
+func (i *interceptor) impersonate(c common.Context, session sessions.Session, sessionData interface{}) interface{} {
+	imp, ok := impersonation.Get(session)
+	if !ok {
+		return sessionData
+	}
+
+	audit := logger.Module(c.Logger(), "audit")
+	if imp.Expired(time.Now()) {
+		impersonation.Clear(session)
+		session.Save()
+		audit.Info("impersonation expired", imp.Fields()...)
+		return sessionData
+	}
+
+	c.SetHeader(impersonation.Header, imp.AdminName)
+	c.SetBanner(imp.Banner())
+	c.AddLoggerFields(zap.String("impersonated_by", imp.AdminName))
+	audit.Info("impersonated request", append(imp.Fields(),
+		zap.String("method", c.Method()),
+		zap.String("path", c.Path()),
+	)...)
+	return imp.UserInfo
+}
+
+// touchSession 检查会话的空闲超时和绝对超时并滑动延长，会话已超时返回 false
+// Redis 不可用时放行，只记录日志
+func (i *interceptor) touchSession(c common.Context, session sessions.Session) bool {
+	if i.activity == nil {
+		return true
+	}
+
+	token, _ := session.Get(common.SESSION_ACTIVITY_KEY).(string)
+	if token == "" {
+		// 启用超时之前登录的会话没有令牌，从本次访问开始计时
+		token, err := i.activity.Begin(c.RequestContext())
+		if err != nil {
+			c.Logger().Warn("session activity begin failed, request allowed", zap.Error(err))
+			return true
+		}
+		session.Set(common.SESSION_ACTIVITY_KEY, token)
+		session.Save()
+		return true
+	}
+
+	err := i.activity.Touch(c.RequestContext(), token)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, activity.ErrIdleTimeout) || errors.Is(err, activity.ErrAbsoluteTimeout) {
+		session.Clear()
+		session.Save()
+		c.AbortWithError(common.Error(
+			http.StatusUnauthorized,
+			code.SessionExpired,
+			code.Text(code.SessionExpired)).WithError(err),
+		)
+		return false
+	}
+
+	c.Logger().Warn("session activity touch failed, request allowed", zap.Error(err))
+	return true
+}
+
+// sessionUserInfo 会话中的用户信息(只取日志需要的字段)
+type sessionUserInfo struct {
+	UserId   uint   `json:"userId"`
+	UserName string `json:"username"`
+}
+
+// sessionUser 从会话数据中解析用户 ID 和用户名
+func sessionUser(sessionData interface{}) (user sessionUserInfo, ok bool) {
+	var data []byte
+	switch v := sessionData.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return user, false
 	}
+	return user, json.Unmarshal(data, &user) == nil
 }
@@ -0,0 +1,55 @@
+package interceptor
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// UserConcurrency 按用户统计正在处理的请求数和请求总数，达到用户的并发上限时返回 429
+// 只统计已登录的请求，需放在认证拦截器之后；Redis 不可用时放行且不计数
+func (i *interceptor) UserConcurrency() common.HandlerFunc {
+	return func(c common.Context) {
+		if i.requests == nil {
+			return
+		}
+
+		user, ok := sessionUser(c.SessionUserInfo())
+		if !ok || user.UserName == "" {
+			return
+		}
+
+		admission, err := i.requests.Begin(c.RequestContext(), user.UserName)
+		if err != nil {
+			c.Logger().Warn("request stats failed, request allowed", zap.Error(err))
+			return
+		}
+
+		if admission.Limit > 0 {
+			c.SetHeader("X-Concurrency-Limit", strconv.Itoa(admission.Limit))
+		}
+		if !admission.Admitted {
+			c.SetHeader("Retry-After", "1")
+			c.AbortWithError(common.Error(
+				http.StatusTooManyRequests,
+				code.ConcurrencyLimited,
+				code.Text(code.ConcurrencyLimited)).WithError(errors.Errorf("%s has %d requests in progress, limit %d", user.UserName, admission.Concurrent, admission.Limit)),
+			)
+			return
+		}
+
+		// 客户端断开时请求的 context 已取消，结束记录不能随之取消
+		defer func() {
+			if err := i.requests.End(context.WithoutCancel(c.RequestContext()), user.UserName, admission.ID); err != nil {
+				c.Logger().Warn("request stats end failed", zap.Error(err))
+			}
+		}()
+		c.GetGinContext().Next()
+	}
+}
@@ -0,0 +1,251 @@
+package lockout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	KindAccount = "account" // 按用户名锁定
+	KindIP      = "ip"      // 按客户端 IP 锁定
+
+	// listScanCount 列出锁定记录时每次 SCAN 返回的键数量
+	listScanCount = 500
+)
+
+// LockedError 账号或 IP 已被锁定
+type LockedError struct {
+	Kind       string
+	Subject    string
+	RetryAfter time.Duration // 距离自动解锁的剩余时间
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s %s is locked for %s", e.Kind, e.Subject, e.RetryAfter.Round(time.Second))
+}
+
+// Lock 一条锁定记录
+type Lock struct {
+	Kind      string
+	Subject   string
+	Failures  int64
+	LockedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// lockValue 锁定键中保存的内容
+type lockValue struct {
+	Failures int64 `json:"failures"`
+	LockedAt int64 `json:"locked_at"`
+}
+
+// Guard 登录失败锁定
+//
+// 在 window 内同一账号连续失败 max_failures 次、或同一 IP 失败 ip_max_failures 次后锁定 duration，
+// 锁定期间直接拒绝登录，不再校验密码。失败计数和锁定记录都保存在 Redis 中，到期自动解除。
+type Guard struct {
+	repo redis.RedisRepository
+	cfg  config.LockoutConfig
+	now  func() time.Time
+}
+
+// NewGuard 创建登录锁定，未启用时返回 nil
+func NewGuard(repo redis.RedisRepository, cfg config.LockoutConfig) *Guard {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Guard{
+		repo: repo,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+const lockPrefix = "lockout:lock:"
+
+func failKey(kind, subject string) string {
+	return fmt.Sprintf("lockout:fail:%s:%s", kind, subject)
+}
+
+func lockKey(kind, subject string) string {
+	return lockPrefix + kind + ":" + subject
+}
+
+// maxFailures kind 对应的失败次数阈值，0 表示不锁定
+func (g *Guard) maxFailures(kind string) int64 {
+	if kind == KindIP {
+		return int64(g.cfg.IPMaxFailures)
+	}
+	return int64(g.cfg.MaxFailures)
+}
+
+// subjects 本次登录涉及的锁定对象
+func subjects(username, ip string) map[string]string {
+	return map[string]string{KindAccount: username, KindIP: ip}
+}
+
+// Check 账号或 IP 已被锁定时返回 *LockedError
+func (g *Guard) Check(ctx context.Context, username, ip string) error {
+	client := g.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+
+	for kind, subject := range subjects(username, ip) {
+		if subject == "" || g.maxFailures(kind) <= 0 {
+			continue
+		}
+		ttl, err := client.TTL(ctx, g.repo.Key(lockKey(kind, subject))).Result()
+		if err != nil {
+			return err
+		}
+		if ttl > 0 {
+			return &LockedError{Kind: kind, Subject: subject, RetryAfter: ttl}
+		}
+	}
+	return nil
+}
+
+// Fail 记录一次登录失败，达到阈值时锁定
+func (g *Guard) Fail(ctx context.Context, username, ip string) error {
+	client := g.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+
+	window := time.Duration(g.cfg.Window) * time.Second
+	for kind, subject := range subjects(username, ip) {
+		max := g.maxFailures(kind)
+		if subject == "" || max <= 0 {
+			continue
+		}
+
+		key := g.repo.Key(failKey(kind, subject))
+		var incr *goredis.IntCmd
+		if _, err := client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			incr = pipe.Incr(ctx, key)
+			pipe.ExpireNX(ctx, key, window)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if incr.Val() < max {
+			continue
+		}
+
+		value, _ := json.Marshal(lockValue{Failures: incr.Val(), LockedAt: g.now().Unix()})
+		if _, err := client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, g.repo.Key(lockKey(kind, subject)), value, time.Duration(g.cfg.Duration)*time.Second)
+			pipe.Del(ctx, key)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Succeed 登录成功后清除账号的失败计数，IP 的计数保留，避免用一个账号的成功登录掩护对其他账号的尝试
+func (g *Guard) Succeed(ctx context.Context, username string) error {
+	client := g.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+	return client.Del(ctx, g.repo.Key(failKey(KindAccount, username))).Err()
+}
+
+// List 列出当前所有锁定记录
+func (g *Guard) List(ctx context.Context) ([]Lock, error) {
+	client := g.repo.GetRedisClient()
+	if client == nil {
+		return nil, redis.ErrUnavailable
+	}
+
+	var (
+		locks  []Lock
+		cursor uint64
+	)
+	for {
+		keys, next, err := client.Scan(ctx, cursor, g.repo.Key(lockPrefix+"*"), listScanCount).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			// 键格式为 lockout:lock:{kind}:{subject}，IPv6 地址中含有冒号，只切分第一个
+			kind, subject, ok := strings.Cut(strings.TrimPrefix(key, g.repo.Key(lockPrefix)), ":")
+			if !ok {
+				continue
+			}
+			lock, err := g.get(ctx, client, kind, subject)
+			if err != nil {
+				return nil, err
+			}
+			if lock != nil {
+				locks = append(locks, *lock)
+			}
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return locks, nil
+}
+
+// get 读取一条锁定记录，已过期时返回 nil
+func (g *Guard) get(ctx context.Context, client *goredis.Client, kind, subject string) (*Lock, error) {
+	key := g.repo.Key(lockKey(kind, subject))
+
+	var (
+		get *goredis.StringCmd
+		ttl *goredis.DurationCmd
+	)
+	_, err := client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		ttl = pipe.TTL(ctx, key)
+		return nil
+	})
+	if err != nil && err != goredis.Nil {
+		return nil, err
+	}
+	if err == goredis.Nil || ttl.Val() <= 0 {
+		return nil, nil
+	}
+
+	var value lockValue
+	_ = json.Unmarshal([]byte(get.Val()), &value)
+	return &Lock{
+		Kind:      kind,
+		Subject:   subject,
+		Failures:  value.Failures,
+		LockedAt:  time.Unix(value.LockedAt, 0),
+		ExpiresAt: g.now().Add(ttl.Val()),
+	}, nil
+}
+
+// Unlock 解除锁定并清空失败计数，返回解除前的锁定记录，未锁定时返回 nil
+func (g *Guard) Unlock(ctx context.Context, kind, subject string) (*Lock, error) {
+	client := g.repo.GetRedisClient()
+	if client == nil {
+		return nil, redis.ErrUnavailable
+	}
+
+	lock, err := g.get(ctx, client, kind, subject)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Del(ctx, g.repo.Key(lockKey(kind, subject)), g.repo.Key(failKey(kind, subject))).Err(); err != nil {
+		return nil, err
+	}
+	return lock, nil
+}
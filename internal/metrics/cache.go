@@ -0,0 +1,24 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// 缓存查询结果
+const (
+	CacheHit   = "hit"   // 命中且数据可用(包括防穿透的空值)
+	CacheMiss  = "miss"  // 未命中，回源数据库
+	CacheStale = "stale" // 命中但数据无法解析，回源数据库
+
+	CacheExpired = "expired" // 命中但已逻辑过期，返回旧数据并在后台刷新
+)
+
+var cacheLookupsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "cache",
+	Name:      "lookups_total",
+	Help:      "Total number of cache lookups by cache name and result.",
+}, []string{"cache", "result"})
+
+// ObserveCacheLookup 记录一次缓存查询
+func ObserveCacheLookup(cache, result string) {
+	cacheLookupsTotal.WithLabelValues(cache, result).Inc()
+}
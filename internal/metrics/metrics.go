@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"net/http"
+	"sort"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/pkg/retry"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gin_app"
+
+// unmatchedRoute 未匹配到路由的请求统一使用该标签，避免原始 URL 导致标签基数爆炸
+const unmatchedRoute = "unmatched"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal *prometheus.CounterVec
+	httpDuration      *prometheus.HistogramVec
+	httpSLOThreshold  *prometheus.GaugeVec
+)
+
+// DefaultBuckets 默认的请求耗时直方图桶(单位秒)
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Init 根据配置注册 HTTP 指标
+//
+// 每个路由的 SLO 阈值会合并进直方图的桶边界，这样 le="<阈值>" 的计数恰好就是达标请求数，
+// 燃烧率告警可以直接用 rate(bucket{le=阈值}) / rate(count) 计算，不存在插值误差。
+func Init(cfg config.MetricsConfig) {
+	buckets := cfg.Buckets
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	for _, slo := range cfg.RouteSLOs {
+		buckets = append(buckets, slo.Threshold)
+	}
+
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests by route template.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route template.",
+		Buckets:   normalizeBuckets(buckets),
+	}, []string{"method", "route", "status"})
+
+	httpSLOThreshold = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "route_slo_threshold_seconds",
+		Help:      "Configured latency SLO threshold per route template.",
+	}, []string{"method", "route"})
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpDuration,
+		httpSLOThreshold,
+		cacheLookupsTotal,
+		retriesTotal,
+		retryCallsTotal,
+		rateLimitRequestsTotal,
+		rateLimitTokensRemaining,
+		rateLimitCapacity,
+		sessionEventsTotal,
+		viewsTotal,
+	)
+	retry.SetObserver(ObserveRetry)
+
+	for _, slo := range cfg.RouteSLOs {
+		httpSLOThreshold.WithLabelValues(slo.Method, slo.Route).Set(slo.Threshold)
+	}
+}
+
+// Enabled 是否已初始化
+func Enabled() bool {
+	return httpDuration != nil
+}
+
+// Register 注册自定义指标
+func Register(cs ...prometheus.Collector) {
+	registry.MustRegister(cs...)
+}
+
+// Handler 暴露 /metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTP 记录一次 HTTP 请求
+// route 必须是路由模板(如 /api/v1/users/:id)，而不是原始 URL
+func ObserveHTTP(method, route, status string, seconds float64) {
+	if !Enabled() {
+		return
+	}
+
+	if route == "" {
+		route = unmatchedRoute
+	}
+
+	httpRequestsTotal.WithLabelValues(method, route, status).Inc()
+	httpDuration.WithLabelValues(method, route, status).Observe(seconds)
+}
+
+// normalizeBuckets 排序并去重，prometheus 要求桶边界严格递增
+func normalizeBuckets(buckets []float64) []float64 {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	res := sorted[:0]
+	for _, b := range sorted {
+		if b <= 0 || (len(res) > 0 && res[len(res)-1] == b) {
+			continue
+		}
+		res = append(res, b)
+	}
+	return res
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var rateLimitRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "rate_limit",
+	Name:      "requests_total",
+	Help:      "Total number of requests checked by the per-IP rate limiter, by result (allowed, rejected).",
+}, []string{"result"})
+
+// rateLimitTokensRemaining 使用剩余令牌占桶容量的比例而不是绝对值，调整 server.limit_num 后桶边界不需要跟着改
+var rateLimitTokensRemaining = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "rate_limit",
+	Name:      "tokens_remaining_ratio",
+	Help:      "Tokens left in the client's bucket after each request, as a fraction of the bucket capacity. Rejected requests observe 0.",
+	Buckets:   []float64{0, 0.1, 0.25, 0.5, 0.75, 0.9, 1},
+})
+
+var rateLimitCapacity = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Subsystem: "rate_limit",
+	Name:      "bucket_capacity",
+	Help:      "Configured bucket capacity and refill rate per second (server.limit_num).",
+})
+
+// SetRateLimitCapacity 记录限速的桶容量
+func SetRateLimitCapacity(capacity int) {
+	rateLimitCapacity.Set(float64(capacity))
+}
+
+// ObserveRateLimit 记录一次限速检查，remaining 为本次请求消耗后剩余的令牌数
+func ObserveRateLimit(allowed bool, remaining, capacity int) {
+	result := "allowed"
+	if !allowed {
+		result = "rejected"
+	}
+	rateLimitRequestsTotal.WithLabelValues(result).Inc()
+
+	if capacity > 0 {
+		rateLimitTokensRemaining.Observe(float64(remaining) / float64(capacity))
+	}
+}
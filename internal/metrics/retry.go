@@ -0,0 +1,30 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var retriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "retry",
+	Name:      "retries_total",
+	Help:      "Total number of retries performed by pkg/retry, by operation.",
+}, []string{"operation"})
+
+var retryCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "retry",
+	Name:      "calls_total",
+	Help:      "Total number of pkg/retry calls by operation and final result.",
+}, []string{"operation", "result"})
+
+// ObserveRetry 记录一次 pkg/retry 调用，作为 retry.Observer 注册
+func ObserveRetry(name string, retried int, err error) {
+	if retried > 0 {
+		retriesTotal.WithLabelValues(name).Add(float64(retried))
+	}
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	retryCallsTotal.WithLabelValues(name, result).Inc()
+}
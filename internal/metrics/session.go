@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// 会话事件
+const (
+	SessionCreated         = "created"          // 登录
+	SessionLoggedOut       = "logout"           // 主动登出
+	SessionIdleTimeout     = "idle_timeout"     // 空闲超时
+	SessionAbsoluteTimeout = "absolute_timeout" // 超过最长有效期
+)
+
+var sessionEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "session",
+	Name:      "events_total",
+	Help:      "Total number of session lifecycle events (created, logout, idle_timeout, absolute_timeout).",
+}, []string{"event"})
+
+// ObserveSession 记录一次会话事件
+func ObserveSession(event string) {
+	sessionEventsTotal.WithLabelValues(event).Inc()
+}
+
+// activeSessionsCollector 每次抓取时统计一次活跃会话数
+type activeSessionsCollector struct {
+	desc  *prometheus.Desc
+	count func(ctx context.Context) (int64, error)
+}
+
+func (c *activeSessionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *activeSessionsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	n, err := c.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n))
+}
+
+// RegisterActiveSessions 注册活跃会话数指标，count 在每次抓取 /metrics 时调用
+func RegisterActiveSessions(count func(ctx context.Context) (int64, error)) {
+	registry.MustRegister(&activeSessionsCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "session", "active"),
+			"Number of sessions that have neither idled out nor expired.",
+			nil, nil,
+		),
+		count: count,
+	})
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// 浏览计数的去向
+const (
+	ViewsBuffered = "buffered" // 累加到 Redis，等待落库
+	ViewsDirect   = "direct"   // 直接写入数据库(Redis 未启用或写入失败)
+	ViewsFlushed  = "flushed"  // 从 Redis 落库
+	ViewsDropped  = "dropped"  // 按 views.loss_tolerant 丢弃
+)
+
+var viewsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "views",
+	Name:      "total",
+	Help:      "Total number of recorded views by subject and outcome (buffered, direct, flushed, dropped).",
+}, []string{"subject", "outcome"})
+
+// ObserveViews 记录 n 次浏览的去向
+func ObserveViews(subject, outcome string, n int64) {
+	viewsTotal.WithLabelValues(subject, outcome).Add(float64(n))
+}
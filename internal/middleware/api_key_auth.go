@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+	"gin-app-start/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+// APIKeyHeader 机器客户端携带 API Key 的请求头
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator 校验 API Key 明文，返回 Key 的创建者
+type APIKeyAuthenticator interface {
+	Authenticate(ctx common.Context, key string) (*model.User, error)
+}
+
+// APIKeyAuth 请求带有 X-API-Key 时按 API Key 认证，注册在 SessionAuth 或 JWTAuth 之前；
+// 认证通过后设置 common.API_KEY_AUTH_KEY，之后的 SessionAuth、JWTAuth 直接放行，没有该请求头时不做处理
+//
+// 与 JWTAuth 一样按会话数据的格式写入 SessionUserInfo，控制器不区分认证方式。API Key 没有身份验证时间，
+// RecentAuth 保护的敏感操作一律要求重新登录。
+func APIKeyAuth(keys APIKeyAuthenticator) common.HandlerFunc {
+	return func(c common.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			return
+		}
+
+		// 与 JWTAuth 一样，认证失败一律返回 401，原因记录在错误日志中
+		user, err := keys.Authenticate(c, key)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusUnauthorized,
+				code.APIKeyInvalid,
+				code.Text(code.APIKeyInvalid)).WithError(errors.Wrap(err, "api key authentication failed")),
+			)
+			return
+		}
+
+		// 与登录时写入会话的数据格式一致，见 controller.userSession
+		data, err := json.Marshal(map[string]interface{}{
+			"userId":   user.ID,
+			"username": user.Username,
+		})
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusInternalServerError,
+				code.MarshalError,
+				code.Text(code.MarshalError)).WithError(err),
+			)
+			return
+		}
+
+		c.SetSessionUserInfo(string(data))
+		c.GetGinContext().Set(common.API_KEY_AUTH_KEY, true)
+		c.AddLoggerFields(
+			zap.Uint("user_id", user.ID),
+			zap.String("username", user.Username),
+			zap.String("auth", "api_key"),
+		)
+	}
+}
+
+// APIKeyAuthenticated 请求是否已通过 API Key 认证
+func APIKeyAuthenticated(c common.Context) bool {
+	return c.GetGinContext().GetBool(common.API_KEY_AUTH_KEY)
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/pkg/errors"
+)
+
+// BodyLimit 限制请求体大小，Content-Length 超出 limit 时直接返回 413；
+// 未声明长度(分块传输)的请求在读取超出时由绑定报错
+func BodyLimit(limit int64) common.HandlerFunc {
+	return func(c common.Context) {
+		req := c.Request()
+		if req.ContentLength > limit {
+			c.AbortWithError(common.Error(
+				http.StatusRequestEntityTooLarge,
+				code.RequestTooLarge,
+				code.Text(code.RequestTooLarge)).WithError(errors.Errorf("content length %d exceeds %d", req.ContentLength, limit)),
+			)
+			return
+		}
+		req.Body = http.MaxBytesReader(c.GetGinContext().Writer, req.Body, limit)
+	}
+}
+
+// NoResponseCache 关闭之后的 ResponseCache 拦截器
+func NoResponseCache(c common.Context) {
+	c.GetGinContext().Set(common.NO_RESPONSE_CACHE_KEY, true)
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// semaphore 基于带缓冲 channel 的信号量
+type semaphore chan struct{}
+
+// tryAcquire 非阻塞获取，满载时立即返回 false，不让请求排队等待
+func (s semaphore) tryAcquire() bool {
+	select {
+	case s <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s semaphore) release() {
+	<-s
+}
+
+// ConcurrencyLimit 限制同时处理的请求数(全局 + 单路由)
+//
+// 与 RateLimit 按客户端限制请求速率不同，这里限制的是服务端正在处理的请求总数，
+// 满载时直接返回 503 + Retry-After 进行削峰，避免请求堆积导致数据库连接被耗尽。
+func ConcurrencyLimit(cfg config.ConcurrencyConfig) gin.HandlerFunc {
+	var global semaphore
+	if cfg.MaxConcurrent > 0 {
+		global = make(semaphore, cfg.MaxConcurrent)
+	}
+
+	routes := make(map[string]semaphore, len(cfg.Routes))
+	for _, r := range cfg.Routes {
+		if r.Limit > 0 {
+			routes[r.Method+" "+r.Route] = make(semaphore, r.Limit)
+		}
+	}
+
+	retryAfter := cfg.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = 1
+	}
+
+	return func(c *gin.Context) {
+		if global != nil {
+			if !global.tryAcquire() {
+				abortServerBusy(c, retryAfter)
+				return
+			}
+			defer global.release()
+		}
+
+		// 使用路由模板匹配，如 /api/v1/users/:id
+		if route, ok := routes[c.Request.Method+" "+c.FullPath()]; ok {
+			if !route.tryAcquire() {
+				abortServerBusy(c, retryAfter)
+				return
+			}
+			defer route.release()
+		}
+
+		c.Next()
+	}
+}
+
+func abortServerBusy(c *gin.Context, retryAfter int) {
+	context := common.NewContext(c)
+	defer common.ReleaseContext(context)
+
+	c.Header("Retry-After", strconv.Itoa(retryAfter))
+	context.AbortWithError(common.Error(
+		http.StatusServiceUnavailable,
+		code.ServerBusy,
+		code.Text(code.ServerBusy)),
+	)
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"encoding/json"
+
+	"gin-app-start/internal/common"
+)
+
+// DebugHeader 值为 1 时请求在响应中附带链路信息，只对管理员生效
+const DebugHeader = "X-Debug"
+
+// debugRequested 请求头 X-Debug 为 1 且当前登录用户为管理员
+// 会话数据的格式见 controller.userSession，JWT 和 API Key 认证写入的格式相同
+func debugRequested(c common.Context) bool {
+	if c.GetHeader(DebugHeader) != "1" {
+		return false
+	}
+
+	var data []byte
+	switch v := c.SessionUserInfo().(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return false
+	}
+
+	var user struct {
+		UserName string `json:"username"`
+	}
+	if err := json.Unmarshal(data, &user); err != nil {
+		return false
+	}
+	return user.UserName == common.ADMIN_NAME
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/impersonation"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DedupHeader 共用了其他相同请求处理结果的响应带有该响应头
+const DedupHeader = "X-Dedup"
+
+// dedupResult 首个请求的处理结果，由同时到达的相同请求共用
+type dedupResult struct {
+	payload interface{}
+	err     common.BusinessError
+	header  http.Header
+	// ok 为 false 时结果不能共用: 处理函数直接写出了响应(文件下载、流式导出)，或中止时没有业务错误
+	ok bool
+}
+
+// Dedup 合并同时到达的相同 GET 请求，只有第一个请求执行之后的处理函数，其余请求等待并返回同一结果
+//
+// 相同请求指路径、查询参数、Accept-Language 以及请求者都相同，因此只会合并同一用户的请求。需放在认证拦截器之后、
+// 配额等路由拦截器之前: 请求者为认证后的用户(区分 API Key 认证和管理员代入)，每个请求都各自完成认证，
+// 共用结果的请求不再计入配额；未认证的路由组按凭证请求头(Authorization、Cookie、X-API-Key，都没有时为客户端 IP)区分。
+func Dedup() common.HandlerFunc {
+	var group singleflight.Group
+
+	return func(c common.Context) {
+		if c.Method() != http.MethodGet {
+			return
+		}
+
+		leader := false
+		v, _, _ := group.Do(dedupKey(c), func() (interface{}, error) {
+			leader = true
+			c.GetGinContext().Next()
+			return snapshotResult(c), nil
+		})
+		if leader {
+			return
+		}
+
+		// 无法共用时按普通请求继续处理
+		result := v.(*dedupResult)
+		if !result.ok {
+			return
+		}
+
+		header := c.GetGinContext().Writer.Header()
+		for key, values := range result.header {
+			if key == "Set-Cookie" || header.Get(key) != "" {
+				continue
+			}
+			header[key] = values
+		}
+		c.SetHeader(DedupHeader, "HIT")
+
+		if result.err != nil {
+			c.AbortWithError(result.err)
+			return
+		}
+		c.Payload(result.payload)
+		c.GetGinContext().Abort()
+	}
+}
+
+// dedupKey 请求的去重键，请求者只参与哈希，不保存原值
+func dedupKey(c common.Context) string {
+	req := c.Request()
+	sum := sha256.Sum256([]byte(req.URL.Path + "?" + req.URL.Query().Encode() + "\n" + req.Header.Get("Accept-Language") + "\n" + requester(c)))
+	return hex.EncodeToString(sum[:])
+}
+
+// requester 请求者: 已认证时为认证拦截器写入的用户信息，加上认证方式和代入的管理员，
+// 同一用户通过 API Key 和会话发起的请求、管理员代入期间的请求受不同的拦截器限制，不能共用结果
+func requester(c common.Context) string {
+	if user := c.SessionUserInfo(); user != nil {
+		return fmt.Sprintf("user:%v\napi_key:%t\nimpersonated_by:%s", user, APIKeyAuthenticated(c),
+			c.GetGinContext().Writer.Header().Get(impersonation.Header))
+	}
+
+	req := c.Request()
+	credential := req.Header.Get("Authorization") + "\n" + req.Header.Get("Cookie") + "\n" + req.Header.Get(APIKeyHeader)
+	if credential == "\n\n" {
+		credential = c.GetGinContext().ClientIP()
+	}
+	return "anonymous:" + credential
+}
+
+func snapshotResult(c common.Context) *dedupResult {
+	ginCtx := c.GetGinContext()
+	result := &dedupResult{
+		payload: c.GetPayload(),
+		err:     c.AbortError(),
+		header:  ginCtx.Writer.Header().Clone(),
+	}
+	result.ok = !ginCtx.Writer.Written() && (result.err != nil || (result.payload != nil && !ginCtx.IsAborted()))
+	return result
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/hooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Hooks 执行请求生命周期钩子，需注册在 Logger 之后，钩子中可以使用请求级 Logger 和 trace，
+// 并在 Logger 写出响应之前调用 OnError、OnResponse
+func Hooks(registry *hooks.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		context := common.NewContext(c)
+		defer common.ReleaseContext(context)
+
+		registry.Request(context)
+		c.Next()
+
+		if err := context.AbortError(); err != nil {
+			registry.Error(context, err)
+		}
+		registry.Response(context)
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/jwt"
+
+	"go.uber.org/zap"
+)
+
+// JWTAuth auth.mode 为 jwt 时代替 SessionAuth 验证访问令牌(Authorization: Bearer <access_token>)
+//
+// 令牌中的用户ID和用户名按会话数据的格式写入 SessionUserInfo，控制器不区分认证方式；
+// 令牌的 auth_time 写入 common.JWT_AUTH_TIME_KEY，供 RecentAuth 判断是否最近验证过身份。
+// 与路由组拦截器一样注册在需要登录的路由组上。
+func JWTAuth(tokens *jwt.Manager) common.HandlerFunc {
+	return func(c common.Context) {
+		if APIKeyAuthenticated(c) {
+			return
+		}
+
+		scheme, token, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.SetHeader("WWW-Authenticate", "Bearer")
+			c.AbortWithError(common.Error(
+				http.StatusUnauthorized,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(errors.New("bearer token not found")),
+			)
+			return
+		}
+
+		claims, err := tokens.Parse(token, jwt.TypeAccess)
+		if err != nil {
+			bizCode := code.AuthorizationError
+			if errors.Is(err, jwt.ErrExpired) {
+				bizCode = code.TokenExpired
+			}
+			c.SetHeader("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithError(common.Error(
+				http.StatusUnauthorized,
+				bizCode,
+				code.Text(bizCode)).WithError(err),
+			)
+			return
+		}
+
+		userID, err := jsonNumber(claims.Subject)
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusUnauthorized,
+				code.AuthorizationError,
+				code.Text(code.AuthorizationError)).WithError(err),
+			)
+			return
+		}
+
+		// 与登录时写入会话的数据格式一致，见 controller.userSession
+		data, err := json.Marshal(map[string]interface{}{
+			"userId":   userID,
+			"username": claims.Username,
+		})
+		if err != nil {
+			c.AbortWithError(common.Error(
+				http.StatusInternalServerError,
+				code.MarshalError,
+				code.Text(code.MarshalError)).WithError(err),
+			)
+			return
+		}
+
+		c.SetSessionUserInfo(string(data))
+		c.GetGinContext().Set(common.JWT_AUTH_TIME_KEY, claims.AuthTime)
+		c.GetGinContext().Set(common.JWT_FAMILY_KEY, claims.Family)
+		c.AddLoggerFields(
+			zap.String("user_id", claims.Subject),
+			zap.String("username", claims.Username),
+		)
+	}
+}
+
+// jsonNumber 校验令牌中的用户ID为数字，按数字写入用户信息
+func jsonNumber(subject string) (json.Number, error) {
+	n := json.Number(subject)
+	if _, err := n.Int64(); err != nil {
+		return "", errors.Errorf("invalid token subject %q", subject)
+	}
+	return n, nil
+}
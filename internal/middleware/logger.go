@@ -10,13 +10,44 @@ import (
 
 	"gin-app-start/internal/code"
 	"gin-app-start/internal/common"
+	"gin-app-start/pkg/response"
 
 	"github.com/gin-gonic/gin"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
 
-func Logger(logger *zap.Logger) gin.HandlerFunc {
+// LoggerOption Logger 中间件配置项
+type LoggerOption func(*loggerOption)
+
+type loggerOption struct {
+	slowLogger    *zap.Logger
+	slowThreshold time.Duration
+	debugTrace    bool
+}
+
+// WithSlowRequestLog 请求耗时超过 threshold 时，向 slowLogger 输出一条 WARN 日志
+func WithSlowRequestLog(slowLogger *zap.Logger, threshold time.Duration) LoggerOption {
+	return func(opt *loggerOption) {
+		opt.slowLogger = slowLogger
+		opt.slowThreshold = threshold
+	}
+}
+
+// WithDebugTrace 管理员携带请求头 X-Debug: 1 时，在响应的 debug 字段中返回本次请求的链路信息
+func WithDebugTrace() LoggerOption {
+	return func(opt *loggerOption) {
+		opt.debugTrace = true
+	}
+}
+
+func Logger(logger *zap.Logger, options ...LoggerOption) gin.HandlerFunc {
+	opt := new(loggerOption)
+	for _, f := range options {
+		f(opt)
+	}
+
 	return func(c *gin.Context) {
 		if c.Writer.Status() == http.StatusNotFound {
 			return
@@ -28,20 +59,30 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 		defer common.ReleaseContext(context)
 
 		context.Init()
-		context.SetLogger(logger)
 
+		// 没有 TRACE-ID 请求头时使用 OpenTelemetry 链路的 trace_id，日志和追踪后端中的链路可以互相查找
 		if traceId := context.GetHeader(trace.Header); traceId != "" {
 			context.SetTrace(trace.New(traceId))
+		} else if span := oteltrace.SpanContextFromContext(c.Request.Context()); span.HasTraceID() {
+			context.SetTrace(trace.New(span.TraceID().String()))
 		} else {
 			context.SetTrace(trace.New(""))
 		}
 
+		// 请求级 Logger: 业务代码通过 context.Logger() 输出的日志都带上 trace_id 和路由，便于按请求关联
+		context.SetLogger(logger.With(
+			zap.String("trace_id", context.Trace().ID()),
+			zap.String("route", c.FullPath()),
+		))
+
 		defer func() {
 			var (
-				response        interface{}
+				resp            interface{}
 				businessCode    int
 				businessCodeMsg string
 				abortErr        error
+				errorFields     map[string]interface{}
+				panicked        bool
 				// traceId         string
 			)
 
@@ -50,10 +91,19 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 				// traceId = ct.ID()
 			}
 
+			// 认证在 Logger 之后执行，此时已能拿到当前用户；链路信息截至响应写出前
+			var debugSummary *trace.Summary
+			if opt.debugTrace && debugRequested(context) {
+				if ct, ok := context.Trace().(*trace.Trace); ok {
+					debugSummary = ct.Summarize(time.Since(start))
+				}
+			}
+
 			// region 发生 panic 时，记录日志并返回服务器错误
 			if err := recover(); err != nil {
 				stackInfo := string(debug.Stack())
-				logger.Error("got panic", zap.String("panic", fmt.Sprintf("%+v", err)), zap.String("stack", stackInfo))
+				context.Logger().Error("got panic", zap.String("panic", fmt.Sprintf("%+v", err)), zap.String("stack", stackInfo))
+				panicked = true
 				context.AbortWithError(common.Error(
 					http.StatusInternalServerError,
 					code.ServerError,
@@ -77,19 +127,52 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 					multierr.AppendInto(&abortErr, err.StackError())
 					businessCode = err.BusinessCode()
 					businessCodeMsg = err.Message()
-					response = &code.Failure{
-						Code:    businessCode,
-						Message: businessCodeMsg,
+					errorFields = err.Fields()
+					// 只有服务器内部错误(panic)时返回 trace_id，便于用户反馈问题时定位日志
+					var traceID string
+					if ct := context.Trace(); panicked && ct != nil {
+						traceID = ct.ID()
 					}
-					c.JSON(err.HTTPCode(), response)
+					// 错误信息按请求的 Accept-Language 返回，日志中仍记录配置语言的原文
+					fail := response.Localize(c, response.Fail(businessCode, businessCodeMsg, traceID))
+					fail.Details = err.Details()
+					fail.Data = err.Data()
+					fail.Banner = context.Banner()
+					resp = fail
+					// 链路信息已完整记录在 trace-log 中，日志里的响应体不再重复
+					fail.Debug = debugSummary
+					c.JSON(err.HTTPCode(), fail)
 				}
 			}
 			// endregion
 
 			// region 正确返回
-			response = context.GetPayload()
-			if response != nil {
-				c.JSON(http.StatusOK, response)
+			if payload := context.GetPayload(); payload != nil {
+				ok := response.Localize(c, response.OK(payload))
+				ok.Banner = context.Banner()
+				resp = ok
+				ok.Debug = debugSummary
+				c.JSON(http.StatusOK, ok)
+			}
+			// endregion
+
+			// region 记录慢请求
+			if cost := time.Since(start); opt.slowLogger != nil && opt.slowThreshold > 0 && cost > opt.slowThreshold {
+				var traceID string
+				if ct := context.Trace(); ct != nil {
+					traceID = ct.ID()
+				}
+
+				opt.slowLogger.Warn("slow-request",
+					zap.String("method", c.Request.Method),
+					zap.String("route", c.FullPath()),
+					zap.String("path", c.Request.URL.Path),
+					zap.Int("http_code", c.Writer.Status()),
+					zap.Int("business_code", businessCode),
+					zap.Float64("cost_seconds", cost.Seconds()),
+					zap.Float64("threshold_seconds", opt.slowThreshold.Seconds()),
+					zap.String("trace_id", traceID),
+				)
 			}
 			// endregion
 
@@ -119,8 +202,8 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 
 			var responseBody interface{}
 
-			if response != nil {
-				responseBody = response
+			if resp != nil {
+				responseBody = resp
 			}
 
 			t.WithResponse(&trace.Response{
@@ -147,6 +230,7 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 				zap.Any("cost_seconds", t.CostSeconds),
 				zap.Any("trace_id", t.Identifier),
 				zap.Any("trace_info", t),
+				zap.Any("error_fields", errorFields),
 				zap.Error(abortErr),
 			)
 			// endregion
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"gin-app-start/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics 记录 HTTP 请求指标
+// 使用 c.FullPath() 作为路由标签(如 /api/v1/users/:id)，避免原始 URL 造成的标签基数爆炸
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		metrics.ObserveHTTP(
+			c.Request.Method,
+			c.FullPath(),
+			strconv.Itoa(c.Writer.Status()),
+			time.Since(start).Seconds(),
+		)
+	}
+}
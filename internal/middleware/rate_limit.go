@@ -1,116 +1,186 @@
-package middleware
-
-import (
-	"net/http"
-	"sync"
-	"time"
-
-	"gin-app-start/internal/code"
-	"gin-app-start/internal/common"
-
-	"github.com/gin-gonic/gin"
-)
-
-type rateLimiter struct {
-	rate       int                  // 每秒允许的请求数
-	lastAccess map[string]time.Time // 记录每个客户端的最后访问时间
-	tokens     map[string]int       // 记录每个客户端当前可用的令牌数
-	mu         sync.Mutex           // 互斥锁，用于保护对 lastAccess 和 tokens 的并发访问
-}
-
-// newRateLimiter 创建一个新的速率限制器
-func newRateLimiter(rate int) *rateLimiter {
-	limiter := &rateLimiter{
-		rate:       rate,
-		lastAccess: make(map[string]time.Time),
-		tokens:     make(map[string]int),
-	}
-
-	go limiter.cleanup()
-
-	return limiter
-}
-
-// allow 检查是否允许当前请求
-// 基于令牌桶算法的速率限制
-func (rl *rateLimiter) allow(key string) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	now := time.Now()
-	lastTime, exists := rl.lastAccess[key]
-
-	// 如果是第一次访问，初始化令牌数为 rate - 1
-	if !exists {
-		rl.lastAccess[key] = now
-		rl.tokens[key] = rl.rate - 1
-		return true
-	}
-
-	// 计算距离上次访问经过了多少秒
-	elapsed := now.Sub(lastTime).Seconds()
-
-	// 计算距离上次访问经过了多少秒，将其转换为令牌数
-	// 若rate=100（每秒最多100次请求），elapsed < 0.01s，tokensToAdd = 0
-	tokensToAdd := int(elapsed * float64(rl.rate))
-
-	if tokensToAdd > 0 {
-		rl.tokens[key] += tokensToAdd
-		if rl.tokens[key] > rl.rate {
-			rl.tokens[key] = rl.rate
-		}
-		rl.lastAccess[key] = now
-	}
-
-	if rl.tokens[key] > 0 {
-		rl.tokens[key]--
-		return true
-	}
-
-	return false
-}
-
-// cleanup 定期清理过期的访问记录
-func (rl *rateLimiter) cleanup() {
-	ticker := time.NewTicker(time.Minute) // 每分钟执行一次清理
-	defer ticker.Stop()
-
-	// 清理过期的访问记录，保留最近 5 分钟内的记录
-	for range ticker.C {
-		rl.mu.Lock()
-		now := time.Now()
-		for key, lastTime := range rl.lastAccess {
-			if now.Sub(lastTime) > 5*time.Minute {
-				delete(rl.lastAccess, key)
-				delete(rl.tokens, key)
-			}
-		}
-		rl.mu.Unlock()
-	}
-}
-
-var globalLimiter *rateLimiter
-
-func RateLimit(rate int) gin.HandlerFunc {
-	if globalLimiter == nil {
-		globalLimiter = newRateLimiter(rate)
-	}
-
-	return func(c *gin.Context) {
-		key := c.ClientIP()
-
-		context := common.NewContext(c)
-		defer common.ReleaseContext(context)
-
-		if !globalLimiter.allow(key) {
-			context.AbortWithError(common.Error(
-				http.StatusTooManyRequests,
-				code.TooManyRequests,
-				code.Text(code.TooManyRequests)),
-			)
-			return
-		}
-
-		c.Next()
-	}
-}
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+type rateLimiter struct {
+	rate       int                  // 每秒允许的请求数
+	lastAccess map[string]time.Time // 记录每个客户端的最后访问时间
+	tokens     map[string]int       // 记录每个客户端当前可用的令牌数
+	mu         sync.Mutex           // 互斥锁，用于保护对 lastAccess 和 tokens 的并发访问
+}
+
+// newRateLimiter 创建一个新的速率限制器
+func newRateLimiter(rate int) *rateLimiter {
+	limiter := &rateLimiter{
+		rate:       rate,
+		lastAccess: make(map[string]time.Time),
+		tokens:     make(map[string]int),
+	}
+
+	go limiter.cleanup()
+
+	return limiter
+}
+
+// allow 检查是否允许当前请求，同时返回本次请求之后剩余的令牌数
+// 基于令牌桶算法的速率限制
+func (rl *rateLimiter) allow(key string) (bool, int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	lastTime, exists := rl.lastAccess[key]
+
+	// 如果是第一次访问，初始化令牌数为 rate - 1
+	if !exists {
+		rl.lastAccess[key] = now
+		rl.tokens[key] = rl.rate - 1
+		return true, rl.tokens[key]
+	}
+
+	// 计算距离上次访问经过了多少秒
+	elapsed := now.Sub(lastTime).Seconds()
+
+	// 计算距离上次访问经过了多少秒，将其转换为令牌数
+	// 若rate=100（每秒最多100次请求），elapsed < 0.01s，tokensToAdd = 0
+	tokensToAdd := int(elapsed * float64(rl.rate))
+
+	if tokensToAdd > 0 {
+		rl.tokens[key] += tokensToAdd
+		if rl.tokens[key] > rl.rate {
+			rl.tokens[key] = rl.rate
+		}
+		rl.lastAccess[key] = now
+	}
+
+	if rl.tokens[key] > 0 {
+		rl.tokens[key]--
+		return true, rl.tokens[key]
+	}
+
+	return false, 0
+}
+
+// cleanup 定期清理过期的访问记录
+func (rl *rateLimiter) cleanup() {
+	ticker := time.NewTicker(time.Minute) // 每分钟执行一次清理
+	defer ticker.Stop()
+
+	// 清理过期的访问记录，保留最近 5 分钟内的记录
+	for range ticker.C {
+		rl.mu.Lock()
+		now := time.Now()
+		for key, lastTime := range rl.lastAccess {
+			if now.Sub(lastTime) > 5*time.Minute {
+				delete(rl.lastAccess, key)
+				delete(rl.tokens, key)
+			}
+		}
+		rl.mu.Unlock()
+	}
+}
+
+var globalLimiter *rateLimiter
+
+func RateLimit(rate int) gin.HandlerFunc {
+	if globalLimiter == nil {
+		globalLimiter = newRateLimiter(rate)
+		metrics.SetRateLimitCapacity(rate)
+	}
+
+	return func(c *gin.Context) {
+		key := c.ClientIP()
+
+		context := common.NewContext(c)
+		defer common.ReleaseContext(context)
+
+		allowed, remaining := globalLimiter.allow(key)
+		metrics.ObserveRateLimit(allowed, remaining, globalLimiter.rate)
+		if !allowed {
+			context.AbortWithError(common.Error(
+				http.StatusTooManyRequests,
+				code.TooManyRequests,
+				code.Text(code.TooManyRequests)),
+			)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GroupRateLimit 路由组单独的限速，每个客户端 IP 每秒最多 rate 个请求，与全局 RateLimit 分别计数
+// 每次调用创建新的限速器，同一路由组的路由应共用返回的拦截器
+func GroupRateLimit(rate int) common.HandlerFunc {
+	limiter := newRateLimiter(rate)
+
+	return func(c common.Context) {
+		if allowed, _ := limiter.allow(c.GetGinContext().ClientIP()); !allowed {
+			c.AbortWithError(common.Error(
+				http.StatusTooManyRequests,
+				code.TooManyRequests,
+				code.Text(code.TooManyRequests)),
+			)
+		}
+	}
+}
+
+// RateLimitedClient 令牌已耗尽、当前请求会被拒绝的客户端
+type RateLimitedClient struct {
+	IP         string
+	LastAccess time.Time
+}
+
+// limited 返回令牌已耗尽的客户端
+func (rl *rateLimiter) limited() []RateLimitedClient {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	var clients []RateLimitedClient
+	for key, lastTime := range rl.lastAccess {
+		refill := int(now.Sub(lastTime).Seconds() * float64(rl.rate))
+		if rl.tokens[key]+refill <= 0 {
+			clients = append(clients, RateLimitedClient{IP: key, LastAccess: lastTime})
+		}
+	}
+	return clients
+}
+
+// reset 清除客户端的限速记录，返回是否存在记录
+func (rl *rateLimiter) reset(key string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	_, ok := rl.lastAccess[key]
+	delete(rl.lastAccess, key)
+	delete(rl.tokens, key)
+	return ok
+}
+
+// RateLimitedClients 当前被限速的客户端，未启用限速时返回空
+// 限速记录保存在本实例内存中，多实例部署时只反映当前实例
+func RateLimitedClients() []RateLimitedClient {
+	if globalLimiter == nil {
+		return nil
+	}
+	return globalLimiter.limited()
+}
+
+// ResetRateLimit 手动解除客户端 ip 的限速，返回是否存在限速记录
+func ResetRateLimit(ip string) bool {
+	if globalLimiter == nil {
+		return false
+	}
+	return globalLimiter.reset(ip)
+}
@@ -5,7 +5,9 @@ import (
 	"runtime/debug"
 
 	"gin-app-start/internal/code"
+	"gin-app-start/internal/common"
 	"gin-app-start/pkg/response"
+	"gin-app-start/pkg/trace"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -15,18 +17,36 @@ func Recovery(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
+				traceID := recoveryTraceID(c)
+
 				logger.Error("HTTP Panic",
 					zap.String("panic", fmt.Sprintf("%+v", err)),
 					zap.String("path", c.Request.URL.Path),
 					zap.String("method", c.Request.Method),
 					zap.String("ip", c.ClientIP()),
+					zap.String("trace_id", traceID),
 					zap.String("stack", string(debug.Stack())),
 				)
 
-				response.Error(c, code.ServerError, code.Text(code.ServerError))
+				// 返回 trace_id，便于用户反馈问题时定位日志
+				c.Header(trace.Header, traceID)
+				response.ErrorWithTrace(c, code.ServerError, code.Text(code.ServerError), traceID)
 				c.Abort() // 终止当前请求的后续处理，防止 panic 后的代码继续执行导致更多问题
 			}
 		}()
 		c.Next()
 	}
 }
+
+// recoveryTraceID 获取当前请求的 trace_id
+// 优先使用 Logger 中间件设置的 Trace，其次使用请求头中的 TRACE-ID，都不存在时生成新的 ID
+func recoveryTraceID(c *gin.Context) string {
+	context := common.NewContext(c)
+	defer common.ReleaseContext(context)
+
+	if t := context.Trace(); t != nil {
+		return t.ID()
+	}
+
+	return trace.New(c.GetHeader(trace.Header)).ID()
+}
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/dto"
+	"gin-app-start/pkg/trace"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultTapeBodySize 请求/响应体默认最多录制的字节数
+const defaultTapeBodySize = 64 << 10
+
+const redactedValue = "[REDACTED]"
+
+// tapeRedactHeaders 录制时隐藏的 Header，避免会话和凭证落盘
+var tapeRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// tapeRedactFields 录制时隐藏的请求体和响应体 JSON 字段，任意层级都隐藏(响应数据在 data 下)
+var tapeRedactFields = map[string]bool{
+	"password":      true,
+	"old_password":  true,
+	"new_password":  true,
+	"token":         true,
+	"access_token":  true, // 登录、刷新令牌的响应
+	"refresh_token": true,
+	"key":           true, // 创建 API Key 的响应，只在创建时返回明文
+}
+
+// Recorder 保存录制结果
+type Recorder interface {
+	Record(rec *dto.Recording)
+}
+
+// tapeWriter 在写出响应的同时保留一份响应体
+type tapeWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *tapeWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *tapeWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *tapeWriter) capture(b []byte) {
+	if room := w.limit - w.body.Len(); room < len(b) {
+		w.truncated = true
+		if room > 0 {
+			w.body.Write(b[:room])
+		}
+		return
+	}
+	w.body.Write(b)
+}
+
+// Tape 请求录制: 按 sample_rate 抽样，保存完整的请求和响应，便于按 trace_id 复现问题
+//
+// 必须注册在 Logger 之前: 响应体由 Logger 在请求结束时写出，trace_id 也由 Logger 写入响应头。
+func Tape(recorder Recorder, cfg config.TapeConfig) gin.HandlerFunc {
+	limit := cfg.MaxBodySize
+	if limit <= 0 {
+		limit = defaultTapeBodySize
+	}
+
+	return func(c *gin.Context) {
+		if cfg.SampleRate <= 0 || rand.Float64() >= cfg.SampleRate {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		writer := &tapeWriter{ResponseWriter: c.Writer, limit: limit}
+		c.Writer = writer
+
+		c.Next()
+
+		traceID := writer.Header().Get(trace.Header)
+		if traceID == "" {
+			return
+		}
+
+		reqBody, reqTruncated := truncateBody(redactBody(requestBody(c)), limit)
+		recorder.Record(&dto.Recording{
+			TraceID:        traceID,
+			Method:         c.Request.Method,
+			Route:          c.FullPath(),
+			URL:            c.Request.URL.RequestURI(),
+			ClientIP:       c.ClientIP(),
+			RequestHeader:  redactHeader(c.Request.Header),
+			RequestBody:    reqBody,
+			HttpCode:       writer.Status(),
+			ResponseHeader: redactHeader(writer.Header()),
+			ResponseBody:   string(redactBody(writer.body.Bytes())),
+			Truncated:      reqTruncated || writer.truncated,
+			CostSeconds:    time.Since(start).Seconds(),
+			RecordedAt:     start,
+		})
+	}
+}
+
+// requestBody Logger 中已读取的请求体
+func requestBody(c *gin.Context) []byte {
+	context := common.NewContext(c)
+	defer common.ReleaseContext(context)
+
+	return context.RawData()
+}
+
+func truncateBody(body []byte, limit int) (string, bool) {
+	if len(body) > limit {
+		return string(body[:limit]), true
+	}
+	return string(body), false
+}
+
+func redactHeader(header http.Header) http.Header {
+	clone := header.Clone()
+	for _, name := range tapeRedactHeaders {
+		if _, ok := clone[name]; ok {
+			clone[name] = []string{redactedValue}
+		}
+	}
+	return clone
+}
+
+// redactBody 隐藏 JSON 请求体、响应体中的密码、令牌等字段，不是 JSON 的(包括被截断的)原样返回
+func redactBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// 数字保持原样，避免大整数经 float64 丢失精度
+	decoder.UseNumber()
+	if decoder.Decode(&value) != nil || !redactValue(value) {
+		return body
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return b
+}
+
+// redactValue 递归隐藏 value 中需要隐藏的字段，返回是否有字段被隐藏
+func redactValue(value interface{}) bool {
+	redacted := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if tapeRedactFields[strings.ToLower(key)] {
+				v[key] = redactedValue
+				redacted = true
+				continue
+			}
+			if redactValue(field) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactValue(item) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
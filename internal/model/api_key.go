@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+// APIKey 用户为脚本和外部系统创建的 API Key，请求通过 X-API-Key 头认证，身份与创建者相同
+//
+// 只保存 Key 的 SHA-256 哈希，明文只在创建时返回一次；Prefix 为明文的前几位，便于用户区分自己的 Key。
+type APIKey struct {
+	ID         uint       `gorm:"primarykey" json:"id" example:"1"`
+	UserID     uint       `gorm:"index;not null" json:"-"`
+	Name       string     `gorm:"size:64;not null" json:"name" example:"nightly export"`
+	Prefix     string     `gorm:"size:16;not null" json:"prefix" example:"gak_3f9a1c"`
+	KeyHash    string     `gorm:"size:64;uniqueIndex:uk_api_keys_key_hash;not null" json:"-"`
+	CreatedAt  time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2023-01-02T00:00:00Z"` // 最近一次认证时间，按分钟更新
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at,omitempty" example:"2023-01-03T00:00:00Z"`
+}
+
+func (APIKey) TableName() string {
+	return "app_schema.api_keys"
+}
@@ -6,11 +6,15 @@ import (
 	"gorm.io/gorm"
 )
 
+// BaseModel 模型共用的主键、时间戳和软删除字段，嵌入到模型结构体中使用
+//
+// created_at、updated_at 由 GORM 在创建和更新时写入(autoCreateTime/autoUpdateTime)，
+// Save、Update、Updates(包括 map)都会刷新 updated_at，模型不需要再通过钩子设置；UpdateColumn(s) 不会刷新。
 type BaseModel struct {
-	ID        uint           `gorm:"primarykey" json:"id"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID        uint           `gorm:"primarykey" json:"id" example:"1"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at" example:"2023-01-01T00:00:00Z"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-" swaggerignore:"true"`
 }
 
 type PageRequest struct {
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 群发状态
+const (
+	BroadcastPending   = "pending"
+	BroadcastRunning   = "running"
+	BroadcastCompleted = "completed"
+	BroadcastCancelled = "cancelled"
+	BroadcastFailed    = "failed"
+)
+
+// 群发渠道
+const (
+	ChannelInApp = "in_app"
+	ChannelEmail = "email"
+)
+
+// Broadcast 定时群发，到达 ScheduledAt 后按标签圈选用户，通过站内信和邮件发送
+type Broadcast struct {
+	ID          uint      `gorm:"primarykey" json:"id" example:"1"`
+	Title       string    `gorm:"size:128;not null" json:"title" example:"Spring sale"`
+	Content     string    `gorm:"type:text;not null" json:"content" example:"20% off for all VIP members"`
+	Channels    string    `gorm:"size:32;not null" json:"channels" example:"in_app,email"` // 逗号分隔的渠道
+	Tags        string    `gorm:"size:512;not null" json:"tags" example:"vip,beta"`        // 逗号分隔的分群标签
+	Match       string    `gorm:"size:8;not null;default:any" json:"match" example:"any"`
+	ScheduledAt time.Time `gorm:"index;not null" json:"scheduled_at" example:"2023-01-01T00:00:00Z"`
+	Status      string    `gorm:"size:16;index;not null" json:"status" example:"pending"`
+
+	// 发送进度，每批结束后更新；LastUserID 用于任务中断后从断点继续
+	Total       int64 `gorm:"not null;default:0" json:"total" example:"1000"`
+	Processed   int64 `gorm:"not null;default:0" json:"processed" example:"200"`
+	EmailSent   int64 `gorm:"not null;default:0" json:"email_sent" example:"180"`
+	EmailFailed int64 `gorm:"not null;default:0" json:"email_failed" example:"2"`
+	LastUserID  uint  `gorm:"not null;default:0" json:"-"`
+
+	Error      string     `gorm:"size:512" json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at" example:"2023-01-01T00:00:00Z"` // 执行中的群发每批刷新，作为心跳
+}
+
+func (Broadcast) TableName() string {
+	return "app_schema.broadcasts"
+}
+
+func (b *Broadcast) BeforeCreate(tx *gorm.DB) error {
+	if b.Status == "" {
+		b.Status = BroadcastPending
+	}
+	return nil
+}
+
+// Notification 站内信
+type Notification struct {
+	ID          uint       `gorm:"primarykey" json:"id" example:"1"`
+	UserID      uint       `gorm:"uniqueIndex:uk_notifications_user_broadcast,priority:1;not null" json:"user_id" example:"1"`
+	BroadcastID uint       `gorm:"uniqueIndex:uk_notifications_user_broadcast,priority:2;not null" json:"broadcast_id" example:"1"`
+	Title       string     `gorm:"size:128;not null" json:"title" example:"Spring sale"`
+	Content     string     `gorm:"type:text;not null" json:"content" example:"20% off for all VIP members"`
+	ReadAt      *time.Time `json:"read_at,omitempty" example:"2023-01-01T00:00:00Z"`
+	CreatedAt   time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+func (Notification) TableName() string {
+	return "app_schema.notifications"
+}
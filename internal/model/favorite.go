@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// Favorite 用户收藏的商品，收藏操作先写入 Redis 集合，由后台任务定期落库
+type Favorite struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false;index:idx_favorites_user_created,priority:1" json:"user_id" example:"1"`
+	ProductID string    `gorm:"primaryKey;size:64" json:"product_id" example:"SKU-10001"`
+	CreatedAt time.Time `gorm:"index:idx_favorites_user_created,priority:2" json:"created_at" example:"2023-01-01T00:00:00Z"` // 落库时间，收藏列表按该时间倒序
+}
+
+func (Favorite) TableName() string {
+	return "app_schema.favorites"
+}
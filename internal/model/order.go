@@ -1,23 +1,23 @@
 package model
 
-import (
-	"time"
+import "gorm.io/gorm"
 
-	"gorm.io/gorm"
-)
+// OrderNumberMaxLen 订单号列的最大长度，订单号格式配置校验时以此为上限
+const OrderNumberMaxLen = 32
 
 // Order represents an order in the system
 type Order struct {
-	ID          uint           `gorm:"primarykey" json:"id" example:"1"`
-	OrderNumber string         `gorm:"unique;not null" json:"order_number" example:"EC20231215123456"`
-	CreatedAt   time.Time      `json:"created_at" example:"2023-01-01T00:00:00Z"`
-	UpdateAt    time.Time      `json:"update_at" example:"2023-01-01T00:00:00Z"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-" swaggerignore:"true"`
-	UserID      uint           `gorm:"index;not null" json:"user_id" example:"1"`
-	Username    string         `gorm:"size:64;;not null" json:"username" binding:"required" example:"john_doe"`
-	TotalPrice  float64        `gorm:"type:decimal(10,2);not null" json:"total_price" example:"100.00"`
-	Description string         `gorm:"size:256" json:"description" example:"Order for product A"`
-	Status      int8           `gorm:"default:1;not null" json:"status" example:"1"`
+	BaseModel
+	OrderNumber    string      `gorm:"size:32;unique;not null" json:"order_number" example:"EC20231215123456"`
+	UserID         uint        `gorm:"index;not null" json:"user_id" example:"1"`
+	Username       string      `gorm:"size:64;;not null" json:"username" binding:"required" example:"john_doe"`
+	TotalPrice     float64     `gorm:"type:decimal(10,2);not null" json:"total_price" example:"100.00"`
+	Description    string      `gorm:"size:256" json:"description" example:"Order for product A"`
+	Status         OrderStatus `gorm:"default:1;not null" json:"status" swaggertype:"string" enums:"pending,paid,shipped,completed,cancelled" example:"pending"`
+	OrganizationID *uint       `gorm:"index" json:"organization_id,omitempty" example:"1"` // 组织订单，组织成员均可查看；个人订单为空
+	Latitude       *float64    `json:"latitude,omitempty" example:"31.2304"`               // 收货地坐标，未提供时为空
+	Longitude      *float64    `json:"longitude,omitempty" example:"121.4737"`             // 收货地坐标，未提供时为空
+	Notes          []OrderNote `gorm:"foreignKey:OrderID" json:"notes,omitempty"`          // 订单详情中预加载，按创建顺序排列
 }
 
 func (Order) TableName() string {
@@ -25,15 +25,8 @@ func (Order) TableName() string {
 }
 
 func (o *Order) BeforeCreate(tx *gorm.DB) error {
-	o.CreatedAt = time.Now()
-	o.UpdateAt = time.Now()
 	if o.Status == 0 {
-		o.Status = 1
+		o.Status = OrderStatusPending
 	}
 	return nil
 }
-
-func (o *Order) BeforeUpdate(tx *gorm.DB) error {
-	o.UpdateAt = time.Now()
-	return nil
-}
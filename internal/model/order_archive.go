@@ -0,0 +1,108 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ArchivedOrder 归档的订单，列与 Order 相同，由 service.OrderArchiveService 从订单表移入
+//
+// 单独定义结构体而不是复用 Order：索引名按表名生成，避免与订单表冲突；也不会给 order_notes 建立指向归档表的外键。
+// 订单表增加列时这里需要同步增加。
+type ArchivedOrder struct {
+	ID             uint           `gorm:"primarykey;autoIncrement:false"`
+	OrderNumber    string         `gorm:"size:32;unique;not null"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime:false;index"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime:false"`
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+	UserID         uint           `gorm:"index;not null"`
+	Username       string         `gorm:"size:64;not null"`
+	TotalPrice     float64        `gorm:"type:decimal(10,2);not null"`
+	Description    string         `gorm:"size:256"`
+	Status         OrderStatus    `gorm:"default:1;not null"`
+	OrganizationID *uint          `gorm:"index"`
+	Latitude       *float64
+	Longitude      *float64
+}
+
+func (ArchivedOrder) TableName() string {
+	return "app_schema.orders_archive"
+}
+
+// NewArchivedOrder 从订单生成归档记录，保留原订单ID和时间
+func NewArchivedOrder(order *Order) *ArchivedOrder {
+	return &ArchivedOrder{
+		ID:             order.ID,
+		OrderNumber:    order.OrderNumber,
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+		DeletedAt:      order.DeletedAt,
+		UserID:         order.UserID,
+		Username:       order.Username,
+		TotalPrice:     order.TotalPrice,
+		Description:    order.Description,
+		Status:         order.Status,
+		OrganizationID: order.OrganizationID,
+		Latitude:       order.Latitude,
+		Longitude:      order.Longitude,
+	}
+}
+
+// Order 转换为订单(不含备注)，供查询接口复用订单的响应结构
+func (a *ArchivedOrder) Order() *Order {
+	return &Order{
+		BaseModel:      BaseModel{ID: a.ID, CreatedAt: a.CreatedAt, UpdatedAt: a.UpdatedAt, DeletedAt: a.DeletedAt},
+		OrderNumber:    a.OrderNumber,
+		UserID:         a.UserID,
+		Username:       a.Username,
+		TotalPrice:     a.TotalPrice,
+		Description:    a.Description,
+		Status:         a.Status,
+		OrganizationID: a.OrganizationID,
+		Latitude:       a.Latitude,
+		Longitude:      a.Longitude,
+	}
+}
+
+// ArchivedOrderNote 归档订单的备注，随订单一起从 order_notes 移入
+type ArchivedOrderNote struct {
+	ID         uint      `gorm:"primarykey;autoIncrement:false"`
+	OrderID    uint      `gorm:"index;not null"`
+	AuthorID   uint      `gorm:"not null"`
+	AuthorName string    `gorm:"size:64;not null"`
+	Content    string    `gorm:"size:2000;not null"`
+	Internal   bool      `gorm:"not null;default:false"`
+	CreatedAt  time.Time `gorm:"autoCreateTime:false"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime:false"`
+}
+
+func (ArchivedOrderNote) TableName() string {
+	return "app_schema.order_notes_archive"
+}
+
+func NewArchivedOrderNote(note *OrderNote) *ArchivedOrderNote {
+	return &ArchivedOrderNote{
+		ID:         note.ID,
+		OrderID:    note.OrderID,
+		AuthorID:   note.AuthorID,
+		AuthorName: note.AuthorName,
+		Content:    note.Content,
+		Internal:   note.Internal,
+		CreatedAt:  note.CreatedAt,
+		UpdatedAt:  note.UpdatedAt,
+	}
+}
+
+func (n *ArchivedOrderNote) Note() OrderNote {
+	return OrderNote{
+		ID:         n.ID,
+		OrderID:    n.OrderID,
+		AuthorID:   n.AuthorID,
+		AuthorName: n.AuthorName,
+		Content:    n.Content,
+		Internal:   n.Internal,
+		CreatedAt:  n.CreatedAt,
+		UpdatedAt:  n.UpdatedAt,
+	}
+}
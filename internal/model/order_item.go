@@ -0,0 +1,23 @@
+package model
+
+// OrderItem 订单明细，订单总价为全部明细的小计之和
+//
+// 加入订单时记录商品名称和单价，之后修改商品不影响已有的明细；
+// 不声明与 Order 的关联，不建立外键，订单归档后明细仍按原订单ID保留。
+type OrderItem struct {
+	BaseModel
+	OrderID   uint    `gorm:"uniqueIndex:uk_order_items_product,priority:1,where:deleted_at IS NULL;not null" json:"-"`
+	ProductID string  `gorm:"size:64;uniqueIndex:uk_order_items_product,priority:2,where:deleted_at IS NULL;not null" json:"product_id" example:"SKU-10001"` // 商品 SKU，同一订单中每个商品只有一条明细
+	Name      string  `gorm:"size:128;not null" json:"name" example:"Coffee mug"`
+	UnitPrice float64 `gorm:"type:decimal(10,2);not null" json:"unit_price" example:"25.00"`
+	Quantity  int     `gorm:"not null" json:"quantity" example:"2"`
+}
+
+func (OrderItem) TableName() string {
+	return "app_schema.order_items"
+}
+
+// Subtotal 明细小计
+func (i *OrderItem) Subtotal() float64 {
+	return i.UnitPrice * float64(i.Quantity)
+}
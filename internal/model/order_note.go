@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// OrderNote 订单备注，用户和管理员都可以追加；Internal 为 true 的备注只有管理员可见
+type OrderNote struct {
+	ID         uint      `gorm:"primarykey" json:"id" example:"1"`
+	OrderID    uint      `gorm:"index;not null" json:"order_id" example:"1"`
+	AuthorID   uint      `gorm:"not null" json:"author_id" example:"1"`
+	AuthorName string    `gorm:"size:64;not null" json:"author_name" example:"john_doe"`
+	Content    string    `gorm:"size:2000;not null" json:"content" example:"Please deliver after 6pm"`
+	Internal   bool      `gorm:"not null;default:false" json:"internal" example:"false"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+func (OrderNote) TableName() string {
+	return "app_schema.order_notes"
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// OrderStatus 订单状态，JSON 和查询参数中使用名称(如 "paid")；
+// 数据库中按底层的 int8 保存为数字，列类型与改为枚举前相同，不需要迁移
+type OrderStatus int8
+
+const (
+	OrderStatusPending   OrderStatus = 1 // 待支付，新建订单的状态
+	OrderStatusPaid      OrderStatus = 2 // 已支付，订单状态更新为该值时触发 OnOrderPaid 钩子
+	OrderStatusShipped   OrderStatus = 3 // 已发货
+	OrderStatusCompleted OrderStatus = 4 // 已完成
+	OrderStatusCancelled OrderStatus = 5 // 已取消
+)
+
+var orderStatusNames = map[OrderStatus]string{
+	OrderStatusPending:   "pending",
+	OrderStatusPaid:      "paid",
+	OrderStatusShipped:   "shipped",
+	OrderStatusCompleted: "completed",
+	OrderStatusCancelled: "cancelled",
+}
+
+// OrderStatusNames 全部订单状态的名称，按数值排列，用于文档和错误信息
+func OrderStatusNames() []string {
+	names := make([]string, 0, len(orderStatusNames))
+	for s := OrderStatusPending; s <= OrderStatusCancelled; s++ {
+		names = append(names, orderStatusNames[s])
+	}
+	return names
+}
+
+// ParseOrderStatus 按名称解析订单状态；为兼容旧客户端和旧缓存，也接受数字
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	for status, name := range orderStatusNames {
+		if name == s {
+			return status, nil
+		}
+	}
+	if n, err := strconv.ParseInt(s, 10, 8); err == nil {
+		return OrderStatus(n), nil
+	}
+	return 0, fmt.Errorf("invalid order status %q", s)
+}
+
+func (s OrderStatus) String() string {
+	if name, ok := orderStatusNames[s]; ok {
+		return name
+	}
+	return strconv.Itoa(int(s))
+}
+
+// IsValid 是否为已定义的订单状态，参数校验规则 enum 通过它检查
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderStatusNames[s]
+	return ok
+}
+
+func (s OrderStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		// 旧版本接口和缓存中的状态为数字
+		var n int8
+		if json.Unmarshal(data, &n) != nil {
+			return fmt.Errorf("invalid order status %s", data)
+		}
+		*s = OrderStatus(n)
+		return nil
+	}
+
+	status, err := ParseOrderStatus(name)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
+// UnmarshalParam 绑定查询参数和表单参数
+func (s *OrderStatus) UnmarshalParam(param string) error {
+	status, err := ParseOrderStatus(param)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// OrderSummary 订单列表的读模型，由订单事件异步维护，见 service.OrderProjectionService
+//
+// 只保存列表展示需要的列，不含备注；索引按列表的排序方式建立，
+// 管理端分页查询不再扫描订单表，也不与订单表的写入争用。
+type OrderSummary struct {
+	OrderID        uint        `gorm:"primarykey;autoIncrement:false"`
+	OrderNumber    string      `gorm:"size:32;not null"`
+	UserID         uint        `gorm:"not null"`
+	Username       string      `gorm:"size:64;not null;index:idx_order_summaries_username_created,priority:1"`
+	TotalPrice     float64     `gorm:"type:decimal(10,2);not null"`
+	Description    string      `gorm:"size:256"`
+	Status         OrderStatus `gorm:"not null;index:idx_order_summaries_status_created,priority:1"`
+	OrganizationID *uint       `gorm:"index"`
+	Latitude       *float64
+	Longitude      *float64
+	CreatedAt      time.Time `gorm:"autoCreateTime:false;not null;index:idx_order_summaries_created;index:idx_order_summaries_username_created,priority:2;index:idx_order_summaries_status_created,priority:2"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime:false;not null"`
+	ProjectedAt    time.Time `gorm:"autoCreateTime:false;not null"` // 最近一次从订单表投影的时间
+}
+
+func (OrderSummary) TableName() string {
+	return "app_schema.order_summaries"
+}
+
+// NewOrderSummary 从订单生成读模型记录
+func NewOrderSummary(order *Order, projectedAt time.Time) *OrderSummary {
+	return &OrderSummary{
+		OrderID:        order.ID,
+		OrderNumber:    order.OrderNumber,
+		UserID:         order.UserID,
+		Username:       order.Username,
+		TotalPrice:     order.TotalPrice,
+		Description:    order.Description,
+		Status:         order.Status,
+		OrganizationID: order.OrganizationID,
+		Latitude:       order.Latitude,
+		Longitude:      order.Longitude,
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+		ProjectedAt:    projectedAt,
+	}
+}
+
+// Order 转换为订单(不含备注)，供列表接口复用订单的响应结构
+func (s *OrderSummary) Order() *Order {
+	return &Order{
+		BaseModel:      BaseModel{ID: s.OrderID, CreatedAt: s.CreatedAt, UpdatedAt: s.UpdatedAt},
+		OrderNumber:    s.OrderNumber,
+		UserID:         s.UserID,
+		Username:       s.Username,
+		TotalPrice:     s.TotalPrice,
+		Description:    s.Description,
+		Status:         s.Status,
+		OrganizationID: s.OrganizationID,
+		Latitude:       s.Latitude,
+		Longitude:      s.Longitude,
+	}
+}
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// 组织成员角色
+const (
+	OrgRoleOwner  = "owner"  // 可以邀请和移除成员，修改、删除组织内的任意订单
+	OrgRoleMember = "member" // 可以查看组织订单，以组织名义下单
+)
+
+// Organization 组织(团队)账号，成员共享组织下的订单
+type Organization struct {
+	ID        uint      `gorm:"primarykey" json:"id" example:"1"`
+	Name      string    `gorm:"size:128;not null" json:"name" example:"Acme Inc."`
+	OwnerID   uint      `gorm:"index;not null" json:"owner_id" example:"1"` // 创建者
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+func (Organization) TableName() string {
+	return "app_schema.organizations"
+}
+
+// OrganizationMember 组织成员
+type OrganizationMember struct {
+	OrganizationID uint      `gorm:"primaryKey;autoIncrement:false" json:"organization_id" example:"1"`
+	UserID         uint      `gorm:"primaryKey;autoIncrement:false;index" json:"user_id" example:"2"`
+	Username       string    `gorm:"size:64;not null" json:"username" example:"john_doe"`
+	Role           string    `gorm:"size:16;not null" json:"role" example:"member"`
+	CreatedAt      time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+func (OrganizationMember) TableName() string {
+	return "app_schema.organization_members"
+}
+
+// IsOwner 是否为组织所有者
+func (m *OrganizationMember) IsOwner() bool {
+	return m.Role == OrgRoleOwner
+}
+
+// OrganizationInvitation 组织邀请，被邀请人登录后凭令牌加入组织
+type OrganizationInvitation struct {
+	ID             uint       `gorm:"primarykey" json:"id" example:"1"`
+	OrganizationID uint       `gorm:"index;not null" json:"organization_id" example:"1"`
+	Email          string     `gorm:"size:128;not null" json:"email" example:"john@example.com"` // 只有该邮箱的用户可以接受邀请
+	Role           string     `gorm:"size:16;not null" json:"role" example:"member"`
+	Token          string     `gorm:"size:64;uniqueIndex:uk_organization_invitations_token;not null" json:"-"`
+	InvitedBy      uint       `gorm:"not null" json:"invited_by" example:"1"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at" example:"2023-01-08T00:00:00Z"`
+	AcceptedAt     *time.Time `json:"accepted_at" example:"2023-01-02T00:00:00Z"` // 未接受时为空
+	CreatedAt      time.Time  `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+func (OrganizationInvitation) TableName() string {
+	return "app_schema.organization_invitations"
+}
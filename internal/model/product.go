@@ -0,0 +1,14 @@
+package model
+
+// Product 商品，SKU 即收藏、浏览计数等接口中的商品ID(product_id)
+type Product struct {
+	BaseModel
+	SKU   string  `gorm:"column:sku;size:64;uniqueIndex:uk_products_sku,where:deleted_at IS NULL;not null" json:"product_id" example:"SKU-10001"`
+	Name  string  `gorm:"size:128;not null" json:"name" example:"Coffee mug"`
+	Price float64 `gorm:"type:decimal(10,2);not null" json:"price" example:"25.00"` // 单价，已下单的订单明细不受修改影响
+	Stock *int    `json:"stock" example:"100"`                                      // 库存，加入订单时扣减；为空表示不限库存
+}
+
+func (Product) TableName() string {
+	return "app_schema.products"
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 物流状态，ShipmentDelivered 和 ShipmentReturned 为终态，不再轮询承运商
+const (
+	ShipmentPending        = "pending"
+	ShipmentInTransit      = "in_transit"
+	ShipmentOutForDelivery = "out_for_delivery"
+	ShipmentDelivered      = "delivered"
+	ShipmentException      = "exception"
+	ShipmentReturned       = "returned"
+)
+
+// ShipmentFinalStatuses 物流终态
+var ShipmentFinalStatuses = []string{ShipmentDelivered, ShipmentReturned}
+
+// Shipment 订单的一个包裹，同一承运商下运单号唯一
+type Shipment struct {
+	ID             uint       `gorm:"primarykey" json:"id" example:"1"`
+	OrderID        uint       `gorm:"index;not null" json:"order_id" example:"1"`
+	Carrier        string     `gorm:"size:32;not null;uniqueIndex:uk_shipments_carrier_tracking" json:"carrier" example:"sf"`
+	TrackingNumber string     `gorm:"size:64;not null;uniqueIndex:uk_shipments_carrier_tracking" json:"tracking_number" example:"SF1234567890"`
+	Status         string     `gorm:"size:32;index;not null" json:"status" example:"in_transit"`
+	LastEventAt    *time.Time `json:"last_event_at,omitempty" example:"2023-01-01T00:00:00Z"` // 最新物流事件的发生时间
+	PolledAt       *time.Time `json:"-"`                                                      // 轮询任务最近一次查询承运商的时间
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at" example:"2023-01-01T00:00:00Z"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at" example:"2023-01-01T00:00:00Z"`
+
+	Events []ShipmentEvent `gorm:"foreignKey:ShipmentID" json:"events,omitempty"` // 按发生时间排列
+}
+
+func (Shipment) TableName() string {
+	return "app_schema.shipments"
+}
+
+func (s *Shipment) BeforeCreate(tx *gorm.DB) error {
+	if s.Status == "" {
+		s.Status = ShipmentPending
+	}
+	return nil
+}
+
+// IsFinal 包裹是否已到达终态
+func (s *Shipment) IsFinal() bool {
+	for _, status := range ShipmentFinalStatuses {
+		if s.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ShipmentEvent 物流事件，来自承运商回调、轮询或管理端录入
+// 同一包裹同一时间的同一状态只记录一次，回调重试和轮询重复拉取时跳过
+type ShipmentEvent struct {
+	ID          uint      `gorm:"primarykey" json:"id" example:"1"`
+	ShipmentID  uint      `gorm:"not null;uniqueIndex:uk_shipment_events_dedup" json:"shipment_id" example:"1"`
+	Status      string    `gorm:"size:32;not null;uniqueIndex:uk_shipment_events_dedup" json:"status" example:"in_transit"`
+	Location    string    `gorm:"size:128" json:"location" example:"Shenzhen"`
+	Description string    `gorm:"size:512" json:"description" example:"Departed from sorting center"`
+	OccurredAt  time.Time `gorm:"not null;uniqueIndex:uk_shipment_events_dedup" json:"occurred_at" example:"2023-01-01T00:00:00Z"`
+	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+func (ShipmentEvent) TableName() string {
+	return "app_schema.shipment_events"
+}
+
+func (e *ShipmentEvent) BeforeCreate(tx *gorm.DB) error {
+	e.CreatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,14 @@
+package model
+
+// Store 门店，坐标同时写入 Redis GEO 索引用于附近门店查询
+type Store struct {
+	BaseModel
+	Name      string  `gorm:"size:128;not null" json:"name" example:"People's Square Store"`
+	Address   string  `gorm:"size:256" json:"address" example:"120 Nanjing Road, Shanghai"`
+	Latitude  float64 `gorm:"not null;index:idx_stores_location,priority:1" json:"latitude" example:"31.2304"`
+	Longitude float64 `gorm:"not null;index:idx_stores_location,priority:2" json:"longitude" example:"121.4737"`
+}
+
+func (Store) TableName() string {
+	return "app_schema.stores"
+}
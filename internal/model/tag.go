@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+// Tag 用户标签，用于按标签圈选用户(分群)
+type Tag struct {
+	ID        uint      `gorm:"primarykey" json:"id" example:"1"`
+	Name      string    `gorm:"size:64;uniqueIndex:uk_tags_name;not null" json:"name" example:"vip"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T00:00:00Z"`
+}
+
+func (Tag) TableName() string {
+	return "app_schema.tags"
+}
+
+// UserTag 用户与标签的多对多关联
+type UserTag struct {
+	UserID    uint `gorm:"primaryKey;autoIncrement:false"`
+	TagID     uint `gorm:"primaryKey;autoIncrement:false;index"`
+	CreatedAt time.Time
+}
+
+func (UserTag) TableName() string {
+	return "app_schema.user_tags"
+}
@@ -1,24 +1,24 @@
 package model
 
-import (
-	"time"
-
-	"gorm.io/gorm"
-)
+import "gorm.io/gorm"
 
 // User represents a user in the system
+//
+// Email、Phone 通过 fieldcrypt 序列化器加密存储(未启用加密时为明文)，按值查询时使用 fieldcrypt.Lookup
 type User struct {
-	ID        uint           `gorm:"primarykey" json:"id" example:"1"`
-	CreatedAt time.Time      `json:"created_at" example:"2023-01-01T00:00:00Z"`
-	UpdateAt  time.Time      `json:"update_at" example:"2023-01-01T00:00:00Z"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-" swaggerignore:"true"`
-	Username  string         `gorm:"size:64;uniqueIndex;not null" json:"username" binding:"required" example:"john_doe"`
-	Email     string         `gorm:"size:128;uniqueIndex" json:"email" example:"john@example.com"`
-	Phone     string         `gorm:"size:32;uniqueIndex" json:"phone" example:"13800138000"`
-	Password  string         `gorm:"size:128;not null" json:"-" swaggerignore:"true"`
-	Salt      string         `gorm:"size:32;not null" json:"-" swaggerignore:"true"`
-	Avatar    string         `gorm:"size:256" json:"avatar" example:"https://example.com/avatar.jpg"`
-	Status    int8           `gorm:"default:1;not null" json:"status" example:"1"`
+	BaseModel
+	Username string `gorm:"size:64;uniqueIndex:uk_users_username,where:deleted_at IS NULL;not null" json:"username" binding:"required" example:"john_doe"`
+	Email    string `gorm:"size:256;serializer:encrypt;uniqueIndex:uk_users_email,where:email <> '' AND deleted_at IS NULL" json:"email" example:"john@example.com"`
+	Phone    string `gorm:"size:128;serializer:encrypt;uniqueIndex:uk_users_phone,where:phone <> '' AND deleted_at IS NULL" json:"phone" example:"13800138000"`
+	Password string `gorm:"size:128;not null" json:"-" swaggerignore:"true"`
+	Salt     string `gorm:"size:32;not null" json:"-" swaggerignore:"true"`
+	Avatar   string `gorm:"size:256" json:"avatar" example:"https://example.com/avatar.jpg"`
+	Status   int8   `gorm:"default:1;not null" json:"status" example:"1"`
+
+	// InviteCode 邀请码，首次查询邀请信息时生成
+	InviteCode string `gorm:"size:16;uniqueIndex:uk_users_invite_code,where:invite_code <> '' AND deleted_at IS NULL" json:"invite_code" example:"K7QX2M9P"`
+	// ReferrerID 邀请人ID，未通过邀请码注册时为 0
+	ReferrerID uint `gorm:"index;default:0;not null" json:"referrer_id" example:"0"`
 }
 
 func (User) TableName() string {
@@ -26,16 +26,16 @@ func (User) TableName() string {
 	return "app_schema.users" // 指定schema为app_schema；PostgreSQL格式: schema.table_name
 }
 
+// CacheProjection 用户缓存不保存密码和盐值，见 repository.CachedRepository
+func (u *User) CacheProjection() *User {
+	projection := *u
+	projection.Password, projection.Salt = "", ""
+	return &projection
+}
+
 func (u *User) BeforeCreate(tx *gorm.DB) error {
-	u.CreatedAt = time.Now()
-	u.UpdateAt = time.Now()
 	if u.Status == 0 {
 		u.Status = 1
 	}
 	return nil
 }
-
-func (u *User) BeforeUpdate(tx *gorm.DB) error {
-	u.UpdateAt = time.Now()
-	return nil
-}
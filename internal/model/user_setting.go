@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// UserSetting 用户的偏好设置，每个用户一行，设置项以键值对保存在 JSONB 中，新增设置项不需要改表
+type UserSetting struct {
+	UserID    uint           `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Settings  map[string]any `gorm:"type:jsonb;serializer:json;not null;default:'{}'" json:"settings"` // 只保存用户改过的项，未设置的项使用默认值
+	UpdatedAt time.Time      `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+func (UserSetting) TableName() string {
+	return "app_schema.user_settings"
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+// 浏览计数的对象类型
+const (
+	ViewSubjectOrder   = "order"
+	ViewSubjectProduct = "product"
+)
+
+// ViewCount 对象的累计浏览数，浏览先在 Redis 中累加，由后台任务批量落库
+type ViewCount struct {
+	Subject   string    `gorm:"primaryKey;size:32" json:"subject" example:"product"`
+	SubjectID string    `gorm:"primaryKey;size:64" json:"subject_id" example:"SKU-10001"`
+	Count     int64     `gorm:"not null;default:0" json:"count" example:"42"`
+	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"` // 最近一次落库时间
+}
+
+func (ViewCount) TableName() string {
+	return "app_schema.view_counts"
+}
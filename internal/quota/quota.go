@@ -0,0 +1,204 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	periodDaily   = "daily"
+	periodMonthly = "monthly"
+
+	// resetScanCount 重置时每次 SCAN 返回的键数量
+	resetScanCount = 500
+	// resetTimeout 单次重置的超时时间
+	resetTimeout = time.Minute
+)
+
+// Usage 本次请求计数后的配额使用情况
+type Usage struct {
+	Tier         string
+	Limit        config.QuotaLimit
+	Daily        int64     // 今日已用次数(包含本次)
+	Monthly      int64     // 本月已用次数(包含本次)
+	DailyReset   time.Time // 日配额重置时间
+	MonthlyReset time.Time // 月配额重置时间
+}
+
+// DailyExceeded 是否超出日配额
+func (u *Usage) DailyExceeded() bool {
+	return u.Limit.Daily > 0 && u.Daily > u.Limit.Daily
+}
+
+// MonthlyExceeded 是否超出月配额
+func (u *Usage) MonthlyExceeded() bool {
+	return u.Limit.Monthly > 0 && u.Monthly > u.Limit.Monthly
+}
+
+// Exceeded 是否超出配额
+func (u *Usage) Exceeded() bool {
+	return u.DailyExceeded() || u.MonthlyExceeded()
+}
+
+// Limiter 基于 Redis 计数的请求配额
+//
+// 计数键不带日期，首次计数时设置到下一周期开始的过期时间；
+// 另有每日零点执行的重置任务兜底，保证 EXPIREAT 没有生效时配额也能按时重置。
+type Limiter struct {
+	repo   redis.RedisRepository
+	cfg    config.QuotaConfig
+	logger *zap.Logger
+	now    func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewLimiter 创建配额限制器
+func NewLimiter(repo redis.RedisRepository, cfg config.QuotaConfig, logger *zap.Logger) *Limiter {
+	return &Limiter{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+		now:    time.Now,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Tier 用户所属套餐
+func (l *Limiter) Tier(username string) string {
+	return l.cfg.Tier(username)
+}
+
+func quotaKey(group, identity, period string) string {
+	return fmt.Sprintf("quota:%s:%s:%s", group, identity, period)
+}
+
+// nextDay 下一个自然日零点
+func nextDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
+
+// nextMonth 下个月 1 日零点
+func nextMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m+1, 1, 0, 0, 0, 0, t.Location())
+}
+
+// Consume 为 identity 在路由组 group 下计数一次，并返回计数后的使用情况
+// 套餐不限制配额时不计数；是否拒绝请求由调用方根据 Usage.Exceeded 决定
+func (l *Limiter) Consume(ctx context.Context, group, identity, tier string) (*Usage, error) {
+	now := l.now()
+	usage := &Usage{
+		Tier:         tier,
+		Limit:        l.cfg.Limit(group, tier),
+		DailyReset:   nextDay(now),
+		MonthlyReset: nextMonth(now),
+	}
+	if usage.Limit.Daily <= 0 && usage.Limit.Monthly <= 0 {
+		return usage, nil
+	}
+
+	client := l.repo.GetRedisClient()
+	if client == nil {
+		return usage, redis.ErrUnavailable
+	}
+
+	dailyKey := l.repo.Key(quotaKey(group, identity, periodDaily))
+	monthlyKey := l.repo.Key(quotaKey(group, identity, periodMonthly))
+
+	var daily, monthly *goredis.IntCmd
+	_, err := client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		daily = pipe.Incr(ctx, dailyKey)
+		pipe.ExpireAt(ctx, dailyKey, usage.DailyReset)
+		monthly = pipe.Incr(ctx, monthlyKey)
+		pipe.ExpireAt(ctx, monthlyKey, usage.MonthlyReset)
+		return nil
+	})
+	if err != nil {
+		return usage, err
+	}
+
+	usage.Daily = daily.Val()
+	usage.Monthly = monthly.Val()
+	return usage, nil
+}
+
+// Start 启动每日零点的配额重置任务，每月 1 日同时重置月配额
+func (l *Limiter) Start() {
+	go func() {
+		defer close(l.done)
+
+		for {
+			next := nextDay(l.now())
+			timer := time.NewTimer(next.Sub(l.now()))
+
+			select {
+			case <-l.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			l.reset(periodDaily)
+			if next.Day() == 1 {
+				l.reset(periodMonthly)
+			}
+		}
+	}()
+}
+
+// Stop 停止重置任务
+func (l *Limiter) Stop() {
+	close(l.stop)
+	<-l.done
+}
+
+// reset 删除所有路由组、所有用户在 period 周期内的计数
+func (l *Limiter) reset(period string) {
+	client := l.repo.GetRedisClient()
+	if client == nil {
+		l.logger.Warn("quota reset skipped, redis unavailable", zap.String("period", period))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resetTimeout)
+	defer cancel()
+
+	var (
+		cursor  uint64
+		deleted int64
+	)
+	for {
+		keys, next, err := client.Scan(ctx, cursor, l.repo.Key(quotaKey("*", "*", period)), resetScanCount).Result()
+		if err != nil {
+			l.logger.Error("quota reset failed", zap.String("period", period), zap.Error(err))
+			return
+		}
+
+		if len(keys) > 0 {
+			n, err := client.Del(ctx, keys...).Result()
+			if err != nil {
+				l.logger.Error("quota reset failed", zap.String("period", period), zap.Error(err))
+				return
+			}
+			deleted += n
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	l.logger.Info("quota reset", zap.String("period", period), zap.Int64("deleted", deleted))
+}
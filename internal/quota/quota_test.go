@@ -0,0 +1,59 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"gin-app-start/internal/config"
+)
+
+func TestResetTime(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	now := time.Date(2024, 12, 31, 23, 59, 59, 0, loc)
+
+	if got, want := nextDay(now), time.Date(2025, 1, 1, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("nextDay = %v, want %v", got, want)
+	}
+	if got, want := nextMonth(now), time.Date(2025, 1, 1, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Fatalf("nextMonth = %v, want %v", got, want)
+	}
+}
+
+func TestUsageExceeded(t *testing.T) {
+	usage := &Usage{Limit: config.QuotaLimit{Daily: 2, Monthly: 0}, Daily: 2, Monthly: 100}
+	if usage.Exceeded() {
+		t.Fatal("usage at daily limit should not be exceeded, monthly 0 means unlimited")
+	}
+
+	usage.Daily = 3
+	if !usage.DailyExceeded() || usage.MonthlyExceeded() {
+		t.Fatal("expected only daily quota exceeded")
+	}
+}
+
+func TestConfigLimit(t *testing.T) {
+	cfg := config.QuotaConfig{
+		DefaultTier: "free",
+		Users:       map[string]string{"alice": "pro"},
+		Tiers: map[string]config.QuotaLimit{
+			"free": {Daily: 100, Monthly: 1000},
+			"pro":  {Daily: 1000},
+		},
+		Groups: map[string]map[string]config.QuotaLimit{
+			"orders": {"free": {Daily: 10}},
+		},
+	}
+
+	if tier := cfg.Tier("Alice"); tier != "pro" {
+		t.Fatalf("Tier(Alice) = %q, want pro", tier)
+	}
+	if tier := cfg.Tier("bob"); tier != "free" {
+		t.Fatalf("Tier(bob) = %q, want free", tier)
+	}
+	if limit := cfg.Limit("orders", "free"); limit.Daily != 10 || limit.Monthly != 0 {
+		t.Fatalf("Limit(orders, free) = %+v", limit)
+	}
+	if limit := cfg.Limit("orders", "pro"); limit.Daily != 1000 {
+		t.Fatalf("Limit(orders, pro) = %+v", limit)
+	}
+}
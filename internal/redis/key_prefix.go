@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyMigration 键前缀迁移结果
+type KeyMigration struct {
+	Matched  int      // 需要迁移的键数
+	Renamed  int      // 已迁移的键数，dry run 时为 0
+	Conflict []string // 目标键已存在而跳过的键
+}
+
+// MigrateKeyPrefix 把前缀为 from 的键改为前缀 to，用于开启或修改 redis.key_prefix 后迁移已有的键
+//
+// 用 SCAN 遍历匹配 from+match 的键，逐个 RENAMENX，过期时间保持不变；已带有前缀 to 或 skip 中任一前缀的键不处理
+// (如其他环境的命名空间)，目标键已存在时跳过并记录在 Conflict 中。dryRun 为 true 时只统计不修改。
+func MigrateKeyPrefix(ctx context.Context, client *redis.Client, from, to, match string, skip []string, batch int64, dryRun bool) (*KeyMigration, error) {
+	if from == to {
+		return nil, errors.New("source and target prefix are the same")
+	}
+	if match == "" {
+		match = "*"
+	}
+
+	res := &KeyMigration{}
+	iter := client.Scan(ctx, 0, from+match, batch).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if to != "" && strings.HasPrefix(key, to) || hasAnyPrefix(key, skip) {
+			continue
+		}
+
+		res.Matched++
+		if dryRun {
+			continue
+		}
+
+		renamed, err := client.RenameNX(ctx, key, to+strings.TrimPrefix(key, from)).Result()
+		if err != nil {
+			// SCAN 返回后键已过期
+			if strings.Contains(err.Error(), "no such key") {
+				res.Matched--
+				continue
+			}
+			return res, err
+		}
+		if !renamed {
+			res.Conflict = append(res.Conflict, key)
+			continue
+		}
+		res.Renamed++
+	}
+	return res, iter.Err()
+}
+
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockNotAcquired 锁已被其他调用方(本进程或其他实例)持有
+var ErrLockNotAcquired = errors.New("redis lock is held by another owner")
+
+// ErrLockNotHeld 释放锁时锁已过期或已被其他调用方重新获取
+var ErrLockNotHeld = errors.New("redis lock is not held")
+
+// unlockScript 只有值等于加锁时的令牌才删除，避免锁过期后误删其他调用方获取的锁
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// Lock 使用 SET key token NX PX ttl 加锁，成功时返回令牌，释放时需要传回；
+// 锁已被持有时返回 ErrLockNotAcquired，不等待
+func (rc *redisRepository) Lock(key string, ttl time.Duration) (string, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return "", err
+	}
+
+	ok, err := rc.conn().SetNX(rc.ctx, rc.Key(key), token, ttl).Result()
+	if err != nil {
+		return "", fmt.Errorf("redis lock %s failed: %w", key, err)
+	}
+	if !ok {
+		return "", fmt.Errorf("redis lock %s: %w", key, ErrLockNotAcquired)
+	}
+	return token, nil
+}
+
+// Unlock 释放 Lock 获取的锁，锁已过期或被其他调用方持有时返回 ErrLockNotHeld
+func (rc *redisRepository) Unlock(key, token string) error {
+	n, err := unlockScript.Run(rc.ctx, rc.conn(), []string{rc.Key(key)}, token).Int()
+	if err != nil {
+		return fmt.Errorf("redis unlock %s failed: %w", key, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("redis unlock %s: %w", key, ErrLockNotHeld)
+	}
+	return nil
+}
+
+// WithLock 持有锁执行 fn，结束后释放锁
+//
+// 锁已被持有时不执行 fn，返回 ErrLockNotAcquired；fn 返回错误时返回该错误，
+// 否则返回释放锁的错误，ErrLockNotHeld 表示 fn 执行时间超过了 ttl，期间可能有其他调用方获取了锁。
+func (rc *redisRepository) WithLock(key string, ttl time.Duration, fn func() error) (err error) {
+	token, err := rc.Lock(key, ttl)
+	if err != nil {
+		return err
+	}
+
+	// fn panic 时也释放锁
+	defer func() {
+		if unlockErr := rc.Unlock(key, token); err == nil {
+			err = unlockErr
+		}
+	}()
+	return fn()
+}
+
+// newLockToken 随机令牌，标识锁的持有者
+func newLockToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate redis lock token failed: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithLockUnavailable(t *testing.T) {
+	repo := NewRedisRepository(nil, context.Background(), "test:")
+
+	called := false
+	err := repo.WithLock("lock", time.Second, func() error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("err = %v, want ErrUnavailable", err)
+	}
+	if called {
+		t.Fatal("fn should not run when the lock cannot be acquired")
+	}
+}
+
+func TestWithLockNoop(t *testing.T) {
+	repo := NewNoopRepository(context.Background())
+
+	want := errors.New("boom")
+	if err := repo.WithLock("lock", time.Second, func() error { return want }); err != want {
+		t.Fatalf("err = %v, want fn error", err)
+	}
+}
+
+func TestLockTokenIsRandom(t *testing.T) {
+	a, err := newLockToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := newLockToken()
+	if a == b || len(a) != 32 {
+		t.Fatalf("tokens %q, %q should be distinct 32-char hex strings", a, b)
+	}
+}
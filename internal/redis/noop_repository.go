@@ -0,0 +1,214 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrDisabled Redis 已在配置中禁用(redis.enabled: false)
+var ErrDisabled = errors.New("redis is disabled")
+
+var _ RedisRepository = (*noopRepository)(nil)
+
+// noopRepository Redis 禁用时注入的空实现: 写操作直接成功，读操作按未命中处理，
+// 调用方无需判断 Redis 是否可用
+type noopRepository struct {
+	ctx context.Context
+}
+
+func NewNoopRepository(ctx context.Context) RedisRepository {
+	return &noopRepository{ctx: ctx}
+}
+
+func (n *noopRepository) Set(key, value string, expiration time.Duration, options ...Option) error {
+	return nil
+}
+
+func (n *noopRepository) Get(key string, options ...Option) (string, error) {
+	return "", ErrDisabled
+}
+
+func (n *noopRepository) MGet(keys []string, options ...Option) (map[string]string, error) {
+	return nil, ErrDisabled
+}
+
+func (n *noopRepository) Delete(key string, options ...Option) error {
+	return nil
+}
+
+func (n *noopRepository) Exists(key string) (bool, error) {
+	return false, nil
+}
+
+func (n *noopRepository) TTL(key string) (time.Duration, error) {
+	return 0, ErrDisabled
+}
+
+func (n *noopRepository) Type(key string) (string, error) {
+	return "none", nil
+}
+
+func (n *noopRepository) SetWithExpire(key, value string, expiration time.Duration, options ...Option) error {
+	return nil
+}
+
+func (n *noopRepository) SetNX(key, value string, expiration time.Duration) (bool, error) {
+	return false, ErrDisabled
+}
+
+// Lock Redis 禁用时按单实例部署处理，不做互斥，加锁总是成功
+func (n *noopRepository) Lock(key string, ttl time.Duration) (string, error) {
+	return "", nil
+}
+
+func (n *noopRepository) Unlock(key, token string) error {
+	return nil
+}
+
+func (n *noopRepository) WithLock(key string, ttl time.Duration, fn func() error) error {
+	return fn()
+}
+
+func (n *noopRepository) Increment(key string, options ...Option) (int64, error) {
+	return 0, ErrDisabled
+}
+
+func (n *noopRepository) Keys(pattern string) ([]string, error) {
+	return nil, ErrDisabled
+}
+
+func (n *noopRepository) ScanKeys(pattern string, count int64) ([]string, error) {
+	return nil, ErrDisabled
+}
+
+func (n *noopRepository) DeleteByPattern(pattern string, count int64) (int64, error) {
+	return 0, nil
+}
+
+func (n *noopRepository) ListRPush(key string, values ...interface{}) error {
+	return nil
+}
+
+func (n *noopRepository) ListLLen(key string) (int64, error) {
+	return 0, nil
+}
+
+func (n *noopRepository) ListLPop(key string) (string, error) {
+	return "", ErrDisabled
+}
+
+func (n *noopRepository) ListLRange(key string, start, stop int64) ([]string, error) {
+	return nil, nil
+}
+
+func (n *noopRepository) SetSAdd(key string, members ...interface{}) error {
+	return nil
+}
+
+func (n *noopRepository) SetSRem(key string, members ...interface{}) error {
+	return nil
+}
+
+func (n *noopRepository) SetSMembers(key string) ([]string, error) {
+	return nil, nil
+}
+
+func (n *noopRepository) SetSIsMember(key string, member interface{}) (bool, error) {
+	return false, nil
+}
+
+func (n *noopRepository) SetSCard(key string) (int64, error) {
+	return 0, nil
+}
+
+func (n *noopRepository) SetSRandMember(key string) (string, error) {
+	return "", ErrDisabled
+}
+
+func (n *noopRepository) SetZAdd(key string, members ...redis.Z) error {
+	return nil
+}
+
+func (n *noopRepository) SetZRem(key string, members ...interface{}) error {
+	return nil
+}
+
+func (n *noopRepository) SetZRange(key string, start, stop int64) ([]string, error) {
+	return nil, nil
+}
+
+func (n *noopRepository) SetZRevRange(key string, start, stop int64) ([]string, error) {
+	return nil, nil
+}
+
+func (n *noopRepository) SetZCard(key string) (int64, error) {
+	return 0, nil
+}
+
+func (n *noopRepository) SetZRangeByScore(key string, min, max string, start, stop int64) ([]string, error) {
+	return nil, nil
+}
+
+func (n *noopRepository) SetZRevRangeByScore(key string, min, max string, start, stop int64) ([]string, error) {
+	return nil, nil
+}
+
+func (n *noopRepository) GeoAdd(key string, locations ...*redis.GeoLocation) error {
+	return nil
+}
+
+func (n *noopRepository) GeoSearch(key string, longitude, latitude, radiusKm float64, count int) ([]redis.GeoLocation, error) {
+	return nil, ErrDisabled
+}
+
+func (n *noopRepository) SetZRevRangeWithScores(key string, start, stop int64) ([]redis.Z, error) {
+	return nil, nil
+}
+
+func (n *noopRepository) SetZScore(key string, member string) (float64, error) {
+	return 0, ErrDisabled
+}
+
+func (n *noopRepository) SetZIncrBy(key string, member string, increment float64) (float64, error) {
+	return 0, nil
+}
+
+func (n *noopRepository) SetZRank(key string, member string) (int64, error) {
+	return 0, ErrDisabled
+}
+
+func (n *noopRepository) SetZRevRank(key string, member string) (int64, error) {
+	return 0, ErrDisabled
+}
+
+func (n *noopRepository) HashSet(hashKey string, expireTime time.Duration, params HashParams) error {
+	return nil
+}
+
+func (n *noopRepository) HashGetAll(hashKey string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (n *noopRepository) HashGet(hashKey string, field string) (string, error) {
+	return "", ErrDisabled
+}
+
+func (n *noopRepository) Key(key string) string {
+	return key
+}
+
+func (n *noopRepository) GetRedisContext() context.Context {
+	return n.ctx
+}
+
+// GetRedisClient Redis 禁用时始终返回 nil，直接使用客户端的调用方需要判空
+func (n *noopRepository) GetRedisClient() *redis.Client {
+	return nil
+}
+
+func (n *noopRepository) SetRedisClient(client *redis.Client) {}
+
+func (n *noopRepository) Close() {}
@@ -2,7 +2,11 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"gin-app-start/pkg/timeutil"
@@ -32,14 +36,34 @@ type RedisRepository interface {
 	Set(key, value string, expiration time.Duration, options ...Option) error
 	// Get 获取键的值
 	Get(key string, options ...Option) (string, error)
+	// MGet 批量获取键的值，返回存在的键 -> 值，不存在的键不在结果中
+	MGet(keys []string, options ...Option) (map[string]string, error)
 	// Delete 删除键
 	Delete(key string, options ...Option) error
 	// Exists 检查键是否存在
 	Exists(key string) (bool, error)
+	// TTL 获取键的剩余过期时间
+	TTL(key string) (time.Duration, error)
+	// Type 获取键的类型(string/list/set/zset/hash)
+	Type(key string) (string, error)
 	// SetWithExpire 设置带过期时间的键值对
 	SetWithExpire(key, value string, expiration time.Duration, options ...Option) error
+	// SetNX 键不存在时设置带过期时间的键值对，返回是否设置成功；需要互斥时使用 Lock/WithLock
+	SetNX(key, value string, expiration time.Duration) (bool, error)
+	// Lock 获取分布式锁，返回释放时需要的令牌，锁已被持有时返回 ErrLockNotAcquired
+	Lock(key string, ttl time.Duration) (string, error)
+	// Unlock 用 Lock 返回的令牌释放锁，锁已过期或被其他调用方持有时返回 ErrLockNotHeld
+	Unlock(key, token string) error
+	// WithLock 持有锁执行 fn，多实例之间同一时刻只有一个调用方在执行
+	WithLock(key string, ttl time.Duration, fn func() error) error
 	// Increment 对数字值进行递增
 	Increment(key string, options ...Option) (int64, error)
+	// Keys 返回匹配 pattern 的键，pattern 和返回的键都不含命名空间前缀；会阻塞 Redis，键较多时使用 ScanKeys
+	Keys(pattern string) ([]string, error)
+	// ScanKeys 用 SCAN 返回匹配 pattern 的键，count 为每次 SCAN 的 COUNT 提示，不阻塞 Redis
+	ScanKeys(pattern string, count int64) ([]string, error)
+	// DeleteByPattern 用 SCAN 查找匹配 pattern 的键并按批 UNLINK 删除，返回删除的键数
+	DeleteByPattern(pattern string, count int64) (int64, error)
 	// ListRPush 从右侧推入列表元素
 	ListRPush(key string, values ...interface{}) error
 	// ListLLen 获取列表长度
@@ -74,36 +98,75 @@ type RedisRepository interface {
 	SetZRangeByScore(key string, min, max string, start, stop int64) ([]string, error)
 	// SetZRevRangeByScore 获取有序集合指定分数范围内的元素(按分数降序)
 	SetZRevRangeByScore(key string, min, max string, start, stop int64) ([]string, error)
-	// SetZScore 获取有序集合中元素的分数
-	SetZScore(key string, member string) error
-	// SetZIncrBy 增加有序集合中元素的分数
-	SetZIncrBy(key string, member string, increment float64) error
-	// SetZRank 获取有序集合中元素的排名（按分数升序）
-	SetZRank(key string, member string) error
-	// SetZRevRank 获取有序集合中元素的排名（按分数降序）
-	SetZRevRank(key string, member string) error
+	// SetZRevRangeWithScores 获取有序集合指定范围的元素及分数(按分数降序)
+	SetZRevRangeWithScores(key string, start, stop int64) ([]redis.Z, error)
+	// SetZScore 获取有序集合中元素的分数，元素不存在时返回 ErrMemberNotExist
+	SetZScore(key string, member string) (float64, error)
+	// SetZIncrBy 增加有序集合中元素的分数，返回增加后的分数
+	SetZIncrBy(key string, member string, increment float64) (float64, error)
+	// SetZRank 获取有序集合中元素的排名（按分数升序，从 0 开始），元素不存在时返回 ErrMemberNotExist
+	SetZRank(key string, member string) (int64, error)
+	// SetZRevRank 获取有序集合中元素的排名（按分数降序，从 0 开始），元素不存在时返回 ErrMemberNotExist
+	SetZRevRank(key string, member string) (int64, error)
+	// GeoAdd 添加/更新地理位置，位置保存在有序集合中，可以用 SetZRem 删除
+	GeoAdd(key string, locations ...*redis.GeoLocation) error
+	// GeoSearch 查询以 (longitude, latitude) 为圆心、radiusKm 公里内的位置，按距离升序，带距离(公里)和坐标；count 为 0 时不限制数量
+	GeoSearch(key string, longitude, latitude, radiusKm float64, count int) ([]redis.GeoLocation, error)
 	// SetHashSet 设置哈希字段
 	HashSet(hashKey string, expireTime time.Duration, params HashParams) error
 	// SetHashGetAll 获取哈希字段的所有值
 	HashGetAll(hashKey string) (map[string]string, error)
 	// SetHashGet 获取哈希字段的值
 	HashGet(hashKey string, field string) (string, error)
+	// Key 加上命名空间前缀后的实际键名，通过 GetRedisClient 直接访问 Redis 时必须用它转换键名
+	Key(key string) string
 	// GetRedisContext 获取Redis上下文
 	GetRedisContext() context.Context
 	// GetRedisClient 获取Redis客户端
 	GetRedisClient() *redis.Client
+	// SetRedisClient 替换Redis客户端(断线重连后调用)
+	SetRedisClient(client *redis.Client)
 	// Close 关闭Redis连接
 	Close()
 }
 
 // redisRepository 封装Redis客户端
 type redisRepository struct {
-	client *redis.Client
+	client atomic.Pointer[redis.Client] // 重连后会被替换
 	ctx    context.Context
+	prefix string // 键的命名空间前缀，多个环境共用一个 Redis 时避免键冲突
 }
 
-func NewRedisRepository(client *redis.Client, ctx context.Context) RedisRepository {
-	return &redisRepository{client: client, ctx: ctx}
+// NewRedisRepository client 可以为 nil(启动时 Redis 不可用)，此时所有操作返回 ErrUnavailable，
+// 重连成功后通过 SetRedisClient 切换
+//
+// prefix 为键的命名空间前缀(如 gin-app:prod:)，所有方法自动加上前缀，调用方使用不带前缀的键名
+func NewRedisRepository(client *redis.Client, ctx context.Context, prefix string) RedisRepository {
+	rc := &redisRepository{ctx: ctx, prefix: prefix}
+	rc.client.Store(client)
+	return rc
+}
+
+// ErrUnavailable Redis 未连接
+var ErrUnavailable = errors.New("redis is not connected")
+
+// ErrMemberNotExist 有序集合中不存在该元素
+var ErrMemberNotExist = errors.New("redis member does not exist")
+
+// unavailableClient 未连接时使用的客户端，所有命令立即返回 ErrUnavailable，避免空指针 panic
+var unavailableClient = redis.NewClient(&redis.Options{
+	Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, ErrUnavailable
+	},
+	MaxRetries: -1,
+})
+
+// conn 当前可用的客户端
+func (rc *redisRepository) conn() *redis.Client {
+	if c := rc.client.Load(); c != nil {
+		return c
+	}
+	return unavailableClient
 }
 
 // Set 设置键值对
@@ -126,7 +189,7 @@ func (rc *redisRepository) Set(key, value string, expiration time.Duration, opti
 		f(opt)
 	}
 
-	err := rc.client.Set(rc.ctx, key, value, expiration).Err()
+	err := rc.conn().Set(rc.ctx, rc.Key(key), value, expiration).Err()
 	if err != nil {
 		return fmt.Errorf("redis set %s -> %s failed: %w", key, value, err)
 	}
@@ -151,7 +214,7 @@ func (rc *redisRepository) Get(key string, options ...Option) (string, error) {
 		f(opt)
 	}
 
-	value, err := rc.client.Get(rc.ctx, key).Result()
+	value, err := rc.conn().Get(rc.ctx, rc.Key(key)).Result()
 	if err == redis.Nil {
 		return "", fmt.Errorf("redis key %s does not exist", key)
 	} else if err != nil {
@@ -160,6 +223,45 @@ func (rc *redisRepository) Get(key string, options ...Option) (string, error) {
 	return value, nil
 }
 
+// MGet 批量获取键的值，返回存在的键 -> 值，不存在的键不在结果中
+func (rc *redisRepository) MGet(keys []string, options ...Option) (map[string]string, error) {
+	start := time.Now()
+	opt := newOption()
+	defer func() {
+		if opt.Trace != nil {
+			opt.Redis.Timestamp = timeutil.CSTLayoutString()
+			opt.Redis.Handle = "MGet"
+			opt.Redis.Key = strings.Join(keys, ",")
+			opt.Redis.CostSeconds = time.Since(start).Seconds()
+			opt.Trace.AppendRedis(opt.Redis)
+		}
+	}()
+
+	for _, f := range options {
+		f(opt)
+	}
+
+	res := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return res, nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = rc.Key(key)
+	}
+	values, err := rc.conn().MGet(rc.ctx, prefixed...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis mget %d keys failed: %w", len(keys), err)
+	}
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			res[keys[i]] = s
+		}
+	}
+	return res, nil
+}
+
 // Delete 删除键
 func (rc *redisRepository) Delete(key string, options ...Option) error {
 	start := time.Now()
@@ -178,7 +280,7 @@ func (rc *redisRepository) Delete(key string, options ...Option) error {
 		f(opt)
 	}
 
-	err := rc.client.Del(rc.ctx, key).Err()
+	err := rc.conn().Del(rc.ctx, rc.Key(key)).Err()
 	if err != nil {
 		return fmt.Errorf("redis delete key %s failed: %w", key, err)
 	}
@@ -187,7 +289,7 @@ func (rc *redisRepository) Delete(key string, options ...Option) error {
 
 // Exists 检查键是否存在
 func (rc *redisRepository) Exists(key string) (bool, error) {
-	result, err := rc.client.Exists(rc.ctx, key).Result()
+	result, err := rc.conn().Exists(rc.ctx, rc.Key(key)).Result()
 	if err != nil {
 		return false, fmt.Errorf("redis check key %s existence failed: %w", key, err)
 	}
@@ -195,6 +297,25 @@ func (rc *redisRepository) Exists(key string) (bool, error) {
 	return exists, nil
 }
 
+// TTL 获取键的剩余过期时间
+// 键不存在时返回 -2，键存在但未设置过期时间时返回 -1
+func (rc *redisRepository) TTL(key string) (time.Duration, error) {
+	ttl, err := rc.conn().TTL(rc.ctx, rc.Key(key)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis ttl %s failed: %w", key, err)
+	}
+	return ttl, nil
+}
+
+// Type 获取键的类型
+func (rc *redisRepository) Type(key string) (string, error) {
+	keyType, err := rc.conn().Type(rc.ctx, rc.Key(key)).Result()
+	if err != nil {
+		return "", fmt.Errorf("redis type %s failed: %w", key, err)
+	}
+	return keyType, nil
+}
+
 // SetWithExpire 设置带过期时间的键值对
 func (rc *redisRepository) SetWithExpire(key, value string, expiration time.Duration, options ...Option) error {
 	start := time.Now()
@@ -215,13 +336,22 @@ func (rc *redisRepository) SetWithExpire(key, value string, expiration time.Dura
 		f(opt)
 	}
 
-	err := rc.client.SetEx(rc.ctx, key, value, expiration).Err()
+	err := rc.conn().SetEx(rc.ctx, rc.Key(key), value, expiration).Err()
 	if err != nil {
 		return fmt.Errorf("redis set %s -> %s with expiration %v failed: %w", key, value, expiration, err)
 	}
 	return nil
 }
 
+// SetNX 键不存在时设置带过期时间的键值对
+func (rc *redisRepository) SetNX(key, value string, expiration time.Duration) (bool, error) {
+	ok, err := rc.conn().SetNX(rc.ctx, rc.Key(key), value, expiration).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis setnx %s failed: %w", key, err)
+	}
+	return ok, nil
+}
+
 // Increment 对数字值进行递增
 func (rc *redisRepository) Increment(key string, options ...Option) (int64, error) {
 	start := time.Now()
@@ -240,16 +370,85 @@ func (rc *redisRepository) Increment(key string, options ...Option) (int64, erro
 		f(opt)
 	}
 
-	result, err := rc.client.Incr(rc.ctx, key).Result()
+	result, err := rc.conn().Incr(rc.ctx, rc.Key(key)).Result()
 	if err != nil {
 		return 0, fmt.Errorf("redis increment key %s failed: %w", key, err)
 	}
 	return result, nil
 }
 
+// Keys 返回匹配 pattern 的键，会阻塞 Redis，只用于键数量有限的场景
+func (rc *redisRepository) Keys(pattern string) ([]string, error) {
+	keys, err := rc.conn().Keys(rc.ctx, rc.Key(pattern)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis keys %s failed: %w", pattern, err)
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, rc.prefix)
+	}
+	return keys, nil
+}
+
+// ScanKeys 用 SCAN 分批遍历匹配 pattern 的键，遍历期间新增或删除的键可能返回也可能不返回
+func (rc *redisRepository) ScanKeys(pattern string, count int64) ([]string, error) {
+	seen := make(map[string]struct{})
+	var keys []string
+	err := rc.scan(pattern, count, func(batch []string) error {
+		// SCAN 可能重复返回同一个键
+		for _, key := range batch {
+			key = strings.TrimPrefix(key, rc.prefix)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// DeleteByPattern 每批 SCAN 的结果用一次 UNLINK 删除，UNLINK 在后台释放内存，不会因大键阻塞 Redis
+func (rc *redisRepository) DeleteByPattern(pattern string, count int64) (int64, error) {
+	var deleted int64
+	err := rc.scan(pattern, count, func(batch []string) error {
+		n, err := rc.conn().Unlink(rc.ctx, batch...).Result()
+		if err != nil {
+			return fmt.Errorf("redis unlink %d keys failed: %w", len(batch), err)
+		}
+		deleted += n
+		return nil
+	})
+	return deleted, err
+}
+
+// scan 遍历匹配 pattern 的键，对每批非空结果调用 fn，批中的键带命名空间前缀
+func (rc *redisRepository) scan(pattern string, count int64, fn func(batch []string) error) error {
+	client := rc.conn()
+	var cursor uint64
+	for {
+		batch, next, err := client.Scan(rc.ctx, cursor, rc.Key(pattern), count).Result()
+		if err != nil {
+			return fmt.Errorf("redis scan %s failed: %w", pattern, err)
+		}
+		if len(batch) > 0 {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
 // ListRPush 从右侧推入列表元素
 func (rc *redisRepository) ListRPush(key string, values ...interface{}) error {
-	err := rc.client.RPush(rc.ctx, key, values...).Err()
+	err := rc.conn().RPush(rc.ctx, rc.Key(key), values...).Err()
 	if err != nil {
 		return fmt.Errorf("redis list rpush %s -> %v failed: %w", key, values, err)
 	}
@@ -258,7 +457,7 @@ func (rc *redisRepository) ListRPush(key string, values ...interface{}) error {
 
 // ListLLen 获取列表长度
 func (rc *redisRepository) ListLLen(key string) (int64, error) {
-	length, err := rc.client.LLen(rc.ctx, key).Result()
+	length, err := rc.conn().LLen(rc.ctx, rc.Key(key)).Result()
 	if err != nil {
 		return 0, fmt.Errorf("redis list llen %s failed: %w", key, err)
 	}
@@ -267,7 +466,7 @@ func (rc *redisRepository) ListLLen(key string) (int64, error) {
 
 // ListLPop 从左侧弹出列表元素
 func (rc *redisRepository) ListLPop(key string) (string, error) {
-	value, err := rc.client.LPop(rc.ctx, key).Result()
+	value, err := rc.conn().LPop(rc.ctx, rc.Key(key)).Result()
 	if err == redis.Nil {
 		return "", fmt.Errorf("redis list %s is empty", key)
 	} else if err != nil {
@@ -278,7 +477,7 @@ func (rc *redisRepository) ListLPop(key string) (string, error) {
 
 // ListLRange 获取列表指定范围的元素[start, stop]
 func (rc *redisRepository) ListLRange(key string, start, stop int64) ([]string, error) {
-	items, err := rc.client.LRange(rc.ctx, key, start, stop).Result()
+	items, err := rc.conn().LRange(rc.ctx, rc.Key(key), start, stop).Result()
 	if err != nil {
 		return nil, fmt.Errorf("redis list lrange %s failed: %w", key, err)
 	}
@@ -287,7 +486,7 @@ func (rc *redisRepository) ListLRange(key string, start, stop int64) ([]string,
 
 // SetSAdd 添加元素到集合
 func (rc *redisRepository) SetSAdd(key string, members ...interface{}) error {
-	err := rc.client.SAdd(rc.ctx, key, members...).Err()
+	err := rc.conn().SAdd(rc.ctx, rc.Key(key), members...).Err()
 	if err != nil {
 		return fmt.Errorf("redis set sadd %s -> %v failed: %w", key, members, err)
 	}
@@ -296,7 +495,7 @@ func (rc *redisRepository) SetSAdd(key string, members ...interface{}) error {
 
 // SetSRem 移除集合中的元素
 func (rc *redisRepository) SetSRem(key string, members ...interface{}) error {
-	err := rc.client.SRem(rc.ctx, key, members...).Err()
+	err := rc.conn().SRem(rc.ctx, rc.Key(key), members...).Err()
 	if err != nil {
 		return fmt.Errorf("redis set srem %s -> %v failed: %w", key, members, err)
 	}
@@ -305,7 +504,7 @@ func (rc *redisRepository) SetSRem(key string, members ...interface{}) error {
 
 // SetSMembers 获取集合所有元素
 func (rc *redisRepository) SetSMembers(key string) ([]string, error) {
-	members, err := rc.client.SMembers(rc.ctx, key).Result()
+	members, err := rc.conn().SMembers(rc.ctx, rc.Key(key)).Result()
 	if err != nil {
 		return nil, fmt.Errorf("redis set smembers %s failed: %w", key, err)
 	}
@@ -314,7 +513,7 @@ func (rc *redisRepository) SetSMembers(key string) ([]string, error) {
 
 // SetSIsMember 检查元素是否在集合中
 func (rc *redisRepository) SetSIsMember(key string, member interface{}) (bool, error) {
-	isMember, err := rc.client.SIsMember(rc.ctx, key, member).Result()
+	isMember, err := rc.conn().SIsMember(rc.ctx, rc.Key(key), member).Result()
 	if err != nil {
 		return false, fmt.Errorf("redis set smember %s -> %v failed: %w", key, member, err)
 	}
@@ -323,7 +522,7 @@ func (rc *redisRepository) SetSIsMember(key string, member interface{}) (bool, e
 
 // SetSCard 获取集合元素数量
 func (rc *redisRepository) SetSCard(key string) (int64, error) {
-	cardinality, err := rc.client.SCard(rc.ctx, key).Result()
+	cardinality, err := rc.conn().SCard(rc.ctx, rc.Key(key)).Result()
 	if err != nil {
 		return 0, fmt.Errorf("redis set scard %s failed: %w", key, err)
 	}
@@ -332,7 +531,7 @@ func (rc *redisRepository) SetSCard(key string) (int64, error) {
 
 // SetSRandMember 随机获取集合中的一个元素
 func (rc *redisRepository) SetSRandMember(key string) (string, error) {
-	randomMember, err := rc.client.SRandMember(rc.ctx, key).Result()
+	randomMember, err := rc.conn().SRandMember(rc.ctx, rc.Key(key)).Result()
 	if err != nil {
 		return "", fmt.Errorf("redis set srandmember %s failed: %w", key, err)
 	}
@@ -341,7 +540,7 @@ func (rc *redisRepository) SetSRandMember(key string) (string, error) {
 
 // SetZAdd 添加/更新有序集合中的元素（带分数）
 func (rc *redisRepository) SetZAdd(key string, members ...redis.Z) error {
-	err := rc.client.ZAdd(rc.ctx, key, members...).Err()
+	err := rc.conn().ZAdd(rc.ctx, rc.Key(key), members...).Err()
 	if err != nil {
 		return fmt.Errorf("redis set zadd %s -> %v failed: %w", key, members, err)
 	}
@@ -350,7 +549,7 @@ func (rc *redisRepository) SetZAdd(key string, members ...redis.Z) error {
 
 // SetZRem 移除有序集合中的元素
 func (rc *redisRepository) SetZRem(key string, members ...interface{}) error {
-	err := rc.client.ZRem(rc.ctx, key, members...).Err()
+	err := rc.conn().ZRem(rc.ctx, rc.Key(key), members...).Err()
 	if err != nil {
 		return fmt.Errorf("redis set zrem %s -> %v failed: %w", key, members, err)
 	}
@@ -359,7 +558,7 @@ func (rc *redisRepository) SetZRem(key string, members ...interface{}) error {
 
 // SetZRange 获取有序集合指定范围的元素(按分数升序) [start, stop]
 func (rc *redisRepository) SetZRange(key string, start, stop int64) ([]string, error) {
-	members, err := rc.client.ZRange(rc.ctx, key, start, stop).Result()
+	members, err := rc.conn().ZRange(rc.ctx, rc.Key(key), start, stop).Result()
 	if err != nil {
 		return nil, fmt.Errorf("redis set zrange %s failed: %w", key, err)
 	}
@@ -368,16 +567,25 @@ func (rc *redisRepository) SetZRange(key string, start, stop int64) ([]string, e
 
 // SetZRevRange 获取有序集合指定范围的元素(按分数降序) [start, stop]
 func (rc *redisRepository) SetZRevRange(key string, start, stop int64) ([]string, error) {
-	members, err := rc.client.ZRevRange(rc.ctx, key, start, stop).Result()
+	members, err := rc.conn().ZRevRange(rc.ctx, rc.Key(key), start, stop).Result()
 	if err != nil {
 		return nil, fmt.Errorf("redis set zrevrange %s failed: %w", key, err)
 	}
 	return members, nil
 }
 
+// SetZRevRangeWithScores 获取有序集合指定范围的元素及分数(按分数降序) [start, stop]
+func (rc *redisRepository) SetZRevRangeWithScores(key string, start, stop int64) ([]redis.Z, error) {
+	members, err := rc.conn().ZRevRangeWithScores(rc.ctx, rc.Key(key), start, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis set zrevrange withscores %s failed: %w", key, err)
+	}
+	return members, nil
+}
+
 // SetZCard 获取有序集合元素数量
 func (rc *redisRepository) SetZCard(key string) (int64, error) {
-	cardinality, err := rc.client.ZCard(rc.ctx, key).Result()
+	cardinality, err := rc.conn().ZCard(rc.ctx, rc.Key(key)).Result()
 	if err != nil {
 		return 0, fmt.Errorf("redis set zcard %s failed: %w", key, err)
 	}
@@ -390,7 +598,7 @@ func (rc *redisRepository) SetZRangeByScore(key string, min, max string, start,
 		return nil, fmt.Errorf("min[%s] must less than max[%s]", min, max)
 	}
 
-	members, err := rc.client.ZRangeByScore(rc.ctx, key, &redis.ZRangeBy{
+	members, err := rc.conn().ZRangeByScore(rc.ctx, rc.Key(key), &redis.ZRangeBy{
 		Min:    min,
 		Max:    max,
 		Offset: start,
@@ -408,7 +616,7 @@ func (rc *redisRepository) SetZRevRangeByScore(key string, min, max string, star
 		return nil, fmt.Errorf("min[%s] must less than max[%s]", min, max)
 	}
 
-	members, err := rc.client.ZRevRangeByScore(rc.ctx, key, &redis.ZRangeBy{
+	members, err := rc.conn().ZRevRangeByScore(rc.ctx, rc.Key(key), &redis.ZRangeBy{
 		Min:    min,
 		Max:    max,
 		Offset: start,
@@ -421,39 +629,74 @@ func (rc *redisRepository) SetZRevRangeByScore(key string, min, max string, star
 }
 
 // SetZScore 获取有序集合中元素的分数
-func (rc *redisRepository) SetZScore(key string, member string) error {
-	_, err := rc.client.ZScore(rc.ctx, key, member).Result()
-	if err != nil {
-		return fmt.Errorf("redis set ZScore failed: %w", err)
+func (rc *redisRepository) SetZScore(key string, member string) (float64, error) {
+	score, err := rc.conn().ZScore(rc.ctx, rc.Key(key), member).Result()
+	if err == redis.Nil {
+		return 0, fmt.Errorf("redis set ZScore %s %s: %w", key, member, ErrMemberNotExist)
+	} else if err != nil {
+		return 0, fmt.Errorf("redis set ZScore failed: %w", err)
 	}
-	return nil
+	return score, nil
 }
 
-// SetZIncrBy 增加有序集合中元素的分数
-func (rc *redisRepository) SetZIncrBy(key string, member string, increment float64) error {
-	_, err := rc.client.ZIncrBy(rc.ctx, key, increment, member).Result()
+// SetZIncrBy 增加有序集合中元素的分数，返回增加后的分数
+func (rc *redisRepository) SetZIncrBy(key string, member string, increment float64) (float64, error) {
+	score, err := rc.conn().ZIncrBy(rc.ctx, rc.Key(key), increment, member).Result()
 	if err != nil {
-		return fmt.Errorf("redis set ZIncrBy failed: %w", err)
+		return 0, fmt.Errorf("redis set ZIncrBy failed: %w", err)
 	}
-	return nil
+	return score, nil
+}
+
+// SetZRank 获取有序集合中元素的排名（按分数升序，从 0 开始）
+func (rc *redisRepository) SetZRank(key string, member string) (int64, error) {
+	rank, err := rc.conn().ZRank(rc.ctx, rc.Key(key), member).Result()
+	if err == redis.Nil {
+		return 0, fmt.Errorf("redis set ZRank %s %s: %w", key, member, ErrMemberNotExist)
+	} else if err != nil {
+		return 0, fmt.Errorf("redis set ZRank failed: %w", err)
+	}
+	return rank, nil
+}
+
+// SetZRevRank 获取有序集合中元素的排名（按分数降序，从 0 开始）
+func (rc *redisRepository) SetZRevRank(key string, member string) (int64, error) {
+	rank, err := rc.conn().ZRevRank(rc.ctx, rc.Key(key), member).Result()
+	if err == redis.Nil {
+		return 0, fmt.Errorf("redis set ZRevRank %s %s: %w", key, member, ErrMemberNotExist)
+	} else if err != nil {
+		return 0, fmt.Errorf("redis set ZRevRank failed: %w", err)
+	}
+	return rank, nil
 }
 
-// SetZRank 获取有序集合中元素的排名（按分数升序）
-func (rc *redisRepository) SetZRank(key string, member string) error {
-	_, err := rc.client.ZRank(rc.ctx, key, member).Result()
+// GeoAdd 添加/更新地理位置
+func (rc *redisRepository) GeoAdd(key string, locations ...*redis.GeoLocation) error {
+	err := rc.conn().GeoAdd(rc.ctx, rc.Key(key), locations...).Err()
 	if err != nil {
-		return fmt.Errorf("redis set ZRank failed: %w", err)
+		return fmt.Errorf("redis geoadd %s failed: %w", key, err)
 	}
 	return nil
 }
 
-// SetZRevRank 获取有序集合中元素的排名（按分数降序）
-func (rc *redisRepository) SetZRevRank(key string, member string) error {
-	_, err := rc.client.ZRevRank(rc.ctx, key, member).Result()
+// GeoSearch 按半径查询地理位置(GEOSEARCH ... FROMLONLAT ... BYRADIUS ... ASC)
+func (rc *redisRepository) GeoSearch(key string, longitude, latitude, radiusKm float64, count int) ([]redis.GeoLocation, error) {
+	locations, err := rc.conn().GeoSearchLocation(rc.ctx, rc.Key(key), &redis.GeoSearchLocationQuery{
+		GeoSearchQuery: redis.GeoSearchQuery{
+			Longitude:  longitude,
+			Latitude:   latitude,
+			Radius:     radiusKm,
+			RadiusUnit: "km",
+			Sort:       "ASC",
+			Count:      count,
+		},
+		WithCoord: true,
+		WithDist:  true,
+	}).Result()
 	if err != nil {
-		return fmt.Errorf("redis set ZRevRank failed: %w", err)
+		return nil, fmt.Errorf("redis geosearch %s failed: %w", key, err)
 	}
-	return nil
+	return locations, nil
 }
 
 type HashParams struct {
@@ -481,9 +724,9 @@ func (rc *redisRepository) HashSet(hashKey string, expireTime time.Duration, par
 		f(opt)
 	}
 
-	pipe := rc.client.TxPipeline()
-	pipe.HSet(rc.ctx, hashKey, params.Values...)
-	pipe.Expire(rc.ctx, hashKey, expireTime).Err()
+	pipe := rc.conn().TxPipeline()
+	pipe.HSet(rc.ctx, rc.Key(hashKey), params.Values...)
+	pipe.Expire(rc.ctx, rc.Key(hashKey), expireTime).Err()
 	_, err := pipe.Exec(rc.ctx)
 	if err != nil {
 		return fmt.Errorf("redis set HashSet failed: %w", err)
@@ -494,7 +737,7 @@ func (rc *redisRepository) HashSet(hashKey string, expireTime time.Duration, par
 
 // SetHashGetAll 获取哈希字段的所有值
 func (rc *redisRepository) HashGetAll(hashKey string) (map[string]string, error) {
-	fields, err := rc.client.HGetAll(rc.ctx, hashKey).Result()
+	fields, err := rc.conn().HGetAll(rc.ctx, rc.Key(hashKey)).Result()
 	if err != nil {
 		return nil, fmt.Errorf("redis set HashGetAll failed: %w", err)
 	}
@@ -503,13 +746,18 @@ func (rc *redisRepository) HashGetAll(hashKey string) (map[string]string, error)
 
 // SetHashGet 获取哈希字段的值
 func (rc *redisRepository) HashGet(hashKey string, field string) (string, error) {
-	value, err := rc.client.HGet(rc.ctx, hashKey, field).Result()
+	value, err := rc.conn().HGet(rc.ctx, rc.Key(hashKey), field).Result()
 	if err != nil {
 		return "", fmt.Errorf("redis set HashGet failed: %w", err)
 	}
 	return value, nil
 }
 
+// Key 加上命名空间前缀后的实际键名
+func (rc *redisRepository) Key(key string) string {
+	return rc.prefix + key
+}
+
 // GetRedisContext 获取Redis上下文
 func (rc *redisRepository) GetRedisContext() context.Context {
 	return rc.ctx
@@ -517,13 +765,20 @@ func (rc *redisRepository) GetRedisContext() context.Context {
 
 // GetRedisClient 获取Redis客户端
 func (rc *redisRepository) GetRedisClient() *redis.Client {
-	return rc.client
+	return rc.client.Load()
+}
+
+// SetRedisClient 替换Redis客户端，用于断线重连后切换到新客户端
+func (rc *redisRepository) SetRedisClient(client *redis.Client) {
+	if old := rc.client.Swap(client); old != nil && old != client {
+		old.Close()
+	}
 }
 
 // Close 关闭Redis连接
 func (rc *redisRepository) Close() {
-	if rc.client != nil {
-		rc.client.Close()
+	if c := rc.client.Load(); c != nil {
+		c.Close()
 	}
 }
 
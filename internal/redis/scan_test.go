@@ -0,0 +1,29 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestScanUnavailable(t *testing.T) {
+	repo := NewRedisRepository(nil, context.Background(), "test:")
+
+	if _, err := repo.ScanKeys("order_list:*", 100); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("ScanKeys err = %v, want ErrUnavailable", err)
+	}
+	if n, err := repo.DeleteByPattern("order_list:*", 100); n != 0 || !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("DeleteByPattern = %d, %v, want ErrUnavailable", n, err)
+	}
+}
+
+func TestScanNoop(t *testing.T) {
+	repo := NewNoopRepository(context.Background())
+
+	if _, err := repo.ScanKeys("order_list:*", 100); !errors.Is(err, ErrDisabled) {
+		t.Fatalf("ScanKeys err = %v, want ErrDisabled", err)
+	}
+	if n, err := repo.DeleteByPattern("order_list:*", 100); n != 0 || err != nil {
+		t.Fatalf("DeleteByPattern = %d, %v, want 0, nil", n, err)
+	}
+}
@@ -0,0 +1,145 @@
+// Package refreshtoken 在 Redis 中记录 jwt 模式下的刷新令牌，实现轮换、重用检测和吊销
+package refreshtoken
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"gin-app-start/internal/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrRevoked 令牌族已吊销或过期(登出、修改密码、检测到重用或超过有效期)
+	ErrRevoked = errors.New("refresh token revoked")
+	// ErrReused 刷新令牌已经换取过新令牌又被再次使用，可能已泄露，整个令牌族随之吊销
+	ErrReused = errors.New("refresh token reused")
+)
+
+const (
+	familyPrefix = "refresh:family:" // 令牌族 -> 当前有效的刷新令牌 jti 和所属用户
+	userPrefix   = "refresh:user:"   // 用户 -> 令牌族集合，用于吊销用户的全部令牌
+
+	fieldCurrent = "current"
+	fieldUser    = "user_id"
+)
+
+// rotateScript 只有当前有效的刷新令牌可以换取新令牌；旧令牌被再次使用时删除整个令牌族
+// 返回 1 轮换成功，0 令牌族不存在，-1 检测到重用
+var rotateScript = goredis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'current')
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return -1
+end
+redis.call('HSET', KEYS[1], 'current', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// Store 刷新令牌记录
+//
+// 每次登录创建一个令牌族，Redis 中以 refresh:family:{族ID} 记录当前唯一有效的刷新令牌，
+// 刷新时换成新令牌的 jti，键的过期时间随之延长为刷新令牌的有效期。
+// 用户的令牌族记录在 refresh:user:{用户ID} 中，修改密码或注销账号时一并删除。
+type Store struct {
+	repo redis.RedisRepository
+	ttl  time.Duration // 刷新令牌有效期
+}
+
+// NewStore 创建刷新令牌记录，ttl 为刷新令牌的有效期
+func NewStore(repo redis.RedisRepository, ttl time.Duration) *Store {
+	return &Store{repo: repo, ttl: ttl}
+}
+
+func familyKey(family string) string {
+	return familyPrefix + family
+}
+
+func userKey(userID uint) string {
+	return userPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+// Register 登录时记录新的令牌族，jti 为首个刷新令牌的ID
+func (s *Store) Register(ctx context.Context, userID uint, family, jti string) error {
+	client := s.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+
+	key := s.repo.Key(familyKey(family))
+	users := s.repo.Key(userKey(userID))
+	_, err := client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, key, fieldCurrent, jti, fieldUser, userID)
+		pipe.Expire(ctx, key, s.ttl)
+		pipe.SAdd(ctx, users, family)
+		pipe.Expire(ctx, users, s.ttl)
+		return nil
+	})
+	return err
+}
+
+// Rotate 用刷新令牌 jti 换取新令牌 next，返回 ErrRevoked 或 ErrReused 时不能签发新令牌
+func (s *Store) Rotate(ctx context.Context, userID uint, family, jti, next string) error {
+	client := s.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+
+	key := s.repo.Key(familyKey(family))
+	result, err := rotateScript.Run(ctx, client, []string{key}, jti, next, s.ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	switch result {
+	case 0:
+		return ErrRevoked
+	case -1:
+		return ErrReused
+	}
+
+	// 令牌族集合的过期时间不短于其中最晚过期的令牌族
+	return client.Expire(ctx, s.repo.Key(userKey(userID)), s.ttl).Err()
+}
+
+// Revoke 吊销令牌族，用于登出
+func (s *Store) Revoke(ctx context.Context, userID uint, family string) error {
+	client := s.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+
+	_, err := client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, s.repo.Key(familyKey(family)))
+		pipe.SRem(ctx, s.repo.Key(userKey(userID)), family)
+		return nil
+	})
+	return err
+}
+
+// RevokeUser 吊销用户的全部令牌族，用于修改密码和注销账号
+func (s *Store) RevokeUser(ctx context.Context, userID uint) error {
+	client := s.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+
+	users := s.repo.Key(userKey(userID))
+	families, err := client.SMembers(ctx, users).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(families)+1)
+	for _, family := range families {
+		keys = append(keys, s.repo.Key(familyKey(family)))
+	}
+	keys = append(keys, users)
+	return client.Del(ctx, keys...).Err()
+}
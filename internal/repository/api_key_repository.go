@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(ctx common.Context, key *model.APIKey) error
+	// GetActiveByHash 按哈希查询未吊销的 API Key，不存在时返回 gorm.ErrRecordNotFound
+	GetActiveByHash(ctx common.Context, keyHash string) (*model.APIKey, error)
+	// ListByUser 用户的全部 API Key(含已吊销)，最近创建的在前
+	ListByUser(ctx common.Context, userID uint) ([]*model.APIKey, error)
+	CountActive(ctx common.Context, userID uint) (int64, error)
+	// Revoke 吊销用户的 API Key，Key 不存在、不属于该用户或已吊销时返回 false
+	Revoke(ctx common.Context, userID, id uint) (bool, error)
+	// Touch 更新最近使用时间
+	Touch(ctx common.Context, id uint, at time.Time) error
+}
+
+type apiKeyRepository struct {
+	*BaseRepository[model.APIKey]
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{
+		BaseRepository: NewBaseRepository[model.APIKey](db),
+	}
+}
+
+func (r *apiKeyRepository) GetActiveByHash(ctx common.Context, keyHash string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := conn(ctx, r.db).
+		Where("key_hash = ? AND revoked_at IS NULL", keyHash).
+		First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) ListByUser(ctx common.Context, userID uint) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	err := conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepository) CountActive(ctx common.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *apiKeyRepository) Revoke(ctx common.Context, userID, id uint) (bool, error) {
+	res := conn(ctx, r.db).Model(&model.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *apiKeyRepository) Touch(ctx common.Context, id uint, at time.Time) error {
+	return conn(ctx, r.db).Model(&model.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error
+}
@@ -1,11 +1,32 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"iter"
+	"reflect"
+
 	"gin-app-start/internal/common"
+	"gin-app-start/pkg/retry"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Scope 附加到查询上的条件，如 func(db *gorm.DB) *gorm.DB { return db.Where("status = ?", 1) }
+type Scope = func(db *gorm.DB) *gorm.DB
+
+// BatchProgress 分批遍历的进度，每批回调时传入
+type BatchProgress struct {
+	Batch   int         // 批次序号，从 1 开始
+	Rows    int         // 本批的行数
+	Done    int64       // 已遍历的行数，含本批
+	LastKey interface{} // 本批最后一行的主键，任务中断后可以用 Where("id > ?", LastKey) 从断点继续
+}
+
+// errStopIteration Iterate 的调用方提前结束循环时用于停止分批查询
+var errStopIteration = errors.New("stop iteration")
+
 type BaseRepository[T any] struct {
 	db *gorm.DB
 }
@@ -15,12 +36,23 @@ func NewBaseRepository[T any](db *gorm.DB) *BaseRepository[T] {
 }
 
 func (r *BaseRepository[T]) Create(ctx common.Context, entity *T) error {
-	return r.db.WithContext(ctx.RequestContext()).Create(entity).Error
+	return conn(ctx, r.db).Create(entity).Error
 }
 
 func (r *BaseRepository[T]) GetByID(ctx common.Context, id uint) (*T, error) {
 	var entity T
-	err := r.db.WithContext(ctx.RequestContext()).First(&entity, id).Error
+	// 事务中的查询出错后整个事务已失效，重试没有意义
+	if InTransaction(ctx) {
+		if err := conn(ctx, r.db).First(&entity, id).Error; err != nil {
+			return nil, err
+		}
+		return &entity, nil
+	}
+
+	// 只读查询幂等，连接重置、超时等临时性错误可以安全重试
+	err := retry.Do(ctx.RequestContext(), func(stdCtx context.Context) error {
+		return r.db.WithContext(stdCtx).First(&entity, id).Error
+	}, retry.WithName("db.get_by_id"))
 	if err != nil {
 		return nil, err
 	}
@@ -28,12 +60,29 @@ func (r *BaseRepository[T]) GetByID(ctx common.Context, id uint) (*T, error) {
 }
 
 func (r *BaseRepository[T]) Update(ctx common.Context, entity *T) error {
-	return r.db.WithContext(ctx.RequestContext()).Save(entity).Error
+	return conn(ctx, r.db).Save(entity).Error
+}
+
+// UpdateFields 只更新 fields 中的列(键为列名)，
+// 不像 Update(Save) 那样写回全部字段，避免并发修改被覆盖、零值被错误写入
+func (r *BaseRepository[T]) UpdateFields(ctx common.Context, id uint, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	result := conn(ctx, r.db).Model(new(T)).Where("id = ?", id).Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 func (r *BaseRepository[T]) Delete(ctx common.Context, id uint) error {
 	// 软删除
-	return r.db.WithContext(ctx.RequestContext()).Delete(new(T), id).Error
+	return conn(ctx, r.db).Delete(new(T), id).Error
 
 	// 硬删除（谨慎使用）
 	// return r.db.WithContext(ctx).Unscoped().Delete(new(T), id).Error
@@ -46,16 +95,95 @@ func (r *BaseRepository[T]) List(ctx common.Context, offset, limit int) ([]*T, i
 		return nil, 0, err
 	}
 
-	err = r.db.WithContext(ctx.RequestContext()).Offset(offset).Limit(limit).Find(&entities).Error
+	err = conn(ctx, r.db).Offset(offset).Limit(limit).Find(&entities).Error
 	return entities, total, err
 }
 
+// ListAfter 游标分页，按主键升序返回主键大于 afterID 的至多 limit 条记录，afterID 为 0 时从第一条开始
+//
+// 与 List 的 OFFSET 分页不同，翻到多深的页查询代价都相同，翻页期间插入、删除数据也不会重复或遗漏；不统计总数。
+func (r *BaseRepository[T]) ListAfter(ctx common.Context, afterID uint, limit int, scopes ...Scope) ([]*T, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	pk := stmt.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, gorm.ErrPrimaryKeyRequired
+	}
+	column := clause.Column{Table: clause.CurrentTable, Name: pk.DBName}
+
+	var entities []*T
+	err := conn(ctx, r.db).Scopes(scopes...).
+		Where(clause.Gt{Column: column, Value: afterID}).
+		Order(clause.OrderByColumn{Column: column}).
+		Limit(limit).Find(&entities).Error
+	return entities, err
+}
+
 func (r *BaseRepository[T]) Count(ctx common.Context) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx.RequestContext()).Model(new(T)).Count(&count).Error
+	err := conn(ctx, r.db).Model(new(T)).Count(&count).Error
 	return count, err
 }
 
+// FindInBatches 按主键顺序分批遍历符合 scopes 条件的记录(不含软删除)，fn 返回错误时停止遍历并返回该错误
+//
+// 每批按上一批最后的主键继续查询(WHERE id > ?)，不使用 OFFSET，遍历上百万行时每批的查询代价相同，
+// 内存中同时只保留一批记录。用于导出、归档、重建索引等需要扫描大量数据的任务，progress 可用于上报进度。
+func (r *BaseRepository[T]) FindInBatches(ctx common.Context, batchSize int, fn func(batch []*T, progress BatchProgress) error, scopes ...Scope) error {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return err
+	}
+	pk := stmt.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return gorm.ErrPrimaryKeyRequired
+	}
+
+	var batch []*T
+	var progress BatchProgress
+	return conn(ctx, r.db).Scopes(scopes...).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, n int) error {
+			progress.Batch = n
+			progress.Rows = len(batch)
+			progress.Done += int64(len(batch))
+			progress.LastKey, _ = pk.ValueOf(tx.Statement.Context, reflect.ValueOf(batch[len(batch)-1]))
+			return fn(batch, progress)
+		}).Error
+}
+
+// Iterate 以迭代器的形式逐行返回 FindInBatches 遍历的记录，查询出错时返回一次 (nil, err) 后结束
+//
+//	for order, err := range repo.Iterate(ctx, 500) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// 循环中 break 或 return 时停止后续批次的查询。
+func (r *BaseRepository[T]) Iterate(ctx common.Context, batchSize int, scopes ...Scope) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		err := r.FindInBatches(ctx, batchSize, func(batch []*T, _ BatchProgress) error {
+			for _, entity := range batch {
+				if !yield(entity, nil) {
+					return errStopIteration
+				}
+			}
+			return nil
+		}, scopes...)
+		if err != nil && !errors.Is(err, errStopIteration) {
+			yield(nil, err)
+		}
+	}
+}
+
+// Transaction 在同一个数据库事务中执行 fn，fn 中使用传入的 ctx 调用的仓储方法(不限于本仓储)都在该事务中，见 Transactor
+func (r *BaseRepository[T]) Transaction(ctx common.Context, fn func(ctx common.Context) error) error {
+	return transaction(ctx, r.db, fn)
+}
+
 func (r *BaseRepository[T]) GetDB() *gorm.DB {
 	return r.db
 }
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BroadcastRepository interface {
+	Create(ctx common.Context, broadcast *model.Broadcast) error
+	GetByID(ctx common.Context, id uint) (*model.Broadcast, error)
+	List(ctx common.Context, offset, limit int) ([]*model.Broadcast, int64, error)
+
+	ClaimDue(ctx common.Context, now time.Time, staleBefore time.Time) (*model.Broadcast, error)
+	SaveProgress(ctx common.Context, broadcast *model.Broadcast) (bool, error)
+	Finish(ctx common.Context, id uint, status, errMsg string) error
+	Cancel(ctx common.Context, id uint) (bool, error)
+
+	CreateNotifications(ctx common.Context, notifications []*model.Notification) error
+	ListNotifications(ctx common.Context, userID uint, offset, limit int) ([]*model.Notification, int64, error)
+}
+
+type broadcastRepository struct {
+	*BaseRepository[model.Broadcast]
+}
+
+func NewBroadcastRepository(db *gorm.DB) BroadcastRepository {
+	return &broadcastRepository{
+		BaseRepository: NewBaseRepository[model.Broadcast](db),
+	}
+}
+
+func (r *broadcastRepository) List(ctx common.Context, offset, limit int) ([]*model.Broadcast, int64, error) {
+	var broadcasts []*model.Broadcast
+	var total int64
+
+	if err := conn(ctx, r.db).Model(&model.Broadcast{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := conn(ctx, r.db).Order("id DESC").Offset(offset).Limit(limit).Find(&broadcasts).Error
+	return broadcasts, total, err
+}
+
+// ClaimDue 领取一个到期的群发并置为 running，没有可领取的群发时返回 nil
+//
+// 可领取的群发: 到期的 pending 群发，以及 updated_at 早于 staleBefore 的 running 群发(执行实例已退出，从断点继续)。
+// 使用 FOR UPDATE SKIP LOCKED，多个实例同时扫描时同一个群发只会被一个实例领取。
+func (r *broadcastRepository) ClaimDue(ctx common.Context, now time.Time, staleBefore time.Time) (*model.Broadcast, error) {
+	sub := r.db.Model(&model.Broadcast{}).Select("id").
+		Where("(status = ? AND scheduled_at <= ?) OR (status = ? AND updated_at < ?)",
+			model.BroadcastPending, now, model.BroadcastRunning, staleBefore).
+		Order("scheduled_at").
+		Limit(1).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var claimed []*model.Broadcast
+	result := conn(ctx, r.db).Model(&claimed).
+		Clauses(clause.Returning{}).
+		Where("id = (?)", sub).
+		Updates(map[string]interface{}{
+			"status":     model.BroadcastRunning,
+			"started_at": gorm.Expr("COALESCE(started_at, ?)", now),
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+	return claimed[0], nil
+}
+
+// SaveProgress 记录发送进度，同时刷新 updated_at 作为心跳；群发已不是 running(如已被取消)时返回 false
+func (r *broadcastRepository) SaveProgress(ctx common.Context, broadcast *model.Broadcast) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.Broadcast{}).
+		Where("id = ? AND status = ?", broadcast.ID, model.BroadcastRunning).
+		Updates(map[string]interface{}{
+			"total":        broadcast.Total,
+			"processed":    broadcast.Processed,
+			"email_sent":   broadcast.EmailSent,
+			"email_failed": broadcast.EmailFailed,
+			"last_user_id": broadcast.LastUserID,
+			"updated_at":   time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Finish 结束 running 状态的群发，已被取消的群发保持 cancelled
+func (r *broadcastRepository) Finish(ctx common.Context, id uint, status, errMsg string) error {
+	return conn(ctx, r.db).Model(&model.Broadcast{}).
+		Where("id = ? AND status = ?", id, model.BroadcastRunning).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"error":       errMsg,
+			"finished_at": time.Now(),
+		}).Error
+}
+
+// Cancel 取消未结束的群发，群发已结束时返回 false
+func (r *broadcastRepository) Cancel(ctx common.Context, id uint) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.Broadcast{}).
+		Where("id = ? AND status IN ?", id, []string{model.BroadcastPending, model.BroadcastRunning}).
+		Updates(map[string]interface{}{
+			"status":      model.BroadcastCancelled,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateNotifications 批量写入站内信，同一群发重复写入同一用户时跳过(断点续发时最后一批可能重复)
+func (r *broadcastRepository) CreateNotifications(ctx common.Context, notifications []*model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&notifications).Error
+}
+
+func (r *broadcastRepository) ListNotifications(ctx common.Context, userID uint, offset, limit int) ([]*model.Notification, int64, error) {
+	var notifications []*model.Notification
+	var total int64
+
+	err := conn(ctx, r.db).Model(&model.Notification{}).Where("user_id = ?", userID).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = conn(ctx, r.db).Where("user_id = ?", userID).Order("id DESC").Offset(offset).Limit(limit).Find(&notifications).Error
+	return notifications, total, err
+}
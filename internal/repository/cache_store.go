@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/redis"
+	"gin-app-start/pkg/cache"
+)
+
+var _ cache.Store = (*CacheStore)(nil)
+
+// CacheStore 把 RedisRepository 适配为 cache.Store
+//
+// ctx 为 common.Context.RequestContext() 返回的 StdContext 时，Redis 调用记录到请求的 trace 中。
+type CacheStore struct {
+	cache redis.RedisRepository
+}
+
+func NewCacheStore(cache redis.RedisRepository) *CacheStore {
+	return &CacheStore{cache: cache}
+}
+
+func (s *CacheStore) Get(ctx context.Context, key string) (string, error) {
+	return s.cache.Get(key, traceOptions(ctx)...)
+}
+
+func (s *CacheStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return s.cache.SetWithExpire(key, value, ttl, traceOptions(ctx)...)
+}
+
+func traceOptions(ctx context.Context) []redis.Option {
+	if std, ok := ctx.(common.StdContext); ok {
+		return []redis.Option{redis.WithTrace(std.Trace)}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/redis"
+)
+
+// DefaultCacheTTL 实体缓存的默认过期时间
+const DefaultCacheTTL = 30 * time.Minute
+
+// CacheKeyFunc 根据主键生成缓存键
+type CacheKeyFunc func(id uint) string
+
+// InvalidateHook 实体被创建/更新/删除后触发，用于清理依赖该实体的其它缓存(如列表缓存)
+type InvalidateHook func(ctx common.Context, id uint) error
+
+// CacheOption 缓存装饰器的配置项
+type CacheOption func(*cacheOption)
+
+type cacheOption struct {
+	ttl     time.Duration
+	keyFunc CacheKeyFunc
+	hooks   []InvalidateHook
+}
+
+// WithCacheTTL 设置实体缓存的过期时间
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(opt *cacheOption) {
+		opt.ttl = ttl
+	}
+}
+
+// WithCacheKey 自定义缓存键
+func WithCacheKey(fn CacheKeyFunc) CacheOption {
+	return func(opt *cacheOption) {
+		opt.keyFunc = fn
+	}
+}
+
+// WithInvalidateHook 添加失效钩子
+func WithInvalidateHook(hook InvalidateHook) CacheOption {
+	return func(opt *cacheOption) {
+		opt.hooks = append(opt.hooks, hook)
+	}
+}
+
+// CacheProjector 实体写入缓存前的投影，返回去掉不应缓存的字段(如密码、盐值)的副本
+type CacheProjector[T any] interface {
+	CacheProjection() *T
+}
+
+// CachedRepository 为 BaseRepository 增加按主键的读穿透缓存
+//
+// GetByID 优先读缓存，未命中时回源数据库并写回缓存；Create/Update/Delete 成功后删除实体缓存并执行失效钩子。
+// 缓存使用 gob 编码；实体实现 CacheProjector 时只缓存投影，GetByID 无论是否命中都返回投影，
+// 因此不能用 GetByID 的结果整体回写(Update)，需要被去掉的字段时按其它条件回源查询。
+// 缓存不可用时所有操作直接走数据库，缓存错误不会影响业务结果。
+type CachedRepository[T any] struct {
+	*BaseRepository[T]
+	cache redis.RedisRepository
+	opt   *cacheOption
+}
+
+func NewCachedRepository[T any](base *BaseRepository[T], cache redis.RedisRepository, opts ...CacheOption) *CachedRepository[T] {
+	name := strings.ToLower(reflect.TypeOf(new(T)).Elem().Name())
+	opt := &cacheOption{
+		ttl: DefaultCacheTTL,
+		keyFunc: func(id uint) string {
+			return fmt.Sprintf("%s:id:%d", name, id)
+		},
+	}
+	for _, f := range opts {
+		f(opt)
+	}
+
+	return &CachedRepository[T]{
+		BaseRepository: base,
+		cache:          cache,
+		opt:            opt,
+	}
+}
+
+// enabled 缓存是否可用
+func (r *CachedRepository[T]) enabled() bool {
+	return r.cache != nil && r.cache.GetRedisClient() != nil
+}
+
+func (r *CachedRepository[T]) GetByID(ctx common.Context, id uint) (*T, error) {
+	if !r.enabled() {
+		return r.load(ctx, id)
+	}
+
+	key := r.opt.keyFunc(id)
+	if data, err := r.cache.Get(key, redis.WithTrace(ctx.Trace())); err == nil && data != "" {
+		var entity T
+		if err := gob.NewDecoder(strings.NewReader(data)).Decode(&entity); err == nil {
+			return &entity, nil
+		}
+	}
+
+	entity, err := r.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entity); err == nil {
+		_ = r.cache.SetWithExpire(key, buf.String(), r.opt.ttl, redis.WithTrace(ctx.Trace()))
+	}
+	return entity, nil
+}
+
+// load 从数据库查询实体，实体实现 CacheProjector 时返回投影，缓存是否可用时结果一致
+func (r *CachedRepository[T]) load(ctx common.Context, id uint) (*T, error) {
+	entity, err := r.BaseRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := any(entity).(CacheProjector[T]); ok {
+		return p.CacheProjection(), nil
+	}
+	return entity, nil
+}
+
+func (r *CachedRepository[T]) Create(ctx common.Context, entity *T) error {
+	if err := r.BaseRepository.Create(ctx, entity); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, entityID(entity))
+}
+
+func (r *CachedRepository[T]) Update(ctx common.Context, entity *T) error {
+	if err := r.BaseRepository.Update(ctx, entity); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, entityID(entity))
+}
+
+func (r *CachedRepository[T]) UpdateFields(ctx common.Context, id uint, fields map[string]interface{}) error {
+	if err := r.BaseRepository.UpdateFields(ctx, id, fields); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, id)
+}
+
+func (r *CachedRepository[T]) Delete(ctx common.Context, id uint) error {
+	if err := r.BaseRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, id)
+}
+
+// Invalidate 删除实体缓存并执行失效钩子，供绕过 CachedRepository 直接写库的方法调用
+func (r *CachedRepository[T]) Invalidate(ctx common.Context, id uint) error {
+	return r.invalidate(ctx, id)
+}
+
+func (r *CachedRepository[T]) invalidate(ctx common.Context, id uint) error {
+	if !r.enabled() {
+		return nil
+	}
+
+	if id != 0 {
+		if err := r.cache.Delete(r.opt.keyFunc(id), redis.WithTrace(ctx.Trace())); err != nil {
+			return err
+		}
+	}
+
+	for _, hook := range r.opt.hooks {
+		if err := hook(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entityID 通过反射读取实体的 ID 字段
+func entityID(entity interface{}) uint {
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	if v.Kind() != reflect.Struct {
+		return 0
+	}
+
+	field := v.FieldByName("ID")
+	if !field.IsValid() || field.Kind() != reflect.Uint {
+		return 0
+	}
+	return uint(field.Uint())
+}
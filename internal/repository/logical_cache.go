@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/redis"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// logicalExpireField 逻辑过期时间(Unix 毫秒)保存在 hash 的该字段中
+	logicalExpireField = "_expire_at"
+	// logicalRefreshLockTTL 后台刷新锁的有效期，刷新异常退出时锁到期自动释放
+	logicalRefreshLockTTL = 30 * time.Second
+)
+
+// RefreshFunc 后台刷新缓存的函数，通常为查询数据库并调用 LogicalCache.Set
+type RefreshFunc func(ctx common.Context) error
+
+// LogicalCache 逻辑过期缓存，用于管理端订单列表等并发读取很高的热点键
+//
+// 数据和逻辑过期时间保存在同一个 hash 中，Redis 中的实际过期时间为 ttl + staleTTL。
+// 逻辑过期后读取方继续返回旧数据，同时只由一个 goroutine 在后台回源刷新：本进程内按键去重，
+// 多实例之间通过 Redis 中的刷新锁去重，避免缓存失效瞬间大量请求同时回源数据库。
+// 超过 staleTTL 仍未刷新的数据由 Redis 删除，之后按未命中处理。
+type LogicalCache struct {
+	cache    redis.RedisRepository
+	ttl      time.Duration
+	staleTTL time.Duration
+
+	refreshing sync.Map // 本进程正在刷新的键
+	now        func() time.Time
+}
+
+func NewLogicalCache(cache redis.RedisRepository, ttl, staleTTL time.Duration) *LogicalCache {
+	return &LogicalCache{
+		cache:    cache,
+		ttl:      ttl,
+		staleTTL: staleTTL,
+		now:      time.Now,
+	}
+}
+
+// Get 读取缓存的全部字段(不含过期时间字段)，ok 为 false 表示未命中，fresh 为 false 表示已逻辑过期
+func (c *LogicalCache) Get(key string) (fields map[string]string, fresh, ok bool) {
+	fields, err := c.cache.HashGetAll(key)
+	if err != nil || len(fields) == 0 {
+		return nil, false, false
+	}
+
+	expireAt, err := strconv.ParseInt(fields[logicalExpireField], 10, 64)
+	delete(fields, logicalExpireField)
+	if err != nil || len(fields) == 0 {
+		return nil, false, false
+	}
+	return fields, c.now().UnixMilli() < expireAt, true
+}
+
+// Set 写入缓存，values 为字段、值交替排列，与 HashSet 相同
+func (c *LogicalCache) Set(key string, values []interface{}, options ...redis.Option) error {
+	expireAt := c.now().Add(c.ttl).UnixMilli()
+	return c.cache.HashSet(key, c.ttl+c.staleTTL, redis.HashParams{
+		Options: options,
+		Values:  append(values[:len(values):len(values)], logicalExpireField, expireAt),
+	})
+}
+
+// Expire 标记为逻辑过期，下次读取时返回旧数据并触发后台刷新；用于数据变更后代替删除，避免失效瞬间的并发回源
+func (c *LogicalCache) Expire(key string) error {
+	exists, err := c.cache.Exists(key)
+	if err != nil || !exists {
+		return err
+	}
+	return c.cache.HashSet(key, c.staleTTL, redis.HashParams{
+		Values: []interface{}{logicalExpireField, 0},
+	})
+}
+
+// Refresh 在后台调用 refresh 刷新 key，已有刷新在进行(本进程或其他实例)时直接返回
+func (c *LogicalCache) Refresh(ctx common.Context, key string, refresh RefreshFunc) {
+	if _, loaded := c.refreshing.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	lockKey := "refresh_lock:" + key
+	token, err := c.cache.Lock(lockKey, logicalRefreshLockTTL)
+	if err != nil {
+		c.refreshing.Delete(key)
+		return
+	}
+
+	log := ctx.Logger()
+	go func() {
+		defer c.refreshing.Delete(key)
+		defer c.cache.Unlock(lockKey, token)
+
+		// 请求结束后 ctx 会被回收，刷新使用独立的上下文
+		if err := refresh(common.NewBackgroundContext(log)); err != nil {
+			log.Warn("refresh logically expired cache failed", zap.String("key", key), zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// legacyUserIndexes 旧版本由 uniqueIndex 标签生成的全量唯一索引
+// 这些索引没有排除空字符串和软删除记录，会导致多个未填写邮箱/手机号的用户互相冲突
+var legacyUserIndexes = []string{
+	"idx_users_username",
+	"idx_users_email",
+	"idx_users_phone",
+}
+
+// renamedColumns 改名的列，AutoMigrate 前先改名以保留原有数据，否则 AutoMigrate 会另外新增一个空列
+var renamedColumns = []struct {
+	model    interface{}
+	from, to string
+}{
+	{&model.User{}, "update_at", "updated_at"},
+	{&model.Order{}, "update_at", "updated_at"},
+	{&model.ArchivedOrder{}, "update_at", "updated_at"},
+	{&model.OrderSummary{}, "update_at", "updated_at"},
+	{&model.OrderNote{}, "update_at", "updated_at"},
+	{&model.ArchivedOrderNote{}, "update_at", "updated_at"},
+	{&model.Store{}, "update_at", "updated_at"},
+	{&model.Shipment{}, "update_at", "updated_at"},
+	{&model.Broadcast{}, "update_at", "updated_at"},
+	{&model.Organization{}, "update_at", "updated_at"},
+}
+
+// models 由 AutoMigrate 维护的表
+func models() []interface{} {
+	return []interface{}{
+		&model.User{}, &model.Order{}, &model.OrderNote{}, &model.Tag{}, &model.UserTag{}, &model.Broadcast{}, &model.Notification{},
+		&model.Shipment{}, &model.ShipmentEvent{}, &model.Favorite{}, &model.Store{}, &model.Organization{}, &model.OrganizationMember{},
+		&model.OrganizationInvitation{}, &model.OrderSummary{}, &model.ArchivedOrder{}, &model.ArchivedOrderNote{}, &model.ViewCount{},
+		&model.APIKey{}, &model.Product{}, &model.OrderItem{}, &model.UserSetting{},
+	}
+}
+
+// AutoMigrate 自动迁移数据库表结构
+//
+// 改名的列在 AutoMigrate 之前改名；唯一索引由模型标签声明(uk_*)，AutoMigrate 完成后再清理历史遗留的索引，
+// 保证同一时刻至少有一套唯一约束生效。
+func AutoMigrate(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, c := range renamedColumns {
+		if !migrator.HasTable(c.model) || !migrator.HasColumn(c.model, c.from) || migrator.HasColumn(c.model, c.to) {
+			continue
+		}
+		if err := migrator.RenameColumn(c.model, c.from, c.to); err != nil {
+			return err
+		}
+	}
+
+	if err := db.AutoMigrate(models()...); err != nil {
+		return err
+	}
+
+	for _, name := range legacyUserIndexes {
+		if !migrator.HasIndex(&model.User{}, name) {
+			continue
+		}
+		if err := migrator.DropIndex(&model.User{}, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PendingMigrations 对比模型和数据库，返回 AutoMigrate 尚未完成的变更(缺少的表、列，待改名的列和待清理的旧索引)，为空表示表结构是最新的
+//
+// 只检查表、列和索引是否存在，不比较列类型。
+func PendingMigrations(db *gorm.DB) ([]string, error) {
+	migrator := db.Migrator()
+
+	var pending []string
+	for _, m := range models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, err
+		}
+		table := stmt.Schema.Table
+
+		if !migrator.HasTable(m) {
+			pending = append(pending, "missing table "+table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			if !migrator.HasColumn(m, field.DBName) {
+				pending = append(pending, fmt.Sprintf("missing column %s.%s", table, field.DBName))
+			}
+		}
+		for _, idx := range stmt.Schema.ParseIndexes() {
+			if !migrator.HasIndex(m, idx.Name) {
+				pending = append(pending, fmt.Sprintf("missing index %s.%s", table, idx.Name))
+			}
+		}
+	}
+
+	for _, c := range renamedColumns {
+		if migrator.HasTable(c.model) && migrator.HasColumn(c.model, c.from) {
+			stmt := &gorm.Statement{DB: db}
+			if err := stmt.Parse(c.model); err != nil {
+				return nil, err
+			}
+			pending = append(pending, fmt.Sprintf("rename column %s.%s to %s", stmt.Schema.Table, c.from, c.to))
+		}
+	}
+	for _, name := range legacyUserIndexes {
+		if migrator.HasIndex(&model.User{}, name) {
+			pending = append(pending, "legacy index "+name)
+		}
+	}
+	sort.Strings(pending)
+	return pending, nil
+}
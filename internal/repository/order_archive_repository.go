@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// orderArchiveColumns 订单表和归档表共有的列，合并查询两张表时使用
+const orderArchiveColumns = "id, order_number, created_at, updated_at, deleted_at, user_id, username, total_price, description, status, organization_id, latitude, longitude"
+
+// OrderArchiveRepository 订单归档表(orders_archive、order_notes_archive)
+type OrderArchiveRepository interface {
+	// ArchiveBefore 把一批创建时间早于 before 的订单(含软删除的订单)及其备注移入归档表，返回移动的订单
+	ArchiveBefore(ctx common.Context, before time.Time, limit int) ([]*model.Order, error)
+	// GetByOrderNumber 查询归档的订单(含备注)
+	GetByOrderNumber(ctx common.Context, orderNumber string) (*model.Order, error)
+	// ListWithLive 合并订单表和归档表分页查询(不含备注)，按创建时间倒序；username 为管理员时查询全部订单
+	ListWithLive(ctx common.Context, username string, offset, limit int) ([]*model.Order, int64, error)
+}
+
+type orderArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderArchiveRepository(db *gorm.DB) OrderArchiveRepository {
+	return &orderArchiveRepository{db: db}
+}
+
+func (r *orderArchiveRepository) ArchiveBefore(ctx common.Context, before time.Time, limit int) ([]*model.Order, error) {
+	var orders []*model.Order
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// 多实例同时归档时跳过其他实例已锁定的订单
+		err := tx.Unscoped().Where("created_at < ?", before).Order("id").Limit(limit).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&orders).Error
+		if err != nil || len(orders) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(orders))
+		archived := make([]*model.ArchivedOrder, 0, len(orders))
+		for _, order := range orders {
+			ids = append(ids, order.ID)
+			archived = append(archived, model.NewArchivedOrder(order))
+		}
+
+		var notes []*model.OrderNote
+		if err := tx.Where("order_id IN ?", ids).Find(&notes).Error; err != nil {
+			return err
+		}
+		if len(notes) > 0 {
+			archivedNotes := make([]*model.ArchivedOrderNote, 0, len(notes))
+			for _, note := range notes {
+				archivedNotes = append(archivedNotes, model.NewArchivedOrderNote(note))
+			}
+			if err := tx.Create(&archivedNotes).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("order_id IN ?", ids).Delete(&model.OrderNote{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&archived).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&model.Order{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *orderArchiveRepository) GetByOrderNumber(ctx common.Context, orderNumber string) (*model.Order, error) {
+	db := conn(ctx, r.db)
+
+	var archived model.ArchivedOrder
+	if err := db.Where("order_number = ?", orderNumber).First(&archived).Error; err != nil {
+		return nil, err
+	}
+
+	var notes []*model.ArchivedOrderNote
+	if err := db.Where("order_id = ?", archived.ID).Order("id").Find(&notes).Error; err != nil {
+		return nil, err
+	}
+
+	order := archived.Order()
+	for _, note := range notes {
+		order.Notes = append(order.Notes, note.Note())
+	}
+	return order, nil
+}
+
+func (r *orderArchiveRepository) ListWithLive(ctx common.Context, username string, offset, limit int) ([]*model.Order, int64, error) {
+	db := conn(ctx, r.db)
+
+	live := db.Table(model.Order{}.TableName()).Select(orderArchiveColumns).Where("deleted_at IS NULL")
+	archived := db.Table(model.ArchivedOrder{}.TableName()).Select(orderArchiveColumns).Where("deleted_at IS NULL")
+	if username != common.ADMIN_NAME {
+		live = live.Where("username = ?", username)
+		archived = archived.Where("username = ?", username)
+	}
+	union := db.Raw("(?) UNION ALL (?)", live, archived)
+
+	var total int64
+	if err := db.Table("(?) AS o", union).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 不经过 Order 模型查询，避免在子查询外再拼接软删除条件
+	var orders []*model.Order
+	err := db.Table("(?) AS o", union).Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Scan(&orders).Error
+	return orders, total, err
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"errors"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderItemRepository 订单明细
+//
+// 修改明细的方法在同一个事务中锁定订单、修改明细，并按全部明细重新计算订单总价写回订单表，返回新的总价；
+// 同一订单的明细修改按订单行锁依次执行，总价不会因并发修改而与明细不一致。
+type OrderItemRepository interface {
+	// ListByOrder 订单的全部明细，按加入顺序排列
+	ListByOrder(ctx common.Context, orderID uint) ([]*model.OrderItem, error)
+	// Get 订单的一条明细，明细不存在时返回 gorm.ErrRecordNotFound
+	Get(ctx common.Context, orderID, itemID uint) (*model.OrderItem, error)
+	// LockOrder 锁定订单直到事务结束，需在 Transaction 中调用；
+	// 在同一事务中同时修改明细和库存时先锁定订单，同一订单的修改依次执行，加锁顺序一致不会死锁
+	LockOrder(ctx common.Context, orderID uint) error
+	// CountByOrder 订单的明细数
+	CountByOrder(ctx common.Context, orderID uint) (int64, error)
+	// Add 加入明细，订单中已有该商品时累加数量，单价仍为第一次加入时的单价；item 更新为加入后的明细
+	Add(ctx common.Context, item *model.OrderItem) (total float64, err error)
+	// SetQuantity 修改明细的数量，明细不存在时返回 gorm.ErrRecordNotFound
+	SetQuantity(ctx common.Context, orderID, itemID uint, quantity int) (total float64, err error)
+	// Remove 删除明细，明细不存在时返回 gorm.ErrRecordNotFound
+	Remove(ctx common.Context, orderID, itemID uint) (total float64, err error)
+}
+
+type orderItemRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderItemRepository(db *gorm.DB) OrderItemRepository {
+	return &orderItemRepository{db: db}
+}
+
+func (r *orderItemRepository) ListByOrder(ctx common.Context, orderID uint) ([]*model.OrderItem, error) {
+	var items []*model.OrderItem
+	err := conn(ctx, r.db).Where("order_id = ?", orderID).Order("id").Find(&items).Error
+	return items, err
+}
+
+func (r *orderItemRepository) Get(ctx common.Context, orderID, itemID uint) (*model.OrderItem, error) {
+	var item model.OrderItem
+	if err := conn(ctx, r.db).Where("id = ? AND order_id = ?", itemID, orderID).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *orderItemRepository) LockOrder(ctx common.Context, orderID uint) error {
+	return conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&model.Order{}, orderID).Error
+}
+
+func (r *orderItemRepository) CountByOrder(ctx common.Context, orderID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.OrderItem{}).Where("order_id = ?", orderID).Count(&count).Error
+	return count, err
+}
+
+func (r *orderItemRepository) Add(ctx common.Context, item *model.OrderItem) (float64, error) {
+	return r.reprice(ctx, item.OrderID, func(tx *gorm.DB) error {
+		var existing model.OrderItem
+		err := tx.Where("order_id = ? AND product_id = ?", item.OrderID, item.ProductID).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(item).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		existing.Quantity += item.Quantity
+		if err := tx.Model(&existing).Update("quantity", existing.Quantity).Error; err != nil {
+			return err
+		}
+		*item = existing
+		return nil
+	})
+}
+
+func (r *orderItemRepository) SetQuantity(ctx common.Context, orderID, itemID uint, quantity int) (float64, error) {
+	return r.reprice(ctx, orderID, func(tx *gorm.DB) error {
+		result := tx.Model(&model.OrderItem{}).Where("id = ? AND order_id = ?", itemID, orderID).Update("quantity", quantity)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *orderItemRepository) Remove(ctx common.Context, orderID, itemID uint) (float64, error) {
+	return r.reprice(ctx, orderID, func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND order_id = ?", itemID, orderID).Delete(&model.OrderItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// reprice 锁定订单后执行 change 修改明细，再按明细重新计算并写入订单总价
+func (r *orderItemRepository) reprice(ctx common.Context, orderID uint, change func(tx *gorm.DB) error) (float64, error) {
+	var total float64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&model.Order{}, orderID).Error
+		if err != nil {
+			return err
+		}
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.OrderItem{}).Where("order_id = ?", orderID).
+			Select("COALESCE(SUM(unit_price * quantity), 0)").Scan(&total).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.Order{}).Where("id = ?", orderID).Update("total_price", total).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
@@ -1,23 +1,105 @@
 package repository
 
 import (
+	"fmt"
+	"strings"
+
 	"gin-app-start/internal/common"
 	"gin-app-start/internal/model"
+	"gin-app-start/pkg/database"
+	"gin-app-start/pkg/fieldcrypt"
+	"gin-app-start/pkg/geo"
+	"gin-app-start/pkg/queryfilter"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type OrderRepository interface {
-	Create(ctx common.Context, order *model.Order) error
+	// Create 保存订单，订单号与已有订单冲突时调用 renumber 生成新订单号后重试，
+	// 最多尝试 orderNumberAttempts 次，仍然冲突时返回最后一次的唯一约束错误
+	Create(ctx common.Context, order *model.Order, renumber func() string) error
 	GetByID(ctx common.Context, id uint) (*model.Order, error)
 	GetOrderByOrderNumber(ctx common.Context, orderNumber string) (*model.Order, error)
+	GetByOrderNumbers(ctx common.Context, orderNumbers []string) ([]*model.Order, error)
 	DeleteOrderByOrderNumber(ctx common.Context, orderNumber string) error
 	Update(ctx common.Context, user *model.Order) error
+	UpdateFields(ctx common.Context, id uint, fields map[string]interface{}) error
 	Delete(ctx common.Context, id uint) error
-	List(ctx common.Context, username string, offset, limit int) ([]*model.Order, int64, error)
+	List(ctx common.Context, username string, filter queryfilter.Query, offset, limit int) ([]*model.Order, int64, error)
+	ListAfter(ctx common.Context, username string, filter queryfilter.Query, afterID uint, limit int) ([]*model.Order, error)
+	ListByOrganization(ctx common.Context, orgID uint, offset, limit int) ([]*model.Order, int64, error)
+	ListWithUsers(ctx common.Context, search OrderSearch, offset, limit int) ([]*OrderWithUser, int64, error)
 	Count(ctx common.Context) (int64, error)
+	CreateNote(ctx common.Context, note *model.OrderNote) error
+	UserOrderStats(ctx common.Context) ([]*UserOrderStat, error)
+
+	GetByIDs(ctx common.Context, ids []uint) ([]*model.Order, error)
+	ListLocatedWithin(ctx common.Context, box geo.Box) ([]*model.Order, error)
+	EachLocated(ctx common.Context, batchSize int, fn func(orders []*model.Order) error) error
+	Each(ctx common.Context, batchSize int, fn func(orders []*model.Order, progress BatchProgress) error) error
+	EachByUsername(ctx common.Context, username string, batchSize int, fn func(orders []*model.Order) error) error
+
+	// Transaction 在同一个数据库事务中执行 fn，用于同时写入订单、明细和库存，见 Transactor
+	Transaction(ctx common.Context, fn func(ctx common.Context) error) error
 }
 
+// UserOrderStat 单个用户的订单数和消费总额
+type UserOrderStat struct {
+	UserID uint
+	Orders int64
+	Spent  float64
+}
+
+// OrderSearch 管理端订单搜索条件，为空的字段不参与过滤
+type OrderSearch struct {
+	Username string
+	Email    string // 下单用户的邮箱
+	Phone    string // 下单用户的手机号
+	Filter   queryfilter.Query
+}
+
+// OrderQuery 订单列表可用的过滤参数和排序字段，默认按创建时间倒序
+var OrderQuery = &queryfilter.Spec{
+	Filters: []queryfilter.Filter{
+		{Param: "status", Column: "status", Op: queryfilter.Eq, Parse: parseOrderStatus},
+		{Param: "created_from", Column: "created_at", Op: queryfilter.Gte, Parse: queryfilter.Time},
+		{Param: "created_to", Column: "created_at", Op: queryfilter.Lt, Parse: queryfilter.Time},
+		{Param: "min_price", Column: "total_price", Op: queryfilter.Gte, Parse: queryfilter.Float},
+		{Param: "max_price", Column: "total_price", Op: queryfilter.Lte, Parse: queryfilter.Float},
+	},
+	Sorts: map[string]string{
+		"id":          "id",
+		"created_at":  "created_at",
+		"total_price": "total_price",
+		"status":      "status",
+	},
+	DefaultSort: []queryfilter.Sort{{Column: "created_at", Desc: true}},
+	Tiebreak:    "id",
+}
+
+// parseOrderStatus 按名称(或数字)解析订单状态过滤参数
+func parseOrderStatus(s string) (interface{}, error) {
+	status, err := model.ParseOrderStatus(s)
+	if err != nil {
+		return nil, err
+	}
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid order status %q", s)
+	}
+	return int8(status), nil
+}
+
+// OrderWithUser 订单及下单用户的联系方式，用户已不存在时为空
+type OrderWithUser struct {
+	model.Order
+	UserEmail string `gorm:"column:user_email;serializer:encrypt"`
+	UserPhone string `gorm:"column:user_phone;serializer:encrypt"`
+}
+
+// orderNumberAttempts 订单号冲突时最多尝试插入的次数
+const orderNumberAttempts = 5
+
 type orderRepository struct {
 	*BaseRepository[model.Order]
 }
@@ -28,27 +110,202 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 	}
 }
 
+// Create 以数据库唯一约束判断订单号是否重复，不再先查询订单号是否存在：
+// 先查后插在并发下仍会冲突，冲突时直接换号重试
+func (r *orderRepository) Create(ctx common.Context, order *model.Order, renumber func() string) error {
+	var err error
+	for attempt := 1; attempt <= orderNumberAttempts; attempt++ {
+		if attempt > 1 {
+			order.OrderNumber = renumber()
+		}
+		// 事务中的唯一约束冲突会使整个事务失效，每次插入放在单独的(嵌套)事务中，冲突时只回滚这一次插入
+		err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+			return tx.Create(order).Error
+		})
+		if !isOrderNumberConflict(err) {
+			return err
+		}
+		ctx.Logger().Warn("order number conflict, regenerating",
+			zap.String("order_number", order.OrderNumber), zap.Int("attempt", attempt))
+	}
+	return err
+}
+
+// isOrderNumberConflict 判断 err 是否为订单号唯一约束冲突
+func isOrderNumberConflict(err error) bool {
+	constraint, ok := database.UniqueViolation(err)
+	return ok && strings.Contains(constraint, "order_number")
+}
+
+// preloadNotes 订单详情预加载备注，按创建顺序排列
+func preloadNotes(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
 func (r *orderRepository) GetOrderByOrderNumber(ctx common.Context, orderNumber string) (*model.Order, error) {
 	var order model.Order
-	err := r.db.WithContext(ctx.RequestContext()).Where("order_number = ?", orderNumber).First(&order).Error
+	err := conn(ctx, r.db).Preload("Notes", preloadNotes).Where("order_number = ?", orderNumber).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetByOrderNumbers 按订单号批量查询订单(含备注)，不存在的订单号会被忽略，返回顺序不保证与 orderNumbers 一致
+func (r *orderRepository) GetByOrderNumbers(ctx common.Context, orderNumbers []string) ([]*model.Order, error) {
+	if len(orderNumbers) == 0 {
+		return nil, nil
+	}
+
+	var orders []*model.Order
+	err := conn(ctx, r.db).Preload("Notes", preloadNotes).Where("order_number IN ?", orderNumbers).Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) GetByID(ctx common.Context, id uint) (*model.Order, error) {
+	var order model.Order
+	err := conn(ctx, r.db).Preload("Notes", preloadNotes).First(&order, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &order, nil
 }
 
+func (r *orderRepository) CreateNote(ctx common.Context, note *model.OrderNote) error {
+	return conn(ctx, r.db).Create(note).Error
+}
+
+// GetByIDs 批量查询订单(不含备注)，不存在的ID会被忽略，返回顺序不保证与 ids 一致
+func (r *orderRepository) GetByIDs(ctx common.Context, ids []uint) ([]*model.Order, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var orders []*model.Order
+	err := conn(ctx, r.db).Where("id IN ?", ids).Find(&orders).Error
+	return orders, err
+}
+
+// ListLocatedWithin 查询收货地坐标在矩形范围内的订单
+func (r *orderRepository) ListLocatedWithin(ctx common.Context, box geo.Box) ([]*model.Order, error) {
+	var orders []*model.Order
+	err := conn(ctx, r.db).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", box.MinLat, box.MaxLat, box.MinLng, box.MaxLng).
+		Find(&orders).Error
+	return orders, err
+}
+
+// EachLocated 按ID顺序分批遍历带收货地坐标的订单
+func (r *orderRepository) EachLocated(ctx common.Context, batchSize int, fn func(orders []*model.Order) error) error {
+	return r.FindInBatches(ctx, batchSize, func(batch []*model.Order, _ BatchProgress) error {
+		return fn(batch)
+	}, func(db *gorm.DB) *gorm.DB {
+		return db.Where("latitude IS NOT NULL AND longitude IS NOT NULL")
+	})
+}
+
+// Each 按ID顺序分批遍历全部未删除的订单(不含备注)
+func (r *orderRepository) Each(ctx common.Context, batchSize int, fn func(orders []*model.Order, progress BatchProgress) error) error {
+	return r.FindInBatches(ctx, batchSize, fn)
+}
+
+// EachByUsername 按ID顺序分批遍历用户的订单(不含备注)，username 为管理员时遍历全部订单
+func (r *orderRepository) EachByUsername(ctx common.Context, username string, batchSize int, fn func(orders []*model.Order) error) error {
+	var scopes []Scope
+	if username != common.ADMIN_NAME {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("username = ?", username)
+		})
+	}
+	return r.FindInBatches(ctx, batchSize, func(batch []*model.Order, _ BatchProgress) error {
+		return fn(batch)
+	}, scopes...)
+}
+
+// UserOrderStats 按用户汇总未删除订单(含已归档订单)的数量和金额，未记录 user_id 的旧订单不计入
+func (r *orderRepository) UserOrderStats(ctx common.Context) ([]*UserOrderStat, error) {
+	db := conn(ctx, r.db)
+	live := db.Table(model.Order{}.TableName()).Select("user_id, total_price").Where("deleted_at IS NULL AND user_id <> 0")
+	archived := db.Table(model.ArchivedOrder{}.TableName()).Select("user_id, total_price").Where("deleted_at IS NULL AND user_id <> 0")
+
+	var res []*UserOrderStat
+	err := db.Table("((?) UNION ALL (?)) AS o", live, archived).
+		Select("user_id, COUNT(*) AS orders, COALESCE(SUM(total_price), 0) AS spent").
+		Group("user_id").
+		Scan(&res).Error
+	return res, err
+}
+
 func (r *orderRepository) DeleteOrderByOrderNumber(ctx common.Context, orderNumber string) error {
-	return r.db.WithContext(ctx.RequestContext()).Where("order_number = ?", orderNumber).Delete(&model.Order{}).Error
+	return conn(ctx, r.db).Where("order_number = ?", orderNumber).Delete(&model.Order{}).Error
 }
 
-func (r *orderRepository) List(ctx common.Context, username string, offset, limit int) ([]*model.Order, int64, error) {
+// List 分页查询用户符合过滤条件的订单，按 filter 指定的排序(默认按创建时间倒序)，total 为符合条件的订单总数；username 为管理员时查询全部订单
+func (r *orderRepository) List(ctx common.Context, username string, filter queryfilter.Query, offset, limit int) ([]*model.Order, int64, error) {
 	var orders []*model.Order
-	var err error
-	if username == common.ADMIN_NAME {
-		err = r.db.WithContext(ctx.RequestContext()).Offset(offset).Limit(limit).Find(&orders).Error
-	} else {
-		err = r.db.WithContext(ctx.RequestContext()).Offset(offset).Limit(limit).Where("username = ?", username).Find(&orders).Error
+	var total int64
+
+	db := conn(ctx, r.db).Model(&model.Order{}).Scopes(filter.Where("orders"))
+	if username != common.ADMIN_NAME {
+		db = db.Where("username = ?", username)
+	}
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
-	total := int64(len(orders))
+
+	err := db.Scopes(OrderQuery.Order(filter, "orders")).Offset(offset).Limit(limit).Find(&orders).Error
+	return orders, total, err
+}
+
+// ListAfter 游标分页查询用户符合过滤条件的订单，按ID升序返回ID大于 afterID 的至多 limit 条，username 为管理员时查询全部订单
+func (r *orderRepository) ListAfter(ctx common.Context, username string, filter queryfilter.Query, afterID uint, limit int) ([]*model.Order, error) {
+	scopes := []Scope{filter.Where("orders")}
+	if username != common.ADMIN_NAME {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where("username = ?", username)
+		})
+	}
+	return r.BaseRepository.ListAfter(ctx, afterID, limit, scopes...)
+}
+
+// ListWithUsers 关联用户表分页查询订单，按 search.Filter 指定的排序(默认按创建时间倒序)，管理端按用户的邮箱、手机号查找订单时使用
+//
+// 邮箱、手机号加密存储，只支持精确匹配，见 fieldcrypt.Lookup
+func (r *orderRepository) ListWithUsers(ctx common.Context, search OrderSearch, offset, limit int) ([]*OrderWithUser, int64, error) {
+	var orders []*OrderWithUser
+	var total int64
+
+	db := conn(ctx, r.db).Model(&model.Order{}).
+		Joins("LEFT JOIN app_schema.users AS u ON u.id = orders.user_id")
+	if search.Username != "" {
+		db = db.Where("orders.username = ?", search.Username)
+	}
+	if search.Email != "" {
+		db = db.Where("u.email IN ?", fieldcrypt.Lookup(search.Email))
+	}
+	if search.Phone != "" {
+		db = db.Where("u.phone IN ?", fieldcrypt.Lookup(search.Phone))
+	}
+	db = db.Scopes(search.Filter.Where("orders"))
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Select("orders.*, u.email AS user_email, u.phone AS user_phone").
+		Scopes(OrderQuery.Order(search.Filter, "orders")).Offset(offset).Limit(limit).Find(&orders).Error
+	return orders, total, err
+}
+
+// ListByOrganization 组织订单，按创建时间倒序
+func (r *orderRepository) ListByOrganization(ctx common.Context, orgID uint, offset, limit int) ([]*model.Order, int64, error) {
+	var orders []*model.Order
+	var total int64
+
+	db := conn(ctx, r.db).Model(&model.Order{}).Where("organization_id = ?", orgID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&orders).Error
 	return orders, total, err
 }
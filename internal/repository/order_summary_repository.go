@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderSummaryRepository 订单列表读模型
+type OrderSummaryRepository interface {
+	// Upsert 写入或覆盖读模型记录，已有记录的订单更新时间比写入的新时保留已有记录
+	Upsert(ctx common.Context, summaries []*model.OrderSummary) error
+	Delete(ctx common.Context, orderIDs []uint) error
+	// DeleteOrphans 删除订单已不存在(含软删除)的记录，返回删除数
+	DeleteOrphans(ctx common.Context) (int64, error)
+	// List 按创建时间倒序分页查询全部订单
+	List(ctx common.Context, offset, limit int) ([]*model.OrderSummary, int64, error)
+	Count(ctx common.Context) (int64, error)
+}
+
+type orderSummaryRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderSummaryRepository(db *gorm.DB) OrderSummaryRepository {
+	return &orderSummaryRepository{db: db}
+}
+
+func (r *orderSummaryRepository) Upsert(ctx common.Context, summaries []*model.OrderSummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+	// 重建和后台写入(可能在不同实例)并发时，先读到的旧快照不能覆盖较新的投影
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "order_id"}},
+			UpdateAll: true,
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "order_summaries.updated_at <= excluded.updated_at"}}},
+		}).
+		Create(&summaries).Error
+}
+
+func (r *orderSummaryRepository) Delete(ctx common.Context, orderIDs []uint) error {
+	if len(orderIDs) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Where("order_id IN ?", orderIDs).Delete(&model.OrderSummary{}).Error
+}
+
+func (r *orderSummaryRepository) DeleteOrphans(ctx common.Context) (int64, error) {
+	result := conn(ctx, r.db).
+		Where("NOT EXISTS (SELECT 1 FROM app_schema.orders o WHERE o.id = order_summaries.order_id AND o.deleted_at IS NULL)").
+		Delete(&model.OrderSummary{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *orderSummaryRepository) List(ctx common.Context, offset, limit int) ([]*model.OrderSummary, int64, error) {
+	var summaries []*model.OrderSummary
+	var total int64
+
+	db := conn(ctx, r.db).Model(&model.OrderSummary{})
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("created_at DESC, order_id DESC").Offset(offset).Limit(limit).Find(&summaries).Error
+	return summaries, total, err
+}
+
+func (r *orderSummaryRepository) Count(ctx common.Context) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&model.OrderSummary{}).Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationRepository interface {
+	Create(ctx common.Context, org *model.Organization, owner *model.OrganizationMember) error
+	GetByID(ctx common.Context, id uint) (*model.Organization, error)
+	ListByUser(ctx common.Context, userID uint) ([]*model.Organization, error)
+
+	GetMember(ctx common.Context, orgID, userID uint) (*model.OrganizationMember, error)
+	ListMembers(ctx common.Context, orgID uint) ([]*model.OrganizationMember, error)
+	CountOwners(ctx common.Context, orgID uint) (int64, error)
+	RemoveMember(ctx common.Context, orgID, userID uint) error
+
+	CreateInvitation(ctx common.Context, invitation *model.OrganizationInvitation) error
+	GetInvitationByToken(ctx common.Context, token string) (*model.OrganizationInvitation, error)
+	AcceptInvitation(ctx common.Context, invitation *model.OrganizationInvitation, member *model.OrganizationMember) (bool, error)
+}
+
+type organizationRepository struct {
+	*BaseRepository[model.Organization]
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{
+		BaseRepository: NewBaseRepository[model.Organization](db),
+	}
+}
+
+// Create 创建组织并把创建者加入为所有者
+func (r *organizationRepository) Create(ctx common.Context, org *model.Organization, owner *model.OrganizationMember) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+
+		owner.OrganizationID = org.ID
+		return tx.Create(owner).Error
+	})
+}
+
+// ListByUser 用户所在的组织，按加入顺序排列
+func (r *organizationRepository) ListByUser(ctx common.Context, userID uint) ([]*model.Organization, error) {
+	var orgs []*model.Organization
+	err := conn(ctx, r.db).
+		Joins("JOIN app_schema.organization_members m ON m.organization_id = organizations.id").
+		Where("m.user_id = ?", userID).
+		Order("m.created_at, organizations.id").
+		Find(&orgs).Error
+	return orgs, err
+}
+
+func (r *organizationRepository) GetMember(ctx common.Context, orgID, userID uint) (*model.OrganizationMember, error) {
+	var member model.OrganizationMember
+	err := conn(ctx, r.db).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *organizationRepository) ListMembers(ctx common.Context, orgID uint) ([]*model.OrganizationMember, error) {
+	var members []*model.OrganizationMember
+	err := conn(ctx, r.db).
+		Where("organization_id = ?", orgID).
+		Order("created_at, user_id").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *organizationRepository) CountOwners(ctx common.Context, orgID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", orgID, model.OrgRoleOwner).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *organizationRepository) RemoveMember(ctx common.Context, orgID, userID uint) error {
+	return conn(ctx, r.db).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&model.OrganizationMember{}).Error
+}
+
+func (r *organizationRepository) CreateInvitation(ctx common.Context, invitation *model.OrganizationInvitation) error {
+	return conn(ctx, r.db).Create(invitation).Error
+}
+
+func (r *organizationRepository) GetInvitationByToken(ctx common.Context, token string) (*model.OrganizationInvitation, error) {
+	var invitation model.OrganizationInvitation
+	if err := conn(ctx, r.db).Where("token = ?", token).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// AcceptInvitation 在同一事务中把邀请标记为已接受并加入成员
+// 邀请已被接受(并发请求)时返回 false；用户已是成员时保留原角色，邀请同样标记为已接受
+func (r *organizationRepository) AcceptInvitation(ctx common.Context, invitation *model.OrganizationInvitation, member *model.OrganizationMember) (bool, error) {
+	accepted := false
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&model.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(member).Error; err != nil {
+			return err
+		}
+		invitation.AcceptedAt = &now
+		accepted = true
+		return nil
+	})
+	return accepted, err
+}
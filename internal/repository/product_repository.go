@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrInsufficientStock 扣减后商品库存将小于 0
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+type ProductRepository interface {
+	Create(ctx common.Context, product *model.Product) error
+	GetBySKU(ctx common.Context, sku string) (*model.Product, error)
+	// AdjustStock 按 delta 增减库存，不限库存(stock 为空)的商品不受影响；
+	// 库存不足时返回 ErrInsufficientStock，商品不存在时返回 gorm.ErrRecordNotFound
+	AdjustStock(ctx common.Context, sku string, delta int) error
+	UpdateFields(ctx common.Context, id uint, fields map[string]interface{}) error
+	Delete(ctx common.Context, id uint) error
+	List(ctx common.Context, offset, limit int) ([]*model.Product, int64, error)
+}
+
+type productRepository struct {
+	*BaseRepository[model.Product]
+}
+
+func NewProductRepository(db *gorm.DB) ProductRepository {
+	return &productRepository{
+		BaseRepository: NewBaseRepository[model.Product](db),
+	}
+}
+
+func (r *productRepository) GetBySKU(ctx common.Context, sku string) (*model.Product, error) {
+	var product model.Product
+	if err := conn(ctx, r.db).Where("sku = ?", sku).First(&product).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// AdjustStock 以条件更新扣减库存，不先查询再写回，并发扣减时不会超卖
+func (r *productRepository) AdjustStock(ctx common.Context, sku string, delta int) error {
+	result := conn(ctx, r.db).Model(&model.Product{}).
+		Where("sku = ? AND (stock IS NULL OR stock + ? >= 0)", sku, delta).
+		Update("stock", gorm.Expr("stock + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// 没有更新到商品时区分商品不存在和库存不足
+	var count int64
+	if err := conn(ctx, r.db).Model(&model.Product{}).Where("sku = ?", sku).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrInsufficientStock
+}
+
+// List 分页查询商品，按ID升序
+func (r *productRepository) List(ctx common.Context, offset, limit int) ([]*model.Product, int64, error) {
+	var products []*model.Product
+	var total int64
+
+	db := conn(ctx, r.db).Model(&model.Product{})
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("id").Offset(offset).Limit(limit).Find(&products).Error
+	return products, total, err
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShipmentRepository interface {
+	Create(ctx common.Context, shipment *model.Shipment) error
+	GetByID(ctx common.Context, id uint) (*model.Shipment, error)
+	GetByTracking(ctx common.Context, carrier, trackingNumber string) (*model.Shipment, error)
+	ListByOrder(ctx common.Context, orderID uint) ([]*model.Shipment, error)
+
+	AddEvents(ctx common.Context, shipmentID uint, events []*model.ShipmentEvent) (int64, error)
+	ClaimPollable(ctx common.Context, carriers []string, polledBefore time.Time, limit int) ([]*model.Shipment, error)
+}
+
+type shipmentRepository struct {
+	*BaseRepository[model.Shipment]
+}
+
+func NewShipmentRepository(db *gorm.DB) ShipmentRepository {
+	return &shipmentRepository{
+		BaseRepository: NewBaseRepository[model.Shipment](db),
+	}
+}
+
+// preloadEvents 物流事件按发生时间排列
+func preloadEvents(db *gorm.DB) *gorm.DB {
+	return db.Order("occurred_at, id")
+}
+
+func (r *shipmentRepository) GetByID(ctx common.Context, id uint) (*model.Shipment, error) {
+	var shipment model.Shipment
+	err := conn(ctx, r.db).Preload("Events", preloadEvents).First(&shipment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+func (r *shipmentRepository) GetByTracking(ctx common.Context, carrier, trackingNumber string) (*model.Shipment, error) {
+	var shipment model.Shipment
+	err := conn(ctx, r.db).
+		Where("carrier = ? AND tracking_number = ?", carrier, trackingNumber).
+		First(&shipment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shipment, nil
+}
+
+func (r *shipmentRepository) ListByOrder(ctx common.Context, orderID uint) ([]*model.Shipment, error) {
+	var shipments []*model.Shipment
+	err := conn(ctx, r.db).Preload("Events", preloadEvents).
+		Where("order_id = ?", orderID).Order("id").Find(&shipments).Error
+	return shipments, err
+}
+
+// AddEvents 写入物流事件并把包裹状态更新为最新事件的状态，返回新写入的事件数
+//
+// 已记录过的事件(同一状态、同一发生时间)跳过，回调重试和轮询重复拉取不会产生重复事件；
+// 只有比当前 last_event_at 更新的事件才会改变包裹状态，乱序到达的旧事件只补充记录。
+func (r *shipmentRepository) AddEvents(ctx common.Context, shipmentID uint, events []*model.ShipmentEvent) (int64, error) {
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	var inserted int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, event := range events {
+			event.ShipmentID = shipmentID
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&events)
+		if result.Error != nil {
+			return result.Error
+		}
+		inserted = result.RowsAffected
+
+		var latest model.ShipmentEvent
+		err := tx.Where("shipment_id = ?", shipmentID).Order("occurred_at DESC, id DESC").First(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Shipment{}).
+			Where("id = ? AND (last_event_at IS NULL OR last_event_at <= ?)", shipmentID, latest.OccurredAt).
+			Updates(map[string]interface{}{
+				"status":        latest.Status,
+				"last_event_at": latest.OccurredAt,
+				"updated_at":    time.Now(),
+			}).Error
+	})
+	return inserted, err
+}
+
+// ClaimPollable 领取一批需要轮询的包裹，并把 polled_at 更新为当前时间
+//
+// 可领取的包裹: 承运商在 carriers 中、未到达终态、且从未轮询或上次轮询早于 polledBefore。
+// 使用 FOR UPDATE SKIP LOCKED，多个实例同时轮询时同一个包裹只会被一个实例领取。
+func (r *shipmentRepository) ClaimPollable(ctx common.Context, carriers []string, polledBefore time.Time, limit int) ([]*model.Shipment, error) {
+	if len(carriers) == 0 {
+		return nil, nil
+	}
+
+	sub := r.db.Model(&model.Shipment{}).Select("id").
+		Where("carrier IN ? AND status NOT IN ? AND (polled_at IS NULL OR polled_at < ?)",
+			carriers, model.ShipmentFinalStatuses, polledBefore).
+		Order("polled_at NULLS FIRST").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var claimed []*model.Shipment
+	err := conn(ctx, r.db).Model(&claimed).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", sub).
+		Update("polled_at", time.Now()).Error
+	return claimed, err
+}
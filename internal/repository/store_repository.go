@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+	"gin-app-start/pkg/geo"
+
+	"gorm.io/gorm"
+)
+
+type StoreRepository interface {
+	Create(ctx common.Context, store *model.Store) error
+	GetByID(ctx common.Context, id uint) (*model.Store, error)
+	GetByIDs(ctx common.Context, ids []uint) ([]*model.Store, error)
+	Delete(ctx common.Context, id uint) error
+	ListWithin(ctx common.Context, box geo.Box) ([]*model.Store, error)
+	Each(ctx common.Context, batchSize int, fn func(stores []*model.Store) error) error
+}
+
+type storeRepository struct {
+	*BaseRepository[model.Store]
+}
+
+func NewStoreRepository(db *gorm.DB) StoreRepository {
+	return &storeRepository{
+		BaseRepository: NewBaseRepository[model.Store](db),
+	}
+}
+
+// GetByIDs 批量查询门店，不存在的ID会被忽略，返回顺序不保证与 ids 一致
+func (r *storeRepository) GetByIDs(ctx common.Context, ids []uint) ([]*model.Store, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var stores []*model.Store
+	err := conn(ctx, r.db).Where("id IN ?", ids).Find(&stores).Error
+	return stores, err
+}
+
+// ListWithin 查询坐标在矩形范围内的门店
+func (r *storeRepository) ListWithin(ctx common.Context, box geo.Box) ([]*model.Store, error) {
+	var stores []*model.Store
+	err := conn(ctx, r.db).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", box.MinLat, box.MaxLat, box.MinLng, box.MaxLng).
+		Find(&stores).Error
+	return stores, err
+}
+
+// Each 按ID顺序分批遍历所有门店
+func (r *storeRepository) Each(ctx common.Context, batchSize int, fn func(stores []*model.Store) error) error {
+	return r.FindInBatches(ctx, batchSize, func(batch []*model.Store, _ BatchProgress) error {
+		return fn(batch)
+	})
+}
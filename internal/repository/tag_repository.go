@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	GetOrCreate(ctx common.Context, names []string) ([]*model.Tag, error)
+	GetByNames(ctx common.Context, names []string) ([]*model.Tag, error)
+	List(ctx common.Context) ([]*TagCount, error)
+
+	AddUserTags(ctx common.Context, userID uint, tagIDs []uint) error
+	RemoveUserTag(ctx common.Context, userID, tagID uint) (bool, error)
+	ListUserTags(ctx common.Context, userID uint) ([]*model.Tag, error)
+
+	ListUsersByTags(ctx common.Context, tagIDs []uint, matchAll bool, offset, limit int) ([]*model.User, int64, error)
+	ListUsersByTagsAfter(ctx common.Context, tagIDs []uint, matchAll bool, afterID uint, limit int) ([]*model.User, error)
+	CountUsersByTags(ctx common.Context, tagIDs []uint, matchAll bool) (int64, error)
+	EachUserByTags(ctx common.Context, tagIDs []uint, matchAll bool, batchSize int, fn func(users []*model.User) error) error
+}
+
+// TagCount 标签及打了该标签的用户数
+type TagCount struct {
+	model.Tag
+	Users int64
+}
+
+type tagRepository struct {
+	*BaseRepository[model.Tag]
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{
+		BaseRepository: NewBaseRepository[model.Tag](db),
+	}
+}
+
+// GetOrCreate 返回 names 对应的标签，不存在的标签自动创建
+func (r *tagRepository) GetOrCreate(ctx common.Context, names []string) ([]*model.Tag, error) {
+	tags := make([]*model.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, &model.Tag{Name: name})
+	}
+
+	// 并发创建同名标签时以唯一索引为准，冲突的行直接跳过
+	err := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByNames(ctx, names)
+}
+
+func (r *tagRepository) GetByNames(ctx common.Context, names []string) ([]*model.Tag, error) {
+	var tags []*model.Tag
+	err := conn(ctx, r.db).Where("name IN ?", names).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+// List 所有标签及各标签下的用户数(不含已删除用户)
+func (r *tagRepository) List(ctx common.Context) ([]*TagCount, error) {
+	var res []*TagCount
+	err := conn(ctx, r.db).Model(&model.Tag{}).
+		Select("app_schema.tags.*, COUNT(app_schema.users.id) AS users").
+		Joins("LEFT JOIN app_schema.user_tags ON app_schema.user_tags.tag_id = app_schema.tags.id").
+		Joins("LEFT JOIN app_schema.users ON app_schema.users.id = app_schema.user_tags.user_id AND app_schema.users.deleted_at IS NULL").
+		Group("app_schema.tags.id").
+		Order("app_schema.tags.name").
+		Scan(&res).Error
+	return res, err
+}
+
+// AddUserTags 给用户打标签，已有的标签跳过
+func (r *tagRepository) AddUserTags(ctx common.Context, userID uint, tagIDs []uint) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	rows := make([]*model.UserTag, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		rows = append(rows, &model.UserTag{UserID: userID, TagID: tagID})
+	}
+
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rows).Error
+}
+
+// RemoveUserTag 移除用户的标签，用户没有该标签时返回 false
+func (r *tagRepository) RemoveUserTag(ctx common.Context, userID, tagID uint) (bool, error) {
+	result := conn(ctx, r.db).
+		Where("user_id = ? AND tag_id = ?", userID, tagID).
+		Delete(&model.UserTag{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *tagRepository) ListUserTags(ctx common.Context, userID uint) ([]*model.Tag, error) {
+	var tags []*model.Tag
+	err := conn(ctx, r.db).
+		Joins("JOIN app_schema.user_tags ON app_schema.user_tags.tag_id = app_schema.tags.id").
+		Where("app_schema.user_tags.user_id = ?", userID).
+		Order("app_schema.tags.name").
+		Find(&tags).Error
+	return tags, err
+}
+
+// usersByTags 打了 tagIDs 中任一标签(matchAll 为 true 时为全部标签)的用户
+func (r *tagRepository) usersByTags(ctx common.Context, tagIDs []uint, matchAll bool) *gorm.DB {
+	sub := r.db.Model(&model.UserTag{}).Select("user_id").Where("tag_id IN ?", tagIDs)
+	if matchAll {
+		sub = sub.Group("user_id").Having("COUNT(*) = ?", len(tagIDs))
+	}
+
+	return conn(ctx, r.db).Model(&model.User{}).Where("id IN (?)", sub)
+}
+
+func (r *tagRepository) ListUsersByTags(ctx common.Context, tagIDs []uint, matchAll bool, offset, limit int) ([]*model.User, int64, error) {
+	var users []*model.User
+	var total int64
+
+	if err := r.usersByTags(ctx, tagIDs, matchAll).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.usersByTags(ctx, tagIDs, matchAll).Order("id").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+// ListUsersByTagsAfter 按主键顺序返回 ID 大于 afterID 的用户，用于可中断、可续传的分批处理
+func (r *tagRepository) ListUsersByTagsAfter(ctx common.Context, tagIDs []uint, matchAll bool, afterID uint, limit int) ([]*model.User, error) {
+	var users []*model.User
+	err := r.usersByTags(ctx, tagIDs, matchAll).Where("id > ?", afterID).Order("id").Limit(limit).Find(&users).Error
+	return users, err
+}
+
+func (r *tagRepository) CountUsersByTags(ctx common.Context, tagIDs []uint, matchAll bool) (int64, error) {
+	var total int64
+	err := r.usersByTags(ctx, tagIDs, matchAll).Count(&total).Error
+	return total, err
+}
+
+// EachUserByTags 按主键顺序分批读取用户，用于导出大量用户时避免一次性加载到内存
+func (r *tagRepository) EachUserByTags(ctx common.Context, tagIDs []uint, matchAll bool, batchSize int, fn func(users []*model.User) error) error {
+	var batch []*model.User
+	return r.usersByTags(ctx, tagIDs, matchAll).FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"gin-app-start/internal/common"
+
+	"gorm.io/gorm"
+)
+
+// Transactor 在同一个数据库事务中执行多次仓储调用
+type Transactor interface {
+	// Transaction 开启事务执行 fn，fn 返回错误或 panic 时回滚，否则提交
+	//
+	// fn 中需使用传入的 ctx 调用仓储方法，这些调用都在该事务中执行；使用外层 ctx 的调用不在事务中。
+	// 在事务中再次调用 Transaction 时以保存点(SAVEPOINT)嵌套，内层出错只回滚到保存点。
+	// 缓存、消息等数据库以外的副作用应在 Transaction 返回后再执行，事务回滚时它们无法撤销。
+	Transaction(ctx common.Context, fn func(ctx common.Context) error) error
+}
+
+// txContext 携带数据库事务的 Context，仓储方法通过 conn 取得事务连接
+type txContext struct {
+	common.Context
+	tx *gorm.DB
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) Transaction(ctx common.Context, fn func(ctx common.Context) error) error {
+	return transaction(ctx, t.db, fn)
+}
+
+func transaction(ctx common.Context, db *gorm.DB, fn func(ctx common.Context) error) error {
+	return conn(ctx, db).Transaction(func(tx *gorm.DB) error {
+		return fn(&txContext{Context: ctx, tx: tx})
+	})
+}
+
+// conn 仓储方法使用的数据库连接，ctx 处于 Transaction 中时返回事务连接
+func conn(ctx common.Context, db *gorm.DB) *gorm.DB {
+	if tc, ok := ctx.(*txContext); ok {
+		return tc.tx
+	}
+	return db.WithContext(ctx.RequestContext())
+}
+
+// InTransaction ctx 是否处于 Transaction 开启的事务中
+func InTransaction(ctx common.Context) bool {
+	_, ok := ctx.(*txContext)
+	return ok
+}
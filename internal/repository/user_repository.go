@@ -1,36 +1,102 @@
 package repository
 
 import (
+	"fmt"
+
 	"gin-app-start/internal/common"
 	"gin-app-start/internal/model"
+	"gin-app-start/internal/redis"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/fieldcrypt"
+	"gin-app-start/pkg/queryfilter"
 
 	"gorm.io/gorm"
 )
 
+// UserQuery 用户列表可用的过滤参数和排序字段，默认按ID升序
+//
+// 邮箱、手机号加密存储，不支持过滤和排序。
+var UserQuery = &queryfilter.Spec{
+	Filters: []queryfilter.Filter{
+		{Param: "status", Column: "status", Op: queryfilter.Eq, Parse: queryfilter.Int},
+		{Param: "username", Column: "username", Op: queryfilter.Prefix},
+		{Param: "created_from", Column: "created_at", Op: queryfilter.Gte, Parse: queryfilter.Time},
+		{Param: "created_to", Column: "created_at", Op: queryfilter.Lt, Parse: queryfilter.Time},
+	},
+	Sorts: map[string]string{
+		"id":         "id",
+		"created_at": "created_at",
+		"username":   "username",
+	},
+	DefaultSort: []queryfilter.Sort{{Column: "id"}},
+	Tiebreak:    "id",
+}
+
 type UserRepository interface {
 	Create(ctx common.Context, user *model.User) error
 	GetByID(ctx common.Context, id uint) (*model.User, error)
+	GetByIDs(ctx common.Context, ids []uint) ([]*model.User, error)
 	GetByUsername(ctx common.Context, username string) (*model.User, error)
 	GetByEmail(ctx common.Context, email string) (*model.User, error)
 	GetByPhone(ctx common.Context, phone string) (*model.User, error)
 	Update(ctx common.Context, user *model.User) error
+	UpdateFields(ctx common.Context, id uint, fields map[string]interface{}) error
 	Delete(ctx common.Context, id uint) error
-	List(ctx common.Context, offset, limit int) ([]*model.User, int64, error)
+	List(ctx common.Context, filter queryfilter.Query, offset, limit int) ([]*model.User, int64, error)
+	ListAfter(ctx common.Context, filter queryfilter.Query, afterID uint, limit int) ([]*model.User, error)
+
+	GetByInviteCode(ctx common.Context, inviteCode string) (*model.User, error)
+	SetInviteCode(ctx common.Context, id uint, inviteCode string) (bool, error)
+	CountReferrals(ctx common.Context, referrerID uint) (int64, error)
+	ReferralCounts(ctx common.Context) (*ReferralCounts, error)
+	TopReferrers(ctx common.Context, limit int) ([]*ReferrerCount, error)
+
+	ReencryptPII(ctx common.Context, batchSize int) (int64, error)
+}
+
+// encryptedUserColumns 加密存储的列，见 model.User
+var encryptedUserColumns = []string{"email", "phone"}
+
+// ReferralCounts 邀请关系汇总
+type ReferralCounts struct {
+	Issued    int64 // 已生成邀请码的用户数
+	Referrers int64 // 至少成功邀请一人的用户数
+	Referred  int64 // 通过邀请码注册的用户数
+}
+
+// ReferrerCount 单个邀请人成功邀请的人数
+type ReferrerCount struct {
+	ReferrerID uint
+	Username   string
+	Invited    int64
 }
 
 type userRepository struct {
-	*BaseRepository[model.User]
+	*CachedRepository[model.User]
 }
 
-func NewUserRepository(db *gorm.DB) UserRepository {
+// NewUserRepository 用户数据访问，GetByID 带读穿透缓存，不返回密码和盐值(校验密码使用 GetByUsername)；
+// cache 为 nil 时直接访问数据库
+func NewUserRepository(db *gorm.DB, cache redis.RedisRepository) UserRepository {
 	return &userRepository{
-		BaseRepository: NewBaseRepository[model.User](db),
+		CachedRepository: NewCachedRepository(NewBaseRepository[model.User](db), cache),
+	}
+}
+
+// GetByIDs 批量查询用户，不存在的ID会被忽略，返回顺序不保证与 ids 一致
+func (r *userRepository) GetByIDs(ctx common.Context, ids []uint) ([]*model.User, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
+
+	var users []*model.User
+	err := conn(ctx, r.db).Where("id IN ?", ids).Find(&users).Error
+	return users, err
 }
 
 func (r *userRepository) GetByUsername(ctx common.Context, username string) (*model.User, error) {
 	var user model.User
-	err := r.db.WithContext(ctx.RequestContext()).Where("username = ?", username).First(&user).Error
+	err := conn(ctx, r.db).Where("username = ?", username).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +105,7 @@ func (r *userRepository) GetByUsername(ctx common.Context, username string) (*mo
 
 func (r *userRepository) GetByEmail(ctx common.Context, email string) (*model.User, error) {
 	var user model.User
-	err := r.db.WithContext(ctx.RequestContext()).Where("email = ?", email).First(&user).Error
+	err := conn(ctx, r.db).Where("email IN ?", fieldcrypt.Lookup(email)).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -48,13 +114,145 @@ func (r *userRepository) GetByEmail(ctx common.Context, email string) (*model.Us
 
 func (r *userRepository) GetByPhone(ctx common.Context, phone string) (*model.User, error) {
 	var user model.User
-	err := r.db.WithContext(ctx.RequestContext()).Where("phone = ?", phone).First(&user).Error
+	err := conn(ctx, r.db).Where("phone IN ?", fieldcrypt.Lookup(phone)).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateFields 通过 map 更新时不经过序列化器，加密列需要在这里手动加密
+func (r *userRepository) UpdateFields(ctx common.Context, id uint, fields map[string]interface{}) error {
+	for _, column := range encryptedUserColumns {
+		value, ok := fields[column].(string)
+		if !ok {
+			continue
+		}
+		sealed, err := fieldcrypt.Seal(value)
+		if err != nil {
+			return err
+		}
+		fields[column] = sealed
+	}
+	return r.CachedRepository.UpdateFields(ctx, id, fields)
+}
+
+// piiColumns 重新加密时读取的原始列值，不经过序列化器
+type piiColumns struct {
+	ID    uint
+	Email string
+	Phone string
+}
+
+// ReencryptPII 用当前密钥重新加密全部用户(含软删除)的加密列，返回更新的用户数
+// 明文和旧密钥的密文都会被改写，可以重复执行；缓存中保存的是解密后的值，不需要失效
+func (r *userRepository) ReencryptPII(ctx common.Context, batchSize int) (int64, error) {
+	c := fieldcrypt.Active()
+	if c == nil {
+		return 0, errors.New("encryption is not enabled")
+	}
+
+	db := conn(ctx, r.db)
+	table := model.User{}.TableName()
+
+	var updated int64
+	var lastID uint
+	for {
+		var rows []piiColumns
+		err := db.Table(table).Select("id, email, phone").
+			Where("id > ?", lastID).Order("id").Limit(batchSize).
+			Scan(&rows).Error
+		if err != nil {
+			return updated, err
+		}
+		if len(rows) == 0 {
+			return updated, nil
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+
+			fields := make(map[string]interface{})
+			for column, value := range map[string]string{"email": row.Email, "phone": row.Phone} {
+				if !c.NeedsRotation(value) {
+					continue
+				}
+				plain, err := c.Decrypt(value)
+				if err != nil {
+					return updated, fmt.Errorf("user %d column %s: %w", row.ID, column, err)
+				}
+				if fields[column], err = c.Encrypt(plain); err != nil {
+					return updated, err
+				}
+			}
+			if len(fields) == 0 {
+				continue
+			}
+
+			if err := db.Table(table).Where("id = ?", row.ID).UpdateColumns(fields).Error; err != nil {
+				return updated, fmt.Errorf("user %d: %w", row.ID, err)
+			}
+			updated++
+		}
+	}
+}
+
+func (r *userRepository) GetByInviteCode(ctx common.Context, inviteCode string) (*model.User, error) {
+	var user model.User
+	err := conn(ctx, r.db).Where("invite_code = ?", inviteCode).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
+// SetInviteCode 仅在用户还没有邀请码时写入，已有邀请码(如并发请求已生成)时返回 false
+func (r *userRepository) SetInviteCode(ctx common.Context, id uint, inviteCode string) (bool, error) {
+	result := conn(ctx, r.db).Model(&model.User{}).
+		Where("id = ? AND (invite_code IS NULL OR invite_code = '')", id).
+		Update("invite_code", inviteCode)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	return true, r.Invalidate(ctx, id)
+}
+
+func (r *userRepository) CountReferrals(ctx common.Context, referrerID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.User{}).Where("referrer_id = ?", referrerID).Count(&count).Error
+	return count, err
+}
+
+func (r *userRepository) ReferralCounts(ctx common.Context) (*ReferralCounts, error) {
+	var counts ReferralCounts
+	err := conn(ctx, r.db).Model(&model.User{}).
+		Select("COUNT(*) FILTER (WHERE invite_code <> '') AS issued, " +
+			"COUNT(DISTINCT NULLIF(referrer_id, 0)) AS referrers, " +
+			"COUNT(*) FILTER (WHERE referrer_id <> 0) AS referred").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+// TopReferrers 按成功邀请人数降序返回前 limit 个邀请人
+func (r *userRepository) TopReferrers(ctx common.Context, limit int) ([]*ReferrerCount, error) {
+	var res []*ReferrerCount
+	err := conn(ctx, r.db).Table("app_schema.users AS invitee").
+		Select("invitee.referrer_id, referrer.username, COUNT(*) AS invited").
+		Joins("JOIN app_schema.users AS referrer ON referrer.id = invitee.referrer_id").
+		Where("invitee.referrer_id <> 0 AND invitee.deleted_at IS NULL").
+		Group("invitee.referrer_id, referrer.username").
+		Order("invited DESC").
+		Limit(limit).
+		Scan(&res).Error
+	return res, err
+}
+
 // List 分页查询用户列表
 //
 // 该方法实现了用户数据的分页查询功能，支持分页参数和总数统计，
@@ -62,6 +260,7 @@ func (r *userRepository) GetByPhone(ctx common.Context, phone string) (*model.Us
 //
 // 参数:
 //   - ctx: 上下文，用于超时控制、取消操作等
+//   - filter: 过滤条件和排序，见 UserQuery
 //   - offset: 偏移量，表示跳过的记录数（从0开始）
 //   - limit: 每页记录数，控制返回的用户数量
 //
@@ -69,14 +268,20 @@ func (r *userRepository) GetByPhone(ctx common.Context, phone string) (*model.Us
 //   - []*model.User: 用户列表切片，包含查询到的用户数据
 //   - int64: 用户总数，用于前端分页组件计算总页数
 //   - error: 错误信息，成功时为nil
-func (r *userRepository) List(ctx common.Context, offset, limit int) ([]*model.User, int64, error) {
+func (r *userRepository) List(ctx common.Context, filter queryfilter.Query, offset, limit int) ([]*model.User, int64, error) {
 	var users []*model.User
 	var total int64
 
-	if err := r.db.WithContext(ctx.RequestContext()).Model(&model.User{}).Count(&total).Error; err != nil {
+	db := conn(ctx, r.db).Model(&model.User{}).Scopes(filter.Where(""))
+	if err := db.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := r.db.WithContext(ctx.RequestContext()).Offset(offset).Limit(limit).Find(&users).Error
+	err := db.Scopes(UserQuery.Order(filter, "")).Offset(offset).Limit(limit).Find(&users).Error
 	return users, total, err
 }
+
+// ListAfter 游标分页查询符合过滤条件的用户，按ID升序返回ID大于 afterID 的至多 limit 个用户，不统计总数
+func (r *userRepository) ListAfter(ctx common.Context, filter queryfilter.Query, afterID uint, limit int) ([]*model.User, error) {
+	return r.BaseRepository.ListAfter(ctx, afterID, limit, filter.Where(""))
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserSettingRepository interface {
+	// Get 用户已保存的设置项，没有保存过时返回空 map
+	Get(ctx common.Context, userID uint) (map[string]any, error)
+	// Patch 合并 set 中的设置项并删除 unset 中的设置项，返回更新后保存的全部设置项
+	Patch(ctx common.Context, userID uint, set map[string]any, unset []string) (map[string]any, error)
+}
+
+type userSettingRepository struct {
+	*BaseRepository[model.UserSetting]
+}
+
+func NewUserSettingRepository(db *gorm.DB) UserSettingRepository {
+	return &userSettingRepository{
+		BaseRepository: NewBaseRepository[model.UserSetting](db),
+	}
+}
+
+func (r *userSettingRepository) Get(ctx common.Context, userID uint) (map[string]any, error) {
+	var settings []*model.UserSetting
+	if err := conn(ctx, r.db).Where("user_id = ?", userID).Limit(1).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 || settings[0].Settings == nil {
+		return map[string]any{}, nil
+	}
+	return settings[0].Settings, nil
+}
+
+// Patch 用一条 upsert 在数据库中合并，并发修改不同的设置项不会互相覆盖
+func (r *userSettingRepository) Patch(ctx common.Context, userID uint, set map[string]any, unset []string) (map[string]any, error) {
+	if set == nil {
+		set = map[string]any{}
+	}
+
+	// jsonb || 合并新值，jsonb - key 删除恢复默认的项
+	merged := "user_settings.settings || excluded.settings" + strings.Repeat(" - ?::text", len(unset))
+	args := make([]interface{}, len(unset))
+	for i, key := range unset {
+		args[i] = key
+	}
+
+	setting := &model.UserSetting{UserID: userID, Settings: set, UpdatedAt: time.Now()}
+	err := conn(ctx, r.db).
+		Clauses(
+			clause.OnConflict{
+				Columns: []clause.Column{{Name: "user_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"settings":   gorm.Expr(merged, args...),
+					"updated_at": gorm.Expr("excluded.updated_at"),
+				}),
+			},
+			clause.Returning{},
+		).
+		Create(setting).Error
+	if err != nil {
+		return nil, err
+	}
+	return setting.Settings, nil
+}
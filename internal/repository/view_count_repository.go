@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ViewCountRepository interface {
+	// Add 在一条语句中累加多个对象的浏览数，counts 为对象ID -> 增量
+	Add(ctx common.Context, subject string, counts map[string]int64) error
+	// Get 读取已落库的浏览数，没有记录时为 0
+	Get(ctx common.Context, subject, subjectID string) (int64, error)
+}
+
+type viewCountRepository struct {
+	db *gorm.DB
+}
+
+func NewViewCountRepository(db *gorm.DB) ViewCountRepository {
+	return &viewCountRepository{db: db}
+}
+
+func (r *viewCountRepository) Add(ctx common.Context, subject string, counts map[string]int64) error {
+	if len(counts) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]*model.ViewCount, 0, len(counts))
+	for subjectID, count := range counts {
+		rows = append(rows, &model.ViewCount{Subject: subject, SubjectID: subjectID, Count: count, UpdatedAt: now})
+	}
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "subject"}, {Name: "subject_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count":      gorm.Expr("view_counts.count + excluded.count"),
+				"updated_at": gorm.Expr("excluded.updated_at"),
+			}),
+		}).
+		Create(&rows).Error
+}
+
+func (r *viewCountRepository) Get(ctx common.Context, subject, subjectID string) (int64, error) {
+	var row model.ViewCount
+	err := conn(ctx, r.db).
+		Where("subject = ? AND subject_id = ?", subject, subjectID).
+		Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return row.Count, err
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WishlistRepository interface {
+	List(ctx common.Context, userID uint, offset, limit int) ([]*model.Favorite, int64, error)
+	ListProductIDs(ctx common.Context, userID uint) ([]string, error)
+	Add(ctx common.Context, userID uint, productID string) error
+	Remove(ctx common.Context, userID uint, productID string) error
+	Sync(ctx common.Context, userID uint, productIDs []string) error
+}
+
+type wishlistRepository struct {
+	*BaseRepository[model.Favorite]
+}
+
+func NewWishlistRepository(db *gorm.DB) WishlistRepository {
+	return &wishlistRepository{
+		BaseRepository: NewBaseRepository[model.Favorite](db),
+	}
+}
+
+func (r *wishlistRepository) List(ctx common.Context, userID uint, offset, limit int) ([]*model.Favorite, int64, error) {
+	var favorites []*model.Favorite
+	var total int64
+
+	err := conn(ctx, r.db).Model(&model.Favorite{}).Where("user_id = ?", userID).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = conn(ctx, r.db).Where("user_id = ?", userID).
+		Order("created_at DESC, product_id").Offset(offset).Limit(limit).Find(&favorites).Error
+	return favorites, total, err
+}
+
+func (r *wishlistRepository) ListProductIDs(ctx common.Context, userID uint) ([]string, error) {
+	var productIDs []string
+	err := conn(ctx, r.db).Model(&model.Favorite{}).
+		Where("user_id = ?", userID).Pluck("product_id", &productIDs).Error
+	return productIDs, err
+}
+
+func (r *wishlistRepository) Add(ctx common.Context, userID uint, productID string) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Favorite{UserID: userID, ProductID: productID}).Error
+}
+
+func (r *wishlistRepository) Remove(ctx common.Context, userID uint, productID string) error {
+	return conn(ctx, r.db).
+		Where("user_id = ? AND product_id = ?", userID, productID).
+		Delete(&model.Favorite{}).Error
+}
+
+// Sync 把用户的收藏同步为 productIDs: 删除不在其中的收藏，补充缺少的收藏，已有收藏保留原落库时间
+func (r *wishlistRepository) Sync(ctx common.Context, userID uint, productIDs []string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		stale := tx.Where("user_id = ?", userID)
+		if len(productIDs) > 0 {
+			stale = stale.Where("product_id NOT IN ?", productIDs)
+		}
+		if err := stale.Delete(&model.Favorite{}).Error; err != nil {
+			return err
+		}
+
+		if len(productIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]*model.Favorite, 0, len(productIDs))
+		for _, productID := range productIDs {
+			rows = append(rows, &model.Favorite{UserID: userID, ProductID: productID})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	})
+}
@@ -0,0 +1,322 @@
+// Package reqstats 在 Redis 中按用户统计正在处理的请求数和滑动窗口内的请求总数，多个实例共用计数
+package reqstats
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/redis"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	inflightPrefix = "reqstats:inflight:" // 用户 -> 正在处理的请求(有序集合，分数为开始时间毫秒)
+	requestsPrefix = "reqstats:requests:" // 用户 -> 各时间片的请求数(哈希，字段为时间片序号)
+	rejectedPrefix = "reqstats:rejected:" // 用户 -> 各时间片因超出并发上限被拒绝的请求数
+	usersKey       = "reqstats:users"     // 最近有请求的用户(有序集合，分数为最近请求时间毫秒)
+
+	// windowSlices 滑动窗口划分的时间片数，时间片至少 1 秒
+	windowSlices = 60
+	// maxScanUsers Top 最多读取的最近活跃用户数
+	maxScanUsers = 1000
+)
+
+// beginScript 清理超时的请求后统计并发数，未超出上限时记录本次请求，并计入窗口内的请求数
+// KEYS: inflight, requests, rejected, users
+// ARGV: 当前毫秒, 超时界限毫秒, 时间片, 最早有效时间片, 并发上限, 请求ID, 用户名, 窗口键过期毫秒, 用户索引清理界限毫秒
+// 返回 {是否放行, 并发数, 窗口内请求数}
+var beginScript = goredis.NewScript(`
+local function count(key, oldest)
+	local values = redis.call('HGETALL', key)
+	local total = 0
+	for i = 1, #values, 2 do
+		if tonumber(values[i]) < oldest then
+			redis.call('HDEL', key, values[i])
+		else
+			total = total + tonumber(values[i + 1])
+		end
+	end
+	return total
+end
+
+local oldest = tonumber(ARGV[4])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', '(' .. ARGV[2])
+local concurrent = redis.call('ZCARD', KEYS[1])
+
+redis.call('HINCRBY', KEYS[2], ARGV[3], 1)
+redis.call('PEXPIRE', KEYS[2], ARGV[8])
+local requests = count(KEYS[2], oldest)
+
+redis.call('ZADD', KEYS[4], ARGV[1], ARGV[7])
+redis.call('ZREMRANGEBYSCORE', KEYS[4], '-inf', '(' .. ARGV[9])
+
+local limit = tonumber(ARGV[5])
+if limit > 0 and concurrent >= limit then
+	redis.call('HINCRBY', KEYS[3], ARGV[3], 1)
+	redis.call('PEXPIRE', KEYS[3], ARGV[8])
+	return {0, concurrent, requests}
+end
+
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[6])
+redis.call('PEXPIRE', KEYS[1], ARGV[8])
+return {1, concurrent + 1, requests}
+`)
+
+// Admission 请求开始时的统计结果
+type Admission struct {
+	ID         string // 请求ID，请求结束时传给 End
+	Admitted   bool   // 为 false 时已达到用户的并发上限，请求没有计入并发数
+	Concurrent int64  // 计入本次请求后正在处理的请求数，被拒绝时为拒绝时的并发数
+	Requests   int64  // 滑动窗口内的请求数，包括本次请求
+	Limit      int    // 用户的并发上限，0 表示不限制
+}
+
+// Stats 用户的请求统计
+type Stats struct {
+	Username   string
+	Concurrent int64     // 正在处理的请求数
+	Requests   int64     // 滑动窗口内的请求数，包括被拒绝的请求
+	Rejected   int64     // 滑动窗口内因超出并发上限被拒绝的请求数
+	Limit      int       // 并发上限，0 表示不限制
+	LastSeen   time.Time // 最近一次请求的时间
+}
+
+// Tracker 按用户统计并发请求数和请求总数
+//
+// 正在处理的请求以请求ID记录在有序集合中，请求结束时删除；实例异常退出时没有结束记录，
+// 超过 stale_after 的记录不再计入并发数，在下一次统计时清理。请求总数按时间片计数，
+// 窗口为最近 window 秒内的时间片之和，时间片为窗口的 1/60，因此窗口边界的误差不超过一个时间片。
+type Tracker struct {
+	repo   redis.RedisRepository
+	cfg    config.UserConcurrencyConfig
+	window time.Duration
+	slice  time.Duration
+	stale  time.Duration
+	now    func() time.Time
+}
+
+// NewTracker 创建请求统计，未启用 concurrency.per_user 时返回 nil
+func NewTracker(repo redis.RedisRepository, cfg config.UserConcurrencyConfig) *Tracker {
+	if !cfg.Enabled {
+		return nil
+	}
+	window := cfg.WindowDuration()
+	return &Tracker{
+		repo:   repo,
+		cfg:    cfg,
+		window: window,
+		slice:  sliceDuration(window),
+		stale:  cfg.StaleDuration(),
+		now:    time.Now,
+	}
+}
+
+// Window 请求总数的滑动窗口长度
+func (t *Tracker) Window() time.Duration {
+	return t.window
+}
+
+// sliceDuration 时间片长度，窗口的 1/windowSlices，至少 1 秒
+func sliceDuration(window time.Duration) time.Duration {
+	slice := (window / windowSlices).Truncate(time.Second)
+	if slice < time.Second {
+		return time.Second
+	}
+	return slice
+}
+
+// sliceOf 时间 now 所在的时间片序号
+func (t *Tracker) sliceOf(now time.Time) int64 {
+	return now.UnixMilli() / t.slice.Milliseconds()
+}
+
+// oldestSlice 窗口内最早的时间片序号，包括当前时间片共 window/slice 个
+func (t *Tracker) oldestSlice(now time.Time) int64 {
+	return t.sliceOf(now) - int64(t.window/t.slice) + 1
+}
+
+// sumSlices 累加 values 中不早于 oldest 的时间片计数
+func sumSlices(values map[string]string, oldest int64) int64 {
+	var total int64
+	for field, value := range values {
+		slice, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || slice < oldest {
+			continue
+		}
+		n, _ := strconv.ParseInt(value, 10, 64)
+		total += n
+	}
+	return total
+}
+
+// retention 统计键的过期时间，覆盖窗口和请求超时中较长的一个
+func (t *Tracker) retention() time.Duration {
+	if t.stale > t.window {
+		return t.stale
+	}
+	return t.window
+}
+
+// Begin 请求开始时计数，达到用户的并发上限时 Admitted 为 false，调用方应拒绝请求且不调用 End
+func (t *Tracker) Begin(ctx context.Context, username string) (*Admission, error) {
+	client := t.repo.GetRedisClient()
+	if client == nil {
+		return nil, redis.ErrUnavailable
+	}
+
+	now := t.now()
+	admission := &Admission{
+		ID:    uuid.NewString(),
+		Limit: t.cfg.Limit(username),
+	}
+	keys := []string{
+		t.repo.Key(inflightPrefix + username),
+		t.repo.Key(requestsPrefix + username),
+		t.repo.Key(rejectedPrefix + username),
+		t.repo.Key(usersKey),
+	}
+	result, err := beginScript.Run(ctx, client, keys,
+		now.UnixMilli(),
+		now.Add(-t.stale).UnixMilli(),
+		t.sliceOf(now),
+		t.oldestSlice(now),
+		admission.Limit,
+		admission.ID,
+		username,
+		t.retention().Milliseconds(),
+		now.Add(-t.retention()).UnixMilli(),
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	admission.Admitted = result[0] == 1
+	admission.Concurrent = result[1]
+	admission.Requests = result[2]
+	return admission, nil
+}
+
+// End 请求结束时从并发数中移除，ctx 不应随请求取消，否则客户端断开时记录会保留到超时
+func (t *Tracker) End(ctx context.Context, username, id string) error {
+	client := t.repo.GetRedisClient()
+	if client == nil {
+		return redis.ErrUnavailable
+	}
+	return client.ZRem(ctx, t.repo.Key(inflightPrefix+username), id).Err()
+}
+
+// User 单个用户的请求统计，窗口内没有请求的用户各项计数为 0
+func (t *Tracker) User(ctx context.Context, username string) (*Stats, error) {
+	stats, err := t.load(ctx, []goredis.Z{{Member: username}})
+	if err != nil {
+		return nil, err
+	}
+	return stats[0], nil
+}
+
+// Top 窗口内有请求的用户，按并发数、请求数从高到低排列，最多返回 limit 个
+// 只读取最近活跃的 maxScanUsers 个用户
+func (t *Tracker) Top(ctx context.Context, limit int) ([]*Stats, error) {
+	client := t.repo.GetRedisClient()
+	if client == nil {
+		return nil, redis.ErrUnavailable
+	}
+
+	now := t.now()
+	users, err := client.ZRevRangeByScoreWithScores(ctx, t.repo.Key(usersKey), &goredis.ZRangeBy{
+		Min:   strconv.FormatInt(now.Add(-t.retention()).UnixMilli(), 10),
+		Max:   "+inf",
+		Count: maxScanUsers,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return []*Stats{}, nil
+	}
+
+	stats, err := t.load(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+	sortStats(stats)
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats, nil
+}
+
+// load 用一次 pipeline 读取 users 的计数，users 的分数为最近请求时间毫秒，为 0 时另行读取
+func (t *Tracker) load(ctx context.Context, users []goredis.Z) ([]*Stats, error) {
+	client := t.repo.GetRedisClient()
+	if client == nil {
+		return nil, redis.ErrUnavailable
+	}
+
+	now := t.now()
+	staleBefore := strconv.FormatInt(now.Add(-t.stale).UnixMilli(), 10)
+
+	type cmds struct {
+		concurrent *goredis.IntCmd
+		requests   *goredis.MapStringStringCmd
+		rejected   *goredis.MapStringStringCmd
+		lastSeen   *goredis.FloatCmd
+	}
+	results := make([]cmds, len(users))
+	_, err := client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, user := range users {
+			username := user.Member.(string)
+			results[i].concurrent = pipe.ZCount(ctx, t.repo.Key(inflightPrefix+username), staleBefore, "+inf")
+			results[i].requests = pipe.HGetAll(ctx, t.repo.Key(requestsPrefix+username))
+			results[i].rejected = pipe.HGetAll(ctx, t.repo.Key(rejectedPrefix+username))
+			if user.Score == 0 {
+				results[i].lastSeen = pipe.ZScore(ctx, t.repo.Key(usersKey), username)
+			}
+		}
+		return nil
+	})
+	// 用户不在索引中时 ZScore 返回 redis.Nil
+	if err != nil && err != goredis.Nil {
+		return nil, err
+	}
+
+	oldest := t.oldestSlice(now)
+	stats := make([]*Stats, len(users))
+	for i, user := range users {
+		username := user.Member.(string)
+		lastSeen := user.Score
+		if results[i].lastSeen != nil {
+			lastSeen = results[i].lastSeen.Val()
+		}
+		stats[i] = &Stats{
+			Username:   username,
+			Concurrent: results[i].concurrent.Val(),
+			Requests:   sumSlices(results[i].requests.Val(), oldest),
+			Rejected:   sumSlices(results[i].rejected.Val(), oldest),
+			Limit:      t.cfg.Limit(username),
+		}
+		if lastSeen > 0 {
+			stats[i].LastSeen = time.UnixMilli(int64(lastSeen))
+		}
+	}
+	return stats, nil
+}
+
+// sortStats 按并发数、请求数从高到低排列，相同时按用户名
+func sortStats(stats []*Stats) {
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.Concurrent != b.Concurrent {
+			return a.Concurrent > b.Concurrent
+		}
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		return a.Username < b.Username
+	})
+}
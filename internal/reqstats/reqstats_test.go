@@ -0,0 +1,80 @@
+package reqstats
+
+import (
+	"testing"
+	"time"
+
+	"gin-app-start/internal/config"
+)
+
+func TestSliceDuration(t *testing.T) {
+	cases := []struct {
+		window time.Duration
+		want   time.Duration
+	}{
+		{10 * time.Second, time.Second},
+		{time.Minute, time.Second},
+		{time.Hour, time.Minute},
+		{90 * time.Second, time.Second},
+	}
+	for _, tc := range cases {
+		if got := sliceDuration(tc.window); got != tc.want {
+			t.Errorf("sliceDuration(%v) = %v, want %v", tc.window, got, tc.want)
+		}
+	}
+}
+
+func TestWindowSlices(t *testing.T) {
+	tr := NewTracker(nil, config.UserConcurrencyConfig{Enabled: true, Window: 60})
+	now := time.Unix(1000, 500*int64(time.Millisecond))
+
+	if got := tr.sliceOf(now); got != 1000 {
+		t.Fatalf("sliceOf = %d, want 1000", got)
+	}
+	// 包括当前时间片共 60 个
+	if got := tr.oldestSlice(now); got != 941 {
+		t.Fatalf("oldestSlice = %d, want 941", got)
+	}
+
+	values := map[string]string{"940": "5", "941": "2", "1000": "3", "bad": "7"}
+	if got := sumSlices(values, tr.oldestSlice(now)); got != 5 {
+		t.Fatalf("sumSlices = %d, want 5", got)
+	}
+}
+
+func TestNewTrackerDisabled(t *testing.T) {
+	if tr := NewTracker(nil, config.UserConcurrencyConfig{}); tr != nil {
+		t.Fatal("tracker should be nil when per-user concurrency is disabled")
+	}
+}
+
+func TestLimit(t *testing.T) {
+	cfg := config.UserConcurrencyConfig{MaxConcurrent: 10, Users: map[string]int{"crawler": 2, "batch": 0}}
+
+	if got := cfg.Limit("Crawler"); got != 2 {
+		t.Fatalf("Limit(Crawler) = %d, want 2", got)
+	}
+	if got := cfg.Limit("batch"); got != 0 {
+		t.Fatalf("Limit(batch) = %d, want 0 (unlimited)", got)
+	}
+	if got := cfg.Limit("alice"); got != 10 {
+		t.Fatalf("Limit(alice) = %d, want 10", got)
+	}
+}
+
+func TestSortStats(t *testing.T) {
+	stats := []*Stats{
+		{Username: "bob", Concurrent: 1, Requests: 50},
+		{Username: "carol", Concurrent: 3, Requests: 5},
+		{Username: "alice", Concurrent: 1, Requests: 50},
+		{Username: "dave", Concurrent: 1, Requests: 80},
+	}
+	sortStats(stats)
+
+	want := []string{"carol", "dave", "alice", "bob"}
+	for i, name := range want {
+		if stats[i].Username != name {
+			t.Fatalf("stats[%d] = %s, want %s", i, stats[i].Username, name)
+		}
+	}
+}
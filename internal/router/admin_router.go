@@ -0,0 +1,51 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/interceptor"
+	"gin-app-start/internal/middleware"
+	"gin-app-start/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetupAdminRouter 管理端口路由，仅用于运维接口，不应对公网开放
+func SetupAdminRouter(
+	logger *zap.Logger,
+	admin Module,
+	cfg *config.Config,
+) (*Server, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	mux := &mux{
+		engine: gin.New(),
+	}
+
+	mux.engine.NoRoute(func(c *gin.Context) {
+		response.Error(c, http.StatusNotFound, fmt.Sprintf("%s %s not found", c.Request.Method, c.Request.URL.Path))
+	})
+
+	mux.engine.Use(middleware.Recovery(logger))
+	mux.engine.Use(middleware.Logger(logger))
+
+	// 管理端口不需要登录，只通过端口隔离，也不使用 route_groups 配置
+	settings, err := newGroupSettings(nil, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := registerModules(mux, logger, "", []Module{admin}, nil, settings, interceptor.New(logger)); err != nil {
+		return nil, err
+	}
+
+	s := new(Server)
+	s.Mux = mux
+
+	return s, nil
+}
@@ -0,0 +1,109 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/interceptor"
+
+	"go.uber.org/zap"
+)
+
+// Module 功能模块，在 RegisterRoutes 中注册自己的路由
+//
+// 公网端口的模块可以通过 modules 配置关闭，关闭后不注册路由；模块的后台任务(如收藏落库)由各自的配置控制。
+type Module interface {
+	// Name 模块名，对应 modules 配置中的键，如 wishlist
+	Name() string
+	// RegisterRoutes 注册模块的路由
+	RegisterRoutes(r Router)
+}
+
+// Router 模块注册路由时使用的路由组和拦截器
+//
+// 通过 Group、AuthGroup 创建的路由组按 route_groups 配置追加限速、请求体大小限制等中间件。
+type Router interface {
+	RouterGroup // 接口路由，公网端口为 /api/v1，管理端口为根路径
+
+	// AuthGroup 创建需要登录的路由组，认证拦截器(SessionAuth 或 JWTAuth)按 auth.mode 和
+	// route_groups 中该路由组的 auth 选择；管理端口不需要登录，调用时 panic
+	AuthGroup(relativePath string, handlers ...common.HandlerFunc) RouterGroup
+
+	// Root 根路径，用于 /health 这类不带版本前缀的路由
+	Root() RouterGroup
+
+	// Interceptors 配额、重新验证身份、响应缓存等拦截器
+	Interceptors() interceptor.Interceptor
+}
+
+type moduleRouter struct {
+	RouterGroup
+	basePath     string
+	root         RouterGroup
+	settings     *groupSettings
+	interceptors interceptor.Interceptor
+}
+
+func (r *moduleRouter) Group(relativePath string, handlers ...common.HandlerFunc) RouterGroup {
+	path := joinPath(r.basePath, relativePath)
+	return r.RouterGroup.Group(relativePath, r.settings.handlers(path, false, handlers)...)
+}
+
+func (r *moduleRouter) AuthGroup(relativePath string, handlers ...common.HandlerFunc) RouterGroup {
+	path := joinPath(r.basePath, relativePath)
+	return r.RouterGroup.Group(relativePath, r.settings.handlers(path, true, handlers)...)
+}
+
+func (r *moduleRouter) Root() RouterGroup {
+	return r.root
+}
+
+func (r *moduleRouter) Interceptors() interceptor.Interceptor {
+	return r.interceptors
+}
+
+// registerModules 按顺序注册已启用的模块，enabled 中出现未知的模块名时返回错误
+// enabled 为模块名 -> 是否启用，未配置的模块默认启用
+func registerModules(m *mux, logger *zap.Logger, basePath string, modules []Module, enabled map[string]bool, settings *groupSettings, interceptors interceptor.Interceptor) error {
+	known := make(map[string]bool, len(modules))
+	for _, module := range modules {
+		if known[module.Name()] {
+			return fmt.Errorf("module %q registered twice", module.Name())
+		}
+		known[module.Name()] = true
+	}
+
+	var unknown []string
+	for name := range enabled {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("modules: unknown module %s", strings.Join(unknown, ", "))
+	}
+
+	for _, module := range modules {
+		name := module.Name()
+		if on, ok := enabled[name]; ok && !on {
+			logger.Info("Module disabled", zap.String("module", name))
+			continue
+		}
+
+		module.RegisterRoutes(&moduleRouter{
+			RouterGroup:  m.group(name, basePath),
+			basePath:     basePath,
+			root:         m.group(name, ""),
+			settings:     settings,
+			interceptors: interceptors,
+		})
+	}
+
+	if unused := settings.unused(); len(unused) > 0 {
+		logger.Warn("route_groups configured for unknown or disabled route groups", zap.Strings("paths", unused))
+	}
+	return nil
+}
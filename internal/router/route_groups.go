@@ -0,0 +1,122 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/middleware"
+)
+
+// groupSettings 按 route_groups 配置为模块创建的路由组追加中间件
+type groupSettings struct {
+	groups      map[string][]common.HandlerFunc // 路由组路径 -> 限速、请求体大小、响应缓存开关
+	dedup       map[string]common.HandlerFunc   // 路由组路径 -> 请求合并，注册在认证拦截器之后
+	auth        map[string]string               // 路由组路径 -> 认证方式
+	authn       map[string]common.HandlerFunc   // 认证方式 -> 认证拦截器
+	defaultAuth string
+	apiKeyAuth  common.HandlerFunc // 启用 API Key 时注册在认证拦截器之前，未启用时为 nil
+	perUser     common.HandlerFunc // 启用 concurrency.per_user 时注册在认证拦截器之后，未启用时为 nil
+	used        map[string]bool
+}
+
+// newGroupSettings 校验配置并创建各路由组的中间件，authn 为可用的认证方式
+func newGroupSettings(configs map[string]config.RouteGroupConfig, authn map[string]common.HandlerFunc, defaultAuth string) (*groupSettings, error) {
+	s := &groupSettings{
+		groups:      make(map[string][]common.HandlerFunc, len(configs)),
+		dedup:       make(map[string]common.HandlerFunc),
+		auth:        make(map[string]string, len(configs)),
+		authn:       authn,
+		defaultAuth: defaultAuth,
+		used:        make(map[string]bool, len(configs)),
+	}
+
+	for path, cfg := range configs {
+		if cfg.RateLimit < 0 || cfg.MaxBodySize < 0 {
+			return nil, fmt.Errorf("route_groups[%s]: rate_limit and max_body_size must not be negative", path)
+		}
+		if cfg.Auth != "" {
+			if _, ok := authn[cfg.Auth]; !ok {
+				if cfg.Auth == config.AuthModeJWT {
+					return nil, fmt.Errorf("route_groups[%s]: auth jwt requires auth.mode jwt, tokens are only issued in jwt mode", path)
+				}
+				return nil, fmt.Errorf("route_groups[%s]: auth %q is not available, must be one of %s", path, cfg.Auth, strings.Join(sortedKeys(authn), ", "))
+			}
+			s.auth[path] = cfg.Auth
+		}
+
+		// 限速放在认证之前，未登录的请求同样计数
+		var handlers []common.HandlerFunc
+		if cfg.RateLimit > 0 {
+			handlers = append(handlers, middleware.GroupRateLimit(cfg.RateLimit))
+		}
+		if cfg.MaxBodySize > 0 {
+			handlers = append(handlers, middleware.BodyLimit(cfg.MaxBodySize))
+		}
+		if cfg.NoCache {
+			handlers = append(handlers, middleware.NoResponseCache)
+		}
+		// 合并放在认证之后，按认证后的用户区分请求，见 handlers
+		if cfg.Dedup {
+			s.dedup[path] = middleware.Dedup()
+		}
+		s.groups[path] = handlers
+	}
+	return s, nil
+}
+
+// handlers 路由组 path 上追加在 handlers 之前的中间件，authenticated 为 true 时包含认证拦截器
+func (s *groupSettings) handlers(path string, authenticated bool, handlers []common.HandlerFunc) []common.HandlerFunc {
+	var chain []common.HandlerFunc
+	if extra, ok := s.groups[path]; ok {
+		s.used[path] = true
+		chain = append(chain, extra...)
+	}
+
+	if authenticated {
+		auth, ok := s.auth[path]
+		if !ok {
+			auth = s.defaultAuth
+		}
+		authn, ok := s.authn[auth]
+		if !ok {
+			panic(fmt.Sprintf("router: no authenticator for route group %s", path))
+		}
+		if s.apiKeyAuth != nil {
+			chain = append(chain, s.apiKeyAuth)
+		}
+		chain = append(chain, authn)
+	}
+	// 等待合并结果的请求不占用并发名额，也不计入配额
+	if dedup, ok := s.dedup[path]; ok {
+		s.used[path] = true
+		chain = append(chain, dedup)
+	}
+	if authenticated && s.perUser != nil {
+		chain = append(chain, s.perUser)
+	}
+	return append(chain, handlers...)
+}
+
+// unused 配置了但没有对应路由组的路径，通常是路径写错或模块已关闭
+func (s *groupSettings) unused() []string {
+	var paths []string
+	for path := range s.groups {
+		if !s.used[path] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func sortedKeys(m map[string]common.HandlerFunc) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
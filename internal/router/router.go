@@ -4,21 +4,29 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	"gin-app-start/internal/activity"
 	"gin-app-start/internal/common"
 	"gin-app-start/internal/config"
-	"gin-app-start/internal/controller"
+	"gin-app-start/internal/hooks"
+	"gin-app-start/internal/httpcache"
 	"gin-app-start/internal/interceptor"
+	"gin-app-start/internal/metrics"
 	"gin-app-start/internal/middleware"
+	"gin-app-start/internal/quota"
+	"gin-app-start/internal/reqstats"
+	"gin-app-start/internal/spa"
+	"gin-app-start/internal/telemetry"
 	"gin-app-start/pkg/color"
+	"gin-app-start/pkg/jwt"
 	"gin-app-start/pkg/response"
 
 	"github.com/gin-contrib/sessions"
-	"github.com/gin-contrib/sessions/cookie"
-	"github.com/gin-contrib/sessions/redis"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
@@ -58,43 +66,54 @@ type IRoutes interface {
 }
 
 type router struct {
-	group *gin.RouterGroup
+	group    *gin.RouterGroup
+	mux      *mux
+	handlers []common.HandlerFunc // 路由组上注册的拦截器，含上级路由组的
+	module   string               // 注册路由的模块
 }
 
 func (r *router) Group(relativePath string, handlers ...common.HandlerFunc) RouterGroup {
 	group := r.group.Group(relativePath, wrapHandlers(handlers...)...)
-	return &router{group: group}
+	return &router{group: group, mux: r.mux, handlers: append(r.handlers[:len(r.handlers):len(r.handlers)], handlers...), module: r.module}
 }
 
 func (r *router) Any(relativePath string, handlers ...common.HandlerFunc) {
+	r.record("ANY", relativePath, handlers)
 	r.group.Any(relativePath, wrapHandlers(handlers...)...)
 }
 
 func (r *router) GET(relativePath string, handlers ...common.HandlerFunc) {
+	r.record(http.MethodGet, relativePath, handlers)
 	r.group.GET(relativePath, wrapHandlers(handlers...)...)
 }
 
 func (r *router) POST(relativePath string, handlers ...common.HandlerFunc) {
+	r.record(http.MethodPost, relativePath, handlers)
 	r.group.POST(relativePath, wrapHandlers(handlers...)...)
 }
 
 func (r *router) DELETE(relativePath string, handlers ...common.HandlerFunc) {
+	r.record(http.MethodDelete, relativePath, handlers)
 	r.group.DELETE(relativePath, wrapHandlers(handlers...)...)
 }
 
 func (r *router) PATCH(relativePath string, handlers ...common.HandlerFunc) {
+	r.record(http.MethodPatch, relativePath, handlers)
 	r.group.PATCH(relativePath, wrapHandlers(handlers...)...)
 }
 
 func (r *router) PUT(relativePath string, handlers ...common.HandlerFunc) {
+	r.record(http.MethodPut, relativePath, handlers)
 	r.group.PUT(relativePath, wrapHandlers(handlers...)...)
 }
 
 func (r *router) OPTIONS(relativePath string, handlers ...common.HandlerFunc) {
+	r.record(http.MethodOptions, relativePath, handlers)
 	r.group.OPTIONS(relativePath, wrapHandlers(handlers...)...)
 }
 
 func (r *router) HEAD(relativePath string, handlers ...common.HandlerFunc) {
+	r.record(http.MethodHead, relativePath, handlers)
 	r.group.HEAD(relativePath, wrapHandlers(handlers...)...)
 }
 
@@ -120,10 +139,13 @@ var _ Mux = (*mux)(nil)
 type Mux interface {
 	http.Handler                                                           // 实现 http.Handler 接口
 	Group(relativePath string, handlers ...common.HandlerFunc) RouterGroup // 创建路由组
+	Routes() []RouteInfo                                                   // 已注册的路由，按注册顺序
+	Middleware() []string                                                  // 全局中间件，按执行顺序
 }
 
 type mux struct {
 	engine *gin.Engine
+	routes []RouteInfo
 }
 
 func (m *mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -132,7 +154,18 @@ func (m *mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 func (m *mux) Group(relativePath string, handlers ...common.HandlerFunc) RouterGroup {
 	return &router{
-		group: m.engine.Group(relativePath, wrapHandlers(handlers...)...),
+		group:    m.engine.Group(relativePath, wrapHandlers(handlers...)...),
+		mux:      m,
+		handlers: handlers,
+	}
+}
+
+// group 创建模块使用的路由组，注册的路由记录所属模块
+func (m *mux) group(module, relativePath string) RouterGroup {
+	return &router{
+		group:  m.engine.Group(relativePath),
+		mux:    m,
+		module: module,
 	}
 }
 
@@ -146,11 +179,19 @@ type Server struct {
 	Mux Mux
 }
 
+// SetupRouter 公网端口路由，全局中间件之后按顺序注册 modules 中已启用的模块
 func SetupRouter(
 	logger *zap.Logger,
-	healthCtrl *controller.HealthController,
-	userCtrl *controller.UserController,
-	orderCtrl *controller.OrderController,
+	slowLogger *zap.Logger,
+	modules []Module,
+	quotaLimiter *quota.Limiter,
+	sessionTracker *activity.Tracker,
+	requestStats *reqstats.Tracker,
+	recorder middleware.Recorder,
+	responseCache *httpcache.Cache,
+	tokens *jwt.Manager,
+	apiKeys middleware.APIKeyAuthenticator,
+	hookRegistry *hooks.Registry,
 	cfg *config.Config,
 ) (*Server, error) {
 	if logger == nil {
@@ -169,8 +210,19 @@ func SetupRouter(
 
 	// 设置最大文件上传大小
 	mux.engine.MaxMultipartMemory = int64(cfg.File.MaxSize)
+	// 启用 spa 时未匹配接口路由的请求先交给前端构建目录，前端不处理的仍返回 404
+	var site *spa.Site
+	if cfg.SPA.Enabled {
+		var err error
+		if site, err = spa.New(cfg.SPA); err != nil {
+			return nil, fmt.Errorf("spa: %w", err)
+		}
+	}
 	// 404处理
 	mux.engine.NoRoute(func(c *gin.Context) {
+		if site != nil && site.Serve(c) {
+			return
+		}
 		path := c.Request.URL.Path
 		method := c.Request.Method
 		response.Error(c, http.StatusNotFound, fmt.Sprintf("%s %s not found", method, path))
@@ -178,18 +230,67 @@ func SetupRouter(
 
 	mux.engine.Use(middleware.CORS())
 	mux.engine.Use(middleware.Recovery(logger))
-	mux.engine.Use(middleware.Logger(logger))
+
+	// 追踪需要在 Logger 之前注册，Logger 才能使用链路的 trace_id，后续中间件和业务代码的 span 都挂在请求的 span 下
+	// 指标抓取和健康检查请求频繁且没有排查价值，不记录 span
+	if cfg.Tracing.Enabled {
+		untraced := map[string]bool{"/metrics": true, "/health": true, "/healthz": true, "/ready": true, "/readyz": true}
+		if cfg.Metrics.Path != "" {
+			untraced[cfg.Metrics.Path] = true
+		}
+		mux.engine.Use(otelgin.Middleware(telemetry.ServiceName(cfg.Tracing), otelgin.WithGinFilter(func(c *gin.Context) bool {
+			return !untraced[c.FullPath()]
+		})))
+	}
+
+	if cfg.Metrics.Enabled {
+		metrics.Init(cfg.Metrics)
+		// 活跃会话数依赖会话超时记录，未配置会话超时(sessionTracker 为 nil)时不统计
+		if sessionTracker != nil {
+			metrics.RegisterActiveSessions(sessionTracker.Count)
+		}
+		mux.engine.Use(middleware.Metrics())
+		metricsPath := cfg.Metrics.Path
+		if metricsPath == "" {
+			metricsPath = "/metrics"
+		}
+		mux.engine.GET(metricsPath, gin.WrapH(metrics.Handler()))
+	}
+
+	// 请求录制需要在 Logger 之前注册，才能拿到 Logger 写出的响应体和 trace_id
+	if recorder != nil {
+		mux.engine.Use(middleware.Tape(recorder, cfg.Tape))
+	}
+
+	var loggerOptions []middleware.LoggerOption
+	if slowLogger != nil && cfg.Log.SlowRequestThreshold > 0 {
+		loggerOptions = append(loggerOptions, middleware.WithSlowRequestLog(
+			slowLogger,
+			time.Duration(cfg.Log.SlowRequestThreshold)*time.Millisecond,
+		))
+	}
+	if cfg.Log.DebugTrace {
+		loggerOptions = append(loggerOptions, middleware.WithDebugTrace())
+	}
+	mux.engine.Use(middleware.Logger(logger, loggerOptions...))
+
+	// 生命周期钩子需要在 Logger 之后注册，才能拿到请求级 Logger 并在响应写出前执行
+	if hookRegistry != nil {
+		mux.engine.Use(middleware.Hooks(hookRegistry))
+	}
 
 	if cfg.Server.LimitNum > 0 {
 		mux.engine.Use(middleware.RateLimit(cfg.Server.LimitNum))
 	}
 
+	if cfg.Concurrency.MaxConcurrent > 0 || len(cfg.Concurrency.Routes) > 0 {
+		mux.engine.Use(middleware.ConcurrencyLimit(cfg.Concurrency))
+	}
+
 	// sessions.Store: 会话存储接口，用于存储会话数据
-	var store sessions.Store
-	if cfg.Session.UseRedis {
-		store, _ = redis.NewStore(cfg.Session.Size, "tcp", cfg.Redis.Addr, "", cfg.Redis.Password, []byte(cfg.Session.Key))
-	} else {
-		store = cookie.NewStore([]byte(cfg.Session.Key))
+	store, err := newSessionStore(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	// 设置session的选项
@@ -210,41 +311,31 @@ func SetupRouter(
 	mux.engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	r.mux = mux
-	r.interceptors = interceptor.New(logger)
-
-	root := mux.Group("")
-	{
-		root.GET("/health", healthCtrl.HealthCheck())
+	r.interceptors = interceptor.New(logger, interceptor.WithQuota(quotaLimiter), interceptor.WithActivity(sessionTracker), interceptor.WithRequestStats(requestStats), interceptor.WithReauthMaxAge(cfg.Session.ReauthWindow()), interceptor.WithResponseCache(responseCache))
+
+	// auth.mode 为 jwt 时(tokens 不为 nil)需要登录的路由改为验证访问令牌；
+	// 令牌只在 jwt 模式下签发，session 模式下路由组不能改用 jwt
+	authn := map[string]common.HandlerFunc{config.AuthModeSession: r.interceptors.SessionAuth()}
+	defaultAuth := config.AuthModeSession
+	if tokens != nil {
+		authn[config.AuthModeJWT] = middleware.JWTAuth(tokens)
+		defaultAuth = config.AuthModeJWT
+	}
+	settings, err := newGroupSettings(cfg.RouteGroups, authn, defaultAuth)
+	if err != nil {
+		return nil, err
+	}
+	// 启用 API Key 时(apiKeys 不为 nil)需要登录的路由组同时接受 X-API-Key
+	if apiKeys != nil {
+		settings.apiKeyAuth = middleware.APIKeyAuth(apiKeys)
+	}
+	// 启用按用户的请求统计时(requestStats 不为 nil)需要登录的路由组在认证之后计数
+	if requestStats != nil {
+		settings.perUser = r.interceptors.UserConcurrency()
 	}
 
-	apiV1 := mux.Group("/api/v1")
-	{
-		users := apiV1.Group("/users")
-		{
-			users.POST("", userCtrl.CreateUser())
-			users.POST("/login", userCtrl.Login())
-		}
-
-		authUsers := apiV1.Group("/users", r.interceptors.SessionAuth())
-		{
-			authUsers.GET("/:id", userCtrl.GetUser())
-			authUsers.PUT("/:id", userCtrl.UpdateUser())
-			authUsers.POST("/change_pwd", userCtrl.ChangePassword())
-			authUsers.POST("/upload_avatar", userCtrl.UploadImage())
-			authUsers.GET("/file", userCtrl.GetImage())
-			authUsers.DELETE("/:id", userCtrl.DeleteUser())
-			authUsers.GET("", userCtrl.ListUsers())
-			authUsers.POST("/logout", userCtrl.Logout())
-		}
-
-		orders := apiV1.Group("/orders", r.interceptors.SessionAuth())
-		{
-			orders.POST("", orderCtrl.CreateOrder())
-			orders.GET("/search", orderCtrl.GetOrderByOrderNumber())
-			orders.PUT("", orderCtrl.UpdateOrderByOrderNumber())
-			orders.DELETE("", orderCtrl.DeleteOrderByOrderNumber())
-			orders.GET("", orderCtrl.ListOrders())
-		}
+	if err := registerModules(mux, logger, "/api/v1", modules, cfg.Modules, settings, r.interceptors); err != nil {
+		return nil, err
 	}
 
 	s := new(Server)
@@ -0,0 +1,173 @@
+package router
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"gin-app-start/internal/common"
+)
+
+// RouteInfo 已注册的路由，供 routes 命令打印，便于审查哪些接口需要登录
+type RouteInfo struct {
+	Method       string
+	Path         string
+	Module       string   // 注册路由的模块，直接注册在 gin.Engine 上的路由(如 /metrics)为空
+	Handler      string   // 处理函数，如 UserController.CreateUser
+	Interceptors []string // 路由组和路由上的拦截器，按执行顺序，如 SessionAuth、Quota
+}
+
+// Auth 根据拦截器概括访问要求: session 需要登录，jwt 需要访问令牌，|api_key 表示也可以使用 API Key，
+// session+admin 为只有管理员可以访问的页面，reauth 还要求最近验证过身份，no-impersonation 表示代入用户期间不可访问，
+// no-api-key 表示不接受 API Key；不需要登录时返回空字符串
+func (ri RouteInfo) Auth() string {
+	var auth []string
+	apiKey := false
+	for _, name := range ri.Interceptors {
+		switch name {
+		case "APIKeyAuth":
+			apiKey = true
+		case "SessionAuth":
+			auth = append(auth, withAPIKey("session", apiKey))
+		case "JWTAuth":
+			auth = append(auth, withAPIKey("jwt", apiKey))
+		case "RecentAuth":
+			auth = append(auth, "reauth")
+		case "NotImpersonating":
+			auth = append(auth, "no-impersonation")
+		case "NotAPIKey":
+			auth = append(auth, "no-api-key")
+		case "PageAuth":
+			auth = append(auth, "session+admin")
+		}
+	}
+	return strings.Join(auth, "+")
+}
+
+func withAPIKey(auth string, apiKey bool) string {
+	if apiKey {
+		return auth + "|api_key"
+	}
+	return auth
+}
+
+// record 记录路由，最后一个 handler 为处理函数，其余为拦截器
+func (r *router) record(method, relativePath string, handlers []common.HandlerFunc) {
+	if len(handlers) == 0 {
+		return
+	}
+
+	chain := append(r.handlers[:len(r.handlers):len(r.handlers)], handlers...)
+	interceptors := make([]string, 0, len(chain)-1)
+	for _, h := range chain[:len(chain)-1] {
+		interceptors = append(interceptors, shortFuncName(h, 1))
+	}
+
+	r.mux.routes = append(r.mux.routes, RouteInfo{
+		Method:       method,
+		Path:         joinPath(r.group.BasePath(), relativePath),
+		Module:       r.module,
+		Handler:      shortFuncName(chain[len(chain)-1], 2),
+		Interceptors: interceptors,
+	})
+}
+
+// Routes 通过路由组注册的路由按注册顺序排列，之后是直接注册在 gin.Engine 上的路由(如 /metrics、/swagger)
+func (m *mux) Routes() []RouteInfo {
+	routes := make([]RouteInfo, len(m.routes), len(m.routes)+4)
+	copy(routes, m.routes)
+
+	recorded := make(map[string]bool, len(m.routes))
+	for _, route := range m.routes {
+		recorded[route.Method+" "+route.Path] = true
+	}
+	for _, route := range m.engine.Routes() {
+		if recorded[route.Method+" "+route.Path] || recorded["ANY "+route.Path] {
+			continue
+		}
+		routes = append(routes, RouteInfo{
+			Method:  route.Method,
+			Path:    route.Path,
+			Handler: shortFuncName(route.HandlerFunc, 2),
+		})
+	}
+	return routes
+}
+
+// Middleware 全局中间件，按执行顺序，对所有路由生效
+func (m *mux) Middleware() []string {
+	names := make([]string, 0, len(m.engine.Handlers))
+	for _, h := range m.engine.Handlers {
+		names = append(names, shortFuncName(h, 2))
+	}
+	return names
+}
+
+// WriteRoutes 以表格形式输出全局中间件和路由表，不需要登录的路由访问要求显示为 unauthenticated
+// (如公网路由为 public，管理端口的路由为 admin-port)
+func WriteRoutes(w io.Writer, s *Server, unauthenticated string) error {
+	if _, err := fmt.Fprintf(w, "Global middleware: %s\n\n", strings.Join(s.Mux.Middleware(), ", ")); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tMODULE\tHANDLER\tINTERCEPTORS\tAUTH")
+	for _, route := range s.Mux.Routes() {
+		interceptors := strings.Join(route.Interceptors, ", ")
+		if interceptors == "" {
+			interceptors = "-"
+		}
+		module := route.Module
+		if module == "" {
+			module = "-"
+		}
+		auth := route.Auth()
+		if auth == "" {
+			auth = unauthenticated
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", route.Method, route.Path, module, route.Handler, interceptors, auth)
+	}
+	return tw.Flush()
+}
+
+// shortFuncName 函数名的最后 parts 段，去掉包路径、闭包后缀和接收者的指针标记
+// 如 gin-app-start/internal/controller.(*UserController).CreateUser.func1 取 2 段为 UserController.CreateUser
+func shortFuncName(fn interface{}, parts int) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+
+	segments := strings.Split(name, ".")
+	for len(segments) > 1 && isClosureName(segments[len(segments)-1]) {
+		segments = segments[:len(segments)-1]
+	}
+	if len(segments) > parts {
+		segments = segments[len(segments)-parts:]
+	}
+	return strings.Join(segments, ".")
+}
+
+// isClosureName 是否为编译器生成的闭包名，如 func1
+func isClosureName(name string) bool {
+	digits := strings.TrimPrefix(name, "func")
+	if digits == name || digits == "" {
+		return false
+	}
+	return strings.Trim(digits, "0123456789") == ""
+}
+
+// joinPath 与 gin 拼接路由组路径的方式一致，保留相对路径末尾的 /
+func joinPath(base, relative string) string {
+	if relative == "" {
+		return base
+	}
+	joined := path.Join(base, relative)
+	if strings.HasSuffix(relative, "/") && !strings.HasSuffix(joined, "/") {
+		return joined + "/"
+	}
+	return joined
+}
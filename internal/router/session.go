@@ -0,0 +1,60 @@
+package router
+
+import (
+	"fmt"
+	"strconv"
+
+	"gin-app-start/internal/config"
+
+	"github.com/boj/redistore"
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-contrib/sessions/redis"
+)
+
+// defaultSessionKeyPrefix redistore 默认的会话键前缀
+const defaultSessionKeyPrefix = "session_"
+
+// newSessionStore 根据配置创建会话存储
+func newSessionStore(cfg *config.Config) (sessions.Store, error) {
+	// 会话密钥: 配置了 key_pairs 时会话内容加密 + 签名，并支持密钥轮换
+	sessionKeys, err := cfg.Session.SessionKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	// Redis 禁用时退回 Cookie 存储
+	if !cfg.Session.UseRedis || !cfg.Redis.Enabled {
+		return cookie.NewStore(sessionKeys...), nil
+	}
+
+	// 会话单独使用一个 Redis 库和键前缀，与缓存数据隔离
+	store, err := redis.NewStoreWithDB(cfg.Session.Size, "tcp", cfg.Redis.Addr, "", cfg.Redis.Password,
+		strconv.Itoa(cfg.Session.RedisDB), sessionKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create redis session store: %w", err)
+	}
+
+	rediStore, err := redis.GetRedisStore(store)
+	if err != nil {
+		return nil, err
+	}
+	// 会话键同样加上 Redis 的命名空间前缀，多个环境共用一个 Redis 时互不影响
+	keyPrefix := cfg.Session.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = defaultSessionKeyPrefix
+	}
+	rediStore.SetKeyPrefix(cfg.Redis.Namespace(cfg.Env) + keyPrefix)
+
+	switch cfg.Session.Serializer {
+	case "", "gob":
+		rediStore.SetSerializer(redistore.GobSerializer{})
+	case "json":
+		// JSON 格式便于在 Redis 中直接查看会话内容
+		rediStore.SetSerializer(redistore.JSONSerializer{})
+	default:
+		return nil, fmt.Errorf("unsupported session serializer %q", cfg.Session.Serializer)
+	}
+
+	return store, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/model"
+)
+
+// Actor 发起本次操作的用户，由 controller 从会话中解析后传入
+type Actor struct {
+	UserID   uint
+	Username string
+}
+
+// IsAdmin 是否为管理员，管理员可以操作任意用户的数据
+func (a Actor) IsAdmin() bool {
+	return a.Username == common.ADMIN_NAME
+}
+
+// ownsOrder 订单是否属于该用户
+// 优先比较用户ID，旧订单可能未记录 user_id，此时退化为比较用户名
+func (a Actor) ownsOrder(order *model.Order) bool {
+	if a.UserID != 0 && order.UserID != 0 {
+		return a.UserID == order.UserID
+	}
+	return a.Username != "" && a.Username == order.Username
+}
+
+// visibleOrder 返回该用户可见的订单，非管理员看不到内部备注
+// 订单可能来自缓存，过滤时复制一份，不修改原对象
+func (a Actor) visibleOrder(order *model.Order) *model.Order {
+	if a.IsAdmin() || len(order.Notes) == 0 {
+		return order
+	}
+
+	visible := *order
+	visible.Notes = make([]model.OrderNote, 0, len(order.Notes))
+	for _, note := range order.Notes {
+		if !note.Internal {
+			visible.Notes = append(visible.Notes, note)
+		}
+	}
+	return &visible
+}
+
+// authorizeOrder 校验用户是否有权操作该订单
+func (a Actor) authorizeOrder(order *model.Order) error {
+	if a.IsAdmin() || a.ownsOrder(order) {
+		return nil
+	}
+	return ErrOrderForbidden
+}
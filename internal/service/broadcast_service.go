@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/mail"
+	"gin-app-start/pkg/pool"
+	"gin-app-start/pkg/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultBroadcastPollInterval = 10 * time.Second
+	defaultBroadcastBatchSize    = 200
+	// broadcastStaleTimeout running 状态的群发超过该时长没有更新进度，视为执行实例已退出，由其他实例从断点继续
+	broadcastStaleTimeout = 5 * time.Minute
+	// broadcastMailConcurrency 每批邮件的并发发送数
+	broadcastMailConcurrency = 4
+)
+
+var _ BroadcastService = (*broadcastService)(nil)
+
+// BroadcastService 定时群发: 管理端创建群发，后台任务在到期后按标签分群逐批发送站内信和邮件
+//
+// 进度按批次写回数据库，批次之间检查群发是否被取消；执行中的实例退出后，
+// 其他实例(或重启后的本实例)会在 broadcastStaleTimeout 后从最后一批继续发送。
+type BroadcastService interface {
+	Schedule(ctx common.Context, req *dto.CreateBroadcastRequest) (*model.Broadcast, error)
+	GetBroadcast(ctx common.Context, id uint) (*model.Broadcast, error)
+	ListBroadcasts(ctx common.Context, page, pageSize int) ([]*model.Broadcast, int64, error)
+	Cancel(ctx common.Context, id uint) error
+	ListNotifications(ctx common.Context, userID uint, page, pageSize int) ([]*model.Notification, int64, error)
+
+	// Start 启动后台群发任务
+	Start()
+	// Stop 停止后台群发任务，正在发送的群发在当前批次结束后停止，之后由其他实例继续
+	Stop()
+}
+
+type broadcastService struct {
+	broadcastRepo repository.BroadcastRepository
+	tagRepo       repository.TagRepository
+	tagService    TagService
+	mailer        mail.Sender // 未配置邮件时为 nil，跳过邮件渠道
+
+	interval  time.Duration
+	batchSize int
+	logger    *zap.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewBroadcastService(broadcastRepo repository.BroadcastRepository, tagRepo repository.TagRepository, tagService TagService, mailer mail.Sender, cfg config.BroadcastConfig, logger *zap.Logger) BroadcastService {
+	interval := time.Duration(cfg.PollInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultBroadcastPollInterval
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBroadcastBatchSize
+	}
+
+	return &broadcastService{
+		broadcastRepo: broadcastRepo,
+		tagRepo:       tagRepo,
+		tagService:    tagService,
+		mailer:        mailer,
+		interval:      interval,
+		batchSize:     batchSize,
+		logger:        logger,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+func (s *broadcastService) Schedule(ctx common.Context, req *dto.CreateBroadcastRequest) (*model.Broadcast, error) {
+	if !req.ScheduledAt.After(time.Now()) {
+		return nil, ErrBroadcastTimeInvalid
+	}
+
+	match := req.Match
+	if match == "" {
+		match = "any"
+	}
+
+	broadcast := &model.Broadcast{
+		Title:       req.Title,
+		Content:     req.Content,
+		Channels:    strings.Join(req.Channels, ","),
+		Tags:        strings.Join(normalizeTagNames(req.Tags), ","),
+		Match:       match,
+		ScheduledAt: req.ScheduledAt,
+		Status:      model.BroadcastPending,
+	}
+	if err := s.broadcastRepo.Create(ctx, broadcast); err != nil {
+		return nil, err
+	}
+	return broadcast, nil
+}
+
+func (s *broadcastService) GetBroadcast(ctx common.Context, id uint) (*model.Broadcast, error) {
+	broadcast, err := s.broadcastRepo.GetByID(ctx, id)
+	if err == gorm.ErrRecordNotFound {
+		return nil, ErrBroadcastNotFound
+	}
+	return broadcast, err
+}
+
+func (s *broadcastService) ListBroadcasts(ctx common.Context, page, pageSize int) ([]*model.Broadcast, int64, error) {
+	return s.broadcastRepo.List(ctx, (page-1)*pageSize, pageSize)
+}
+
+func (s *broadcastService) Cancel(ctx common.Context, id uint) error {
+	if _, err := s.GetBroadcast(ctx, id); err != nil {
+		return err
+	}
+
+	cancelled, err := s.broadcastRepo.Cancel(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return ErrBroadcastFinished
+	}
+	return nil
+}
+
+func (s *broadcastService) ListNotifications(ctx common.Context, userID uint, page, pageSize int) ([]*model.Notification, int64, error) {
+	return s.broadcastRepo.ListNotifications(ctx, userID, (page-1)*pageSize, pageSize)
+}
+
+func (s *broadcastService) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.runDue()
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *broadcastService) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+func (s *broadcastService) stopping() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// runDue 依次领取并执行所有到期的群发
+func (s *broadcastService) runDue() {
+	ctx := common.NewBackgroundContext(s.logger)
+
+	for !s.stopping() {
+		now := time.Now()
+		broadcast, err := s.broadcastRepo.ClaimDue(ctx, now, now.Add(-broadcastStaleTimeout))
+		if err != nil {
+			s.logger.Error("claim due broadcast failed", zap.Error(err))
+			return
+		}
+		if broadcast == nil {
+			return
+		}
+
+		s.run(ctx, broadcast)
+	}
+}
+
+func (s *broadcastService) run(ctx common.Context, broadcast *model.Broadcast) {
+	logger := s.logger.With(zap.Uint("broadcast_id", broadcast.ID))
+	logger.Info("broadcast started", zap.Uint("last_user_id", broadcast.LastUserID))
+
+	tagIDs, ok, err := s.tagService.ResolveSegment(ctx, broadcast.Tags, broadcast.Match)
+	if err != nil {
+		s.finish(ctx, logger, broadcast, model.BroadcastFailed, err)
+		return
+	}
+	if !ok {
+		s.finish(ctx, logger, broadcast, model.BroadcastCompleted, nil)
+		return
+	}
+
+	matchAll := broadcast.Match == "all"
+	// 断点续发时沿用首次执行时统计的总数
+	if broadcast.Processed == 0 {
+		if broadcast.Total, err = s.tagRepo.CountUsersByTags(ctx, tagIDs, matchAll); err != nil {
+			s.finish(ctx, logger, broadcast, model.BroadcastFailed, err)
+			return
+		}
+	}
+
+	for !s.stopping() {
+		users, err := s.tagRepo.ListUsersByTagsAfter(ctx, tagIDs, matchAll, broadcast.LastUserID, s.batchSize)
+		if err != nil {
+			s.finish(ctx, logger, broadcast, model.BroadcastFailed, err)
+			return
+		}
+		if len(users) == 0 {
+			s.finish(ctx, logger, broadcast, model.BroadcastCompleted, nil)
+			return
+		}
+
+		if err := s.deliver(ctx, broadcast, users); err != nil {
+			s.finish(ctx, logger, broadcast, model.BroadcastFailed, err)
+			return
+		}
+
+		broadcast.LastUserID = users[len(users)-1].ID
+		broadcast.Processed += int64(len(users))
+
+		running, err := s.broadcastRepo.SaveProgress(ctx, broadcast)
+		if err != nil {
+			s.finish(ctx, logger, broadcast, model.BroadcastFailed, err)
+			return
+		}
+		if !running {
+			logger.Info("broadcast cancelled", zap.Int64("processed", broadcast.Processed))
+			return
+		}
+	}
+
+	logger.Info("broadcast paused on shutdown", zap.Int64("processed", broadcast.Processed))
+}
+
+// deliver 向一批用户发送站内信和邮件，邮件发送失败只计数，不中断群发
+func (s *broadcastService) deliver(ctx common.Context, broadcast *model.Broadcast, users []*model.User) error {
+	channels := strings.Split(broadcast.Channels, ",")
+
+	if utils.Contains(channels, model.ChannelInApp) {
+		notifications := make([]*model.Notification, 0, len(users))
+		for _, user := range users {
+			notifications = append(notifications, &model.Notification{
+				UserID:      user.ID,
+				BroadcastID: broadcast.ID,
+				Title:       broadcast.Title,
+				Content:     broadcast.Content,
+			})
+		}
+		if err := s.broadcastRepo.CreateNotifications(ctx, notifications); err != nil {
+			return err
+		}
+	}
+
+	if utils.Contains(channels, model.ChannelEmail) && s.mailer != nil {
+		var sent, failed int64
+		_ = pool.ForEach(context.Background(), users, broadcastMailConcurrency, func(stdCtx context.Context, user *model.User) error {
+			if user.Email == "" {
+				return nil
+			}
+
+			if err := s.mailer.Send(stdCtx, user.Email, broadcast.Title, broadcast.Content); err != nil {
+				atomic.AddInt64(&failed, 1)
+				s.logger.Warn("send broadcast email failed",
+					zap.Uint("broadcast_id", broadcast.ID),
+					zap.Uint("user_id", user.ID),
+					zap.Error(err),
+				)
+				return nil
+			}
+			atomic.AddInt64(&sent, 1)
+			return nil
+		})
+
+		broadcast.EmailSent += sent
+		broadcast.EmailFailed += failed
+	}
+
+	return nil
+}
+
+func (s *broadcastService) finish(ctx common.Context, logger *zap.Logger, broadcast *model.Broadcast, status string, cause error) {
+	var errMsg string
+	if cause != nil {
+		// error 列长度为 512 个字符
+		if runes := []rune(cause.Error()); len(runes) > 512 {
+			errMsg = string(runes[:512])
+		} else {
+			errMsg = string(runes)
+		}
+		logger.Error("broadcast failed", zap.Error(cause))
+	} else {
+		logger.Info("broadcast completed",
+			zap.Int64("processed", broadcast.Processed),
+			zap.Int64("email_sent", broadcast.EmailSent),
+			zap.Int64("email_failed", broadcast.EmailFailed),
+		)
+	}
+
+	// 先写入最终进度，再结束群发
+	if _, err := s.broadcastRepo.SaveProgress(ctx, broadcast); err != nil {
+		logger.Error("save broadcast progress failed", zap.Error(err))
+	}
+	if err := s.broadcastRepo.Finish(ctx, broadcast.ID, status, errMsg); err != nil {
+		logger.Error("finish broadcast failed", zap.Error(err))
+	}
+}
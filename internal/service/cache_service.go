@@ -0,0 +1,89 @@
+package service
+
+import (
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/redis"
+	"gin-app-start/pkg/errors"
+)
+
+// cachePreviewLimit 查看 list/zset 内容时最多返回的元素个数，避免大 key 拖垮接口
+const cachePreviewLimit = 100
+
+var ErrCacheKeyNotFound = errors.New("Cache key does not exist")
+
+var _ CacheService = (*cacheService)(nil)
+
+// CacheService 缓存运维，替代线上直接使用 redis-cli 操作
+type CacheService interface {
+	// Inspect 查看缓存键的类型、剩余过期时间和内容
+	Inspect(ctx common.Context, key string) (*dto.CacheKeyResponse, error)
+	// Delete 按完整键名删除缓存，返回键是否存在
+	Delete(ctx common.Context, key string) (bool, error)
+}
+
+type cacheService struct {
+	redisCache redis.RedisRepository
+}
+
+func NewCacheService(redisCache redis.RedisRepository) CacheService {
+	return &cacheService{
+		redisCache: redisCache,
+	}
+}
+
+func (s *cacheService) Inspect(ctx common.Context, key string) (*dto.CacheKeyResponse, error) {
+	keyType, err := s.redisCache.Type(key)
+	if err != nil {
+		return nil, err
+	}
+	if keyType == "none" {
+		return nil, ErrCacheKeyNotFound
+	}
+
+	ttl, err := s.redisCache.TTL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.CacheKeyResponse{
+		Key:  key,
+		Type: keyType,
+		TTL:  int64(ttl / time.Second),
+	}
+	if ttl < 0 {
+		res.TTL = -1
+	}
+
+	switch keyType {
+	case "string":
+		res.Value, err = s.redisCache.Get(key, redis.WithTrace(ctx.Trace()))
+	case "hash":
+		res.Value, err = s.redisCache.HashGetAll(key)
+	case "list":
+		res.Value, err = s.redisCache.ListLRange(key, 0, cachePreviewLimit-1)
+	case "set":
+		res.Value, err = s.redisCache.SetSMembers(key)
+	case "zset":
+		res.Value, err = s.redisCache.SetZRange(key, 0, cachePreviewLimit-1)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *cacheService) Delete(ctx common.Context, key string) (bool, error) {
+	exists, err := s.redisCache.Exists(key)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	if err := s.redisCache.Delete(key, redis.WithTrace(ctx.Trace())); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"gin-app-start/pkg/database"
+	"gin-app-start/pkg/errors"
+)
+
+var (
+	ErrUserExists  = errors.New("User already exists")
+	ErrEmailExists = errors.New("Email already exists")
+	ErrPhoneExists = errors.New("Phone already exists")
+
+	ErrUserNotFound      = errors.New("User not found")
+	ErrInviteCodeInvalid = errors.New("Invitation code is invalid")
+
+	ErrTagNotFound = errors.New("Tag not found")
+
+	ErrBroadcastNotFound    = errors.New("Broadcast not found")
+	ErrBroadcastFinished    = errors.New("Broadcast has already finished")
+	ErrBroadcastTimeInvalid = errors.New("Broadcast must be scheduled in the future")
+
+	ErrOrderNotFound    = errors.New("Order not found")
+	ErrOrderForbidden   = errors.New("Order does not belong to current user")
+	ErrOrderTypeInvalid = errors.New("Order type is not configured")
+	// ErrOrderUserNotFound 管理员代为下单的用户不存在
+	ErrOrderUserNotFound = errors.New("User to create the order for not found")
+	// ErrOrderNumberConflict 多次重新生成订单号后仍与已有订单重复，通常是订单号随机位数过少
+	ErrOrderNumberConflict = errors.New("Could not generate a unique order number, please retry")
+	ErrOrderItemNotFound   = errors.New("Order item not found")
+	// ErrOrderTotalComputed 订单已有明细时总价按明细计算，不能直接修改
+	ErrOrderTotalComputed = errors.New("Order total is computed from its items")
+
+	ErrProductNotFound = errors.New("Product not found")
+	ErrProductExists   = errors.New("Product already exists")
+	// ErrProductOutOfStock 加入订单的数量超过商品库存
+	ErrProductOutOfStock = errors.New("Insufficient stock for the product")
+
+	ErrShipmentNotFound = errors.New("Shipment not found")
+	ErrShipmentExists   = errors.New("Tracking number already registered for this carrier")
+
+	ErrWishlistFull = errors.New("Wishlist is full")
+
+	ErrLeaderboardNotFound = errors.New("Leaderboard not found")
+
+	ErrStoreNotFound = errors.New("Store not found")
+
+	ErrProjectionDisabled = errors.New("Order projection is not enabled")
+
+	ErrOrgNotFound       = errors.New("Organization not found")
+	ErrOrgForbidden      = errors.New("Not allowed in this organization")
+	ErrOrgMemberNotFound = errors.New("Organization member not found")
+	ErrOrgLastOwner      = errors.New("Organization must keep at least one owner")
+	ErrInvitationInvalid = errors.New("Invitation is invalid, expired or already accepted")
+
+	ErrAPIKeyInvalid  = errors.New("API key is invalid or revoked")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrAPIKeyLimit    = errors.New("Too many API keys")
+)
+
+// userUniqueIndexes 用户表唯一索引与业务错误的映射，索引名见 model.User 的 gorm 标签
+var userUniqueIndexes = map[string]error{
+	"uk_users_username": ErrUserExists,
+	"uk_users_email":    ErrEmailExists,
+	"uk_users_phone":    ErrPhoneExists,
+}
+
+// translateUserConflict 将用户表的唯一约束冲突转换为对应的业务错误
+// 读后写的校验存在竞态，最终以数据库的唯一索引为准
+func translateUserConflict(err error) error {
+	constraint, ok := database.UniqueViolation(err)
+	if !ok {
+		return err
+	}
+
+	if bizErr, ok := userUniqueIndexes[constraint]; ok {
+		return bizErr
+	}
+	return ErrUserExists
+}
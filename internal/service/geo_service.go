@@ -0,0 +1,353 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/httpcache"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/redis"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/geo"
+	"gin-app-start/pkg/logger"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Redis GEO 索引的键，成员为门店ID/订单ID
+const (
+	geoStoresKey = "geo:stores"
+	geoOrdersKey = "geo:orders"
+)
+
+// geoRebuildBatch 重建 GEO 索引时每批写入的位置数
+const geoRebuildBatch = 500
+
+var _ GeoService = (*geoService)(nil)
+
+// GeoService 门店和订单收货地的附近查询
+//
+// 坐标保存在数据库中，同时写入 Redis GEO 索引，附近查询使用 GEOSEARCH；
+// Redis 被禁用时退化为数据库按经纬度矩形粗筛后计算距离。
+// 索引写入失败只记录日志，Redis 数据丢失时通过 Rebuild 从数据库重建。
+// 门店变更后失效附近门店接口的响应缓存。
+type GeoService interface {
+	CreateStore(ctx common.Context, req *dto.CreateStoreRequest) (*model.Store, error)
+	DeleteStore(ctx common.Context, id uint) error
+	NearbyStores(ctx common.Context, query *dto.NearbyQuery) ([]*dto.NearbyStoreResponse, error)
+	NearbyOrders(ctx common.Context, query *dto.NearbyQuery) ([]*dto.NearbyOrderResponse, error)
+
+	// OrderLocated / OrderRemoved 订单创建和删除时更新 GEO 索引，没有收货地坐标的订单忽略
+	OrderLocated(ctx common.Context, order *model.Order)
+	OrderRemoved(ctx common.Context, order *model.Order)
+
+	// Rebuild 从数据库重建门店和订单的 GEO 索引
+	Rebuild(ctx common.Context) (*dto.GeoRebuildResponse, error)
+}
+
+type geoService struct {
+	storeRepo  repository.StoreRepository
+	orderRepo  repository.OrderRepository
+	redisCache redis.RedisRepository
+	responses  *httpcache.Cache
+}
+
+// NewGeoService responses 为 nil 时表示未启用响应缓存
+func NewGeoService(storeRepo repository.StoreRepository, orderRepo repository.OrderRepository, redisCache redis.RedisRepository, responses *httpcache.Cache) GeoService {
+	return &geoService{
+		storeRepo:  storeRepo,
+		orderRepo:  orderRepo,
+		redisCache: redisCache,
+		responses:  responses,
+	}
+}
+
+// nearby 附近查询的结果，ID 为门店ID或订单ID
+type nearby struct {
+	ID       uint
+	Distance float64
+}
+
+// nearbyDefaults 补全查询的默认半径和条数
+func nearbyDefaults(query *dto.NearbyQuery) (float64, int) {
+	radius, limit := query.Radius, query.Limit
+	if radius <= 0 {
+		radius = 5
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	return radius, limit
+}
+
+// search 在 GEO 索引中查询附近的成员，Redis 被禁用时返回 redis.ErrDisabled
+func (s *geoService) search(key string, query *dto.NearbyQuery) ([]nearby, error) {
+	radius, limit := nearbyDefaults(query)
+	locations, err := s.redisCache.GeoSearch(key, *query.Longitude, *query.Latitude, radius, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]nearby, 0, len(locations))
+	for _, location := range locations {
+		id, err := strconv.ParseUint(location.Name, 10, 64)
+		if err != nil {
+			continue
+		}
+		res = append(res, nearby{ID: uint(id), Distance: location.Dist})
+	}
+	return res, nil
+}
+
+// within 从数据库粗筛的结果中计算距离，过滤掉半径外的位置，按距离升序取前 limit 个
+func within(query *dto.NearbyQuery, ids []uint, coords [][2]float64) []nearby {
+	radius, limit := nearbyDefaults(query)
+
+	res := make([]nearby, 0, len(ids))
+	for i, id := range ids {
+		d := geo.Distance(*query.Latitude, *query.Longitude, coords[i][0], coords[i][1])
+		if d <= radius {
+			res = append(res, nearby{ID: id, Distance: d})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Distance < res[j].Distance })
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
+
+func (s *geoService) boundingBox(query *dto.NearbyQuery) geo.Box {
+	radius, _ := nearbyDefaults(query)
+	return geo.BoundingBox(*query.Latitude, *query.Longitude, radius)
+}
+
+func (s *geoService) index(ctx common.Context, key string, id uint, latitude, longitude float64) {
+	err := s.redisCache.GeoAdd(key, &goredis.GeoLocation{
+		Name:      strconv.FormatUint(uint64(id), 10),
+		Latitude:  latitude,
+		Longitude: longitude,
+	})
+	if err != nil {
+		logger.Module(ctx.Logger(), "service").Warn("geo index update failed",
+			zap.String("key", key),
+			zap.Uint("id", id),
+			zap.Error(err),
+		)
+	}
+}
+
+// storesChanged 门店变更后失效附近门店的响应缓存，失败时旧响应最多保留到缓存过期
+func (s *geoService) storesChanged(ctx common.Context) {
+	if err := s.responses.Invalidate(httpcache.Stores); err != nil {
+		logger.Module(ctx.Logger(), "service").Warn("invalidate response cache failed",
+			zap.String("cache", httpcache.Stores),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *geoService) unindex(ctx common.Context, key string, id uint) {
+	if err := s.redisCache.SetZRem(key, strconv.FormatUint(uint64(id), 10)); err != nil {
+		logger.Module(ctx.Logger(), "service").Warn("geo index remove failed",
+			zap.String("key", key),
+			zap.Uint("id", id),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *geoService) CreateStore(ctx common.Context, req *dto.CreateStoreRequest) (*model.Store, error) {
+	store := &model.Store{
+		Name:      req.Name,
+		Address:   req.Address,
+		Latitude:  *req.Latitude,
+		Longitude: *req.Longitude,
+	}
+	if err := s.storeRepo.Create(ctx, store); err != nil {
+		return nil, err
+	}
+
+	s.index(ctx, geoStoresKey, store.ID, store.Latitude, store.Longitude)
+	s.storesChanged(ctx)
+	return store, nil
+}
+
+func (s *geoService) DeleteStore(ctx common.Context, id uint) error {
+	if _, err := s.storeRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrStoreNotFound
+		}
+		return err
+	}
+
+	if err := s.storeRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.unindex(ctx, geoStoresKey, id)
+	s.storesChanged(ctx)
+	return nil
+}
+
+func (s *geoService) NearbyStores(ctx common.Context, query *dto.NearbyQuery) ([]*dto.NearbyStoreResponse, error) {
+	found, err := s.search(geoStoresKey, query)
+	if errors.Is(err, redis.ErrDisabled) {
+		var stores []*model.Store
+		stores, err = s.storeRepo.ListWithin(ctx, s.boundingBox(query))
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]uint, 0, len(stores))
+		coords := make([][2]float64, 0, len(stores))
+		for _, store := range stores {
+			ids = append(ids, store.ID)
+			coords = append(coords, [2]float64{store.Latitude, store.Longitude})
+		}
+		found = within(query, ids, coords)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(found))
+	for _, item := range found {
+		ids = append(ids, item.ID)
+	}
+	stores, err := s.storeRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*model.Store, len(stores))
+	for _, store := range stores {
+		byID[store.ID] = store
+	}
+
+	// 按距离顺序输出，索引中残留的已删除门店跳过
+	res := make([]*dto.NearbyStoreResponse, 0, len(found))
+	for _, item := range found {
+		store, ok := byID[item.ID]
+		if !ok {
+			continue
+		}
+		res = append(res, &dto.NearbyStoreResponse{
+			StoreResponse: *dto.NewStoreResponse(store),
+			Distance:      item.Distance,
+		})
+	}
+	return res, nil
+}
+
+func (s *geoService) NearbyOrders(ctx common.Context, query *dto.NearbyQuery) ([]*dto.NearbyOrderResponse, error) {
+	found, err := s.search(geoOrdersKey, query)
+	if errors.Is(err, redis.ErrDisabled) {
+		var orders []*model.Order
+		orders, err = s.orderRepo.ListLocatedWithin(ctx, s.boundingBox(query))
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]uint, 0, len(orders))
+		coords := make([][2]float64, 0, len(orders))
+		for _, order := range orders {
+			ids = append(ids, order.ID)
+			coords = append(coords, [2]float64{*order.Latitude, *order.Longitude})
+		}
+		found = within(query, ids, coords)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(found))
+	for _, item := range found {
+		ids = append(ids, item.ID)
+	}
+	orders, err := s.orderRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*model.Order, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+	}
+
+	// 按距离顺序输出，索引中残留的已删除订单跳过
+	res := make([]*dto.NearbyOrderResponse, 0, len(found))
+	for _, item := range found {
+		order, ok := byID[item.ID]
+		if !ok {
+			continue
+		}
+		res = append(res, &dto.NearbyOrderResponse{
+			OrderResponse: *dto.NewOrderResponse(order),
+			Distance:      item.Distance,
+		})
+	}
+	return res, nil
+}
+
+func (s *geoService) OrderLocated(ctx common.Context, order *model.Order) {
+	if order.Latitude == nil || order.Longitude == nil {
+		return
+	}
+	s.index(ctx, geoOrdersKey, order.ID, *order.Latitude, *order.Longitude)
+}
+
+func (s *geoService) OrderRemoved(ctx common.Context, order *model.Order) {
+	if order.Latitude == nil || order.Longitude == nil {
+		return
+	}
+	s.unindex(ctx, geoOrdersKey, order.ID)
+}
+
+// Rebuild 先删除旧索引再逐批写入，重建期间附近查询的结果可能不完整
+func (s *geoService) Rebuild(ctx common.Context) (*dto.GeoRebuildResponse, error) {
+	res := &dto.GeoRebuildResponse{}
+
+	if err := s.redisCache.Delete(geoStoresKey); err != nil {
+		return nil, err
+	}
+	err := s.storeRepo.Each(ctx, geoRebuildBatch, func(stores []*model.Store) error {
+		locations := make([]*goredis.GeoLocation, 0, len(stores))
+		for _, store := range stores {
+			locations = append(locations, &goredis.GeoLocation{
+				Name:      strconv.FormatUint(uint64(store.ID), 10),
+				Latitude:  store.Latitude,
+				Longitude: store.Longitude,
+			})
+		}
+		res.Stores += len(locations)
+		return s.redisCache.GeoAdd(geoStoresKey, locations...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.storesChanged(ctx)
+
+	if err := s.redisCache.Delete(geoOrdersKey); err != nil {
+		return nil, err
+	}
+	err = s.orderRepo.EachLocated(ctx, geoRebuildBatch, func(orders []*model.Order) error {
+		locations := make([]*goredis.GeoLocation, 0, len(orders))
+		for _, order := range orders {
+			locations = append(locations, &goredis.GeoLocation{
+				Name:      strconv.FormatUint(uint64(order.ID), 10),
+				Latitude:  *order.Latitude,
+				Longitude: *order.Longitude,
+			})
+		}
+		res.Orders += len(locations)
+		return s.redisCache.GeoAdd(geoOrdersKey, locations...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
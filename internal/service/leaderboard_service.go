@@ -0,0 +1,216 @@
+package service
+
+import (
+	"strconv"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/redis"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/hashid"
+	"gin-app-start/pkg/logger"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// 排行榜名称
+const (
+	LeaderboardTopSpenders = "top_spenders" // 消费总额
+	LeaderboardMostActive  = "most_active"  // 订单数
+)
+
+// leaderboardRebuildBatch 重建排行榜时每次写入 Redis 的成员数
+const leaderboardRebuildBatch = 500
+
+var _ LeaderboardService = (*leaderboardService)(nil)
+
+// LeaderboardService 用户排行榜，保存在 Redis 有序集合 leaderboard:{name} 中，成员为用户ID
+//
+// 排行榜随订单创建、删除和改价增量更新，更新失败只记录日志，不影响订单操作；
+// Redis 数据丢失或与订单不一致时通过 Rebuild 从订单表重新计算。
+type LeaderboardService interface {
+	OrderCreated(ctx common.Context, order *model.Order)
+	OrderDeleted(ctx common.Context, order *model.Order)
+	OrderRepriced(ctx common.Context, order *model.Order, oldPrice float64)
+
+	// Get 排行榜前 limit 名，以及 actor 的名次(不在榜上时为 nil)
+	Get(ctx common.Context, name string, actor Actor, limit int) (*dto.LeaderboardResponse, error)
+	// Rebuild 从订单表重新计算排行榜，返回上榜用户数
+	Rebuild(ctx common.Context, name string) (int, error)
+}
+
+type leaderboardService struct {
+	orderRepo  repository.OrderRepository
+	userRepo   repository.UserRepository
+	redisCache redis.RedisRepository
+}
+
+func NewLeaderboardService(orderRepo repository.OrderRepository, userRepo repository.UserRepository, redisCache redis.RedisRepository) LeaderboardService {
+	return &leaderboardService{
+		orderRepo:  orderRepo,
+		userRepo:   userRepo,
+		redisCache: redisCache,
+	}
+}
+
+// leaderboardScores 每个排行榜从用户订单汇总中取分数的方式
+var leaderboardScores = map[string]func(stat *repository.UserOrderStat) float64{
+	LeaderboardTopSpenders: func(stat *repository.UserOrderStat) float64 { return stat.Spent },
+	LeaderboardMostActive:  func(stat *repository.UserOrderStat) float64 { return float64(stat.Orders) },
+}
+
+func (s *leaderboardService) getLeaderboardKey(name string) string {
+	return "leaderboard:" + name
+}
+
+func (s *leaderboardService) OrderCreated(ctx common.Context, order *model.Order) {
+	s.incr(ctx, LeaderboardTopSpenders, order.UserID, order.TotalPrice)
+	s.incr(ctx, LeaderboardMostActive, order.UserID, 1)
+}
+
+func (s *leaderboardService) OrderDeleted(ctx common.Context, order *model.Order) {
+	s.incr(ctx, LeaderboardTopSpenders, order.UserID, -order.TotalPrice)
+	s.incr(ctx, LeaderboardMostActive, order.UserID, -1)
+}
+
+func (s *leaderboardService) OrderRepriced(ctx common.Context, order *model.Order, oldPrice float64) {
+	if order.TotalPrice != oldPrice {
+		s.incr(ctx, LeaderboardTopSpenders, order.UserID, order.TotalPrice-oldPrice)
+	}
+}
+
+// incr 增量更新排行榜，未记录 user_id 的旧订单不计入
+func (s *leaderboardService) incr(ctx common.Context, name string, userID uint, delta float64) {
+	if userID == 0 {
+		return
+	}
+
+	member := strconv.FormatUint(uint64(userID), 10)
+	if _, err := s.redisCache.SetZIncrBy(s.getLeaderboardKey(name), member, delta); err != nil {
+		logger.Module(ctx.Logger(), "service").Warn("leaderboard update failed",
+			zap.String("leaderboard", name),
+			zap.Uint("user_id", userID),
+			zap.Error(err),
+		)
+	}
+}
+
+func (s *leaderboardService) Get(ctx common.Context, name string, actor Actor, limit int) (*dto.LeaderboardResponse, error) {
+	if _, ok := leaderboardScores[name]; !ok {
+		return nil, ErrLeaderboardNotFound
+	}
+	key := s.getLeaderboardKey(name)
+
+	members, err := s.redisCache.SetZRevRangeWithScores(key, 0, int64(limit-1))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.LeaderboardResponse{
+		Name:    name,
+		Entries: make([]*dto.LeaderboardEntry, 0, len(members)),
+	}
+	ids := make([]uint, 0, len(members))
+	for i, member := range members {
+		id, err := strconv.ParseUint(member.Member.(string), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
+		res.Entries = append(res.Entries, &dto.LeaderboardEntry{
+			Rank:   int64(i + 1),
+			UserID: hashid.ID(id),
+			Score:  member.Score,
+		})
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	usernames := make(map[uint]string, len(users))
+	for _, user := range users {
+		usernames[user.ID] = user.Username
+	}
+	for _, entry := range res.Entries {
+		entry.Username = usernames[uint(entry.UserID)]
+	}
+
+	res.Me, err = s.rankOf(key, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if res.Me != nil {
+		res.Me.Username = actor.Username
+	}
+	return res, nil
+}
+
+// rankOf 用户的名次和分数，不在榜上(或 Redis 被禁用)时返回 nil
+func (s *leaderboardService) rankOf(key string, userID uint) (*dto.LeaderboardEntry, error) {
+	member := strconv.FormatUint(uint64(userID), 10)
+
+	rank, err := s.redisCache.SetZRevRank(key, member)
+	if errors.Is(err, redis.ErrMemberNotExist) || errors.Is(err, redis.ErrDisabled) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := s.redisCache.SetZScore(key, member)
+	if errors.Is(err, redis.ErrMemberNotExist) {
+		// 两次查询之间被移出排行榜
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LeaderboardEntry{
+		Rank:   rank + 1,
+		UserID: hashid.ID(userID),
+		Score:  score,
+	}, nil
+}
+
+// Rebuild 先删除旧的排行榜再逐批写入，重建期间读取到的排行榜可能不完整
+func (s *leaderboardService) Rebuild(ctx common.Context, name string) (int, error) {
+	scoreOf, ok := leaderboardScores[name]
+	if !ok {
+		return 0, ErrLeaderboardNotFound
+	}
+
+	stats, err := s.orderRepo.UserOrderStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	key := s.getLeaderboardKey(name)
+	if err := s.redisCache.Delete(key); err != nil {
+		return 0, err
+	}
+
+	members := make([]goredis.Z, 0, leaderboardRebuildBatch)
+	for _, stat := range stats {
+		members = append(members, goredis.Z{
+			Score:  scoreOf(stat),
+			Member: strconv.FormatUint(uint64(stat.UserID), 10),
+		})
+		if len(members) == leaderboardRebuildBatch {
+			if err := s.redisCache.SetZAdd(key, members...); err != nil {
+				return 0, err
+			}
+			members = members[:0]
+		}
+	}
+	if len(members) > 0 {
+		if err := s.redisCache.SetZAdd(key, members...); err != nil {
+			return 0, err
+		}
+	}
+	return len(stats), nil
+}
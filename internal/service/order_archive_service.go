@@ -0,0 +1,137 @@
+package service
+
+import (
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultArchiveAfterMonths = 12
+	defaultArchiveInterval    = time.Hour
+	defaultArchiveBatchSize   = 500
+)
+
+var _ OrderArchiveService = (*orderArchiveService)(nil)
+
+// OrderArchiveService 定时把创建时间超过 after_months 个月的订单移入 orders_archive
+//
+// 每批订单在一个事务中写入归档表并从订单表删除，中途失败的批次整体回滚，下次继续；
+// 订单ID保持不变，发货记录等按订单ID关联的数据不需要迁移。
+type OrderArchiveService interface {
+	// ArchiveOnce 归档全部到期的订单，返回移动的订单数
+	ArchiveOnce(ctx common.Context) (int, error)
+
+	// Start 启动后台归档任务
+	Start()
+	// Stop 停止后台归档任务，正在归档时在当前批次结束后停止
+	Stop()
+}
+
+type orderArchiveService struct {
+	archiveRepo  repository.OrderArchiveRepository
+	orderService OrderService
+	afterMonths  int
+	interval     time.Duration
+	batchSize    int
+	logger       *zap.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewOrderArchiveService(archiveRepo repository.OrderArchiveRepository, orderService OrderService, cfg config.ArchiveConfig, logger *zap.Logger) OrderArchiveService {
+	afterMonths := cfg.AfterMonths
+	if afterMonths <= 0 {
+		afterMonths = defaultArchiveAfterMonths
+	}
+	interval := time.Duration(cfg.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultArchiveInterval
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultArchiveBatchSize
+	}
+
+	return &orderArchiveService{
+		archiveRepo:  archiveRepo,
+		orderService: orderService,
+		afterMonths:  afterMonths,
+		interval:     interval,
+		batchSize:    batchSize,
+		logger:       logger,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+func (s *orderArchiveService) ArchiveOnce(ctx common.Context) (int, error) {
+	before := time.Now().AddDate(0, -s.afterMonths, 0)
+
+	archived := 0
+	for !s.stopping() {
+		orders, err := s.archiveRepo.ArchiveBefore(ctx, before, s.batchSize)
+		if err != nil {
+			return archived, err
+		}
+		if len(orders) == 0 {
+			break
+		}
+
+		s.orderService.OrdersArchived(ctx, orders)
+		archived += len(orders)
+	}
+	return archived, nil
+}
+
+func (s *orderArchiveService) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.run()
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *orderArchiveService) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+func (s *orderArchiveService) stopping() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *orderArchiveService) run() {
+	ctx := common.NewBackgroundContext(s.logger)
+
+	start := time.Now()
+	archived, err := s.ArchiveOnce(ctx)
+	if err != nil {
+		s.logger.Error("archive orders failed, will retry", zap.Int("archived", archived), zap.Error(err))
+		return
+	}
+	if archived > 0 {
+		s.logger.Info("orders archived", zap.Int("orders", archived), zap.Int("after_months", s.afterMonths), zap.Duration("cost", time.Since(start)))
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"sort"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// ListOrderItems 订单的全部明细，订单的查看权限同 GetOrderByOrderNumber
+func (s *orderService) ListOrderItems(ctx common.Context, actor Actor, orderNumber string) (*model.Order, []*model.OrderItem, error) {
+	order, err := s.authorizedOrder(ctx, actor, orderNumber, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := s.itemRepo.ListByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, items, nil
+}
+
+// AddOrderItem 向订单加入商品，记录商品当前的名称和单价，并在同一事务中扣减库存
+func (s *orderService) AddOrderItem(ctx common.Context, actor Actor, orderNumber string, req *dto.AddOrderItemRequest) (*model.Order, []*model.OrderItem, error) {
+	order, err := s.authorizedOrder(ctx, actor, orderNumber, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var total float64
+	err = s.orderRepo.Transaction(ctx, func(ctx common.Context) error {
+		if err := s.itemRepo.LockOrder(ctx, order.ID); err != nil {
+			return err
+		}
+		total, err = s.addItem(ctx, order.ID, req)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrOrderNotFound
+		}
+		return nil, nil, err
+	}
+	return s.orderItemsChanged(ctx, order, total)
+}
+
+// UpdateOrderItem 修改明细的数量，按数量的变化扣减或退回库存
+func (s *orderService) UpdateOrderItem(ctx common.Context, actor Actor, orderNumber string, itemID uint, req *dto.UpdateOrderItemRequest) (*model.Order, []*model.OrderItem, error) {
+	order, err := s.authorizedOrder(ctx, actor, orderNumber, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var total float64
+	err = s.orderRepo.Transaction(ctx, func(ctx common.Context) error {
+		if err := s.itemRepo.LockOrder(ctx, order.ID); err != nil {
+			return err
+		}
+		item, err := s.itemRepo.Get(ctx, order.ID, itemID)
+		if err != nil {
+			return err
+		}
+		if err := s.adjustStock(ctx, item.ProductID, item.Quantity-req.Quantity); err != nil {
+			return err
+		}
+		total, err = s.itemRepo.SetQuantity(ctx, order.ID, itemID, req.Quantity)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrOrderItemNotFound
+		}
+		return nil, nil, err
+	}
+	return s.orderItemsChanged(ctx, order, total)
+}
+
+// RemoveOrderItem 删除明细并退回库存，删除最后一条明细后订单总价为 0
+func (s *orderService) RemoveOrderItem(ctx common.Context, actor Actor, orderNumber string, itemID uint) (*model.Order, []*model.OrderItem, error) {
+	order, err := s.authorizedOrder(ctx, actor, orderNumber, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var total float64
+	err = s.orderRepo.Transaction(ctx, func(ctx common.Context) error {
+		if err := s.itemRepo.LockOrder(ctx, order.ID); err != nil {
+			return err
+		}
+		item, err := s.itemRepo.Get(ctx, order.ID, itemID)
+		if err != nil {
+			return err
+		}
+		if err := s.adjustStock(ctx, item.ProductID, item.Quantity); err != nil {
+			return err
+		}
+		total, err = s.itemRepo.Remove(ctx, order.ID, itemID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrOrderItemNotFound
+		}
+		return nil, nil, err
+	}
+	return s.orderItemsChanged(ctx, order, total)
+}
+
+// addItem 扣减库存并加入明细，返回订单的新总价；需在事务中调用，库存和明细同时写入或同时回滚
+func (s *orderService) addItem(ctx common.Context, orderID uint, req *dto.AddOrderItemRequest) (float64, error) {
+	product, err := s.productRepo.GetBySKU(ctx, req.ProductID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrProductNotFound
+		}
+		return 0, err
+	}
+	if err := s.adjustStock(ctx, product.SKU, -req.Quantity); err != nil {
+		return 0, err
+	}
+
+	return s.itemRepo.Add(ctx, &model.OrderItem{
+		OrderID:   orderID,
+		ProductID: product.SKU,
+		Name:      product.Name,
+		UnitPrice: product.Price,
+		Quantity:  req.Quantity,
+	})
+}
+
+// adjustStock 按 delta 增减商品库存；退回库存时商品已删除则忽略
+func (s *orderService) adjustStock(ctx common.Context, sku string, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+
+	err := s.productRepo.AdjustStock(ctx, sku, delta)
+	switch {
+	case errors.Is(err, repository.ErrInsufficientStock):
+		return ErrProductOutOfStock
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if delta > 0 {
+			return nil
+		}
+		return ErrProductNotFound
+	}
+	return err
+}
+
+// sortedItems 按商品排序的明细副本，多个事务扣减多件商品的库存时按相同顺序加锁，避免互相等待形成死锁
+func sortedItems(items []*dto.AddOrderItemRequest) []*dto.AddOrderItemRequest {
+	sorted := append([]*dto.AddOrderItemRequest(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ProductID < sorted[j].ProductID
+	})
+	return sorted
+}
+
+// orderItemsChanged 明细修改后同步订单总价到排行榜、读模型和缓存，并返回订单的全部明细
+func (s *orderService) orderItemsChanged(ctx common.Context, order *model.Order, total float64) (*model.Order, []*model.OrderItem, error) {
+	oldPrice := order.TotalPrice
+	order.TotalPrice = total
+	s.leaderboard.OrderRepriced(ctx, order, oldPrice)
+	s.projection.OrderChanged(order)
+
+	// 保存订单到Redis, 设置订单缓存过期时间为30min
+	if err := s.cacheError(ctx, cacheOpOrderSave, s.SaveOrderInCache(ctx, order, orderCacheTTL)); err != nil {
+		return nil, nil, err
+	}
+
+	// 删除订单列表缓存
+	if err := s.cacheError(ctx, cacheOpOrderListInvalidate, s.DeleteOrderListCache(ctx)); err != nil {
+		return nil, nil, err
+	}
+
+	items, err := s.itemRepo.ListByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, items, nil
+}
+
+// checkTotalEditable 订单已有明细时总价按明细计算，拒绝直接修改总价
+func (s *orderService) checkTotalEditable(ctx common.Context, order *model.Order, req *dto.PatchOrderRequest) error {
+	if req.TotalPrice == nil {
+		return nil
+	}
+
+	count, err := s.itemRepo.CountByOrder(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrOrderTotalComputed
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/errors"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultProjectionFlushInterval = 500 * time.Millisecond
+	defaultProjectionBatchSize     = 1000
+)
+
+// errProjectionStopped 服务停止时中断重建
+var errProjectionStopped = errors.New("order projection stopped")
+
+var _ OrderProjectionService = (*orderProjectionService)(nil)
+
+// OrderProjectionService 维护订单列表读模型(order_summaries)
+//
+// 订单创建、修改、删除后只记录订单ID，后台任务按 flush_interval 合并后从订单表重新读取这些订单，
+// 写入或删除读模型记录。每次都以订单表的当前数据为准，事件重复或乱序不影响结果；
+// 实例崩溃丢失的变更通过 Rebuild 修复。读模型为空时启动后自动回填，回填完成前列表仍查询订单表。
+type OrderProjectionService interface {
+	OrderChanged(order *model.Order)
+	OrderRemoved(order *model.Order)
+
+	// ListOrders 从读模型分页查询全部订单，按创建时间倒序；ok 为 false 表示读模型未启用或尚未回填完成
+	ListOrders(ctx common.Context, offset, limit int) (orders []*model.Order, total int64, ok bool, err error)
+	// Rebuild 从订单表重新生成读模型，返回写入的订单数
+	Rebuild(ctx common.Context) (int, error)
+	// OnProjected 读模型更新后回调，用于清除基于读模型的缓存，需在 Start 之前设置
+	OnProjected(fn func(ctx common.Context))
+
+	// Start 启动后台任务，读模型为空时先回填
+	Start()
+	// Stop 停止后台任务，停止前写入所有未处理的变更
+	Stop()
+}
+
+type orderProjectionService struct {
+	orderRepo   repository.OrderRepository
+	summaryRepo repository.OrderSummaryRepository
+	enabled     bool
+	interval    time.Duration
+	batchSize   int
+	logger      *zap.Logger
+
+	mu      sync.Mutex
+	pending map[uint]struct{} // 待投影的订单ID
+
+	ready       atomic.Bool
+	rebuildMu   sync.Mutex
+	onProjected func(ctx common.Context)
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewOrderProjectionService 未启用时记录变更和查询都直接跳过，不需要启动后台任务
+func NewOrderProjectionService(orderRepo repository.OrderRepository, summaryRepo repository.OrderSummaryRepository, cfg config.ProjectionConfig, logger *zap.Logger) OrderProjectionService {
+	interval := time.Duration(cfg.FlushInterval) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultProjectionFlushInterval
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultProjectionBatchSize
+	}
+
+	return &orderProjectionService{
+		orderRepo:   orderRepo,
+		summaryRepo: summaryRepo,
+		enabled:     cfg.Enabled,
+		interval:    interval,
+		batchSize:   batchSize,
+		logger:      logger,
+		pending:     make(map[uint]struct{}),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+func (s *orderProjectionService) OrderChanged(order *model.Order) {
+	s.mark(order.ID)
+}
+
+func (s *orderProjectionService) OrderRemoved(order *model.Order) {
+	s.mark(order.ID)
+}
+
+func (s *orderProjectionService) mark(ids ...uint) {
+	if !s.enabled {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.pending[id] = struct{}{}
+	}
+}
+
+func (s *orderProjectionService) OnProjected(fn func(ctx common.Context)) {
+	s.onProjected = fn
+}
+
+func (s *orderProjectionService) projected(ctx common.Context) {
+	if s.onProjected != nil {
+		s.onProjected(ctx)
+	}
+}
+
+func (s *orderProjectionService) ListOrders(ctx common.Context, offset, limit int) ([]*model.Order, int64, bool, error) {
+	if !s.enabled || !s.ready.Load() {
+		return nil, 0, false, nil
+	}
+
+	summaries, total, err := s.summaryRepo.List(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, true, err
+	}
+
+	orders := make([]*model.Order, 0, len(summaries))
+	for _, summary := range summaries {
+		orders = append(orders, summary.Order())
+	}
+	return orders, total, true, nil
+}
+
+func (s *orderProjectionService) Rebuild(ctx common.Context) (int, error) {
+	if !s.enabled {
+		return 0, ErrProjectionDisabled
+	}
+
+	s.rebuildMu.Lock()
+	defer s.rebuildMu.Unlock()
+
+	// 重建期间读模型仍可查询，只是可能短暂落后；重建与后台写入并发时由 Upsert 按订单更新时间保留较新的记录，
+	// 重建读到后又被删除的订单在最后删除订单已不存在的记录时清除
+	projected := 0
+	err := s.orderRepo.Each(ctx, s.batchSize, func(orders []*model.Order, progress repository.BatchProgress) error {
+		select {
+		case <-s.stop:
+			return errProjectionStopped
+		default:
+		}
+
+		now := time.Now()
+		summaries := make([]*model.OrderSummary, 0, len(orders))
+		for _, order := range orders {
+			summaries = append(summaries, model.NewOrderSummary(order, now))
+		}
+		if err := s.summaryRepo.Upsert(ctx, summaries); err != nil {
+			return err
+		}
+		projected += len(orders)
+		ctx.Logger().Info("rebuild order projection",
+			zap.Int("batch", progress.Batch), zap.Int64("done", progress.Done), zap.Any("last_id", progress.LastKey))
+		return nil
+	})
+	if err != nil {
+		return projected, err
+	}
+
+	if _, err := s.summaryRepo.DeleteOrphans(ctx); err != nil {
+		return projected, err
+	}
+	s.ready.Store(true)
+	s.projected(ctx)
+	return projected, nil
+}
+
+func (s *orderProjectionService) Start() {
+	go func() {
+		defer close(s.done)
+
+		s.backfill()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				s.flush()
+				return
+			case <-ticker.C:
+				s.flush()
+			}
+		}
+	}()
+}
+
+func (s *orderProjectionService) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+// backfill 读模型为空时从订单表回填，读模型已有数据时直接使用
+func (s *orderProjectionService) backfill() {
+	ctx := common.NewBackgroundContext(s.logger)
+
+	count, err := s.summaryRepo.Count(ctx)
+	if err != nil {
+		s.logger.Error("count order summaries failed, order list falls back to orders table", zap.Error(err))
+		return
+	}
+	if count > 0 {
+		s.ready.Store(true)
+		return
+	}
+
+	start := time.Now()
+	projected, err := s.Rebuild(ctx)
+	if err != nil {
+		s.logger.Error("backfill order summaries failed, order list falls back to orders table", zap.Int("projected", projected), zap.Error(err))
+		return
+	}
+	s.logger.Info("order summaries backfilled", zap.Int("orders", projected), zap.Duration("cost", time.Since(start)))
+}
+
+// flush 把待投影的订单从订单表重新读取后写入读模型，查不到的订单(已删除)从读模型删除
+// 失败时订单ID放回待处理集合，下次重试
+func (s *orderProjectionService) flush() {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	ids := make([]uint, 0, len(s.pending))
+	for id := range s.pending {
+		ids = append(ids, id)
+	}
+	s.pending = make(map[uint]struct{})
+	s.mu.Unlock()
+
+	ctx := common.NewBackgroundContext(s.logger)
+	for start := 0; start < len(ids); start += s.batchSize {
+		end := min(start+s.batchSize, len(ids))
+		if err := s.project(ctx, ids[start:end]); err != nil {
+			s.logger.Error("project orders failed, will retry", zap.Int("orders", len(ids)-start), zap.Error(err))
+			s.mark(ids[start:]...)
+			s.projected(ctx)
+			return
+		}
+	}
+	s.projected(ctx)
+}
+
+func (s *orderProjectionService) project(ctx common.Context, ids []uint) error {
+	orders, err := s.orderRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	found := make(map[uint]bool, len(orders))
+	summaries := make([]*model.OrderSummary, 0, len(orders))
+	for _, order := range orders {
+		found[order.ID] = true
+		summaries = append(summaries, model.NewOrderSummary(order, now))
+	}
+	if err := s.summaryRepo.Upsert(ctx, summaries); err != nil {
+		return err
+	}
+
+	var removed []uint
+	for _, id := range ids {
+		if !found[id] {
+			removed = append(removed, id)
+		}
+	}
+	return s.summaryRepo.Delete(ctx, removed)
+}
@@ -1,49 +1,168 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
 	"gin-app-start/internal/dto"
+	"gin-app-start/internal/hooks"
+	"gin-app-start/internal/metrics"
 	"gin-app-start/internal/model"
 	"gin-app-start/internal/redis"
 	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/cache"
+	"gin-app-start/pkg/database"
 	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/logger"
+	"gin-app-start/pkg/pool"
+	"gin-app-start/pkg/queryfilter"
+	"gin-app-start/pkg/trace"
 	"gin-app-start/pkg/utils"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 var _ OrderService = (*orderService)(nil)
 
+// orderListWarmConcurrency 订单列表缓存预热的最大并发数
+const orderListWarmConcurrency = 4
+
+// orderStreamBatchSize 流式导出订单时每批从数据库读取的订单数
+const orderStreamBatchSize = 500
+
+const (
+	// orderListCachePattern 所有订单列表缓存键，键格式见 getOrderListCacheKey
+	orderListCachePattern = "order_list:*"
+	// orderListScanCount 查找订单列表缓存键时每次 SCAN 的 COUNT
+	orderListScanCount = 500
+)
+
+const (
+	// orderCacheTTL 订单缓存的过期时间
+	orderCacheTTL = 30 * time.Minute
+	// orderNotFoundCacheTTL 订单不存在标记的过期时间，较短以免订单创建前的查询长时间影响结果
+	orderNotFoundCacheTTL = time.Minute
+)
+
 type OrderService interface {
 	SaveOrderInCache(ctx common.Context, order *model.Order, expireTime time.Duration) error
 	SaveOrderListInCache(ctx common.Context, orders []*model.Order, total int64, username string, page, pageSize int, expireTime time.Duration) error
 	DeleteOrderListCache(ctx common.Context) error
-
-	CreateOrder(ctx common.Context, req *dto.CreateOrderRequest) (*model.Order, error)
-	GetOrderByOrderNumber(ctx common.Context, orderNumber string) (*model.Order, error)
-	UpdateOrderByOrderNumber(ctx common.Context, req *dto.UpdateOrderRequest) (*model.Order, error)
-	DeleteOrderByOrderNumber(ctx common.Context, orderNumber string) error
-	GetOrderByID(ctx common.Context, id uint) (*model.Order, error)
-	UpdateOrder(ctx common.Context, id uint, req *dto.UpdateOrderRequest) (*model.Order, error)
-	DeleteOrder(ctx common.Context, id uint) error
-	ListOrders(ctx common.Context, username string, page, pageSize int) ([]*model.Order, int64, error)
+	WarmOrderListCache(ctx common.Context) (int, error)
+
+	// CreateOrder 为 req.Username 创建订单，管理员为其他用户下单时订单归属该用户，用户不存在时返回 ErrOrderUserNotFound
+	CreateOrder(ctx common.Context, actor Actor, req *dto.CreateOrderRequest) (*model.Order, error)
+	GetOrderByOrderNumber(ctx common.Context, actor Actor, orderNumber string) (*model.Order, error)
+	BatchGetOrders(ctx common.Context, actor Actor, orderNumbers []string) ([]*model.Order, []string, error)
+	// UpdateOrderByOrderNumber 更新订单，请求中的零值表示不修改
+	UpdateOrderByOrderNumber(ctx common.Context, actor Actor, req *dto.UpdateOrderRequest) (*model.Order, error)
+	// PatchOrder 部分更新订单，只修改 req 中不为 nil 的字段
+	PatchOrder(ctx common.Context, actor Actor, orderNumber string, req *dto.PatchOrderRequest) (*model.Order, error)
+	DeleteOrderByOrderNumber(ctx common.Context, actor Actor, orderNumber string) error
+	GetOrderByID(ctx common.Context, actor Actor, id uint) (*model.Order, error)
+	UpdateOrder(ctx common.Context, actor Actor, id uint, req *dto.UpdateOrderRequest) (*model.Order, error)
+	DeleteOrder(ctx common.Context, actor Actor, id uint) error
+	// ListOrders 分页查询订单，带过滤条件时不经过缓存和读模型
+	ListOrders(ctx common.Context, username string, filter queryfilter.Query, page, pageSize int) ([]*model.Order, int64, error)
+	// ListOrdersAfter 游标分页查询订单，按ID升序返回ID大于 afterID 的一页，more 表示是否还有下一页；不经过缓存
+	ListOrdersAfter(ctx common.Context, username string, filter queryfilter.Query, afterID uint, pageSize int) (orders []*model.Order, more bool, err error)
+	AddOrderNote(ctx common.Context, actor Actor, req *dto.CreateOrderNoteRequest) (*model.OrderNote, error)
+
+	// ListOrderItems 订单的全部明细
+	ListOrderItems(ctx common.Context, actor Actor, orderNumber string) (*model.Order, []*model.OrderItem, error)
+	// AddOrderItem 加入明细，与下面两个方法一样在事务中重新计算订单总价，返回修改后的订单和全部明细
+	AddOrderItem(ctx common.Context, actor Actor, orderNumber string, req *dto.AddOrderItemRequest) (*model.Order, []*model.OrderItem, error)
+	UpdateOrderItem(ctx common.Context, actor Actor, orderNumber string, itemID uint, req *dto.UpdateOrderItemRequest) (*model.Order, []*model.OrderItem, error)
+	RemoveOrderItem(ctx common.Context, actor Actor, orderNumber string, itemID uint) (*model.Order, []*model.OrderItem, error)
+
+	// GetArchivedOrder 按订单号查询已归档的订单，不经过缓存
+	GetArchivedOrder(ctx common.Context, actor Actor, orderNumber string) (*model.Order, error)
+	// ListOrdersWithArchive 合并未归档和已归档的订单分页查询，不经过缓存
+	ListOrdersWithArchive(ctx common.Context, username string, page, pageSize int) ([]*model.Order, int64, error)
+	// SearchOrders 管理端按下单用户的邮箱、手机号查找订单，结果附带用户联系方式，不经过缓存
+	SearchOrders(ctx common.Context, search repository.OrderSearch, page, pageSize int) ([]*repository.OrderWithUser, int64, error)
+	// OrdersArchived 订单移入归档表后清除缓存和索引
+	OrdersArchived(ctx common.Context, orders []*model.Order)
+	// EachOrder 按ID顺序分批遍历用户的订单，不经过缓存，用于流式导出；username 为管理员时遍历全部订单
+	EachOrder(ctx common.Context, username string, fn func(orders []*model.Order) error) error
 }
 
+// 缓存操作名，对应配置 cache.operations 中的键
+const (
+	cacheOpOrderSave           = "order_save"
+	cacheOpOrderInvalidate     = "order_invalidate"
+	cacheOpOrderListSave       = "order_list_save"
+	cacheOpOrderListInvalidate = "order_list_invalidate"
+)
+
 type orderService struct {
 	orderRepo  repository.OrderRepository
+	userRepo   repository.UserRepository
 	redisCache redis.RedisRepository
+	cacheCfg   config.CacheConfig
+	numberCfg  config.OrderNumberConfig
+
+	leaderboard LeaderboardService
+	geo         GeoService
+	orgRepo     repository.OrganizationRepository
+	projection  OrderProjectionService
+	archiveRepo repository.OrderArchiveRepository
+	itemRepo    repository.OrderItemRepository
+	productRepo repository.ProductRepository
+	hooks       *hooks.Registry
+
+	// orders 按订单号的读穿透缓存，并发回源合并为一次，不存在的订单缓存空值标记
+	orders *cache.Cache
+	// hotLists 管理端订单列表的逻辑过期缓存，未启用时为 nil，按普通缓存处理
+	hotLists *repository.LogicalCache
 }
 
-func NewOrderService(orderRepo repository.OrderRepository, redisCache redis.RedisRepository) OrderService {
-	return &orderService{
-		orderRepo:  orderRepo,
-		redisCache: redisCache,
+// NewOrderService numberCfg 需先经过 OrderNumberConfig.Validate 校验
+func NewOrderService(orderRepo repository.OrderRepository, userRepo repository.UserRepository, redisCache redis.RedisRepository, cacheCfg config.CacheConfig, numberCfg config.OrderNumberConfig, leaderboard LeaderboardService, geo GeoService, orgRepo repository.OrganizationRepository, projection OrderProjectionService, archiveRepo repository.OrderArchiveRepository, itemRepo repository.OrderItemRepository, productRepo repository.ProductRepository, hookRegistry *hooks.Registry) OrderService {
+	s := &orderService{
+		orderRepo:   orderRepo,
+		userRepo:    userRepo,
+		redisCache:  redisCache,
+		cacheCfg:    cacheCfg,
+		numberCfg:   numberCfg,
+		leaderboard: leaderboard,
+		geo:         geo,
+		orgRepo:     orgRepo,
+		projection:  projection,
+		archiveRepo: archiveRepo,
+		itemRepo:    itemRepo,
+		productRepo: productRepo,
+		hooks:       hookRegistry,
+	}
+	s.orders = cache.New(repository.NewCacheStore(redisCache),
+		cache.WithNegativeTTL(orderNotFoundCacheTTL),
+		cache.WithObserver(func(ctx context.Context, key string, result cache.Result) {
+			var t common.Trace
+			if std, ok := ctx.(common.StdContext); ok {
+				t = std.Trace
+			}
+			observeCacheLookup(t, "order", key, string(result))
+		}),
+	)
+	if logical := cacheCfg.LogicalExpiry; logical.Enabled {
+		s.hotLists = repository.NewLogicalCache(redisCache, time.Duration(logical.TTL)*time.Second, time.Duration(logical.StaleTTL)*time.Second)
 	}
+
+	// 管理端订单列表缓存的是读模型的查询结果，读模型异步更新后需要再清除一次，否则会缓存更新前的列表
+	projection.OnProjected(func(ctx common.Context) {
+		if err := s.DeleteOrderListCache(ctx); err != nil && !errors.Is(err, redis.ErrUnavailable) {
+			logger.Module(ctx.Logger(), "service").Warn("invalidate order list cache after projection failed", zap.Error(err))
+		}
+	})
+	return s
 }
 
 func (s *orderService) getOrderCacheKey(orderNumber string) string {
@@ -54,20 +173,44 @@ func (s *orderService) getOrderListCacheKey(username string, page, pageSize int)
 	return fmt.Sprintf("order_list:%s:%d:%d", username, page, pageSize)
 }
 
-func (s *orderService) SaveOrderInCache(ctx common.Context, order *model.Order, expireTime time.Duration) error {
-	cacheKey := s.getOrderCacheKey(order.OrderNumber)
+// recordCacheLookup 记录缓存命中情况: 上报指标，并写入本次请求 trace 的调试信息
+func (s *orderService) recordCacheLookup(ctx common.Context, cache, key, result string) {
+	observeCacheLookup(ctx.Trace(), cache, key, result)
+}
 
-	data, err := json.MarshalIndent(order, "", "  ")
-	if err != nil {
-		return err
+func observeCacheLookup(t common.Trace, cache, key, result string) {
+	metrics.ObserveCacheLookup(cache, result)
+
+	if t, ok := t.(*trace.Trace); ok && t != nil {
+		t.AppendDebug(&trace.Debug{
+			Key:   "cache." + cache,
+			Value: map[string]string{"key": key, "result": result},
+		})
 	}
+}
 
-	if err := s.redisCache.SetWithExpire(cacheKey, string(data), expireTime, redis.WithTrace(ctx.Trace())); err != nil {
+// cacheError 处理缓存操作的错误: 可选操作只记录日志并返回 nil，必需操作原样返回
+func (s *orderService) cacheError(ctx common.Context, op string, err error) error {
+	if err == nil || !s.cacheCfg.IsOptional(op) {
 		return err
 	}
+
+	logger.Module(ctx.Logger(), "service").Warn("cache operation failed, skipped",
+		zap.String("operation", op),
+		zap.Error(err),
+	)
 	return nil
 }
 
+// saveOrderNotFound 缓存订单不存在的标记
+func (s *orderService) saveOrderNotFound(ctx common.Context, orderNumber string) error {
+	return s.orders.SetNotFound(ctx.RequestContext(), s.getOrderCacheKey(orderNumber))
+}
+
+func (s *orderService) SaveOrderInCache(ctx common.Context, order *model.Order, expireTime time.Duration) error {
+	return cache.Set(ctx.RequestContext(), s.orders, s.getOrderCacheKey(order.OrderNumber), order, expireTime)
+}
+
 // 保存订单列表到Redis缓存, 设置过期时间为expireTime
 func (s *orderService) SaveOrderListInCache(ctx common.Context, orders []*model.Order, total int64, username string, page, pageSize int, expireTime time.Duration) error {
 	cacheKey := s.getOrderListCacheKey(username, page, pageSize)
@@ -92,16 +235,28 @@ func (s *orderService) SaveOrderListInCache(ctx common.Context, orders []*model.
 	return nil
 }
 
-// 删除订单列表缓存
+// 删除订单列表缓存，使用逻辑过期的管理端列表只标记为过期
 func (s *orderService) DeleteOrderListCache(ctx common.Context) error {
-	pattern := "order_list:*"
-	redisCtx := s.redisCache.GetRedisContext()
-	keys, err := s.redisCache.GetRedisClient().Keys(redisCtx, pattern).Result()
+	if s.redisCache.GetRedisClient() == nil {
+		return redis.ErrUnavailable
+	}
+	// 没有使用逻辑过期的列表时直接按模式删除
+	if s.hotLists == nil {
+		_, err := s.redisCache.DeleteByPattern(orderListCachePattern, orderListScanCount)
+		return err
+	}
+	keys, err := s.redisCache.ScanKeys(orderListCachePattern, orderListScanCount)
 	if err != nil {
 		return err
 	}
 
 	for _, key := range keys {
+		if s.isHotList(key) {
+			if err := s.hotLists.Expire(key); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := s.redisCache.Delete(key, redis.WithTrace(ctx.Trace())); err != nil {
 			return err
 		}
@@ -109,198 +264,594 @@ func (s *orderService) DeleteOrderListCache(ctx common.Context) error {
 	return nil
 }
 
-func (s *orderService) CreateOrder(ctx common.Context, req *dto.CreateOrderRequest) (*model.Order, error) {
-	// 生成订单号
-	orderNumber := utils.GenerateOrderNumberWithPrefix("EC")
+// isHotList 订单列表缓存键是否使用逻辑过期
+func (s *orderService) isHotList(key string) bool {
+	if s.hotLists == nil {
+		return false
+	}
+	username, _, _, ok := s.parseOrderListCacheKey(key)
+	return ok && username == common.ADMIN_NAME
+}
+
+// parseOrderListCacheKey 解析订单列表缓存键，用户名中可能包含冒号，因此从右侧解析分页参数
+func (s *orderService) parseOrderListCacheKey(key string) (username string, page, pageSize int, ok bool) {
+	rest := strings.TrimPrefix(key, "order_list:")
+	if rest == key {
+		return "", 0, 0, false
+	}
+
+	i := strings.LastIndex(rest, ":")
+	if i < 0 {
+		return "", 0, 0, false
+	}
+	pageSize, err := strconv.Atoi(rest[i+1:])
+	if err != nil {
+		return "", 0, 0, false
+	}
+
+	rest = rest[:i]
+	i = strings.LastIndex(rest, ":")
+	if i < 0 {
+		return "", 0, 0, false
+	}
+	page, err = strconv.Atoi(rest[i+1:])
+	if err != nil {
+		return "", 0, 0, false
+	}
+
+	return rest[:i], page, pageSize, true
+}
+
+// 重新预热订单列表缓存: 对当前已缓存的每个列表重新查询数据库并写回缓存
+func (s *orderService) WarmOrderListCache(ctx common.Context) (int, error) {
+	if s.redisCache.GetRedisClient() == nil {
+		return 0, redis.ErrUnavailable
+	}
+	keys, err := s.redisCache.ScanKeys(orderListCachePattern, orderListScanCount)
+	if err != nil {
+		return 0, err
+	}
+
+	// 限制并发预热数，避免大量缓存键同时回源压垮数据库
+	var warmed int64
+	err = pool.ForEach(ctx.RequestContext(), keys, orderListWarmConcurrency, func(_ context.Context, key string) error {
+		username, page, pageSize, ok := s.parseOrderListCacheKey(key)
+		if !ok {
+			return nil
+		}
+
+		if err := s.redisCache.Delete(key, redis.WithTrace(ctx.Trace())); err != nil {
+			return err
+		}
+		if _, _, err := s.ListOrders(ctx, username, queryfilter.Query{}, page, pageSize); err != nil {
+			return err
+		}
+		atomic.AddInt64(&warmed, 1)
+		return nil
+	})
+	return int(warmed), err
+}
+
+func (s *orderService) CreateOrder(ctx common.Context, actor Actor, req *dto.CreateOrderRequest) (*model.Order, error) {
+	// 按订单类型的格式生成订单号
+	format, ok := s.numberCfg.Format(req.OrderType)
+	if !ok {
+		return nil, ErrOrderTypeInvalid
+	}
+	nextNumber := func() string {
+		return utils.GenerateOrderNumber(format.Prefix, format.DateFormat, format.RandomLength)
+	}
+
+	if len(req.Items) > 0 && req.TotalPrice != 0 {
+		return nil, ErrOrderTotalComputed
+	}
+
+	userID, err := s.orderOwner(ctx, actor, req.Username)
+	if err != nil {
+		return nil, err
+	}
 
-	order, err := s.GetOrderByOrderNumber(ctx, orderNumber)
-	// 如果订单号已存在, 则重新生成
-	if err == nil && order != nil {
-		return nil, errors.New("Order number already exists")
+	// 组织订单只能由组织成员创建
+	if req.OrganizationID != nil {
+		if _, err := s.orgRepo.GetMember(ctx, *req.OrganizationID, userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrOrgForbidden
+			}
+			return nil, err
+		}
 	}
 
-	order = &model.Order{
-		OrderNumber: orderNumber,
+	order := &model.Order{
+		OrderNumber: nextNumber(),
 		Username:    req.Username,
-		UserID:      req.UserId,
+		UserID:      userID,
 		TotalPrice:  req.TotalPrice,
 		Description: req.Description,
-		Status:      1,
+		Status:      model.OrderStatusPending,
+		Latitude:    req.Latitude,
+		Longitude:   req.Longitude,
+
+		OrganizationID: req.OrganizationID,
 	}
 
-	// 保存订单到数据库
-	if err := s.orderRepo.Create(ctx, order); err != nil {
+	// 订单、明细和库存扣减在同一事务中写入，订单号重复时由仓储层换号重试
+	err = s.orderRepo.Transaction(ctx, func(ctx common.Context) error {
+		if err := s.orderRepo.Create(ctx, order, nextNumber); err != nil {
+			if _, conflict := database.UniqueViolation(err); conflict {
+				return ErrOrderNumberConflict
+			}
+			return err
+		}
+		for _, item := range sortedItems(req.Items) {
+			total, err := s.addItem(ctx, order.ID, item)
+			if err != nil {
+				return err
+			}
+			order.TotalPrice = total
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.leaderboard.OrderCreated(ctx, order)
+	s.geo.OrderLocated(ctx, order)
+	s.projection.OrderChanged(order)
 
 	// 保存订单到Redis, 设置订单缓存过期时间为30min
-	if err := s.SaveOrderInCache(ctx, order, 30*time.Minute); err != nil {
+	if err := s.cacheError(ctx, cacheOpOrderSave, s.SaveOrderInCache(ctx, order, orderCacheTTL)); err != nil {
 		return nil, err
 	}
 
 	// 删除订单列表缓存
-	if err := s.DeleteOrderListCache(ctx); err != nil {
+	if err := s.cacheError(ctx, cacheOpOrderListInvalidate, s.DeleteOrderListCache(ctx)); err != nil {
 		return nil, err
 	}
 
 	return order, nil
 }
 
-func (s *orderService) GetOrderByOrderNumber(ctx common.Context, orderNumber string) (*model.Order, error) {
-	cacheKey := s.getOrderCacheKey(orderNumber)
+// orderOwner 订单归属用户的ID: 为自己下单时为会话用户，管理员为其他用户下单时按用户名查询
+func (s *orderService) orderOwner(ctx common.Context, actor Actor, username string) (uint, error) {
+	if username == actor.Username {
+		return actor.UserID, nil
+	}
+	if !actor.IsAdmin() {
+		return 0, ErrOrderForbidden
+	}
 
-	// 检查缓存中是否已存在该订单号
-	orderStr, err := s.redisCache.Get(cacheKey)
-	if err == nil && orderStr != "" {
-		var order model.Order
-		if err := json.Unmarshal([]byte(orderStr), &order); err == nil {
-			return &order, nil
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrOrderUserNotFound
 		}
+		return 0, err
 	}
+	return user.ID, nil
+}
 
-	if err == nil && orderStr == "" {
-		return nil, nil
+// loadOrder 按订单号读取订单，优先读缓存，不做权限校验
+// 同一订单号的并发回源合并为一次，订单不存在时缓存空值标记，防止缓存穿透
+func (s *orderService) loadOrder(ctx common.Context, orderNumber string) (*model.Order, error) {
+	order, err := cache.GetOrLoad(ctx.RequestContext(), s.orders, s.getOrderCacheKey(orderNumber), orderCacheTTL, func(context.Context) (*model.Order, error) {
+		order, err := s.orderRepo.GetOrderByOrderNumber(ctx, orderNumber)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, cache.ErrNotFound
+		}
+		return order, err
+	})
+	// 写缓存失败时结果仍然可用，按配置决定是否忽略
+	if errors.Is(err, cache.ErrStore) {
+		if err := s.cacheError(ctx, cacheOpOrderSave, err); err != nil {
+			return nil, err
+		}
+		if !errors.Is(err, cache.ErrNotFound) {
+			err = nil
+		}
+	}
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, ErrOrderNotFound
 	}
-
-	order, err := s.orderRepo.GetOrderByOrderNumber(ctx, orderNumber)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			// 缓存空值，防止缓存穿透
-			if err := s.redisCache.SetWithExpire(cacheKey, "", 30*time.Minute); err != nil {
-				return nil, err
-			}
-			return nil, err
+		return nil, err
+	}
+	return order, nil
+}
+
+// authorize 校验用户是否有权操作订单，write 为 true 表示修改或删除
+// 订单创建者和管理员可以任意操作；组织订单的成员可以查看，组织所有者还可以修改和删除
+func (s *orderService) authorize(ctx common.Context, actor Actor, order *model.Order, write bool) error {
+	err := actor.authorizeOrder(order)
+	if err == nil || order.OrganizationID == nil {
+		return err
+	}
+
+	member, memberErr := s.orgRepo.GetMember(ctx, *order.OrganizationID, actor.UserID)
+	if memberErr != nil {
+		if errors.Is(memberErr, gorm.ErrRecordNotFound) {
+			return err
 		}
+		return memberErr
+	}
+	if write && !member.IsOwner() {
+		return err
+	}
+	return nil
+}
+
+// authorizedOrder 按订单号读取订单并校验用户是否有权操作
+func (s *orderService) authorizedOrder(ctx common.Context, actor Actor, orderNumber string, write bool) (*model.Order, error) {
+	order, err := s.loadOrder(ctx, orderNumber)
+	if err != nil {
 		return nil, err
 	}
 
-	// 保存订单到Redis, 设置订单缓存过期时间为30min
-	if err := s.SaveOrderInCache(ctx, order, 30*time.Minute); err != nil {
+	if err := s.authorize(ctx, actor, order, write); err != nil {
 		return nil, err
 	}
 	return order, nil
 }
 
-func (s *orderService) UpdateOrderByOrderNumber(ctx common.Context, req *dto.UpdateOrderRequest) (*model.Order, error) {
-	orderNumber := req.OrderNumber
-	order, err := s.GetOrderByOrderNumber(ctx, orderNumber)
-	if err != nil || order == nil {
+func (s *orderService) GetOrderByOrderNumber(ctx common.Context, actor Actor, orderNumber string) (*model.Order, error) {
+	order, err := s.authorizedOrder(ctx, actor, orderNumber, false)
+	if err != nil {
 		return nil, err
 	}
+	return actor.visibleOrder(order), nil
+}
 
-	// 更新订单字段
-	if req.TotalPrice != 0 {
-		order.TotalPrice = req.TotalPrice
+// BatchGetOrders 按订单号批量查询订单，返回可见的订单(按请求顺序)和不存在或无权查看的订单号
+// 先用一次 MGET 读取缓存，未命中的订单号合并为一次 IN 查询，查到的订单写回缓存
+func (s *orderService) BatchGetOrders(ctx common.Context, actor Actor, orderNumbers []string) ([]*model.Order, []string, error) {
+	orderNumbers = utils.Unique(orderNumbers)
+
+	keys := make([]string, 0, len(orderNumbers))
+	for _, orderNumber := range orderNumbers {
+		keys = append(keys, s.getOrderCacheKey(orderNumber))
 	}
-	if req.Description != "" {
-		order.Description = req.Description
+	// Redis 不可用时按全部未命中处理
+	cached, err := s.redisCache.MGet(keys, redis.WithTrace(ctx.Trace()))
+	if err != nil {
+		cached = nil
 	}
-	if req.Status != 0 {
-		order.Status = req.Status
+
+	found := make(map[string]*model.Order, len(orderNumbers))
+	var misses []string
+	for i, orderNumber := range orderNumbers {
+		value, ok := cached[keys[i]]
+		if !ok {
+			s.recordCacheLookup(ctx, "order", keys[i], metrics.CacheMiss)
+			misses = append(misses, orderNumber)
+			continue
+		}
+		order, err := cache.Decode[*model.Order](s.orders, value)
+		notFound := errors.Is(err, cache.ErrNotFound)
+		if err != nil && !notFound {
+			s.recordCacheLookup(ctx, "order", keys[i], metrics.CacheStale)
+			misses = append(misses, orderNumber)
+			continue
+		}
+		s.recordCacheLookup(ctx, "order", keys[i], metrics.CacheHit)
+		// 不存在的标记是防穿透的缓存，订单不存在
+		if !notFound {
+			found[orderNumber] = order
+		}
+	}
+
+	if len(misses) > 0 {
+		orders, err := s.orderRepo.GetByOrderNumbers(ctx, misses)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, order := range orders {
+			found[order.OrderNumber] = order
+		}
+
+		for _, orderNumber := range misses {
+			var cacheErr error
+			if order, ok := found[orderNumber]; ok {
+				cacheErr = s.SaveOrderInCache(ctx, order, orderCacheTTL)
+			} else {
+				cacheErr = s.saveOrderNotFound(ctx, orderNumber)
+			}
+			if err := s.cacheError(ctx, cacheOpOrderSave, cacheErr); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	// 无权查看的订单与不存在的订单一样返回在 missing 中，不暴露订单是否存在
+	visible := make([]*model.Order, 0, len(found))
+	var missing []string
+	for _, orderNumber := range orderNumbers {
+		order, ok := found[orderNumber]
+		if !ok {
+			missing = append(missing, orderNumber)
+			continue
+		}
+		if err := s.authorize(ctx, actor, order, false); err != nil {
+			if errors.Is(err, ErrOrderForbidden) {
+				missing = append(missing, orderNumber)
+				continue
+			}
+			return nil, nil, err
+		}
+		visible = append(visible, actor.visibleOrder(order))
+	}
+	return visible, missing, nil
+}
+
+func (s *orderService) UpdateOrderByOrderNumber(ctx common.Context, actor Actor, req *dto.UpdateOrderRequest) (*model.Order, error) {
+	return s.PatchOrder(ctx, actor, req.OrderNumber, orderPatch(req))
+}
+
+func (s *orderService) PatchOrder(ctx common.Context, actor Actor, orderNumber string, req *dto.PatchOrderRequest) (*model.Order, error) {
+	order, err := s.authorizedOrder(ctx, actor, orderNumber, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTotalEditable(ctx, order, req); err != nil {
+		return nil, err
 	}
 
-	if err := s.orderRepo.Update(ctx, order); err != nil {
+	// 更新订单字段，只写入修改的列
+	oldPrice, oldStatus := order.TotalPrice, order.Status
+	if err := s.orderRepo.UpdateFields(ctx, order.ID, applyOrderUpdate(order, req)); err != nil {
 		return nil, err
 	}
+	s.leaderboard.OrderRepriced(ctx, order, oldPrice)
+	s.projection.OrderChanged(order)
+	if oldStatus != model.OrderStatusPaid && order.Status == model.OrderStatusPaid {
+		s.hooks.OrderPaid(ctx, order)
+	}
 
 	// 保存订单到Redis, 设置订单缓存过期时间为30min
-	if err := s.SaveOrderInCache(ctx, order, 30*time.Minute); err != nil {
+	if err := s.cacheError(ctx, cacheOpOrderSave, s.SaveOrderInCache(ctx, order, orderCacheTTL)); err != nil {
 		return nil, err
 	}
 
 	// 删除订单列表缓存
-	if err := s.DeleteOrderListCache(ctx); err != nil {
+	if err := s.cacheError(ctx, cacheOpOrderListInvalidate, s.DeleteOrderListCache(ctx)); err != nil {
 		return nil, err
 	}
-	return order, nil
+	return actor.visibleOrder(order), nil
 }
 
-func (s *orderService) DeleteOrderByOrderNumber(ctx common.Context, orderNumber string) error {
-	order, err := s.GetOrderByOrderNumber(ctx, orderNumber)
-	if err != nil || order == nil {
+func (s *orderService) DeleteOrderByOrderNumber(ctx common.Context, actor Actor, orderNumber string) error {
+	order, err := s.authorizedOrder(ctx, actor, orderNumber, true)
+	if err != nil {
 		return err
 	}
 
 	// 删除订单缓存
-	if err := s.redisCache.Delete(s.getOrderCacheKey(orderNumber)); err != nil {
+	if err := s.cacheError(ctx, cacheOpOrderInvalidate, s.redisCache.Delete(s.getOrderCacheKey(orderNumber))); err != nil {
 		return err
 	}
 
 	// 删除订单列表缓存
-	if err := s.DeleteOrderListCache(ctx); err != nil {
+	if err := s.cacheError(ctx, cacheOpOrderListInvalidate, s.DeleteOrderListCache(ctx)); err != nil {
 		return err
 	}
 
 	if err := s.orderRepo.Delete(ctx, order.ID); err != nil {
 		return err
 	}
+	s.leaderboard.OrderDeleted(ctx, order)
+	s.geo.OrderRemoved(ctx, order)
+	s.projection.OrderRemoved(order)
 	return nil
 }
 
-func (s *orderService) GetOrderByID(ctx common.Context, id uint) (*model.Order, error) {
+// authorizedOrderByID 按ID读取订单并校验用户是否有权操作
+func (s *orderService) authorizedOrderByID(ctx common.Context, actor Actor, id uint, write bool) (*model.Order, error) {
 	order, err := s.orderRepo.GetByID(ctx, id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
 		}
 		return nil, err
 	}
+
+	if err := s.authorize(ctx, actor, order, write); err != nil {
+		return nil, err
+	}
 	return order, nil
 }
 
-func (s *orderService) UpdateOrder(ctx common.Context, id uint, req *dto.UpdateOrderRequest) (*model.Order, error) {
-	order, err := s.orderRepo.GetByID(ctx, id)
+func (s *orderService) GetOrderByID(ctx common.Context, actor Actor, id uint) (*model.Order, error) {
+	order, err := s.authorizedOrderByID(ctx, actor, id, false)
+	if err != nil {
+		return nil, err
+	}
+	return actor.visibleOrder(order), nil
+}
+
+func (s *orderService) UpdateOrder(ctx common.Context, actor Actor, id uint, req *dto.UpdateOrderRequest) (*model.Order, error) {
+	order, err := s.authorizedOrderByID(ctx, actor, id, true)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, err
-		}
+		return nil, err
+	}
+	patch := orderPatch(req)
+	if err := s.checkTotalEditable(ctx, order, patch); err != nil {
 		return nil, err
 	}
 
-	// 更新订单字段
+	// 更新订单字段，只写入修改的列
+	oldPrice, oldStatus := order.TotalPrice, order.Status
+	if err := s.orderRepo.UpdateFields(ctx, order.ID, applyOrderUpdate(order, patch)); err != nil {
+		return nil, err
+	}
+	s.leaderboard.OrderRepriced(ctx, order, oldPrice)
+	s.projection.OrderChanged(order)
+	if oldStatus != model.OrderStatusPaid && order.Status == model.OrderStatusPaid {
+		s.hooks.OrderPaid(ctx, order)
+	}
+	return actor.visibleOrder(order), nil
+}
+
+// orderPatch PUT 请求中的零值表示不修改，转换为只包含非零值字段的部分更新
+func orderPatch(req *dto.UpdateOrderRequest) *dto.PatchOrderRequest {
+	patch := &dto.PatchOrderRequest{}
 	if req.TotalPrice != 0 {
-		order.TotalPrice = req.TotalPrice
+		patch.TotalPrice = &req.TotalPrice
 	}
 	if req.Description != "" {
-		order.Description = req.Description
+		patch.Description = &req.Description
 	}
 	if req.Status != 0 {
-		order.Status = req.Status
+		patch.Status = &req.Status
 	}
+	return patch
+}
 
-	if err := s.orderRepo.Update(ctx, order); err != nil {
-		return nil, err
+// applyOrderUpdate 把请求中的修改应用到 order，并返回需要更新的列
+func applyOrderUpdate(order *model.Order, req *dto.PatchOrderRequest) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if req.TotalPrice != nil {
+		order.TotalPrice = *req.TotalPrice
+		fields["total_price"] = *req.TotalPrice
 	}
-	return order, nil
+	if req.Description != nil {
+		order.Description = *req.Description
+		fields["description"] = *req.Description
+	}
+	if req.Status != nil {
+		order.Status = *req.Status
+		fields["status"] = *req.Status
+	}
+	return fields
 }
 
-func (s *orderService) DeleteOrder(ctx common.Context, id uint) error {
+func (s *orderService) DeleteOrder(ctx common.Context, actor Actor, id uint) error {
+	order, err := s.authorizedOrderByID(ctx, actor, id, true)
+	if err != nil {
+		return err
+	}
+
 	if err := s.orderRepo.Delete(ctx, id); err != nil {
 		return err
 	}
+	s.leaderboard.OrderDeleted(ctx, order)
+	s.geo.OrderRemoved(ctx, order)
+	s.projection.OrderRemoved(order)
 	return nil
 }
 
-func (s *orderService) ListOrders(ctx common.Context, username string, page, pageSize int) ([]*model.Order, int64, error) {
+// AddOrderNote 追加订单备注，只有管理员可以写入内部备注
+func (s *orderService) AddOrderNote(ctx common.Context, actor Actor, req *dto.CreateOrderNoteRequest) (*model.OrderNote, error) {
+	if req.Internal && !actor.IsAdmin() {
+		return nil, ErrOrderForbidden
+	}
+
+	// 组织成员可以在组织订单上留言
+	order, err := s.authorizedOrder(ctx, actor, req.OrderNumber, false)
+	if err != nil {
+		return nil, err
+	}
+
+	note := &model.OrderNote{
+		OrderID:    order.ID,
+		AuthorID:   actor.UserID,
+		AuthorName: actor.Username,
+		Content:    req.Content,
+		Internal:   req.Internal,
+	}
+	if err := s.orderRepo.CreateNote(ctx, note); err != nil {
+		return nil, err
+	}
+
+	// 订单缓存中包含备注，删除后由下次读取回源重建
+	if err := s.cacheError(ctx, cacheOpOrderInvalidate, s.redisCache.Delete(s.getOrderCacheKey(order.OrderNumber))); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (s *orderService) ListOrders(ctx common.Context, username string, filter queryfilter.Query, page, pageSize int) ([]*model.Order, int64, error) {
+	// 过滤条件和排序的组合太多，缓存命中率低，读模型也只按默认排序建了索引，直接查询订单表
+	if !filter.IsZero() {
+		return s.queryFilteredOrderList(ctx, username, filter, page, pageSize)
+	}
+
+	if username == common.ADMIN_NAME && s.hotLists != nil {
+		return s.listHotOrders(ctx, username, page, pageSize)
+	}
+
 	// 从Redis缓存中获取订单列表
 	cacheKey := s.getOrderListCacheKey(username, page, pageSize)
 	cachedOrders, _ := s.redisCache.HashGet(cacheKey, "orders")
 	cachedTotal, _ := s.redisCache.HashGet(cacheKey, "total")
 	if cachedOrders != "" && cachedTotal != "" {
-		total, err := strconv.ParseInt(cachedTotal, 10, 64)
-		if err != nil {
-			return nil, 0, err
+		orders, total, err := decodeOrderList(cachedOrders, cachedTotal)
+		if err == nil {
+			s.recordCacheLookup(ctx, "order_list", cacheKey, metrics.CacheHit)
+			return orders, total, nil
 		}
 
-		var orders []*model.Order
-		err = json.Unmarshal([]byte(cachedOrders), &orders)
-		if err != nil {
-			return nil, 0, err
+		// 缓存数据损坏时回源数据库，并由下面的写缓存覆盖
+		s.recordCacheLookup(ctx, "order_list", cacheKey, metrics.CacheStale)
+	} else {
+		s.recordCacheLookup(ctx, "order_list", cacheKey, metrics.CacheMiss)
+	}
+
+	orders, total, err := s.queryOrderList(ctx, username, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 保存订单列表到Redis缓存, 设置过期时间为5min
+	err = s.SaveOrderListInCache(ctx, orders, total, username, page, pageSize, 30*time.Minute)
+	if err := s.cacheError(ctx, cacheOpOrderListSave, err); err != nil {
+		return nil, 0, err
+	}
+
+	return orders, total, nil
+}
+
+// listHotOrders 从逻辑过期缓存读取订单列表: 已过期时仍返回旧列表，并在后台刷新，只有未命中时才同步查询数据库
+func (s *orderService) listHotOrders(ctx common.Context, username string, page, pageSize int) ([]*model.Order, int64, error) {
+	cacheKey := s.getOrderListCacheKey(username, page, pageSize)
+	if fields, fresh, ok := s.hotLists.Get(cacheKey); ok {
+		orders, total, err := decodeOrderList(fields["orders"], fields["total"])
+		if err == nil {
+			if fresh {
+				s.recordCacheLookup(ctx, "order_list", cacheKey, metrics.CacheHit)
+			} else {
+				s.recordCacheLookup(ctx, "order_list", cacheKey, metrics.CacheExpired)
+				s.hotLists.Refresh(ctx, cacheKey, func(bg common.Context) error {
+					_, _, err := s.refreshHotList(bg, username, page, pageSize)
+					return err
+				})
+			}
+			return orders, total, nil
 		}
+		s.recordCacheLookup(ctx, "order_list", cacheKey, metrics.CacheStale)
+	} else {
+		s.recordCacheLookup(ctx, "order_list", cacheKey, metrics.CacheMiss)
+	}
+
+	return s.refreshHotList(ctx, username, page, pageSize)
+}
 
-		return orders, total, nil
+// refreshHotList 查询数据库并写入逻辑过期缓存
+func (s *orderService) refreshHotList(ctx common.Context, username string, page, pageSize int) ([]*model.Order, int64, error) {
+	orders, total, err := s.queryOrderList(ctx, username, page, pageSize)
+	if err != nil {
+		return nil, 0, err
 	}
 
+	data, err := json.Marshal(orders)
+	if err == nil {
+		err = s.hotLists.Set(s.getOrderListCacheKey(username, page, pageSize), []interface{}{"orders", data, "total", total}, redis.WithTrace(ctx.Trace()))
+	}
+	if err := s.cacheError(ctx, cacheOpOrderListSave, err); err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// queryOrderList 从数据库查询订单列表，管理员查看全部订单时优先查询读模型
+func (s *orderService) queryOrderList(ctx common.Context, username string, page, pageSize int) ([]*model.Order, int64, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -312,15 +863,131 @@ func (s *orderService) ListOrders(ctx common.Context, username string, page, pag
 	}
 
 	offset := (page - 1) * pageSize
-	orders, total, err := s.orderRepo.List(ctx, username, offset, pageSize)
+	var orders []*model.Order
+	var total int64
+	var err error
+	projected := false
+	// 管理员查看全部订单时优先查询读模型，读模型不可用时查询订单表
+	if username == common.ADMIN_NAME {
+		orders, total, projected, err = s.projection.ListOrders(ctx, offset, pageSize)
+	}
+	if !projected {
+		orders, total, err = s.orderRepo.List(ctx, username, queryfilter.Query{}, offset, pageSize)
+	}
 	if err != nil {
 		return nil, 0, err
 	}
+	return orders, total, nil
+}
 
-	// 保存订单列表到Redis缓存, 设置过期时间为5min
-	if err := s.SaveOrderListInCache(ctx, orders, total, username, page, pageSize, 30*time.Minute); err != nil {
-		return nil, 0, err
+// queryFilteredOrderList 按过滤条件和排序从订单表查询订单列表
+func (s *orderService) queryFilteredOrderList(ctx common.Context, username string, filter queryfilter.Query, page, pageSize int) ([]*model.Order, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return s.orderRepo.List(ctx, username, filter, (page-1)*pageSize, pageSize)
+}
+
+func (s *orderService) ListOrdersAfter(ctx common.Context, username string, filter queryfilter.Query, afterID uint, pageSize int) ([]*model.Order, bool, error) {
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
 	}
 
+	// 多查一条判断是否还有下一页
+	orders, err := s.orderRepo.ListAfter(ctx, username, filter, afterID, pageSize+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(orders) > pageSize {
+		return orders[:pageSize], true, nil
+	}
+	return orders, false, nil
+}
+
+// decodeOrderList 解析缓存中的订单列表和总数
+func decodeOrderList(ordersJSON, totalStr string) ([]*model.Order, int64, error) {
+	total, err := strconv.ParseInt(totalStr, 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+	var orders []*model.Order
+	if err := json.Unmarshal([]byte(ordersJSON), &orders); err != nil {
+		return nil, 0, err
+	}
 	return orders, total, nil
 }
+
+func (s *orderService) GetArchivedOrder(ctx common.Context, actor Actor, orderNumber string) (*model.Order, error) {
+	order, err := s.archiveRepo.GetByOrderNumber(ctx, orderNumber)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, actor, order, false); err != nil {
+		return nil, err
+	}
+	return actor.visibleOrder(order), nil
+}
+
+func (s *orderService) ListOrdersWithArchive(ctx common.Context, username string, page, pageSize int) ([]*model.Order, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return s.archiveRepo.ListWithLive(ctx, username, (page-1)*pageSize, pageSize)
+}
+
+func (s *orderService) SearchOrders(ctx common.Context, search repository.OrderSearch, page, pageSize int) ([]*repository.OrderWithUser, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	// username=admin 表示不按用户名过滤
+	if search.Username == common.ADMIN_NAME {
+		search.Username = ""
+	}
+	return s.orderRepo.ListWithUsers(ctx, search, (page-1)*pageSize, pageSize)
+}
+
+func (s *orderService) EachOrder(ctx common.Context, username string, fn func(orders []*model.Order) error) error {
+	return s.orderRepo.EachByUsername(ctx, username, orderStreamBatchSize, fn)
+}
+
+// OrdersArchived 归档后订单表中已没有这些订单：删除订单缓存，从 GEO 索引和列表读模型中移除
+// 排行榜按订单表和归档表合计，不需要更新
+func (s *orderService) OrdersArchived(ctx common.Context, orders []*model.Order) {
+	log := logger.Module(ctx.Logger(), "service")
+	for _, order := range orders {
+		if err := s.redisCache.Delete(s.getOrderCacheKey(order.OrderNumber)); err != nil && !errors.Is(err, redis.ErrUnavailable) {
+			log.Warn("invalidate archived order cache failed", zap.String("order_number", order.OrderNumber), zap.Error(err))
+		}
+		s.geo.OrderRemoved(ctx, order)
+		s.projection.OrderRemoved(order)
+	}
+
+	if err := s.DeleteOrderListCache(ctx); err != nil && !errors.Is(err, redis.ErrUnavailable) {
+		log.Warn("invalidate order list cache after archiving failed", zap.Error(err))
+	}
+}
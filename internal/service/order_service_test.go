@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/redis"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeOrderRepo 只实现创建和按订单号查询，其它方法未实现
+type fakeOrderRepo struct {
+	repository.OrderRepository
+	orders map[string]*model.Order
+}
+
+func (r *fakeOrderRepo) Transaction(ctx common.Context, fn func(ctx common.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *fakeOrderRepo) Create(_ common.Context, order *model.Order, _ func() string) error {
+	order.ID = uint(len(r.orders) + 1)
+	saved := *order
+	r.orders[order.OrderNumber] = &saved
+	return nil
+}
+
+func (r *fakeOrderRepo) GetOrderByOrderNumber(_ common.Context, orderNumber string) (*model.Order, error) {
+	order, ok := r.orders[orderNumber]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	saved := *order
+	return &saved, nil
+}
+
+// fakeUserRepo 只实现按用户名查询
+type fakeUserRepo struct {
+	repository.UserRepository
+	users []*model.User
+}
+
+func (r *fakeUserRepo) GetByUsername(_ common.Context, username string) (*model.User, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeLeaderboard struct{ LeaderboardService }
+
+func (fakeLeaderboard) OrderCreated(common.Context, *model.Order) {}
+
+type fakeGeo struct{ GeoService }
+
+func (fakeGeo) OrderLocated(common.Context, *model.Order) {}
+
+func newTestOrderService(users ...*model.User) OrderService {
+	orderRepo := &fakeOrderRepo{orders: map[string]*model.Order{}}
+	numberCfg := config.OrderNumberConfig{Prefix: "EC", DateFormat: "20060102", RandomLength: 6}
+	return NewOrderService(orderRepo, &fakeUserRepo{users: users}, redis.NewNoopRepository(context.Background()),
+		config.CacheConfig{Optional: true}, numberCfg, fakeLeaderboard{}, fakeGeo{}, nil,
+		NewOrderProjectionService(orderRepo, nil, config.ProjectionConfig{}, zap.NewNop()), nil, nil, nil, nil)
+}
+
+func TestCreateOrderForOtherUser(t *testing.T) {
+	bob := &model.User{BaseModel: model.BaseModel{ID: 2}, Username: "bob"}
+	s := newTestOrderService(bob)
+	ctx := common.NewBackgroundContext(zap.NewNop())
+	admin := Actor{UserID: 1, Username: common.ADMIN_NAME}
+
+	// 管理员为 bob 下单，订单归属 bob，bob 可以查看
+	order, err := s.CreateOrder(ctx, admin, &dto.CreateOrderRequest{Username: "bob", TotalPrice: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.UserID != bob.ID || order.Username != "bob" {
+		t.Fatalf("order owner = %d %q, want %d bob", order.UserID, order.Username, bob.ID)
+	}
+	got, err := s.GetOrderByOrderNumber(ctx, Actor{UserID: bob.ID, Username: "bob"}, order.OrderNumber)
+	if err != nil {
+		t.Fatalf("bob reading his order: %v", err)
+	}
+	if got.OrderNumber != order.OrderNumber {
+		t.Fatalf("got order %s, want %s", got.OrderNumber, order.OrderNumber)
+	}
+
+	// 下单用户不存在
+	if _, err := s.CreateOrder(ctx, admin, &dto.CreateOrderRequest{Username: "nobody", TotalPrice: 10}); !errors.Is(err, ErrOrderUserNotFound) {
+		t.Fatalf("err = %v, want ErrOrderUserNotFound", err)
+	}
+
+	// 普通用户不能为其他用户下单
+	alice := Actor{UserID: 3, Username: "alice"}
+	if _, err := s.CreateOrder(ctx, alice, &dto.CreateOrderRequest{Username: "bob", TotalPrice: 10}); !errors.Is(err, ErrOrderForbidden) {
+		t.Fatalf("err = %v, want ErrOrderForbidden", err)
+	}
+}
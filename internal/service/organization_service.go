@@ -0,0 +1,234 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/logger"
+	"gin-app-start/pkg/mail"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// invitationTTL 组织邀请的有效期
+const invitationTTL = 7 * 24 * time.Hour
+
+type OrganizationService interface {
+	CreateOrganization(ctx common.Context, actor Actor, req *dto.CreateOrganizationRequest) (*model.Organization, error)
+	ListOrganizations(ctx common.Context, actor Actor) ([]*model.Organization, error)
+	ListMembers(ctx common.Context, actor Actor, orgID uint) ([]*model.OrganizationMember, error)
+	RemoveMember(ctx common.Context, actor Actor, orgID, userID uint) error
+	Invite(ctx common.Context, actor Actor, orgID uint, req *dto.InviteMemberRequest) (*model.OrganizationInvitation, error)
+	AcceptInvitation(ctx common.Context, actor Actor, token string) (*model.OrganizationMember, error)
+	ListOrders(ctx common.Context, actor Actor, orgID uint, page, pageSize int) ([]*model.Order, int64, error)
+}
+
+type organizationService struct {
+	orgRepo   repository.OrganizationRepository
+	userRepo  repository.UserRepository
+	orderRepo repository.OrderRepository
+	mailer    mail.Sender // 未配置邮件时为 nil，邀请令牌只在响应中返回
+}
+
+func NewOrganizationService(orgRepo repository.OrganizationRepository, userRepo repository.UserRepository, orderRepo repository.OrderRepository, mailer mail.Sender) OrganizationService {
+	return &organizationService{
+		orgRepo:   orgRepo,
+		userRepo:  userRepo,
+		orderRepo: orderRepo,
+		mailer:    mailer,
+	}
+}
+
+func (s *organizationService) CreateOrganization(ctx common.Context, actor Actor, req *dto.CreateOrganizationRequest) (*model.Organization, error) {
+	org := &model.Organization{
+		Name:    req.Name,
+		OwnerID: actor.UserID,
+	}
+	owner := &model.OrganizationMember{
+		UserID:   actor.UserID,
+		Username: actor.Username,
+		Role:     model.OrgRoleOwner,
+	}
+	if err := s.orgRepo.Create(ctx, org, owner); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (s *organizationService) ListOrganizations(ctx common.Context, actor Actor) ([]*model.Organization, error) {
+	return s.orgRepo.ListByUser(ctx, actor.UserID)
+}
+
+// membership 校验组织存在且用户是成员，管理员视为所有者
+// owner 为 true 时要求用户是组织所有者
+func (s *organizationService) membership(ctx common.Context, actor Actor, orgID uint, owner bool) (*model.OrganizationMember, error) {
+	if _, err := s.orgRepo.GetByID(ctx, orgID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrgNotFound
+		}
+		return nil, err
+	}
+
+	if actor.IsAdmin() {
+		return &model.OrganizationMember{OrganizationID: orgID, UserID: actor.UserID, Username: actor.Username, Role: model.OrgRoleOwner}, nil
+	}
+
+	member, err := s.orgRepo.GetMember(ctx, orgID, actor.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrgForbidden
+		}
+		return nil, err
+	}
+	if owner && !member.IsOwner() {
+		return nil, ErrOrgForbidden
+	}
+	return member, nil
+}
+
+func (s *organizationService) ListMembers(ctx common.Context, actor Actor, orgID uint) ([]*model.OrganizationMember, error) {
+	if _, err := s.membership(ctx, actor, orgID, false); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.ListMembers(ctx, orgID)
+}
+
+// RemoveMember 所有者可以移除任意成员，成员可以移除自己(退出组织)；组织至少保留一名所有者
+func (s *organizationService) RemoveMember(ctx common.Context, actor Actor, orgID, userID uint) error {
+	self := actor.UserID == userID
+	if _, err := s.membership(ctx, actor, orgID, !self); err != nil {
+		return err
+	}
+
+	target, err := s.orgRepo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrgMemberNotFound
+		}
+		return err
+	}
+
+	if target.IsOwner() {
+		owners, err := s.orgRepo.CountOwners(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		if owners <= 1 {
+			return ErrOrgLastOwner
+		}
+	}
+
+	return s.orgRepo.RemoveMember(ctx, orgID, userID)
+}
+
+// Invite 所有者邀请用户加入组织，配置了邮件时把邀请令牌发送到被邀请人邮箱
+func (s *organizationService) Invite(ctx common.Context, actor Actor, orgID uint, req *dto.InviteMemberRequest) (*model.OrganizationInvitation, error) {
+	if _, err := s.membership(ctx, actor, orgID, true); err != nil {
+		return nil, err
+	}
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = model.OrgRoleMember
+	}
+	invitation := &model.OrganizationInvitation{
+		OrganizationID: orgID,
+		Email:          strings.ToLower(req.Email),
+		Role:           role,
+		Token:          hex.EncodeToString(buf),
+		InvitedBy:      actor.UserID,
+		ExpiresAt:      time.Now().Add(invitationTTL),
+	}
+	if err := s.orgRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	// 邮件发送失败不影响邀请，邀请人仍可以转交响应中的令牌
+	if s.mailer != nil {
+		subject := fmt.Sprintf("You are invited to join %s", org.Name)
+		body := fmt.Sprintf("%s invited you to join %s as %s.\n\nInvitation token: %s\nThe invitation expires at %s.",
+			actor.Username, org.Name, role, invitation.Token, invitation.ExpiresAt.Format(time.RFC3339))
+		if err := s.mailer.Send(ctx.RequestContext(), invitation.Email, subject, body); err != nil {
+			logger.Module(ctx.Logger(), "service").Warn("organization invitation mail failed",
+				zap.Uint("organization_id", orgID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return invitation, nil
+}
+
+// AcceptInvitation 当前用户凭令牌加入组织，邀请需未过期、未被接受，且用户邮箱与邀请邮箱一致
+func (s *organizationService) AcceptInvitation(ctx common.Context, actor Actor, token string) (*model.OrganizationMember, error) {
+	invitation, err := s.orgRepo.GetInvitationByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationInvalid
+		}
+		return nil, err
+	}
+	if invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		return nil, ErrInvitationInvalid
+	}
+
+	user, err := s.userRepo.GetByID(ctx, actor.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, ErrInvitationInvalid
+	}
+
+	member := &model.OrganizationMember{
+		OrganizationID: invitation.OrganizationID,
+		UserID:         user.ID,
+		Username:       user.Username,
+		Role:           invitation.Role,
+	}
+	accepted, err := s.orgRepo.AcceptInvitation(ctx, invitation, member)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, ErrInvitationInvalid
+	}
+
+	// 已是成员时保留原角色
+	return s.orgRepo.GetMember(ctx, invitation.OrganizationID, user.ID)
+}
+
+func (s *organizationService) ListOrders(ctx common.Context, actor Actor, orgID uint, page, pageSize int) ([]*model.Order, int64, error) {
+	if _, err := s.membership(ctx, actor, orgID, false); err != nil {
+		return nil, 0, err
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return s.orderRepo.ListByOrganization(ctx, orgID, (page-1)*pageSize, pageSize)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/pkg/logger"
+	"gin-app-start/pkg/password"
+
+	"go.uber.org/zap"
+)
+
+// defaultPasswordMinLength 未配置最小长度时的默认值，与请求参数的校验保持一致
+const defaultPasswordMinLength = 6
+
+// PasswordPolicyError 密码不满足策略，Rules 为所有未满足的规则
+type PasswordPolicyError struct {
+	Rules     []password.Rule
+	MinLength int
+}
+
+func (e *PasswordPolicyError) Error() string {
+	rules := make([]string, len(e.Rules))
+	for i, rule := range e.Rules {
+		rules[i] = string(rule)
+	}
+	return "Password violates policy: " + strings.Join(rules, ",")
+}
+
+// PasswordPolicy 校验新密码是否满足密码策略
+type PasswordPolicy interface {
+	Check(ctx common.Context, plain, username string) error
+}
+
+type passwordPolicy struct {
+	policy    *password.Policy
+	breach    password.BreachChecker // 未启用泄露检查时为 nil
+	threshold int
+	failOpen  bool
+}
+
+func NewPasswordPolicy(cfg config.PasswordConfig) (PasswordPolicy, error) {
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = defaultPasswordMinLength
+	}
+
+	policy, err := password.NewPolicy(password.Policy{
+		MinLength:     cfg.MinLength,
+		RequireUpper:  cfg.RequireUpper,
+		RequireLower:  cfg.RequireLower,
+		RequireDigit:  cfg.RequireDigit,
+		RequireSymbol: cfg.RequireSymbol,
+	}, cfg.DictionaryFile)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &passwordPolicy{
+		policy:    policy,
+		threshold: cfg.BreachCheck.Threshold,
+		failOpen:  cfg.BreachCheck.FailOpen,
+	}
+	if p.threshold <= 0 {
+		p.threshold = 1
+	}
+	if cfg.BreachCheck.Enabled {
+		p.breach = password.NewHIBPChecker(cfg.BreachCheck.Endpoint, time.Duration(cfg.BreachCheck.Timeout)*time.Second)
+	}
+	return p, nil
+}
+
+// Check 先做本地规则校验，全部通过后再查询泄露库，避免为明显的弱密码发起外部请求
+func (p *passwordPolicy) Check(ctx common.Context, plain, username string) error {
+	if rules := p.policy.Validate(plain, username); len(rules) > 0 {
+		return &PasswordPolicyError{Rules: rules, MinLength: p.policy.MinLength}
+	}
+
+	if p.breach == nil {
+		return nil
+	}
+
+	count, err := p.breach.Count(ctx.Request().Context(), plain)
+	if err != nil {
+		if p.failOpen {
+			logger.Module(ctx.Logger(), "service").Warn("password breach check failed, skipped", zap.Error(err))
+			return nil
+		}
+		return err
+	}
+	if count >= p.threshold {
+		return &PasswordPolicyError{Rules: []password.Rule{password.RuleBreached}, MinLength: p.policy.MinLength}
+	}
+	return nil
+}
+
+// NewPasswordHasher 按配置创建密码哈希器，用于注册、登录和修改密码
+func NewPasswordHasher(cfg config.PasswordHashConfig) (*password.Hasher, error) {
+	return password.NewHasher(password.HashOptions{
+		Algorithm:  cfg.Algorithm,
+		BcryptCost: cfg.BcryptCost,
+		Argon2: password.Argon2Params{
+			Time:    cfg.Argon2.Time,
+			Memory:  cfg.Argon2.Memory,
+			Threads: cfg.Argon2.Threads,
+		},
+	})
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/database"
+	"gin-app-start/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+var _ ProductService = (*productService)(nil)
+
+// ProductService 商品目录，商品按 SKU 查找；管理端维护商品，订单明细加入时从这里读取名称和单价
+type ProductService interface {
+	CreateProduct(ctx common.Context, req *dto.CreateProductRequest) (*model.Product, error)
+	GetProduct(ctx common.Context, sku string) (*model.Product, error)
+	ListProducts(ctx common.Context, query *dto.ProductListQuery) ([]*model.Product, int64, error)
+	UpdateProduct(ctx common.Context, sku string, req *dto.UpdateProductRequest) (*model.Product, error)
+	DeleteProduct(ctx common.Context, sku string) error
+}
+
+type productService struct {
+	productRepo repository.ProductRepository
+}
+
+func NewProductService(productRepo repository.ProductRepository) ProductService {
+	return &productService{
+		productRepo: productRepo,
+	}
+}
+
+func (s *productService) CreateProduct(ctx common.Context, req *dto.CreateProductRequest) (*model.Product, error) {
+	product := &model.Product{
+		SKU:   req.ProductID,
+		Name:  req.Name,
+		Price: req.Price,
+		Stock: req.Stock,
+	}
+	if err := s.productRepo.Create(ctx, product); err != nil {
+		if _, ok := database.UniqueViolation(err); ok {
+			return nil, ErrProductExists
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *productService) GetProduct(ctx common.Context, sku string) (*model.Product, error) {
+	product, err := s.productRepo.GetBySKU(ctx, sku)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProductNotFound
+	}
+	return product, err
+}
+
+// ListProducts query 中未指定的分页参数会被填充为默认值
+func (s *productService) ListProducts(ctx common.Context, query *dto.ProductListQuery) ([]*model.Product, int64, error) {
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = 10
+	}
+	return s.productRepo.List(ctx, (query.Page-1)*query.PageSize, query.PageSize)
+}
+
+// UpdateProduct 修改商品名称、价格和库存，不影响已加入订单的明细
+func (s *productService) UpdateProduct(ctx common.Context, sku string, req *dto.UpdateProductRequest) (*model.Product, error) {
+	product, err := s.GetProduct(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]interface{})
+	if req.Name != "" {
+		product.Name = req.Name
+		fields["name"] = req.Name
+	}
+	if req.Price != 0 {
+		product.Price = req.Price
+		fields["price"] = req.Price
+	}
+	if req.Stock != nil {
+		product.Stock = req.Stock
+		fields["stock"] = *req.Stock
+	}
+	if err := s.productRepo.UpdateFields(ctx, product.ID, fields); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *productService) DeleteProduct(ctx common.Context, sku string) error {
+	product, err := s.GetProduct(ctx, sku)
+	if err != nil {
+		return err
+	}
+	return s.productRepo.Delete(ctx, product.ID)
+}
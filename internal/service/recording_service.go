@@ -0,0 +1,110 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/redis"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/pool"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// recordingWorkers 写入录制的并发数
+	recordingWorkers = 2
+	// recordingQueueSize 待写入的录制队列长度，队列满时丢弃新的录制，不阻塞请求
+	recordingQueueSize = 256
+	// defaultRecordingTTL 录制默认保存时长
+	defaultRecordingTTL = time.Hour
+)
+
+var ErrRecordingNotFound = errors.New("Recording does not exist")
+
+var _ RecordingService = (*recordingService)(nil)
+
+// RecordingService 请求录制，保存抽样请求的完整请求和响应，按 trace_id 查询
+type RecordingService interface {
+	// Record 异步保存一条录制，不阻塞当前请求
+	Record(rec *dto.Recording)
+	// GetRecording 按 trace_id 查询录制
+	GetRecording(ctx common.Context, traceID string) (*dto.Recording, error)
+	// Close 等待队列中的录制写入完成
+	Close()
+}
+
+type recordingService struct {
+	redisCache redis.RedisRepository
+	ttl        time.Duration
+	logger     *zap.Logger
+	pool       *pool.Pool
+}
+
+func NewRecordingService(redisCache redis.RedisRepository, cfg config.TapeConfig, logger *zap.Logger) RecordingService {
+	ttl := time.Duration(cfg.TTL) * time.Minute
+	if ttl <= 0 {
+		ttl = defaultRecordingTTL
+	}
+
+	return &recordingService{
+		redisCache: redisCache,
+		ttl:        ttl,
+		logger:     logger,
+		pool: pool.New(recordingWorkers, recordingQueueSize, pool.WithPanicHandler(func(r interface{}, stack []byte) {
+			logger.Error("save recording panic", zap.Any("panic", r), zap.ByteString("stack", stack))
+		})),
+	}
+}
+
+func (s *recordingService) getRecordingKey(traceID string) string {
+	return fmt.Sprintf("tape:%s", traceID)
+}
+
+func (s *recordingService) Record(rec *dto.Recording) {
+	ok := s.pool.TrySubmit(func() {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			s.logger.Warn("marshal recording failed", zap.String("trace_id", rec.TraceID), zap.Error(err))
+			return
+		}
+
+		if err := s.redisCache.SetWithExpire(s.getRecordingKey(rec.TraceID), string(data), s.ttl); err != nil {
+			s.logger.Warn("save recording failed", zap.String("trace_id", rec.TraceID), zap.Error(err))
+		}
+	})
+	if !ok {
+		s.logger.Warn("recording queue is full, dropped", zap.String("trace_id", rec.TraceID))
+	}
+}
+
+func (s *recordingService) GetRecording(ctx common.Context, traceID string) (*dto.Recording, error) {
+	key := s.getRecordingKey(traceID)
+
+	exists, err := s.redisCache.Exists(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrRecordingNotFound
+	}
+
+	data, err := s.redisCache.Get(key, redis.WithTrace(ctx.Trace()))
+	if err != nil {
+		return nil, err
+	}
+
+	var rec dto.Recording
+	if err := json.Unmarshal([]byte(data), &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (s *recordingService) Close() {
+	s.pool.Close()
+}
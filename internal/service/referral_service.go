@@ -0,0 +1,222 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/redis"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/database"
+	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/hashid"
+	"gin-app-start/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// inviteCodeCharset 邀请码字符集，去掉了容易混淆的 0/O、1/I
+	inviteCodeCharset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	inviteCodeLength  = 8
+	// inviteCodeRetries 邀请码冲突时的重试次数
+	inviteCodeRetries = 3
+	// topReferrersLimit 统计中返回的邀请人数
+	topReferrersLimit = 10
+)
+
+// 邀请计数在 Redis 中的键
+const (
+	referralIssuedKey   = "referral:issued"
+	referralAttemptsKey = "referral:attempts"
+	referralSignupsKey  = "referral:signups"
+)
+
+var _ ReferralService = (*referralService)(nil)
+
+// ReferralService 邀请码: 每个用户一个邀请码，新用户携带邀请码注册后邀请人获得奖励
+//
+// 邀请关系保存在 users.referrer_id 中，奖励和转化漏斗计数保存在 Redis 中，
+// Redis 不可用时只影响计数，不影响注册。
+type ReferralService interface {
+	// GetReferral 用户的邀请信息，还没有邀请码时生成
+	GetReferral(ctx common.Context, userID uint) (*dto.ReferralResponse, error)
+	// ResolveInviteCode 查找邀请码对应的邀请人，邀请码不存在时返回 ErrInviteCodeInvalid
+	ResolveInviteCode(ctx common.Context, inviteCode string) (*model.User, error)
+	// Credit 被邀请人注册成功后给邀请人记一次奖励
+	Credit(ctx common.Context, referrer *model.User)
+	// GetStats 邀请转化统计
+	GetStats(ctx common.Context) (*dto.ReferralStatsResponse, error)
+}
+
+type referralService struct {
+	userRepo   repository.UserRepository
+	redisCache redis.RedisRepository
+}
+
+func NewReferralService(userRepo repository.UserRepository, redisCache redis.RedisRepository) ReferralService {
+	return &referralService{
+		userRepo:   userRepo,
+		redisCache: redisCache,
+	}
+}
+
+func (s *referralService) getCreditsKey(userID uint) string {
+	return fmt.Sprintf("referral:credits:%d", userID)
+}
+
+func (s *referralService) GetReferral(ctx common.Context, userID uint) (*dto.ReferralResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteCode := user.InviteCode
+	if inviteCode == "" {
+		if inviteCode, err = s.issueInviteCode(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	invited, err := s.userRepo.CountReferrals(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ReferralResponse{
+		InviteCode:   inviteCode,
+		InvitedCount: invited,
+		Credits:      s.counter(ctx, s.getCreditsKey(userID)),
+	}, nil
+}
+
+// issueInviteCode 生成邀请码，与其他用户的邀请码冲突时重新生成
+func (s *referralService) issueInviteCode(ctx common.Context, userID uint) (string, error) {
+	for i := 0; i < inviteCodeRetries; i++ {
+		inviteCode, err := generateInviteCode()
+		if err != nil {
+			return "", err
+		}
+
+		ok, err := s.userRepo.SetInviteCode(ctx, userID, inviteCode)
+		if _, conflict := database.UniqueViolation(err); conflict {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if !ok {
+			// 并发请求已经生成了邀请码，以数据库中的为准
+			user, err := s.userRepo.GetByID(ctx, userID)
+			if err != nil {
+				return "", err
+			}
+			return user.InviteCode, nil
+		}
+
+		s.increment(ctx, referralIssuedKey)
+		return inviteCode, nil
+	}
+	return "", fmt.Errorf("generate invite code for user %d: too many conflicts", userID)
+}
+
+func (s *referralService) ResolveInviteCode(ctx common.Context, inviteCode string) (*model.User, error) {
+	s.increment(ctx, referralAttemptsKey)
+
+	referrer, err := s.userRepo.GetByInviteCode(ctx, strings.ToUpper(inviteCode))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInviteCodeInvalid
+		}
+		return nil, err
+	}
+	return referrer, nil
+}
+
+func (s *referralService) Credit(ctx common.Context, referrer *model.User) {
+	s.increment(ctx, referralSignupsKey)
+	s.increment(ctx, s.getCreditsKey(referrer.ID))
+}
+
+func (s *referralService) GetStats(ctx common.Context) (*dto.ReferralStatsResponse, error) {
+	counts, err := s.userRepo.ReferralCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := s.userRepo.TopReferrers(ctx, topReferrersLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &dto.ReferralStatsResponse{
+		IssuedCodes:    counts.Issued,
+		Referrers:      counts.Referrers,
+		ReferredUsers:  counts.Referred,
+		SignupAttempts: s.counter(ctx, referralAttemptsKey),
+		Signups:        s.counter(ctx, referralSignupsKey),
+		TopReferrers:   make([]*dto.ReferrerStat, 0, len(top)),
+	}
+	if stats.IssuedCodes > 0 {
+		stats.CodeConversionRate = float64(stats.Referrers) / float64(stats.IssuedCodes)
+	}
+	if stats.SignupAttempts > 0 {
+		stats.SignupConversionRate = float64(stats.Signups) / float64(stats.SignupAttempts)
+	}
+
+	for _, item := range top {
+		stats.TopReferrers = append(stats.TopReferrers, &dto.ReferrerStat{
+			UserID:   hashid.ID(item.ReferrerID),
+			Username: item.Username,
+			Invited:  item.Invited,
+		})
+	}
+	return stats, nil
+}
+
+// increment 计数失败只记录日志，不影响注册等主流程；Redis 被禁用时不记录
+func (s *referralService) increment(ctx common.Context, key string) {
+	_, err := s.redisCache.Increment(key, redis.WithTrace(ctx.Trace()))
+	if err != nil && !errors.Is(err, redis.ErrDisabled) {
+		logger.Module(ctx.Logger(), "service").Warn("referral counter increment failed",
+			zap.String("key", key),
+			zap.Error(err),
+		)
+	}
+}
+
+// counter 读取计数，键不存在或 Redis 不可用时返回 0
+func (s *referralService) counter(ctx common.Context, key string) int64 {
+	exists, err := s.redisCache.Exists(key)
+	if err != nil || !exists {
+		return 0
+	}
+
+	value, err := s.redisCache.Get(key, redis.WithTrace(ctx.Trace()))
+	if err != nil {
+		return 0
+	}
+
+	n, _ := strconv.ParseInt(value, 10, 64)
+	return n
+}
+
+func generateInviteCode() (string, error) {
+	max := big.NewInt(int64(len(inviteCodeCharset)))
+	b := make([]byte, inviteCodeLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = inviteCodeCharset[n.Int64()]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/repository"
+)
+
+// 用户设置项，嵌套的设置用 . 分隔
+const (
+	SettingLocale                = "locale"
+	SettingTheme                 = "theme"
+	SettingNotificationEmail     = "notifications.email"
+	SettingNotificationSMS       = "notifications.sms"
+	SettingNotificationBroadcast = "notifications.broadcast"
+)
+
+// settingSpec 设置项的类型和取值范围，类型由默认值决定(string 或 bool)
+type settingSpec struct {
+	def   any
+	oneOf []string // string 类型的可选值，为空时不限制
+}
+
+var _ SettingsService = (*settingsService)(nil)
+
+// SettingsService 用户偏好设置
+//
+// 设置项以键值对保存在 user_settings.settings(JSONB)中，只保存用户改过的项；
+// 读取时按 schema 补全默认值，已从 schema 删除的项不再返回。
+type SettingsService interface {
+	// Get 用户的全部设置项，包括默认值
+	Get(ctx common.Context, userID uint) (UserSettings, error)
+	// Update 修改 patch 中的设置项，值为 null 时恢复默认值，校验失败时返回 *SettingsError
+	Update(ctx common.Context, userID uint, patch map[string]any) (UserSettings, error)
+}
+
+type settingsService struct {
+	settingRepo repository.UserSettingRepository
+	schema      map[string]settingSpec
+}
+
+// NewSettingsService defaultLocale 为用户未设置语言时的默认语言，通常为 language.local(不区分大小写)
+func NewSettingsService(settingRepo repository.UserSettingRepository, defaultLocale string) SettingsService {
+	return &settingsService{
+		settingRepo: settingRepo,
+		schema: map[string]settingSpec{
+			SettingLocale:                {def: strings.ToLower(defaultLocale), oneOf: []string{"zh-cn", "en-us"}},
+			SettingTheme:                 {def: "system", oneOf: []string{"light", "dark", "system"}},
+			SettingNotificationEmail:     {def: true},
+			SettingNotificationSMS:       {def: false},
+			SettingNotificationBroadcast: {def: true},
+		},
+	}
+}
+
+// UserSettings 补全默认值后的用户设置
+type UserSettings map[string]any
+
+// String string 类型设置项的值
+func (s UserSettings) String(key string) string {
+	v, _ := s[key].(string)
+	return v
+}
+
+// Bool bool 类型设置项的值
+func (s UserSettings) Bool(key string) bool {
+	v, _ := s[key].(bool)
+	return v
+}
+
+// SettingsError 设置项校验失败，Details 为逐项的错误
+type SettingsError struct {
+	Details []common.FieldError
+}
+
+func (e *SettingsError) Error() string {
+	var message string
+	for _, d := range e.Details {
+		message += d.Message + ";"
+	}
+	return message
+}
+
+func (s *settingsService) Get(ctx common.Context, userID uint) (UserSettings, error) {
+	saved, err := s.settingRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withDefaults(saved), nil
+}
+
+func (s *settingsService) Update(ctx common.Context, userID uint, patch map[string]any) (UserSettings, error) {
+	set, unset, err := s.validate(patch)
+	if err != nil {
+		return nil, err
+	}
+	if len(set) == 0 && len(unset) == 0 {
+		return s.Get(ctx, userID)
+	}
+
+	saved, err := s.settingRepo.Patch(ctx, userID, set, unset)
+	if err != nil {
+		return nil, err
+	}
+	return s.withDefaults(saved), nil
+}
+
+// validate 按 schema 校验 patch，返回要保存和要删除(恢复默认值)的设置项
+func (s *settingsService) validate(patch map[string]any) (map[string]any, []string, error) {
+	set := make(map[string]any, len(patch))
+	var unset []string
+	var details []common.FieldError
+
+	// 按键排序，错误顺序稳定
+	keys := make([]string, 0, len(patch))
+	for key := range patch {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := patch[key]
+		spec, ok := s.schema[key]
+		if !ok {
+			details = append(details, settingError(key, "unknown", fmt.Sprintf("%s is not a known setting", key)))
+			continue
+		}
+		if value == nil {
+			unset = append(unset, key)
+			continue
+		}
+
+		switch spec.def.(type) {
+		case bool:
+			if _, ok := value.(bool); !ok {
+				details = append(details, settingError(key, "type", fmt.Sprintf("%s must be of type bool", key)))
+				continue
+			}
+		case string:
+			str, ok := value.(string)
+			if !ok {
+				details = append(details, settingError(key, "type", fmt.Sprintf("%s must be of type string", key)))
+				continue
+			}
+			if len(spec.oneOf) > 0 && !containsString(spec.oneOf, str) {
+				details = append(details, settingError(key, "oneof", fmt.Sprintf("%s must be one of [%s]", key, strings.Join(spec.oneOf, " "))))
+				continue
+			}
+		}
+		set[key] = value
+	}
+
+	if len(details) > 0 {
+		return nil, nil, &SettingsError{Details: details}
+	}
+	return set, unset, nil
+}
+
+// withDefaults 按 schema 补全默认值，类型与 schema 不符的已保存值(schema 变更前保存的)按未设置处理
+func (s *settingsService) withDefaults(saved map[string]any) UserSettings {
+	settings := make(UserSettings, len(s.schema))
+	for key, spec := range s.schema {
+		settings[key] = spec.def
+		if value, ok := saved[key]; ok && fmt.Sprintf("%T", value) == fmt.Sprintf("%T", spec.def) {
+			settings[key] = value
+		}
+	}
+	return settings
+}
+
+func settingError(key, rule, message string) common.FieldError {
+	return common.FieldError{
+		// 设置项的键是扁平的，. 不是层级分隔符
+		Field:   "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key),
+		Rule:    rule,
+		Message: message,
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/carrier"
+	"gin-app-start/pkg/database"
+	"gin-app-start/pkg/errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultShipmentPollInterval = 30 * time.Minute
+	defaultShipmentBatchSize    = 100
+	// shipmentTrackTimeout 单次查询承运商接口的超时时间
+	shipmentTrackTimeout = 10 * time.Second
+)
+
+var _ ShipmentService = (*shipmentService)(nil)
+
+// ShipmentService 物流跟踪: 管理端为订单登记包裹，物流事件来自承运商回调、后台轮询或管理端录入
+type ShipmentService interface {
+	CreateShipment(ctx common.Context, req *dto.CreateShipmentRequest) (*model.Shipment, error)
+	AddEvents(ctx common.Context, id uint, events []*model.ShipmentEvent) (*model.Shipment, error)
+	ListOrderShipments(ctx common.Context, actor Actor, orderNumber string) ([]*model.Shipment, error)
+	GetShipment(ctx common.Context, actor Actor, id uint) (*model.Shipment, error)
+
+	// VerifyWebhook 校验承运商回调的签名，未配置密钥的承运商一律返回 false
+	VerifyWebhook(carrierName string, body []byte, signature string) bool
+	// HandleWebhook 记录承运商推送的物流事件，返回新记录的事件数
+	HandleWebhook(ctx common.Context, carrierName string, req *dto.ShipmentWebhookRequest) (int64, error)
+
+	// Start 启动后台轮询任务，只轮询 trackers 中已接入的承运商
+	Start()
+	// Stop 停止后台轮询任务
+	Stop()
+}
+
+type shipmentService struct {
+	shipmentRepo repository.ShipmentRepository
+	orderRepo    repository.OrderRepository
+	trackers     map[string]carrier.Tracker // 承运商 -> 查询接口
+	secrets      map[string]string          // 承运商 -> 回调签名密钥
+
+	interval  time.Duration
+	batchSize int
+	logger    *zap.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewShipmentService trackers 为已接入查询接口的承运商，键为承运商代码(小写)，可以为空
+func NewShipmentService(shipmentRepo repository.ShipmentRepository, orderRepo repository.OrderRepository, trackers map[string]carrier.Tracker, cfg config.ShipmentConfig, logger *zap.Logger) ShipmentService {
+	interval := time.Duration(cfg.PollInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultShipmentPollInterval
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultShipmentBatchSize
+	}
+
+	return &shipmentService{
+		shipmentRepo: shipmentRepo,
+		orderRepo:    orderRepo,
+		trackers:     trackers,
+		secrets:      cfg.WebhookSecrets,
+		interval:     interval,
+		batchSize:    batchSize,
+		logger:       logger,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+func (s *shipmentService) CreateShipment(ctx common.Context, req *dto.CreateShipmentRequest) (*model.Shipment, error) {
+	order, err := s.orderRepo.GetOrderByOrderNumber(ctx, req.OrderNumber)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shipment := &model.Shipment{
+		OrderID:        order.ID,
+		Carrier:        strings.ToLower(req.Carrier),
+		TrackingNumber: req.TrackingNumber,
+		Status:         model.ShipmentPending,
+	}
+	if err := s.shipmentRepo.Create(ctx, shipment); err != nil {
+		if _, ok := database.UniqueViolation(err); ok {
+			return nil, ErrShipmentExists
+		}
+		return nil, err
+	}
+	return shipment, nil
+}
+
+func (s *shipmentService) AddEvents(ctx common.Context, id uint, events []*model.ShipmentEvent) (*model.Shipment, error) {
+	if _, err := s.getShipment(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.shipmentRepo.AddEvents(ctx, id, events); err != nil {
+		return nil, err
+	}
+	return s.getShipment(ctx, id)
+}
+
+func (s *shipmentService) getShipment(ctx common.Context, id uint) (*model.Shipment, error) {
+	shipment, err := s.shipmentRepo.GetByID(ctx, id)
+	if err == gorm.ErrRecordNotFound {
+		return nil, ErrShipmentNotFound
+	}
+	return shipment, err
+}
+
+func (s *shipmentService) ListOrderShipments(ctx common.Context, actor Actor, orderNumber string) ([]*model.Shipment, error) {
+	order, err := s.orderRepo.GetOrderByOrderNumber(ctx, orderNumber)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := actor.authorizeOrder(order); err != nil {
+		return nil, err
+	}
+	return s.shipmentRepo.ListByOrder(ctx, order.ID)
+}
+
+// GetShipment 按ID查询包裹，只有订单所属用户和管理员可以查看
+func (s *shipmentService) GetShipment(ctx common.Context, actor Actor, id uint) (*model.Shipment, error) {
+	shipment, err := s.getShipment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderRepo.GetByID(ctx, shipment.OrderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 订单已删除，包裹不再对外展示
+		return nil, ErrShipmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := actor.authorizeOrder(order); err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+func (s *shipmentService) VerifyWebhook(carrierName string, body []byte, signature string) bool {
+	return carrier.Verify(s.secrets[strings.ToLower(carrierName)], body, signature)
+}
+
+func (s *shipmentService) HandleWebhook(ctx common.Context, carrierName string, req *dto.ShipmentWebhookRequest) (int64, error) {
+	shipment, err := s.shipmentRepo.GetByTracking(ctx, strings.ToLower(carrierName), req.TrackingNumber)
+	if err == gorm.ErrRecordNotFound {
+		return 0, ErrShipmentNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return s.shipmentRepo.AddEvents(ctx, shipment.ID, dto.NewShipmentEvents(req.Events))
+}
+
+func (s *shipmentService) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.poll()
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *shipmentService) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+func (s *shipmentService) stopping() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// carriers 已接入查询接口的承运商
+func (s *shipmentService) carriers() []string {
+	names := make([]string, 0, len(s.trackers))
+	for name := range s.trackers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// poll 领取一批到期的包裹并向承运商查询最新物流事件
+func (s *shipmentService) poll() {
+	carriers := s.carriers()
+	if len(carriers) == 0 {
+		return
+	}
+
+	ctx := common.NewBackgroundContext(s.logger)
+	shipments, err := s.shipmentRepo.ClaimPollable(ctx, carriers, time.Now().Add(-s.interval), s.batchSize)
+	if err != nil {
+		s.logger.Error("claim pollable shipments failed", zap.Error(err))
+		return
+	}
+
+	for _, shipment := range shipments {
+		if s.stopping() {
+			return
+		}
+		s.track(ctx, shipment)
+	}
+}
+
+func (s *shipmentService) track(ctx common.Context, shipment *model.Shipment) {
+	logger := s.logger.With(
+		zap.Uint("shipment_id", shipment.ID),
+		zap.String("carrier", shipment.Carrier),
+	)
+
+	trackCtx, cancel := context.WithTimeout(context.Background(), shipmentTrackTimeout)
+	events, err := s.trackers[shipment.Carrier].Track(trackCtx, shipment.TrackingNumber)
+	cancel()
+	if err != nil {
+		logger.Warn("track shipment failed", zap.Error(err))
+		return
+	}
+
+	records := make([]*model.ShipmentEvent, 0, len(events))
+	for _, event := range events {
+		records = append(records, &model.ShipmentEvent{
+			Status:      event.Status,
+			Location:    event.Location,
+			Description: event.Description,
+			OccurredAt:  event.OccurredAt,
+		})
+	}
+
+	inserted, err := s.shipmentRepo.AddEvents(ctx, shipment.ID, records)
+	if err != nil {
+		logger.Error("save shipment events failed", zap.Error(err))
+		return
+	}
+	if inserted > 0 {
+		logger.Info("shipment events updated", zap.Int64("inserted", inserted))
+	}
+}
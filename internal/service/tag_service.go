@@ -0,0 +1,217 @@
+package service
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/dto"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/timeutil"
+
+	"gorm.io/gorm"
+)
+
+// segmentExportBatchSize 导出分群时每批读取的用户数
+const segmentExportBatchSize = 500
+
+var _ TagService = (*tagService)(nil)
+
+// TagService 用户标签和分群: 给用户打标签，按标签圈选用户，导出分群用户用于定向通知
+type TagService interface {
+	TagUser(ctx common.Context, userID uint, names []string) ([]*model.Tag, error)
+	UntagUser(ctx common.Context, userID uint, name string) error
+	ListUserTags(ctx common.Context, userID uint) ([]*model.Tag, error)
+	ListTags(ctx common.Context) ([]*dto.TagResponse, error)
+
+	// ResolveSegment 解析分群标签，返回 false 表示分群必然为空(标签都不存在，或 match=all 时有标签不存在)
+	ResolveSegment(ctx common.Context, tags, match string) ([]uint, bool, error)
+	// ListSegment 分页查询分群内的用户
+	ListSegment(ctx common.Context, query *dto.SegmentQuery) ([]*model.User, int64, error)
+	// ExportSegment 以 CSV 格式写出分群内的全部用户
+	ExportSegment(ctx common.Context, query *dto.SegmentQuery, w io.Writer) error
+}
+
+type tagService struct {
+	tagRepo  repository.TagRepository
+	userRepo repository.UserRepository
+}
+
+func NewTagService(tagRepo repository.TagRepository, userRepo repository.UserRepository) TagService {
+	return &tagService{
+		tagRepo:  tagRepo,
+		userRepo: userRepo,
+	}
+}
+
+// normalizeTagNames 标签名统一转为小写并去重，忽略空白
+func normalizeTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		res = append(res, name)
+	}
+	return res
+}
+
+func (s *tagService) TagUser(ctx common.Context, userID uint, names []string) ([]*model.Tag, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	names = normalizeTagNames(names)
+	if len(names) == 0 {
+		return nil, ErrTagNotFound
+	}
+
+	tags, err := s.tagRepo.GetOrCreate(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs := make([]uint, 0, len(tags))
+	for _, tag := range tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	if err := s.tagRepo.AddUserTags(ctx, userID, tagIDs); err != nil {
+		return nil, err
+	}
+
+	return s.tagRepo.ListUserTags(ctx, userID)
+}
+
+func (s *tagService) UntagUser(ctx common.Context, userID uint, name string) error {
+	tags, err := s.tagRepo.GetByNames(ctx, normalizeTagNames([]string{name}))
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return ErrTagNotFound
+	}
+
+	removed, err := s.tagRepo.RemoveUserTag(ctx, userID, tags[0].ID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+func (s *tagService) ListUserTags(ctx common.Context, userID uint) ([]*model.Tag, error) {
+	return s.tagRepo.ListUserTags(ctx, userID)
+}
+
+func (s *tagService) ListTags(ctx common.Context) ([]*dto.TagResponse, error) {
+	tags, err := s.tagRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		users := tag.Users
+		res = append(res, &dto.TagResponse{
+			ID:        tag.ID,
+			Name:      tag.Name,
+			Users:     &users,
+			CreatedAt: tag.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (s *tagService) ResolveSegment(ctx common.Context, tags, match string) ([]uint, bool, error) {
+	names := normalizeTagNames(strings.Split(tags, ","))
+	if len(names) == 0 {
+		return nil, false, nil
+	}
+
+	found, err := s.tagRepo.GetByNames(ctx, names)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(found) == 0 || (match == "all" && len(found) < len(names)) {
+		return nil, false, nil
+	}
+
+	tagIDs := make([]uint, 0, len(found))
+	for _, tag := range found {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	return tagIDs, true, nil
+}
+
+// ListSegment query 中未指定的分页参数会被填充为默认值
+func (s *tagService) ListSegment(ctx common.Context, query *dto.SegmentQuery) ([]*model.User, int64, error) {
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = 10
+	}
+
+	tagIDs, ok, err := s.ResolveSegment(ctx, query.Tags, query.Match)
+	if err != nil || !ok {
+		return []*model.User{}, 0, err
+	}
+
+	return s.tagRepo.ListUsersByTags(ctx, tagIDs, query.Match == "all", (query.Page-1)*query.PageSize, query.PageSize)
+}
+
+func (s *tagService) ExportSegment(ctx common.Context, query *dto.SegmentQuery, w io.Writer) error {
+	tagIDs, ok, err := s.ResolveSegment(ctx, query.Tags, query.Match)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"id", "username", "email", "phone", "created_at"}); err != nil {
+		return err
+	}
+
+	if ok {
+		err = s.tagRepo.EachUserByTags(ctx, tagIDs, query.Match == "all", segmentExportBatchSize, func(users []*model.User) error {
+			for _, user := range users {
+				record := []string{
+					strconv.FormatUint(uint64(user.ID), 10),
+					csvSafe(user.Username),
+					csvSafe(user.Email),
+					csvSafe(user.Phone),
+					user.CreatedAt.Format(timeutil.CSTLayout),
+				}
+				if err := writer.Write(record); err != nil {
+					return err
+				}
+			}
+			writer.Flush()
+			return writer.Error()
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe 以 = + - @ 开头的值在表格软件中会被当作公式执行，前面加单引号转为文本
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
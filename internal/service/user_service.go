@@ -1,15 +1,18 @@
 package service
 
 import (
-	"crypto/md5"
-	"crypto/rand"
-	"encoding/hex"
 	"gin-app-start/internal/common"
 	"gin-app-start/internal/dto"
+	"gin-app-start/internal/hooks"
+	"gin-app-start/internal/lockout"
 	"gin-app-start/internal/model"
 	"gin-app-start/internal/repository"
 	"gin-app-start/pkg/errors"
+	"gin-app-start/pkg/logger"
+	"gin-app-start/pkg/password"
+	"gin-app-start/pkg/queryfilter"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
@@ -20,18 +23,34 @@ type UserService interface {
 	UploadImage(ctx common.Context, username, filename string) error
 	GetUser(ctx common.Context, id uint) (*model.User, error)
 	GetUserByUsername(ctx common.Context, username string) (*model.User, error)
+	// UpdateUser 更新用户信息，请求中的零值表示不修改
 	UpdateUser(ctx common.Context, id uint, req *dto.UpdateUserRequest) (*model.User, error)
+	// PatchUser 部分更新用户信息，只修改 req 中不为 nil 的字段
+	PatchUser(ctx common.Context, id uint, req *dto.PatchUserRequest) (*model.User, error)
 	DeleteUser(ctx common.Context, id uint) error
-	ListUsers(ctx common.Context, page, pageSize int) ([]*model.User, int64, error)
+	// ListUsers 分页查询符合过滤条件的用户，过滤条件和排序见 repository.UserQuery
+	ListUsers(ctx common.Context, filter queryfilter.Query, page, pageSize int) ([]*model.User, int64, error)
+	// ListUsersAfter 游标分页查询符合过滤条件的用户，按ID升序返回ID大于 afterID 的一页，more 表示是否还有下一页
+	ListUsersAfter(ctx common.Context, filter queryfilter.Query, afterID uint, pageSize int) (users []*model.User, more bool, err error)
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo  repository.UserRepository
+	referral  ReferralService
+	passwords PasswordPolicy
+	hasher    *password.Hasher
+	lockout   *lockout.Guard // 未启用登录锁定时为 nil
+	hooks     *hooks.Registry
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, referral ReferralService, passwords PasswordPolicy, hasher *password.Hasher, guard *lockout.Guard, hookRegistry *hooks.Registry) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:  userRepo,
+		referral:  referral,
+		passwords: passwords,
+		hasher:    hasher,
+		lockout:   guard,
+		hooks:     hookRegistry,
 	}
 }
 
@@ -42,7 +61,7 @@ func (s *userService) CreateUser(ctx common.Context, req *dto.CreateUserRequest)
 	}
 
 	if existingUser != nil {
-		return nil, errors.New("User already exists")
+		return nil, ErrUserExists
 	}
 
 	if req.Email != "" {
@@ -51,45 +70,124 @@ func (s *userService) CreateUser(ctx common.Context, req *dto.CreateUserRequest)
 			return nil, err
 		}
 		if existingUser != nil {
-			return nil, errors.New("Email already exists")
+			return nil, ErrEmailExists
 		}
 	}
 
-	salt := generateSalt()
-	hashedPassword := hashPassword(req.Password, salt)
+	if err := s.passwords.Check(ctx, req.Password, req.Username); err != nil {
+		return nil, err
+	}
+
+	// 携带邀请码时先确认邀请人存在，无效的邀请码直接拒绝注册，避免用户以为已绑定邀请关系
+	var referrer *model.User
+	if req.InviteCode != "" {
+		if referrer, err = s.referral.ResolveInviteCode(ctx, req.InviteCode); err != nil {
+			return nil, err
+		}
+	}
+
+	// 盐值保存在哈希中，salt 列只有旧版 MD5 哈希使用
+	hashedPassword, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		return nil, err
+	}
 
 	user := &model.User{
 		Username: req.Username,
 		Email:    req.Email,
 		Phone:    req.Phone,
 		Password: hashedPassword,
-		Salt:     salt,
 		Status:   1,
 	}
+	if referrer != nil {
+		user.ReferrerID = referrer.ID
+	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
-		return nil, err
+		return nil, translateUserConflict(err)
+	}
+
+	if referrer != nil {
+		s.referral.Credit(ctx, referrer)
 	}
+	s.hooks.UserCreated(ctx, user)
 
 	return user, nil
 }
 
 func (s *userService) Login(ctx common.Context, req *dto.LoginRequest) (*model.User, error) {
+	ip := ctx.GetGinContext().ClientIP()
+
+	// 已锁定的账号或 IP 直接拒绝，不再校验密码
+	if s.lockout != nil {
+		if err := s.lockout.Check(ctx.RequestContext(), req.Username, ip); err != nil {
+			var locked *lockout.LockedError
+			if errors.As(err, &locked) {
+				return nil, err
+			}
+			logger.Module(ctx.Logger(), "service").Warn("login lockout check failed, skipped", zap.Error(err))
+		}
+	}
+
 	user, err := s.userRepo.GetByUsername(ctx, req.Username)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
+			// 不存在的用户名同样计入失败次数，避免借此探测用户名
+			s.loginFailed(ctx, req.Username, ip)
 			return nil, err
 		}
 		return nil, err
 	}
 
-	if !VerifyPassword(req.Password, user.Salt, user.Password) {
+	ok, err := s.hasher.Verify(req.Password, user.Password, user.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.loginFailed(ctx, req.Username, ip)
 		return nil, errors.New("Password not match")
 	}
+	s.rehash(ctx, user, req.Password)
+
+	if s.lockout != nil {
+		if err := s.lockout.Succeed(ctx.RequestContext(), req.Username); err != nil {
+			logger.Module(ctx.Logger(), "service").Warn("login lockout reset failed", zap.Error(err))
+		}
+	}
 
 	return user, nil
 }
 
+// rehash 密码校验通过后，旧版 MD5 或按旧配置计算的哈希用当前算法重新计算，失败只记录日志，下次登录再试
+func (s *userService) rehash(ctx common.Context, user *model.User, plain string) {
+	if !s.hasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashed, err := s.hasher.Hash(plain)
+	if err == nil {
+		err = s.userRepo.UpdateFields(ctx, user.ID, map[string]interface{}{
+			"salt":     "",
+			"password": hashed,
+		})
+	}
+	if err != nil {
+		logger.Module(ctx.Logger(), "service").Warn("password rehash failed", zap.Uint("user_id", user.ID), zap.Error(err))
+		return
+	}
+	user.Password, user.Salt = hashed, ""
+}
+
+// loginFailed 记录一次登录失败，Redis 不可用时只记录日志
+func (s *userService) loginFailed(ctx common.Context, username, ip string) {
+	if s.lockout == nil {
+		return
+	}
+	if err := s.lockout.Fail(ctx.RequestContext(), username, ip); err != nil {
+		logger.Module(ctx.Logger(), "service").Warn("login failure record failed", zap.Error(err))
+	}
+}
+
 func (s *userService) UpdatePassword(ctx common.Context, req *dto.UpdatePasswordRequest) error {
 	user, err := s.GetUserByUsername(ctx, req.Username)
 	if err != nil {
@@ -100,19 +198,28 @@ func (s *userService) UpdatePassword(ctx common.Context, req *dto.UpdatePassword
 	}
 
 	// 验证旧密码是否匹配
-	if !VerifyPassword(req.OldPassword, user.Salt, user.Password) {
+	ok, err := s.hasher.Verify(req.OldPassword, user.Password, user.Salt)
+	if err != nil {
+		return err
+	}
+	if !ok {
 		return errors.New("Old password error")
 	}
 
-	// 生成新的盐值和哈希密码
-	newSalt := generateSalt()
-	newHashedPassword := hashPassword(req.NewPassword, newSalt)
+	if err := s.passwords.Check(ctx, req.NewPassword, user.Username); err != nil {
+		return err
+	}
 
-	// 更新用户密码和盐值
-	user.Salt = newSalt
-	user.Password = newHashedPassword
+	newHashedPassword, err := s.hasher.Hash(req.NewPassword)
+	if err != nil {
+		return err
+	}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	// 只更新用户密码，清空旧版 MD5 哈希使用的盐值
+	if err := s.userRepo.UpdateFields(ctx, user.ID, map[string]interface{}{
+		"salt":     "",
+		"password": newHashedPassword,
+	}); err != nil {
 		return err
 	}
 
@@ -128,8 +235,7 @@ func (s *userService) UploadImage(ctx common.Context, username, filename string)
 		return err
 	}
 
-	user.Avatar = filename
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	if err := s.userRepo.UpdateFields(ctx, user.ID, map[string]interface{}{"avatar": filename}); err != nil {
 		return err
 	}
 
@@ -159,6 +265,24 @@ func (s *userService) GetUserByUsername(ctx common.Context, username string) (*m
 }
 
 func (s *userService) UpdateUser(ctx common.Context, id uint, req *dto.UpdateUserRequest) (*model.User, error) {
+	// 零值表示不修改，转换为只包含非零值字段的部分更新
+	patch := &dto.PatchUserRequest{}
+	if req.Email != "" {
+		patch.Email = &req.Email
+	}
+	if req.Phone != "" {
+		patch.Phone = &req.Phone
+	}
+	if req.Avatar != "" {
+		patch.Avatar = &req.Avatar
+	}
+	if req.Status != 0 {
+		patch.Status = &req.Status
+	}
+	return s.PatchUser(ctx, id, patch)
+}
+
+func (s *userService) PatchUser(ctx common.Context, id uint, req *dto.PatchUserRequest) (*model.User, error) {
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -167,21 +291,27 @@ func (s *userService) UpdateUser(ctx common.Context, id uint, req *dto.UpdateUse
 		return nil, err
 	}
 
-	if req.Email != "" {
-		user.Email = req.Email
+	// 只更新请求中出现的列
+	fields := make(map[string]interface{})
+	if req.Email != nil {
+		user.Email = *req.Email
+		fields["email"] = *req.Email
 	}
-	if req.Phone != "" {
-		user.Phone = req.Phone
+	if req.Phone != nil {
+		user.Phone = *req.Phone
+		fields["phone"] = *req.Phone
 	}
-	if req.Avatar != "" {
-		user.Avatar = req.Avatar
+	if req.Avatar != nil {
+		user.Avatar = *req.Avatar
+		fields["avatar"] = *req.Avatar
 	}
-	if req.Status != 0 {
-		user.Status = req.Status
+	if req.Status != nil {
+		user.Status = *req.Status
+		fields["status"] = *req.Status
 	}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		return nil, err
+	if err := s.userRepo.UpdateFields(ctx, user.ID, fields); err != nil {
+		return nil, translateUserConflict(err)
 	}
 
 	return user, nil
@@ -195,7 +325,7 @@ func (s *userService) DeleteUser(ctx common.Context, id uint) error {
 	return nil
 }
 
-func (s *userService) ListUsers(ctx common.Context, page, pageSize int) ([]*model.User, int64, error) {
+func (s *userService) ListUsers(ctx common.Context, filter queryfilter.Query, page, pageSize int) ([]*model.User, int64, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -207,7 +337,7 @@ func (s *userService) ListUsers(ctx common.Context, page, pageSize int) ([]*mode
 	}
 
 	offset := (page - 1) * pageSize
-	users, total, err := s.userRepo.List(ctx, offset, pageSize)
+	users, total, err := s.userRepo.List(ctx, filter, offset, pageSize)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -215,17 +345,21 @@ func (s *userService) ListUsers(ctx common.Context, page, pageSize int) ([]*mode
 	return users, total, nil
 }
 
-func generateSalt() string {
-	salt := make([]byte, 16)
-	rand.Read(salt)
-	return hex.EncodeToString(salt)
-}
-
-func hashPassword(password, salt string) string {
-	hash := md5.Sum([]byte(password + salt))
-	return hex.EncodeToString(hash[:])
-}
+func (s *userService) ListUsersAfter(ctx common.Context, filter queryfilter.Query, afterID uint, pageSize int) ([]*model.User, bool, error) {
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
 
-func VerifyPassword(password, salt, hashedPassword string) bool {
-	return hashPassword(password, salt) == hashedPassword
+	// 多查一条判断是否还有下一页
+	users, err := s.userRepo.ListAfter(ctx, filter, afterID, pageSize+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(users) > pageSize {
+		return users[:pageSize], true, nil
+	}
+	return users, false, nil
 }
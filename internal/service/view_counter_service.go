@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/metrics"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/redis"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/errors"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultViewFlushInterval = 10 * time.Second
+	defaultViewBatchSize     = 500
+
+	// viewPendingKeyPrefix 未落库的浏览增量，每类对象一个 hash: views:pending:{subject}，字段为对象ID
+	viewPendingKeyPrefix = "views:pending:"
+	// viewFlushingKeyPrefix 正在落库的增量，落库前把 pending 改名为该键，之后的浏览累加到新的 pending 中
+	viewFlushingKeyPrefix = "views:flushing:"
+	// viewFlushLockKey 多实例同时落库会重复写入同一批增量，落库期间持有该锁
+	viewFlushLockKey = "views:flush_lock"
+	viewFlushLockTTL = time.Minute
+)
+
+// viewSubjects 需要落库的对象类型
+var viewSubjects = []string{model.ViewSubjectOrder, model.ViewSubjectProduct}
+
+var _ ViewCounterService = (*viewCounterService)(nil)
+
+// ViewCounterService 订单、商品浏览计数
+//
+// Redis 启用时浏览只在 Redis hash views:pending:{subject} 中累加，后台任务定期把增量改名为
+// views:flushing:{subject} 后分批累加到数据库；Redis 禁用时每次浏览直接写库。
+// 写入或落库失败时按 views.loss_tolerant 丢弃或重试，见 config.ViewsConfig。
+type ViewCounterService interface {
+	// Record 记录一次浏览，允许丢失计数时 Redis 写入失败也返回 nil
+	Record(ctx common.Context, subject, subjectID string) error
+	// Count 累计浏览数，包括尚未落库的增量
+	Count(ctx common.Context, subject, subjectID string) (int64, error)
+
+	// Start 启动后台落库任务
+	Start()
+	// Stop 停止后台落库任务，停止前把未落库的增量写入数据库
+	Stop()
+}
+
+type viewCounterService struct {
+	viewRepo    repository.ViewCountRepository
+	redisCache  redis.RedisRepository
+	writeBehind bool // Redis 启用时为 true
+
+	interval     time.Duration
+	batchSize    int
+	lossTolerant bool
+	logger       *zap.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewViewCounterService writeBehind 为 false(Redis 禁用)时浏览直接写入数据库，无需启动后台任务
+func NewViewCounterService(viewRepo repository.ViewCountRepository, redisCache redis.RedisRepository, writeBehind bool, cfg config.ViewsConfig, logger *zap.Logger) ViewCounterService {
+	interval := time.Duration(cfg.FlushInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultViewFlushInterval
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultViewBatchSize
+	}
+
+	return &viewCounterService{
+		viewRepo:     viewRepo,
+		redisCache:   redisCache,
+		writeBehind:  writeBehind,
+		interval:     interval,
+		batchSize:    batchSize,
+		lossTolerant: cfg.LossTolerant,
+		logger:       logger,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+func (s *viewCounterService) Record(ctx common.Context, subject, subjectID string) error {
+	if s.writeBehind {
+		err := redis.ErrUnavailable
+		if client := s.redisCache.GetRedisClient(); client != nil {
+			err = client.HIncrBy(ctx.RequestContext(), s.redisCache.Key(viewPendingKeyPrefix+subject), subjectID, 1).Err()
+		}
+		if err == nil {
+			metrics.ObserveViews(subject, metrics.ViewsBuffered, 1)
+			return nil
+		}
+		// 允许丢失计数时不影响调用方
+		if s.lossTolerant {
+			metrics.ObserveViews(subject, metrics.ViewsDropped, 1)
+			ctx.Logger().Warn("view dropped", zap.String("subject", subject), zap.Error(err))
+			return nil
+		}
+	}
+
+	if err := s.viewRepo.Add(ctx, subject, map[string]int64{subjectID: 1}); err != nil {
+		return err
+	}
+	metrics.ObserveViews(subject, metrics.ViewsDirect, 1)
+	return nil
+}
+
+func (s *viewCounterService) Count(ctx common.Context, subject, subjectID string) (int64, error) {
+	count, err := s.viewRepo.Get(ctx, subject, subjectID)
+	if err != nil || !s.writeBehind {
+		return count, err
+	}
+
+	// 未落库的增量读取失败时只返回已落库的部分
+	client := s.redisCache.GetRedisClient()
+	if client == nil {
+		return count, nil
+	}
+	for _, prefix := range []string{viewPendingKeyPrefix, viewFlushingKeyPrefix} {
+		n, err := client.HGet(ctx.RequestContext(), s.redisCache.Key(prefix+subject), subjectID).Int64()
+		if err == nil {
+			count += n
+		}
+	}
+	return count, nil
+}
+
+// flush 把一类对象的增量落库
+//
+// 上次落库失败留下的 views:flushing 键优先处理，本轮不再改名 pending；
+// 每批写库成功后从 flushing 中删除这批字段，中途失败时已写入的批次不会重复写入。
+func (s *viewCounterService) flush(ctx common.Context, client *goredis.Client, subject string) error {
+	pendingKey := s.redisCache.Key(viewPendingKeyPrefix + subject)
+	flushingKey := s.redisCache.Key(viewFlushingKeyPrefix + subject)
+	rctx := ctx.RequestContext()
+
+	n, err := client.Exists(rctx, flushingKey).Result()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if err := client.Rename(rctx, pendingKey, flushingKey).Err(); err != nil {
+			// 本轮没有新的浏览
+			if strings.Contains(err.Error(), "no such key") {
+				return nil
+			}
+			return err
+		}
+	}
+
+	values, err := client.HGetAll(rctx, flushingKey).Result()
+	if err != nil {
+		return err
+	}
+
+	fields := make([]string, 0, s.batchSize)
+	counts := make(map[string]int64, s.batchSize)
+	var total int64
+	write := func() error {
+		if err := s.viewRepo.Add(ctx, subject, counts); err != nil {
+			return err
+		}
+		metrics.ObserveViews(subject, metrics.ViewsFlushed, total)
+		if err := client.HDel(rctx, flushingKey, fields...).Err(); err != nil {
+			return err
+		}
+		fields, counts, total = fields[:0], make(map[string]int64, s.batchSize), 0
+		return nil
+	}
+
+	for subjectID, value := range values {
+		fields = append(fields, subjectID)
+		// 无法解析的字段随这一批一起删除
+		if count, err := strconv.ParseInt(value, 10, 64); err == nil && count > 0 {
+			counts[subjectID] = count
+			total += count
+		}
+		if len(fields) >= s.batchSize {
+			if err := write(); err != nil {
+				return s.flushFailed(rctx, client, subject, flushingKey, err)
+			}
+		}
+	}
+	if len(fields) > 0 {
+		if err := write(); err != nil {
+			return s.flushFailed(rctx, client, subject, flushingKey, err)
+		}
+	}
+	return nil
+}
+
+// flushFailed 落库失败时，允许丢失计数则删除剩余的增量，否则保留到下次落库
+func (s *viewCounterService) flushFailed(rctx context.Context, client *goredis.Client, subject, flushingKey string, err error) error {
+	if !s.lossTolerant {
+		return err
+	}
+
+	values, getErr := client.HGetAll(rctx, flushingKey).Result()
+	if getErr == nil {
+		var dropped int64
+		for _, value := range values {
+			count, _ := strconv.ParseInt(value, 10, 64)
+			dropped += count
+		}
+		metrics.ObserveViews(subject, metrics.ViewsDropped, dropped)
+	}
+	if delErr := client.Del(rctx, flushingKey).Err(); delErr != nil {
+		s.logger.Error("drop unflushed views failed", zap.String("subject", subject), zap.Error(delErr))
+	}
+	return err
+}
+
+// flushAll 落库所有类型的增量，多实例之间通过 Redis 锁保证同一时刻只有一个实例在落库
+func (s *viewCounterService) flushAll() {
+	client := s.redisCache.GetRedisClient()
+	if client == nil {
+		return
+	}
+
+	err := s.redisCache.WithLock(viewFlushLockKey, viewFlushLockTTL, func() error {
+		ctx := common.NewBackgroundContext(s.logger)
+		for _, subject := range viewSubjects {
+			if err := s.flush(ctx, client, subject); err != nil {
+				s.logger.Error("flush views failed", zap.String("subject", subject), zap.Bool("dropped", s.lossTolerant), zap.Error(err))
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.ErrLockNotAcquired) {
+		s.logger.Error("views flush lock failed", zap.Error(err))
+	}
+}
+
+func (s *viewCounterService) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				s.flushAll()
+				return
+			case <-ticker.C:
+				s.flushAll()
+			}
+		}
+	}()
+}
+
+func (s *viewCounterService) Stop() {
+	close(s.stop)
+	<-s.done
+}
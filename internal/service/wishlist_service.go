@@ -0,0 +1,269 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"gin-app-start/internal/common"
+	"gin-app-start/internal/config"
+	"gin-app-start/internal/model"
+	"gin-app-start/internal/redis"
+	"gin-app-start/internal/repository"
+	"gin-app-start/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWishlistFlushInterval = time.Minute
+	defaultWishlistMaxItems      = 500
+
+	// wishlistDirtyKey 有未落库修改的用户ID集合
+	wishlistDirtyKey = "wishlist:dirty"
+	// wishlistLoadedMarker 收藏集合从数据库加载后写入的占位成员(商品ID不能为空)，
+	// 集合中没有它说明集合被淘汰后又被重新创建，内容不完整，不能用来覆盖数据库
+	wishlistLoadedMarker = ""
+)
+
+var _ WishlistService = (*wishlistService)(nil)
+
+// WishlistService 收藏夹
+//
+// Redis 启用时收藏和取消收藏只修改 Redis 集合 wishlist:{user_id} 并把用户记入 wishlist:dirty，
+// 后台任务定期把有修改的用户收藏同步到数据库；Redis 禁用时直接读写数据库。
+// 收藏列表从数据库分页读取，读取前先同步该用户未落库的修改。
+type WishlistService interface {
+	Add(ctx common.Context, userID uint, productID string) error
+	Remove(ctx common.Context, userID uint, productID string) error
+	List(ctx common.Context, userID uint, page, pageSize int) ([]*model.Favorite, int64, error)
+
+	// Start 启动后台落库任务
+	Start()
+	// Stop 停止后台落库任务，停止前把所有未落库的修改写入数据库
+	Stop()
+}
+
+type wishlistService struct {
+	wishlistRepo repository.WishlistRepository
+	redisCache   redis.RedisRepository
+	writeBehind  bool // Redis 启用时为 true
+
+	interval time.Duration
+	maxItems int
+	logger   *zap.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWishlistService writeBehind 为 false(Redis 禁用)时收藏直接写入数据库，无需启动后台任务
+func NewWishlistService(wishlistRepo repository.WishlistRepository, redisCache redis.RedisRepository, writeBehind bool, cfg config.WishlistConfig, logger *zap.Logger) WishlistService {
+	interval := time.Duration(cfg.FlushInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultWishlistFlushInterval
+	}
+	maxItems := cfg.MaxItems
+	if maxItems <= 0 {
+		maxItems = defaultWishlistMaxItems
+	}
+
+	return &wishlistService{
+		wishlistRepo: wishlistRepo,
+		redisCache:   redisCache,
+		writeBehind:  writeBehind,
+		interval:     interval,
+		maxItems:     maxItems,
+		logger:       logger,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+func (s *wishlistService) getWishlistKey(userID uint) string {
+	return fmt.Sprintf("wishlist:%d", userID)
+}
+
+// load 收藏集合不存在时从数据库加载，集合中始终包含 wishlistLoadedMarker
+func (s *wishlistService) load(ctx common.Context, userID uint) error {
+	key := s.getWishlistKey(userID)
+	exists, err := s.redisCache.Exists(key)
+	if err != nil || exists {
+		return err
+	}
+
+	productIDs, err := s.wishlistRepo.ListProductIDs(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	members := make([]interface{}, 0, len(productIDs)+1)
+	members = append(members, wishlistLoadedMarker)
+	for _, productID := range productIDs {
+		members = append(members, productID)
+	}
+	return s.redisCache.SetSAdd(key, members...)
+}
+
+func (s *wishlistService) Add(ctx common.Context, userID uint, productID string) error {
+	if !s.writeBehind {
+		productIDs, err := s.wishlistRepo.ListProductIDs(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(productIDs) >= s.maxItems && !utils.Contains(productIDs, productID) {
+			return ErrWishlistFull
+		}
+		return s.wishlistRepo.Add(ctx, userID, productID)
+	}
+
+	if err := s.load(ctx, userID); err != nil {
+		return err
+	}
+
+	key := s.getWishlistKey(userID)
+	exists, err := s.redisCache.SetSIsMember(key, productID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	count, err := s.redisCache.SetSCard(key)
+	if err != nil {
+		return err
+	}
+	// 集合中包含一个占位成员
+	if count-1 >= int64(s.maxItems) {
+		return ErrWishlistFull
+	}
+
+	if err := s.redisCache.SetSAdd(key, productID); err != nil {
+		return err
+	}
+	return s.redisCache.SetSAdd(wishlistDirtyKey, userID)
+}
+
+func (s *wishlistService) Remove(ctx common.Context, userID uint, productID string) error {
+	if !s.writeBehind {
+		return s.wishlistRepo.Remove(ctx, userID, productID)
+	}
+
+	if err := s.load(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.redisCache.SetSRem(s.getWishlistKey(userID), productID); err != nil {
+		return err
+	}
+	return s.redisCache.SetSAdd(wishlistDirtyKey, userID)
+}
+
+func (s *wishlistService) List(ctx common.Context, userID uint, page, pageSize int) ([]*model.Favorite, int64, error) {
+	if s.writeBehind {
+		dirty, err := s.redisCache.SetSIsMember(wishlistDirtyKey, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if dirty {
+			if err := s.flush(ctx, userID); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	return s.wishlistRepo.List(ctx, userID, (page-1)*pageSize, pageSize)
+}
+
+// flush 把用户的收藏集合同步到数据库，失败时把用户重新记入 wishlist:dirty 等待下次落库
+func (s *wishlistService) flush(ctx common.Context, userID uint) error {
+	// 先移出 dirty 集合再读取收藏集合，读取之后的修改会重新记入 dirty 集合，不会丢失
+	if err := s.redisCache.SetSRem(wishlistDirtyKey, userID); err != nil {
+		return err
+	}
+
+	members, err := s.redisCache.SetSMembers(s.getWishlistKey(userID))
+	if err != nil {
+		s.markDirty(userID)
+		return err
+	}
+
+	productIDs := make([]string, 0, len(members))
+	loaded := false
+	for _, member := range members {
+		if member == wishlistLoadedMarker {
+			loaded = true
+			continue
+		}
+		productIDs = append(productIDs, member)
+	}
+	if !loaded {
+		// 集合在加载后被淘汰，淘汰前未落库的修改已经丢失，不能用不完整的集合覆盖数据库；
+		// 删除残缺的集合，下次访问时重新从数据库加载
+		s.logger.Warn("wishlist set evicted before flush, changes dropped", zap.Uint("user_id", userID))
+		return s.redisCache.Delete(s.getWishlistKey(userID))
+	}
+
+	if err := s.wishlistRepo.Sync(ctx, userID, productIDs); err != nil {
+		s.markDirty(userID)
+		return err
+	}
+	return nil
+}
+
+func (s *wishlistService) markDirty(userID uint) {
+	if err := s.redisCache.SetSAdd(wishlistDirtyKey, userID); err != nil {
+		s.logger.Error("mark wishlist dirty failed", zap.Uint("user_id", userID), zap.Error(err))
+	}
+}
+
+func (s *wishlistService) Start() {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				s.flushAll()
+				return
+			case <-ticker.C:
+				s.flushAll()
+			}
+		}
+	}()
+}
+
+func (s *wishlistService) Stop() {
+	close(s.stop)
+	<-s.done
+}
+
+// flushAll 落库所有有未落库修改的用户
+func (s *wishlistService) flushAll() {
+	members, err := s.redisCache.SetSMembers(wishlistDirtyKey)
+	if err != nil {
+		s.logger.Error("list dirty wishlists failed", zap.Error(err))
+		return
+	}
+
+	ctx := common.NewBackgroundContext(s.logger)
+	flushed := 0
+	for _, member := range members {
+		userID, err := strconv.ParseUint(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		if err := s.flush(ctx, uint(userID)); err != nil {
+			s.logger.Error("flush wishlist failed", zap.Uint64("user_id", userID), zap.Error(err))
+			continue
+		}
+		flushed++
+	}
+	if flushed > 0 {
+		s.logger.Info("wishlists flushed", zap.Int("users", flushed))
+	}
+}
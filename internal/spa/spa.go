@@ -0,0 +1,260 @@
+// Package spa 托管前端单页应用的构建目录，单二进制部署时由同一个进程提供接口和前端页面
+package spa
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gin-app-start/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// minGzipSize 小于该大小的文件压缩收益不大，直接返回原文件
+	minGzipSize = 1024
+	// maxGzipSize 即时压缩的结果缓存在内存中，超过该大小的文件只使用预压缩的 .gz 文件
+	maxGzipSize = 8 << 20
+)
+
+// Site 前端构建目录
+//
+// 文件的 ETag 由修改时间和大小生成，请求带 If-None-Match 或 If-Modified-Since 时由 http.ServeContent 返回 304。
+// 客户端接受 gzip 时优先返回构建时生成的同名 .gz 文件，没有时即时压缩文本类文件并缓存压缩结果。
+type Site struct {
+	root    string
+	prefix  string
+	index   string
+	maxAge  time.Duration
+	exclude []string
+
+	mu   sync.Mutex
+	gzip map[string]*gzipped // 文件路径 -> 即时压缩的结果
+}
+
+type gzipped struct {
+	etag string // 原文件的 ETag，文件变化后重新压缩
+	data []byte
+}
+
+// New 校验构建目录，目录中没有 index 文件时返回错误
+func New(cfg config.SPAConfig) (*Site, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("dir is required")
+	}
+	root, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Site{
+		root:    root,
+		prefix:  cfg.Prefix,
+		index:   cfg.Index,
+		maxAge:  time.Duration(cfg.MaxAge) * time.Second,
+		exclude: cfg.Exclude,
+		gzip:    make(map[string]*gzipped),
+	}
+	if s.prefix == "" {
+		s.prefix = "/"
+	}
+	if !strings.HasSuffix(s.prefix, "/") {
+		s.prefix += "/"
+	}
+	if s.index == "" {
+		s.index = "index.html"
+	}
+
+	if info, err := os.Stat(filepath.Join(root, s.index)); err != nil {
+		return nil, fmt.Errorf("index file: %w", err)
+	} else if info.IsDir() {
+		return nil, fmt.Errorf("index file %s is a directory", s.index)
+	}
+	return s, nil
+}
+
+// Serve 返回请求对应的前端文件，找不到文件且路径没有扩展名时返回 index；
+// 返回 false 表示请求不由前端处理(方法不是 GET、HEAD，路径不在 prefix 下或被排除，或静态资源不存在)
+func (s *Site) Serve(c *gin.Context) bool {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+
+	urlPath := c.Request.URL.Path
+	for _, prefix := range s.exclude {
+		if strings.HasPrefix(urlPath, prefix) {
+			return false
+		}
+	}
+
+	// path.Clean 去掉 ..，请求不能访问构建目录以外的文件
+	var rel string
+	switch {
+	case urlPath+"/" == s.prefix:
+		rel = "/"
+	case strings.HasPrefix(urlPath, s.prefix):
+		rel = path.Clean("/" + urlPath[len(s.prefix):])
+	default:
+		return false
+	}
+	if hidden(rel) {
+		return false
+	}
+
+	name := filepath.Join(s.root, filepath.FromSlash(rel))
+	info, err := os.Stat(name)
+	if err == nil && info.IsDir() {
+		name = filepath.Join(name, s.index)
+		info, err = os.Stat(name)
+	}
+	if err == nil && !info.IsDir() {
+		s.serveFile(c, name, info, filepath.Base(name) == s.index)
+		return true
+	}
+
+	// 带扩展名的路径是静态资源，不存在时返回 404，避免以 HTML 响应脚本、图片请求
+	if path.Ext(rel) != "" {
+		return false
+	}
+	name = filepath.Join(s.root, s.index)
+	if info, err = os.Stat(name); err != nil {
+		return false
+	}
+	s.serveFile(c, name, info, true)
+	return true
+}
+
+// hidden 路径中是否有以 . 开头的部分，如 .env、.git
+func hidden(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Site) serveFile(c *gin.Context, name string, info os.FileInfo, index bool) {
+	header := c.Writer.Header()
+	etag := fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size())
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	// index 引用的资源文件名随构建变化，index 本身每次都需要验证
+	if index {
+		header.Set("Cache-Control", "no-cache")
+	} else if s.maxAge > 0 {
+		header.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(s.maxAge.Seconds())))
+	}
+
+	if compressible(contentType) && info.Size() >= minGzipSize {
+		header.Add("Vary", "Accept-Encoding")
+		if acceptsGzip(c.Request) && s.serveGzip(c, name, info, etag) {
+			return
+		}
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	header.Set("ETag", etag)
+	http.ServeContent(c.Writer, c.Request, name, info.ModTime(), f)
+}
+
+// serveGzip 返回预压缩的 .gz 文件或即时压缩的结果，都不可用时返回 false
+func (s *Site) serveGzip(c *gin.Context, name string, info os.FileInfo, etag string) bool {
+	header := c.Writer.Header()
+
+	// 预压缩文件比原文件旧时视为过期，改为即时压缩
+	if gz, err := os.Stat(name + ".gz"); err == nil && !gz.IsDir() && !gz.ModTime().Before(info.ModTime()) {
+		f, err := os.Open(name + ".gz")
+		if err == nil {
+			defer f.Close()
+			header.Set("Content-Encoding", "gzip")
+			header.Set("ETag", fmt.Sprintf(`"%x-%x-gz"`, gz.ModTime().UnixNano(), gz.Size()))
+			http.ServeContent(c.Writer, c.Request, name, info.ModTime(), f)
+			return true
+		}
+	}
+
+	if info.Size() > maxGzipSize {
+		return false
+	}
+	data, err := s.compress(name, etag)
+	if err != nil {
+		return false
+	}
+	header.Set("Content-Encoding", "gzip")
+	header.Set("ETag", strings.TrimSuffix(etag, `"`)+`-gz"`)
+	http.ServeContent(c.Writer, c.Request, name, info.ModTime(), bytes.NewReader(data))
+	return true
+}
+
+// compress 即时压缩文件，结果按 ETag 缓存，文件变化后重新压缩
+func (s *Site) compress(name, etag string) ([]byte, error) {
+	s.mu.Lock()
+	cached, ok := s.gzip[name]
+	s.mu.Unlock()
+	if ok && cached.etag == etag {
+		return cached.data, nil
+	}
+
+	raw, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if _, err := w.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.gzip[name] = &gzipped{etag: etag, data: buf.Bytes()}
+	s.mu.Unlock()
+	return buf.Bytes(), nil
+}
+
+// compressible 文本类文件压缩效果好，图片、字体等已压缩的格式不再压缩
+func compressible(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/javascript", "application/json", "application/manifest+json",
+		"application/xml", "application/wasm", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		encoding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.TrimSpace(encoding) == "gzip" && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
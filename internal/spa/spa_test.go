@@ -0,0 +1,182 @@
+package spa
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gin-app-start/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newSite(t *testing.T, prefix string) (*Site, *gin.Engine) {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":       "<html>app</html>",
+		"assets/app.js":    strings.Repeat("console.log('app');\n", 100),
+		"assets/logo.png":  "png",
+		".env":             "SECRET=1",
+		"docs/index.html":  "<html>docs</html>",
+		"assets/style.css": strings.Repeat("body { margin: 0; }\n", 100),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	site, err := New(config.SPAConfig{Dir: dir, Prefix: prefix, MaxAge: 3600, Exclude: []string{"/api/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.NoRoute(func(c *gin.Context) {
+		if !site.Serve(c) {
+			c.String(http.StatusNotFound, "not found")
+		}
+	})
+	return site, engine
+}
+
+func get(engine *gin.Engine, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	return w
+}
+
+func TestServeFilesAndFallback(t *testing.T) {
+	_, engine := newSite(t, "")
+
+	tests := []struct {
+		target string
+		code   int
+		body   string
+	}{
+		{"/", http.StatusOK, "<html>app</html>"},
+		{"/orders/42", http.StatusOK, "<html>app</html>"}, // history 路由回退到 index.html
+		{"/docs/", http.StatusOK, "<html>docs</html>"},
+		{"/assets/logo.png", http.StatusOK, "png"},
+		{"/assets/missing.js", http.StatusNotFound, "not found"},
+		{"/api/v1/unknown", http.StatusNotFound, "not found"},
+		{"/.env", http.StatusNotFound, "not found"},
+		{"/assets/../../etc/passwd", http.StatusOK, "<html>app</html>"}, // 不能访问构建目录以外的文件
+	}
+	for _, tt := range tests {
+		w := get(engine, tt.target, nil)
+		if w.Code != tt.code || w.Body.String() != tt.body {
+			t.Errorf("GET %s = %d %q, want %d %q", tt.target, w.Code, w.Body.String(), tt.code, tt.body)
+		}
+	}
+}
+
+func TestCacheHeaders(t *testing.T) {
+	_, engine := newSite(t, "")
+
+	w := get(engine, "/", nil)
+	if got := w.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("index Cache-Control = %q, want no-cache", got)
+	}
+
+	w = get(engine, "/assets/logo.png", nil)
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=3600" {
+		t.Errorf("asset Cache-Control = %q", got)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+
+	w = get(engine, "/assets/logo.png", map[string]string{"If-None-Match": etag})
+	if w.Code != http.StatusNotModified {
+		t.Errorf("conditional GET = %d, want 304", w.Code)
+	}
+}
+
+func TestGzip(t *testing.T) {
+	_, engine := newSite(t, "")
+
+	w := get(engine, "/assets/app.js", map[string]string{"Accept-Encoding": "gzip, deflate"})
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", w.Header().Get("Content-Encoding"))
+	}
+	if !strings.HasSuffix(w.Header().Get("ETag"), `-gz"`) {
+		t.Errorf("gzip ETag = %q, want -gz suffix", w.Header().Get("ETag"))
+	}
+	r, err := gzip.NewReader(bytes.NewReader(w.Body.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(r)
+	if !strings.HasPrefix(string(body), "console.log") {
+		t.Errorf("decompressed body = %q", body[:20])
+	}
+
+	// 不接受 gzip 的客户端收到原文件
+	w = get(engine, "/assets/app.js", nil)
+	if w.Header().Get("Content-Encoding") != "" || !strings.HasPrefix(w.Body.String(), "console.log") {
+		t.Errorf("identity response encoded as %q", w.Header().Get("Content-Encoding"))
+	}
+
+	// 小文件和图片不压缩
+	w = get(engine, "/assets/logo.png", map[string]string{"Accept-Encoding": "gzip"})
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("png encoded as %q", w.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestPrecompressed(t *testing.T) {
+	site, engine := newSite(t, "")
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("precompressed"))
+	zw.Close()
+	if err := os.WriteFile(filepath.Join(site.root, "assets/style.css.gz"), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := get(engine, "/assets/style.css", map[string]string{"Accept-Encoding": "gzip"})
+	if !bytes.Equal(w.Body.Bytes(), buf.Bytes()) {
+		t.Errorf("expected the .gz file to be served")
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/css") {
+		t.Errorf("Content-Type = %q, want text/css", w.Header().Get("Content-Type"))
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	_, engine := newSite(t, "/app")
+
+	for _, target := range []string{"/app", "/app/", "/app/settings"} {
+		if w := get(engine, target, nil); w.Code != http.StatusOK || w.Body.String() != "<html>app</html>" {
+			t.Errorf("GET %s = %d %q", target, w.Code, w.Body.String())
+		}
+	}
+	if w := get(engine, "/other", nil); w.Code != http.StatusNotFound {
+		t.Errorf("GET /other = %d, want 404", w.Code)
+	}
+}
+
+func TestNewRequiresIndex(t *testing.T) {
+	if _, err := New(config.SPAConfig{Dir: t.TempDir()}); err == nil {
+		t.Fatal("expected error for missing index.html")
+	}
+}
@@ -0,0 +1,115 @@
+// Package telemetry 初始化 OpenTelemetry 分布式追踪，链路通过 OTLP 导出到 Jaeger、Tempo 等后端
+//
+// 初始化后全局 TracerProvider 和 W3C traceparent、baggage 传播器生效，otelgin 中间件、
+// GORM 和 go-redis 的追踪插件都使用全局配置；未初始化时全局配置为空实现，不产生任何 span。
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gin-app-start/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// DefaultServiceName 未配置 service_name 时上报的服务名
+const DefaultServiceName = "gin-app-start"
+
+// ServiceName 上报的服务名
+func ServiceName(cfg config.TracingConfig) string {
+	if cfg.ServiceName == "" {
+		return DefaultServiceName
+	}
+	return cfg.ServiceName
+}
+
+// Init 创建 OTLP 导出器并设置全局 TracerProvider 和传播器，返回的 shutdown 在退出前导出剩余的 span
+//
+// 导出器在后台连接，后端不可用时不影响启动，span 在导出失败后丢弃。
+func Init(cfg config.TracingConfig, version, env string) (shutdown func(context.Context) error, err error) {
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(ServiceName(cfg)),
+		semconv.ServiceVersion(version),
+		semconv.DeploymentEnvironmentName(env),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(Sampler(cfg.SampleRatio)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(Propagator())
+	return provider.Shutdown, nil
+}
+
+// Sampler 上游请求带 traceparent 时沿用上游的采样结果，新链路按 ratio 采样
+func Sampler(ratio float64) sdktrace.Sampler {
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+}
+
+// Propagator 从请求头读取、向下游写入 W3C traceparent 和 baggage
+func Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// Validate 检查导出配置，不连接后端
+func Validate(cfg config.TracingConfig) error {
+	if cfg.Endpoint == "" {
+		return errors.New("endpoint is required")
+	}
+	switch strings.ToLower(cfg.Protocol) {
+	case "", "grpc", "http":
+		return nil
+	default:
+		return fmt.Errorf("unknown protocol %q, expected grpc or http", cfg.Protocol)
+	}
+}
+
+func newExporter(cfg config.TracingConfig) (sdktrace.SpanExporter, error) {
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	switch strings.ToLower(cfg.Protocol) {
+	case "", "grpc":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	default:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+}
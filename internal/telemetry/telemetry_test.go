@@ -0,0 +1,66 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gin-app-start/internal/config"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		cfg config.TracingConfig
+		ok  bool
+	}{
+		{config.TracingConfig{Endpoint: "localhost:4317"}, true},
+		{config.TracingConfig{Endpoint: "localhost:4318", Protocol: "HTTP"}, true},
+		{config.TracingConfig{}, false},
+		{config.TracingConfig{Endpoint: "localhost:4317", Protocol: "zipkin"}, false},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.cfg); (err == nil) != tt.ok {
+			t.Errorf("Validate(%+v) = %v, want ok=%v", tt.cfg, err, tt.ok)
+		}
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(Sampler(0)), sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("test")
+
+	// 上游已采样的链路即使采样比例为 0 也继续记录，trace_id 与上游一致
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := Propagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+	_, span := tracer.Start(ctx, "request")
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %s", got)
+	}
+
+	// 新链路按采样比例 0 不记录
+	_, span = tracer.Start(context.Background(), "request")
+	if span.SpanContext().IsSampled() {
+		t.Error("new trace sampled with ratio 0")
+	}
+	span.End()
+
+	// 向下游写入 traceparent
+	out := http.Header{}
+	Propagator().Inject(trace.ContextWithSpanContext(context.Background(), spans[0].SpanContext()), propagation.HeaderCarrier(out))
+	if got := out.Get("traceparent"); got != "00-4bf92f3577b34da6a3ce929d0e0e4736-"+spans[0].SpanContext().SpanID().String()+"-01" {
+		t.Errorf("injected traceparent = %q", got)
+	}
+}
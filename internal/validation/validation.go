@@ -1,11 +1,16 @@
 package validation
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"strings"
 
 	"gin-app-start/internal/common"
 	"gin-app-start/internal/config"
+	"gin-app-start/pkg/queryfilter"
 
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/locales/en"
@@ -26,19 +31,63 @@ func init() {
 
 	lang := cfg.Language.Local
 
+	validate := binding.Validator.Engine().(*validator.Validate)
+	// 错误信息和 details 中的字段名使用请求中的参数名
+	validate.RegisterTagNameFunc(fieldName)
+	if err := validate.RegisterValidation("enum", validateEnum); err != nil {
+		fmt.Println("validator register enum error", err)
+	}
+
 	if lang == common.ZhCN {
 		trans, _ = ut.New(zh.New()).GetTranslator("zh")
-		if err := zhTranslation.RegisterDefaultTranslations(binding.Validator.Engine().(*validator.Validate), trans); err != nil {
+		if err := zhTranslation.RegisterDefaultTranslations(validate, trans); err != nil {
 			fmt.Println("validator zh translation error", err)
 		}
+		registerTranslation(validate, "enum", "{0}的取值无效")
 	}
 
 	if lang == common.EnUS {
 		trans, _ = ut.New(en.New()).GetTranslator("en")
-		if err := enTranslation.RegisterDefaultTranslations(binding.Validator.Engine().(*validator.Validate), trans); err != nil {
+		if err := enTranslation.RegisterDefaultTranslations(validate, trans); err != nil {
 			fmt.Println("validator en translation error", err)
 		}
+		registerTranslation(validate, "enum", "{0} must be one of the defined values")
+	}
+}
+
+// enum 校验规则适用的枚举类型，如 model.OrderStatus
+type enum interface {
+	IsValid() bool
+}
+
+// validateEnum 校验规则 enum：字段值为已定义的枚举值，指针字段校验指向的值
+func validateEnum(fl validator.FieldLevel) bool {
+	v, ok := fl.Field().Interface().(enum)
+	return ok && v.IsValid()
+}
+
+// registerTranslation 注册自定义校验规则的错误信息，{0} 为参数名
+func registerTranslation(validate *validator.Validate, tag, text string) {
+	err := validate.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
+		return ut.Add(tag, text, true)
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T(tag, fe.Field())
+		return t
+	})
+	if err != nil {
+		fmt.Println("validator register translation error", tag, err)
+	}
+}
+
+// fieldName 字段在请求中的参数名，依次取 json、form、uri 标签，都没有时使用结构体字段名
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
 	}
+	return field.Name
 }
 
 func Error(err error) (message string) {
@@ -51,3 +100,69 @@ func Error(err error) (message string) {
 	}
 	return message
 }
+
+// Details 把参数绑定错误转换为逐个字段的错误，字段路径为 JSON Pointer(如 /items/0/price)
+// 支持校验规则错误、JSON 类型不匹配和过滤排序参数错误，无法定位到字段的错误(如 JSON 格式错误)返回 nil
+func Details(err error) []common.FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		details := make([]common.FieldError, 0, len(validationErrors))
+		for _, e := range validationErrors {
+			details = append(details, common.FieldError{
+				Field:   Pointer(e.Namespace()),
+				Rule:    e.Tag(),
+				Message: e.Translate(trans),
+			})
+		}
+		return details
+	}
+
+	var filterErr *queryfilter.Error
+	if errors.As(err, &filterErr) {
+		rule := "filter"
+		if filterErr.Param == queryfilter.SortParam {
+			rule = "sort"
+		}
+		return []common.FieldError{{
+			Field:   "/" + filterErr.Param,
+			Rule:    rule,
+			Message: filterErr.Err.Error(),
+		}}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []common.FieldError{{
+			Field:   Pointer("." + typeErr.Field),
+			Rule:    "type",
+			Message: typeMessage(typeErr),
+		}}
+	}
+	return nil
+}
+
+// typeMessage JSON 类型不匹配的错误信息，只包含参数名和期望的类型
+func typeMessage(err *json.UnmarshalTypeError) string {
+	if trans != nil && trans.Locale() == "zh" {
+		return fmt.Sprintf("%s的类型错误，应为%s", err.Field, err.Type)
+	}
+	return fmt.Sprintf("%s must be of type %s", err.Field, err.Type)
+}
+
+// Pointer 把校验错误的命名空间(如 CreateOrderRequest.items[0].price)转换为 JSON Pointer(/items/0/price)，
+// 第一段是最外层的结构体名，不计入路径
+func Pointer(namespace string) string {
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		namespace = namespace[i+1:]
+	}
+
+	var b strings.Builder
+	for _, token := range strings.FieldsFunc(namespace, func(r rune) bool {
+		return r == '.' || r == '[' || r == ']'
+	}) {
+		b.WriteByte('/')
+		// JSON Pointer 中 ~ 和 / 需要转义
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+	return b.String()
+}
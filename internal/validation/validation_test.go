@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"gin-app-start/pkg/queryfilter"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+type item struct {
+	SKU   string  `json:"sku" binding:"required"`
+	Price float64 `json:"price" binding:"gt=0"`
+}
+
+type order struct {
+	Name  string `json:"name" binding:"required"`
+	Items []item `json:"items" binding:"required,dive"`
+}
+
+func bindJSON(t *testing.T, body string) error {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return binding.JSON.Bind(req, &order{})
+}
+
+func TestDetails(t *testing.T) {
+	details := Details(bindJSON(t, `{"items":[{"sku":"A","price":1},{"price":0}]}`))
+
+	want := map[string]string{
+		"/name":          "required",
+		"/items/1/sku":   "required",
+		"/items/1/price": "gt",
+	}
+	if len(details) != len(want) {
+		t.Fatalf("details = %+v", details)
+	}
+	for _, d := range details {
+		if want[d.Field] != d.Rule {
+			t.Errorf("unexpected detail %+v", d)
+		}
+		if d.Message == "" {
+			t.Errorf("missing message for %s", d.Field)
+		}
+	}
+}
+
+func TestDetailsType(t *testing.T) {
+	details := Details(bindJSON(t, `{"name":"a","items":[{"sku":"A","price":"1"}]}`))
+	if len(details) != 1 || details[0].Rule != "type" || details[0].Field != "/items/0/price" {
+		t.Fatalf("details = %+v", details)
+	}
+
+	if details := Details(bindJSON(t, `{"name":`)); details != nil {
+		t.Errorf("syntax error should have no details, got %+v", details)
+	}
+}
+
+func TestPointer(t *testing.T) {
+	cases := map[string]string{
+		"order.name":             "/name",
+		"order.items[2].price":   "/items/2/price",
+		"order.attrs[a/b~c]":     "/attrs/a~1b~0c",
+		"order.items[0].tags[1]": "/items/0/tags/1",
+	}
+	for namespace, want := range cases {
+		if got := Pointer(namespace); got != want {
+			t.Errorf("Pointer(%q) = %q, want %q", namespace, got, want)
+		}
+	}
+}
+
+type color int8
+
+func (c color) IsValid() bool { return c == 1 || c == 2 }
+
+type palette struct {
+	Main   color  `json:"main" binding:"omitempty,enum"`
+	Accent *color `json:"accent" binding:"omitempty,enum"`
+}
+
+func TestEnum(t *testing.T) {
+	cases := map[string]string{
+		`{"main":1,"accent":2}`: "",
+		`{}`:                    "",
+		`{"main":3}`:            "/main",
+		`{"accent":0}`:          "/accent",
+	}
+	for body, field := range cases {
+		req, err := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		details := Details(binding.JSON.Bind(req, &palette{}))
+		if field == "" {
+			if details != nil {
+				t.Errorf("%s: details = %+v", body, details)
+			}
+			continue
+		}
+		if len(details) != 1 || details[0].Field != field || details[0].Rule != "enum" || details[0].Message == "" {
+			t.Errorf("%s: details = %+v", body, details)
+		}
+	}
+}
+
+func TestDetailsFilter(t *testing.T) {
+	spec := &queryfilter.Spec{Sorts: map[string]string{"id": "id"}}
+	_, err := spec.Parse(url.Values{"sort": {"password"}})
+	details := Details(err)
+	if len(details) != 1 || details[0].Field != "/sort" || details[0].Rule != "sort" || details[0].Message == "" {
+		t.Fatalf("details = %+v", details)
+	}
+}
@@ -0,0 +1,77 @@
+// Package web 服务端渲染页面的模板和静态资源，编译时嵌入二进制，部署时不需要额外的文件
+package web
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/render"
+)
+
+//go:embed templates static
+var files embed.FS
+
+// layoutFile 所有页面共用的布局，页面通过 {{define "content"}} 填充正文
+const layoutFile = "templates/layout.html"
+
+var _ render.HTMLRender = (*Renderer)(nil)
+
+// Renderer 按页面名渲染模板，每个页面与布局单独解析，不同页面可以定义同名的 content、title
+type Renderer struct {
+	pages map[string]*template.Template
+}
+
+// NewRenderer 解析 templates 目录下除布局外的全部页面，页面名为去掉 .html 的文件名，如 users
+func NewRenderer() (*Renderer, error) {
+	names, err := fs.Glob(files, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Renderer{pages: make(map[string]*template.Template, len(names))}
+	for _, name := range names {
+		if name == layoutFile {
+			continue
+		}
+		page := strings.TrimSuffix(path.Base(name), ".html")
+		tmpl, err := template.New(page).Funcs(funcs).ParseFS(files, layoutFile, name)
+		if err != nil {
+			return nil, fmt.Errorf("parse page %s: %w", page, err)
+		}
+		r.pages[page] = tmpl
+	}
+	return r, nil
+}
+
+// Instance 实现 gin 的 render.HTMLRender，页面不存在时 panic
+func (r *Renderer) Instance(name string, data interface{}) render.Render {
+	tmpl, ok := r.pages[name]
+	if !ok {
+		panic(fmt.Sprintf("web: page %q not found", name))
+	}
+	return render.HTML{Template: tmpl, Name: "layout", Data: data}
+}
+
+// Static 嵌入的静态资源(样式表等)，路径相对于 static 目录
+func Static() http.FileSystem {
+	sub, err := fs.Sub(files, "static")
+	if err != nil {
+		panic(err)
+	}
+	return http.FS(sub)
+}
+
+var funcs = template.FuncMap{
+	"datetime": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Local().Format("2006-01-02 15:04")
+	},
+}
@@ -0,0 +1,52 @@
+package web
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRendererPages(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, page := range []string{"login", "users", "orders", "error"} {
+		w := httptest.NewRecorder()
+		data := map[string]interface{}{"Title": "Test", "Admin": "admin", "Error": "<b>failed</b>"}
+		if err := r.Instance(page, data).Render(w); err != nil {
+			t.Fatalf("render %s: %v", page, err)
+		}
+
+		body := w.Body.String()
+		if !strings.Contains(body, "<title>Test · gin-app-start</title>") {
+			t.Errorf("%s: layout not rendered:\n%s", page, body)
+		}
+		if !strings.Contains(body, "&lt;b&gt;failed&lt;/b&gt;") {
+			t.Errorf("%s: error message not escaped:\n%s", page, body)
+		}
+	}
+}
+
+func TestRendererUnknownPage(t *testing.T) {
+	r, err := NewRenderer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for unknown page")
+		}
+	}()
+	r.Instance("layout", nil)
+}
+
+func TestStatic(t *testing.T) {
+	f, err := Static().Open("/app.css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+}
@@ -0,0 +1,22 @@
+// Package avscan 调用 ClamAV(clamd)或外部扫描接口检查文件是否含有病毒
+package avscan
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrUnexpectedResponse 扫描服务返回了无法识别的结果
+var ErrUnexpectedResponse = errors.New("avscan: unexpected response")
+
+// Result 扫描结果
+type Result struct {
+	Infected  bool
+	Signature string // 命中的病毒特征名，未感染时为空
+}
+
+// Scanner 扫描文件内容，返回错误表示扫描没有完成(连接失败、超时等)，不代表文件感染
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (*Result, error)
+}
@@ -0,0 +1,144 @@
+package avscan
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd 接收一次 INSTREAM，返回 reply(内容含 "EICAR" 时)或 stream: OK
+func fakeClamd(t *testing.T, reply string) (string, <-chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		cmd := make([]byte, len("zINSTREAM\x00"))
+		if _, err := io.ReadFull(conn, cmd); err != nil || string(cmd) != "zINSTREAM\x00" {
+			conn.Write([]byte("UNKNOWN COMMAND\x00"))
+			return
+		}
+		var data bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&data, conn, int64(size)); err != nil {
+				return
+			}
+		}
+		received <- data.Bytes()
+		if strings.Contains(data.String(), "EICAR") {
+			conn.Write([]byte(reply + "\x00"))
+		} else {
+			conn.Write([]byte("stream: OK\x00"))
+		}
+	}()
+	return ln.Addr().String(), received
+}
+
+func TestClamAVClean(t *testing.T) {
+	addr, received := fakeClamd(t, "")
+	content := bytes.Repeat([]byte("a"), clamavChunkSize*2+10)
+
+	res, err := NewClamAV(addr).Scan(context.Background(), bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Infected {
+		t.Fatalf("result = %+v, want clean", res)
+	}
+	if got := <-received; !bytes.Equal(got, content) {
+		t.Fatalf("clamd received %d bytes, want %d", len(got), len(content))
+	}
+}
+
+func TestClamAVInfected(t *testing.T) {
+	addr, _ := fakeClamd(t, "stream: Eicar-Test-Signature FOUND")
+
+	res, err := NewClamAV(addr).Scan(context.Background(), strings.NewReader("X5O!P%@AP EICAR"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Infected || res.Signature != "Eicar-Test-Signature" {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestClamAVUnavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := NewClamAV(addr).Scan(ctx, strings.NewReader("a")); err == nil {
+		t.Fatal("scan should fail when clamd is not listening")
+	}
+}
+
+func TestParseClamAVReply(t *testing.T) {
+	if _, err := parseClamAVReply([]byte("stream: INSTREAM size limit exceeded. ERROR\x00")); !errors.Is(err, ErrUnexpectedResponse) {
+		t.Fatalf("err = %v, want ErrUnexpectedResponse", err)
+	}
+	if _, err := parseClamAVReply([]byte("")); !errors.Is(err, ErrUnexpectedResponse) {
+		t.Fatalf("err = %v, want ErrUnexpectedResponse", err)
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch string(body) {
+		case "virus":
+			w.Write([]byte(`{"infected": true, "signature": "Test.Virus"}`))
+		case "clean":
+			w.Write([]byte(`{"infected": false}`))
+		case "broken":
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	scanner := NewHTTP(srv.URL, nil)
+	res, err := scanner.Scan(context.Background(), strings.NewReader("virus"))
+	if err != nil || !res.Infected || res.Signature != "Test.Virus" {
+		t.Fatalf("virus: %+v, %v", res, err)
+	}
+	res, err = scanner.Scan(context.Background(), strings.NewReader("clean"))
+	if err != nil || res.Infected {
+		t.Fatalf("clean: %+v, %v", res, err)
+	}
+	for _, body := range []string{"broken", "down"} {
+		if _, err := scanner.Scan(context.Background(), strings.NewReader(body)); !errors.Is(err, ErrUnexpectedResponse) {
+			t.Fatalf("%s: err = %v, want ErrUnexpectedResponse", body, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package avscan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// clamavChunkSize INSTREAM 每块发送的字节数，需小于 clamd 的 StreamMaxLength
+const clamavChunkSize = 64 << 10
+
+var _ Scanner = (*ClamAV)(nil)
+
+// ClamAV 通过 TCP 连接 clamd，使用 INSTREAM 命令发送文件内容，clamd 不需要能访问本机文件系统
+type ClamAV struct {
+	addr   string
+	dialer net.Dialer
+}
+
+// NewClamAV addr 为 clamd 的 TCP 地址，如 127.0.0.1:3310
+func NewClamAV(addr string) *ClamAV {
+	return &ClamAV{addr: addr}
+}
+
+// Scan 每次扫描使用一个新连接，超时由 ctx 控制
+//
+// 协议: 发送 zINSTREAM\0，之后每块为 4 字节大端长度 + 数据，以长度 0 结束；
+// clamd 返回 "stream: OK" 或 "stream: <特征名> FOUND"，文件超过 StreamMaxLength 时返回 "... ERROR"。
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	// ctx 被取消时关闭连接，结束阻塞中的读写
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	w := bufio.NewWriterSize(conn, clamavChunkSize+4)
+	if _, err := w.WriteString("zINSTREAM\x00"); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, clamavChunkSize)
+	var size [4]byte
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, err := w.Write(size[:]); err != nil {
+				return nil, err
+			}
+			if _, err := w.Write(buf[:n]); err != nil {
+				return nil, err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err := w.Write(size[:]); err != nil {
+		return nil, err
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseClamAVReply(reply)
+}
+
+// parseClamAVReply 解析 clamd 的回复，z 前缀的命令以 \0 结尾
+func parseClamAVReply(reply []byte) (*Result, error) {
+	line := strings.TrimSpace(string(bytes.TrimRight(reply, "\x00")))
+	_, status, ok := strings.Cut(line, ": ")
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnexpectedResponse, line)
+	}
+
+	switch {
+	case status == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &Result{Infected: true, Signature: strings.TrimSuffix(status, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnexpectedResponse, line)
+	}
+}
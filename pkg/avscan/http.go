@@ -0,0 +1,53 @@
+package avscan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var _ Scanner = (*HTTP)(nil)
+
+// HTTP 调用外部扫描接口: 以请求体 POST 文件内容(application/octet-stream)，
+// 接口返回 200 和 {"infected": true, "signature": "Eicar-Test-Signature"}
+type HTTP struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTP client 为 nil 时使用 http.DefaultClient，超时由 Scan 的 ctx 控制
+func NewHTTP(url string, client *http.Client) *HTTP {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTP{url: url, client: client}
+}
+
+func (h *HTTP) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrUnexpectedResponse, resp.StatusCode)
+	}
+
+	var body struct {
+		Infected  *bool  `json:"infected"`
+		Signature string `json:"signature"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil || body.Infected == nil {
+		return nil, fmt.Errorf("%w: missing infected field", ErrUnexpectedResponse)
+	}
+	return &Result{Infected: *body.Infected, Signature: body.Signature}, nil
+}
@@ -0,0 +1,30 @@
+package buildinfo
+
+import "runtime"
+
+// 以下变量在编译时通过 -ldflags 注入，例如:
+//
+//	go build -ldflags "-X gin-app-start/pkg/buildinfo.Version=v1.0.0 -X gin-app-start/pkg/buildinfo.GitCommit=$(git rev-parse --short HEAD)"
+var (
+	Version   = "unknown"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info 构建信息
+type Info struct {
+	Version   string `json:"version" example:"v1.0.0"`
+	GitCommit string `json:"git_commit" example:"0853152"`
+	BuildTime string `json:"build_time" example:"2024-01-01 00:00:00"`
+	GoVersion string `json:"go_version" example:"go1.24.0"`
+}
+
+// Get 获取当前二进制的构建信息
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
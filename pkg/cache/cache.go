@@ -0,0 +1,228 @@
+// Package cache 读穿透缓存
+//
+// GetOrLoad 优先读缓存，未命中时调用 loader 回源并写回缓存；同一个键的并发回源通过 singleflight 合并为一次，
+// 避免缓存失效瞬间大量请求同时回源(缓存击穿)；loader 返回 ErrNotFound 时写入短期的空值标记，避免反复查询
+// 不存在的数据(缓存穿透)。
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultLoadTimeout 合并回源的默认超时
+const DefaultLoadTimeout = 10 * time.Second
+
+// DefaultNegativeTTL 空值标记的默认过期时间，较短以免数据创建前的查询长时间影响结果
+const DefaultNegativeTTL = time.Minute
+
+// notFoundMarker 数据不存在时写入缓存的标记，与 JSON 对象和读取失败都能区分
+const notFoundMarker = `{"_nil":true}`
+
+var (
+	// ErrNotFound 数据不存在: loader 返回它(可以包装)时写入空值标记，读到空值标记时返回它
+	ErrNotFound = errors.New("cache: value not found")
+	// ErrStore 写缓存失败，此时 GetOrLoad 仍返回 loader 的结果，由调用方决定是否忽略
+	ErrStore = errors.New("cache: store failed")
+)
+
+// Result 一次查询的缓存命中情况
+type Result string
+
+const (
+	Hit   Result = "hit"   // 命中且数据可用(包括空值标记)
+	Miss  Result = "miss"  // 未命中，回源
+	Stale Result = "stale" // 命中但数据无法解码，回源
+)
+
+// Store 缓存存储，键不存在和读取失败都返回错误，GetOrLoad 都按未命中处理
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+}
+
+// Codec 缓存值的编解码
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// JSON 默认的编解码，不会保存 json:"-" 的字段
+var JSON Codec = jsonCodec{}
+
+// Option Cache 的配置项
+type Option func(*Cache)
+
+// WithCodec 自定义编解码，如 msgpack
+func WithCodec(codec Codec) Option {
+	return func(c *Cache) {
+		c.codec = codec
+	}
+}
+
+// WithNegativeTTL 空值标记的过期时间，<= 0 时不缓存不存在的结果
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.negativeTTL = ttl
+	}
+}
+
+// WithLoadTimeout 合并回源的超时，回源不随发起回源的调用方的 ctx 取消，只受这个超时限制
+func WithLoadTimeout(timeout time.Duration) Option {
+	return func(c *Cache) {
+		c.loadTimeout = timeout
+	}
+}
+
+// WithObserver 每次 GetOrLoad 读缓存后调用，用于上报命中率等指标
+func WithObserver(fn func(ctx context.Context, key string, result Result)) Option {
+	return func(c *Cache) {
+		c.observe = fn
+	}
+}
+
+// Cache 读穿透缓存，并发安全
+type Cache struct {
+	store       Store
+	codec       Codec
+	negativeTTL time.Duration
+	loadTimeout time.Duration
+	observe     func(ctx context.Context, key string, result Result)
+	group       singleflight.Group
+}
+
+func New(store Store, opts ...Option) *Cache {
+	c := &Cache{
+		store:       store,
+		codec:       JSON,
+		negativeTTL: DefaultNegativeTTL,
+		loadTimeout: DefaultLoadTimeout,
+		observe:     func(context.Context, string, Result) {},
+	}
+	for _, f := range opts {
+		f(c)
+	}
+	return c
+}
+
+// loaded 合并回源的结果，等待的调用方各自从 data 解码一份，不与回源的调用方共享同一个值
+type loaded struct {
+	data []byte
+}
+
+// GetOrLoad 读取 key，未命中或无法解码时调用 loader 回源，并以 ttl 写回缓存
+//
+// 同一个键同时只有一个 loader 在执行，其他调用方等待并共享它的结果。loader 和写回缓存使用的 ctx
+// 保留发起回源的调用方 ctx 中的值(如 trace)，但不随它取消，以免该调用方断开导致所有等待的调用方失败，
+// 超时见 WithLoadTimeout。
+// loader 返回 ErrNotFound 时写入空值标记并返回 ErrNotFound；写缓存失败时返回结果和包装了 ErrStore 的错误。
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if value, err := c.store.Get(ctx, key); err == nil {
+		v, err := Decode[T](c, value)
+		switch {
+		case err == nil:
+			c.observe(ctx, key, Hit)
+			return v, nil
+		case errors.Is(err, ErrNotFound):
+			c.observe(ctx, key, Hit)
+			return zero, err
+		default:
+			c.observe(ctx, key, Stale)
+		}
+	} else {
+		c.observe(ctx, key, Miss)
+	}
+
+	var (
+		leader bool
+		own    T
+	)
+	res, err, _ := c.group.Do(key, func() (any, error) {
+		leader = true
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.loadTimeout)
+		defer cancel()
+
+		v, err := loader(ctx)
+		if errors.Is(err, ErrNotFound) {
+			if storeErr := c.SetNotFound(ctx, key); storeErr != nil {
+				return nil, errors.Join(err, storeErr)
+			}
+			return nil, err
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		own = v
+		data, err := c.codec.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: marshal %s: %w", ErrStore, key, err)
+		}
+		if err := c.store.Set(ctx, key, string(data), ttl); err != nil {
+			return &loaded{data: data}, fmt.Errorf("%w: %w", ErrStore, err)
+		}
+		return &loaded{data: data}, nil
+	})
+	if leader {
+		return own, err
+	}
+
+	shared, _ := res.(*loaded)
+	if shared == nil {
+		return zero, err
+	}
+	var v T
+	if decodeErr := c.codec.Unmarshal(shared.data, &v); decodeErr != nil {
+		return zero, decodeErr
+	}
+	return v, err
+}
+
+// Decode 解码缓存值，空值标记返回 ErrNotFound；用于调用方自行批量读取(如 MGET)后解码
+func Decode[T any](c *Cache, value string) (T, error) {
+	var v T
+	if value == notFoundMarker {
+		return v, ErrNotFound
+	}
+	if value == "" {
+		return v, errors.New("cache: empty value")
+	}
+	if err := c.codec.Unmarshal([]byte(value), &v); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// Set 编码 v 并写入缓存，用于数据变更后主动刷新缓存
+func Set[T any](ctx context.Context, c *Cache, key string, v T, ttl time.Duration) error {
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("%w: marshal %s: %w", ErrStore, key, err)
+	}
+	if err := c.store.Set(ctx, key, string(data), ttl); err != nil {
+		return fmt.Errorf("%w: %w", ErrStore, err)
+	}
+	return nil
+}
+
+// SetNotFound 写入空值标记，未启用负缓存时不写入
+func (c *Cache) SetNotFound(ctx context.Context, key string) error {
+	if c.negativeTTL <= 0 {
+		return nil
+	}
+	if err := c.store.Set(ctx, key, notFoundMarker, c.negativeTTL); err != nil {
+		return fmt.Errorf("%w: %w", ErrStore, err)
+	}
+	return nil
+}
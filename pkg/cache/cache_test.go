@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type memoryStore struct {
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]time.Duration
+	setErr error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: map[string]string{}, ttls: map[string]time.Duration{}}
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	if !ok {
+		return "", errors.New("not exist")
+	}
+	return v, nil
+}
+
+func (s *memoryStore) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.setErr != nil {
+		return s.setErr
+	}
+	s.values[key] = value
+	s.ttls[key] = ttl
+	return nil
+}
+
+type item struct {
+	Name string `json:"name"`
+}
+
+func TestGetOrLoad(t *testing.T) {
+	store := newMemoryStore()
+	var results []Result
+	c := New(store, WithObserver(func(_ context.Context, _ string, r Result) { results = append(results, r) }))
+
+	var calls int
+	loader := func(context.Context) (*item, error) {
+		calls++
+		return &item{Name: "a"}, nil
+	}
+	for i := 0; i < 2; i++ {
+		v, err := GetOrLoad(context.Background(), c, "k", time.Minute, loader)
+		if err != nil || v.Name != "a" {
+			t.Fatalf("GetOrLoad = %+v, %v", v, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("loader called %d times, want 1", calls)
+	}
+	if store.ttls["k"] != time.Minute {
+		t.Fatalf("ttl = %v, want 1m", store.ttls["k"])
+	}
+	if len(results) != 2 || results[0] != Miss || results[1] != Hit {
+		t.Fatalf("results = %v, want [miss hit]", results)
+	}
+
+	// 无法解码的值按 stale 处理并回源
+	store.values["k"] = "{broken"
+	if _, err := GetOrLoad(context.Background(), c, "k", time.Minute, loader); err != nil || calls != 2 {
+		t.Fatalf("stale value should reload: err = %v, calls = %d", err, calls)
+	}
+	if results[2] != Stale {
+		t.Fatalf("result = %v, want stale", results[2])
+	}
+}
+
+func TestGetOrLoadNegative(t *testing.T) {
+	store := newMemoryStore()
+	c := New(store, WithNegativeTTL(time.Second))
+
+	var calls int
+	loader := func(context.Context) (*item, error) {
+		calls++
+		return nil, errors.Join(errors.New("record not found"), ErrNotFound)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := GetOrLoad(context.Background(), c, "k", time.Minute, loader); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("err = %v, want ErrNotFound", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("loader called %d times, want 1", calls)
+	}
+	if store.ttls["k"] != time.Second {
+		t.Fatalf("negative ttl = %v, want 1s", store.ttls["k"])
+	}
+
+	// 关闭负缓存时每次都回源
+	c = New(newMemoryStore(), WithNegativeTTL(0))
+	for i := 0; i < 2; i++ {
+		GetOrLoad(context.Background(), c, "k", time.Minute, loader)
+	}
+	if calls != 3 {
+		t.Fatalf("loader called %d times, want 3", calls)
+	}
+}
+
+func TestGetOrLoadSingleflight(t *testing.T) {
+	c := New(newMemoryStore())
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(context.Context) (*item, error) {
+		calls.Add(1)
+		<-release
+		return &item{Name: "a"}, nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	values := make([]*item, n)
+	var started sync.WaitGroup
+	started.Add(n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			started.Done()
+			v, err := GetOrLoad(context.Background(), c, "k", time.Minute, loader)
+			if err != nil {
+				t.Error(err)
+			}
+			values[i] = v
+		}(i)
+	}
+	started.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// 回源期间到达的请求合并为一次，之后到达的请求命中缓存
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("loader called %d times, want 1", got)
+	}
+	// 每个调用方拿到各自的值，修改不会互相影响
+	for i := 1; i < n; i++ {
+		if values[i] == values[0] || values[i].Name != "a" {
+			t.Fatalf("values[%d] = %p %+v shares or differs from values[0] = %p", i, values[i], values[i], values[0])
+		}
+	}
+}
+
+func TestGetOrLoadLeaderCanceled(t *testing.T) {
+	c := New(newMemoryStore(), WithLoadTimeout(time.Second))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(ctx context.Context) (*item, error) {
+		close(started)
+		select {
+		case <-release:
+			return &item{Name: "a"}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error, 1)
+	go func() {
+		_, err := GetOrLoad(leaderCtx, c, "k", time.Minute, loader)
+		leaderDone <- err
+	}()
+	<-started
+
+	waiterDone := make(chan *item, 1)
+	go func() {
+		v, err := GetOrLoad(context.Background(), c, "k", time.Minute, loader)
+		if err != nil {
+			t.Error(err)
+		}
+		waiterDone <- v
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// 发起回源的调用方断开不影响回源，等待的调用方仍拿到结果
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	if err := <-leaderDone; err != nil {
+		t.Fatalf("leader err = %v, want nil", err)
+	}
+	if v := <-waiterDone; v == nil || v.Name != "a" {
+		t.Fatalf("waiter got %+v, want a", v)
+	}
+
+	// 回源超时后返回超时错误
+	c = New(newMemoryStore(), WithLoadTimeout(20*time.Millisecond))
+	_, err := GetOrLoad(context.Background(), c, "k", time.Minute, func(ctx context.Context) (*item, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+}
+
+func TestGetOrLoadStoreError(t *testing.T) {
+	store := newMemoryStore()
+	store.setErr = errors.New("redis down")
+	c := New(store)
+
+	v, err := GetOrLoad(context.Background(), c, "k", time.Minute, func(context.Context) (*item, error) {
+		return &item{Name: "a"}, nil
+	})
+	if !errors.Is(err, ErrStore) || v == nil || v.Name != "a" {
+		t.Fatalf("GetOrLoad = %+v, %v, want value with ErrStore", v, err)
+	}
+
+	loadErr := errors.New("db down")
+	if _, err := GetOrLoad(context.Background(), c, "k", time.Minute, func(context.Context) (*item, error) {
+		return nil, loadErr
+	}); !errors.Is(err, loadErr) || errors.Is(err, ErrStore) {
+		t.Fatalf("err = %v, want loader error", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	c := New(newMemoryStore())
+
+	if _, err := Decode[*item](c, notFoundMarker); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("marker: err = %v, want ErrNotFound", err)
+	}
+	if _, err := Decode[*item](c, ""); err == nil {
+		t.Fatal("empty value should fail")
+	}
+	if v, err := Decode[*item](c, `{"name": "a"}`); err != nil || v.Name != "a" {
+		t.Fatalf("Decode = %+v, %v", v, err)
+	}
+}
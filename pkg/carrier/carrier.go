@@ -0,0 +1,37 @@
+package carrier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Event 承运商返回的一条物流事件
+type Event struct {
+	Status      string    // 已映射为本系统的物流状态，如 in_transit、delivered
+	Location    string    // 事件发生地
+	Description string    // 承运商的原始描述
+	OccurredAt  time.Time // 事件发生时间
+}
+
+// Tracker 承运商查询接口，由各承运商的适配实现，轮询任务通过它主动拉取物流事件
+type Tracker interface {
+	Track(ctx context.Context, trackingNumber string) ([]Event, error)
+}
+
+// Sign 计算回调请求体的签名: hex(HMAC-SHA256(secret, body))
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验承运商回调的签名，secret 为空时一律拒绝
+func Verify(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,21 @@
+package carrier
+
+import "testing"
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"tracking_number":"SF1234567890"}`)
+	sig := Sign("secret", body)
+
+	if !Verify("secret", body, sig) {
+		t.Fatalf("valid signature rejected")
+	}
+	if Verify("other", body, sig) {
+		t.Fatalf("signature with wrong secret accepted")
+	}
+	if Verify("secret", []byte(`{}`), sig) {
+		t.Fatalf("signature for different body accepted")
+	}
+	if Verify("", body, Sign("", body)) {
+		t.Fatalf("empty secret should be rejected")
+	}
+}
@@ -0,0 +1,33 @@
+// Package cursor 游标分页(keyset pagination)的游标编解码
+//
+// 游标记录上一页最后一条记录的主键，下一页按 WHERE id > ? ORDER BY id 查询，
+// 不使用 OFFSET，翻到多深的页查询代价都相同，翻页期间插入、删除数据也不会重复或遗漏。
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"gin-app-start/pkg/hashid"
+)
+
+// ErrInvalid 游标格式错误，或不是当前 ID 混淆配置编码的游标
+var ErrInvalid = errors.New("invalid cursor")
+
+// Encode 把上一页最后一条记录的主键编码为不透明的游标，启用 ID 混淆时游标中不含原始主键
+func Encode(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(hashid.Encode(id)))
+}
+
+// Decode 解析 Encode 编码的游标，返回上一页最后一条记录的主键
+func Decode(s string) (uint, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	id, err := hashid.Parse(string(data))
+	if err != nil {
+		return 0, ErrInvalid
+	}
+	return id, nil
+}
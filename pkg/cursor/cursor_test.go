@@ -0,0 +1,40 @@
+package cursor
+
+import (
+	"errors"
+	"testing"
+
+	"gin-app-start/pkg/hashid"
+)
+
+func TestRoundTrip(t *testing.T) {
+	codec, err := hashid.New("salt", "", 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []*hashid.Codec{nil, codec} {
+		hashid.Use(c)
+		for _, id := range []uint{0, 1, 42, 1 << 40} {
+			got, err := Decode(Encode(id))
+			if err != nil || got != id {
+				t.Errorf("codec %v: Decode(Encode(%d)) = %d, %v", c != nil, id, got, err)
+			}
+		}
+	}
+	hashid.Use(nil)
+}
+
+func TestDecodeRejects(t *testing.T) {
+	plain := Encode(42)
+
+	codec, _ := hashid.New("salt", "", 8)
+	hashid.Use(codec)
+	defer hashid.Use(nil)
+
+	// 启用混淆后不再接受原始主键编码的游标
+	for _, s := range []string{"", "!!!", "NDI=", plain} {
+		if _, err := Decode(s); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Decode(%q): want ErrInvalid, got %v", s, err)
+		}
+	}
+}
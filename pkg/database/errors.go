@@ -0,0 +1,20 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolationCode PostgreSQL 唯一约束冲突的错误码
+const uniqueViolationCode = "23505"
+
+// UniqueViolation 判断 err 是否为唯一约束冲突
+// 返回值为冲突的约束(索引)名称，便于上层映射为具体的业务错误
+func UniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
@@ -1,12 +1,17 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"gin-app-start/pkg/retry"
+
+	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/opentelemetry/tracing"
 )
 
 type PostgresConfig struct {
@@ -20,6 +25,11 @@ type PostgresConfig struct {
 	MaxOpenConns int
 	MaxLifetime  int
 	LogLevel     string
+
+	SlowThreshold time.Duration // 慢 SQL 阈值，0 表示不记录
+	SlowLogger    *zap.Logger   // 慢 SQL 日志输出
+
+	Tracing bool // 是否记录 OpenTelemetry span，查询通过 WithContext 带有请求的 span 时作为其子 span
 }
 
 type Repo interface {
@@ -78,14 +88,27 @@ func NewPostgresDB(config *PostgresConfig) (*gorm.DB, error) {
 		sqlDB.SetConnMaxLifetime(time.Duration(config.MaxLifetime) * time.Second)
 	}
 
-	if err := sqlDB.Ping(); err != nil {
+	// 启动时数据库可能尚未就绪(如 docker-compose 同时启动)，临时性错误重试几次
+	err = retry.Do(context.Background(), func(ctx context.Context) error {
+		return sqlDB.PingContext(ctx)
+	}, retry.WithName("db.ping"), retry.WithMaxAttempts(5), retry.WithBackoff(500*time.Millisecond, 5*time.Second))
+	if err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	DBRepo = &dbRepo{DB: db}
 
 	// 使用插件
-	db.Use(&TracePlugin{})
+	db.Use(&TracePlugin{
+		SlowThreshold: config.SlowThreshold,
+		SlowLogger:    config.SlowLogger,
+	})
+	// span 中的 SQL 不带参数值，避免密码哈希、手机号等敏感数据导出到追踪后端
+	if config.Tracing {
+		if err := db.Use(tracing.NewPlugin(tracing.WithoutMetrics(), tracing.WithoutQueryVariables())); err != nil {
+			return nil, fmt.Errorf("database tracing: %w", err)
+		}
+	}
 
 	return db, nil
 }
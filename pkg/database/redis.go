@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"time"
 
+	"gin-app-start/pkg/retry"
+
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -15,6 +18,9 @@ type RedisConfig struct {
 	PoolSize     int    // 连接池大小
 	MinIdleConns int    // 最小空闲连接数
 	MaxRetries   int    // 最大重试次数
+
+	Breaker *RedisBreakerConfig // 熔断配置，nil 表示不启用
+	Tracing bool                // 是否记录 OpenTelemetry span，命令的 context 带有请求的 span 时作为其子 span
 }
 
 func NewRedisClient(config *RedisConfig) (*redis.Client, error) {
@@ -30,14 +36,24 @@ func NewRedisClient(config *RedisConfig) (*redis.Client, error) {
 		WriteTimeout: 3 * time.Second,
 	})
 
-	timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// 需5s内连接成功，否则报错
-	_, err := client.Ping(timeoutCtx).Result()
+	// 每次尝试需5s内连接成功，临时性错误重试几次后仍失败则报错
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		return client.Ping(timeoutCtx).Err()
+	}, retry.WithName("redis.ping"), retry.WithBackoff(500*time.Millisecond, 2*time.Second))
 	if err != nil {
 		return nil, fmt.Errorf("cannot connect to redis: %w", err)
 	}
 
+	if config.Breaker != nil {
+		client.AddHook(newRedisBreakerHook(config.Breaker))
+	}
+	if config.Tracing {
+		if err := redisotel.InstrumentTracing(client); err != nil {
+			return nil, fmt.Errorf("redis tracing: %w", err)
+		}
+	}
+
 	return client, nil
 }
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sony/gobreaker"
+)
+
+// RedisBreakerConfig Redis 熔断配置
+type RedisBreakerConfig struct {
+	MaxFailures      uint32        // 连续失败多少次后熔断
+	OpenTimeout      time.Duration // 熔断持续时间，之后进入半开状态
+	HalfOpenRequests uint32        // 半开状态下允许通过的探测请求数
+
+	// OnStateChange 熔断状态变化回调，一般用于记录日志
+	OnStateChange func(from, to gobreaker.State)
+}
+
+// redisBreakerHook 以 go-redis hook 的方式给所有命令加上熔断，
+// Redis 抖动时直接快速失败，调用方(缓存)按未命中处理回源数据库，而不是每个请求都等待读超时
+type redisBreakerHook struct {
+	cb *gobreaker.CircuitBreaker
+}
+
+var _ redis.Hook = (*redisBreakerHook)(nil)
+
+func newRedisBreakerHook(config *RedisBreakerConfig) *redisBreakerHook {
+	settings := gobreaker.Settings{
+		Name:        "redis",
+		MaxRequests: config.HalfOpenRequests,
+		Timeout:     config.OpenTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= config.MaxFailures
+		},
+		IsSuccessful: isRedisHealthy,
+	}
+	if config.OnStateChange != nil {
+		settings.OnStateChange = func(_ string, from, to gobreaker.State) {
+			config.OnStateChange(from, to)
+		}
+	}
+	return &redisBreakerHook{cb: gobreaker.NewCircuitBreaker(settings)}
+}
+
+// isRedisHealthy 只有连接、超时类错误才计入失败；
+// 键不存在(redis.Nil)、WRONGTYPE 等 Redis 正常返回的错误不代表 Redis 不可用
+func isRedisHealthy(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) {
+		return true
+	}
+
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		return true
+	}
+
+	var netErr net.Error
+	return !errors.As(err, &netErr) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func (h *redisBreakerHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *redisBreakerHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		_, err := h.cb.Execute(func() (interface{}, error) {
+			return nil, next(ctx, cmd)
+		})
+		if isBreakerRejected(err) {
+			cmd.SetErr(err)
+		}
+		return err
+	}
+}
+
+func (h *redisBreakerHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		_, err := h.cb.Execute(func() (interface{}, error) {
+			return nil, next(ctx, cmds)
+		})
+		if isBreakerRejected(err) {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+		}
+		return err
+	}
+}
+
+// isBreakerRejected 请求是否被熔断器直接拒绝(未发送到 Redis)
+func isBreakerRejected(err error) bool {
+	return errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests)
+}
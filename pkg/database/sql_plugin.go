@@ -7,6 +7,7 @@ import (
 	"gin-app-start/pkg/timeutil"
 	"gin-app-start/pkg/trace"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 	"gorm.io/gorm/utils"
 )
@@ -17,7 +18,10 @@ const (
 	startTime          = "_start_time"
 )
 
-type TracePlugin struct{}
+type TracePlugin struct {
+	SlowThreshold time.Duration // 慢 SQL 阈值，0 表示不记录
+	SlowLogger    *zap.Logger   // 慢 SQL 日志输出
+}
 
 func (op *TracePlugin) Name() string {
 	return "tracePlugin"
@@ -34,12 +38,12 @@ func (op *TracePlugin) Initialize(db *gorm.DB) (err error) {
 	_ = db.Callback().Raw().Before("gorm:raw").Register(callBackBeforeName, before)                    // 原始SQL操作前
 
 	// 结束后
-	_ = db.Callback().Create().After("gorm:after_create").Register(callBackAfterName, after) // 创建操作后
-	_ = db.Callback().Query().After("gorm:after_query").Register(callBackAfterName, after)   // 查询操作后
-	_ = db.Callback().Delete().After("gorm:after_delete").Register(callBackAfterName, after) // 删除操作后
-	_ = db.Callback().Update().After("gorm:after_update").Register(callBackAfterName, after) // 更新操作后
-	_ = db.Callback().Row().After("gorm:row").Register(callBackAfterName, after)             // 行级操作后
-	_ = db.Callback().Raw().After("gorm:raw").Register(callBackAfterName, after)             // 原始SQL操作后
+	_ = db.Callback().Create().After("gorm:after_create").Register(callBackAfterName, op.after) // 创建操作后
+	_ = db.Callback().Query().After("gorm:after_query").Register(callBackAfterName, op.after)   // 查询操作后
+	_ = db.Callback().Delete().After("gorm:after_delete").Register(callBackAfterName, op.after) // 删除操作后
+	_ = db.Callback().Update().After("gorm:after_update").Register(callBackAfterName, op.after) // 更新操作后
+	_ = db.Callback().Row().After("gorm:row").Register(callBackAfterName, op.after)             // 行级操作后
+	_ = db.Callback().Raw().After("gorm:raw").Register(callBackAfterName, op.after)             // 原始SQL操作后
 	return
 }
 
@@ -51,14 +55,8 @@ func before(db *gorm.DB) {
 	db.InstanceSet(startTime, time.Now())
 }
 
-func after(db *gorm.DB) {
+func (op *TracePlugin) after(db *gorm.DB) {
 	// 1. 获取上下文和开始时间
-	_ctx := db.Statement.Context
-	ctx, ok := _ctx.(common.StdContext)
-	if !ok {
-		return
-	}
-
 	_ts, isExist := db.InstanceGet(startTime)
 	if !isExist {
 		return
@@ -69,6 +67,30 @@ func after(db *gorm.DB) {
 		return
 	}
 
+	cost := time.Since(ts)
+	ctx, isStdCtx := db.Statement.Context.(common.StdContext)
+
+	// 记录慢 SQL，不依赖请求上下文(后台任务中的 SQL 同样需要记录)
+	if op.SlowLogger != nil && op.SlowThreshold > 0 && cost > op.SlowThreshold {
+		var traceID string
+		if isStdCtx && ctx.Trace != nil {
+			traceID = ctx.Trace.ID()
+		}
+
+		op.SlowLogger.Warn("slow-query",
+			zap.String("sql", db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...)),
+			zap.Int64("rows_affected", db.Statement.RowsAffected),
+			zap.String("stack", utils.FileWithLineNum()),
+			zap.Float64("cost_seconds", cost.Seconds()),
+			zap.Float64("threshold_seconds", op.SlowThreshold.Seconds()),
+			zap.String("trace_id", traceID),
+		)
+	}
+
+	if !isStdCtx {
+		return
+	}
+
 	// 2. 构建SQL追踪信息
 	sql := db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...)
 
@@ -77,7 +99,7 @@ func after(db *gorm.DB) {
 	sqlInfo.SQL = sql                              // 完整的SQL语句
 	sqlInfo.Stack = utils.FileWithLineNum()        // 文件地址和行号
 	sqlInfo.Rows = db.Statement.RowsAffected       // 受影响的行数
-	sqlInfo.CostSeconds = time.Since(ts).Seconds() // 执行耗时（秒）
+	sqlInfo.CostSeconds = cost.Seconds()           // 执行耗时（秒）
 
 	// 3. 追加到上下文的SQL追踪列表
 	if ctx.Trace != nil {
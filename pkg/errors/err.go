@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"io"
 	"runtime"
@@ -26,6 +27,7 @@ var _ fmt.Formatter = (*item)(nil)
 type item struct {
 	msg   string
 	stack []uintptr
+	cause error // 被包装的原始错误，供 errors.Is/As 沿链查找
 }
 
 func (i *item) Error() string {
@@ -34,6 +36,11 @@ func (i *item) Error() string {
 
 func (i *item) t() {}
 
+// Unwrap 返回被包装的原始错误
+func (i *item) Unwrap() error {
+	return i.cause
+}
+
 // Format used by go.uber.org/zap in Verbose
 func (i *item) Format(s fmt.State, verb rune) {
 	io.WriteString(s, i.msg)
@@ -55,6 +62,7 @@ func Errorf(format string, args ...interface{}) Error {
 }
 
 // Wrap with some extra message into err
+// 返回新的错误且保留原始错误，不会修改 err 本身(err 可能是包级的哨兵错误)
 func Wrap(err error, msg string) Error {
 	if err == nil {
 		return nil
@@ -62,11 +70,10 @@ func Wrap(err error, msg string) Error {
 
 	e, ok := err.(*item)
 	if !ok {
-		return &item{msg: fmt.Sprintf("%s; %s", msg, err.Error()), stack: callers()}
+		return &item{msg: fmt.Sprintf("%s; %s", msg, err.Error()), stack: callers(), cause: err}
 	}
 
-	e.msg = fmt.Sprintf("%s; %s", msg, e.msg)
-	return e
+	return &item{msg: fmt.Sprintf("%s; %s", msg, e.msg), stack: e.stack, cause: e}
 }
 
 // Wrapf with some extra message into err
@@ -79,11 +86,10 @@ func Wrapf(err error, format string, args ...interface{}) Error {
 
 	e, ok := err.(*item)
 	if !ok {
-		return &item{msg: fmt.Sprintf("%s; %s", msg, err.Error()), stack: callers()}
+		return &item{msg: fmt.Sprintf("%s; %s", msg, err.Error()), stack: callers(), cause: err}
 	}
 
-	e.msg = fmt.Sprintf("%s; %s", msg, e.msg)
-	return e
+	return &item{msg: fmt.Sprintf("%s; %s", msg, e.msg), stack: e.stack, cause: e}
 }
 
 // WithStack add caller stack information
@@ -96,5 +102,47 @@ func WithStack(err error) Error {
 		return e
 	}
 
-	return &item{msg: err.Error(), stack: callers()}
+	return &item{msg: err.Error(), stack: callers(), cause: err}
+}
+
+// Is 同标准库 errors.Is
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As 同标准库 errors.As
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
+
+// Unwrap 同标准库 errors.Unwrap
+func Unwrap(err error) error {
+	return stderrors.Unwrap(err)
+}
+
+// coder 携带业务码的错误，如 common.BusinessError
+type coder interface {
+	BusinessCode() int
+}
+
+// Code 沿错误链查找第一个携带业务码的错误并返回业务码
+func Code(err error) (int, bool) {
+	for err != nil {
+		if c, ok := err.(coder); ok {
+			return c.BusinessCode(), true
+		}
+		err = stderrors.Unwrap(err)
+	}
+	return 0, false
+}
+
+// IsCode 判断错误链中是否存在业务码为 code 的错误
+func IsCode(err error, code int) bool {
+	for err != nil {
+		if c, ok := err.(coder); ok && c.BusinessCode() == code {
+			return true
+		}
+		err = stderrors.Unwrap(err)
+	}
+	return false
 }
@@ -32,3 +32,42 @@ func TestErr(t *testing.T) {
 
 	t.Logf("%+v", New("a dummy error"))
 }
+
+type codeErr struct{ code int }
+
+func (e *codeErr) Error() string     { return "code error" }
+func (e *codeErr) BusinessCode() int { return e.code }
+
+func TestWrapKeepsCause(t *testing.T) {
+	sentinel := New("sentinel")
+
+	wrapped := Wrapf(Wrap(sentinel, "layer one"), "layer %d", 2)
+	if !Is(wrapped, sentinel) {
+		t.Fatalf("expected wrapped error to match sentinel")
+	}
+	if sentinel.Error() != "sentinel" {
+		t.Fatalf("sentinel message mutated: %q", sentinel.Error())
+	}
+
+	std := errors.New("std err")
+	if !Is(WithStack(std), std) {
+		t.Fatalf("expected WithStack to keep std error")
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	err := Wrap(&codeErr{code: 10023}, "query failed")
+
+	if !IsCode(err, 10023) {
+		t.Fatalf("expected code 10023 in chain")
+	}
+	if IsCode(err, 10024) {
+		t.Fatalf("unexpected code 10024 in chain")
+	}
+	if code, ok := Code(err); !ok || code != 10023 {
+		t.Fatalf("Code() = %d, %v", code, ok)
+	}
+	if _, ok := Code(New("plain")); ok {
+		t.Fatalf("plain error should not carry a code")
+	}
+}
@@ -0,0 +1,180 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gin-app-start/pkg/secrets"
+)
+
+// prefix 密文前缀，完整格式为 enc:<key_id>:base64url(nonce+密文)，不带前缀的值视为未加密的明文
+const prefix = "enc:"
+
+// KeySize 密钥长度，AES-256
+const KeySize = 32
+
+var (
+	// ErrUnknownKey 密文使用的密钥未配置，轮换后旧密钥被提前移除时会出现
+	ErrUnknownKey = errors.New("fieldcrypt: unknown key")
+	// ErrMalformed 密文格式错误或被篡改
+	ErrMalformed = errors.New("fieldcrypt: malformed ciphertext")
+)
+
+type key struct {
+	aead cipher.AEAD
+	mac  []byte
+}
+
+// Cipher 字段加解密，使用 AES-256-GCM，nonce 由明文的 HMAC 派生
+//
+// 同一密钥下相同明文得到相同密文，数据库中的等值查询和唯一索引仍然有效；
+// 代价是能看出两行的值是否相同，只适用于手机号、邮箱这类需要按值查找的字段。
+type Cipher struct {
+	current string
+	keys    map[string]*key
+}
+
+// New 创建 Cipher，keys 为密钥ID -> 32 字节密钥，current 为加密使用的密钥，其余密钥只用于解密
+func New(current string, keys map[string][]byte) (*Cipher, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("fieldcrypt: current key %q is not configured", current)
+	}
+
+	c := &Cipher{current: current, keys: make(map[string]*key, len(keys))}
+	for id, raw := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("fieldcrypt: invalid key id %q", id)
+		}
+		if len(raw) != KeySize {
+			return nil, fmt.Errorf("fieldcrypt: key %q must be %d bytes, got %d", id, KeySize, len(raw))
+		}
+
+		// 加密和派生 nonce 使用不同的子密钥
+		block, err := aes.NewCipher(derive(raw, "encrypt"))
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		c.keys[id] = &key{aead: aead, mac: derive(raw, "nonce")}
+	}
+	return c, nil
+}
+
+// FromProvider 从密钥服务读取密钥创建 Cipher，密钥名为 pii_key_<id>，内容为 base64 编码的 32 字节
+func FromProvider(ctx context.Context, provider secrets.Provider, current string, ids []string) (*Cipher, error) {
+	keys := make(map[string][]byte, len(ids))
+	for _, id := range ids {
+		encoded, err := provider.Get(ctx, "pii_key_"+id)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: key %q is not valid base64: %w", id, err)
+		}
+		keys[id] = raw
+	}
+	return New(current, keys)
+}
+
+func derive(master []byte, label string) []byte {
+	h := hmac.New(sha256.New, master)
+	h.Write([]byte(label))
+	return h.Sum(nil)
+}
+
+// CurrentKey 当前加密使用的密钥ID
+func (c *Cipher) CurrentKey() string {
+	return c.current
+}
+
+// Encrypt 使用当前密钥加密，空字符串不加密
+func (c *Cipher) Encrypt(plain string) (string, error) {
+	return c.encryptWith(c.current, plain)
+}
+
+func (c *Cipher) encryptWith(id, plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+
+	k := c.keys[id]
+	h := hmac.New(sha256.New, k.mac)
+	h.Write([]byte(plain))
+	nonce := h.Sum(nil)[:k.aead.NonceSize()]
+
+	sealed := k.aead.Seal(nonce, nonce, []byte(plain), []byte(id))
+	return prefix + id + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密，不带密文前缀的值原样返回，兼容加密上线前写入的明文
+func (c *Cipher) Decrypt(value string) (string, error) {
+	id, payload, ok := split(value)
+	if !ok {
+		return value, nil
+	}
+
+	k, found := c.keys[id]
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < k.aead.NonceSize() {
+		return "", ErrMalformed
+	}
+
+	nonce, ciphertext := sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():]
+	plain, err := k.aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", ErrMalformed
+	}
+	return string(plain), nil
+}
+
+// Candidates 返回明文在每个密钥下的密文以及明文本身，用于等值查询
+// 密钥轮换完成前，同一个值在库里可能是旧密钥的密文或尚未加密的明文
+func (c *Cipher) Candidates(plain string) []string {
+	res := make([]string, 0, len(c.keys)+1)
+	res = append(res, plain)
+	if plain == "" {
+		return res
+	}
+	for id := range c.keys {
+		encrypted, _ := c.encryptWith(id, plain)
+		res = append(res, encrypted)
+	}
+	return res
+}
+
+// NeedsRotation 值是否需要用当前密钥重新加密: 明文或其他密钥的密文，空字符串不需要
+func (c *Cipher) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	id, _, ok := split(value)
+	return !ok || id != c.current
+}
+
+// IsEncrypted 值是否为密文
+func IsEncrypted(value string) bool {
+	_, _, ok := split(value)
+	return ok
+}
+
+func split(value string) (id, payload string, ok bool) {
+	rest, found := strings.CutPrefix(value, prefix)
+	if !found {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
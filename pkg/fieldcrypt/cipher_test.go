@@ -0,0 +1,97 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKeys() map[string][]byte {
+	return map[string][]byte{
+		"v1": bytes.Repeat([]byte{1}, KeySize),
+		"v2": bytes.Repeat([]byte{2}, KeySize),
+	}
+}
+
+func TestEncryptDeterministic(t *testing.T) {
+	c, err := New("v1", testKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, _ := c.Encrypt("john@example.com")
+	b, _ := c.Encrypt("john@example.com")
+	if a != b {
+		t.Fatalf("same plaintext should give same ciphertext: %s != %s", a, b)
+	}
+	if !strings.HasPrefix(a, "enc:v1:") || strings.Contains(a, "john") {
+		t.Fatalf("unexpected ciphertext %s", a)
+	}
+
+	other, _ := c.Encrypt("jane@example.com")
+	if other == a {
+		t.Fatal("different plaintexts should give different ciphertexts")
+	}
+
+	plain, err := c.Decrypt(a)
+	if err != nil || plain != "john@example.com" {
+		t.Fatalf("Decrypt = %q, %v", plain, err)
+	}
+}
+
+func TestDecryptPlaintextAndTampered(t *testing.T) {
+	c, _ := New("v1", testKeys())
+
+	if plain, err := c.Decrypt("13800138000"); err != nil || plain != "13800138000" {
+		t.Fatalf("legacy plaintext should pass through, got %q, %v", plain, err)
+	}
+
+	enc, _ := c.Encrypt("13800138000")
+	tampered := enc[:len(enc)-2] + "AA"
+	if _, err := c.Decrypt(tampered); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected ErrMalformed, got %v", err)
+	}
+	if _, err := c.Decrypt("enc:v9:AAAA"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	old, _ := New("v1", testKeys())
+	enc, _ := old.Encrypt("john@example.com")
+
+	c, _ := New("v2", testKeys())
+	if !c.NeedsRotation(enc) || !c.NeedsRotation("john@example.com") || c.NeedsRotation("") {
+		t.Fatal("old ciphertext and plaintext should need rotation, empty should not")
+	}
+
+	plain, err := c.Decrypt(enc)
+	if err != nil || plain != "john@example.com" {
+		t.Fatalf("Decrypt with old key = %q, %v", plain, err)
+	}
+	rotated, _ := c.Encrypt(plain)
+	if c.NeedsRotation(rotated) {
+		t.Fatal("value encrypted with current key should not need rotation")
+	}
+
+	candidates := c.Candidates("john@example.com")
+	for _, want := range []string{"john@example.com", enc, rotated} {
+		found := false
+		for _, v := range candidates {
+			found = found || v == want
+		}
+		if !found {
+			t.Fatalf("candidates %v missing %s", candidates, want)
+		}
+	}
+}
+
+func TestNewValidatesKeys(t *testing.T) {
+	if _, err := New("v3", testKeys()); err == nil {
+		t.Fatal("missing current key should fail")
+	}
+	if _, err := New("v1", map[string][]byte{"v1": []byte("short")}); err == nil {
+		t.Fatal("short key should fail")
+	}
+}
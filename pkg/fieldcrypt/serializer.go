@@ -0,0 +1,88 @@
+package fieldcrypt
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName GORM 序列化器名称，在模型字段上使用 `gorm:"serializer:encrypt"`
+const SerializerName = "encrypt"
+
+// active 当前生效的 Cipher；GORM 的序列化器是全局注册的，密钥也只能全局设置
+var active atomic.Pointer[Cipher]
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// Use 设置序列化器使用的 Cipher，为 nil 时按明文读写(已加密的值仍无法读取)
+func Use(c *Cipher) {
+	active.Store(c)
+}
+
+// Active 当前生效的 Cipher，未启用加密时返回 nil
+func Active() *Cipher {
+	return active.Load()
+}
+
+// Seal 按当前配置加密，未启用加密时原样返回
+// 用于 Updates(map) 这类不经过序列化器的写入
+func Seal(plain string) (string, error) {
+	c := active.Load()
+	if c == nil {
+		return plain, nil
+	}
+	return c.Encrypt(plain)
+}
+
+// Lookup 返回等值查询时需要匹配的值，配合 WHERE col IN ? 使用
+func Lookup(plain string) []string {
+	c := active.Load()
+	if c == nil {
+		return []string{plain}
+	}
+	return c.Candidates(plain)
+}
+
+// Serializer 字符串字段的透明加解密
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("fieldcrypt: unsupported value type %T for field %s", dbValue, field.Name)
+	}
+
+	if IsEncrypted(value) {
+		c := active.Load()
+		if c == nil {
+			return fmt.Errorf("fieldcrypt: field %s is encrypted but encryption is not configured", field.Name)
+		}
+		plain, err := c.Decrypt(value)
+		if err != nil {
+			return err
+		}
+		value = plain
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(value)
+	return nil
+}
+
+func (Serializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plain, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("fieldcrypt: field %s must be a string, got %T", field.Name, fieldValue)
+	}
+	return Seal(plain)
+}
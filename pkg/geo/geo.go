@@ -0,0 +1,50 @@
+package geo
+
+import "math"
+
+// earthRadiusKm 地球平均半径，单位公里
+const earthRadiusKm = 6371.0088
+
+// Distance 两点之间的球面距离(haversine 公式)，单位公里
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// Box 经纬度矩形范围
+type Box struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// BoundingBox 包含以 (lat, lng) 为圆心、radiusKm 公里为半径的圆的最小矩形，
+// 用于数据库按经纬度范围粗筛，再用 Distance 精确过滤；不处理跨越 180 度经线的情况
+func BoundingBox(lat, lng, radiusKm float64) Box {
+	dLat := degrees(radiusKm / earthRadiusKm)
+
+	box := Box{
+		MinLat: math.Max(lat-dLat, -90),
+		MaxLat: math.Min(lat+dLat, 90),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+	// 靠近极点时经度范围覆盖全部
+	if box.MinLat > -90 && box.MaxLat < 90 {
+		dLng := degrees(math.Asin(math.Sin(radiusKm/earthRadiusKm) / math.Cos(radians(lat))))
+		box.MinLng = math.Max(lng-dLng, -180)
+		box.MaxLng = math.Min(lng+dLng, 180)
+	}
+	return box
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
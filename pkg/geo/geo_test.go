@@ -0,0 +1,36 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	// 北京天安门 -> 上海人民广场，约 1067 公里
+	d := Distance(39.9087, 116.3975, 31.2304, 121.4737)
+	if math.Abs(d-1067) > 5 {
+		t.Fatalf("unexpected distance %.1f km", d)
+	}
+
+	if d := Distance(31.2304, 121.4737, 31.2304, 121.4737); d != 0 {
+		t.Fatalf("distance to self should be 0, got %f", d)
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	lat, lng, radius := 31.2304, 121.4737, 10.0
+	box := BoundingBox(lat, lng, radius)
+
+	// 圆上的四个方向的点都应落在矩形内
+	for _, p := range [][2]float64{
+		{box.MinLat, lng}, {box.MaxLat, lng}, {lat, box.MinLng}, {lat, box.MaxLng},
+	} {
+		if d := Distance(lat, lng, p[0], p[1]); d < radius-0.01 {
+			t.Fatalf("box edge %v is inside the circle (%.3f km)", p, d)
+		}
+	}
+
+	if box := BoundingBox(89.99, 0, 10); box.MinLng != -180 || box.MaxLng != 180 {
+		t.Fatalf("box near the pole should cover all longitudes, got %+v", box)
+	}
+}
@@ -0,0 +1,255 @@
+// Package hashid 按 Hashids 算法把数字ID编码为不连续的短字符串，用于对外接口隐藏自增ID
+//
+// 编码结果与其他语言的 Hashids 实现一致(相同的 salt、alphabet、min_length 得到相同的字符串)。
+// 这只是混淆而不是加密，不能代替权限校验。
+package hashid
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	// DefaultAlphabet 默认字符表
+	DefaultAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+
+	defaultSeparators = "cfhistuCFHISTU"
+	minAlphabetLength = 16
+	separatorDiv      = 3.5
+	guardDiv          = 12.0
+)
+
+var (
+	// ErrInvalid 字符串不是当前配置编码出的ID
+	ErrInvalid = errors.New("hashid: invalid id")
+	// ErrAlphabet 字符表过短、含重复或空白字符
+	ErrAlphabet = errors.New("hashid: alphabet must contain at least 16 unique characters without spaces")
+)
+
+// Codec ID 编解码器，创建后只读，可以并发使用
+type Codec struct {
+	salt       []byte
+	alphabet   []byte
+	separators []byte
+	guards     []byte
+	minLength  int
+}
+
+// New 创建编解码器，alphabet 为空时使用 DefaultAlphabet，minLength 为编码结果的最小长度
+func New(salt, alphabet string, minLength int) (*Codec, error) {
+	if alphabet == "" {
+		alphabet = DefaultAlphabet
+	}
+	if strings.ContainsAny(alphabet, " \t\r\n") {
+		return nil, ErrAlphabet
+	}
+
+	var chars []byte
+	seen := make(map[byte]bool, len(alphabet))
+	for i := 0; i < len(alphabet); i++ {
+		if seen[alphabet[i]] {
+			return nil, ErrAlphabet
+		}
+		seen[alphabet[i]] = true
+		chars = append(chars, alphabet[i])
+	}
+	if len(chars) < minAlphabetLength {
+		return nil, ErrAlphabet
+	}
+
+	// 分隔符只保留字符表中存在的字符，并从字符表中移除
+	var separators, rest []byte
+	for _, c := range []byte(defaultSeparators) {
+		if seen[c] {
+			separators = append(separators, c)
+		}
+	}
+	for _, c := range chars {
+		if !strings.ContainsRune(defaultSeparators, rune(c)) {
+			rest = append(rest, c)
+		}
+	}
+	chars = rest
+
+	c := &Codec{salt: []byte(salt), minLength: minLength}
+	shuffle(separators, c.salt)
+
+	if len(separators) == 0 || float64(len(chars))/float64(len(separators)) > separatorDiv {
+		n := int(math.Ceil(float64(len(chars)) / separatorDiv))
+		if n == 1 {
+			n = 2
+		}
+		if n > len(separators) {
+			diff := n - len(separators)
+			separators = append(separators, chars[:diff]...)
+			chars = chars[diff:]
+		} else {
+			separators = separators[:n]
+		}
+	}
+	shuffle(chars, c.salt)
+
+	guardCount := int(math.Ceil(float64(len(chars)) / guardDiv))
+	if len(chars) < 3 {
+		c.guards = separators[:guardCount]
+		separators = separators[guardCount:]
+	} else {
+		c.guards = chars[:guardCount]
+		chars = chars[guardCount:]
+	}
+
+	c.alphabet = chars
+	c.separators = separators
+	return c, nil
+}
+
+// Encode 编码一个或多个非负整数
+func (c *Codec) Encode(numbers ...uint64) string {
+	if len(numbers) == 0 {
+		return ""
+	}
+
+	alphabet := append([]byte(nil), c.alphabet...)
+
+	var numbersHash uint64
+	for i, n := range numbers {
+		numbersHash += n % uint64(i+100)
+	}
+
+	lottery := alphabet[numbersHash%uint64(len(alphabet))]
+	result := []byte{lottery}
+	buffer := make([]byte, 0, 1+len(c.salt)+len(alphabet))
+	for i, n := range numbers {
+		buffer = append(append(append(buffer[:0], lottery), c.salt...), alphabet...)
+		shuffle(alphabet, buffer[:len(alphabet)])
+
+		last := hash(n, alphabet)
+		result = append(result, last...)
+		if i+1 < len(numbers) {
+			n %= uint64(last[0]) + uint64(i)
+			result = append(result, c.separators[n%uint64(len(c.separators))])
+		}
+	}
+
+	if len(result) < c.minLength {
+		guard := c.guards[(numbersHash+uint64(result[0]))%uint64(len(c.guards))]
+		result = append([]byte{guard}, result...)
+
+		if len(result) < c.minLength {
+			guard = c.guards[(numbersHash+uint64(result[2]))%uint64(len(c.guards))]
+			result = append(result, guard)
+		}
+	}
+
+	half := len(alphabet) / 2
+	for len(result) < c.minLength {
+		shuffle(alphabet, append([]byte(nil), alphabet...))
+		padded := make([]byte, 0, len(result)+len(alphabet))
+		padded = append(padded, alphabet[half:]...)
+		padded = append(padded, result...)
+		padded = append(padded, alphabet[:half]...)
+		result = padded
+
+		if excess := len(result) - c.minLength; excess > 0 {
+			result = result[excess/2 : excess/2+c.minLength]
+		}
+	}
+	return string(result)
+}
+
+// Decode 解码 Encode 的结果，不是当前配置编码出的字符串时返回 ErrInvalid
+func (c *Codec) Decode(id string) ([]uint64, error) {
+	if id == "" {
+		return nil, ErrInvalid
+	}
+
+	parts := splitAny(id, c.guards)
+	breakdown := parts[0]
+	if len(parts) == 2 || len(parts) == 3 {
+		breakdown = parts[1]
+	}
+	if breakdown == "" {
+		return nil, ErrInvalid
+	}
+
+	alphabet := append([]byte(nil), c.alphabet...)
+	lottery := breakdown[0]
+	var numbers []uint64
+	buffer := make([]byte, 0, 1+len(c.salt)+len(alphabet))
+	for _, sub := range splitAny(breakdown[1:], c.separators) {
+		buffer = append(append(append(buffer[:0], lottery), c.salt...), alphabet...)
+		shuffle(alphabet, buffer[:len(alphabet)])
+
+		n, ok := unhash(sub, alphabet)
+		if !ok {
+			return nil, ErrInvalid
+		}
+		numbers = append(numbers, n)
+	}
+
+	// 任意字符串都可能解出数字，重新编码一致才是合法的ID
+	if len(numbers) == 0 || c.Encode(numbers...) != id {
+		return nil, ErrInvalid
+	}
+	return numbers, nil
+}
+
+// shuffle 按 salt 确定性地打乱 alphabet(原地修改)
+func shuffle(alphabet, salt []byte) {
+	if len(salt) == 0 {
+		return
+	}
+	for i, v, p := len(alphabet)-1, 0, 0; i > 0; i, v = i-1, v+1 {
+		v %= len(salt)
+		n := int(salt[v])
+		p += n
+		j := (n + v + p) % i
+		alphabet[i], alphabet[j] = alphabet[j], alphabet[i]
+	}
+}
+
+func hash(n uint64, alphabet []byte) []byte {
+	size := uint64(len(alphabet))
+	var result []byte
+	for {
+		result = append([]byte{alphabet[n%size]}, result...)
+		n /= size
+		if n == 0 {
+			return result
+		}
+	}
+}
+
+func unhash(s string, alphabet []byte) (uint64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	size := uint64(len(alphabet))
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		pos := bytes.IndexByte(alphabet, s[i])
+		if pos < 0 {
+			return 0, false
+		}
+		if n > (math.MaxUint64-uint64(pos))/size {
+			return 0, false
+		}
+		n = n*size + uint64(pos)
+	}
+	return n, true
+}
+
+// splitAny 按 seps 中的任一字符切分，保留空段(守卫字符位于首尾时段数有意义)
+func splitAny(s string, seps []byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if bytes.IndexByte(seps, s[i]) >= 0 {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
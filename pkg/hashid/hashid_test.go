@@ -0,0 +1,92 @@
+package hashid
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// 与 hashids.js / hashids.py 的输出对照
+func TestEncodeCompatible(t *testing.T) {
+	c, err := New("this is my salt", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Encode(12345); got != "NkK9" {
+		t.Errorf("Encode(12345) = %q, want NkK9", got)
+	}
+	if got := c.Encode(1, 2, 3); got != "laHquq" {
+		t.Errorf("Encode(1, 2, 3) = %q, want laHquq", got)
+	}
+
+	padded, _ := New("this is my salt", "", 8)
+	if got := padded.Encode(1); got != "gB0NV05e" {
+		t.Errorf("Encode(1) with min length 8 = %q, want gB0NV05e", got)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	c, _ := New("salt", "", 10)
+	for _, n := range []uint64{0, 1, 42, 99999, 1 << 40} {
+		s := c.Encode(n)
+		if len(s) < 10 {
+			t.Errorf("Encode(%d) = %q, shorter than min length", n, s)
+		}
+		got, err := c.Decode(s)
+		if err != nil || len(got) != 1 || got[0] != n {
+			t.Errorf("Decode(%q) = %v, %v, want %d", s, got, err, n)
+		}
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	c, _ := New("salt", "", 8)
+	other, _ := New("other salt", "", 8)
+
+	for _, s := range []string{"", "1", "!!!!", other.Encode(42), c.Encode(42) + "x"} {
+		if _, err := c.Decode(s); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Decode(%q): want ErrInvalid, got %v", s, err)
+		}
+	}
+}
+
+func TestNewRejectsAlphabet(t *testing.T) {
+	for _, alphabet := range []string{"abc", "abcdefghijklmnopa", "abcdefghij klmnopq"} {
+		if _, err := New("salt", alphabet, 0); !errors.Is(err, ErrAlphabet) {
+			t.Errorf("New(%q): want ErrAlphabet, got %v", alphabet, err)
+		}
+	}
+}
+
+func TestIDJSON(t *testing.T) {
+	type payload struct {
+		ID ID `json:"id"`
+	}
+
+	Use(nil)
+	data, _ := json.Marshal(payload{ID: 42})
+	if string(data) != `{"id":42}` {
+		t.Errorf("disabled: got %s", data)
+	}
+
+	c, _ := New("salt", "", 8)
+	Use(c)
+	defer Use(nil)
+
+	data, _ = json.Marshal(payload{ID: 42})
+	want := `{"id":"` + c.Encode(42) + `"}`
+	if string(data) != want {
+		t.Errorf("enabled: got %s, want %s", data, want)
+	}
+
+	var p payload
+	if err := json.Unmarshal(data, &p); err != nil || p.ID != 42 {
+		t.Errorf("unmarshal: got %d, %v", p.ID, err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":42}`), &p); err == nil {
+		t.Error("enabled: plain number should be rejected")
+	}
+	if _, err := Parse("42"); err == nil {
+		t.Error("enabled: Parse should reject plain numbers")
+	}
+}
@@ -0,0 +1,81 @@
+package hashid
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+)
+
+// active 当前生效的编解码器；ID 类型在 JSON 编解码时没有其他途径拿到配置，只能全局设置
+var active atomic.Pointer[Codec]
+
+// Use 设置 ID 使用的编解码器，为 nil 时 ID 按数字输出
+func Use(c *Codec) {
+	active.Store(c)
+}
+
+// Active 当前生效的编解码器，未启用混淆时返回 nil
+func Active() *Codec {
+	return active.Load()
+}
+
+// ID 对外接口中的数字ID，启用混淆时在 JSON 中编码为字符串，内部仍按 uint 使用
+//
+// 只在 DTO 中使用，模型和数据库保持 uint 主键。
+type ID uint
+
+// Encode 按当前配置编码，未启用混淆时返回十进制数字
+func Encode(id uint) string {
+	c := active.Load()
+	if c == nil {
+		return strconv.FormatUint(uint64(id), 10)
+	}
+	return c.Encode(uint64(id))
+}
+
+// Parse 解析路径参数或查询参数中的ID；启用混淆后只接受编码后的字符串，不再接受数字
+func Parse(s string) (uint, error) {
+	c := active.Load()
+	if c == nil {
+		n, err := strconv.ParseUint(s, 10, 0)
+		if err != nil {
+			return 0, ErrInvalid
+		}
+		return uint(n), nil
+	}
+
+	numbers, err := c.Decode(s)
+	if err != nil || len(numbers) != 1 || uint64(uint(numbers[0])) != numbers[0] {
+		return 0, ErrInvalid
+	}
+	return uint(numbers[0]), nil
+}
+
+func (id ID) MarshalJSON() ([]byte, error) {
+	if active.Load() == nil {
+		return strconv.AppendUint(nil, uint64(id), 10), nil
+	}
+	return json.Marshal(Encode(uint(id)))
+}
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if active.Load() == nil {
+		var n uint
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+		*id = ID(n)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	n, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*id = ID(n)
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package i18n 按请求协商的语言翻译响应中的提示信息
+//
+// 消息以 ID 注册到 Bundle 中，翻译时既可以传 ID，也可以传任一语言的原文(如代码中写死的英文提示、
+// 按配置语言取出的错误码文本)，找不到时原样返回，未注册的动态文本不受影响。
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bundle 各语言的消息，创建并添加完消息后只读，可以并发使用
+type Bundle struct {
+	fallback string
+	langs    []string                     // 支持的语言，按添加顺序
+	messages map[string]map[string]string // 语言 -> 消息ID -> 文本
+	ids      map[string]string            // 任一语言的文本 -> 消息ID
+}
+
+// NewBundle 创建消息集合，fallback 为请求未指定或不支持所指定的语言时使用的语言
+func NewBundle(fallback string) *Bundle {
+	return &Bundle{
+		fallback: normalize(fallback),
+		messages: make(map[string]map[string]string),
+		ids:      make(map[string]string),
+	}
+}
+
+// Add 添加一种语言的消息，键为消息ID；同一语言多次添加时合并
+func (b *Bundle) Add(lang string, messages map[string]string) {
+	lang = normalize(lang)
+	if _, ok := b.messages[lang]; !ok {
+		b.langs = append(b.langs, lang)
+		b.messages[lang] = make(map[string]string, len(messages))
+	}
+	ids := make([]string, 0, len(messages))
+	for id := range messages {
+		ids = append(ids, id)
+	}
+	// 不同消息的文本相同时按消息ID排序保留第一个，结果不随 map 遍历顺序变化
+	sort.Strings(ids)
+	for _, id := range ids {
+		text := messages[id]
+		b.messages[lang][id] = text
+		if _, ok := b.ids[text]; !ok && text != "" {
+			b.ids[text] = id
+		}
+	}
+}
+
+// Languages 支持的语言
+func (b *Bundle) Languages() []string {
+	return b.langs
+}
+
+// Translate 把消息ID或任一语言的原文翻译为 lang，lang 中没有该消息时使用 fallback，都没有时原样返回
+func (b *Bundle) Translate(lang, key string) string {
+	id := key
+	if _, ok := b.messages[b.fallback][id]; !ok {
+		if mapped, ok := b.ids[key]; ok {
+			id = mapped
+		}
+	}
+	for _, l := range []string{normalize(lang), b.fallback} {
+		if text, ok := b.messages[l][id]; ok && text != "" {
+			return text
+		}
+	}
+	return key
+}
+
+// Match 按 Accept-Language 请求头选择支持的语言: 按权重从高到低先完全匹配(不区分大小写)，
+// 再按主语言匹配(如 zh-TW、zh 匹配 zh-cn)；都不支持时返回 fallback
+func (b *Bundle) Match(acceptLanguage string) string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if tag = normalize(tag); tag != "" && q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	for _, t := range tags {
+		if _, ok := b.messages[t.tag]; ok {
+			return t.tag
+		}
+		primary, _, _ := strings.Cut(t.tag, "-")
+		for _, lang := range b.langs {
+			if p, _, _ := strings.Cut(lang, "-"); p == primary {
+				return lang
+			}
+		}
+	}
+	return b.fallback
+}
+
+// normalize 语言标签统一为小写、以 - 分隔，如 zh_CN -> zh-cn
+func normalize(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// active 当前生效的消息集合；pkg/response 等只拿得到 gin.Context 的地方没有其他途径拿到配置，只能全局设置
+var active atomic.Pointer[Bundle]
+
+// Use 设置生效的消息集合，为 nil 时不翻译
+func Use(b *Bundle) {
+	active.Store(b)
+}
+
+// Active 当前生效的消息集合，未设置时返回 nil
+func Active() *Bundle {
+	return active.Load()
+}
+
+// languageKey 协商出的语言在 gin.Context 中的键
+const languageKey = "_i18n_language"
+
+// Language 请求协商出的语言，按 Accept-Language 请求头选择，同一请求只解析一次；未设置消息集合时返回空字符串
+func Language(c *gin.Context) string {
+	b := active.Load()
+	if b == nil || c == nil {
+		return ""
+	}
+	if lang := c.GetString(languageKey); lang != "" {
+		return lang
+	}
+
+	lang := b.fallback
+	if c.Request != nil {
+		lang = b.Match(c.GetHeader("Accept-Language"))
+	}
+	c.Set(languageKey, lang)
+	return lang
+}
+
+// T 按请求协商的语言翻译消息ID或原文，未设置消息集合时原样返回
+func T(c *gin.Context, key string) string {
+	b := active.Load()
+	if b == nil {
+		return key
+	}
+	return b.Translate(Language(c), key)
+}
@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestBundle() *Bundle {
+	b := NewBundle("zh-CN")
+	b.Add("zh-CN", map[string]string{"code.10103": "参数信息错误", "Deleted successfully": "删除成功"})
+	b.Add("en-US", map[string]string{"code.10103": "Parameter error", "Deleted successfully": "Deleted successfully"})
+	return b
+}
+
+func TestMatch(t *testing.T) {
+	b := newTestBundle()
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "zh-cn"},
+		{"en-US", "en-us"},
+		{"en", "en-us"},
+		{"zh-TW,en;q=0.8", "zh-cn"},
+		{"fr-FR,en;q=0.5,zh;q=0.9", "zh-cn"},
+		{"fr, de;q=0.7", "zh-cn"},
+		{"en;q=0, zh_CN", "zh-cn"},
+		{"en;q=bad, en-US;q=0.1", "en-us"},
+	}
+	for _, tt := range tests {
+		if got := b.Match(tt.header); got != tt.want {
+			t.Errorf("Match(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	b := newTestBundle()
+	tests := []struct {
+		lang, key, want string
+	}{
+		{"en-us", "code.10103", "Parameter error"},
+		{"en-us", "参数信息错误", "Parameter error"}, // 按配置语言取出的错误码文本
+		{"zh-cn", "Deleted successfully", "删除成功"},
+		{"en-us", "Deleted successfully", "Deleted successfully"},
+		{"fr", "Deleted successfully", "删除成功"}, // 不支持的语言使用 fallback
+		{"en-us", "GET /unknown not found", "GET /unknown not found"},
+	}
+	for _, tt := range tests {
+		if got := b.Translate(tt.lang, tt.key); got != tt.want {
+			t.Errorf("Translate(%q, %q) = %q, want %q", tt.lang, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	if got := T(c, "删除成功"); got != "删除成功" {
+		t.Errorf("T without bundle = %q", got)
+	}
+
+	Use(newTestBundle())
+	defer Use(nil)
+	if got := T(c, "删除成功"); got != "Deleted successfully" {
+		t.Errorf("T = %q, want Deleted successfully", got)
+	}
+	if got := Language(c); got != "en-us" {
+		t.Errorf("Language = %q, want en-us", got)
+	}
+}
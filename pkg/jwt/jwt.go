@@ -0,0 +1,204 @@
+// Package jwt 签发和校验 HS256 签名的 JSON Web Token，用于无法保存 cookie 的 API 客户端认证
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// 令牌类型，访问令牌用于调用接口，刷新令牌只能用于换取新的访问令牌
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+)
+
+var (
+	// ErrInvalid 令牌格式错误、签名算法不是 HS256 或签名不匹配
+	ErrInvalid = errors.New("jwt: invalid token")
+	// ErrExpired 令牌已过期
+	ErrExpired = errors.New("jwt: token expired")
+	// ErrTypeMismatch 令牌类型不匹配，如用刷新令牌调用接口
+	ErrTypeMismatch = errors.New("jwt: token type mismatch")
+)
+
+// header 固定的 JOSE 头，校验时拒绝其他算法(包括 none)
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims 令牌中携带的信息，字段名遵循 RFC 7519
+type Claims struct {
+	ID        string `json:"jti"`
+	Issuer    string `json:"iss,omitempty"`
+	Subject   string `json:"sub"`       // 用户ID
+	Username  string `json:"username"`  // 用户名，避免每次请求查询用户表
+	Type      string `json:"typ"`       // TypeAccess 或 TypeRefresh
+	IssuedAt  int64  `json:"iat"`       // 签发时间(unix 秒)
+	ExpiresAt int64  `json:"exp"`       // 过期时间(unix 秒)
+	AuthTime  int64  `json:"auth_time"` // 用户验证密码的时间(unix 秒)，刷新令牌换取新令牌时保持不变
+	Family    string `json:"fam"`       // 令牌族，一次登录及之后刷新得到的令牌属于同一族，用于吊销和检测刷新令牌重用
+}
+
+// Pair 登录或刷新时签发的一对令牌
+type Pair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	RefreshID        string // 刷新令牌的 jti
+	Family           string
+}
+
+// Manager 签发和校验令牌
+//
+// 访问令牌不在服务端保存，有效期内无法单独吊销，有效期应尽量短；刷新令牌的轮换和吊销见 internal/refreshtoken。
+type Manager struct {
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// New 创建令牌管理器，secret 为 HS256 签名密钥
+func New(secret []byte, issuer string, accessTTL, refreshTTL time.Duration) *Manager {
+	return &Manager{
+		secret:     secret,
+		issuer:     issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}
+}
+
+// Issue 为用户签发访问令牌和刷新令牌，authTime 为用户验证密码的时间
+// family 为空时(登录)创建新的令牌族，刷新时传入原刷新令牌的 Family
+func (m *Manager) Issue(subject, username string, authTime time.Time, family string) (*Pair, error) {
+	now := m.now()
+	if family == "" {
+		id, err := randomID()
+		if err != nil {
+			return nil, err
+		}
+		family = id
+	}
+
+	access, err := m.sign(Claims{
+		Subject:   subject,
+		Username:  username,
+		Type:      TypeAccess,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(m.accessTTL).Unix(),
+		AuthTime:  authTime.Unix(),
+		Family:    family,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshID, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := m.sign(Claims{
+		ID:        refreshID,
+		Subject:   subject,
+		Username:  username,
+		Type:      TypeRefresh,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(m.refreshTTL).Unix(),
+		AuthTime:  authTime.Unix(),
+		Family:    family,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pair{
+		AccessToken:      access,
+		AccessExpiresAt:  now.Add(m.accessTTL),
+		RefreshToken:     refresh,
+		RefreshExpiresAt: now.Add(m.refreshTTL),
+		RefreshID:        refreshID,
+		Family:           family,
+	}, nil
+}
+
+// Parse 校验令牌的签名、类型和过期时间
+func (m *Manager) Parse(token, tokenType string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != header {
+		return nil, ErrInvalid
+	}
+
+	if !hmac.Equal([]byte(parts[2]), []byte(m.signature(parts[0]+"."+parts[1]))) {
+		return nil, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" || claims.Subject == "" {
+		return nil, ErrInvalid
+	}
+	if m.issuer != "" && claims.Issuer != m.issuer {
+		return nil, ErrInvalid
+	}
+
+	if claims.Type != tokenType {
+		return nil, ErrTypeMismatch
+	}
+	if m.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+
+	return &claims, nil
+}
+
+// RefreshTTL 刷新令牌的有效期
+func (m *Manager) RefreshTTL() time.Duration {
+	return m.refreshTTL
+}
+
+// sign 签名，claims.ID 为空时生成随机的 jti
+func (m *Manager) sign(claims Claims) (string, error) {
+	if claims.ID == "" {
+		id, err := randomID()
+		if err != nil {
+			return "", err
+		}
+		claims.ID = id
+	}
+	claims.Issuer = m.issuer
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + m.signature(unsigned), nil
+}
+
+func randomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (m *Manager) signature(unsigned string) string {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestManager() *Manager {
+	return New([]byte("test-secret"), "gin-app", 15*time.Minute, 7*24*time.Hour)
+}
+
+func TestIssueAndParse(t *testing.T) {
+	m := newTestManager()
+	authTime := time.Now().Add(-time.Minute)
+
+	pair, err := m.Issue("42", "alice", authTime, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := m.Parse(pair.AccessToken, TypeAccess)
+	if err != nil {
+		t.Fatalf("parse access token: %v", err)
+	}
+	if claims.Subject != "42" || claims.Username != "alice" || claims.Issuer != "gin-app" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+	if claims.AuthTime != authTime.Unix() {
+		t.Fatalf("auth_time = %d, want %d", claims.AuthTime, authTime.Unix())
+	}
+
+	refresh, err := m.Parse(pair.RefreshToken, TypeRefresh)
+	if err != nil {
+		t.Fatalf("parse refresh token: %v", err)
+	}
+	if refresh.ID != pair.RefreshID || refresh.Family != pair.Family || claims.Family != pair.Family || pair.Family == "" {
+		t.Fatalf("refresh jti/family mismatch: pair %+v, claims %+v", pair, refresh)
+	}
+}
+
+func TestIssueKeepsFamily(t *testing.T) {
+	m := newTestManager()
+	first, _ := m.Issue("42", "alice", time.Now(), "")
+	second, err := m.Issue("42", "alice", time.Now(), first.Family)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Family != first.Family || second.RefreshID == first.RefreshID {
+		t.Fatalf("want same family and new jti, got %+v and %+v", first, second)
+	}
+}
+
+func TestParseTypeMismatch(t *testing.T) {
+	m := newTestManager()
+	pair, _ := m.Issue("42", "alice", time.Now(), "")
+
+	if _, err := m.Parse(pair.RefreshToken, TypeAccess); !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("want ErrTypeMismatch, got %v", err)
+	}
+}
+
+func TestParseExpired(t *testing.T) {
+	m := newTestManager()
+	pair, _ := m.Issue("42", "alice", time.Now(), "")
+
+	m.now = func() time.Time { return time.Now().Add(16 * time.Minute) }
+	if _, err := m.Parse(pair.AccessToken, TypeAccess); !errors.Is(err, ErrExpired) {
+		t.Fatalf("want ErrExpired, got %v", err)
+	}
+}
+
+func TestParseRejectsTampered(t *testing.T) {
+	m := newTestManager()
+	pair, _ := m.Issue("42", "alice", time.Now(), "")
+	parts := strings.Split(pair.AccessToken, ".")
+
+	cases := map[string]string{
+		"other secret": func() string {
+			p, _ := New([]byte("other"), "gin-app", time.Minute, time.Minute).Issue("42", "alice", time.Now(), "")
+			return p.AccessToken
+		}(),
+		"alg none":  "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0." + parts[1] + ".",
+		"payload":   parts[0] + "." + parts[1] + "x." + parts[2],
+		"malformed": "abc",
+	}
+	for name, token := range cases {
+		if _, err := m.Parse(token, TypeAccess); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: want ErrInvalid, got %v", name, err)
+		}
+	}
+
+	other := New([]byte("test-secret"), "other-issuer", time.Minute, time.Minute)
+	if _, err := other.Parse(pair.AccessToken, TypeAccess); !errors.Is(err, ErrInvalid) {
+		t.Errorf("issuer mismatch: want ErrInvalid, got %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+// Package lifecycle 管理进程退出时各组件的关闭顺序和时限
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultTimeout 全部关闭钩子的默认总时限
+	DefaultTimeout = 30 * time.Second
+	// DefaultHookTimeout 单个关闭钩子的默认时限
+	DefaultHookTimeout = 10 * time.Second
+)
+
+type option struct {
+	timeout     time.Duration
+	hookTimeout time.Duration
+}
+
+// Option 关闭配置项
+type Option func(*option)
+
+// WithTimeout 设置全部关闭钩子的总时限，超过后剩余的钩子不再执行
+func WithTimeout(d time.Duration) Option {
+	return func(opt *option) {
+		if d > 0 {
+			opt.timeout = d
+		}
+	}
+}
+
+// WithHookTimeout 设置单个关闭钩子的默认时限，超时后不再等待该钩子，继续执行下一个
+func WithHookTimeout(d time.Duration) Option {
+	return func(opt *option) {
+		if d > 0 {
+			opt.hookTimeout = d
+		}
+	}
+}
+
+type hook struct {
+	name    string
+	timeout time.Duration
+	stop    func(ctx context.Context) error
+}
+
+// Manager 进程退出时按注册的相反顺序执行关闭钩子，与 defer 的顺序一致:
+// 先注册的数据库、Redis 最后关闭，最后注册的 HTTP 服务最先停止接收请求
+type Manager struct {
+	opt    *option
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	hooks []hook
+	done  bool
+}
+
+func New(logger *zap.Logger, options ...Option) *Manager {
+	opt := &option{timeout: DefaultTimeout, hookTimeout: DefaultHookTimeout}
+	for _, f := range options {
+		f(opt)
+	}
+	return &Manager{opt: opt, logger: logger}
+}
+
+// Register 注册关闭钩子，stop 应在 ctx 结束前返回
+func (m *Manager) Register(name string, stop func(ctx context.Context) error) {
+	m.RegisterWithTimeout(name, 0, stop)
+}
+
+// RegisterWithTimeout 注册关闭钩子并单独设置时限，如需要等待请求处理完的 HTTP 服务；timeout 为 0 时使用默认时限
+func (m *Manager) RegisterWithTimeout(name string, timeout time.Duration, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, timeout: timeout, stop: stop})
+}
+
+// Func 把不接受 context、不返回错误的关闭函数(如后台任务的 Stop)转换为关闭钩子
+func Func(stop func()) func(ctx context.Context) error {
+	return func(context.Context) error {
+		stop()
+		return nil
+	}
+}
+
+// Closer 把 Close() error 形式的关闭函数转换为关闭钩子
+func Closer(close func() error) func(ctx context.Context) error {
+	return func(context.Context) error {
+		return close()
+	}
+}
+
+// Shutdown 按注册的相反顺序执行全部关闭钩子，返回所有钩子的错误；只执行一次，再次调用直接返回
+//
+// 钩子超时后不再等待，继续执行下一个；超过总时限后剩余的钩子不再执行。
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.done {
+		m.mu.Unlock()
+		return nil
+	}
+	m.done = true
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, m.opt.timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := m.run(ctx, hooks[i]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hooks[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) run(ctx context.Context, h hook) error {
+	if err := ctx.Err(); err != nil {
+		m.logger.Error("shutdown hook skipped", zap.String("hook", h.name), zap.Error(err))
+		return fmt.Errorf("skipped: %w", err)
+	}
+
+	timeout := h.timeout
+	if timeout <= 0 {
+		timeout = m.opt.hookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- h.stop(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	fields := []zap.Field{zap.String("hook", h.name), zap.Duration("cost", time.Since(start))}
+	if err != nil {
+		m.logger.Error("shutdown hook failed", append(fields, zap.Error(err))...)
+		return err
+	}
+	m.logger.Info("shutdown hook finished", fields...)
+	return nil
+}
@@ -0,0 +1,80 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestShutdownOrder(t *testing.T) {
+	m := New(zap.NewNop())
+	var order []string
+	for _, name := range []string{"db", "redis", "worker", "http"} {
+		name := name
+		m.Register(name, Func(func() { order = append(order, name) }))
+	}
+
+	if err := m.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"http", "worker", "redis", "db"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	// 只执行一次
+	if err := m.Shutdown(context.Background()); err != nil || len(order) != 4 {
+		t.Errorf("second shutdown ran hooks again: %v", order)
+	}
+}
+
+func TestShutdownErrors(t *testing.T) {
+	m := New(zap.NewNop(), WithHookTimeout(20*time.Millisecond))
+	closed := false
+	m.Register("db", Closer(func() error { closed = true; return nil }))
+	m.Register("redis", Closer(func() error { return errors.New("connection reset") }))
+	m.Register("worker", func(ctx context.Context) error { panic("boom") })
+	m.Register("http", func(ctx context.Context) error {
+		time.Sleep(time.Second) // 不理会 ctx 的钩子也不会阻塞后续钩子
+		return nil
+	})
+
+	start := time.Now()
+	err := m.Shutdown(context.Background())
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("shutdown waited for a timed out hook")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded", err)
+	}
+	for _, want := range []string{"http: context deadline exceeded", "worker: panic: boom", "redis: connection reset"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want %q", err, want)
+		}
+	}
+	if !closed {
+		t.Error("hook after failures was not run")
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	m := New(zap.NewNop(), WithTimeout(30*time.Millisecond), WithHookTimeout(time.Second))
+	ran := false
+	m.Register("db", Func(func() { ran = true }))
+	m.Register("http", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := m.Shutdown(context.Background())
+	if ran {
+		t.Error("hook ran after the overall deadline")
+	}
+	if err == nil || !strings.Contains(err.Error(), "db: skipped") {
+		t.Errorf("err = %v, want db skipped", err)
+	}
+}
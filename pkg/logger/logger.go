@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -32,6 +33,18 @@ type option struct {
 	file           io.Writer         // 日志输出目标
 	timeLayout     string            // 时间格式
 	disableConsole bool              // 是否禁用控制台输出
+
+	async         bool          // 是否异步缓冲写入日志文件
+	bufferSize    int           // 异步缓冲区大小(字节)，0 表示使用 zap 默认值
+	flushInterval time.Duration // 异步缓冲区刷新间隔，0 表示使用 zap 默认值
+
+	modules map[string]zapcore.Level // 按模块覆盖的日志级别
+	sinks   []zapcore.WriteSyncer    // 额外的日志外发目标(syslog、Loki 等)
+	rings   []*Ring                  // 内存环形缓冲
+
+	sampling   bool // 是否开启采样
+	initial    int  // 每秒同级别同消息的前 initial 条全部输出
+	thereafter int  // 之后每 thereafter 条输出 1 条
 }
 
 // WithDebugLevel only greater than 'level' will output
@@ -88,6 +101,24 @@ func WithFileP(file string) Option {
 
 // WithFileRotationP write log to some file with rotation
 func WithFileRotationP(file string, maxSize, maxAge int) Option {
+	return WithFileRotation(file, Rotation{
+		MaxSize:    maxSize,
+		MaxAge:     maxAge,
+		MaxBackups: 300,
+		Compress:   true,
+	})
+}
+
+// Rotation 日志文件轮转配置
+type Rotation struct {
+	MaxSize    int  // 单个文件最大尺寸，单位 M
+	MaxAge     int  // 备份文件最长保留天数，0 表示不按时间清理
+	MaxBackups int  // 最多保留的备份文件数，0 表示不按数量清理
+	Compress   bool // 是否 gzip 压缩备份文件
+}
+
+// WithFileRotation write log to some file with rotation
+func WithFileRotation(file string, rotation Rotation) Option {
 	dir := filepath.Dir(file)
 	if err := os.MkdirAll(dir, 0766); err != nil {
 		panic(err)
@@ -95,16 +126,53 @@ func WithFileRotationP(file string, maxSize, maxAge int) Option {
 
 	return func(opt *option) {
 		opt.file = &lumberjack.Logger{ // concurrent-safed
-			Filename:   file,    // 文件路径
-			MaxSize:    maxSize, // 单个文件最大尺寸，默认单位 M
-			MaxBackups: 300,     // 最多保留 300 个备份
-			MaxAge:     maxAge,  // 最大时间，默认单位 day
-			LocalTime:  true,    // 使用本地时间
-			Compress:   true,    // 是否压缩 disabled by default
+			Filename:   file,                // 文件路径
+			MaxSize:    rotation.MaxSize,    // 单个文件最大尺寸，默认单位 M
+			MaxBackups: rotation.MaxBackups, // 最多保留的备份数
+			MaxAge:     rotation.MaxAge,     // 最大时间，默认单位 day
+			LocalTime:  true,                // 使用本地时间
+			Compress:   rotation.Compress,   // 是否压缩 disabled by default
 		}
 	}
 }
 
+// RotationFromConfig 从日志配置中读取轮转配置
+func RotationFromConfig(config config.LogConfig) Rotation {
+	return Rotation{
+		MaxSize:    config.MaxSize,
+		MaxAge:     config.MaxAge,
+		MaxBackups: config.MaxBackups,
+		Compress:   config.Compress,
+	}
+}
+
+// WithAsyncBuffer 日志文件改为异步缓冲写入，缓冲区满或每隔 flushInterval 刷盘一次
+//
+// 进程退出前必须调用 logger.Sync() 刷新缓冲区，否则最后一批日志会丢失。
+func WithAsyncBuffer(size int, flushInterval time.Duration) Option {
+	return func(opt *option) {
+		opt.async = true
+		opt.bufferSize = size
+		opt.flushInterval = flushInterval
+	}
+}
+
+// WithSampling 开启采样，每秒内同级别同消息的日志前 initial 条全部输出，之后每 thereafter 条输出 1 条
+func WithSampling(initial, thereafter int) Option {
+	return func(opt *option) {
+		opt.sampling = true
+		opt.initial = initial
+		opt.thereafter = thereafter
+	}
+}
+
+// WithModuleLevel 设置模块的日志级别，对 Module(logger, name) 得到的子日志记录器生效
+func WithModuleLevel(name string, level zapcore.Level) Option {
+	return func(opt *option) {
+		opt.modules[name] = level
+	}
+}
+
 // WithTimeLayout custom time format
 func WithTimeLayout(timeLayout string) Option {
 	return func(opt *option) {
@@ -119,7 +187,20 @@ func WithDisableConsole() Option {
 	}
 }
 
+// Init 创建日志记录器并设置为全局日志记录器
 func Init(config *config.Config, opts ...Option) (*zap.Logger, error) {
+	logger, err := New(config, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	globalLogger = logger
+	return logger, nil
+}
+
+// New 创建日志记录器，不会修改全局日志记录器；用于慢日志等独立的日志流
+// log.sinks 中的外发目标不在这里创建，由调用方通过 OpenSinks 创建一次，用 Sinks.Option 传给各个日志记录器
+func New(config *config.Config, opts ...Option) (*zap.Logger, error) {
 	switch config.Log.Level {
 	case "debug":
 		opts = append(opts, WithDebugLevel())
@@ -133,11 +214,38 @@ func Init(config *config.Config, opts ...Option) (*zap.Logger, error) {
 		opts = append(opts, WithInfoLevel())
 	}
 
-	opt := &option{level: DefaultLevel, fields: make(map[string]string)}
+	// 配置文件中的异步、采样设置作为默认值，调用方传入的 opts 可以覆盖
+	if config.Log.Async.Enabled {
+		opts = append([]Option{WithAsyncBuffer(
+			config.Log.Async.BufferSize,
+			time.Duration(config.Log.Async.FlushInterval)*time.Millisecond,
+		)}, opts...)
+	}
+	if config.Log.Sampling.Enabled {
+		opts = append([]Option{WithSampling(config.Log.Sampling.Initial, config.Log.Sampling.Thereafter)}, opts...)
+	}
+	for name, level := range config.Log.Levels {
+		lvl, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level %q for module %s: %w", level, name, err)
+		}
+		opts = append([]Option{WithModuleLevel(name, lvl)}, opts...)
+	}
+
+	opt := &option{level: DefaultLevel, fields: make(map[string]string), modules: make(map[string]zapcore.Level)}
 	for _, f := range opts {
 		f(opt)
 	}
 
+	// 底层 core 按所有级别中最低的一级输出，再由外层 moduleLevelCore 按模块过滤，
+	// 这样模块既可以比全局级别更安静，也可以更详细(如单独打开某个模块的 debug)
+	rootLevel := opt.level
+	for _, lvl := range opt.modules {
+		if lvl < opt.level {
+			opt.level = lvl
+		}
+	}
+
 	timeLayout := DefaultTimeLayout
 	if opt.timeLayout != "" {
 		timeLayout = opt.timeLayout
@@ -207,9 +315,20 @@ func Init(config *config.Config, opts ...Option) (*zap.Logger, error) {
 
 	// 文件日志
 	if opt.file != nil {
+		// 将文件写入器转换为zap兼容的同步器(普通文件或轮转文件写入器)
+		fileSyncer := zapcore.AddSync(opt.file)
+		if opt.async {
+			// 异步缓冲写入: 请求路径上只写内存，由后台定时刷盘，Sync 时强制刷新
+			fileSyncer = &zapcore.BufferedWriteSyncer{
+				WS:            fileSyncer,
+				Size:          opt.bufferSize,
+				FlushInterval: opt.flushInterval,
+			}
+		}
+
 		core = zapcore.NewTee(core,
 			zapcore.NewCore(jsonEncoder,
-				zapcore.AddSync(opt.file), // 将文件写入器转换为zap兼容的同步器(普通文件或轮转文件写入器)
+				fileSyncer,
 
 				// 保留的日志级别：级别 >= 配置级别
 				zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
@@ -219,6 +338,29 @@ func Init(config *config.Config, opts ...Option) (*zap.Logger, error) {
 		)
 	}
 
+	// 外发日志，级别与文件日志一致
+	for _, sink := range opt.sinks {
+		core = zapcore.NewTee(core,
+			zapcore.NewCore(jsonEncoder, sink, zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+				return lvl >= opt.level
+			})),
+		)
+	}
+
+	// 内存环形缓冲，级别与文件日志一致
+	for _, ring := range opt.rings {
+		core = zapcore.NewTee(core, newRingCore(jsonEncoder, ring, zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return lvl >= opt.level
+		})))
+	}
+
+	// 采样: 高并发时同一条日志(如每个请求的 trace)只输出一部分，降低日志写入开销
+	if opt.sampling {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, opt.initial, opt.thereafter)
+	}
+
+	core = &moduleLevelCore{Core: core, level: rootLevel, modules: opt.modules}
+
 	// 创建日志记录器
 	logger := zap.New(core,
 		zap.AddCaller(),         // 自动记录每条日志的调用者信息（内容包括文件名和行号）
@@ -230,10 +372,46 @@ func Init(config *config.Config, opts ...Option) (*zap.Logger, error) {
 		logger = logger.WithOptions(zap.Fields(zapcore.Field{Key: key, Type: zapcore.StringType, String: value}))
 	}
 
-	globalLogger = logger
 	return logger, nil
 }
 
+// Module 返回指定模块的子日志记录器，日志级别取配置 log.levels 中该模块的级别，未配置时与 l 相同
+func Module(l *zap.Logger, name string) *zap.Logger {
+	return l.Named(name).WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		mc, ok := core.(*moduleLevelCore)
+		if !ok {
+			return core
+		}
+		level, ok := mc.modules[name]
+		if !ok {
+			return core
+		}
+		return &moduleLevelCore{Core: mc.Core, level: level, modules: mc.modules}
+	}))
+}
+
+// moduleLevelCore 按模块级别过滤日志
+type moduleLevelCore struct {
+	zapcore.Core
+	level   zapcore.Level
+	modules map[string]zapcore.Level
+}
+
+func (c *moduleLevelCore) Enabled(lvl zapcore.Level) bool {
+	return lvl >= c.level && c.Core.Enabled(lvl)
+}
+
+func (c *moduleLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &moduleLevelCore{Core: c.Core.With(fields), level: c.level, modules: c.modules}
+}
+
+func (c *moduleLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.level {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
 func GetLogger() *zap.Logger {
 	if globalLogger == nil {
 		logger, _ := zap.NewDevelopment()
@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RingEntry 内存环形缓冲中的一条日志，Data 为与文件日志相同的 JSON，不带换行
+type RingEntry struct {
+	Level zapcore.Level
+	Data  []byte
+}
+
+// Ring 在内存中保留最近的 size 条日志，并推送给订阅者，用于不登录服务器时查看日志
+type Ring struct {
+	level zapcore.Level
+
+	mu      sync.Mutex
+	entries []RingEntry
+	next    int  // 下一条写入的位置
+	full    bool // 缓冲是否已写满一轮
+	subs    map[chan RingEntry]struct{}
+}
+
+// DefaultRingSize 环形缓冲默认保留的日志条数
+const DefaultRingSize = 1000
+
+// NewRing 创建保留最近 size 条、级别不低于 level 的日志的缓冲，size <= 0 时使用 DefaultRingSize
+func NewRing(size int, level zapcore.Level) *Ring {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+	return &Ring{
+		level:   level,
+		entries: make([]RingEntry, size),
+		subs:    make(map[chan RingEntry]struct{}),
+	}
+}
+
+// WithRing 日志同时写入内存环形缓冲，级别取日志级别和 ring 级别中较高的一个
+func WithRing(ring *Ring) Option {
+	return func(opt *option) {
+		opt.rings = append(opt.rings, ring)
+	}
+}
+
+func (r *Ring) add(entry RingEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+
+	// 订阅者接收不及时时丢弃，不能阻塞写日志的业务代码
+	for ch := range r.subs {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
+
+// Recent 最近的 n 条日志，按时间从旧到新排列；n <= 0 时返回全部
+func (r *Ring) Recent(n int) []RingEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recent(n)
+}
+
+func (r *Ring) recent(n int) []RingEntry {
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+
+	res := make([]RingEntry, 0, n)
+	for i := r.next - n; i < r.next; i++ {
+		res = append(res, r.entries[(i+len(r.entries))%len(r.entries)])
+	}
+	return res
+}
+
+// Subscribe 返回最近的 n 条日志和之后新写入日志的 channel，两者之间不会遗漏或重复；
+// buffer 为 channel 的容量，接收不及时的日志会被丢弃。使用完后调用 cancel 取消订阅
+func (r *Ring) Subscribe(n, buffer int) (recent []RingEntry, entries <-chan RingEntry, cancel func()) {
+	ch := make(chan RingEntry, buffer)
+
+	r.mu.Lock()
+	recent = r.recent(n)
+	r.subs[ch] = struct{}{}
+	r.mu.Unlock()
+
+	var once sync.Once
+	return recent, ch, func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.subs, ch)
+			r.mu.Unlock()
+		})
+	}
+}
+
+// ringCore 把日志编码为 JSON 后写入 Ring
+type ringCore struct {
+	zapcore.LevelEnabler
+	enc  zapcore.Encoder
+	ring *Ring
+}
+
+func newRingCore(enc zapcore.Encoder, ring *Ring, enabler zapcore.LevelEnabler) zapcore.Core {
+	return &ringCore{
+		LevelEnabler: zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return lvl >= ring.level && enabler.Enabled(lvl)
+		}),
+		enc:  enc.Clone(),
+		ring: ring,
+	}
+}
+
+func (c *ringCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &ringCore{LevelEnabler: c.LevelEnabler, enc: enc, ring: c.ring}
+}
+
+func (c *ringCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *ringCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	data := buf.Bytes()
+	if n := len(data); n > 0 && data[n-1] == '\n' {
+		data = data[:n-1]
+	}
+	c.ring.add(RingEntry{Level: ent.Level, Data: append([]byte(nil), data...)})
+	return nil
+}
+
+func (c *ringCore) Sync() error {
+	return nil
+}
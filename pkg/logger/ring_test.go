@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"gin-app-start/internal/config"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRingRecent(t *testing.T) {
+	ring := NewRing(3, zapcore.DebugLevel)
+	if got := ring.Recent(0); len(got) != 0 {
+		t.Fatalf("empty ring returned %d entries", len(got))
+	}
+
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		ring.add(RingEntry{Data: []byte(msg)})
+	}
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "bcd"},
+		{2, "cd"},
+		{10, "bcd"},
+	}
+	for _, tt := range tests {
+		var got string
+		for _, e := range ring.Recent(tt.n) {
+			got += string(e.Data)
+		}
+		if got != tt.want {
+			t.Errorf("Recent(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRingSubscribe(t *testing.T) {
+	ring := NewRing(10, zapcore.DebugLevel)
+	ring.add(RingEntry{Data: []byte("old")})
+
+	recent, entries, cancel := ring.Subscribe(10, 1)
+	if len(recent) != 1 || string(recent[0].Data) != "old" {
+		t.Fatalf("recent = %v", recent)
+	}
+
+	// channel 满时丢弃，不阻塞写入
+	ring.add(RingEntry{Data: []byte("new")})
+	ring.add(RingEntry{Data: []byte("dropped")})
+	if e := <-entries; string(e.Data) != "new" {
+		t.Errorf("received %q, want new", e.Data)
+	}
+
+	cancel()
+	cancel()
+	ring.add(RingEntry{Data: []byte("after cancel")})
+	select {
+	case e := <-entries:
+		t.Errorf("received %q after cancel", e.Data)
+	default:
+	}
+}
+
+func TestWithRing(t *testing.T) {
+	ring := NewRing(10, zapcore.WarnLevel)
+	l, err := New(&config.Config{Log: config.LogConfig{Level: "debug"}}, WithDisableConsole(), WithRing(ring))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.With(zap.String("trace_id", "t1")).Warn("disk almost full")
+	l.Info("ignored")
+
+	entries := ring.Recent(0)
+	if len(entries) != 1 {
+		t.Fatalf("ring has %d entries, want 1", len(entries))
+	}
+	data := string(entries[0].Data)
+	if entries[0].Level != zapcore.WarnLevel || !strings.Contains(data, `"msg":"disk almost full"`) || !strings.Contains(data, `"trace_id":"t1"`) {
+		t.Errorf("entry = %s", data)
+	}
+	if strings.HasSuffix(data, "\n") {
+		t.Error("entry ends with a newline")
+	}
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gin-app-start/internal/config"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 日志外发目标类型
+const (
+	SinkSyslog = "syslog"
+	SinkLoki   = "loki"
+)
+
+// WithSink 增加一个日志输出目标(syslog、Loki 等)，与文件日志使用相同的 JSON 格式和级别
+func WithSink(ws zapcore.WriteSyncer) Option {
+	return func(opt *option) {
+		opt.sinks = append(opt.sinks, ws)
+	}
+}
+
+// Sinks 按配置创建的日志外发目标，由进程内所有日志记录器共用，避免每个记录器各自连接 syslog、各自启动 Loki 推送协程
+type Sinks struct {
+	sinks []zapcore.WriteSyncer
+}
+
+// OpenSinks 创建配置中启用的全部日志外发目标，进程退出前需调用 Close
+func OpenSinks(configs []config.LogSinkConfig) (*Sinks, error) {
+	s := &Sinks{}
+	for _, cfg := range configs {
+		if !cfg.Enabled {
+			continue
+		}
+		sink, err := newSink(cfg)
+		if err != nil {
+			_ = s.Close()
+			return nil, fmt.Errorf("failed to create %s log sink: %w", cfg.Type, err)
+		}
+		s.sinks = append(s.sinks, sink)
+	}
+	return s, nil
+}
+
+// Option 把全部外发目标加入日志记录器，s 为 nil 时不做处理
+func (s *Sinks) Option() Option {
+	return func(opt *option) {
+		if s != nil {
+			opt.sinks = append(opt.sinks, s.sinks...)
+		}
+	}
+}
+
+// Close 推送缓冲中的日志，停止后台协程并断开连接；需在所有日志记录器 Sync 之后调用
+func (s *Sinks) Close() error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	for _, sink := range s.sinks {
+		if c, ok := sink.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newSink 根据配置创建日志外发目标
+func newSink(cfg config.LogSinkConfig) (zapcore.WriteSyncer, error) {
+	switch cfg.Type {
+	case SinkSyslog:
+		return newSyslogSink(cfg.Network, cfg.Address, cfg.Tag)
+	case SinkLoki:
+		if cfg.Address == "" {
+			return nil, fmt.Errorf("loki sink requires address")
+		}
+		return newLokiSink(lokiConfig{
+			url:           cfg.Address,
+			labels:        cfg.Labels,
+			batchSize:     cfg.BatchSize,
+			flushInterval: time.Duration(cfg.FlushInterval) * time.Millisecond,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported log sink type %q", cfg.Type)
+	}
+}
@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLokiBatchSize     = 500
+	defaultLokiFlushInterval = time.Second
+
+	// lokiMaxPendingBatches Loki 不可用时最多缓存的批次数，超出后丢弃最旧的日志，避免内存无限增长
+	lokiMaxPendingBatches = 10
+)
+
+type lokiConfig struct {
+	url           string            // push 接口地址，如 http://loki:3100/loki/api/v1/push
+	labels        map[string]string // 日志流标签，如 {app: gin-app, env: prod}
+	batchSize     int
+	flushInterval time.Duration
+}
+
+// lokiSink 通过 Loki push API 批量推送日志
+//
+// Write 只把日志追加到内存缓冲区，由后台协程按批次或时间间隔推送，不阻塞请求。
+type lokiSink struct {
+	cfg    lokiConfig
+	client *http.Client
+
+	mu      sync.Mutex
+	entries [][2]string // [纳秒时间戳, 日志行]
+	pushMu  sync.Mutex  // 保证批次按顺序推送
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newLokiSink(cfg lokiConfig) *lokiSink {
+	if cfg.batchSize <= 0 {
+		cfg.batchSize = defaultLokiBatchSize
+	}
+	if cfg.flushInterval <= 0 {
+		cfg.flushInterval = defaultLokiFlushInterval
+	}
+
+	s := &lokiSink{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 5 * time.Second},
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+func (s *lokiSink) loop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			_ = s.Sync()
+		}
+	}
+}
+
+// Close 停止后台推送协程，并推送缓冲区中剩余的日志
+func (s *lokiSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+	return s.Sync()
+}
+
+// Write 实现 zapcore.WriteSyncer，p 会被 zap 复用，需要拷贝
+func (s *lokiSink) Write(p []byte) (int, error) {
+	line := string(bytes.TrimRight(p, "\n"))
+	ts := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	s.mu.Lock()
+	s.entries = append(s.entries, [2]string{ts, line})
+	if max := s.cfg.batchSize * lokiMaxPendingBatches; len(s.entries) > max {
+		s.entries = s.entries[len(s.entries)-max:]
+	}
+	full := len(s.entries) >= s.cfg.batchSize
+	s.mu.Unlock()
+
+	if full {
+		go s.Sync()
+	}
+	return len(p), nil
+}
+
+// Sync 推送缓冲区中的全部日志，推送失败时日志保留在缓冲区等待下次重试
+func (s *lokiSink) Sync() error {
+	s.pushMu.Lock()
+	defer s.pushMu.Unlock()
+
+	s.mu.Lock()
+	entries := s.entries
+	s.entries = nil
+	s.mu.Unlock()
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := s.push(entries); err != nil {
+		fmt.Fprintf(os.Stderr, "loki sink: push %d entries failed: %v\n", len(entries), err)
+
+		// 放回缓冲区头部等待重试，超出上限的由 Write 丢弃
+		s.mu.Lock()
+		s.entries = append(entries, s.entries...)
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (s *lokiSink) push(entries [][2]string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"streams": []map[string]interface{}{{
+			"stream": s.cfg.labels,
+			"values": entries,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.cfg.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
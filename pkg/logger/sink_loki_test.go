@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLokiSinkClose(t *testing.T) {
+	var pushed atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Streams []struct {
+				Values [][2]string `json:"values"`
+			} `json:"streams"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err == nil && len(body.Streams) == 1 {
+			pushed.Add(int32(len(body.Streams[0].Values)))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	// 推送间隔远大于测试时长，日志只会在 Close 时推送
+	sink := newLokiSink(lokiConfig{url: srv.URL, flushInterval: time.Hour})
+	sink.Write([]byte(`{"msg":"a"}` + "\n"))
+	sink.Write([]byte(`{"msg":"b"}` + "\n"))
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := pushed.Load(); got != 2 {
+		t.Fatalf("pushed %d entries on close, want 2", got)
+	}
+
+	// 后台协程已退出，重复 Close 不会阻塞
+	select {
+	case <-sink.done:
+	default:
+		t.Fatal("push loop still running after Close")
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
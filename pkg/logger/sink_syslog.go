@@ -0,0 +1,26 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"log/syslog"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogSink syslog 连接，Close 时断开
+type syslogSink struct {
+	*syslog.Writer
+}
+
+// Sync syslog 每条日志直接发送，没有缓冲
+func (syslogSink) Sync() error { return nil }
+
+// newSyslogSink 连接 syslog，network 为空时连接本机 syslog
+func newSyslogSink(network, address, tag string) (zapcore.WriteSyncer, error) {
+	w, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_LOCAL0, tag)
+	if err != nil {
+		return nil, err
+	}
+	return syslogSink{Writer: w}, nil
+}
@@ -0,0 +1,14 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"errors"
+	"runtime"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newSyslogSink(network, address, tag string) (zapcore.WriteSyncer, error) {
+	return nil, errors.New("syslog sink is not supported on " + runtime.GOOS)
+}
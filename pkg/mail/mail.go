@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// Sender 发送邮件
+type Sender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+var _ Sender = (*SMTPSender)(nil)
+
+// SMTPSender 通过 SMTP 发送纯文本邮件，服务器支持时自动使用 STARTTLS
+type SMTPSender struct {
+	addr string
+	from *mail.Address
+	auth smtp.Auth
+}
+
+// NewSMTPSender from 为发件人，可以带显示名称，如 "Gin App <noreply@example.com>"；username 为空时不做认证
+func NewSMTPSender(host string, port int, username, password, from string) (*SMTPSender, error) {
+	addr, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("mail: invalid from address %q: %w", from, err)
+	}
+
+	s := &SMTPSender{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		from: addr,
+	}
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s, nil
+}
+
+// Send net/smtp 不支持 context，ctx 只用于在发送前检查是否已取消
+func (s *SMTPSender) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rcpt, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("mail: invalid recipient %q: %w", to, err)
+	}
+
+	return smtp.SendMail(s.addr, s.auth, s.from.Address, []string{rcpt.Address}, s.message(rcpt, subject, body))
+}
+
+func (s *SMTPSender) message(to *mail.Address, subject, body string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(body)
+	return buf.Bytes()
+}
@@ -0,0 +1,49 @@
+package mask
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Phone 手机号脱敏，保留前 3 位和后 4 位，如 138****1234
+// 不足 8 位时只保留首尾各 1/4，空字符串原样返回
+func Phone(phone string) string {
+	n := utf8.RuneCountInString(phone)
+	if n == 0 {
+		return phone
+	}
+
+	head, tail := 3, 4
+	if n < head+tail+1 {
+		head, tail = n/4, n/4
+	}
+	return keep(phone, head, tail)
+}
+
+// Email 邮箱脱敏，用户名只保留首字符，域名保留，如 j***@example.com
+// 不是合法邮箱格式时按普通字符串只保留首字符
+func Email(email string) string {
+	if email == "" {
+		return email
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return keep(email, 1, 0)
+	}
+	return keep(email[:at], 1, 0) + email[at:]
+}
+
+// keep 保留前 head 个和后 tail 个字符，中间替换为 *，至少替换 3 个以免暴露原始长度过短
+func keep(s string, head, tail int) string {
+	runes := []rune(s)
+	if head+tail >= len(runes) {
+		head, tail = 0, 0
+	}
+
+	stars := len(runes) - head - tail
+	if stars < 3 {
+		stars = 3
+	}
+	return string(runes[:head]) + strings.Repeat("*", stars) + string(runes[len(runes)-tail:])
+}
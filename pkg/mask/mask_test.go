@@ -0,0 +1,31 @@
+package mask
+
+import "testing"
+
+func TestPhone(t *testing.T) {
+	cases := map[string]string{
+		"13800131234":    "138****1234",
+		"+8613800131234": "+86*******1234",
+		"12345":          "1***5",
+		"":               "",
+	}
+	for in, want := range cases {
+		if got := Phone(in); got != want {
+			t.Errorf("Phone(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestEmail(t *testing.T) {
+	cases := map[string]string{
+		"john@example.com": "j***@example.com",
+		"j@example.com":    "***@example.com",
+		"not-an-email":     "n***********",
+		"":                 "",
+	}
+	for in, want := range cases {
+		if got := Email(in); got != want {
+			t.Errorf("Email(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package password
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 密码哈希算法
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+const (
+	DefaultBcryptCost = 12
+
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+	md5HexLen     = 32
+)
+
+// ErrUnknownHash 无法识别的密码哈希格式
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// Argon2Params argon2id 的计算参数，修改后旧哈希在下次登录时按新参数重新计算
+type Argon2Params struct {
+	Time    uint32 // 迭代次数
+	Memory  uint32 // 内存，单位 KiB
+	Threads uint8  // 并行度
+}
+
+// DefaultArgon2Params 参考 OWASP 的推荐值
+var DefaultArgon2Params = Argon2Params{Time: 3, Memory: 64 * 1024, Threads: 2}
+
+// HashOptions 新密码使用的算法和参数，零值字段使用默认值
+type HashOptions struct {
+	Algorithm  string // bcrypt(默认) 或 argon2id
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// Hasher 生成和校验密码哈希
+//
+// 哈希是自描述的，盐值和参数都保存在哈希中：bcrypt 为 $2a$...，argon2id 为 PHC 格式
+// $argon2id$v=19$m=65536,t=3,p=2$<盐值>$<哈希>。旧版本以 md5(密码+盐值) 保存的十六进制哈希仍然可以校验，
+// 校验通过后应按 NeedsRehash 用当前算法重新计算并保存，盐值另存在用户表的 salt 列中。
+type Hasher struct {
+	algorithm string
+	cost      int
+	argon2    Argon2Params
+}
+
+// NewHasher 按 opts 创建密码哈希器，算法或参数无效时返回错误
+func NewHasher(opts HashOptions) (*Hasher, error) {
+	h := &Hasher{
+		algorithm: opts.Algorithm,
+		cost:      opts.BcryptCost,
+		argon2:    opts.Argon2,
+	}
+	if h.algorithm == "" {
+		h.algorithm = AlgorithmBcrypt
+	}
+	if h.cost == 0 {
+		h.cost = DefaultBcryptCost
+	}
+	if h.argon2.Time == 0 {
+		h.argon2.Time = DefaultArgon2Params.Time
+	}
+	if h.argon2.Memory == 0 {
+		h.argon2.Memory = DefaultArgon2Params.Memory
+	}
+	if h.argon2.Threads == 0 {
+		h.argon2.Threads = DefaultArgon2Params.Threads
+	}
+
+	switch h.algorithm {
+	case AlgorithmBcrypt:
+		if h.cost < bcrypt.MinCost || h.cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case AlgorithmArgon2id:
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q, must be bcrypt or argon2id", h.algorithm)
+	}
+	return h, nil
+}
+
+// Algorithm 新密码使用的算法
+func (h *Hasher) Algorithm() string {
+	return h.algorithm
+}
+
+// Hash 用当前算法计算密码哈希；bcrypt 只支持 72 字节以内的密码，超出时返回错误
+func (h *Hasher) Hash(plain string) (string, error) {
+	if h.algorithm == AlgorithmArgon2id {
+		salt := make([]byte, argon2SaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(plain), salt, h.argon2.Time, h.argon2.Memory, h.argon2.Threads, argon2KeyLen)
+		return encodeArgon2(h.argon2, salt, key), nil
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Verify 校验密码是否与哈希一致，legacySalt 为旧版 MD5 哈希的盐值，新哈希忽略
+func (h *Hasher) Verify(plain, hashed, legacySalt string) (bool, error) {
+	switch {
+	case isBcrypt(hashed):
+		err := bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plain))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	case strings.HasPrefix(hashed, "$argon2id$"):
+		params, salt, key, err := decodeArgon2(hashed)
+		if err != nil {
+			return false, err
+		}
+		actual := argon2.IDKey([]byte(plain), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(actual, key) == 1, nil
+	case isLegacyMD5(hashed):
+		sum := md5.Sum([]byte(plain + legacySalt))
+		return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(strings.ToLower(hashed))) == 1, nil
+	}
+	return false, ErrUnknownHash
+}
+
+// NeedsRehash 哈希不是用当前算法和参数计算的，包括旧版 MD5 哈希
+func (h *Hasher) NeedsRehash(hashed string) bool {
+	switch {
+	case isBcrypt(hashed):
+		cost, err := bcrypt.Cost([]byte(hashed))
+		return h.algorithm != AlgorithmBcrypt || err != nil || cost != h.cost
+	case strings.HasPrefix(hashed, "$argon2id$"):
+		params, _, _, err := decodeArgon2(hashed)
+		return h.algorithm != AlgorithmArgon2id || err != nil || params != h.argon2
+	}
+	return true
+}
+
+func isBcrypt(hashed string) bool {
+	return strings.HasPrefix(hashed, "$2a$") || strings.HasPrefix(hashed, "$2b$") || strings.HasPrefix(hashed, "$2y$")
+}
+
+func isLegacyMD5(hashed string) bool {
+	if len(hashed) != md5HexLen {
+		return false
+	}
+	_, err := hex.DecodeString(hashed)
+	return err == nil
+}
+
+func encodeArgon2(params Argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Time, params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+// decodeArgon2 解析 PHC 格式的 argon2id 哈希
+func decodeArgon2(hashed string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %q", parts[2])
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2 params %q: %w", parts[3], err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2 salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, fmt.Errorf("invalid argon2 hash: %w", err)
+	}
+	if len(key) == 0 {
+		return params, nil, nil, ErrUnknownHash
+	}
+	return params, salt, key, nil
+}
@@ -0,0 +1,77 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHIBPEndpoint Have I Been Pwned 的密码范围查询接口
+const DefaultHIBPEndpoint = "https://api.pwnedpasswords.com/range/"
+
+// HIBPChecker 基于 Have I Been Pwned 的 k-anonymity 接口查询泄露次数
+//
+// 只发送密码 SHA-1 的前 5 位，接口返回该前缀下所有哈希后缀及出现次数，在本地比对，
+// 密码和完整哈希都不会离开本机。
+type HIBPChecker struct {
+	endpoint string
+	client   *http.Client
+}
+
+func NewHIBPChecker(endpoint string, timeout time.Duration) *HIBPChecker {
+	if endpoint == "" {
+		endpoint = DefaultHIBPEndpoint
+	}
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+
+	return &HIBPChecker{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (h *HIBPChecker) Count(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.endpoint+prefix, nil)
+	if err != nil {
+		return 0, err
+	}
+	// 填充响应，避免通过响应大小推断查询的前缀
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("hibp range query failed: %s", resp.Status)
+	}
+
+	// 每行格式为 SUFFIX:COUNT，填充行的次数为 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		candidate, count, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(candidate, suffix) {
+			continue
+		}
+		return strconv.Atoi(count)
+	}
+	return 0, scanner.Err()
+}
@@ -0,0 +1,126 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Rule 密码策略中的一条规则，校验失败时用于定位具体原因
+type Rule string
+
+const (
+	RuleMinLength  Rule = "min_length" // 长度不足
+	RuleUpper      Rule = "upper"      // 缺少大写字母
+	RuleLower      Rule = "lower"      // 缺少小写字母
+	RuleDigit      Rule = "digit"      // 缺少数字
+	RuleSymbol     Rule = "symbol"     // 缺少特殊字符
+	RuleDictionary Rule = "dictionary" // 常见弱密码或包含用户名
+	RuleBreached   Rule = "breached"   // 出现在已泄露的密码库中
+)
+
+// commonPasswords 内置的常见弱密码，配置的字典文件在此基础上追加
+var commonPasswords = []string{
+	"123456", "12345678", "123456789", "1234567890", "111111", "000000",
+	"password", "password1", "password123", "passw0rd", "qwerty", "qwerty123",
+	"abc123", "abcd1234", "iloveyou", "admin", "admin123", "welcome",
+	"letmein", "monkey", "dragon", "football", "baseball", "sunshine",
+	"princess", "1q2w3e4r", "qwertyuiop", "zaq12wsx", "a123456", "woaini1314",
+}
+
+// BreachChecker 查询密码在泄露库中出现的次数
+type BreachChecker interface {
+	Count(ctx context.Context, password string) (int, error)
+}
+
+// Policy 密码策略
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+
+	dictionary map[string]struct{}
+}
+
+// NewPolicy 创建密码策略，dictionaryFile 为弱密码字典文件，每行一个，为空时只使用内置字典
+func NewPolicy(policy Policy, dictionaryFile string) (*Policy, error) {
+	policy.dictionary = make(map[string]struct{}, len(commonPasswords))
+	for _, word := range commonPasswords {
+		policy.dictionary[word] = struct{}{}
+	}
+
+	if dictionaryFile != "" {
+		f, err := os.Open(dictionaryFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if word := strings.ToLower(strings.TrimSpace(scanner.Text())); word != "" {
+				policy.dictionary[word] = struct{}{}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &policy, nil
+}
+
+// Validate 按策略校验密码，返回所有未满足的规则，全部满足时返回空
+// username 不为空时，包含用户名的密码视为字典密码
+func (p *Policy) Validate(password, username string) []Rule {
+	var (
+		rules                       []Rule
+		upper, lower, digit, symbol bool
+	)
+
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if len([]rune(password)) < p.MinLength {
+		rules = append(rules, RuleMinLength)
+	}
+	if p.RequireUpper && !upper {
+		rules = append(rules, RuleUpper)
+	}
+	if p.RequireLower && !lower {
+		rules = append(rules, RuleLower)
+	}
+	if p.RequireDigit && !digit {
+		rules = append(rules, RuleDigit)
+	}
+	if p.RequireSymbol && !symbol {
+		rules = append(rules, RuleSymbol)
+	}
+	if p.inDictionary(password, username) {
+		rules = append(rules, RuleDictionary)
+	}
+
+	return rules
+}
+
+func (p *Policy) inDictionary(password, username string) bool {
+	lowered := strings.ToLower(password)
+	if _, ok := p.dictionary[lowered]; ok {
+		return true
+	}
+	return len(username) >= 3 && strings.Contains(lowered, strings.ToLower(username))
+}
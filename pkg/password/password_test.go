@@ -0,0 +1,121 @@
+package password
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	policy, err := NewPolicy(Policy{MinLength: 8, RequireUpper: true, RequireLower: true, RequireDigit: true}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		password string
+		username string
+		want     []Rule
+	}{
+		{"Tr0ub4dor", "alice", nil},
+		{"short1A", "alice", []Rule{RuleMinLength}},
+		{"alllowercase1", "alice", []Rule{RuleUpper}},
+		{"Password123", "alice", []Rule{RuleDictionary}},
+		{"Alice2024x", "alice", []Rule{RuleDictionary}},
+		{"abc", "", []Rule{RuleMinLength, RuleUpper, RuleDigit}},
+	}
+	for _, tc := range cases {
+		if got := policy.Validate(tc.password, tc.username); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Validate(%q, %q) = %v, want %v", tc.password, tc.username, got, tc.want)
+		}
+	}
+}
+
+func TestHIBPChecker(t *testing.T) {
+	// "password" 的 SHA-1 为 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.Path) != len("/range/5BAA6") {
+			t.Errorf("unexpected path %s, only the 5-char prefix may be sent", r.URL.Path)
+		}
+		if r.URL.Path != "/range/5BAA6" {
+			return
+		}
+		fmt.Fprint(w, "0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\r\n")
+	}))
+	defer srv.Close()
+
+	checker := NewHIBPChecker(srv.URL+"/range", time.Second)
+
+	count, err := checker.Count(context.Background(), "password")
+	if err != nil || count != 3861493 {
+		t.Fatalf("Count(password) = %d, %v", count, err)
+	}
+
+	count, err = checker.Count(context.Background(), "not-in-range")
+	if err != nil || count != 0 {
+		t.Fatalf("Count(not-in-range) = %d, %v", count, err)
+	}
+}
+
+func TestHasher(t *testing.T) {
+	bcryptHasher, err := NewHasher(HashOptions{BcryptCost: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	argonHasher, err := NewHasher(HashOptions{Algorithm: AlgorithmArgon2id, Argon2: Argon2Params{Time: 1, Memory: 64, Threads: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, h := range []*Hasher{bcryptHasher, argonHasher} {
+		hashed, err := h.Hash("Tr0ub4dor")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := h.Verify("Tr0ub4dor", hashed, ""); !ok || err != nil {
+			t.Errorf("%s: Verify(correct) = %v, %v", h.Algorithm(), ok, err)
+		}
+		if ok, err := h.Verify("wrong", hashed, ""); ok || err != nil {
+			t.Errorf("%s: Verify(wrong) = %v, %v", h.Algorithm(), ok, err)
+		}
+		if h.NeedsRehash(hashed) {
+			t.Errorf("%s: fresh hash needs rehash", h.Algorithm())
+		}
+	}
+
+	// 切换算法或调整参数后旧哈希需要重新计算
+	hashed, _ := bcryptHasher.Hash("Tr0ub4dor")
+	if !argonHasher.NeedsRehash(hashed) {
+		t.Error("bcrypt hash should be rehashed by argon2id hasher")
+	}
+	stronger, _ := NewHasher(HashOptions{BcryptCost: 5})
+	if !stronger.NeedsRehash(hashed) {
+		t.Error("bcrypt hash should be rehashed after cost change")
+	}
+}
+
+func TestHasherLegacyMD5(t *testing.T) {
+	h, err := NewHasher(HashOptions{BcryptCost: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// md5("password123" + "salt")
+	legacy := "cffba26ed2548ed5d09e293b0b3c517d"
+	if ok, err := h.Verify("password123", legacy, "salt"); !ok || err != nil {
+		t.Errorf("Verify(legacy) = %v, %v", ok, err)
+	}
+	if ok, _ := h.Verify("password123", legacy, "pepper"); ok {
+		t.Error("Verify(legacy) accepted a wrong salt")
+	}
+	if !h.NeedsRehash(legacy) {
+		t.Error("legacy MD5 hash should be rehashed")
+	}
+	if _, err := h.Verify("password123", "plaintext", ""); err != ErrUnknownHash {
+		t.Errorf("Verify(unknown) error = %v, want ErrUnknownHash", err)
+	}
+}
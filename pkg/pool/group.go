@@ -0,0 +1,58 @@
+package pool
+
+import (
+	"context"
+	"runtime/debug"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Group 带并发上限的任务组，基于 errgroup:
+//   - 任一任务返回错误(或 panic)时取消 ctx，Wait 返回第一个错误
+//   - 任务中的 panic 会被恢复并转换为 *PanicError，不会导致进程崩溃
+type Group struct {
+	g   *errgroup.Group
+	ctx context.Context
+}
+
+// WithContext 创建任务组，limit <= 0 表示不限制并发数
+func WithContext(ctx context.Context, limit int) (*Group, context.Context) {
+	g, ctx := errgroup.WithContext(ctx)
+	if limit > 0 {
+		g.SetLimit(limit)
+	}
+	return &Group{g: g, ctx: ctx}, ctx
+}
+
+// Go 启动任务，达到并发上限时阻塞；ctx 已取消时不再执行新任务
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	g.g.Go(func() (err error) {
+		if err := g.ctx.Err(); err != nil {
+			return err
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				err = &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		return fn(g.ctx)
+	})
+}
+
+// Wait 等待所有任务结束，返回第一个错误
+func (g *Group) Wait() error {
+	return g.g.Wait()
+}
+
+// ForEach 以最多 limit 个并发处理 items，遇到第一个错误后取消其余任务
+func ForEach[T any](ctx context.Context, items []T, limit int, fn func(ctx context.Context, item T) error) error {
+	g, _ := WithContext(ctx, limit)
+	for _, item := range items {
+		item := item
+		g.Go(func(ctx context.Context) error {
+			return fn(ctx, item)
+		})
+	}
+	return g.Wait()
+}
@@ -0,0 +1,133 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// ErrPoolClosed 向已关闭的协程池提交任务
+var ErrPoolClosed = errors.New("pool: closed")
+
+// Task 协程池中执行的任务
+type Task func()
+
+// PanicHandler 任务 panic 时的回调，r 为 recover() 的返回值
+type PanicHandler func(r interface{}, stack []byte)
+
+// Option 协程池配置项
+type Option func(*Pool)
+
+// WithPanicHandler 设置任务 panic 时的回调，一般用于记录日志
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(p *Pool) {
+		p.panicHandler = handler
+	}
+}
+
+// Pool 固定数量 worker 的协程池，任务队列满时 Submit 阻塞，
+// 用于限制后台任务(导出、webhook 推送、缓存预热等)同时占用的数据库/Redis 连接数
+type Pool struct {
+	tasks        chan Task
+	wg           sync.WaitGroup
+	mu           sync.RWMutex
+	closed       bool
+	panicHandler PanicHandler
+}
+
+// New 创建协程池，workers 为 worker 数量，queueSize 为等待队列长度
+func New(workers, queueSize int, opts ...Option) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	p := &Pool{
+		tasks: make(chan Task, queueSize),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+func (p *Pool) run(task Task) {
+	defer func() {
+		if r := recover(); r != nil && p.panicHandler != nil {
+			p.panicHandler(r, debug.Stack())
+		}
+	}()
+	task()
+}
+
+// Submit 提交任务，队列已满时阻塞直到有空位或 ctx 结束
+func (p *Pool) Submit(ctx context.Context, task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TrySubmit 非阻塞提交任务，队列已满或协程池已关闭时返回 false
+func (p *Pool) TrySubmit(task Task) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close 停止接收新任务，并等待队列中已有任务执行完成
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// PanicError 任务 panic 后转换得到的错误
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("pool: task panic: %v", e.Value)
+}
@@ -0,0 +1,58 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	var panics int32
+	p := New(2, 4, WithPanicHandler(func(r interface{}, stack []byte) {
+		atomic.AddInt32(&panics, 1)
+	}))
+
+	var done int32
+	for i := 0; i < 10; i++ {
+		if err := p.Submit(context.Background(), func() {
+			atomic.AddInt32(&done, 1)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = p.Submit(context.Background(), func() { panic("boom") })
+	p.Close()
+
+	if done != 10 {
+		t.Fatalf("done = %d, want 10", done)
+	}
+	if panics != 1 {
+		t.Fatalf("panics = %d, want 1", panics)
+	}
+	if err := p.Submit(context.Background(), func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("submit after close: %v", err)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	var sum int64
+	err := ForEach(context.Background(), []int64{1, 2, 3, 4}, 2, func(ctx context.Context, n int64) error {
+		atomic.AddInt64(&sum, n)
+		return nil
+	})
+	if err != nil || sum != 10 {
+		t.Fatalf("sum = %d, err = %v", sum, err)
+	}
+
+	err = ForEach(context.Background(), []int{1, 2}, 1, func(ctx context.Context, n int) error {
+		if n == 2 {
+			panic("boom")
+		}
+		return nil
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("err = %v, want *PanicError", err)
+	}
+}
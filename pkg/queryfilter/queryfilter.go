@@ -0,0 +1,202 @@
+// Package queryfilter 把列表接口的过滤和排序查询参数(如 ?status=paid&min_price=10&sort=-created_at)转换为 GORM 查询条件
+//
+// 可用的参数和对应的列都由 Spec 白名单声明，请求中的参数名不会拼接进 SQL，参数值作为绑定参数传入。
+package queryfilter
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SortParam 排序参数名，取值为逗号分隔的字段名，字段名前加 - 表示降序，如 sort=-created_at,id
+const SortParam = "sort"
+
+// Op 过滤参数与列的比较方式
+type Op string
+
+const (
+	Eq     Op = "="
+	Gt     Op = ">"
+	Gte    Op = ">="
+	Lt     Op = "<"
+	Lte    Op = "<="
+	Prefix Op = "prefix" // 前缀匹配，参数值中的 % 和 _ 按字面匹配
+)
+
+// Filter 一个过滤参数
+type Filter struct {
+	Param  string                            // 查询参数名
+	Column string                            // 列名
+	Op     Op                                // 比较方式
+	Parse  func(string) (interface{}, error) // 把参数值转换为列的取值，为空时按字符串比较
+}
+
+// Spec 列表接口允许的过滤参数和排序字段，不在其中的查询参数会被忽略，不在其中的排序字段返回错误
+type Spec struct {
+	Filters     []Filter
+	Sorts       map[string]string // sort 参数中可用的字段名及对应的列名
+	DefaultSort []Sort            // 未指定 sort 参数时的排序
+	Tiebreak    string            // 唯一列(通常为 id)，排序列取值相同时按它排序，保证分页结果稳定
+}
+
+// Condition 一个过滤条件
+type Condition struct {
+	Column string
+	Op     Op
+	Value  interface{}
+}
+
+// Sort 一个排序列
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Query 从查询参数解析出的过滤条件和排序
+type Query struct {
+	Conditions []Condition
+	Sort       []Sort // 请求指定的排序，为空时按 Spec.DefaultSort 排序
+}
+
+// IsZero 请求没有指定任何过滤条件和排序
+func (q Query) IsZero() bool {
+	return len(q.Conditions) == 0 && len(q.Sort) == 0
+}
+
+// Error 过滤或排序参数错误
+type Error struct {
+	Param string // 出错的查询参数名
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return e.Param + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Parse 解析查询参数，参数值为空的过滤参数不参与过滤；参数值无法解析或排序字段不可用时返回 *Error
+func (s *Spec) Parse(values url.Values) (Query, error) {
+	var q Query
+	for _, f := range s.Filters {
+		raw := values.Get(f.Param)
+		if raw == "" {
+			continue
+		}
+		var value interface{} = raw
+		if f.Parse != nil {
+			v, err := f.Parse(raw)
+			if err != nil {
+				return Query{}, &Error{Param: f.Param, Err: fmt.Errorf("invalid value %q", raw)}
+			}
+			value = v
+		}
+		q.Conditions = append(q.Conditions, Condition{Column: f.Column, Op: f.Op, Value: value})
+	}
+
+	if raw := values.Get(SortParam); raw != "" {
+		seen := make(map[string]bool)
+		for _, field := range strings.Split(raw, ",") {
+			desc := strings.HasPrefix(field, "-")
+			field = strings.TrimPrefix(field, "-")
+			column, ok := s.Sorts[field]
+			if !ok {
+				return Query{}, &Error{Param: SortParam, Err: fmt.Errorf("cannot sort by %q, allowed: %s", field, s.sortFields())}
+			}
+			if seen[field] {
+				return Query{}, &Error{Param: SortParam, Err: fmt.Errorf("duplicate sort field %q", field)}
+			}
+			seen[field] = true
+			q.Sort = append(q.Sort, Sort{Column: column, Desc: desc})
+		}
+	}
+	return q, nil
+}
+
+func (s *Spec) sortFields() string {
+	fields := make([]string, 0, len(s.Sorts))
+	for field := range s.Sorts {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ", ")
+}
+
+// Where 过滤条件，table 为表在查询中的名称或别名，关联查询时用于区分同名列，为空时不加限定
+func (q Query) Where(table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, c := range q.Conditions {
+			db = db.Where(c.expression(table))
+		}
+		return db
+	}
+}
+
+func (c Condition) expression(table string) clause.Expression {
+	column := clause.Column{Table: table, Name: c.Column}
+	switch c.Op {
+	case Gt:
+		return clause.Gt{Column: column, Value: c.Value}
+	case Gte:
+		return clause.Gte{Column: column, Value: c.Value}
+	case Lt:
+		return clause.Lt{Column: column, Value: c.Value}
+	case Lte:
+		return clause.Lte{Column: column, Value: c.Value}
+	case Prefix:
+		return clause.Like{Column: column, Value: escapeLike(fmt.Sprint(c.Value)) + "%"}
+	default:
+		return clause.Eq{Column: column, Value: c.Value}
+	}
+}
+
+// escapeLike 转义 LIKE 的通配符，使用默认的转义字符 \
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// Order 按请求指定的排序(未指定时按 DefaultSort)排序，最后按 Tiebreak 列与第一个排序列同向排序
+func (s *Spec) Order(q Query, table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		sorts := q.Sort
+		if len(sorts) == 0 {
+			sorts = s.DefaultSort
+		}
+		tiebreak := s.Tiebreak != ""
+		for _, o := range sorts {
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Table: table, Name: o.Column}, Desc: o.Desc})
+			if o.Column == s.Tiebreak {
+				tiebreak = false
+			}
+		}
+		if tiebreak {
+			desc := len(sorts) > 0 && sorts[0].Desc
+			db = db.Order(clause.OrderByColumn{Column: clause.Column{Table: table, Name: s.Tiebreak}, Desc: desc})
+		}
+		return db
+	}
+}
+
+// Int 解析整数参数
+func Int(s string) (interface{}, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// Float 解析数字参数
+func Float(s string) (interface{}, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+// Time 解析 RFC 3339 格式的时间参数，如 2025-12-01T00:00:00Z
+func Time(s string) (interface{}, error) {
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,96 @@
+package queryfilter
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type order struct {
+	ID     uint
+	Status int64
+}
+
+var spec = &Spec{
+	Filters: []Filter{
+		{Param: "status", Column: "status", Op: Eq, Parse: Int},
+		{Param: "min_price", Column: "total_price", Op: Gte, Parse: Float},
+		{Param: "created_to", Column: "created_at", Op: Lt, Parse: Time},
+		{Param: "username", Column: "username", Op: Prefix},
+	},
+	Sorts:       map[string]string{"created_at": "created_at", "price": "total_price", "id": "id"},
+	DefaultSort: []Sort{{Column: "created_at", Desc: true}},
+	Tiebreak:    "id",
+}
+
+func dryRun(t *testing.T, scopes ...func(*gorm.DB) *gorm.DB) (string, []interface{}) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt := db.Table("orders").Scopes(scopes...).Find(&[]order{}).Statement
+	return stmt.SQL.String(), stmt.Vars
+}
+
+func TestParse(t *testing.T) {
+	q, err := spec.Parse(url.Values{
+		"status":    {"2"},
+		"min_price": {"10.5"},
+		"username":  {"a_b%"},
+		"page":      {"3"}, // 不在白名单中的参数被忽略
+		"sort":      {"-price,id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sql, vars := dryRun(t, q.Where("orders"), spec.Order(q, "orders"))
+	want := `SELECT * FROM "orders" WHERE "orders"."status" = $1 AND "orders"."total_price" >= $2 AND "orders"."username" LIKE $3 ORDER BY "orders"."total_price" DESC,"orders"."id"`
+	if sql != want {
+		t.Errorf("sql = %s\nwant  %s", sql, want)
+	}
+	if len(vars) != 3 || vars[0] != int64(2) || vars[1] != 10.5 || vars[2] != `a\_b\%%` {
+		t.Errorf("vars = %#v", vars)
+	}
+}
+
+func TestDefaultSort(t *testing.T) {
+	q, err := spec.Parse(url.Values{"status": {""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.IsZero() {
+		t.Errorf("empty values should not filter: %+v", q)
+	}
+
+	sql, _ := dryRun(t, q.Where(""), spec.Order(q, ""))
+	if !strings.HasSuffix(sql, `ORDER BY "created_at" DESC,"id" DESC`) {
+		t.Errorf("sql = %s", sql)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]url.Values{
+		"status":     {"status": {"paid"}},
+		"created_to": {"created_to": {"2025-12-01"}},
+		"sort":       {"sort": {"password"}},
+	}
+	for param, values := range cases {
+		_, err := spec.Parse(values)
+		var e *Error
+		if !errors.As(err, &e) || e.Param != param {
+			t.Errorf("Parse(%v) error = %v, want error for %s", values, err, param)
+		}
+	}
+
+	for _, sort := range []string{"id,-id", "id,", "created_at;drop"} {
+		if _, err := spec.Parse(url.Values{"sort": {sort}}); err == nil {
+			t.Errorf("sort=%s should be rejected", sort)
+		}
+	}
+}
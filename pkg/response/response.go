@@ -3,64 +3,124 @@ package response
 import (
 	"net/http"
 
+	"gin-app-start/pkg/i18n"
+	"gin-app-start/pkg/trace"
+
 	"github.com/gin-gonic/gin"
 )
 
+// SuccessMessage 成功响应的 message
+const SuccessMessage = "success"
+
 // Response is the standard API response structure
+// 成功时 code 为 0、data 为返回数据；失败时 code 为业务码，一般不返回 data(个别接口用 data 返回失败的上下文，如就绪探针)
 type Response struct {
-	Code    int         `json:"code" example:"0"`
-	Message string      `json:"message" example:"success"`
-	Data    interface{} `json:"data"`
-	TraceID string      `json:"trace_id,omitempty" example:"trace-id-123"`
+	Code    int            `json:"code" example:"0"`
+	Message string         `json:"message" example:"success"`
+	Details []FieldError   `json:"details,omitempty"` // 参数校验失败时逐个列出不合法的字段
+	Data    interface{}    `json:"data,omitempty"`
+	Page    *Page          `json:"page,omitempty"`
+	TraceID string         `json:"trace_id,omitempty" example:"trace-id-123"`
+	Banner  string         `json:"banner,omitempty" example:"admin is impersonating john_doe (user 12) until 2023-01-01T00:30:00Z, all actions are audited"` // 需要客户端醒目展示的提示，如管理员正在代入用户
+	Debug   *trace.Summary `json:"debug,omitempty"`                                                                                                          // 管理员携带请求头 X-Debug: 1 时返回本次请求执行的 SQL、Redis 命令及耗时
 }
 
-func Success(c *gin.Context, data interface{}) {
-	c.JSON(http.StatusOK, Response{
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field" example:"/items/0/price"` // 字段的 JSON Pointer 路径，查询参数和路径参数为 /参数名
+	Rule    string `json:"rule" example:"required"`        // 未通过的校验规则，JSON 类型不匹配时为 type
+	Message string `json:"message" example:"price is a required field"`
+}
+
+// Page 分页信息
+type Page struct {
+	Total      int64  `json:"total" example:"100"`
+	Page       int    `json:"page" example:"1"`
+	PageSize   int    `json:"page_size" example:"10"`
+	NextCursor string `json:"next_cursor,omitempty" example:"NDI"` // 游标分页时下一页的游标，没有下一页时为空
+}
+
+// Paged 带分页信息的列表，经 OK 格式化后列表放在 data，分页信息放在 page
+type Paged struct {
+	List interface{}
+	Page Page
+}
+
+// NewPaged 创建分页列表
+func NewPaged(list interface{}, total int64, page, pageSize int) *Paged {
+	return &Paged{
+		List: list,
+		Page: Page{Total: total, Page: page, PageSize: pageSize},
+	}
+}
+
+// NewCursorPaged 创建游标分页列表，nextCursor 为空表示没有下一页；游标分页不统计总数，total 和 page 为 0
+func NewCursorPaged(list interface{}, nextCursor string, pageSize int) *Paged {
+	return &Paged{
+		List: list,
+		Page: Page{PageSize: pageSize, NextCursor: nextCursor},
+	}
+}
+
+// OK 格式化成功响应，Logger 中间件的 Payload 和下面的辅助函数都通过它输出
+func OK(data interface{}) Response {
+	res := Response{
 		Code:    0,
-		Message: "success",
+		Message: SuccessMessage,
 		Data:    data,
-	})
+	}
+
+	if paged, ok := data.(*Paged); ok {
+		page := paged.Page
+		res.Data = paged.List
+		res.Page = &page
+	}
+	return res
 }
 
-// PageResponse represents a paginated response
-type PageResponse struct {
-	List     interface{} `json:"list"`
-	Total    int64       `json:"total" example:"100"`
-	Page     int         `json:"page" example:"1"`
-	PageSize int         `json:"page_size" example:"10"`
+// Fail 格式化失败响应，traceID 为空时不返回
+func Fail(code int, message, traceID string) Response {
+	return Response{
+		Code:    code,
+		Message: message,
+		TraceID: traceID,
+	}
 }
 
-func SuccessWithPage(c *gin.Context, list interface{}, total int64, page, pageSize int) {
-	pageResponse := PageResponse{
-		List:     list,
-		Total:    total,
-		Page:     page,
-		PageSize: pageSize,
+// Localize 按请求协商的语言翻译 message 和字符串类型的 data(如 "Deleted successfully")，
+// 未在 i18n 中注册的文本原样保留
+func Localize(c *gin.Context, res Response) Response {
+	res.Message = i18n.T(c, res.Message)
+	if text, ok := res.Data.(string); ok {
+		res.Data = i18n.T(c, text)
 	}
-	Success(c, pageResponse)
+	return res
+}
+
+func Success(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusOK, Localize(c, OK(data)))
 }
 
+func SuccessWithPage(c *gin.Context, list interface{}, total int64, page, pageSize int) {
+	Success(c, NewPaged(list, total, page, pageSize))
+}
+
+func SuccessWithCursor(c *gin.Context, list interface{}, nextCursor string, pageSize int) {
+	Success(c, NewCursorPaged(list, nextCursor, pageSize))
+}
+
+// Error message 可以是 i18n 中注册的消息ID或原文，按请求协商的语言返回
 func Error(c *gin.Context, code int, message string) {
-	c.JSON(http.StatusOK, Response{
-		Code:    code,
-		Message: message,
-		Data:    nil,
-	})
+	c.JSON(http.StatusOK, Localize(c, Fail(code, message, "")))
 }
 
 func ErrorWithTrace(c *gin.Context, code int, message string, traceID string) {
-	c.JSON(http.StatusOK, Response{
-		Code:    code,
-		Message: message,
-		Data:    nil,
-		TraceID: traceID,
-	})
+	c.JSON(http.StatusOK, Localize(c, Fail(code, message, traceID)))
 }
 
+// SuccessWithMessage message 可以是 i18n 中注册的消息ID或原文，按请求协商的语言返回
 func SuccessWithMessage(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, Response{
-		Code:    0,
-		Message: message,
-		Data:    data,
-	})
+	res := OK(data)
+	res.Message = message
+	c.JSON(http.StatusOK, Localize(c, res))
 }
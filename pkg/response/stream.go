@@ -0,0 +1,88 @@
+package response
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// 流式响应的 Content-Type
+const (
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeJSON   = "application/json; charset=utf-8"
+)
+
+// flusher 与 http.Flusher 相同，gin 的 ResponseWriter 实现了该接口
+type flusher interface {
+	Flush()
+}
+
+// StreamWriter 逐条写出列表元素，用于数据量大的导出接口，内存中不需要保留完整的列表
+//
+// 默认输出 NDJSON(每行一个 JSON 对象)；array 为 true 时输出一个 JSON 数组，按元素分块写出。
+// 调用方每写完一批调用 Flush 推送给客户端，全部写完后调用 Close。
+type StreamWriter struct {
+	w     io.Writer
+	enc   *json.Encoder
+	array bool
+	count int
+}
+
+func NewStreamWriter(w io.Writer, array bool) *StreamWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &StreamWriter{w: w, enc: enc, array: array}
+}
+
+// ContentType 当前格式对应的 Content-Type
+func (s *StreamWriter) ContentType() string {
+	if s.array {
+		return ContentTypeJSON
+	}
+	return ContentTypeNDJSON
+}
+
+// Count 已写出的元素数
+func (s *StreamWriter) Count() int {
+	return s.count
+}
+
+// Write 写出一个元素
+func (s *StreamWriter) Write(v interface{}) error {
+	if s.array {
+		sep := ","
+		if s.count == 0 {
+			sep = "["
+		}
+		if _, err := io.WriteString(s.w, sep); err != nil {
+			return err
+		}
+	}
+	// Encoder 在每个元素后追加换行，正好作为 NDJSON 的行分隔符，JSON 数组中的换行也是合法的空白
+	if err := s.enc.Encode(v); err != nil {
+		return err
+	}
+	s.count++
+	return nil
+}
+
+// Flush 把已写出的内容推送给客户端
+func (s *StreamWriter) Flush() {
+	if f, ok := s.w.(flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close 结束输出，JSON 数组补上结尾
+func (s *StreamWriter) Close() error {
+	if s.array {
+		end := "]"
+		if s.count == 0 {
+			end = "[]"
+		}
+		if _, err := io.WriteString(s.w, end); err != nil {
+			return err
+		}
+	}
+	s.Flush()
+	return nil
+}
@@ -0,0 +1,58 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type row struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestStreamWriterNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewStreamWriter(&buf, false)
+	for i := 1; i <= 3; i++ {
+		if err := s.Write(row{ID: i, Name: "<a>"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 || s.Count() != 3 {
+		t.Fatalf("output = %q", buf.String())
+	}
+	var r row
+	if err := json.Unmarshal([]byte(lines[2]), &r); err != nil || r.ID != 3 || r.Name != "<a>" {
+		t.Errorf("last line = %q", lines[2])
+	}
+}
+
+func TestStreamWriterArray(t *testing.T) {
+	for _, n := range []int{0, 1, 5} {
+		var buf bytes.Buffer
+		s := NewStreamWriter(&buf, true)
+		for i := 0; i < n; i++ {
+			if err := s.Write(row{ID: i}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		var rows []row
+		if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+			t.Fatalf("n=%d invalid json %q: %v", n, buf.String(), err)
+		}
+		if len(rows) != n {
+			t.Errorf("n=%d decoded %d rows", n, len(rows))
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+)
+
+// Classifier 判断错误是否可以重试
+type Classifier func(err error) bool
+
+// Observer 重试观察者，用于上报重试次数等指标
+//
+// retried 为本次调用实际重试的次数(不含首次执行)，err 为最终结果
+type Observer func(name string, retried int, err error)
+
+var observer Observer
+
+// SetObserver 设置全局重试观察者，需在启动阶段调用
+func SetObserver(o Observer) {
+	observer = o
+}
+
+type option struct {
+	name        string
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	retryable   Classifier
+	onRetry     func(attempt int, err error, delay time.Duration)
+}
+
+// Option 重试配置项
+type Option func(*option)
+
+func newOption() *option {
+	return &option{
+		name:        "default",
+		maxAttempts: 3,
+		baseDelay:   100 * time.Millisecond,
+		maxDelay:    2 * time.Second,
+		retryable:   IsTransient,
+	}
+}
+
+// WithName 设置操作名，用作指标标签，如 "redis.ping"
+func WithName(name string) Option {
+	return func(opt *option) {
+		opt.name = name
+	}
+}
+
+// WithMaxAttempts 设置最大执行次数(含首次执行)
+func WithMaxAttempts(n int) Option {
+	return func(opt *option) {
+		if n > 0 {
+			opt.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff 设置退避的初始间隔和最大间隔，每次重试间隔翻倍
+func WithBackoff(base, max time.Duration) Option {
+	return func(opt *option) {
+		opt.baseDelay = base
+		opt.maxDelay = max
+	}
+}
+
+// WithClassifier 设置可重试错误的判断方法，默认为 IsTransient
+func WithClassifier(c Classifier) Option {
+	return func(opt *option) {
+		opt.retryable = c
+	}
+}
+
+// WithOnRetry 每次重试前回调，一般用于记录日志
+func WithOnRetry(fn func(attempt int, err error, delay time.Duration)) Option {
+	return func(opt *option) {
+		opt.onRetry = fn
+	}
+}
+
+// Do 执行 fn，遇到可重试错误时按指数退避 + 随机抖动重试，
+// 直到成功、遇到不可重试错误、达到最大次数或 ctx 结束
+func Do(ctx context.Context, fn func(ctx context.Context) error, options ...Option) error {
+	opt := newOption()
+	for _, f := range options {
+		f(opt)
+	}
+
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			break
+		}
+		if attempt >= opt.maxAttempts || !opt.retryable(err) {
+			break
+		}
+
+		delay := backoff(opt.baseDelay, opt.maxDelay, attempt)
+		if opt.onRetry != nil {
+			opt.onRetry(attempt, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if observer != nil {
+				observer(opt.name, attempt-1, err)
+			}
+			return err
+		case <-timer.C:
+		}
+	}
+
+	if observer != nil {
+		observer(opt.name, attempt-1, err)
+	}
+	return err
+}
+
+// backoff 计算第 attempt 次重试前的等待时间，在 [d/2, d) 之间随机抖动，避免重试风暴
+func backoff(base, max time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	d := base << (attempt - 1)
+	if d <= 0 || (max > 0 && d > max) {
+		d = max
+	}
+
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+// IsTransient 判断是否为连接重置、超时等临时性错误
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// 调用方主动取消不重试
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
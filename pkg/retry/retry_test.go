@@ -0,0 +1,51 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestDo(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return syscall.ECONNRESET
+		}
+		return nil
+	}, WithBackoff(time.Millisecond, 5*time.Millisecond))
+	if err != nil || calls != 3 {
+		t.Fatalf("calls = %d, err = %v", calls, err)
+	}
+
+	calls = 0
+	permanent := errors.New("permanent")
+	err = Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return permanent
+	}, WithBackoff(time.Millisecond, 5*time.Millisecond))
+	if !errors.Is(err, permanent) || calls != 1 {
+		t.Fatalf("calls = %d, err = %v", calls, err)
+	}
+
+	calls = 0
+	err = Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return context.DeadlineExceeded
+	}, WithMaxAttempts(2), WithBackoff(time.Millisecond, 5*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) || calls != 2 {
+		t.Fatalf("calls = %d, err = %v", calls, err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 1; attempt < 40; attempt++ {
+		d := backoff(100*time.Millisecond, time.Second, attempt)
+		if d < 0 || d > time.Second {
+			t.Fatalf("attempt %d: delay %s out of range", attempt, d)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound 密钥不存在
+var ErrNotFound = errors.New("secrets: not found")
+
+// Provider 按名称读取密钥，密钥不写入配置文件，由部署环境注入
+type Provider interface {
+	Get(ctx context.Context, name string) ([]byte, error)
+}
+
+// EnvProvider 从环境变量读取密钥，变量名为 prefix + 大写的 name，如 APP_SECRET_PII_KEY_V1
+type EnvProvider struct {
+	Prefix string
+}
+
+func (p EnvProvider) Get(_ context.Context, name string) ([]byte, error) {
+	key := p.Prefix + strings.ToUpper(name)
+	value, ok := os.LookupEnv(key)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("%w: env %s", ErrNotFound, key)
+	}
+	return []byte(value), nil
+}
+
+// FileProvider 从目录下的同名文件读取密钥，适用于 Docker/Kubernetes secrets 挂载(默认 /run/secrets)
+// 文件末尾的换行会被去掉
+type FileProvider struct {
+	Dir string
+}
+
+func (p FileProvider) Get(_ context.Context, name string) ([]byte, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return nil, fmt.Errorf("secrets: invalid name %q", name)
+	}
+
+	path := filepath.Join(p.Dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: file %s", ErrNotFound, path)
+		}
+		return nil, err
+	}
+	return []byte(strings.TrimRight(string(data), "\r\n")), nil
+}
+
+// New 按类型创建 Provider: env(默认) 或 file
+func New(kind, envPrefix, dir string) (Provider, error) {
+	switch kind {
+	case "", "env":
+		return EnvProvider{Prefix: envPrefix}, nil
+	case "file":
+		if dir == "" {
+			dir = "/run/secrets"
+		}
+		return FileProvider{Dir: dir}, nil
+	default:
+		return nil, fmt.Errorf("secrets: unsupported provider %q", kind)
+	}
+}
@@ -0,0 +1,145 @@
+package token
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// 常用的 token 用途，生成和校验时必须一致，避免邮箱验证的 token 被拿去下载文件
+const (
+	PurposeEmailVerify = "email_verify"
+	PurposeDownload    = "download"
+	PurposeUnsubscribe = "unsubscribe"
+)
+
+var (
+	// ErrInvalid token 格式错误或签名不匹配
+	ErrInvalid = errors.New("token: invalid")
+	// ErrExpired token 已过期
+	ErrExpired = errors.New("token: expired")
+	// ErrPurposeMismatch token 用途不匹配
+	ErrPurposeMismatch = errors.New("token: purpose mismatch")
+	// ErrUsed token 已被使用
+	ErrUsed = errors.New("token: already used")
+)
+
+// Claims token 中携带的信息
+type Claims struct {
+	ID        string `json:"id"`  // 随机ID，用于记录是否已使用
+	Purpose   string `json:"p"`   // 用途
+	Subject   string `json:"s"`   // 主体，如用户ID、文件名、邮箱
+	ExpiresAt int64  `json:"exp"` // 过期时间(unix 秒)
+}
+
+// Store 记录已使用的 token，多实例部署时需使用共享存储(如 RedisRepository.SetNX，键带命名空间前缀)
+type Store interface {
+	// MarkUsed 标记 id 已使用，ttl 后记录可以被清理；id 此前已被标记时返回 false
+	MarkUsed(ctx context.Context, id string, ttl time.Duration) (bool, error)
+}
+
+// Manager 生成和校验一次性 token
+//
+// token 格式为 base64url(claims).base64url(HMAC-SHA256(claims))，签名保证内容不可篡改，
+// Store 保证同一个 token 只能被 Consume 一次，防止链接被重放。
+type Manager struct {
+	secret []byte
+	store  Store
+	now    func() time.Time
+}
+
+// New 创建 token 管理器，secret 为签名密钥
+func New(secret []byte, store Store) *Manager {
+	return &Manager{
+		secret: secret,
+		store:  store,
+		now:    time.Now,
+	}
+}
+
+// Generate 生成一个 ttl 后过期的 token
+func (m *Manager) Generate(purpose, subject string, ttl time.Duration) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(Claims{
+		ID:        hex.EncodeToString(buf),
+		Purpose:   purpose,
+		Subject:   subject,
+		ExpiresAt: m.now().Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + m.sign(encoded), nil
+}
+
+// Verify 校验 token 的签名、用途和过期时间，不标记为已使用
+// 用于展示确认页等只读场景，真正执行操作时必须调用 Consume
+func (m *Manager) Verify(token, purpose string) (*Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalid
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(m.sign(encoded))) {
+		return nil, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" {
+		return nil, ErrInvalid
+	}
+
+	if claims.Purpose != purpose {
+		return nil, ErrPurposeMismatch
+	}
+	if m.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpired
+	}
+
+	return &claims, nil
+}
+
+// Consume 校验 token 并标记为已使用，同一个 token 第二次调用返回 ErrUsed
+func (m *Manager) Consume(ctx context.Context, token, purpose string) (*Claims, error) {
+	claims, err := m.Verify(token, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	// 使用记录只需保留到 token 过期，之后签名校验就会拒绝
+	ttl := time.Unix(claims.ExpiresAt, 0).Sub(m.now()) + time.Second
+	first, err := m.store.MarkUsed(ctx, claims.ID, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		return nil, ErrUsed
+	}
+
+	return claims, nil
+}
+
+func (m *Manager) sign(encoded string) string {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
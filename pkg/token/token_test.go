@@ -0,0 +1,81 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memoryStore struct {
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+func (s *memoryStore) MarkUsed(_ context.Context, id string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.used[id] {
+		return false, nil
+	}
+	s.used[id] = true
+	return true, nil
+}
+
+func newTestManager() *Manager {
+	return New([]byte("test-secret"), &memoryStore{used: make(map[string]bool)})
+}
+
+func TestConsumeOnce(t *testing.T) {
+	m := newTestManager()
+	ctx := context.Background()
+
+	tok, err := m.Generate(PurposeEmailVerify, "42", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := m.Consume(ctx, tok, PurposeEmailVerify)
+	if err != nil {
+		t.Fatalf("first consume: %v", err)
+	}
+	if claims.Subject != "42" {
+		t.Fatalf("subject = %q, want 42", claims.Subject)
+	}
+
+	if _, err := m.Consume(ctx, tok, PurposeEmailVerify); !errors.Is(err, ErrUsed) {
+		t.Fatalf("second consume err = %v, want ErrUsed", err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	m := newTestManager()
+
+	tok, err := m.Generate(PurposeDownload, "report.csv", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.Verify(tok, PurposeUnsubscribe); !errors.Is(err, ErrPurposeMismatch) {
+		t.Fatalf("purpose mismatch err = %v", err)
+	}
+
+	encoded, signature, _ := strings.Cut(tok, ".")
+	tampered := encoded + "x." + signature
+	if _, err := m.Verify(tampered, PurposeDownload); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("tampered err = %v", err)
+	}
+
+	other := New([]byte("other-secret"), nil)
+	if _, err := other.Verify(tok, PurposeDownload); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("wrong secret err = %v", err)
+	}
+
+	m.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if _, err := m.Verify(tok, PurposeDownload); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expired err = %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package trace
+
+import "time"
+
+// Summary 写出响应前的链路信息，调试模式下随响应返回给客户端；不含请求和响应本身
+type Summary struct {
+	Identifier         string    `json:"trace_id"`             // 链路ID
+	ThirdPartyRequests []*Dialog `json:"third_party_requests"` // 调用第三方接口的信息
+	Debugs             []*Debug  `json:"debugs"`               // 调试信息
+	SQLs               []*SQL    `json:"sqls"`                 // 执行的 SQL 信息
+	Redis              []*Redis  `json:"redis"`                // 执行的 Redis 信息
+	CostSeconds        float64   `json:"cost_seconds"`         // 截至写出响应前的执行时长(单位秒)
+}
+
+// Summarize 复制当前已记录的链路信息，cost 为截至目前的请求耗时
+// 返回的切片与 Trace 不共用底层数组，之后追加的记录不会出现在 Summary 中
+func (t *Trace) Summarize(cost time.Duration) *Summary {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	return &Summary{
+		Identifier:         t.Identifier,
+		ThirdPartyRequests: append(make([]*Dialog, 0, len(t.ThirdPartyRequests)), t.ThirdPartyRequests...),
+		Debugs:             append(make([]*Debug, 0, len(t.Debugs)), t.Debugs...),
+		SQLs:               append(make([]*SQL, 0, len(t.SQLs)), t.SQLs...),
+		Redis:              append(make([]*Redis, 0, len(t.Redis)), t.Redis...),
+		CostSeconds:        cost.Seconds(),
+	}
+}
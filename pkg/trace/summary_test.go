@@ -0,0 +1,36 @@
+package trace
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	tr := New("trace-1")
+	tr.AppendSQL(&SQL{SQL: "SELECT 1", CostSeconds: 0.002})
+	tr.AppendRedis(&Redis{Handle: "GET", Key: "k", CostSeconds: 0.001})
+
+	s := tr.Summarize(1500 * time.Millisecond)
+	tr.AppendSQL(&SQL{SQL: "SELECT 2"})
+
+	if s.Identifier != "trace-1" || s.CostSeconds != 1.5 {
+		t.Fatalf("summary = %+v", s)
+	}
+	if len(s.SQLs) != 1 || s.SQLs[0].SQL != "SELECT 1" {
+		t.Fatalf("SQLs = %+v, want only the statement recorded before Summarize", s.SQLs)
+	}
+	if len(s.Redis) != 1 {
+		t.Fatalf("Redis = %+v", s.Redis)
+	}
+
+	// 没有记录时输出空数组，调试客户端不需要区分 null
+	data, err := json.Marshal(New("trace-2").Summarize(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"trace_id":"trace-2","third_party_requests":[],"debugs":[],"sqls":[],"redis":[],"cost_seconds":0}`
+	if string(data) != want {
+		t.Fatalf("json = %s, want %s", data, want)
+	}
+}
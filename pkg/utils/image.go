@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrImageEncoding 不是合法的 base64
+	ErrImageEncoding = errors.New("image is not valid base64")
+	// ErrImageTooLarge 解码后超过大小限制
+	ErrImageTooLarge = errors.New("image exceeds the size limit")
+	// ErrImageType 内容不是支持的图片格式
+	ErrImageType = errors.New("image type is not allowed")
+)
+
+// imageExts 允许的图片类型及保存时使用的后缀名
+var imageExts = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// DecodeBase64Image 解码 base64 图片，返回内容和按内容识别出的后缀名
+//
+// s 可以带 data:image/png;base64, 前缀，可以省略填充、含换行；类型按内容识别，不信任前缀中声明的类型。
+// maxSize 为解码后的最大字节数，<= 0 时不限制，超出时在解码前即返回 ErrImageTooLarge。
+func DecodeBase64Image(s string, maxSize int64) ([]byte, string, error) {
+	if strings.HasPrefix(s, "data:") {
+		_, data, ok := strings.Cut(s, ",")
+		if !ok {
+			return nil, "", ErrImageEncoding
+		}
+		s = data
+	}
+	s = strings.NewReplacer("\r", "", "\n", "").Replace(s)
+	s = strings.TrimRight(s, "=")
+
+	if maxSize > 0 && int64(base64.RawStdEncoding.DecodedLen(len(s))) > maxSize {
+		return nil, "", ErrImageTooLarge
+	}
+	data, err := base64.RawStdEncoding.DecodeString(s)
+	if err != nil || len(data) == 0 {
+		return nil, "", ErrImageEncoding
+	}
+
+	ext, ok := imageExts[http.DetectContentType(data)]
+	if !ok {
+		return nil, "", ErrImageType
+	}
+	return data, ext, nil
+}
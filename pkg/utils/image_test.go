@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+// pngHeader PNG 文件签名，足以让 http.DetectContentType 识别为 image/png
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestDecodeBase64Image(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(pngHeader)
+
+	cases := []string{
+		encoded,
+		"data:image/png;base64," + encoded,
+		base64.RawStdEncoding.EncodeToString(pngHeader),
+		encoded[:8] + "\r\n" + encoded[8:],
+	}
+	for _, s := range cases {
+		data, ext, err := DecodeBase64Image(s, 1024)
+		if err != nil {
+			t.Fatalf("DecodeBase64Image(%q): %v", s, err)
+		}
+		if ext != ".png" || !bytes.Equal(data, pngHeader) {
+			t.Fatalf("DecodeBase64Image(%q) = %q, %q", s, data, ext)
+		}
+	}
+}
+
+func TestDecodeBase64ImageRejects(t *testing.T) {
+	png := base64.StdEncoding.EncodeToString(pngHeader)
+
+	cases := []struct {
+		name    string
+		s       string
+		maxSize int64
+		want    error
+	}{
+		{"not base64", "not*base64", 0, ErrImageEncoding},
+		{"empty", "", 0, ErrImageEncoding},
+		{"data url without comma", "data:image/png;base64", 0, ErrImageEncoding},
+		{"too large", png, int64(len(pngHeader)) - 1, ErrImageTooLarge},
+		{"text", base64.StdEncoding.EncodeToString([]byte("hello world")), 0, ErrImageType},
+		// 声明的类型与内容不符时以内容为准
+		{"html as png", "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("<html><script>")), 0, ErrImageType},
+	}
+	for _, tc := range cases {
+		if _, _, err := DecodeBase64Image(tc.s, tc.maxSize); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
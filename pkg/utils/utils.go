@@ -36,6 +36,20 @@ func Contains[T comparable](slice []T, target T) bool {
 	return false
 }
 
+// Unique 去重并保留首次出现的顺序
+func Unique[T comparable](slice []T) []T {
+	seen := make(map[T]struct{}, len(slice))
+	res := make([]T, 0, len(slice))
+	for _, item := range slice {
+		if _, ok := seen[item]; ok {
+			continue
+		}
+		seen[item] = struct{}{}
+		res = append(res, item)
+	}
+	return res
+}
+
 func Pointer[T any](v T) *T {
 	return &v
 }
@@ -43,13 +57,20 @@ func Pointer[T any](v T) *T {
 // GenerateOrderNumberWithPrefix 生成带业务前缀的订单号
 // 格式: 前缀 + 年月日 + 6位随机数 (示例: EC20231215123456)
 func GenerateOrderNumberWithPrefix(prefix string) string {
-	now := time.Now()
+	return GenerateOrderNumber(prefix, "20060102", 6)
+}
 
-	// 格式化时间部分: 年月日
-	datePart := now.Format("20060102")
+// GenerateOrderNumber 生成订单号
+// 格式: 前缀 + 按 dateLayout 格式化的当前时间 + randomLength 位随机数
+func GenerateOrderNumber(prefix, dateLayout string, randomLength int) string {
+	// 格式化时间部分
+	datePart := time.Now().Format(dateLayout)
 
-	// 生成6位随机数
-	randomPart := fmt.Sprintf("%06d", rand.Intn(1000000))
+	// 生成随机数部分，逐位生成，不受 int 范围限制
+	randomPart := make([]byte, randomLength)
+	for i := range randomPart {
+		randomPart[i] = byte('0' + rand.Intn(10))
+	}
 
 	return fmt.Sprintf("%s%s%s", prefix, datePart, randomPart)
 }
@@ -84,3 +105,12 @@ func SaveToFile(file *multipart.FileHeader, dst string) (string, error) {
 	}
 	return fileName, nil
 }
+
+// SaveBytesToFile 把 data 保存到 dst 目录下，文件名为 UUID + ext
+func SaveBytesToFile(data []byte, ext, dst string) (string, error) {
+	fileName := GenerateUUID() + ext
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return fileName, err
+	}
+	return fileName, os.WriteFile(path.Join(dst, fileName), data, 0644)
+}